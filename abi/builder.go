@@ -3,6 +3,7 @@ package abi
 import (
 	"bytes"
 	"context"
+	"errors"
 	"github.com/goccy/go-json"
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
@@ -42,6 +43,25 @@ func NewBuilderFromSources(ctx context.Context, sources *solgo.Sources) (*Builde
 	}, nil
 }
 
+// NewBuilderFromIR initializes a new ABI builder on top of an existing IR builder. It allows ABI generation
+// for IR produced by other language frontends, such as Vyper, which do not go through the Solidity parser.
+func NewBuilderFromIR(ctx context.Context, parser *ir.Builder) (*Builder, error) {
+	if parser == nil {
+		return nil, errors.New("ir builder needed to initialize abi builder")
+	}
+
+	return &Builder{
+		ctx:        ctx,
+		sources:    parser.GetSources(),
+		parser:     parser,
+		astBuilder: parser.GetAstBuilder(),
+		resolver: &TypeResolver{
+			parser:         parser,
+			processedTypes: make(map[string]bool),
+		},
+	}, nil
+}
+
 // GetSources returns the source files being processed.
 func (b *Builder) GetSources() *solgo.Sources {
 	return b.sources
@@ -48,8 +48,12 @@ func (c *Contract) ToProto() *abi_pb.Contract {
 func (b *Builder) processContract(contract *ir.Contract) (*Contract, error) {
 	toReturn := Contract{}
 
-	// Process state variables.
+	// Process state variables. Vyper generates getters only for the public state variables.
 	for _, stateVar := range contract.GetStateVariables() {
+		if contract.GetLanguage() == ir.LanguageVyper && stateVar.GetVisibility() != ast_pb.Visibility_PUBLIC {
+			continue
+		}
+
 		method := b.processStateVariable(stateVar)
 		toReturn = append(toReturn, method)
 	}
@@ -86,7 +90,7 @@ func (b *Builder) processContract(contract *ir.Contract) (*Contract, error) {
 
 	// Process functions.
 	for _, function := range contract.GetFunctions() {
-		if function.GetVisibility() == ast_pb.Visibility_PUBLIC && function.GetVisibility() == ast_pb.Visibility_EXTERNAL {
+		if isAbiFunction(contract, function) {
 			method, err := b.processFunction(function)
 			if err != nil {
 				return nil, err
@@ -142,3 +146,13 @@ func (b *Builder) buildMethodIO(method MethodIO, typeDescr *ast.TypeDescription)
 
 	return method
 }
+
+// isAbiFunction reports whether the function is the part of the contract ABI. Vyper functions are external unless
+// marked internal, while the Solidity functions keep the existing filter of the builder.
+func isAbiFunction(contract *ir.Contract, function *ir.Function) bool {
+	if contract.GetLanguage() == ir.LanguageVyper {
+		return function.GetVisibility() == ast_pb.Visibility_PUBLIC || function.GetVisibility() == ast_pb.Visibility_EXTERNAL
+	}
+
+	return function.GetVisibility() == ast_pb.Visibility_PUBLIC && function.GetVisibility() == ast_pb.Visibility_EXTERNAL
+}
@@ -273,6 +273,11 @@ func (t *TypeResolver) discoverType(typeName string) Type {
 			}
 		}
 
+		// Roots lowered from other languages, such as Vyper, do not carry the Solidity AST.
+		if t.parser.GetRoot().GetAST() == nil {
+			return toReturn
+		}
+
 		for _, node := range t.parser.GetRoot().GetAST().GetGlobalNodes() {
 			switch nodeCtx := node.(type) {
 			case *ast.EnumDefinition:
@@ -5,17 +5,14 @@ import (
 	"encoding/base64"
 	"fmt"
 	"github.com/goccy/go-json"
-	"log"
 	"math/big"
 	"os"
-	"strings"
 
 	account "github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/unpackdev/solgo/clients"
 	"github.com/unpackdev/solgo/utils"
 )
@@ -29,15 +26,16 @@ const (
 // It also includes fields for account details, network information, and additional tags.
 type Account struct {
 	client             *clients.Client     `json:"-" yaml:"-"` // Client for Ethereum client interactions
+	audit              AuditLog            `json:"-" yaml:"-"` // Audit log recording the usage of the account keys
 	*keystore.KeyStore `json:"-" yaml:"-"` // KeyStore for managing account keys
-	Address            common.Address      `json:"address" yaml:"address"`         // Ethereum address of the account
-	Type               utils.AccountType   `json:"type" yaml:"type"`               // Account type
-	PrivateKey         string              `json:"private_key" yaml:"private_key"` // Private key of the account
-	PublicKey          string              `json:"public_key" yaml:"public_key"`   // Public key of the account
-	KeystoreAccount    account.Account     `json:"account" yaml:"account"`         // Ethereum account information
-	Password           string              `json:"password" yaml:"password"`       // Account's password
-	Network            utils.Network       `json:"network" yaml:"network"`         // Network information
-	Tags               []string            `json:"tags" yaml:"tags"`               // Arbitrary tags for the account
+	Address            common.Address      `json:"address" yaml:"address"`       // Ethereum address of the account
+	Type               utils.AccountType   `json:"type" yaml:"type"`             // Account type
+	PrivateKey         string              `json:"-" yaml:"-"`                   // Private key of the account, never serialized
+	PublicKey          string              `json:"public_key" yaml:"public_key"` // Public key of the account
+	KeystoreAccount    account.Account     `json:"account" yaml:"account"`       // Ethereum account information
+	Password           string              `json:"-" yaml:"-"`                   // Account's password, never serialized
+	Network            utils.Network       `json:"network" yaml:"network"`       // Network information
+	Tags               []string            `json:"tags" yaml:"tags"`             // Arbitrary tags for the account
 }
 
 // HasTag checks if the account has a specific tag.
@@ -74,6 +72,16 @@ func (a *Account) GetClient() *clients.Client {
 	return a.client
 }
 
+// SetAuditLog assigns the audit log recording the usage of the account keys.
+func (a *Account) SetAuditLog(log AuditLog) {
+	a.audit = log
+}
+
+// GetAuditLog retrieves the audit log recording the usage of the account keys.
+func (a *Account) GetAuditLog() AuditLog {
+	return a.audit
+}
+
 // SetAccountBalance sets the account's balance to a specific amount.
 // This method is mainly used for testing purposes in simulation environments like Anvil.
 // It does not affect the real balance on the Ethereum network.
@@ -111,49 +119,21 @@ func (a *Account) TransactOpts(client *clients.Client, amount *big.Int, simulate
 	}
 
 	if !simulate {
-		if a.Type == utils.SimpleAccountType {
-			privateKey, err := crypto.HexToECDSA(strings.TrimLeft(a.PrivateKey, "0x"))
-			if err != nil {
-				return nil, err
-			}
-
-			auth, err := bind.NewKeyedTransactorWithChainID(privateKey, big.NewInt(client.GetNetworkID()))
-			if err != nil {
-				return nil, err
-			}
-
-			auth.Nonce = big.NewInt(int64(nonce))
-			auth.GasPrice = gasPrice
-			auth.GasLimit = DEFAULT_GAS_LIMIT
-			auth.Value = amount
-			return auth, nil
-		} else if a.Type == utils.KeystoreAccountType {
-			password, _ := a.DecodePassword()
-
-			keyJson, err := os.ReadFile(a.KeystoreAccount.URL.Path)
-			if err != nil {
-				log.Fatalf("Failed to read the keystore file: %v", err)
-			}
-
-			// Decrypt the key with the password
-			key, err := keystore.DecryptKey(keyJson, password)
-			if err != nil {
-				log.Fatalf("Failed to decrypt key: %v", err)
-			}
-
-			auth, err := bind.NewKeyedTransactorWithChainID(key.PrivateKey, big.NewInt(client.GetNetworkID()))
-			if err != nil {
-				return nil, err
-			}
-
-			auth.Nonce = big.NewInt(int64(nonce))
-			auth.GasPrice = gasPrice
-			auth.GasLimit = DEFAULT_GAS_LIMIT
-			auth.Value = amount
-			return auth, nil
-		} else {
-			return nil, fmt.Errorf("failure to build transact opts due to invalid account type: %s", a.Type)
+		privateKey, err := a.getPrivateKey(AuditTransact)
+		if err != nil {
+			return nil, err
 		}
+
+		auth, err := bind.NewKeyedTransactorWithChainID(privateKey, big.NewInt(client.GetNetworkID()))
+		if err != nil {
+			return nil, err
+		}
+
+		auth.Nonce = big.NewInt(int64(nonce))
+		auth.GasPrice = gasPrice
+		auth.GasLimit = DEFAULT_GAS_LIMIT
+		auth.Value = amount
+		return auth, nil
 	}
 
 	return &bind.TransactOpts{
@@ -200,7 +180,7 @@ func (a *Account) Transfer(ctx context.Context, to common.Address, value *big.In
 	tx := types.NewTransaction(nonce, to, value, gasLimit, gasPrice, data)
 
 	signedTx, err := a.KeyStore.SignTxWithPassphrase(a.KeystoreAccount, passwd, tx, big.NewInt(a.client.GetNetworkID()))
-	if err != nil {
+	if err := recordAudit(a.audit, AuditTransfer, a, "", err); err != nil {
 		return nil, err
 	}
 
@@ -211,31 +191,40 @@ func (a *Account) Transfer(ctx context.Context, to common.Address, value *big.In
 	return signedTx, nil
 }
 
-// SaveToPath serializes the account information and writes it to a specified file path.
-// The account data is saved in JSON format. Returns an error if the writing process fails.
+// SaveToPath serializes the account information and writes it to a specified file path, readable by the owner only.
+// The private key and the password are never written in plaintext, so accounts carrying them are refused with
+// ErrMasterKeyRequired; use the Store to persist them encrypted. Returns an error if the writing process fails.
 func (a *Account) SaveToPath(path string) error {
-	file, err := json.MarshalIndent(a, "", "  ")
+	if a.hasSecrets() {
+		return fmt.Errorf("%w: account %s", ErrMasterKeyRequired, a.Address.Hex())
+	}
+
+	file, err := json.MarshalIndent(newAccountRecord(a), "", "  ")
 	if err != nil {
 		return err
 	}
 
-	return os.WriteFile(path, file, 0644)
+	return writeFileAtomic(path, file)
 }
 
 // LoadAccount loads an account from a JSON file located at a given path.
-// The method reads the file, unmarshals the JSON content into an Account struct,
-// and returns the populated Account instance or an error if the loading process fails.
+// Files written before the encryption carry the plaintext private key and password, which are loaded along with the
+// account. Files written by the Store carry the secrets encrypted and are refused with ErrEncryptedAccount, as only
+// the Store holding the master key can decrypt them.
 func LoadAccount(path string) (*Account, error) {
 	file, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
 
-	var account Account
-	err = json.Unmarshal(file, &account)
-	if err != nil {
+	var record accountRecord
+	if err := json.Unmarshal(file, &record); err != nil {
 		return nil, err
 	}
 
-	return &account, nil
+	if record.Crypto != nil {
+		return nil, fmt.Errorf("%w: %s", ErrEncryptedAccount, path)
+	}
+
+	return record.toAccount(), nil
 }
@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/unpackdev/solgo/clients"
@@ -113,6 +114,16 @@ func TestAccountsManager(t *testing.T) {
 			err = manager.Delete(utils.Ethereum, newAccount.GetAddress())
 			require.NoError(t, err)
 
+			// Pinned accounts need the master key encrypting their passwords
+			_, err = manager.Create(utils.Ethereum, utils.SimulatorAccountType.String(), true, "test")
+			require.ErrorIs(t, err, ErrMasterKeyRequired)
+
+			masterKey, err := NewLocalMasterKey(crypto.Keccak256([]byte("master")))
+			require.NoError(t, err)
+
+			manager, err = NewManager(ctx, pool, &Options{KeystorePath: t.TempDir(), SupportedNetworks: tc.supportedNetworks, MasterKey: masterKey})
+			require.NoError(t, err)
+
 			// Create new account with pin
 			newAccount, err = manager.Create(utils.Ethereum, utils.SimulatorAccountType.String(), true, "test")
 			require.NoError(t, err)
@@ -0,0 +1,113 @@
+package accounts
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/goccy/go-json"
+	"github.com/unpackdev/solgo/utils"
+)
+
+// AuditAction is the kind of the account operation recorded in the audit log.
+type AuditAction string
+
+const (
+	AuditSave           AuditAction = "save"            // Account was written to the store.
+	AuditLoad           AuditAction = "load"            // Account was read from the store.
+	AuditMigrate        AuditAction = "migrate"         // Plaintext secrets of the account were encrypted.
+	AuditDelete         AuditAction = "delete"          // Account was removed from the store.
+	AuditChangePassword AuditAction = "change_password" // Keystore password of the account was changed.
+	AuditRotateKey      AuditAction = "rotate_key"      // Data key of the account was encrypted with the new master key.
+	AuditSign           AuditAction = "sign"            // Private key of the account signed the message.
+	AuditTransact       AuditAction = "transact"        // Private key of the account was handed to the transactor.
+	AuditTransfer       AuditAction = "transfer"        // Account signed the transfer.
+)
+
+// AuditEvent is the single entry of the audit log. It never carries any of the secrets of the account.
+type AuditEvent struct {
+	Time        time.Time      `json:"time"`
+	Action      AuditAction    `json:"action"`
+	Address     common.Address `json:"address"`
+	Network     utils.Network  `json:"network,omitempty"`
+	MasterKeyID string         `json:"master_key_id,omitempty"`
+	Error       string         `json:"error,omitempty"` // Error the operation failed with, empty on success.
+}
+
+// AuditLog records the usage of the accounts. Operations fail when their event cannot be recorded, so no use of
+// the keys goes unrecorded.
+type AuditLog interface {
+	Record(event *AuditEvent) error
+}
+
+// FileAuditLog is the audit log appending the events to the file as JSON lines.
+type FileAuditLog struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewFileAuditLog opens the audit log file for appending, creating it readable by the owner only when it does
+// not exist.
+func NewFileAuditLog(path string) (*FileAuditLog, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+
+	return &FileAuditLog{file: file}, nil
+}
+
+// Record appends the event to the file, setting its time when not set.
+func (l *FileAuditLog) Record(event *AuditEvent) error {
+	if event.Time.IsZero() {
+		event.Time = time.Now().UTC()
+	}
+
+	line, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit event: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, err := l.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit event: %w", err)
+	}
+
+	return nil
+}
+
+// Close closes the audit log file.
+func (l *FileAuditLog) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.file.Close()
+}
+
+// recordAudit records the event of the account operation in the audit log, if any. The error of the operation
+// is recorded along with the event and returned back, unless recording the event itself fails.
+func recordAudit(log AuditLog, action AuditAction, acc *Account, masterKeyID string, opErr error) error {
+	if log == nil {
+		return opErr
+	}
+
+	event := &AuditEvent{
+		Action:      action,
+		Address:     acc.Address,
+		Network:     acc.Network,
+		MasterKeyID: masterKeyID,
+	}
+	if opErr != nil {
+		event.Error = opErr.Error()
+	}
+
+	if err := log.Record(event); err != nil {
+		return fmt.Errorf("failed to record %s audit event of %s: %w", action, acc.Address.Hex(), err)
+	}
+
+	return opErr
+}
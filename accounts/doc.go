@@ -5,4 +5,13 @@
 // The package is designed to be flexible and adaptable to various Ethereum-compatible networks like Ethereum mainnet,
 // Binance Smart Chain (BSC), and Polygon. It provides a structured approach to handle accounts across these networks,
 // making it easier for developers to interact with different blockchain environments through a unified interface.
+//
+// Accounts sign and recover the EIP-191 personal messages and the EIP-712 typed data, with the domain separators
+// and the typed data of the ERC-2612 permits, the Permit2 allowance and signature transfers and the Safe
+// transactions. Signatures of the smart contract wallets are verified through ERC-1271 with the bindings manager.
+//
+// Account files never hold the plaintext private keys or passwords. The Store encrypts them at rest with the
+// envelope encryption, under the master key read from the environment, the file or the key management service,
+// supports the password and master key rotation, and records the usage of the accounts in the audit log. Accounts
+// carrying the secrets are never written without the master key.
 package accounts
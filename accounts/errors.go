@@ -0,0 +1,31 @@
+package accounts
+
+import "errors"
+
+var (
+	// ErrInvalidSignature is returned for the signatures that are not 65 bytes long or do not recover the signer.
+	ErrInvalidSignature = errors.New("invalid signature")
+
+	// ErrKeyMismatch is returned when the private key of the account does not derive the address of the account.
+	ErrKeyMismatch = errors.New("private key does not match account address")
+
+	// ErrInvalidMasterKey is returned for the master keys that are not set or are not 32 bytes long.
+	ErrInvalidMasterKey = errors.New("invalid master key")
+
+	// ErrInsecureKeyFile is returned for the master key files accessible by the group or other users.
+	ErrInsecureKeyFile = errors.New("master key file is accessible by other users")
+
+	// ErrUnknownMasterKey is returned for the accounts encrypted with the master key the store does not hold.
+	ErrUnknownMasterKey = errors.New("unknown master key")
+
+	// ErrMasterKeyRequired is returned when the account carrying the private key or the password is written without
+	// the master key, which would lose the secrets.
+	ErrMasterKeyRequired = errors.New("master key is required to write account secrets")
+
+	// ErrEncryptedAccount is returned when the account file written by the Store, with the encrypted secrets, is
+	// loaded without it. Load such accounts through the Store or the Manager.
+	ErrEncryptedAccount = errors.New("account is encrypted, load it through the store")
+
+	// ErrDecryptionFailed is returned when the ciphertext was tampered with or encrypted with another key.
+	ErrDecryptionFailed = errors.New("decryption failed")
+)
@@ -23,12 +23,14 @@ type Manager struct {
 	cfg      *Options                             // Configuration options for the Manager
 	client   *clients.ClientPool                  // Ethereum client pool
 	ks       map[utils.Network]*keystore.KeyStore // Keystores for different networks
+	stores   map[utils.Network]*Store             // Stores of the accounts for different networks
+	audit    *FileAuditLog                        // Audit log of the accounts usage, nil when not configured
 	accounts map[utils.Network][]*Account         // Accounts mapped by their network
 }
 
 // NewManager initializes a new Manager instance.
 // It checks for the existence of keystore paths and supported networks,
-// and loads existing accounts from the keystore.
+// and loads existing accounts from the keystore. Account files are encrypted with the master key of the options.
 func NewManager(ctx context.Context, client *clients.ClientPool, cfg *Options) (*Manager, error) {
 	if !utils.PathExists(cfg.KeystorePath) {
 		return nil, fmt.Errorf("keystore path does not exist: %s", cfg.KeystorePath)
@@ -38,7 +40,22 @@ func NewManager(ctx context.Context, client *clients.ClientPool, cfg *Options) (
 		return nil, fmt.Errorf("no supported networks provided. You must provide at least one network")
 	}
 
+	masterKey, err := cfg.GetMasterKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get master key: %w", err)
+	}
+
+	var auditLog *FileAuditLog
+	var storeAuditLog AuditLog
+	if cfg.AuditLogPath != "" {
+		if auditLog, err = NewFileAuditLog(cfg.AuditLogPath); err != nil {
+			return nil, err
+		}
+		storeAuditLog = auditLog
+	}
+
 	var keystores = make(map[utils.Network]*keystore.KeyStore)
+	var stores = make(map[utils.Network]*Store)
 
 	// Now for each supported network, we need to create a subdirectory in the keystore path if it does not exist.
 	// Be sure that write permissions are set correctly.
@@ -55,12 +72,20 @@ func NewManager(ctx context.Context, client *clients.ClientPool, cfg *Options) (
 			keystore.StandardScryptN,
 			keystore.StandardScryptP,
 		)
+
+		store, err := NewStore(networkPath, masterKey, storeAuditLog)
+		if err != nil {
+			return nil, err
+		}
+		stores[network] = store
 	}
 
 	toReturn := &Manager{
 		ctx:      ctx,
 		cfg:      cfg,
 		ks:       keystores,
+		stores:   stores,
+		audit:    auditLog,
 		client:   client,
 		accounts: make(map[utils.Network][]*Account),
 	}
@@ -72,7 +97,7 @@ func NewManager(ctx context.Context, client *clients.ClientPool, cfg *Options) (
 	return toReturn, nil
 }
 
-// Load loads accounts from the keystore for each network.
+// Load loads accounts from the keystore for each network, decrypting their account files.
 // Returns an error if it fails to load accounts for any network.
 func (m *Manager) Load() error {
 	for _, network := range m.cfg.SupportedNetworks {
@@ -81,9 +106,13 @@ func (m *Manager) Load() error {
 			return err
 		}
 
+		store, err := m.GetStore(network)
+		if err != nil {
+			return err
+		}
+
 		for _, kAcc := range ks.Accounts() {
-			path := path.Join(m.GetNetworkPath(network), kAcc.Address.Hex()+".json")
-			acc, err := LoadAccount(path)
+			acc, err := store.Load(m.ctx, kAcc.Address)
 			if err != nil {
 				return err
 			}
@@ -120,6 +149,16 @@ func (m *Manager) GetKeystore(network utils.Network) (*keystore.KeyStore, error)
 	return m.ks[network], nil
 }
 
+// GetStore retrieves the account store for a given network.
+// Returns an error if the network is not supported.
+func (m *Manager) GetStore(network utils.Network) (*Store, error) {
+	if _, ok := m.stores[network]; !ok {
+		return nil, fmt.Errorf("network %s is not supported", network)
+	}
+
+	return m.stores[network], nil
+}
+
 // Create creates a new account for a given network with a specified password and optional tags.
 // It saves the account to the network's keystore path and adds it to the accounts map.
 func (m *Manager) Create(network utils.Network, password string, pin bool, tags ...string) (*Account, error) {
@@ -160,6 +199,10 @@ func (m *Manager) Create(network utils.Network, password string, pin bool, tags
 			Tags:       tags,
 		}
 
+		if m.audit != nil {
+			acc.SetAuditLog(m.audit)
+		}
+
 		// Now we need to add the account to the accounts map.
 		m.accounts[network] = append(m.accounts[network], acc)
 
@@ -167,6 +210,16 @@ func (m *Manager) Create(network utils.Network, password string, pin bool, tags
 		return acc, nil
 	}
 
+	// Password of the keystore is written encrypted, so no keystore file is created that the store cannot unlock.
+	store, err := m.GetStore(network)
+	if err != nil {
+		return nil, err
+	}
+
+	if store.GetMasterKey() == nil {
+		return nil, fmt.Errorf("%w: pinned accounts of %s", ErrMasterKeyRequired, network)
+	}
+
 	kacc, err := ks.NewAccount(password)
 	if err != nil {
 		return nil, err
@@ -184,10 +237,8 @@ func (m *Manager) Create(network utils.Network, password string, pin bool, tags
 
 	acc.SetClient(m.client.GetClientByGroup(network.String()))
 
-	// Now we need to save the account to the keystore path.
-	path := path.Join(m.GetNetworkPath(network), kacc.Address.Hex()+".json")
-
-	if err := acc.SaveToPath(path); err != nil {
+	// Now we need to save the account to the keystore path, with the password encrypted.
+	if err := store.Save(m.ctx, acc); err != nil {
 		return nil, err
 	}
 
@@ -260,6 +311,15 @@ func (m *Manager) Delete(network utils.Network, address common.Address) error {
 					if err != nil {
 						return fmt.Errorf("failed to delete account from keystore: %s", err)
 					}
+
+					store, err := m.GetStore(network)
+					if err != nil {
+						return err
+					}
+
+					if err := store.Delete(m.ctx, acc); err != nil {
+						return err
+					}
 				}
 
 				// Remove the account from the Manager's accounts map.
@@ -273,3 +333,45 @@ func (m *Manager) Delete(network utils.Network, address common.Address) error {
 
 	return fmt.Errorf("account for network: %s not found: %s", network.String(), address.Hex())
 }
+
+// ChangePassword changes the password of the account for a given network, encrypting its keystore file with the
+// new password. Returns an error if the account is not found or if the password cannot be changed.
+func (m *Manager) ChangePassword(network utils.Network, address common.Address, newPassword string) error {
+	acc, err := m.Get(network, address)
+	if err != nil {
+		return err
+	}
+
+	store, err := m.GetStore(network)
+	if err != nil {
+		return err
+	}
+
+	return store.ChangePassword(m.ctx, acc, newPassword)
+}
+
+// RotateMasterKey encrypts the account files of all of the supported networks with the new master key.
+// The previous master key is kept for decrypting the account files, so a failed rotation can be retried.
+func (m *Manager) RotateMasterKey(ctx context.Context, newKey MasterKey) error {
+	for _, network := range m.cfg.SupportedNetworks {
+		store, err := m.GetStore(network)
+		if err != nil {
+			return err
+		}
+
+		if err := store.RotateMasterKey(ctx, newKey); err != nil {
+			return fmt.Errorf("failed to rotate master key for network %s: %w", network, err)
+		}
+	}
+
+	return nil
+}
+
+// Close closes the audit log of the Manager, if any.
+func (m *Manager) Close() error {
+	if m.audit != nil {
+		return m.audit.Close()
+	}
+
+	return nil
+}
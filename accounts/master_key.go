@@ -0,0 +1,159 @@
+package accounts
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// MasterKey encrypts the data keys of the account store, the envelope encryption key encryption key. It is
+// implemented by the local keys read from the environment or the file, and may be implemented with the key
+// management service, such as AWS KMS or Vault transit, keeping the master key out of the process entirely.
+type MasterKey interface {
+	// GetID returns the identifier of the key, recorded along with the data keys it encrypted to select the key
+	// decrypting them. It must not reveal the key itself.
+	GetID() string
+
+	// Encrypt encrypts the data key.
+	Encrypt(ctx context.Context, plaintext []byte) ([]byte, error)
+
+	// Decrypt decrypts the data key encrypted with Encrypt.
+	Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error)
+}
+
+// LocalMasterKey is the 32 bytes master key held in the memory of the process, encrypting with AES-256-GCM.
+type LocalMasterKey struct {
+	id   string
+	aead cipher.AEAD
+}
+
+// NewLocalMasterKey creates the master key out of the 32 bytes key. The identifier of the key is derived from
+// its SHA-256 hash, so the same key is recognized whichever source it was read from.
+func NewLocalMasterKey(key []byte) (*LocalMasterKey, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("%w: expected 32 bytes, got %d", ErrInvalidMasterKey, len(key))
+	}
+
+	aead, err := newAead(key)
+	if err != nil {
+		return nil, err
+	}
+
+	fingerprint := sha256.Sum256(key)
+	return &LocalMasterKey{
+		id:   "local:" + hex.EncodeToString(fingerprint[:8]),
+		aead: aead,
+	}, nil
+}
+
+// NewEnvMasterKey creates the master key out of the hex or base64 encoded key of the environment variable.
+func NewEnvMasterKey(name string) (*LocalMasterKey, error) {
+	encoded, ok := os.LookupEnv(name)
+	if !ok || strings.TrimSpace(encoded) == "" {
+		return nil, fmt.Errorf("%w: environment variable %s is not set", ErrInvalidMasterKey, name)
+	}
+
+	key, err := decodeMasterKey(encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewLocalMasterKey(key)
+}
+
+// NewFileMasterKey creates the master key out of the hex or base64 encoded key of the file. The file must not
+// be accessible by the group or other users.
+func NewFileMasterKey(path string) (*LocalMasterKey, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat master key file: %w", err)
+	}
+
+	if info.Mode().Perm()&0077 != 0 {
+		return nil, fmt.Errorf("%w: %s has permissions %s", ErrInsecureKeyFile, path, info.Mode().Perm())
+	}
+
+	encoded, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read master key file: %w", err)
+	}
+
+	key, err := decodeMasterKey(string(encoded))
+	if err != nil {
+		return nil, err
+	}
+
+	return NewLocalMasterKey(key)
+}
+
+// GetID returns the identifier of the key, derived from its hash.
+func (k *LocalMasterKey) GetID() string {
+	return k.id
+}
+
+// Encrypt encrypts the data key, prefixing the ciphertext with the random nonce.
+func (k *LocalMasterKey) Encrypt(ctx context.Context, plaintext []byte) ([]byte, error) {
+	return seal(k.aead, plaintext, []byte(k.id))
+}
+
+// Decrypt decrypts the data key encrypted with Encrypt.
+func (k *LocalMasterKey) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	return open(k.aead, ciphertext, []byte(k.id))
+}
+
+// decodeMasterKey decodes the hex, with or without the 0x prefix, or the base64 encoded key.
+func decodeMasterKey(encoded string) ([]byte, error) {
+	encoded = strings.TrimSpace(encoded)
+
+	if key, err := hex.DecodeString(strings.TrimPrefix(encoded, "0x")); err == nil {
+		return key, nil
+	}
+
+	if key, err := base64.StdEncoding.DecodeString(encoded); err == nil {
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("%w: key is neither hex nor base64 encoded", ErrInvalidMasterKey)
+}
+
+// newAead creates the AES-256-GCM cipher of the key.
+func newAead(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// seal encrypts the plaintext, prefixing the ciphertext with the random nonce.
+func seal(aead cipher.AEAD, plaintext []byte, additionalData []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+// open decrypts the ciphertext of seal.
+func open(aead cipher.AEAD, ciphertext []byte, additionalData []byte) ([]byte, error) {
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, ErrDecryptionFailed
+	}
+
+	plaintext, err := aead.Open(nil, ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():], additionalData)
+	if err != nil {
+		return nil, ErrDecryptionFailed
+	}
+
+	return plaintext, nil
+}
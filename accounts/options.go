@@ -1,6 +1,8 @@
 package accounts
 
-import "github.com/unpackdev/solgo/utils"
+import (
+	"github.com/unpackdev/solgo/utils"
+)
 
 // Options defines the configuration parameters for account management.
 type Options struct {
@@ -11,4 +13,34 @@ type Options struct {
 	// SupportedNetworks lists the Ethereum based networks that the account manager will interact with.
 	// Each network has a corresponding keystore and set of account configurations.
 	SupportedNetworks []utils.Network `json:"supported_networks" yaml:"supported_networks"`
+
+	// MasterKeyEnv names the environment variable holding the hex or base64 encoded 32 bytes master key, which
+	// encrypts the passwords and private keys of the accounts at rest.
+	MasterKeyEnv string `json:"master_key_env" yaml:"master_key_env"`
+
+	// MasterKeyFile specifies the file holding the hex or base64 encoded 32 bytes master key, accessible by the
+	// owner only. It takes precedence over the MasterKeyEnv.
+	MasterKeyFile string `json:"master_key_file" yaml:"master_key_file"`
+
+	// MasterKey is the master key implemented by the key management service. It takes precedence over both the
+	// MasterKeyFile and the MasterKeyEnv. Without any of them, no pinned accounts can be created.
+	MasterKey MasterKey `json:"-" yaml:"-"`
+
+	// AuditLogPath specifies the file the usage of the accounts is recorded in, as JSON lines. Usage is not
+	// recorded when empty.
+	AuditLogPath string `json:"audit_log_path" yaml:"audit_log_path"`
+}
+
+// GetMasterKey returns the configured master key, nil when none is configured.
+func (o *Options) GetMasterKey() (MasterKey, error) {
+	switch {
+	case o.MasterKey != nil:
+		return o.MasterKey, nil
+	case o.MasterKeyFile != "":
+		return NewFileMasterKey(o.MasterKeyFile)
+	case o.MasterKeyEnv != "":
+		return NewEnvMasterKey(o.MasterKeyEnv)
+	default:
+		return nil, nil
+	}
 }
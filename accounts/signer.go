@@ -0,0 +1,163 @@
+package accounts
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"os"
+	"strings"
+
+	account "github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/unpackdev/solgo/bindings"
+	"github.com/unpackdev/solgo/utils"
+)
+
+// SignHash signs the 32 bytes hash with the private key of the account. The signature is returned in the
+// 65 bytes [R || S || V] form with V being 27 or 28, as expected by ecrecover and the signature verifying
+// contracts. The hash must be the result of the hashing scheme preventing the signing of the transactions,
+// such as the one of SignPersonalMessage or SignTypedData.
+func (a *Account) SignHash(hash common.Hash) ([]byte, error) {
+	privateKey, err := a.getPrivateKey(AuditSign)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := crypto.Sign(hash.Bytes(), privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign hash: %w", err)
+	}
+
+	signature[crypto.RecoveryIDOffset] += 27
+	return signature, nil
+}
+
+// SignPersonalMessage signs the message following EIP-191 version 0x45, the personal_sign and eth_sign scheme
+// prefixing the message with "\x19Ethereum Signed Message:\n" and its length.
+func (a *Account) SignPersonalMessage(message []byte) ([]byte, error) {
+	return a.SignHash(PersonalMessageHash(message))
+}
+
+// SignTypedData signs the EIP-712 typed structured data, the eth_signTypedData_v4 scheme. The EIP712Domain
+// type is derived from the domain when the types do not declare it.
+func (a *Account) SignTypedData(typedData apitypes.TypedData) ([]byte, error) {
+	hash, err := TypedDataHash(typedData)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.SignHash(hash)
+}
+
+// getPrivateKey returns the private key of the account, recording the access for the action in the audit log.
+func (a *Account) getPrivateKey(action AuditAction) (*ecdsa.PrivateKey, error) {
+	privateKey, err := a.decodePrivateKey()
+	if err := recordAudit(a.audit, action, a, "", err); err != nil {
+		return nil, err
+	}
+
+	return privateKey, nil
+}
+
+// decodePrivateKey decodes the private key of the account. Simple accounts carry the hex encoded key, while the
+// keys of the keystore accounts are decrypted from their keystore files with the password of the account.
+func (a *Account) decodePrivateKey() (*ecdsa.PrivateKey, error) {
+	var privateKey *ecdsa.PrivateKey
+
+	switch a.Type {
+	case utils.SimpleAccountType:
+		// Keys are stored without the leading zeros, while the decoding expects all of the 32 bytes.
+		hexKey := strings.TrimPrefix(a.PrivateKey, "0x")
+		if len(hexKey) < 64 {
+			hexKey = strings.Repeat("0", 64-len(hexKey)) + hexKey
+		}
+
+		key, err := crypto.HexToECDSA(hexKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode private key: %w", err)
+		}
+		privateKey = key
+	case utils.KeystoreAccountType:
+		password, err := a.DecodePassword()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode password: %w", err)
+		}
+
+		keyJson, err := os.ReadFile(a.KeystoreAccount.URL.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read keystore file: %w", err)
+		}
+
+		key, err := keystore.DecryptKey(keyJson, password)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt key: %w", err)
+		}
+		privateKey = key.PrivateKey
+	default:
+		return nil, fmt.Errorf("failure to get private key due to invalid account type: %s", a.Type)
+	}
+
+	if a.Address != utils.ZeroAddress && crypto.PubkeyToAddress(privateKey.PublicKey) != a.Address {
+		return nil, ErrKeyMismatch
+	}
+
+	return privateKey, nil
+}
+
+// PersonalMessageHash returns the EIP-191 version 0x45 hash of the message, the hash signed by personal_sign.
+func PersonalMessageHash(message []byte) common.Hash {
+	return common.BytesToHash(account.TextHash(message))
+}
+
+// RecoverHash recovers the address of the signer of the hash out of the 65 bytes [R || S || V] signature. Both
+// the 0 and 1 and the 27 and 28 recovery identifiers are accepted.
+func RecoverHash(hash common.Hash, signature []byte) (common.Address, error) {
+	if len(signature) != crypto.SignatureLength {
+		return common.Address{}, fmt.Errorf("%w: expected %d bytes, got %d", ErrInvalidSignature, crypto.SignatureLength, len(signature))
+	}
+
+	sig := common.CopyBytes(signature)
+	if sig[crypto.RecoveryIDOffset] >= 27 {
+		sig[crypto.RecoveryIDOffset] -= 27
+	}
+
+	publicKey, err := crypto.SigToPub(hash.Bytes(), sig)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("%w: %s", ErrInvalidSignature, err)
+	}
+
+	return crypto.PubkeyToAddress(*publicKey), nil
+}
+
+// RecoverPersonalMessage recovers the address of the signer of the EIP-191 personal message.
+func RecoverPersonalMessage(message []byte, signature []byte) (common.Address, error) {
+	return RecoverHash(PersonalMessageHash(message), signature)
+}
+
+// RecoverTypedData recovers the address of the signer of the EIP-712 typed structured data.
+func RecoverTypedData(typedData apitypes.TypedData, signature []byte) (common.Address, error) {
+	hash, err := TypedDataHash(typedData)
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	return RecoverHash(hash, signature)
+}
+
+// VerifySignature reports whether the signature of the hash is valid for the signer. Signatures recovering the
+// signer are valid without any network interaction, while for the rest the signer is asked through the ERC-1271
+// isValidSignature of the manager, covering the smart contract wallets such as Safe.
+func VerifySignature(ctx context.Context, manager *bindings.Manager, network utils.Network, signer common.Address, hash common.Hash, signature []byte) (bool, error) {
+	if recovered, err := RecoverHash(hash, signature); err == nil && recovered == signer {
+		return true, nil
+	}
+
+	if manager == nil {
+		return false, nil
+	}
+
+	return manager.IsValidSignature(ctx, network, signer, hash, signature)
+}
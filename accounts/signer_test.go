@@ -0,0 +1,479 @@
+package accounts
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	account "github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vm/runtime"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unpackdev/solgo/bindings"
+	"github.com/unpackdev/solgo/clients"
+	"github.com/unpackdev/solgo/utils"
+)
+
+// mailTypedData is the example of the EIP-712 specification.
+func mailTypedData() apitypes.TypedData {
+	return apitypes.TypedData{
+		Types: apitypes.Types{
+			"Person": {
+				{Name: "name", Type: "string"},
+				{Name: "wallet", Type: "address"},
+			},
+			"Mail": {
+				{Name: "from", Type: "Person"},
+				{Name: "to", Type: "Person"},
+				{Name: "contents", Type: "string"},
+			},
+		},
+		PrimaryType: "Mail",
+		Domain:      NewDomain("Ether Mail", "1", big.NewInt(1), common.HexToAddress("0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC")),
+		Message: apitypes.TypedDataMessage{
+			"from": map[string]interface{}{
+				"name":   "Cow",
+				"wallet": "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826",
+			},
+			"to": map[string]interface{}{
+				"name":   "Bob",
+				"wallet": "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB",
+			},
+			"contents": "Hello, Bob!",
+		},
+	}
+}
+
+func TestTypedDataHash(t *testing.T) {
+	testCases := []struct {
+		name              string
+		typedData         apitypes.TypedData
+		primaryType       string
+		expectedTypeHash  string
+		expectedSeparator string
+		expectedHash      string
+	}{
+		{
+			name:              "EIP-712 Mail Example",
+			typedData:         mailTypedData(),
+			primaryType:       "Mail",
+			expectedTypeHash:  "0xa0cedeb2dc280ba39b857546d74f5549c3a1d7bdc2dd96bf881f76108e23dac2",
+			expectedSeparator: "0xf2cee375fa42b42143804025fc449deafd50cc031ca257e0b194a650a912090f",
+			expectedHash:      "0xbe609aee343fb3c4b28e1df9e632fca64fcfaede20f02e86244efddf30957bd2",
+		},
+		{
+			name: "ERC-2612 Permit",
+			typedData: (&Permit{
+				Owner:    common.HexToAddress("0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"),
+				Spender:  common.HexToAddress("0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"),
+				Value:    big.NewInt(1000),
+				Nonce:    big.NewInt(0),
+				Deadline: big.NewInt(1700000000),
+			}).TypedData(NewDomain("USD Coin", "2", big.NewInt(1), common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"))),
+			primaryType:       "Permit",
+			expectedTypeHash:  "0x6e71edae12b1b97f4d1f60370fef10105fa2faae0126114a169c64845d6126c9",
+			expectedSeparator: "0x06c37168a7db5138defc7866392bb87a741f9b3d104deb5094588ce041cae335",
+		},
+		{
+			name: "Permit2 PermitSingle",
+			typedData: (&Permit2Single{
+				Details: Permit2Details{
+					Token:      common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"),
+					Amount:     big.NewInt(1000),
+					Expiration: big.NewInt(1700000000),
+					Nonce:      big.NewInt(0),
+				},
+				Spender:     common.HexToAddress("0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"),
+				SigDeadline: big.NewInt(1700000000),
+			}).TypedData(NewPermit2Domain(big.NewInt(1))),
+			primaryType:       "PermitSingle",
+			expectedTypeHash:  "0xf3841cd1ff0085026a6327b620b67997ce40f282c88a8e905a7a5626e310f3d0",
+			expectedSeparator: "0x866a5aba21966af95d6c7ab78eb2b2fc913915c28be3b9aa07cc04ff903e3f28",
+		},
+		{
+			name: "Permit2 PermitTransferFrom",
+			typedData: (&Permit2TransferFrom{
+				Token:    common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"),
+				Amount:   big.NewInt(1000),
+				Spender:  common.HexToAddress("0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"),
+				Nonce:    big.NewInt(7),
+				Deadline: big.NewInt(1700000000),
+			}).TypedData(NewPermit2Domain(big.NewInt(1))),
+			primaryType:       "PermitTransferFrom",
+			expectedTypeHash:  "0x939c21a48a8dbe3a9a2404a1d46691e4d39f6583d6ec6b35714604c986d80106",
+			expectedSeparator: "0x866a5aba21966af95d6c7ab78eb2b2fc913915c28be3b9aa07cc04ff903e3f28",
+		},
+		{
+			name: "Safe Transaction",
+			typedData: (&SafeTransaction{
+				To:    common.HexToAddress("0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"),
+				Value: big.NewInt(1),
+				Data:  common.FromHex("0xa9059cbb"),
+				Nonce: big.NewInt(3),
+			}).TypedData(NewSafeDomain(big.NewInt(1), common.HexToAddress("0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC"))),
+			primaryType:      "SafeTx",
+			expectedTypeHash: "0xbb8310d486368db6bd6f849402fdd73ad53d316b5a4b2644ad6efe0f941286d8",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedTypeHash, common.BytesToHash(tc.typedData.TypeHash(tc.primaryType)).Hex())
+
+			separator, err := DomainSeparator(tc.typedData.Domain)
+			require.NoError(t, err)
+			if tc.expectedSeparator != "" {
+				assert.Equal(t, tc.expectedSeparator, separator.Hex())
+			}
+
+			structHash, err := tc.typedData.HashStruct(tc.primaryType, tc.typedData.Message)
+			require.NoError(t, err)
+
+			hash, err := TypedDataHash(tc.typedData)
+			require.NoError(t, err)
+			assert.Equal(t, crypto.Keccak256Hash([]byte{0x19, 0x01}, separator.Bytes(), structHash), hash)
+			if tc.expectedHash != "" {
+				assert.Equal(t, tc.expectedHash, hash.Hex())
+			}
+		})
+	}
+}
+
+func TestSafeTransactionHash(t *testing.T) {
+	safe := common.HexToAddress("0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC")
+	tx := &SafeTransaction{
+		To:    common.HexToAddress("0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"),
+		Value: big.NewInt(1),
+		Nonce: big.NewInt(3),
+	}
+
+	// Encoded the same way as getTransactionHash of the Safe.
+	uint256 := func(value int64) []byte { return common.LeftPadBytes(big.NewInt(value).Bytes(), 32) }
+	separator := crypto.Keccak256Hash(
+		common.FromHex("0x47e79534a245952e8b16893a336b85a3d9ea9fa8c573f3d803afb92a79469218"),
+		uint256(1),
+		common.LeftPadBytes(safe.Bytes(), 32),
+	)
+	structHash := crypto.Keccak256Hash(
+		common.FromHex("0xbb8310d486368db6bd6f849402fdd73ad53d316b5a4b2644ad6efe0f941286d8"),
+		common.LeftPadBytes(tx.To.Bytes(), 32),
+		uint256(1),
+		crypto.Keccak256(nil),
+		uint256(0), uint256(0), uint256(0), uint256(0),
+		uint256(0), uint256(0),
+		uint256(3),
+	)
+
+	hash, err := SafeTransactionHash(big.NewInt(1), safe, tx)
+	require.NoError(t, err)
+	assert.Equal(t, crypto.Keccak256Hash([]byte{0x19, 0x01}, separator.Bytes(), structHash.Bytes()), hash)
+}
+
+func TestSignatures(t *testing.T) {
+	privateKey := crypto.Keccak256([]byte("cow"))
+	address := common.HexToAddress("0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826")
+
+	key, err := crypto.ToECDSA(privateKey)
+	require.NoError(t, err)
+
+	ks := keystore.NewKeyStore(t.TempDir(), keystore.LightScryptN, keystore.LightScryptP)
+	keystoreAccount, err := ks.ImportECDSA(key, "secret")
+	require.NoError(t, err)
+
+	// Keys generated with the leading zero byte are stored without it.
+	zeroKey, err := crypto.ToECDSA(common.FromHex("0x00a1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f60718293a4b5c6d7e8f"))
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name        string
+		account     *Account
+		expectedErr error
+	}{
+		{
+			name: "Simple Account",
+			account: &Account{
+				Address:    address,
+				Type:       utils.SimpleAccountType,
+				PrivateKey: fmt.Sprintf("%x", privateKey),
+			},
+		},
+		{
+			name: "Simple Account With Leading Zero",
+			account: &Account{
+				Address:    crypto.PubkeyToAddress(zeroKey.PublicKey),
+				Type:       utils.SimpleAccountType,
+				PrivateKey: fmt.Sprintf("%x", zeroKey.D),
+			},
+		},
+		{
+			name: "Keystore Account",
+			account: &Account{
+				KeyStore:        ks,
+				Address:         address,
+				Type:            utils.KeystoreAccountType,
+				KeystoreAccount: keystoreAccount,
+				Password:        base64.StdEncoding.EncodeToString([]byte("secret")),
+			},
+		},
+		{
+			name: "Key Not Matching Address",
+			account: &Account{
+				Address:    common.HexToAddress("0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"),
+				Type:       utils.SimpleAccountType,
+				PrivateKey: fmt.Sprintf("%x", privateKey),
+			},
+			expectedErr: ErrKeyMismatch,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			message := []byte("Hello, Bob!")
+			personalSignature, err := tc.account.SignPersonalMessage(message)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, personalSignature, 65)
+			assert.Contains(t, []byte{27, 28}, personalSignature[64])
+
+			recovered, err := RecoverPersonalMessage(message, personalSignature)
+			require.NoError(t, err)
+			assert.Equal(t, tc.account.Address, recovered)
+			assert.Equal(t, common.BytesToHash(account.TextHash(message)), PersonalMessageHash(message))
+
+			typedData := mailTypedData()
+			typedSignature, err := tc.account.SignTypedData(typedData)
+			require.NoError(t, err)
+
+			recovered, err = RecoverTypedData(typedData, typedSignature)
+			require.NoError(t, err)
+			assert.Equal(t, tc.account.Address, recovered)
+
+			// Signature of the EIP-712 specification example, made with the keccak256("cow") key.
+			if tc.account.Address == address {
+				assert.Equal(t, "0x4355c47d63924e8a72e509b65029052eb6c299d53a04e167c5775fd466751c9d07299936d304c153f6443dfa05f40ff007d72911b6f72307f996231605b915621c", hexutil.Encode(typedSignature))
+			}
+
+			hash, err := TypedDataHash(typedData)
+			require.NoError(t, err)
+
+			valid, err := VerifySignature(context.Background(), nil, utils.Ethereum, tc.account.Address, hash, typedSignature)
+			require.NoError(t, err)
+			assert.True(t, valid)
+
+			valid, err = VerifySignature(context.Background(), nil, utils.Ethereum, tc.account.Address, hash, personalSignature)
+			require.NoError(t, err)
+			assert.False(t, valid)
+		})
+	}
+}
+
+func TestRecoverHash(t *testing.T) {
+	key, err := crypto.ToECDSA(crypto.Keccak256([]byte("cow")))
+	require.NoError(t, err)
+
+	hash := crypto.Keccak256Hash([]byte("hash"))
+	signature, err := crypto.Sign(hash.Bytes(), key)
+	require.NoError(t, err)
+
+	legacySignature := common.CopyBytes(signature)
+	legacySignature[64] += 27
+
+	testCases := []struct {
+		name        string
+		signature   []byte
+		expected    common.Address
+		expectedErr error
+	}{
+		{
+			name:      "Recovery Id 0 or 1",
+			signature: signature,
+			expected:  crypto.PubkeyToAddress(key.PublicKey),
+		},
+		{
+			name:      "Recovery Id 27 or 28",
+			signature: legacySignature,
+			expected:  crypto.PubkeyToAddress(key.PublicKey),
+		},
+		{
+			name:        "Short Signature",
+			signature:   signature[:64],
+			expectedErr: ErrInvalidSignature,
+		},
+		{
+			name:        "Invalid Recovery Id",
+			signature:   append(common.CopyBytes(signature[:64]), 5),
+			expectedErr: ErrInvalidSignature,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			original := common.CopyBytes(tc.signature)
+			recovered, err := RecoverHash(hash, tc.signature)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, recovered)
+			assert.Equal(t, original, tc.signature, "signature must not be modified")
+		})
+	}
+}
+
+// evmService serves eth_getCode and eth_call of the contracts deployed in the in-memory EVM.
+type evmService struct {
+	mu    sync.Mutex
+	state *state.StateDB
+}
+
+// evmCallArgs is the call message of eth_call.
+type evmCallArgs struct {
+	To    *common.Address `json:"to"`
+	Input hexutil.Bytes   `json:"input"`
+}
+
+// evmRevertError is the JSON-RPC error of the reverted call, as returned by the nodes.
+type evmRevertError struct {
+	data string
+}
+
+func (e *evmRevertError) Error() string          { return "execution reverted" }
+func (e *evmRevertError) ErrorCode() int         { return 3 }
+func (e *evmRevertError) ErrorData() interface{} { return e.data }
+
+func (s *evmService) GetCode(address common.Address, blockNumber string) (hexutil.Bytes, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.state.GetCode(address), nil
+}
+
+func (s *evmService) Call(args evmCallArgs, blockNumber string) (hexutil.Bytes, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cfg := &runtime.Config{State: s.state}
+
+	var output []byte
+	var err error
+	if args.To == nil {
+		output, _, _, err = runtime.Create(args.Input, cfg)
+	} else {
+		output, _, err = runtime.Call(*args.To, args.Input, cfg)
+	}
+
+	if errors.Is(err, vm.ErrExecutionReverted) {
+		return nil, &evmRevertError{data: hexutil.Encode(output)}
+	}
+	return output, err
+}
+
+// netService serves net_version.
+type netService struct{}
+
+func (s *netService) Version() string {
+	return "1"
+}
+
+func TestVerifySignatureErc1271(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	address := common.HexToAddress("0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826")
+	key, err := crypto.ToECDSA(crypto.Keccak256([]byte("cow")))
+	require.NoError(t, err)
+
+	hash := crypto.Keccak256Hash([]byte("hello"))
+	signature, err := crypto.Sign(hash.Bytes(), key)
+	require.NoError(t, err)
+	signature[64] += 27
+
+	var (
+		walletAddress    = common.HexToAddress("0x1000000000000000000000000000000000000001")
+		rejectingAddress = common.HexToAddress("0x1000000000000000000000000000000000000002")
+		emptyAddress     = common.HexToAddress("0x1000000000000000000000000000000000000003")
+		shortAddress     = common.HexToAddress("0x1000000000000000000000000000000000000004")
+		revertAddress    = common.HexToAddress("0x1000000000000000000000000000000000000005")
+	)
+
+	statedb, err := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	require.NoError(t, err)
+
+	// Returns the ABI encoded magic value for any signature.
+	statedb.SetCode(walletAddress, common.FromHex("0x7f1626ba7e0000000000000000000000000000000000000000000000000000000060005260206000f3"))
+	// Returns the ABI encoded zero bytes4.
+	statedb.SetCode(rejectingAddress, common.FromHex("0x60206000f3"))
+	// Fallback returning nothing.
+	statedb.SetCode(emptyAddress, common.FromHex("0x00"))
+	// Returns the magic value as the 4 bytes, not ABI encoded.
+	statedb.SetCode(shortAddress, common.FromHex("0x631626ba7e60e01b60005260046000f3"))
+	// Reverts any call.
+	statedb.SetCode(revertAddress, common.FromHex("0x60006000fd"))
+
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", &evmService{state: statedb}))
+	require.NoError(t, server.RegisterName("net", &netService{}))
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	pool, err := clients.NewClientPool(ctx, &clients.Options{
+		HealthCheckInterval: -1,
+		Nodes: []clients.Node{
+			{
+				Group:             string(utils.Ethereum),
+				Type:              "mainnet",
+				Endpoint:          httpServer.URL,
+				NetworkId:         1,
+				ConcurrentClients: 1,
+			},
+		},
+	})
+	require.NoError(t, err)
+	defer pool.Close()
+
+	manager, err := bindings.NewManager(ctx, pool)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		signer   common.Address
+		manager  *bindings.Manager
+		expected bool
+	}{
+		{name: "Externally Owned Account", signer: address, manager: manager, expected: true},
+		{name: "Account Without Code", signer: common.HexToAddress("0x2000000000000000000000000000000000000001"), manager: manager},
+		{name: "Without Manager", signer: walletAddress},
+		{name: "Valid Contract Signature", signer: walletAddress, manager: manager, expected: true},
+		{name: "Rejected Contract Signature", signer: rejectingAddress, manager: manager},
+		{name: "Empty Return Data", signer: emptyAddress, manager: manager},
+		{name: "Not ABI Encoded Return Data", signer: shortAddress, manager: manager},
+		{name: "Reverted Call", signer: revertAddress, manager: manager},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			valid, err := VerifySignature(ctx, tc.manager, utils.Ethereum, tc.signer, hash, signature)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, valid)
+		})
+	}
+}
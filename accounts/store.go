@@ -0,0 +1,528 @@
+package accounts
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	account "github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/goccy/go-json"
+	"github.com/unpackdev/solgo/utils"
+)
+
+// accountRecordVersion is the version of the account files written by the store.
+const accountRecordVersion = 1
+
+// accountRecord is the account file of the store. Secrets of the account are only ever written encrypted, while
+// the plaintext ones are read from the files written before the encryption, to be migrated.
+type accountRecord struct {
+	Version         int               `json:"version"`
+	Address         common.Address    `json:"address"`
+	Type            utils.AccountType `json:"type"`
+	PublicKey       string            `json:"public_key,omitempty"`
+	KeystoreAccount *account.Account  `json:"account,omitempty"` // Keystore file of the keystore accounts.
+	Network         utils.Network     `json:"network"`
+	Tags            []string          `json:"tags"`
+	Crypto          *accountEnvelope  `json:"crypto,omitempty"`
+	PrivateKey      string            `json:"private_key,omitempty"` // Plaintext private key of the legacy files.
+	Password        string            `json:"password,omitempty"`    // Plaintext password of the legacy files.
+}
+
+// accountEnvelope is the secrets of the account encrypted with the data key, along with the data key encrypted
+// with the master key. Rotating the master key only encrypts the data key again.
+type accountEnvelope struct {
+	MasterKeyID string        `json:"master_key_id"`
+	DataKey     hexutil.Bytes `json:"data_key"`
+	Ciphertext  hexutil.Bytes `json:"ciphertext"` // AES-256-GCM of the secrets, bound to the account address.
+}
+
+// accountSecrets is the plaintext of the envelope.
+type accountSecrets struct {
+	PrivateKey string `json:"private_key,omitempty"`
+	Password   string `json:"password,omitempty"`
+}
+
+// newAccountRecord creates the record of the account without any of its secrets.
+func newAccountRecord(acc *Account) *accountRecord {
+	toReturn := &accountRecord{
+		Version:   accountRecordVersion,
+		Address:   acc.Address,
+		Type:      acc.Type,
+		PublicKey: acc.PublicKey,
+		Network:   acc.Network,
+		Tags:      acc.Tags,
+	}
+
+	if acc.KeystoreAccount.URL.Path != "" {
+		keystoreAccount := acc.KeystoreAccount
+		toReturn.KeystoreAccount = &keystoreAccount
+	}
+
+	return toReturn
+}
+
+// toAccount creates the account of the record, with the plaintext secrets of the legacy files.
+func (r *accountRecord) toAccount() *Account {
+	toReturn := &Account{
+		Address:    r.Address,
+		Type:       r.Type,
+		PublicKey:  r.PublicKey,
+		Network:    r.Network,
+		Tags:       r.Tags,
+		PrivateKey: r.PrivateKey,
+		Password:   r.Password,
+	}
+
+	if r.KeystoreAccount != nil {
+		toReturn.KeystoreAccount = *r.KeystoreAccount
+	}
+
+	return toReturn
+}
+
+// hasPlaintextSecrets reports whether the record was written before the encryption, carrying the plaintext secrets.
+func (r *accountRecord) hasPlaintextSecrets() bool {
+	return r.PrivateKey != "" || r.Password != ""
+}
+
+// hasSecrets reports whether the account carries the private key or the password.
+func (a *Account) hasSecrets() bool {
+	return a.PrivateKey != "" || a.Password != ""
+}
+
+// Store persists the accounts in the directory, one file per account, encrypting their private keys and keystore
+// passwords at rest with the envelope encryption. Every account is encrypted with its own random data key, which
+// is encrypted with the master key, so the master key can be rotated, or kept in the key management service,
+// without touching the secrets themselves. Keys of the keystore accounts remain in their keystore v3 files.
+//
+// Without the master key the store refuses to write the accounts carrying the secrets with ErrMasterKeyRequired,
+// rather than losing them. Files holding the plaintext secrets, as written by the earlier versions, are encrypted on
+// load once the master key is set and are never rewritten without it. It is safe for concurrent use.
+type Store struct {
+	path      string
+	audit     AuditLog
+	mu        sync.Mutex
+	masterKey MasterKey            // Master key encrypting the data keys, nil when no secrets can be written.
+	keys      map[string]MasterKey // Master keys decrypting the data keys by their identifiers.
+}
+
+// NewStore creates the store of the directory, creating the directory readable by the owner only when it does not
+// exist. Both the master key and the audit log may be nil.
+func NewStore(path string, masterKey MasterKey, auditLog AuditLog) (*Store, error) {
+	if err := os.MkdirAll(path, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create store directory: %w", err)
+	}
+
+	toReturn := &Store{
+		path:  path,
+		audit: auditLog,
+		keys:  make(map[string]MasterKey),
+	}
+
+	if masterKey != nil {
+		toReturn.masterKey = masterKey
+		toReturn.keys[masterKey.GetID()] = masterKey
+	}
+
+	return toReturn, nil
+}
+
+// GetPath returns the directory of the store.
+func (s *Store) GetPath() string {
+	return s.path
+}
+
+// GetMasterKey returns the master key encrypting the accounts written, nil when no secrets can be written.
+func (s *Store) GetMasterKey() MasterKey {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.masterKey
+}
+
+// AddMasterKey adds the previous master key, decrypting the accounts not yet rotated to the current one.
+func (s *Store) AddMasterKey(key MasterKey) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.keys[key.GetID()] = key
+}
+
+// GetAccountPath returns the path of the file of the account.
+func (s *Store) GetAccountPath(address common.Address) string {
+	return filepath.Join(s.path, address.Hex()+".json")
+}
+
+// List returns the addresses of the accounts of the store.
+func (s *Store) List() ([]common.Address, error) {
+	entries, err := os.ReadDir(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read store directory: %w", err)
+	}
+
+	toReturn := make([]common.Address, 0)
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), ".json")
+		if entry.IsDir() || name == entry.Name() || !common.IsHexAddress(name) {
+			continue
+		}
+		toReturn = append(toReturn, common.HexToAddress(name))
+	}
+
+	return toReturn, nil
+}
+
+// Save writes the account, encrypting its secrets with the master key. The audit log of the store is assigned to
+// the account.
+func (s *Store) Save(ctx context.Context, acc *Account) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	acc.SetAuditLog(s.audit)
+	return recordAudit(s.audit, AuditSave, acc, s.getMasterKeyID(), s.save(ctx, acc))
+}
+
+// Load reads the account, decrypting its secrets. Accounts written before the encryption are encrypted with the
+// master key right away. The audit log of the store is assigned to the account.
+func (s *Store) Load(ctx context.Context, address common.Address) (*Account, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, err := s.read(address)
+	if err != nil {
+		return nil, err
+	}
+
+	acc := record.toAccount()
+	acc.SetAuditLog(s.audit)
+
+	if record.Crypto != nil {
+		secrets, err := s.decrypt(ctx, record)
+		if err := recordAudit(s.audit, AuditLoad, acc, record.Crypto.MasterKeyID, err); err != nil {
+			return nil, err
+		}
+
+		acc.PrivateKey, acc.Password = secrets.PrivateKey, secrets.Password
+		return acc, nil
+	}
+
+	if err := recordAudit(s.audit, AuditLoad, acc, "", nil); err != nil {
+		return nil, err
+	}
+
+	if record.hasPlaintextSecrets() && s.masterKey != nil {
+		if err := recordAudit(s.audit, AuditMigrate, acc, s.masterKey.GetID(), s.save(ctx, acc)); err != nil {
+			return nil, err
+		}
+	}
+
+	return acc, nil
+}
+
+// Delete removes the file of the account. Keystore files are left to the keystore.
+func (s *Store) Delete(ctx context.Context, acc *Account) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var err error
+	if removeErr := os.Remove(s.GetAccountPath(acc.Address)); removeErr != nil && !os.IsNotExist(removeErr) {
+		err = fmt.Errorf("failed to delete account %s: %w", acc.Address.Hex(), removeErr)
+	}
+
+	return recordAudit(s.audit, AuditDelete, acc, "", err)
+}
+
+// ChangePassword changes the password of the account, writing the account with the new password first and only
+// then encrypting the keystore file of the keystore accounts with it. The previous account file is restored when
+// the keystore file cannot be encrypted, so the password of the keystore file is always the one stored.
+func (s *Store) ChangePassword(ctx context.Context, acc *Account, newPassword string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return recordAudit(s.audit, AuditChangePassword, acc, s.getMasterKeyID(), s.changePassword(ctx, acc, newPassword))
+}
+
+// RotateMasterKey makes the key the master key of the store, encrypting the data keys of all of the accounts with
+// it. Accounts written before the encryption are encrypted too. The previous master keys are kept for decrypting
+// the accounts that could not be rotated, so the rotation can be retried.
+func (s *Store) RotateMasterKey(ctx context.Context, newKey MasterKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.masterKey = newKey
+	s.keys[newKey.GetID()] = newKey
+
+	addresses, err := s.List()
+	if err != nil {
+		return err
+	}
+
+	for _, address := range addresses {
+		record, err := s.read(address)
+		if err != nil {
+			return err
+		}
+
+		acc := record.toAccount()
+
+		switch {
+		case record.Crypto != nil && record.Crypto.MasterKeyID != newKey.GetID():
+			err = s.rewrap(ctx, record, newKey)
+		case record.Crypto == nil && record.hasPlaintextSecrets():
+			err = s.save(ctx, acc)
+		default:
+			continue
+		}
+
+		if err := recordAudit(s.audit, AuditRotateKey, acc, newKey.GetID(), err); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// changePassword writes the account with the new password and encrypts its keystore file with it, restoring the
+// previous account file on failure.
+func (s *Store) changePassword(ctx context.Context, acc *Account, newPassword string) error {
+	previous, err := os.ReadFile(s.GetAccountPath(acc.Address))
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read account %s: %w", acc.Address.Hex(), err)
+	}
+
+	// Keystore is decrypted with the current password of the account, kept aside until the keystore is updated.
+	current := *acc
+	acc.Password = base64.StdEncoding.EncodeToString([]byte(newPassword))
+	if err := s.save(ctx, acc); err != nil {
+		acc.Password = current.Password
+		return err
+	}
+
+	if err := changeKeystorePassword(&current, newPassword); err != nil {
+		acc.Password = current.Password
+
+		var restoreErr error
+		if previous != nil {
+			restoreErr = writeFileAtomic(s.GetAccountPath(acc.Address), previous)
+		} else {
+			restoreErr = os.Remove(s.GetAccountPath(acc.Address))
+		}
+		if restoreErr != nil {
+			return fmt.Errorf("%w (failed to restore account %s: %s)", err, acc.Address.Hex(), restoreErr)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// getMasterKeyID returns the identifier of the master key, empty when it is not set.
+func (s *Store) getMasterKeyID() string {
+	if s.masterKey == nil {
+		return ""
+	}
+	return s.masterKey.GetID()
+}
+
+// read reads the record of the account.
+func (s *Store) read(address common.Address) (*accountRecord, error) {
+	data, err := os.ReadFile(s.GetAccountPath(address))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read account %s: %w", address.Hex(), err)
+	}
+
+	var record accountRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal account %s: %w", address.Hex(), err)
+	}
+
+	return &record, nil
+}
+
+// write writes the record of the account.
+func (s *Store) write(record *accountRecord) error {
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal account %s: %w", record.Address.Hex(), err)
+	}
+
+	return writeFileAtomic(s.GetAccountPath(record.Address), data)
+}
+
+// save encrypts the secrets of the account with the new data key and writes it. Accounts carrying the secrets are
+// not written at all when the store has no master key.
+func (s *Store) save(ctx context.Context, acc *Account) error {
+	record := newAccountRecord(acc)
+
+	if acc.hasSecrets() {
+		if s.masterKey == nil {
+			return fmt.Errorf("%w: account %s", ErrMasterKeyRequired, acc.Address.Hex())
+		}
+
+		plaintext, err := json.Marshal(&accountSecrets{PrivateKey: acc.PrivateKey, Password: acc.Password})
+		if err != nil {
+			return fmt.Errorf("failed to marshal account secrets: %w", err)
+		}
+
+		dataKey := make([]byte, 32)
+		if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+			return fmt.Errorf("failed to generate data key: %w", err)
+		}
+
+		aead, err := newAead(dataKey)
+		if err != nil {
+			return err
+		}
+
+		ciphertext, err := seal(aead, plaintext, acc.Address.Bytes())
+		if err != nil {
+			return err
+		}
+
+		wrapped, err := s.masterKey.Encrypt(ctx, dataKey)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt data key: %w", err)
+		}
+
+		record.Crypto = &accountEnvelope{
+			MasterKeyID: s.masterKey.GetID(),
+			DataKey:     wrapped,
+			Ciphertext:  ciphertext,
+		}
+	}
+
+	return s.write(record)
+}
+
+// decrypt decrypts the secrets of the record.
+func (s *Store) decrypt(ctx context.Context, record *accountRecord) (*accountSecrets, error) {
+	dataKey, err := s.unwrap(ctx, record)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newAead(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := open(aead, record.Crypto.Ciphertext, record.Address.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt account %s: %w", record.Address.Hex(), err)
+	}
+
+	var secrets accountSecrets
+	if err := json.Unmarshal(plaintext, &secrets); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal account secrets: %w", err)
+	}
+
+	return &secrets, nil
+}
+
+// unwrap decrypts the data key of the record with the master key it was encrypted with.
+func (s *Store) unwrap(ctx context.Context, record *accountRecord) ([]byte, error) {
+	key, ok := s.keys[record.Crypto.MasterKeyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: account %s is encrypted with %s", ErrUnknownMasterKey, record.Address.Hex(), record.Crypto.MasterKeyID)
+	}
+
+	dataKey, err := key.Decrypt(ctx, record.Crypto.DataKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt data key of account %s: %w", record.Address.Hex(), err)
+	}
+
+	return dataKey, nil
+}
+
+// rewrap encrypts the data key of the record with the new master key and writes the record.
+func (s *Store) rewrap(ctx context.Context, record *accountRecord, newKey MasterKey) error {
+	dataKey, err := s.unwrap(ctx, record)
+	if err != nil {
+		return err
+	}
+
+	wrapped, err := newKey.Encrypt(ctx, dataKey)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt data key: %w", err)
+	}
+
+	record.Crypto.MasterKeyID = newKey.GetID()
+	record.Crypto.DataKey = wrapped
+	return s.write(record)
+}
+
+// changeKeystorePassword encrypts the keystore file of the keystore account with the new password. Simple accounts
+// have no keystore file and are left as they are.
+func changeKeystorePassword(acc *Account, newPassword string) error {
+	if acc.Type != utils.KeystoreAccountType {
+		return nil
+	}
+
+	password, err := acc.DecodePassword()
+	if err != nil {
+		return fmt.Errorf("failed to decode password: %w", err)
+	}
+
+	if acc.KeyStore != nil {
+		if err := acc.KeyStore.Update(acc.KeystoreAccount, password, newPassword); err != nil {
+			return fmt.Errorf("failed to update keystore password: %w", err)
+		}
+		return nil
+	}
+
+	keyJson, err := os.ReadFile(acc.KeystoreAccount.URL.Path)
+	if err != nil {
+		return fmt.Errorf("failed to read keystore file: %w", err)
+	}
+
+	key, err := keystore.DecryptKey(keyJson, password)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt key: %w", err)
+	}
+
+	keyJson, err = keystore.EncryptKey(key, newPassword, keystore.StandardScryptN, keystore.StandardScryptP)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt key: %w", err)
+	}
+
+	return writeFileAtomic(acc.KeystoreAccount.URL.Path, keyJson)
+}
+
+// writeFileAtomic writes the file readable by the owner only, replacing it at once so that no partially written
+// file is ever left behind.
+func writeFileAtomic(path string, data []byte) error {
+	file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write temporary file: %w", err)
+	}
+
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to sync temporary file: %w", err)
+	}
+
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to close temporary file: %w", err)
+	}
+
+	if err := os.Rename(file.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,537 @@
+package accounts
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unpackdev/solgo/utils"
+)
+
+func newTestMasterKey(t *testing.T, seed string) *LocalMasterKey {
+	key, err := NewLocalMasterKey(crypto.Keccak256([]byte(seed)))
+	require.NoError(t, err)
+	return key
+}
+
+func TestMasterKeys(t *testing.T) {
+	key := crypto.Keccak256([]byte("master"))
+	expected, err := NewLocalMasterKey(key)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	writeKeyFile := func(name string, content string, perm os.FileMode) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), perm))
+		require.NoError(t, os.Chmod(path, perm))
+		return path
+	}
+
+	testCases := []struct {
+		name        string
+		env         string
+		newKey      func() (*LocalMasterKey, error)
+		expectedErr error
+	}{
+		{
+			name: "Hex Environment Variable",
+			env:  "0x" + hex.EncodeToString(key),
+			newKey: func() (*LocalMasterKey, error) {
+				return NewEnvMasterKey("SOLGO_TEST_MASTER_KEY")
+			},
+		},
+		{
+			name: "Base64 Environment Variable",
+			env:  base64.StdEncoding.EncodeToString(key),
+			newKey: func() (*LocalMasterKey, error) {
+				return NewEnvMasterKey("SOLGO_TEST_MASTER_KEY")
+			},
+		},
+		{
+			name: "Missing Environment Variable",
+			newKey: func() (*LocalMasterKey, error) {
+				return NewEnvMasterKey("SOLGO_TEST_MISSING_MASTER_KEY")
+			},
+			expectedErr: ErrInvalidMasterKey,
+		},
+		{
+			name: "Short Key",
+			env:  hex.EncodeToString(key[:16]),
+			newKey: func() (*LocalMasterKey, error) {
+				return NewEnvMasterKey("SOLGO_TEST_MASTER_KEY")
+			},
+			expectedErr: ErrInvalidMasterKey,
+		},
+		{
+			name: "Key File",
+			newKey: func() (*LocalMasterKey, error) {
+				return NewFileMasterKey(writeKeyFile("master.key", hex.EncodeToString(key)+"\n", 0600))
+			},
+		},
+		{
+			name: "Key File Readable By Others",
+			newKey: func() (*LocalMasterKey, error) {
+				return NewFileMasterKey(writeKeyFile("insecure.key", hex.EncodeToString(key), 0644))
+			},
+			expectedErr: ErrInsecureKeyFile,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("SOLGO_TEST_MASTER_KEY", tc.env)
+
+			masterKey, err := tc.newKey()
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, expected.GetID(), masterKey.GetID())
+
+			ciphertext, err := masterKey.Encrypt(context.Background(), []byte("data key"))
+			require.NoError(t, err)
+
+			plaintext, err := expected.Decrypt(context.Background(), ciphertext)
+			require.NoError(t, err)
+			assert.Equal(t, []byte("data key"), plaintext)
+
+			ciphertext[len(ciphertext)-1] ^= 0xff
+			_, err = masterKey.Decrypt(context.Background(), ciphertext)
+			assert.ErrorIs(t, err, ErrDecryptionFailed)
+		})
+	}
+}
+
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+	privateKey := crypto.Keccak256([]byte("cow"))
+	address := common.HexToAddress("0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826")
+	password := base64.StdEncoding.EncodeToString([]byte("secret"))
+
+	testCases := []struct {
+		name          string
+		masterKey     MasterKey
+		account       *Account
+		expectSecrets bool
+		expectedErr   error
+	}{
+		{
+			name:      "Simple Account",
+			masterKey: newTestMasterKey(t, "master"),
+			account: &Account{
+				Address:    address,
+				Type:       utils.SimpleAccountType,
+				PrivateKey: fmt.Sprintf("%x", privateKey),
+				Password:   password,
+				Network:    utils.Ethereum,
+				Tags:       []string{"bot"},
+			},
+			expectSecrets: true,
+		},
+		{
+			name:      "Keystore Account",
+			masterKey: newTestMasterKey(t, "master"),
+			account: &Account{
+				Address:  address,
+				Type:     utils.KeystoreAccountType,
+				Password: password,
+				Network:  utils.AnvilNetwork,
+			},
+			expectSecrets: true,
+		},
+		{
+			name: "Without Master Key",
+			account: &Account{
+				Address:    address,
+				Type:       utils.SimpleAccountType,
+				PrivateKey: fmt.Sprintf("%x", privateKey),
+				Password:   password,
+				Network:    utils.Ethereum,
+			},
+			expectedErr: ErrMasterKeyRequired,
+		},
+		{
+			name: "Without Master Key And Secrets",
+			account: &Account{
+				Address: address,
+				Type:    utils.SimpleAccountType,
+				Network: utils.Ethereum,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			auditPath := filepath.Join(t.TempDir(), "audit.log")
+			auditLog, err := NewFileAuditLog(auditPath)
+			require.NoError(t, err)
+			defer auditLog.Close()
+
+			store, err := NewStore(t.TempDir(), tc.masterKey, auditLog)
+			require.NoError(t, err)
+
+			err = store.Save(ctx, tc.account)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				assert.NoFileExists(t, store.GetAccountPath(address))
+				return
+			}
+			require.NoError(t, err)
+
+			data, err := os.ReadFile(store.GetAccountPath(address))
+			require.NoError(t, err)
+			assert.NotContains(t, string(data), fmt.Sprintf("%x", privateKey))
+			assert.NotContains(t, string(data), password)
+			assert.NotContains(t, string(data), "secret")
+
+			info, err := os.Stat(store.GetAccountPath(address))
+			require.NoError(t, err)
+			assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+			addresses, err := store.List()
+			require.NoError(t, err)
+			assert.Equal(t, []common.Address{address}, addresses)
+
+			loaded, err := store.Load(ctx, address)
+			require.NoError(t, err)
+			assert.Equal(t, tc.account.Type, loaded.Type)
+			assert.Equal(t, tc.account.Network, loaded.Network)
+			assert.Equal(t, tc.account.Tags, loaded.Tags)
+			assert.Equal(t, auditLog, loaded.GetAuditLog())
+
+			if tc.expectSecrets {
+				assert.Equal(t, tc.account.PrivateKey, loaded.PrivateKey)
+				assert.Equal(t, tc.account.Password, loaded.Password)
+			} else {
+				assert.Empty(t, loaded.PrivateKey)
+				assert.Empty(t, loaded.Password)
+			}
+
+			require.NoError(t, store.Delete(ctx, loaded))
+			_, err = store.Load(ctx, address)
+			assert.Error(t, err)
+
+			assert.Equal(t, []AuditAction{AuditSave, AuditLoad, AuditDelete}, readAuditActions(t, auditPath))
+		})
+	}
+}
+
+func TestStoreMigrationAndRotation(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	key, err := crypto.ToECDSA(crypto.Keccak256([]byte("cow")))
+	require.NoError(t, err)
+
+	ks := keystore.NewKeyStore(dir, keystore.LightScryptN, keystore.LightScryptP)
+	keystoreAccount, err := ks.ImportECDSA(key, "secret")
+	require.NoError(t, err)
+
+	address := keystoreAccount.Address
+	password := base64.StdEncoding.EncodeToString([]byte("secret"))
+
+	// Account file written before the encryption, with the plaintext password.
+	legacy := fmt.Sprintf(`{
+  "address": "%s",
+  "type": "keystore",
+  "private_key": "",
+  "public_key": "",
+  "account": {
+    "address": "%s",
+    "url": "%s"
+  },
+  "password": "%s",
+  "network": "anvil",
+  "tags": ["simulator"]
+}`, strings.ToLower(address.Hex()), strings.ToLower(address.Hex()), keystoreAccount.URL.String(), password)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, address.Hex()+".json"), []byte(legacy), 0600))
+
+	// Without the master key the legacy file is neither migrated nor overwritten, it is the only copy of the password.
+	plainStore, err := NewStore(dir, nil, nil)
+	require.NoError(t, err)
+
+	acc, err := plainStore.Load(ctx, address)
+	require.NoError(t, err)
+	assert.Equal(t, password, acc.Password)
+	assert.ErrorIs(t, plainStore.Save(ctx, acc), ErrMasterKeyRequired)
+
+	data, err := os.ReadFile(plainStore.GetAccountPath(address))
+	require.NoError(t, err)
+	assert.Equal(t, legacy, string(data))
+
+	previousKey := newTestMasterKey(t, "previous")
+	store, err := NewStore(dir, previousKey, nil)
+	require.NoError(t, err)
+
+	acc, err = store.Load(ctx, address)
+	require.NoError(t, err)
+	assert.Equal(t, password, acc.Password)
+	assert.Equal(t, keystoreAccount.URL, acc.KeystoreAccount.URL)
+
+	data, err = os.ReadFile(store.GetAccountPath(address))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), password)
+	assert.Contains(t, string(data), previousKey.GetID())
+
+	newKey := newTestMasterKey(t, "new")
+	require.NoError(t, store.RotateMasterKey(ctx, newKey))
+	assert.Equal(t, newKey.GetID(), store.GetMasterKey().GetID())
+
+	rotated, err := os.ReadFile(store.GetAccountPath(address))
+	require.NoError(t, err)
+	assert.Contains(t, string(rotated), newKey.GetID())
+	assert.NotContains(t, string(rotated), previousKey.GetID())
+
+	testCases := []struct {
+		name        string
+		masterKey   MasterKey
+		expectedErr error
+	}{
+		{
+			name:      "New Master Key",
+			masterKey: newKey,
+		},
+		{
+			name:        "Previous Master Key",
+			masterKey:   previousKey,
+			expectedErr: ErrUnknownMasterKey,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			store, err := NewStore(dir, tc.masterKey, nil)
+			require.NoError(t, err)
+
+			acc, err := store.Load(ctx, address)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, password, acc.Password)
+			assert.Equal(t, []string{"simulator"}, acc.Tags)
+
+			signature, err := acc.SignPersonalMessage([]byte("rotated"))
+			require.NoError(t, err)
+
+			recovered, err := RecoverPersonalMessage([]byte("rotated"), signature)
+			require.NoError(t, err)
+			assert.Equal(t, address, recovered)
+		})
+	}
+}
+
+func TestStoreChangePassword(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	key, err := crypto.ToECDSA(crypto.Keccak256([]byte("cow")))
+	require.NoError(t, err)
+
+	ks := keystore.NewKeyStore(dir, keystore.LightScryptN, keystore.LightScryptP)
+	keystoreAccount, err := ks.ImportECDSA(key, "secret")
+	require.NoError(t, err)
+
+	auditPath := filepath.Join(t.TempDir(), "audit.log")
+	auditLog, err := NewFileAuditLog(auditPath)
+	require.NoError(t, err)
+	defer auditLog.Close()
+
+	store, err := NewStore(dir, newTestMasterKey(t, "master"), auditLog)
+	require.NoError(t, err)
+
+	acc := &Account{
+		KeyStore:        ks,
+		Address:         keystoreAccount.Address,
+		Type:            utils.KeystoreAccountType,
+		KeystoreAccount: keystoreAccount,
+		Password:        base64.StdEncoding.EncodeToString([]byte("secret")),
+		Network:         utils.AnvilNetwork,
+	}
+	require.NoError(t, store.Save(ctx, acc))
+	require.NoError(t, store.ChangePassword(ctx, acc, "rotated secret"))
+
+	keyJson, err := os.ReadFile(keystoreAccount.URL.Path)
+	require.NoError(t, err)
+
+	_, err = keystore.DecryptKey(keyJson, "secret")
+	assert.Error(t, err)
+
+	_, err = keystore.DecryptKey(keyJson, "rotated secret")
+	require.NoError(t, err)
+
+	loaded, err := store.Load(ctx, acc.Address)
+	require.NoError(t, err)
+
+	password, err := loaded.DecodePassword()
+	require.NoError(t, err)
+	assert.Equal(t, "rotated secret", password)
+
+	_, err = loaded.SignPersonalMessage([]byte("hello"))
+	require.NoError(t, err)
+
+	assert.Equal(t, []AuditAction{AuditSave, AuditChangePassword, AuditLoad, AuditSign}, readAuditActions(t, auditPath))
+}
+
+func TestStoreChangePasswordFailure(t *testing.T) {
+	ctx := context.Background()
+	password := base64.StdEncoding.EncodeToString([]byte("secret"))
+
+	testCases := []struct {
+		name            string
+		masterKey       MasterKey
+		removeKeystore  bool
+		expectedErr     error
+		expectedAccount bool
+	}{
+		{
+			name:        "Without Master Key",
+			expectedErr: ErrMasterKeyRequired,
+		},
+		{
+			name:            "Keystore Update Failure",
+			masterKey:       newTestMasterKey(t, "master"),
+			removeKeystore:  true,
+			expectedAccount: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+
+			key, err := crypto.ToECDSA(crypto.Keccak256([]byte("cow")))
+			require.NoError(t, err)
+
+			ks := keystore.NewKeyStore(dir, keystore.LightScryptN, keystore.LightScryptP)
+			keystoreAccount, err := ks.ImportECDSA(key, "secret")
+			require.NoError(t, err)
+
+			store, err := NewStore(dir, tc.masterKey, nil)
+			require.NoError(t, err)
+
+			acc := &Account{
+				Address:         keystoreAccount.Address,
+				Type:            utils.KeystoreAccountType,
+				KeystoreAccount: keystoreAccount,
+				Password:        password,
+				Network:         utils.AnvilNetwork,
+			}
+			if tc.expectedAccount {
+				require.NoError(t, store.Save(ctx, acc))
+			}
+
+			keyJson, err := os.ReadFile(keystoreAccount.URL.Path)
+			require.NoError(t, err)
+			if tc.removeKeystore {
+				require.NoError(t, os.Remove(keystoreAccount.URL.Path))
+			}
+
+			err = store.ChangePassword(ctx, acc, "rotated secret")
+			require.Error(t, err)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+			}
+			assert.Equal(t, password, acc.Password)
+
+			if tc.removeKeystore {
+				require.NoError(t, os.WriteFile(keystoreAccount.URL.Path, keyJson, 0600))
+			}
+
+			// Keystore is left encrypted with the password of the account file.
+			current, err := os.ReadFile(keystoreAccount.URL.Path)
+			require.NoError(t, err)
+			_, err = keystore.DecryptKey(current, "secret")
+			require.NoError(t, err)
+
+			if !tc.expectedAccount {
+				assert.NoFileExists(t, store.GetAccountPath(acc.Address))
+				return
+			}
+
+			loaded, err := store.Load(ctx, acc.Address)
+			require.NoError(t, err)
+			assert.Equal(t, password, loaded.Password)
+		})
+	}
+}
+
+func TestSaveToPath(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "account.json")
+	acc := &Account{
+		Address:    common.HexToAddress("0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"),
+		Type:       utils.SimpleAccountType,
+		PrivateKey: fmt.Sprintf("%x", crypto.Keccak256([]byte("cow"))),
+		Password:   base64.StdEncoding.EncodeToString([]byte("secret")),
+		Network:    utils.Ethereum,
+	}
+	assert.ErrorIs(t, acc.SaveToPath(path), ErrMasterKeyRequired)
+	assert.NoFileExists(t, path)
+
+	acc.PrivateKey, acc.Password = "", ""
+	require.NoError(t, acc.SaveToPath(path))
+
+	loaded, err := LoadAccount(path)
+	require.NoError(t, err)
+	assert.Equal(t, acc.Address, loaded.Address)
+	assert.Equal(t, acc.Network, loaded.Network)
+
+	// Files written before the encryption load with their plaintext secrets.
+	privateKey := fmt.Sprintf("%x", crypto.Keccak256([]byte("cow")))
+	legacy := fmt.Sprintf(`{"address": "%s", "type": "simple", "private_key": "%s", "network": "ethereum"}`, acc.Address.Hex(), privateKey)
+	require.NoError(t, os.WriteFile(path, []byte(legacy), 0600))
+
+	loaded, err = LoadAccount(path)
+	require.NoError(t, err)
+	assert.Equal(t, privateKey, loaded.PrivateKey)
+
+	// Secrets are never serialized along with the account.
+	data, err := json.Marshal(loaded)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), privateKey)
+
+	// Files written by the store carry the encrypted secrets only the store can decrypt.
+	store, err := NewStore(t.TempDir(), newTestMasterKey(t, "master"), nil)
+	require.NoError(t, err)
+	require.NoError(t, store.Save(context.Background(), loaded))
+
+	_, err = LoadAccount(store.GetAccountPath(loaded.Address))
+	assert.ErrorIs(t, err, ErrEncryptedAccount)
+}
+
+// readAuditActions returns the actions of the audit log, asserting no secrets were written to it.
+func readAuditActions(t *testing.T, path string) []AuditAction {
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var actions []AuditAction
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		assert.False(t, strings.Contains(scanner.Text(), "secret"))
+
+		var event AuditEvent
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+		assert.Empty(t, event.Error)
+		assert.False(t, event.Time.IsZero())
+		actions = append(actions, event.Action)
+	}
+	require.NoError(t, scanner.Err())
+
+	return actions
+}
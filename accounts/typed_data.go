@@ -0,0 +1,281 @@
+package accounts
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// Permit2Address is the address of the Uniswap Permit2 contract, the same on all of the supported networks.
+var Permit2Address = common.HexToAddress("0x000000000022D473030F116dDEE9F6B43aC78BA3")
+
+// NewDomain creates the EIP-712 domain of the verifying contract. Name and version are left out of the domain
+// when empty, as some of the contracts, such as Permit2 and Safe, do not use them.
+func NewDomain(name string, version string, chainID *big.Int, verifyingContract common.Address) apitypes.TypedDataDomain {
+	return apitypes.TypedDataDomain{
+		Name:              name,
+		Version:           version,
+		ChainId:           (*math.HexOrDecimal256)(chainID),
+		VerifyingContract: verifyingContract.Hex(),
+	}
+}
+
+// DomainType returns the EIP712Domain type of the domain, made of the fields the domain sets, in the order
+// defined by EIP-712.
+func DomainType(domain apitypes.TypedDataDomain) []apitypes.Type {
+	toReturn := make([]apitypes.Type, 0, 5)
+	if domain.Name != "" {
+		toReturn = append(toReturn, apitypes.Type{Name: "name", Type: "string"})
+	}
+	if domain.Version != "" {
+		toReturn = append(toReturn, apitypes.Type{Name: "version", Type: "string"})
+	}
+	if domain.ChainId != nil {
+		toReturn = append(toReturn, apitypes.Type{Name: "chainId", Type: "uint256"})
+	}
+	if domain.VerifyingContract != "" {
+		toReturn = append(toReturn, apitypes.Type{Name: "verifyingContract", Type: "address"})
+	}
+	if domain.Salt != "" {
+		toReturn = append(toReturn, apitypes.Type{Name: "salt", Type: "bytes32"})
+	}
+
+	return toReturn
+}
+
+// DomainSeparator computes the EIP-712 domain separator of the domain, the value the verifying contracts expose
+// as DOMAIN_SEPARATOR().
+func DomainSeparator(domain apitypes.TypedDataDomain) (common.Hash, error) {
+	typedData := apitypes.TypedData{
+		Types:  apitypes.Types{"EIP712Domain": DomainType(domain)},
+		Domain: domain,
+	}
+
+	separator, err := typedData.HashStruct("EIP712Domain", domain.Map())
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to hash domain: %w", err)
+	}
+
+	return common.BytesToHash(separator), nil
+}
+
+// TypedDataHash computes the EIP-712 hash of the typed structured data, keccak256("\x19\x01" || domainSeparator ||
+// hashStruct(message)). The EIP712Domain type is derived from the domain when the types do not declare it.
+func TypedDataHash(typedData apitypes.TypedData) (common.Hash, error) {
+	if _, ok := typedData.Types["EIP712Domain"]; !ok {
+		types := make(apitypes.Types, len(typedData.Types)+1)
+		for name, fields := range typedData.Types {
+			types[name] = fields
+		}
+		types["EIP712Domain"] = DomainType(typedData.Domain)
+		typedData.Types = types
+	}
+
+	hash, _, err := apitypes.TypedDataAndHash(typedData)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to hash typed data: %w", err)
+	}
+
+	return common.BytesToHash(hash), nil
+}
+
+// Permit is the ERC-2612 permit approving the spender to transfer the tokens of the owner, signed by the owner.
+type Permit struct {
+	Owner    common.Address `json:"owner"`
+	Spender  common.Address `json:"spender"`
+	Value    *big.Int       `json:"value"`
+	Nonce    *big.Int       `json:"nonce"`    // Current nonces(owner) of the token.
+	Deadline *big.Int       `json:"deadline"` // Timestamp the permit expires at.
+}
+
+// TypedData returns the EIP-712 typed data of the permit within the domain of the token, commonly made of the
+// name of the token, the version "1", the chain id and the token address.
+func (p *Permit) TypedData(domain apitypes.TypedDataDomain) apitypes.TypedData {
+	return apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": DomainType(domain),
+			"Permit": {
+				{Name: "owner", Type: "address"},
+				{Name: "spender", Type: "address"},
+				{Name: "value", Type: "uint256"},
+				{Name: "nonce", Type: "uint256"},
+				{Name: "deadline", Type: "uint256"},
+			},
+		},
+		PrimaryType: "Permit",
+		Domain:      domain,
+		Message: apitypes.TypedDataMessage{
+			"owner":    p.Owner.Hex(),
+			"spender":  p.Spender.Hex(),
+			"value":    p.Value,
+			"nonce":    p.Nonce,
+			"deadline": p.Deadline,
+		},
+	}
+}
+
+// NewPermit2Domain creates the EIP-712 domain of the Permit2 contract on the chain.
+func NewPermit2Domain(chainID *big.Int) apitypes.TypedDataDomain {
+	return NewDomain("Permit2", "", chainID, Permit2Address)
+}
+
+// Permit2Details is the allowance of the token set by the Permit2 allowance transfer permit.
+type Permit2Details struct {
+	Token      common.Address `json:"token"`
+	Amount     *big.Int       `json:"amount"`     // Allowed amount, uint160.
+	Expiration *big.Int       `json:"expiration"` // Timestamp the allowance expires at, uint48.
+	Nonce      *big.Int       `json:"nonce"`      // Current allowance nonce of the owner, token and spender, uint48.
+}
+
+// Permit2Single is the Permit2 allowance transfer permit, PermitSingle, setting the allowance of the spender
+// over the single token.
+type Permit2Single struct {
+	Details     Permit2Details `json:"details"`
+	Spender     common.Address `json:"spender"`
+	SigDeadline *big.Int       `json:"sigDeadline"` // Timestamp the signature expires at.
+}
+
+// TypedData returns the EIP-712 typed data of the permit within the Permit2 domain.
+func (p *Permit2Single) TypedData(domain apitypes.TypedDataDomain) apitypes.TypedData {
+	return apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": DomainType(domain),
+			"PermitSingle": {
+				{Name: "details", Type: "PermitDetails"},
+				{Name: "spender", Type: "address"},
+				{Name: "sigDeadline", Type: "uint256"},
+			},
+			"PermitDetails": {
+				{Name: "token", Type: "address"},
+				{Name: "amount", Type: "uint160"},
+				{Name: "expiration", Type: "uint48"},
+				{Name: "nonce", Type: "uint48"},
+			},
+		},
+		PrimaryType: "PermitSingle",
+		Domain:      domain,
+		Message: apitypes.TypedDataMessage{
+			"details": map[string]interface{}{
+				"token":      p.Details.Token.Hex(),
+				"amount":     p.Details.Amount,
+				"expiration": p.Details.Expiration,
+				"nonce":      p.Details.Nonce,
+			},
+			"spender":     p.Spender.Hex(),
+			"sigDeadline": p.SigDeadline,
+		},
+	}
+}
+
+// Permit2TransferFrom is the Permit2 signature transfer permit, PermitTransferFrom, allowing the spender to
+// transfer the tokens once.
+type Permit2TransferFrom struct {
+	Token    common.Address `json:"token"`
+	Amount   *big.Int       `json:"amount"`
+	Spender  common.Address `json:"spender"`  // Address calling permitTransferFrom.
+	Nonce    *big.Int       `json:"nonce"`    // Unordered nonce, set in the nonce bitmap of the owner once used.
+	Deadline *big.Int       `json:"deadline"` // Timestamp the signature expires at.
+}
+
+// TypedData returns the EIP-712 typed data of the permit within the Permit2 domain.
+func (p *Permit2TransferFrom) TypedData(domain apitypes.TypedDataDomain) apitypes.TypedData {
+	return apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": DomainType(domain),
+			"PermitTransferFrom": {
+				{Name: "permitted", Type: "TokenPermissions"},
+				{Name: "spender", Type: "address"},
+				{Name: "nonce", Type: "uint256"},
+				{Name: "deadline", Type: "uint256"},
+			},
+			"TokenPermissions": {
+				{Name: "token", Type: "address"},
+				{Name: "amount", Type: "uint256"},
+			},
+		},
+		PrimaryType: "PermitTransferFrom",
+		Domain:      domain,
+		Message: apitypes.TypedDataMessage{
+			"permitted": map[string]interface{}{
+				"token":  p.Token.Hex(),
+				"amount": p.Amount,
+			},
+			"spender":  p.Spender.Hex(),
+			"nonce":    p.Nonce,
+			"deadline": p.Deadline,
+		},
+	}
+}
+
+// NewSafeDomain creates the EIP-712 domain of the Safe, made of the chain id and the Safe address since Safe
+// 1.3.0.
+func NewSafeDomain(chainID *big.Int, safe common.Address) apitypes.TypedDataDomain {
+	return NewDomain("", "", chainID, safe)
+}
+
+// SafeTransaction is the transaction executed by the Safe through execTransaction once signed by its owners.
+type SafeTransaction struct {
+	To             common.Address `json:"to"`
+	Value          *big.Int       `json:"value"`
+	Data           []byte         `json:"data"`
+	Operation      uint8          `json:"operation"` // 0 for the call, 1 for the delegate call.
+	SafeTxGas      *big.Int       `json:"safeTxGas"`
+	BaseGas        *big.Int       `json:"baseGas"`
+	GasPrice       *big.Int       `json:"gasPrice"`
+	GasToken       common.Address `json:"gasToken"`
+	RefundReceiver common.Address `json:"refundReceiver"`
+	Nonce          *big.Int       `json:"nonce"` // Current nonce() of the Safe.
+}
+
+// TypedData returns the EIP-712 typed data of the transaction within the domain of the Safe. Unset amounts are
+// encoded as zero.
+func (t *SafeTransaction) TypedData(domain apitypes.TypedDataDomain) apitypes.TypedData {
+	return apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": DomainType(domain),
+			"SafeTx": {
+				{Name: "to", Type: "address"},
+				{Name: "value", Type: "uint256"},
+				{Name: "data", Type: "bytes"},
+				{Name: "operation", Type: "uint8"},
+				{Name: "safeTxGas", Type: "uint256"},
+				{Name: "baseGas", Type: "uint256"},
+				{Name: "gasPrice", Type: "uint256"},
+				{Name: "gasToken", Type: "address"},
+				{Name: "refundReceiver", Type: "address"},
+				{Name: "nonce", Type: "uint256"},
+			},
+		},
+		PrimaryType: "SafeTx",
+		Domain:      domain,
+		Message: apitypes.TypedDataMessage{
+			"to":             t.To.Hex(),
+			"value":          orZero(t.Value),
+			"data":           common.CopyBytes(t.Data),
+			"operation":      big.NewInt(int64(t.Operation)),
+			"safeTxGas":      orZero(t.SafeTxGas),
+			"baseGas":        orZero(t.BaseGas),
+			"gasPrice":       orZero(t.GasPrice),
+			"gasToken":       t.GasToken.Hex(),
+			"refundReceiver": t.RefundReceiver.Hex(),
+			"nonce":          orZero(t.Nonce),
+		},
+	}
+}
+
+// SafeTransactionHash computes the hash of the transaction the owners of the Safe sign, the value returned by
+// getTransactionHash of the Safe.
+func SafeTransactionHash(chainID *big.Int, safe common.Address, tx *SafeTransaction) (common.Hash, error) {
+	return TypedDataHash(tx.TypedData(NewSafeDomain(chainID, safe)))
+}
+
+// orZero returns the amount, zero when it is not set.
+func orZero(amount *big.Int) *big.Int {
+	if amount == nil {
+		return new(big.Int)
+	}
+	return amount
+}
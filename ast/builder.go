@@ -19,6 +19,8 @@ type ASTBuilder struct {
 	sources                     *solgo.Sources         // sources is the source code of the Solidity files.
 	parser                      *parser.SolidityParser // parser is the Solidity parser instance.
 	nextID                      int64                  // nextID is the next ID to assign to a node.
+	nextDetachedID              int64                  // nextDetachedID is the next ID to assign to a detached node.
+	detached                    int32                  // detached is the depth of the detached nodes being parsed.
 	comments                    []*Comment
 	commentsParsed              bool
 	sourceUnits                 []*SourceUnit[Node[ast_pb.SourceUnit]]
@@ -54,6 +56,7 @@ func NewAstBuilder(parser *parser.SolidityParser, sources *solgo.Sources) *ASTBu
 		currentVariables:            make([]Node[NodeType], 0),
 		globalDefinitions:           make([]Node[NodeType], 0),
 		nextID:                      1,
+		nextDetachedID:              -1,
 	}
 
 	// Used for resolving references.
@@ -3,8 +3,23 @@ package ast
 import "sync/atomic"
 
 // GetNextID generates the next unique identifier for nodes in the abstract syntax tree (AST).
-// It uses an atomic operation to ensure thread safety.
+// It uses an atomic operation to ensure thread safety. Detached nodes, see withDetachedIDs, take
+// negative identifiers instead.
 func (b *ASTBuilder) GetNextID() int64 {
+	if atomic.LoadInt32(&b.detached) > 0 {
+		// Decrement the value of b.nextDetachedID atomically and then add 1 to get the next unique ID.
+		return atomic.AddInt64(&b.nextDetachedID, -1) + 1
+	}
+
 	// Increment the value of b.nextID atomically and then subtract 1 to get the next unique ID.
 	return atomic.AddInt64(&b.nextID, 1) - 1
 }
+
+// withDetachedIDs runs the function with the nodes it creates detached from the ID sequence of the
+// tree. The Yul nodes the inline assembly analysis needs but the tree did not have before, such as
+// the switch expressions and default cases, are detached so the IDs of the other nodes do not shift.
+func (b *ASTBuilder) withDetachedIDs(fn func()) {
+	atomic.AddInt32(&b.detached, 1)
+	defer atomic.AddInt32(&b.detached, -1)
+	fn()
+}
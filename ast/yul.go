@@ -1,6 +1,8 @@
 package ast
 
 import (
+	"strings"
+
 	ast_pb "github.com/unpackdev/protos/dist/go/ast"
 	"github.com/unpackdev/solgo/parser"
 )
@@ -12,6 +14,7 @@ type Yul struct {
 	Id       int64           `json:"id"`        // Id uniquely identifies the assembly statement.
 	NodeType ast_pb.NodeType `json:"node_type"` // NodeType specifies the type of the node.
 	Src      SrcNode         `json:"src"`       // Src contains source location details of the node.
+	Flags    []string        `json:"flags"`     // Flags are the assembly block flags, e.g. "memory-safe".
 	Body     *BodyNode       `json:"body"`      // Body represents the content of the assembly statement.
 }
 
@@ -21,6 +24,7 @@ func NewYul(b *ASTBuilder) *Yul {
 		ASTBuilder: b,
 		Id:         b.GetNextID(),
 		NodeType:   ast_pb.NodeType_ASSEMBLY_STATEMENT,
+		Flags:      make([]string, 0),
 	}
 }
 
@@ -39,6 +43,21 @@ func (a *Yul) GetSrc() SrcNode {
 	return a.Src
 }
 
+// GetFlags returns the assembly block flags, e.g. "memory-safe".
+func (a *Yul) GetFlags() []string {
+	return a.Flags
+}
+
+// IsMemorySafe returns true if the assembly block is annotated as memory-safe.
+func (a *Yul) IsMemorySafe() bool {
+	for _, flag := range a.Flags {
+		if flag == "memory-safe" {
+			return true
+		}
+	}
+	return false
+}
+
 // GetBody returns the content of the assembly statement.
 func (a *Yul) GetBody() *BodyNode {
 	return a.Body
@@ -92,15 +111,27 @@ func (a *Yul) Parse(
 		ParentIndex: bodyNode.GetId(),
 	}
 
+	if ctx.AssemblyFlags() != nil {
+		for _, flag := range ctx.AssemblyFlags().AllAssemblyFlagString() {
+			a.Flags = append(a.Flags, strings.Trim(flag.GetText(), "\""))
+		}
+	}
+
 	a.Body = NewBodyNode(a.ASTBuilder, false)
 	a.Body.Src = a.Src
 	a.Body.Src.ParentIndex = a.Id
 	a.Body.NodeType = ast_pb.NodeType_YUL_BLOCK
 	a.Body.Statements = make([]Node[NodeType], 0)
 
-	yulStatement := NewYulStatement(a.ASTBuilder)
+	for i, yulCtx := range ctx.AllYulStatement() {
+		// The statements used to share the first statement node, so the others are detached.
+		var yulStatement *YulStatement
+		if i == 0 {
+			yulStatement = NewYulStatement(a.ASTBuilder)
+		} else {
+			a.withDetachedIDs(func() { yulStatement = NewYulStatement(a.ASTBuilder) })
+		}
 
-	for _, yulCtx := range ctx.AllYulStatement() {
 		a.Body.Statements = append(a.Body.Statements,
 			yulStatement.Parse(
 				unit, contractNode, fnNode, a.Body, a, a, yulCtx.(*parser.YulStatementContext),
@@ -180,21 +180,7 @@ func (y *YulAssignment) Parse(
 
 	if ctx.AllYulPath() != nil {
 		for _, path := range ctx.AllYulPath() {
-			for _, identifier := range path.AllYulIdentifier() {
-				y.VariableNames = append(y.VariableNames, &YulIdentifier{
-					Id:       y.GetNextID(),
-					NodeType: ast_pb.NodeType_YUL_IDENTIFIER,
-					Src: SrcNode{
-						Line:        int64(identifier.GetSymbol().GetLine()),
-						Column:      int64(identifier.GetSymbol().GetColumn()),
-						Start:       int64(identifier.GetSymbol().GetStart()),
-						End:         int64(identifier.GetSymbol().GetStop()),
-						Length:      int64(identifier.GetSymbol().GetStop() - identifier.GetSymbol().GetStart() + 1),
-						ParentIndex: y.GetId(),
-					},
-					Name: identifier.GetText(),
-				})
-			}
+			y.VariableNames = append(y.VariableNames, parseYulPath(y.ASTBuilder, y, path))
 		}
 	}
 
@@ -162,6 +162,10 @@ func (y *YulExpressionStatement) Parse(
 		)
 	}
 
+	if ctx.YulPath() != nil {
+		y.withDetachedIDs(func() { y.Expression = parseYulPath(y.ASTBuilder, y, ctx.YulPath()) })
+	}
+
 	if ctx.YulFunctionCall() != nil {
 		fcStatement := NewYulFunctionCallStatement(y.ASTBuilder)
 		y.Expression = fcStatement.Parse(
@@ -195,6 +199,12 @@ func ParseYulExpression(
 		)
 	}
 
+	if ctx.YulPath() != nil {
+		var toReturn *YulIdentifier
+		b.withDetachedIDs(func() { toReturn = parseYulPath(b, parentNode, ctx.YulPath()) })
+		return toReturn
+	}
+
 	if ctx.YulFunctionCall() != nil {
 		fcStatement := NewYulFunctionCallStatement(b)
 		return fcStatement.Parse(
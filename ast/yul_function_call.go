@@ -215,22 +215,7 @@ func (y *YulFunctionCallStatement) Parse(
 	if ctx.AllYulExpression() != nil {
 		for _, expression := range ctx.AllYulExpression() {
 			if expression.YulPath() != nil {
-				path := expression.YulPath()
-				for _, identifier := range path.AllYulIdentifier() {
-					y.Arguments = append(y.Arguments, &YulIdentifier{
-						Id:       y.GetNextID(),
-						NodeType: ast_pb.NodeType_YUL_IDENTIFIER,
-						Name:     identifier.GetText(),
-						Src: SrcNode{
-							Line:        int64(identifier.GetSymbol().GetLine()),
-							Column:      int64(identifier.GetSymbol().GetColumn()),
-							Start:       int64(identifier.GetSymbol().GetStart()),
-							End:         int64(identifier.GetSymbol().GetStop()),
-							Length:      int64(identifier.GetSymbol().GetStop() - identifier.GetSymbol().GetStart() + 1),
-							ParentIndex: y.GetId(),
-						},
-					})
-				}
+				y.Arguments = append(y.Arguments, parseYulPath(y.ASTBuilder, y, expression.YulPath()))
 			}
 
 			if expression.YulFunctionCall() != nil {
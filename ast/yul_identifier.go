@@ -2,6 +2,7 @@ package ast
 
 import (
 	ast_pb "github.com/unpackdev/protos/dist/go/ast"
+	"github.com/unpackdev/solgo/parser"
 )
 
 // YulIdentifier represents a YUL identifier in the abstract syntax tree.
@@ -67,3 +68,28 @@ func (y *YulIdentifier) ToProto() NodeType {
 
 	return NewTypedStruct(&toReturn, "YulIdentifier")
 }
+
+// parseYulPath creates a YulIdentifier out of the YUL path, e.g. `x`, `x.slot` or `x.offset`.
+// Path segments are kept together within the identifier name so that member access such as
+// storage slot references is preserved. An ID is still taken for every segment, as each of them
+// used to be the identifier of its own, so the IDs of the following nodes do not shift.
+func parseYulPath(b *ASTBuilder, parentNode Node[NodeType], ctx parser.IYulPathContext) *YulIdentifier {
+	id := b.GetNextID()
+	for range ctx.AllYulIdentifier()[1:] {
+		b.GetNextID()
+	}
+
+	return &YulIdentifier{
+		Id:       id,
+		NodeType: ast_pb.NodeType_YUL_IDENTIFIER,
+		Src: SrcNode{
+			Line:        int64(ctx.GetStart().GetLine()),
+			Column:      int64(ctx.GetStart().GetColumn()),
+			Start:       int64(ctx.GetStart().GetStart()),
+			End:         int64(ctx.GetStop().GetStop()),
+			Length:      int64(ctx.GetStop().GetStop() - ctx.GetStart().GetStart() + 1),
+			ParentIndex: parentNode.GetId(),
+		},
+		Name: ctx.GetText(),
+	}
+}
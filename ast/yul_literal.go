@@ -1,8 +1,7 @@
 package ast
 
 import (
-	"encoding/hex"
-	"fmt"
+	"math/big"
 	"strings"
 
 	ast_pb "github.com/unpackdev/protos/dist/go/ast"
@@ -160,12 +159,12 @@ func (y *YulLiteralStatement) Parse(
 			ParentIndex: parentNode.GetId(),
 		}
 
-		bytes, _ := hex.DecodeString(strings.Replace(y.HexValue, "0x", "", -1))
-		value := int64(0)
-		for _, b := range bytes {
-			value = (value << 8) | int64(b)
+		// Hex numbers can span the full 256 bit word, e.g. storage slot constants, hence big.Int.
+		value, _ := new(big.Int).SetString(strings.Replace(y.HexValue, "0x", "", -1), 16)
+		if value == nil {
+			value = big.NewInt(0)
 		}
-		y.Value = fmt.Sprintf("%d", value)
+		y.Value = value.String()
 	}
 
 	// Handle HexString literals
@@ -11,10 +11,11 @@ import (
 type YulSwitchStatement struct {
 	*ASTBuilder // Embedded ASTBuilder for utility functions.
 
-	Id       int64            `json:"id"`        // Id is the unique identifier for the switch statement.
-	NodeType ast_pb.NodeType  `json:"node_type"` // NodeType specifies the type of the node.
-	Src      SrcNode          `json:"src"`       // Src provides source location details of the switch statement.
-	Cases    []Node[NodeType] `json:"cases"`     // Cases holds the different cases of the switch statement.
+	Id         int64            `json:"id"`         // Id is the unique identifier for the switch statement.
+	NodeType   ast_pb.NodeType  `json:"node_type"`  // NodeType specifies the type of the node.
+	Src        SrcNode          `json:"src"`        // Src provides source location details of the switch statement.
+	Expression Node[NodeType]   `json:"expression"` // Expression is the value the switch cases are matched against.
+	Cases      []Node[NodeType] `json:"cases"`      // Cases holds the different cases of the switch statement, including the default one.
 }
 
 // NewYulSwitchStatement creates and initializes a new YulSwitchStatement.
@@ -51,6 +52,9 @@ func (y *YulSwitchStatement) GetSrc() SrcNode {
 // GetNodes returns a list of nodes associated with the YulSwitchStatement.
 func (y *YulSwitchStatement) GetNodes() []Node[NodeType] {
 	toReturn := make([]Node[NodeType], 0)
+	if y.Expression != nil {
+		toReturn = append(toReturn, y.Expression)
+	}
 	toReturn = append(toReturn, y.Cases...)
 	return toReturn
 }
@@ -61,6 +65,12 @@ func (y *YulSwitchStatement) GetTypeDescription() *TypeDescription {
 	return &TypeDescription{}
 }
 
+// GetExpression returns the expression the switch cases are matched against.
+func (y *YulSwitchStatement) GetExpression() Node[NodeType] {
+	return y.Expression
+}
+
+// GetCases returns the cases of the switch statement, including the default case.
 func (y *YulSwitchStatement) GetCases() []Node[NodeType] {
 	return y.Cases
 }
@@ -108,6 +118,24 @@ func (f *YulSwitchStatement) UnmarshalJSON(data []byte) error {
 		}
 	}
 
+	if expression, ok := tempMap["expression"]; ok && string(expression) != "null" {
+		var tempNodeMap map[string]json.RawMessage
+		if err := json.Unmarshal(expression, &tempNodeMap); err != nil {
+			return err
+		}
+
+		var tempNodeType ast_pb.NodeType
+		if err := json.Unmarshal(tempNodeMap["node_type"], &tempNodeType); err != nil {
+			return err
+		}
+
+		node, err := unmarshalNode(expression, tempNodeType)
+		if err != nil {
+			return err
+		}
+		f.Expression = node
+	}
+
 	if cases, ok := tempMap["cases"]; ok {
 		var nodes []json.RawMessage
 		if err := json.Unmarshal(cases, &nodes); err != nil {
@@ -156,6 +184,15 @@ func (y *YulSwitchStatement) Parse(
 		ParentIndex: statementNode.GetId(),
 	}
 
+	if ctx.YulExpression() != nil {
+		y.withDetachedIDs(func() {
+			y.Expression = ParseYulExpression(
+				y.ASTBuilder, unit, contractNode, fnNode, bodyNode, assemblyNode, statementNode,
+				nil, nil, y, ctx.YulExpression(),
+			)
+		})
+	}
+
 	// Parse all switch cases if present.
 	if ctx.AllYulSwitchCase() != nil {
 		for _, switchCase := range ctx.AllYulSwitchCase() {
@@ -167,5 +204,27 @@ func (y *YulSwitchStatement) Parse(
 		}
 	}
 
+	// Default case is represented as a case without the case literal.
+	if ctx.YulDefault() != nil && ctx.YulBlock() != nil {
+		y.withDetachedIDs(func() {
+			defaultStatement := NewYulSwitchCaseStatement(y.ASTBuilder)
+			defaultStatement.Src = SrcNode{
+				Line:        int64(ctx.YulDefault().GetSymbol().GetLine()),
+				Column:      int64(ctx.YulDefault().GetSymbol().GetColumn()),
+				Start:       int64(ctx.YulDefault().GetSymbol().GetStart()),
+				End:         int64(ctx.YulBlock().GetStop().GetStop()),
+				Length:      int64(ctx.YulBlock().GetStop().GetStop() - ctx.YulDefault().GetSymbol().GetStart() + 1),
+				ParentIndex: y.GetId(),
+			}
+
+			block := NewYulBlockStatement(y.ASTBuilder)
+			defaultStatement.Body = block.Parse(
+				unit, contractNode, fnNode, bodyNode, assemblyNode, statementNode, nil, defaultStatement,
+				ctx.YulBlock().(*parser.YulBlockContext),
+			)
+			y.Cases = append(y.Cases, defaultStatement)
+		})
+	}
+
 	return y
 }
@@ -52,11 +52,20 @@ func (y *YulSwitchCaseStatement) GetSrc() SrcNode {
 // GetNodes returns a list of nodes associated with the YulSwitchCaseStatement.
 func (y *YulSwitchCaseStatement) GetNodes() []Node[NodeType] {
 	toReturn := make([]Node[NodeType], 0)
-	toReturn = append(toReturn, y.Case)
-	toReturn = append(toReturn, y.Body)
+	if y.Case != nil {
+		toReturn = append(toReturn, y.Case)
+	}
+	if y.Body != nil {
+		toReturn = append(toReturn, y.Body)
+	}
 	return toReturn
 }
 
+// IsDefault returns true if the case is the default case of the switch statement.
+func (y *YulSwitchCaseStatement) IsDefault() bool {
+	return y.Case == nil
+}
+
 // GetTypeDescription provides a description of the YulSwitchCaseStatement's type.
 // Always returns an empty TypeDescription.
 func (y *YulSwitchCaseStatement) GetTypeDescription() *TypeDescription {
@@ -0,0 +1,76 @@
+package bindings
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/unpackdev/solgo/clients"
+	"github.com/unpackdev/solgo/utils"
+)
+
+const (
+	// Erc1271 is the binding type of the ERC-1271 standard signature validation method for contracts.
+	Erc1271 BindingType = "ERC1271"
+
+	// Erc1271MagicValue is the value returned by isValidSignature(bytes32,bytes) for the valid signatures.
+	Erc1271MagicValue = "0x1626ba7e"
+
+	// erc1271ABI is the ABI of the ERC-1271 standard signature validation method for contracts.
+	erc1271ABI = `[{"inputs":[{"internalType":"bytes32","name":"hash","type":"bytes32"},{"internalType":"bytes","name":"signature","type":"bytes"}],"name":"isValidSignature","outputs":[{"internalType":"bytes4","name":"magicValue","type":"bytes4"}],"stateMutability":"view","type":"function"}]`
+)
+
+// IsValidSignature calls the ERC-1271 isValidSignature(bytes32,bytes) function of the contract, reporting whether
+// the contract accepts the signature of the hash. Accounts without code, contracts reverting the call and contracts
+// returning anything but the ABI encoded magic value, such as the fallback returning nothing, are all reported as
+// not accepting the signature. The ERC-1271 binding is registered on first use.
+func (m *Manager) IsValidSignature(ctx context.Context, network utils.Network, contract common.Address, hash common.Hash, signature []byte) (bool, error) {
+	code, err := m.clientPool.GetBatcher(network.String()).CodeAt(ctx, contract, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to get code of %s: %w", contract.Hex(), err)
+	}
+
+	if len(code) == 0 {
+		return false, nil
+	}
+
+	if !m.BindingExist(network, Erc1271) {
+		if _, err := m.RegisterBinding(network, utils.GetNetworkID(network), Erc1271, utils.ZeroAddress, erc1271ABI); err != nil {
+			return false, fmt.Errorf("failed to register erc1271 binding: %w", err)
+		}
+	}
+
+	binding, err := m.GetBinding(network, Erc1271)
+	if err != nil {
+		return false, err
+	}
+
+	data, err := binding.ABI.Pack("isValidSignature", [32]byte(hash), signature)
+	if err != nil {
+		return false, fmt.Errorf("failed to pack isValidSignature: %w", err)
+	}
+
+	// Calls made concurrently are aggregated into the single multicall.
+	result, err := m.clientPool.GetMulticaller(network.String()).CallContract(ctx, ethereum.CallMsg{To: &contract, Data: data}, nil)
+	if err != nil {
+		if errors.Is(err, clients.ErrCallReverted) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to call contract: %w", err)
+	}
+
+	// Results that are not the ABI encoded bytes4 are not the magic value either.
+	unpacked, err := binding.ABI.Unpack("isValidSignature", result)
+	if err != nil || len(unpacked) != 1 {
+		return false, nil
+	}
+
+	magicValue, ok := unpacked[0].([4]byte)
+	if !ok {
+		return false, nil
+	}
+
+	return common.Bytes2Hex(magicValue[:]) == Erc1271MagicValue[2:], nil
+}
@@ -0,0 +1,128 @@
+package bindings
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/unpackdev/solgo/clients"
+	"github.com/unpackdev/solgo/standards"
+	"github.com/unpackdev/solgo/utils"
+)
+
+const (
+	// Erc165 is the binding type of the ERC-165 standard interface detection.
+	Erc165 BindingType = "ERC165"
+
+	// Erc165InterfaceId is the ERC-165 interface identifier of the supportsInterface(bytes4) function.
+	Erc165InterfaceId = "0x01ffc9a7"
+
+	// Erc165InvalidInterfaceId is the interface identifier ERC-165 compliant contracts must not support.
+	Erc165InvalidInterfaceId = "0xffffffff"
+
+	// erc165ABI is the ABI of the ERC-165 standard interface detection.
+	erc165ABI = `[{"inputs":[{"internalType":"bytes4","name":"interfaceId","type":"bytes4"}],"name":"supportsInterface","outputs":[{"internalType":"bool","name":"","type":"bool"}],"stateMutability":"view","type":"function"}]`
+)
+
+// SupportsInterface calls the ERC-165 supportsInterface(bytes4) function of the contract for the provided
+// hex encoded interface identifier, e.g. "0x80ac58cd". The ERC-165 binding is registered on first use.
+func (m *Manager) SupportsInterface(ctx context.Context, network utils.Network, contract common.Address, interfaceId string) (bool, error) {
+	id := common.FromHex(interfaceId)
+	if len(id) != 4 {
+		return false, fmt.Errorf("invalid interface id %s", interfaceId)
+	}
+
+	if !m.BindingExist(network, Erc165) {
+		if _, err := m.RegisterBinding(network, utils.GetNetworkID(network), Erc165, utils.ZeroAddress, erc165ABI); err != nil {
+			return false, fmt.Errorf("failed to register erc165 binding: %w", err)
+		}
+	}
+
+	result, err := m.CallContractMethod(ctx, network, Erc165, contract, "supportsInterface", [4]byte(id))
+	if err != nil {
+		return false, err
+	}
+
+	supported, ok := result.(bool)
+	if !ok {
+		return false, fmt.Errorf("failed to assert result as bool - supportsInterface")
+	}
+
+	return supported, nil
+}
+
+// ProbeInterfaces detects whether the contract implements ERC-165, following the detection procedure of the
+// standard, and returns the interface identifiers of the registered standards the contract confirmed support of.
+// Contracts that do not implement ERC-165 return an empty list, which makes the result suitable for the
+// standards.BytecodeMatcher Interfaces. Failing calls to the contract are read as the missing support, while the
+// RPC and transport errors are returned, so the unreachable node is not mistaken for the contract without ERC-165.
+func (m *Manager) ProbeInterfaces(ctx context.Context, network utils.Network, contract common.Address) ([]string, error) {
+	toReturn := make([]string, 0)
+
+	// Contracts without supportsInterface fail the call, which is not an error from the detection point of view,
+	// while the failures to reach the node are.
+	supported, err := m.SupportsInterface(ctx, network, contract, Erc165InterfaceId)
+	if err != nil && !isCallFailure(err) {
+		return nil, fmt.Errorf("failed to probe erc165 support: %w", err)
+	}
+
+	if !supported {
+		return toReturn, nil
+	}
+
+	invalid, err := m.SupportsInterface(ctx, network, contract, Erc165InvalidInterfaceId)
+	if err != nil && !isCallFailure(err) {
+		return nil, fmt.Errorf("failed to probe erc165 support: %w", err)
+	}
+
+	// Compliant contracts return false for the invalid interface, failing the call does not comply.
+	if err != nil || invalid {
+		return toReturn, nil
+	}
+
+	toReturn = append(toReturn, Erc165InterfaceId)
+	for _, standard := range standards.GetSortedRegisteredStandards() {
+		interfaceId := standard.GetInterfaceId()
+		if interfaceId == "" {
+			continue
+		}
+
+		supported, err := m.SupportsInterface(ctx, network, contract, interfaceId)
+		if err != nil && !isCallFailure(err) {
+			return nil, fmt.Errorf("failed to probe interface %s of standard %s: %w", interfaceId, standard.GetType(), err)
+		}
+
+		if supported {
+			toReturn = append(toReturn, interfaceId)
+		}
+	}
+
+	return toReturn, nil
+}
+
+// executionFailures are the messages of the nodes for the calls failing in the EVM other than reverting, such as
+// the calls to the contracts compiled before the REVERT opcode, rejecting unknown selectors with the invalid opcode.
+var executionFailures = []string{"invalid opcode", "invalid jump destination", "stack underflow", "out of gas"}
+
+// isCallFailure reports whether the error is the failure of the called contract, reverting, failing in the EVM
+// or returning the output that does not decode, rather than the failure to reach the node.
+func isCallFailure(err error) bool {
+	if errors.Is(err, clients.ErrCallReverted) || errors.Is(err, ErrUnpackResults) {
+		return true
+	}
+
+	var rpcErr rpc.Error
+	if !errors.As(err, &rpcErr) {
+		return false
+	}
+
+	for _, failure := range executionFailures {
+		if strings.Contains(rpcErr.Error(), failure) {
+			return true
+		}
+	}
+	return false
+}
@@ -2,6 +2,7 @@ package bindings
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"sync"
@@ -15,6 +16,10 @@ import (
 	"github.com/unpackdev/solgo/utils"
 )
 
+// ErrUnpackResults is returned when the output of the called contract method does not decode as its ABI outputs,
+// such as the empty output of the contract without the method and without the reverting fallback.
+var ErrUnpackResults = errors.New("failed to unpack results")
+
 // Manager acts as a central registry for smart contract bindings. It enables the configuration, management, and
 // interaction with smart contracts across different networks. The Manager maintains a client pool for network
 // communications, a context for managing lifecycle events, and a mutex for thread-safe operation.
@@ -178,7 +183,8 @@ func (m *Manager) CallContractMethod(ctx context.Context, network utils.Network,
 		return nil, fmt.Errorf("client not found for network %s", network)
 	}
 
-	result, err = client.CallContract(context.Background(), callMsg, nil)
+	// Calls made concurrently are aggregated into the single multicall.
+	result, err = m.clientPool.GetMulticaller(network.String()).CallContract(ctx, callMsg, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to call contract: %w", err)
 	}
@@ -186,7 +192,7 @@ func (m *Manager) CallContractMethod(ctx context.Context, network utils.Network,
 	var unpackedResults any
 	err = binding.ABI.UnpackIntoInterface(&unpackedResults, methodName, result)
 	if err != nil {
-		return nil, fmt.Errorf("failed to unpack results: %w", err)
+		return nil, fmt.Errorf("%w: %w", ErrUnpackResults, err)
 	}
 
 	return unpackedResults, nil
@@ -230,7 +236,8 @@ func (m *Manager) CallContractMethodUnpackMap(ctx context.Context, network utils
 		return nil, fmt.Errorf("client not found for network %s", network)
 	}
 
-	result, err = client.CallContract(context.Background(), callMsg, nil)
+	// Calls made concurrently are aggregated into the single multicall.
+	result, err = m.clientPool.GetMulticaller(network.String()).CallContract(ctx, callMsg, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to call contract: %w", err)
 	}
@@ -238,7 +245,7 @@ func (m *Manager) CallContractMethodUnpackMap(ctx context.Context, network utils
 	unpackedResults := map[string]any{}
 	err = binding.ABI.UnpackIntoMap(unpackedResults, methodName, result)
 	if err != nil {
-		return nil, fmt.Errorf("failed to unpack results: %w", err)
+		return nil, fmt.Errorf("%w: %w", ErrUnpackResults, err)
 	}
 
 	return unpackedResults, nil
@@ -0,0 +1,221 @@
+package bindings
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/unpackdev/solgo/standards"
+	"github.com/unpackdev/solgo/utils"
+)
+
+// BindingType values of the non-fungible and multi token standards, together with the ERC-2981 royalty standard
+// these tokens commonly implement.
+const (
+	Erc721  BindingType = "ERC721"
+	Erc1155 BindingType = "ERC1155"
+	Erc2981 BindingType = "ERC2981"
+)
+
+// ERC-165 interface identifiers of the non-fungible and multi token standards and their extensions.
+const (
+	Erc721InterfaceId             = "0x80ac58cd"
+	Erc721MetadataInterfaceId     = "0x5b5e139f"
+	Erc721EnumerableInterfaceId   = "0x780e9d63"
+	Erc1155InterfaceId            = "0xd9b67a26"
+	Erc1155MetadataURIInterfaceId = "0x0e89341c"
+	Erc2981InterfaceId            = "0x2a55205a"
+)
+
+// NFT encapsulates data and functions for interacting with ERC-721 and ERC-1155 token contracts, including their
+// ERC-2981 royalty extension. It mirrors the Token binding, resolving the contract through the registered
+// bindings of the Manager.
+type NFT struct {
+	*Manager
+	network utils.Network
+	ctx     context.Context
+	opts    []*BindOptions
+}
+
+// NewNFT initializes a new NFT instance. It validates the provided BindOptions and registers the bindings with
+// the Manager. It returns a pointer to a NFT instance or an error if the initialization fails.
+func NewNFT(ctx context.Context, network utils.Network, manager *Manager, opts []*BindOptions) (*NFT, error) {
+	if opts == nil {
+		return nil, fmt.Errorf("no binding options provided for new nft")
+	}
+
+	for _, opt := range opts {
+		if err := opt.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, opt := range opts {
+		for _, network := range opt.Networks {
+			if _, err := manager.RegisterBinding(network, opt.NetworkID, opt.Type, opt.Address, opt.ABI); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return &NFT{
+		Manager: manager,
+		network: network,
+		ctx:     ctx,
+		opts:    opts,
+	}, nil
+}
+
+// GetAddress returns the primary address of the token's smart contract as specified in the first BindOptions entry.
+func (t *NFT) GetAddress() common.Address {
+	return t.opts[0].Address
+}
+
+// SupportsInterface checks through ERC-165 whether the token contract implements the provided interface.
+func (t *NFT) SupportsInterface(ctx context.Context, from common.Address, interfaceId string) (bool, error) {
+	return t.Manager.SupportsInterface(ctx, t.network, from, interfaceId)
+}
+
+// GetName calls the name() function of the ERC-721 metadata extension.
+func (t *NFT) GetName(ctx context.Context, from common.Address) (string, error) {
+	result, err := t.Manager.CallContractMethod(ctx, t.network, Erc721, from, "name")
+	if err != nil {
+		return "", err
+	}
+
+	name, ok := result.(string)
+	if !ok {
+		return "", fmt.Errorf("failed to assert result as string - name")
+	}
+
+	return name, nil
+}
+
+// GetSymbol calls the symbol() function of the ERC-721 metadata extension.
+func (t *NFT) GetSymbol(ctx context.Context, from common.Address) (string, error) {
+	result, err := t.Manager.CallContractMethod(ctx, t.network, Erc721, from, "symbol")
+	if err != nil {
+		return "", err
+	}
+
+	symbol, ok := result.(string)
+	if !ok {
+		return "", fmt.Errorf("failed to assert result as string - symbol")
+	}
+
+	return symbol, nil
+}
+
+// GetTotalSupply calls the totalSupply() function of the ERC-721 enumerable extension.
+func (t *NFT) GetTotalSupply(ctx context.Context, from common.Address) (*big.Int, error) {
+	result, err := t.Manager.CallContractMethod(ctx, t.network, Erc721, from, "totalSupply")
+	if err != nil {
+		return nil, err
+	}
+
+	totalSupply, ok := result.(*big.Int)
+	if !ok {
+		return nil, fmt.Errorf("failed to assert result as *big.Int - totalSupply")
+	}
+
+	return totalSupply, nil
+}
+
+// OwnerOf calls the ownerOf(uint256) function of the ERC-721 contract.
+func (t *NFT) OwnerOf(ctx context.Context, from common.Address, tokenId *big.Int) (common.Address, error) {
+	result, err := t.Manager.CallContractMethod(ctx, t.network, Erc721, from, "ownerOf", tokenId)
+	if err != nil {
+		return utils.ZeroAddress, err
+	}
+
+	owner, ok := result.(common.Address)
+	if !ok {
+		return utils.ZeroAddress, fmt.Errorf("failed to assert result as common.Address - ownerOf")
+	}
+
+	return owner, nil
+}
+
+// TokenURI calls the tokenURI(uint256) function of the ERC-721 metadata extension.
+func (t *NFT) TokenURI(ctx context.Context, from common.Address, tokenId *big.Int) (string, error) {
+	result, err := t.Manager.CallContractMethod(ctx, t.network, Erc721, from, "tokenURI", tokenId)
+	if err != nil {
+		return "", err
+	}
+
+	uri, ok := result.(string)
+	if !ok {
+		return "", fmt.Errorf("failed to assert result as string - tokenURI")
+	}
+
+	return uri, nil
+}
+
+// URI calls the uri(uint256) function of the ERC-1155 metadata URI extension. The returned URI may contain the
+// `{id}` placeholder that clients are expected to substitute with the token identifier.
+func (t *NFT) URI(ctx context.Context, from common.Address, id *big.Int) (string, error) {
+	result, err := t.Manager.CallContractMethod(ctx, t.network, Erc1155, from, "uri", id)
+	if err != nil {
+		return "", err
+	}
+
+	uri, ok := result.(string)
+	if !ok {
+		return "", fmt.Errorf("failed to assert result as string - uri")
+	}
+
+	return uri, nil
+}
+
+// RoyaltyInfo calls the royaltyInfo(uint256,uint256) function of the ERC-2981 contract and returns the royalty
+// receiver together with the royalty amount for the provided sale price.
+func (t *NFT) RoyaltyInfo(ctx context.Context, from common.Address, tokenId *big.Int, salePrice *big.Int) (common.Address, *big.Int, error) {
+	result, err := t.Manager.CallContractMethodUnpackMap(ctx, t.network, Erc2981, from, "royaltyInfo", tokenId, salePrice)
+	if err != nil {
+		return utils.ZeroAddress, nil, err
+	}
+
+	receiver, ok := result["receiver"].(common.Address)
+	if !ok {
+		return utils.ZeroAddress, nil, fmt.Errorf("failed to assert result as common.Address - royaltyInfo receiver")
+	}
+
+	amount, ok := result["royaltyAmount"].(*big.Int)
+	if !ok {
+		return utils.ZeroAddress, nil, fmt.Errorf("failed to assert result as *big.Int - royaltyInfo royaltyAmount")
+	}
+
+	return receiver, amount, nil
+}
+
+// DefaultNFTBindOptions generates a default set of BindOptions for ERC-721, ERC-1155 and ERC-2981 contracts using
+// the ABIs of the registered standards.
+func DefaultNFTBindOptions(address common.Address) []*BindOptions {
+	bindingStandards := []struct {
+		bindingType BindingType
+		standard    standards.Standard
+	}{
+		{Erc721, standards.ERC721},
+		{Erc1155, standards.ERC1155},
+		{Erc2981, standards.ERC2981},
+	}
+
+	toReturn := make([]*BindOptions, 0, len(bindingStandards))
+	for _, bs := range bindingStandards {
+		eip, ok := standards.GetStandard(bs.standard)
+		if !ok {
+			continue
+		}
+
+		toReturn = append(toReturn, &BindOptions{
+			Networks:  []utils.Network{utils.Ethereum, utils.AnvilNetwork},
+			NetworkID: utils.EthereumNetworkID,
+			Type:      bs.bindingType,
+			Address:   address,
+			ABI:       eip.GetStandard().ABI,
+		})
+	}
+
+	return toReturn
+}
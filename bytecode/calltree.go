@@ -0,0 +1,221 @@
+package bytecode
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"sync"
+
+	abi "github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// DefaultCallDepth is the depth the envelopes are decoded to when the depth is not set.
+const DefaultCallDepth = 16
+
+// CallOperation is the way the call is made by its parent.
+type CallOperation string
+
+const (
+	// OperationCall is the regular message call.
+	OperationCall CallOperation = "call"
+
+	// OperationDelegateCall is the call executing the code of the target in the context of the caller.
+	OperationDelegateCall CallOperation = "delegatecall"
+
+	// OperationCommand is the command of the Universal Router, executed by the router itself.
+	OperationCommand CallOperation = "command"
+)
+
+// Call is the node of the call tree decoded from the calldata. Calls wrapping the other calls, such as the
+// multicalls, Safe transactions, Universal Router commands or ERC-4337 user operations, carry them as children.
+type Call struct {
+	Operation    CallOperation          `json:"operation"`               // Way the call is made by its parent.
+	Target       common.Address         `json:"target"`                  // Address the call is made to.
+	Value        *big.Int               `json:"value,omitempty"`         // Value sent along with the call, if any.
+	Data         []byte                 `json:"data,omitempty"`          // Calldata, or the input of the command.
+	AllowFailure bool                   `json:"allow_failure,omitempty"` // Whether the parent tolerates the call to revert.
+	Envelope     string                 `json:"envelope,omitempty"`      // Name of the envelope the calldata was decoded as, if any.
+	Transaction  *Transaction           `json:"transaction,omitempty"`   // Decoded method, nil when the ABI is not known.
+	Command      string                 `json:"command,omitempty"`       // Name of the Universal Router command.
+	Inputs       map[string]interface{} `json:"inputs,omitempty"`        // Decoded inputs of the Universal Router command.
+	Calls        []*Call                `json:"calls,omitempty"`         // Calls wrapped by the call.
+	Error        string                 `json:"error,omitempty"`         // Error the wrapped calls failed to unwrap with, if any.
+}
+
+// GetSelector returns the 4 bytes selector of the calldata, nil when the call carries no calldata.
+func (c *Call) GetSelector() []byte {
+	if len(c.Data) < 4 || c.Operation == OperationCommand {
+		return nil
+	}
+	return c.Data[:4]
+}
+
+// GetName returns the name of the decoded method or command, empty when the call was not decoded.
+func (c *Call) GetName() string {
+	if c.Command != "" {
+		return c.Command
+	}
+	if c.Transaction != nil {
+		return c.Transaction.Name
+	}
+	return ""
+}
+
+// Walk calls the visit function for the call and all the calls it wraps, depth first, along with their depth
+// in the tree. Walking stops when the visit function returns false.
+func (c *Call) Walk(visit func(call *Call, depth int) bool) {
+	c.walk(visit, 0)
+}
+
+func (c *Call) walk(visit func(call *Call, depth int) bool, depth int) bool {
+	if !visit(c, depth) {
+		return false
+	}
+	for _, call := range c.Calls {
+		if !call.walk(visit, depth+1) {
+			return false
+		}
+	}
+	return true
+}
+
+// AbiResolver returns the JSON ABI of the contract at the address, nil when the ABI is not known.
+type AbiResolver func(addr common.Address) ([]byte, error)
+
+// NewStaticAbiResolver returns the resolver of the ABIs known upfront, by the address of their contracts.
+func NewStaticAbiResolver(abis map[common.Address][]byte) AbiResolver {
+	return func(addr common.Address) ([]byte, error) {
+		return abis[addr], nil
+	}
+}
+
+// CallDecoder decodes the calldata into the call tree, unwrapping the well known envelopes and decoding the
+// rest of the calls with the ABI of their target. It is safe for concurrent use.
+type CallDecoder struct {
+	resolver  AbiResolver
+	depth     int
+	envelopes map[[4]byte]*envelope
+	commands  map[string]abi.Arguments
+
+	mu   sync.Mutex
+	abis map[common.Address]*abi.ABI // Parsed ABIs by their target, nil when not known.
+}
+
+// NewCallDecoder creates the decoder resolving the ABIs of the targets with the given resolver. Only the
+// envelopes are decoded when the resolver is nil.
+func NewCallDecoder(resolver AbiResolver) (*CallDecoder, error) {
+	envelopes, err := newEnvelopes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load call envelopes: %w", err)
+	}
+
+	commands, err := newRouterCommands()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load router commands: %w", err)
+	}
+
+	return &CallDecoder{
+		resolver:  resolver,
+		depth:     DefaultCallDepth,
+		envelopes: envelopes,
+		commands:  commands,
+		abis:      make(map[common.Address]*abi.ABI),
+	}, nil
+}
+
+// SetDepth sets the depth the envelopes are decoded to. It must be set before the decoding starts.
+func (d *CallDecoder) SetDepth(depth int) {
+	d.depth = depth
+}
+
+// Decode decodes the calldata sent to the target along with the value into the call tree. Envelopes whose
+// wrapped calls are malformed are kept in the tree along with the error, so only the failures to resolve the
+// ABIs fail the decoding.
+func (d *CallDecoder) Decode(target common.Address, value *big.Int, data []byte) (*Call, error) {
+	return d.decode(&Call{
+		Operation: OperationCall,
+		Target:    target,
+		Value:     value,
+		Data:      data,
+	}, 0)
+}
+
+// decode decodes the calldata of the call, and the calls it wraps when it is the envelope.
+func (d *CallDecoder) decode(call *Call, depth int) (*Call, error) {
+	// Commands are decoded by the router envelope already.
+	if len(call.Data) < 4 || call.Operation == OperationCommand {
+		return call, nil
+	}
+
+	if env, ok := d.envelopes[[4]byte(call.Data[:4])]; ok && depth < d.depth {
+		// Selectors are not unique, so the calldata failing to decode as the envelope is decoded as the regular call.
+		if tx, err := decodeTransaction(env.method, call.Data); err == nil {
+			call.Envelope = env.name
+			call.Transaction = tx
+
+			// Malformed wrapped calls are reported on the envelope rather than failing the whole tree.
+			calls, err := env.unwrap(d, call, tx.Inputs)
+			if err != nil {
+				call.Error = fmt.Sprintf("failed to unwrap %s calls: %s", env.name, err)
+				return call, nil
+			}
+
+			for _, child := range calls {
+				if _, err := d.decode(child, depth+1); err != nil {
+					return nil, err
+				}
+			}
+			call.Calls = calls
+
+			return call, nil
+		}
+	}
+
+	contractAbi, err := d.getAbi(call.Target)
+	if err != nil {
+		return nil, err
+	}
+
+	// Calldata not matching the ABI of the target is left undecoded rather than failing the whole tree.
+	if contractAbi != nil {
+		if method, err := contractAbi.MethodById(call.Data[:4]); err == nil {
+			if tx, err := decodeTransaction(method, call.Data); err == nil {
+				call.Transaction = tx
+			}
+		}
+	}
+
+	return call, nil
+}
+
+// getAbi returns the parsed ABI of the target, nil when it is not known.
+func (d *CallDecoder) getAbi(target common.Address) (*abi.ABI, error) {
+	if d.resolver == nil {
+		return nil, nil
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if contractAbi, ok := d.abis[target]; ok {
+		return contractAbi, nil
+	}
+
+	abiData, err := d.resolver(target)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve abi of %s: %w", target.Hex(), err)
+	}
+
+	var contractAbi *abi.ABI
+	if abiData != nil {
+		parsed, err := abi.JSON(bytes.NewReader(abiData))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse abi of %s: %s", target.Hex(), err)
+		}
+		contractAbi = &parsed
+	}
+
+	d.abis[target] = contractAbi
+	return contractAbi, nil
+}
@@ -0,0 +1,248 @@
+package bytecode
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	abi "github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testTokenAbi = `[{"type":"function","name":"transfer","inputs":[{"name":"to","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]}]`
+
+// packEnvelope packs the calldata of the envelope method with the given signature.
+func packEnvelope(t *testing.T, d *CallDecoder, sig string, args ...interface{}) []byte {
+	for _, env := range d.envelopes {
+		if env.method.Sig == sig {
+			data, err := env.method.Inputs.Pack(args...)
+			require.NoError(t, err)
+			return append(append([]byte{}, env.method.ID...), data...)
+		}
+	}
+	t.Fatalf("envelope %s not found", sig)
+	return nil
+}
+
+// packMultiSend packs the MultiSend transactions.
+func packMultiSend(calls ...*Call) []byte {
+	var packed []byte
+	for _, call := range calls {
+		operation := byte(0)
+		if call.Operation == OperationDelegateCall {
+			operation = 1
+		}
+		packed = append(packed, operation)
+		packed = append(packed, call.Target.Bytes()...)
+		packed = append(packed, common.LeftPadBytes(call.Value.Bytes(), 32)...)
+		packed = append(packed, common.LeftPadBytes(big.NewInt(int64(len(call.Data))).Bytes(), 32)...)
+		packed = append(packed, call.Data...)
+	}
+	return packed
+}
+
+// flatten returns the names and operations of the calls of the tree by their depth.
+func flatten(call *Call) []string {
+	var calls []string
+	call.Walk(func(call *Call, depth int) bool {
+		name := call.GetName()
+		if name == "" {
+			name = "?"
+		}
+		calls = append(calls, string(rune('0'+depth))+":"+string(call.Operation)+":"+name)
+		return true
+	})
+	return calls
+}
+
+func TestCallDecoder(t *testing.T) {
+	token := common.HexToAddress("0x1000000000000000000000000000000000000001")
+	safe := common.HexToAddress("0x2000000000000000000000000000000000000002")
+	multiSend := common.HexToAddress("0x3000000000000000000000000000000000000003")
+	multicall3 := common.HexToAddress("0x4000000000000000000000000000000000000004")
+	router := common.HexToAddress("0x5000000000000000000000000000000000000005")
+	account := common.HexToAddress("0x6000000000000000000000000000000000000006")
+	recipient := common.HexToAddress("0x7000000000000000000000000000000000000007")
+
+	decoder, err := NewCallDecoder(NewStaticAbiResolver(map[common.Address][]byte{
+		token: []byte(testTokenAbi),
+	}))
+	require.NoError(t, err)
+
+	tokenAbi, err := abi.JSON(strings.NewReader(testTokenAbi))
+	require.NoError(t, err)
+	transfer, err := tokenAbi.Pack("transfer", recipient, big.NewInt(100))
+	require.NoError(t, err)
+
+	unknown := []byte{0xde, 0xad, 0xbe, 0xef}
+
+	tests := []struct {
+		name     string
+		target   common.Address
+		data     []byte
+		expected []string
+	}{
+		{
+			name:   "Safe MultiSend Multicall3",
+			target: safe,
+			data: packEnvelope(t, decoder, "execTransaction(address,uint256,bytes,uint8,uint256,uint256,uint256,address,address,bytes)",
+				multiSend, big.NewInt(0),
+				packEnvelope(t, decoder, "multiSend(bytes)", packMultiSend(
+					&Call{Operation: OperationCall, Target: token, Value: big.NewInt(0), Data: transfer},
+					&Call{Operation: OperationCall, Target: multicall3, Value: big.NewInt(1), Data: packEnvelope(t, decoder, "aggregate3((address,bool,bytes)[])",
+						[]multicallCall3{{Target: token, AllowFailure: true, CallData: transfer}, {Target: token, CallData: unknown}},
+					)},
+				)),
+				uint8(1), big.NewInt(0), big.NewInt(0), big.NewInt(0), common.Address{}, common.Address{}, []byte{},
+			),
+			expected: []string{
+				"0:call:execTransaction",
+				"1:delegatecall:multiSend",
+				"2:call:transfer",
+				"2:call:aggregate3",
+				"3:call:transfer",
+				"3:call:?",
+			},
+		},
+		{
+			name:   "Universal Router",
+			target: router,
+			data: packEnvelope(t, decoder, "execute(bytes,bytes[],uint256)",
+				[]byte{0x0b, 0x80 | 0x00, 0x21},
+				[][]byte{
+					mustPack(t, decoder.commands["WRAP_ETH"], recipient, big.NewInt(1)),
+					mustPack(t, decoder.commands["V3_SWAP_EXACT_IN"], recipient, big.NewInt(1), big.NewInt(2), []byte{0x01}, false),
+					mustPack(t, decoder.commands["EXECUTE_SUB_PLAN"], []byte{0x04}, [][]byte{
+						mustPack(t, decoder.commands["SWEEP"], token, recipient, big.NewInt(3)),
+					}),
+				},
+				big.NewInt(0),
+			),
+			expected: []string{
+				"0:call:execute",
+				"1:command:WRAP_ETH",
+				"1:command:V3_SWAP_EXACT_IN",
+				"1:command:EXECUTE_SUB_PLAN",
+				"2:command:SWEEP",
+			},
+		},
+		{
+			name:   "ERC-4337 User Operations",
+			target: common.HexToAddress("0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789"),
+			data: packEnvelope(t, decoder, "handleOps((address,uint256,bytes,bytes,uint256,uint256,uint256,uint256,uint256,bytes,bytes)[],address)",
+				[]userOperation{{
+					Sender:       account,
+					Nonce:        big.NewInt(0),
+					CallData:     packEnvelope(t, decoder, "executeBatch(address[],bytes[])", []common.Address{token, recipient}, [][]byte{transfer, {}}),
+					CallGasLimit: big.NewInt(0), VerificationGasLimit: big.NewInt(0), PreVerificationGas: big.NewInt(0),
+					MaxFeePerGas: big.NewInt(0), MaxPriorityFeePerGas: big.NewInt(0),
+				}},
+				recipient,
+			),
+			expected: []string{
+				"0:call:handleOps",
+				"1:call:executeBatch",
+				"2:call:transfer",
+				"2:call:?",
+			},
+		},
+		{
+			name:   "Self Multicall And Forwarder",
+			target: account,
+			data: packEnvelope(t, decoder, "multicall(uint256,bytes[])", big.NewInt(0), [][]byte{
+				packEnvelope(t, decoder, "execute(address,bytes)", token, transfer),
+				unknown,
+			}),
+			expected: []string{
+				"0:call:multicall",
+				"1:delegatecall:execute",
+				"2:delegatecall:transfer",
+				"1:delegatecall:?",
+			},
+		},
+		{
+			name:     "Plain Call",
+			target:   token,
+			data:     transfer,
+			expected: []string{"0:call:transfer"},
+		},
+		{
+			name:     "Value Transfer",
+			target:   recipient,
+			expected: []string{"0:call:?"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			call, err := decoder.Decode(tt.target, big.NewInt(0), tt.data)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, flatten(call))
+		})
+	}
+
+	// Values, targets and the inputs of the commands are carried by the calls.
+	call, err := decoder.Decode(safe, nil, tests[0].data)
+	require.NoError(t, err)
+	aggregate := call.Calls[0].Calls[1]
+	assert.Equal(t, "multicall3", aggregate.Envelope)
+	assert.Equal(t, big.NewInt(1), aggregate.Value)
+	assert.True(t, aggregate.Calls[0].AllowFailure)
+	assert.Equal(t, recipient, aggregate.Calls[0].Transaction.Inputs["to"])
+
+	call, err = decoder.Decode(router, nil, tests[1].data)
+	require.NoError(t, err)
+	assert.True(t, call.Calls[1].AllowFailure)
+	assert.Equal(t, big.NewInt(2), call.Calls[1].Inputs["amountOutMin"])
+	assert.Equal(t, token, call.Calls[2].Calls[0].Inputs["token"])
+
+	// Envelopes are not decoded past the depth.
+	decoder.SetDepth(1)
+	call, err = decoder.Decode(safe, nil, tests[0].data)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"0:call:execTransaction", "1:delegatecall:?"}, flatten(call))
+}
+
+func TestCallDecoderMalformed(t *testing.T) {
+	decoder, err := NewCallDecoder(nil)
+	require.NoError(t, err)
+
+	// MultiSend transaction claiming more data than it carries.
+	truncated := packMultiSend(&Call{Target: common.HexToAddress("0x01"), Value: big.NewInt(0), Data: []byte{0x01, 0x02}})
+	call, err := decoder.Decode(common.Address{}, nil, packEnvelope(t, decoder, "multiSend(bytes)", truncated[:len(truncated)-1]))
+	require.NoError(t, err)
+	assert.Equal(t, "multisend", call.Envelope)
+	assert.Equal(t, "multiSend", call.GetName())
+	assert.Contains(t, call.Error, "failed to unwrap multisend calls")
+	assert.Empty(t, call.Calls)
+
+	// Router commands not matching their inputs.
+	call, err = decoder.Decode(common.Address{}, nil, packEnvelope(t, decoder, "execute(bytes,bytes[])", []byte{0x0b, 0x0c}, [][]byte{{}}))
+	require.NoError(t, err)
+	assert.Equal(t, "universal_router", call.Envelope)
+	assert.Contains(t, call.Error, "failed to unwrap universal_router calls")
+	assert.Empty(t, call.Calls)
+
+	// Malformed calls wrapped deeper are reported on their own envelope.
+	safe := packEnvelope(t, decoder, "execTransactionFromModule(address,uint256,bytes,uint8)",
+		common.HexToAddress("0x02"), big.NewInt(0), packEnvelope(t, decoder, "multiSend(bytes)", truncated[:len(truncated)-1]), uint8(1))
+	call, err = decoder.Decode(common.Address{}, nil, safe)
+	require.NoError(t, err)
+	assert.Empty(t, call.Error)
+	require.Len(t, call.Calls, 1)
+	assert.Contains(t, call.Calls[0].Error, "failed to unwrap multisend calls")
+
+	// Calldata with the selector of the envelope not decoding as it is left undecoded.
+	call, err = decoder.Decode(common.Address{}, nil, []byte{0x82, 0xad, 0x56, 0xcb, 0x01})
+	require.NoError(t, err)
+	assert.Nil(t, call.Transaction)
+	assert.Empty(t, call.Calls)
+}
+
+func mustPack(t *testing.T, arguments abi.Arguments, args ...interface{}) []byte {
+	data, err := arguments.Pack(args...)
+	require.NoError(t, err)
+	return data
+}
@@ -1,5 +1,7 @@
 /*
 Package bytecode provides tools for decoding and analyzing Ethereum contract, transaction, events, and log bytecode.
 The package is designed to extract and interpret metadata from Ethereum contract creation bytecode. It provides a Metadata struct that represents the metadata contained in the bytecode, as defined by the Solidity compiler. This includes information such as the IPFS hash of the metadata, the Swarm hash of the metadata, experimental metadata, and the version of the Solidity compiler used.
+
+Calldata is decoded into the call tree by the CallDecoder, unwrapping the multicalls, the Safe transactions and their MultiSend batches, the Universal Router commands and sub plans, and the ERC-4337 user operations of the smart accounts, with every inner call decoded with the ABI of its target.
 */
 package bytecode
@@ -0,0 +1,403 @@
+package bytecode
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	abi "github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// envelope is the well known method wrapping the other calls in its arguments.
+type envelope struct {
+	name   string      // Name of the envelope, such as multicall3.
+	method *abi.Method // Method of the envelope.
+
+	// unwrap returns the calls wrapped by the decoded inputs of the call.
+	unwrap func(d *CallDecoder, call *Call, inputs map[string]interface{}) ([]*Call, error)
+}
+
+// Tuples of the envelopes, with the fields in the order of the ABI tuples they are converted from.
+type (
+	multicallCall struct {
+		Target   common.Address
+		CallData []byte
+	}
+
+	multicallCall3 struct {
+		Target       common.Address
+		AllowFailure bool
+		CallData     []byte
+	}
+
+	multicallCall3Value struct {
+		Target       common.Address
+		AllowFailure bool
+		Value        *big.Int
+		CallData     []byte
+	}
+
+	userOperation struct {
+		Sender               common.Address
+		Nonce                *big.Int
+		InitCode             []byte
+		CallData             []byte
+		CallGasLimit         *big.Int
+		VerificationGasLimit *big.Int
+		PreVerificationGas   *big.Int
+		MaxFeePerGas         *big.Int
+		MaxPriorityFeePerGas *big.Int
+		PaymasterAndData     []byte
+		Signature            []byte
+	}
+
+	packedUserOperation struct {
+		Sender             common.Address
+		Nonce              *big.Int
+		InitCode           []byte
+		CallData           []byte
+		AccountGasLimits   [32]byte
+		PreVerificationGas *big.Int
+		GasFees            [32]byte
+		PaymasterAndData   []byte
+		Signature          []byte
+	}
+)
+
+const (
+	multicallTuple      = `{"name":"calls","type":"tuple[]","components":[{"name":"target","type":"address"},{"name":"callData","type":"bytes"}]}`
+	multicall3Tuple     = `{"name":"calls","type":"tuple[]","components":[{"name":"target","type":"address"},{"name":"allowFailure","type":"bool"},{"name":"callData","type":"bytes"}]}`
+	multicall3ValTuple  = `{"name":"calls","type":"tuple[]","components":[{"name":"target","type":"address"},{"name":"allowFailure","type":"bool"},{"name":"value","type":"uint256"},{"name":"callData","type":"bytes"}]}`
+	userOperationTuple  = `{"name":"ops","type":"tuple[]","components":[{"name":"sender","type":"address"},{"name":"nonce","type":"uint256"},{"name":"initCode","type":"bytes"},{"name":"callData","type":"bytes"},{"name":"callGasLimit","type":"uint256"},{"name":"verificationGasLimit","type":"uint256"},{"name":"preVerificationGas","type":"uint256"},{"name":"maxFeePerGas","type":"uint256"},{"name":"maxPriorityFeePerGas","type":"uint256"},{"name":"paymasterAndData","type":"bytes"},{"name":"signature","type":"bytes"}]}`
+	packedUserOperTuple = `{"name":"ops","type":"tuple[]","components":[{"name":"sender","type":"address"},{"name":"nonce","type":"uint256"},{"name":"initCode","type":"bytes"},{"name":"callData","type":"bytes"},{"name":"accountGasLimits","type":"bytes32"},{"name":"preVerificationGas","type":"uint256"},{"name":"gasFees","type":"bytes32"},{"name":"paymasterAndData","type":"bytes"},{"name":"signature","type":"bytes"}]}`
+)
+
+// newEnvelopes returns the envelopes by the selectors of their methods.
+func newEnvelopes() (map[[4]byte]*envelope, error) {
+	definitions := []struct {
+		name   string
+		method string // Name of the method followed by its JSON ABI inputs.
+		unwrap func(d *CallDecoder, call *Call, inputs map[string]interface{}) ([]*Call, error)
+	}{
+		// Multicall of the Uniswap periphery contracts, delegate calling the contract itself.
+		{"multicall", `multicall:[{"name":"data","type":"bytes[]"}]`, unwrapSelfMulticall},
+		{"multicall", `multicall:[{"name":"deadline","type":"uint256"},{"name":"data","type":"bytes[]"}]`, unwrapSelfMulticall},
+		{"multicall", `multicall:[{"name":"previousBlockhash","type":"bytes32"},{"name":"data","type":"bytes[]"}]`, unwrapSelfMulticall},
+
+		// Multicall, Multicall2 and Multicall3 aggregates.
+		{"multicall3", `aggregate:[` + multicallTuple + `]`, unwrapAggregate},
+		{"multicall3", `blockAndAggregate:[` + multicallTuple + `]`, unwrapAggregate},
+		{"multicall3", `tryAggregate:[{"name":"requireSuccess","type":"bool"},` + multicallTuple + `]`, unwrapAggregate},
+		{"multicall3", `tryBlockAndAggregate:[{"name":"requireSuccess","type":"bool"},` + multicallTuple + `]`, unwrapAggregate},
+		{"multicall3", `aggregate3:[` + multicall3Tuple + `]`, unwrapAggregate3},
+		{"multicall3", `aggregate3Value:[` + multicall3ValTuple + `]`, unwrapAggregate3Value},
+
+		// Gnosis Safe transactions and the MultiSend batches they delegate call.
+		{"safe", `execTransaction:[{"name":"to","type":"address"},{"name":"value","type":"uint256"},{"name":"data","type":"bytes"},{"name":"operation","type":"uint8"},{"name":"safeTxGas","type":"uint256"},{"name":"baseGas","type":"uint256"},{"name":"gasPrice","type":"uint256"},{"name":"gasToken","type":"address"},{"name":"refundReceiver","type":"address"},{"name":"signatures","type":"bytes"}]`, unwrapSafeTransaction},
+		{"safe", `execTransactionFromModule:[{"name":"to","type":"address"},{"name":"value","type":"uint256"},{"name":"data","type":"bytes"},{"name":"operation","type":"uint8"}]`, unwrapSafeTransaction},
+		{"multisend", `multiSend:[{"name":"transactions","type":"bytes"}]`, unwrapMultiSend},
+
+		// Uniswap Universal Router commands.
+		{"universal_router", `execute:[{"name":"commands","type":"bytes"},{"name":"inputs","type":"bytes[]"}]`, unwrapRouterExecute},
+		{"universal_router", `execute:[{"name":"commands","type":"bytes"},{"name":"inputs","type":"bytes[]"},{"name":"deadline","type":"uint256"}]`, unwrapRouterExecute},
+
+		// ERC-4337 EntryPoint v0.6 and v0.7 user operations, and the calls of the smart accounts executing them.
+		{"erc4337", `handleOps:[` + userOperationTuple + `,{"name":"beneficiary","type":"address"}]`, unwrapUserOperations},
+		{"erc4337", `handleOps:[` + packedUserOperTuple + `,{"name":"beneficiary","type":"address"}]`, unwrapPackedUserOperations},
+		{"smart_account", `execute:[{"name":"dest","type":"address"},{"name":"value","type":"uint256"},{"name":"func","type":"bytes"}]`, unwrapAccountExecute},
+		{"smart_account", `executeBatch:[{"name":"dest","type":"address[]"},{"name":"func","type":"bytes[]"}]`, unwrapAccountBatch},
+		{"smart_account", `executeBatch:[{"name":"dest","type":"address[]"},{"name":"value","type":"uint256[]"},{"name":"func","type":"bytes[]"}]`, unwrapAccountBatch},
+
+		// DSProxy and the other forwarders delegate calling the target.
+		{"forwarder", `execute:[{"name":"target","type":"address"},{"name":"data","type":"bytes"}]`, unwrapForwarder},
+	}
+
+	envelopes := make(map[[4]byte]*envelope, len(definitions))
+	for _, definition := range definitions {
+		name, inputs, _ := strings.Cut(definition.method, ":")
+
+		// Every method is parsed on its own, so the overloaded ones keep their names.
+		parsed, err := abi.JSON(strings.NewReader(fmt.Sprintf(`[{"type":"function","name":"%s","inputs":%s}]`, name, inputs)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s envelope: %s", definition.name, err)
+		}
+
+		method := parsed.Methods[name]
+		envelopes[[4]byte(method.ID)] = &envelope{
+			name:   definition.name,
+			method: &method,
+			unwrap: definition.unwrap,
+		}
+	}
+
+	return envelopes, nil
+}
+
+// convert converts the decoded ABI value into the given type, matching the tuple fields by their position.
+func convert[T any](value interface{}) (result T, err error) {
+	if value == nil {
+		return result, fmt.Errorf("missing value of %T", result)
+	}
+
+	// Conversion panics when the value does not fit the type.
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("failed to convert %T into %T: %v", value, result, r)
+		}
+	}()
+
+	return *abi.ConvertType(value, new(T)).(*T), nil
+}
+
+// unwrapSelfMulticall returns the calls of the multicall delegate calling the contract itself.
+func unwrapSelfMulticall(d *CallDecoder, call *Call, inputs map[string]interface{}) ([]*Call, error) {
+	data, err := convert[[][]byte](inputs["data"])
+	if err != nil {
+		return nil, err
+	}
+
+	calls := make([]*Call, 0, len(data))
+	for _, callData := range data {
+		calls = append(calls, &Call{Operation: OperationDelegateCall, Target: call.Target, Data: callData})
+	}
+	return calls, nil
+}
+
+// unwrapAggregate returns the calls of the aggregate, tolerating their failures when the success is not required.
+func unwrapAggregate(d *CallDecoder, call *Call, inputs map[string]interface{}) ([]*Call, error) {
+	aggregated, err := convert[[]multicallCall](inputs["calls"])
+	if err != nil {
+		return nil, err
+	}
+
+	requireSuccess := true
+	if value, ok := inputs["requireSuccess"].(bool); ok {
+		requireSuccess = value
+	}
+
+	calls := make([]*Call, 0, len(aggregated))
+	for _, c := range aggregated {
+		calls = append(calls, &Call{Operation: OperationCall, Target: c.Target, Data: c.CallData, AllowFailure: !requireSuccess})
+	}
+	return calls, nil
+}
+
+// unwrapAggregate3 returns the calls of the Multicall3 aggregate3.
+func unwrapAggregate3(d *CallDecoder, call *Call, inputs map[string]interface{}) ([]*Call, error) {
+	aggregated, err := convert[[]multicallCall3](inputs["calls"])
+	if err != nil {
+		return nil, err
+	}
+
+	calls := make([]*Call, 0, len(aggregated))
+	for _, c := range aggregated {
+		calls = append(calls, &Call{Operation: OperationCall, Target: c.Target, Data: c.CallData, AllowFailure: c.AllowFailure})
+	}
+	return calls, nil
+}
+
+// unwrapAggregate3Value returns the calls of the Multicall3 aggregate3Value, along with their values.
+func unwrapAggregate3Value(d *CallDecoder, call *Call, inputs map[string]interface{}) ([]*Call, error) {
+	aggregated, err := convert[[]multicallCall3Value](inputs["calls"])
+	if err != nil {
+		return nil, err
+	}
+
+	calls := make([]*Call, 0, len(aggregated))
+	for _, c := range aggregated {
+		calls = append(calls, &Call{Operation: OperationCall, Target: c.Target, Value: c.Value, Data: c.CallData, AllowFailure: c.AllowFailure})
+	}
+	return calls, nil
+}
+
+// safeOperation returns the operation of the Safe transaction.
+func safeOperation(operation uint8) (CallOperation, error) {
+	switch operation {
+	case 0:
+		return OperationCall, nil
+	case 1:
+		return OperationDelegateCall, nil
+	default:
+		return "", fmt.Errorf("invalid safe operation: %d", operation)
+	}
+}
+
+// unwrapSafeTransaction returns the call of the Safe transaction.
+func unwrapSafeTransaction(d *CallDecoder, call *Call, inputs map[string]interface{}) ([]*Call, error) {
+	to, err := convert[common.Address](inputs["to"])
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := convert[*big.Int](inputs["value"])
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := convert[[]byte](inputs["data"])
+	if err != nil {
+		return nil, err
+	}
+
+	op, err := convert[uint8](inputs["operation"])
+	if err != nil {
+		return nil, err
+	}
+
+	operation, err := safeOperation(op)
+	if err != nil {
+		return nil, err
+	}
+
+	return []*Call{{Operation: operation, Target: to, Value: value, Data: data}}, nil
+}
+
+// unwrapMultiSend returns the calls of the MultiSend batch, packed as the operation (1 byte), the target
+// (20 bytes), the value (32 bytes), the length of the data (32 bytes) and the data of every call.
+func unwrapMultiSend(d *CallDecoder, call *Call, inputs map[string]interface{}) ([]*Call, error) {
+	transactions, err := convert[[]byte](inputs["transactions"])
+	if err != nil {
+		return nil, err
+	}
+
+	var calls []*Call
+	for offset := 0; offset < len(transactions); {
+		if len(transactions)-offset < 85 {
+			return nil, fmt.Errorf("truncated multisend transaction at offset %d", offset)
+		}
+
+		operation, err := safeOperation(transactions[offset])
+		if err != nil {
+			return nil, err
+		}
+
+		length := new(big.Int).SetBytes(transactions[offset+53 : offset+85])
+		if !length.IsUint64() || length.Uint64() > uint64(len(transactions)-offset-85) {
+			return nil, fmt.Errorf("invalid multisend data length at offset %d", offset)
+		}
+		end := offset + 85 + int(length.Uint64())
+
+		calls = append(calls, &Call{
+			Operation: operation,
+			Target:    common.BytesToAddress(transactions[offset+1 : offset+21]),
+			Value:     new(big.Int).SetBytes(transactions[offset+21 : offset+53]),
+			Data:      transactions[offset+85 : end],
+		})
+		offset = end
+	}
+
+	return calls, nil
+}
+
+// unwrapRouterExecute returns the commands of the Universal Router.
+func unwrapRouterExecute(d *CallDecoder, call *Call, inputs map[string]interface{}) ([]*Call, error) {
+	commands, err := convert[[]byte](inputs["commands"])
+	if err != nil {
+		return nil, err
+	}
+
+	commandInputs, err := convert[[][]byte](inputs["inputs"])
+	if err != nil {
+		return nil, err
+	}
+
+	return d.unwrapCommands(call.Target, commands, commandInputs)
+}
+
+// unwrapUserOperations returns the calls of the EntryPoint v0.6 user operations to their accounts.
+func unwrapUserOperations(d *CallDecoder, call *Call, inputs map[string]interface{}) ([]*Call, error) {
+	ops, err := convert[[]userOperation](inputs["ops"])
+	if err != nil {
+		return nil, err
+	}
+
+	calls := make([]*Call, 0, len(ops))
+	for _, op := range ops {
+		calls = append(calls, &Call{Operation: OperationCall, Target: op.Sender, Data: op.CallData})
+	}
+	return calls, nil
+}
+
+// unwrapPackedUserOperations returns the calls of the EntryPoint v0.7 packed user operations to their accounts.
+func unwrapPackedUserOperations(d *CallDecoder, call *Call, inputs map[string]interface{}) ([]*Call, error) {
+	ops, err := convert[[]packedUserOperation](inputs["ops"])
+	if err != nil {
+		return nil, err
+	}
+
+	calls := make([]*Call, 0, len(ops))
+	for _, op := range ops {
+		calls = append(calls, &Call{Operation: OperationCall, Target: op.Sender, Data: op.CallData})
+	}
+	return calls, nil
+}
+
+// unwrapAccountExecute returns the call of the smart account.
+func unwrapAccountExecute(d *CallDecoder, call *Call, inputs map[string]interface{}) ([]*Call, error) {
+	dest, err := convert[common.Address](inputs["dest"])
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := convert[*big.Int](inputs["value"])
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := convert[[]byte](inputs["func"])
+	if err != nil {
+		return nil, err
+	}
+
+	return []*Call{{Operation: OperationCall, Target: dest, Value: value, Data: data}}, nil
+}
+
+// unwrapAccountBatch returns the batch of the calls of the smart account, with or without their values.
+func unwrapAccountBatch(d *CallDecoder, call *Call, inputs map[string]interface{}) ([]*Call, error) {
+	dest, err := convert[[]common.Address](inputs["dest"])
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := convert[[][]byte](inputs["func"])
+	if err != nil {
+		return nil, err
+	}
+
+	var values []*big.Int
+	if inputs["value"] != nil {
+		if values, err = convert[[]*big.Int](inputs["value"]); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(dest) != len(data) || (values != nil && len(values) != len(dest)) {
+		return nil, fmt.Errorf("mismatched batch lengths: %d targets, %d values, %d calls", len(dest), len(values), len(data))
+	}
+
+	calls := make([]*Call, 0, len(dest))
+	for i := range dest {
+		c := &Call{Operation: OperationCall, Target: dest[i], Data: data[i]}
+		if values != nil {
+			c.Value = values[i]
+		}
+		calls = append(calls, c)
+	}
+	return calls, nil
+}
+
+// unwrapForwarder returns the call the forwarder delegate calls.
+func unwrapForwarder(d *CallDecoder, call *Call, inputs map[string]interface{}) ([]*Call, error) {
+	target, err := convert[common.Address](inputs["target"])
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := convert[[]byte](inputs["data"])
+	if err != nil {
+		return nil, err
+	}
+
+	return []*Call{{Operation: OperationDelegateCall, Target: target, Data: data}}, nil
+}
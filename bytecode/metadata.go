@@ -83,6 +83,18 @@ func (m *Metadata) GetBzzr1() string {
 	return fmt.Sprintf("bzz://%s", base58.Encode(m.Bzzr1))
 }
 
+// GetBzzr0RawURL returns the Swarm (version 0) hash of the contract's metadata as the bzz-raw://<hex> URL
+// resolved by the Swarm gateways, if present.
+func (m *Metadata) GetBzzr0RawURL() string {
+	return fmt.Sprintf("bzz-raw://%x", m.Bzzr0)
+}
+
+// GetBzzr1RawURL returns the Swarm (version 1) hash of the contract's metadata as the bzz-raw://<hex> URL
+// resolved by the Swarm gateways, if present.
+func (m *Metadata) GetBzzr1RawURL() string {
+	return fmt.Sprintf("bzz-raw://%x", m.Bzzr1)
+}
+
 // GetExecutionBytecode returns the execution bytecode of the contract.
 func (m *Metadata) GetExecutionBytecode() []byte {
 	return m.executionBytecode
@@ -107,13 +119,13 @@ func (m *Metadata) AuxFound(b []byte) bool {
 // GetUrls returns the URLs of the contract's metadata.
 func (m *Metadata) GetUrls() []string {
 	urls := make([]string, 0)
-	if len(m.GetIPFS()) > 7 {
+	if len(m.Ipfs) > 0 {
 		urls = append(urls, m.GetIPFS())
 	}
-	if len(m.GetBzzr0()) > 6 {
+	if len(m.Bzzr1) > 0 {
 		urls = append(urls, m.GetBzzr1())
 	}
-	if len(m.GetBzzr1()) > 6 {
+	if len(m.Bzzr0) > 0 {
 		urls = append(urls, m.GetBzzr0())
 	}
 	return urls
@@ -71,3 +71,44 @@ func TestDecodeContractCreationMetadata(t *testing.T) {
 		})
 	}
 }
+
+func TestMetadataSwarmUrls(t *testing.T) {
+	hash, _ := hex.DecodeString("a2e5b3a4b5e9eb9b1cbb3f0bd3b1e0dd6b5b1c8cfb4f9c1a0e3d5e5f6a7b8c9d")
+
+	tests := []struct {
+		name     string
+		metadata *Metadata
+		bzzr0    string
+		bzzr1    string
+		urls     int
+	}{
+		{
+			name:     "Bzzr0",
+			metadata: &Metadata{Bzzr0: hash},
+			bzzr0:    "bzz-raw://a2e5b3a4b5e9eb9b1cbb3f0bd3b1e0dd6b5b1c8cfb4f9c1a0e3d5e5f6a7b8c9d",
+			bzzr1:    "bzz-raw://",
+			urls:     1,
+		},
+		{
+			name:     "Bzzr1",
+			metadata: &Metadata{Bzzr1: hash},
+			bzzr0:    "bzz-raw://",
+			bzzr1:    "bzz-raw://a2e5b3a4b5e9eb9b1cbb3f0bd3b1e0dd6b5b1c8cfb4f9c1a0e3d5e5f6a7b8c9d",
+			urls:     1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.bzzr0, tt.metadata.GetBzzr0RawURL())
+			assert.Equal(t, tt.bzzr1, tt.metadata.GetBzzr1RawURL())
+
+			urls := tt.metadata.GetUrls()
+			assert.Len(t, urls, tt.urls)
+			for _, url := range urls {
+				assert.Contains(t, []string{tt.metadata.GetBzzr0(), tt.metadata.GetBzzr1()}, url)
+				assert.NotEqual(t, "bzz://", url)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package bytecode
+
+import (
+	"fmt"
+	"strings"
+
+	abi "github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// routerCommandMask masks the type of the Universal Router command.
+	routerCommandMask = 0x3f
+
+	// routerAllowRevert is the flag of the Universal Router command allowed to revert.
+	routerAllowRevert = 0x80
+)
+
+// routerCommands are the names of the Universal Router commands by their type.
+var routerCommands = map[byte]string{
+	0x00: "V3_SWAP_EXACT_IN",
+	0x01: "V3_SWAP_EXACT_OUT",
+	0x02: "PERMIT2_TRANSFER_FROM",
+	0x03: "PERMIT2_PERMIT_BATCH",
+	0x04: "SWEEP",
+	0x05: "TRANSFER",
+	0x06: "PAY_PORTION",
+	0x08: "V2_SWAP_EXACT_IN",
+	0x09: "V2_SWAP_EXACT_OUT",
+	0x0a: "PERMIT2_PERMIT",
+	0x0b: "WRAP_ETH",
+	0x0c: "UNWRAP_WETH",
+	0x0d: "PERMIT2_TRANSFER_FROM_BATCH",
+	0x0e: "BALANCE_CHECK_ERC20",
+	0x10: "SEAPORT_V1_5",
+	0x11: "LOOKS_RARE_V2",
+	0x12: "NFTX",
+	0x13: "CRYPTOPUNKS",
+	0x15: "OWNER_CHECK_721",
+	0x16: "OWNER_CHECK_1155",
+	0x17: "SWEEP_ERC721",
+	0x18: "X2Y2_721",
+	0x19: "SUDOSWAP",
+	0x1a: "NFT20",
+	0x1b: "X2Y2_1155",
+	0x1c: "FOUNDATION",
+	0x1d: "SWEEP_ERC1155",
+	0x1e: "ELEMENT_MARKET",
+	0x20: "SEAPORT_V1_4",
+	0x21: "EXECUTE_SUB_PLAN",
+	0x22: "APPROVE_ERC20",
+}
+
+// routerCommandInputs are the JSON ABI inputs of the Universal Router commands decoded into their arguments.
+// Inputs of the marketplace commands are passed through to the marketplaces, so they are left undecoded.
+var routerCommandInputs = map[string]string{
+	"V3_SWAP_EXACT_IN":            `[{"name":"recipient","type":"address"},{"name":"amountIn","type":"uint256"},{"name":"amountOutMin","type":"uint256"},{"name":"path","type":"bytes"},{"name":"payerIsUser","type":"bool"}]`,
+	"V3_SWAP_EXACT_OUT":           `[{"name":"recipient","type":"address"},{"name":"amountOut","type":"uint256"},{"name":"amountInMax","type":"uint256"},{"name":"path","type":"bytes"},{"name":"payerIsUser","type":"bool"}]`,
+	"PERMIT2_TRANSFER_FROM":       `[{"name":"token","type":"address"},{"name":"recipient","type":"address"},{"name":"amount","type":"uint160"}]`,
+	"PERMIT2_PERMIT_BATCH":        `[{"name":"permitBatch","type":"tuple","components":[{"name":"details","type":"tuple[]","components":[{"name":"token","type":"address"},{"name":"amount","type":"uint160"},{"name":"expiration","type":"uint48"},{"name":"nonce","type":"uint48"}]},{"name":"spender","type":"address"},{"name":"sigDeadline","type":"uint256"}]},{"name":"signature","type":"bytes"}]`,
+	"SWEEP":                       `[{"name":"token","type":"address"},{"name":"recipient","type":"address"},{"name":"amountMin","type":"uint256"}]`,
+	"TRANSFER":                    `[{"name":"token","type":"address"},{"name":"recipient","type":"address"},{"name":"value","type":"uint256"}]`,
+	"PAY_PORTION":                 `[{"name":"token","type":"address"},{"name":"recipient","type":"address"},{"name":"bips","type":"uint256"}]`,
+	"V2_SWAP_EXACT_IN":            `[{"name":"recipient","type":"address"},{"name":"amountIn","type":"uint256"},{"name":"amountOutMin","type":"uint256"},{"name":"path","type":"address[]"},{"name":"payerIsUser","type":"bool"}]`,
+	"V2_SWAP_EXACT_OUT":           `[{"name":"recipient","type":"address"},{"name":"amountOut","type":"uint256"},{"name":"amountInMax","type":"uint256"},{"name":"path","type":"address[]"},{"name":"payerIsUser","type":"bool"}]`,
+	"PERMIT2_PERMIT":              `[{"name":"permitSingle","type":"tuple","components":[{"name":"details","type":"tuple","components":[{"name":"token","type":"address"},{"name":"amount","type":"uint160"},{"name":"expiration","type":"uint48"},{"name":"nonce","type":"uint48"}]},{"name":"spender","type":"address"},{"name":"sigDeadline","type":"uint256"}]},{"name":"signature","type":"bytes"}]`,
+	"WRAP_ETH":                    `[{"name":"recipient","type":"address"},{"name":"amountMin","type":"uint256"}]`,
+	"UNWRAP_WETH":                 `[{"name":"recipient","type":"address"},{"name":"amountMin","type":"uint256"}]`,
+	"PERMIT2_TRANSFER_FROM_BATCH": `[{"name":"batchDetails","type":"tuple[]","components":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"amount","type":"uint160"},{"name":"token","type":"address"}]}]`,
+	"BALANCE_CHECK_ERC20":         `[{"name":"owner","type":"address"},{"name":"token","type":"address"},{"name":"minBalance","type":"uint256"}]`,
+	"EXECUTE_SUB_PLAN":            `[{"name":"commands","type":"bytes"},{"name":"inputs","type":"bytes[]"}]`,
+}
+
+// newRouterCommands returns the ABI arguments of the Universal Router commands by their names.
+func newRouterCommands() (map[string]abi.Arguments, error) {
+	var methods []string
+	for name, inputs := range routerCommandInputs {
+		methods = append(methods, fmt.Sprintf(`{"type":"function","name":"%s","inputs":%s}`, name, inputs))
+	}
+
+	parsed, err := abi.JSON(strings.NewReader("[" + strings.Join(methods, ",") + "]"))
+	if err != nil {
+		return nil, err
+	}
+
+	commands := make(map[string]abi.Arguments, len(parsed.Methods))
+	for name, method := range parsed.Methods {
+		commands[name] = method.Inputs
+	}
+
+	return commands, nil
+}
+
+// unwrapCommands returns the commands executed by the Universal Router, with the commands of the sub plans
+// as their children.
+func (d *CallDecoder) unwrapCommands(router common.Address, commands []byte, inputs [][]byte) ([]*Call, error) {
+	if len(commands) != len(inputs) {
+		return nil, fmt.Errorf("mismatched router commands and inputs: %d commands, %d inputs", len(commands), len(inputs))
+	}
+
+	calls := make([]*Call, 0, len(commands))
+	for i, command := range commands {
+		name, ok := routerCommands[command&routerCommandMask]
+		if !ok {
+			name = fmt.Sprintf("UNKNOWN_0x%02x", command&routerCommandMask)
+		}
+
+		call := &Call{
+			Operation:    OperationCommand,
+			Target:       router,
+			Data:         inputs[i],
+			AllowFailure: command&routerAllowRevert != 0,
+			Command:      name,
+		}
+
+		if arguments, ok := d.commands[name]; ok {
+			call.Inputs = make(map[string]interface{})
+			if err := arguments.UnpackIntoMap(call.Inputs, inputs[i]); err != nil {
+				return nil, fmt.Errorf("failed to unpack %s command inputs: %s", name, err)
+			}
+		}
+
+		if name == "EXECUTE_SUB_PLAN" {
+			subCommands, err := convert[[]byte](call.Inputs["commands"])
+			if err != nil {
+				return nil, err
+			}
+
+			subInputs, err := convert[[][]byte](call.Inputs["inputs"])
+			if err != nil {
+				return nil, err
+			}
+
+			if call.Calls, err = d.unwrapCommands(router, subCommands, subInputs); err != nil {
+				return nil, err
+			}
+		}
+
+		calls = append(calls, call)
+	}
+
+	return calls, nil
+}
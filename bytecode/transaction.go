@@ -33,6 +33,10 @@ type Transaction struct {
 // This function simplifies the process of interacting with raw Ethereum transactions, making
 // it easier to analyze and use the transaction data programmatically.
 func DecodeTransactionFromAbi(data []byte, abiData []byte) (*Transaction, error) {
+	if len(data) < 4 {
+		return nil, fmt.Errorf("invalid transaction data length: %d", len(data))
+	}
+
 	// The first 4 bytes of the data represent the ID of the method in the ABI.
 	methodSigData := data[:4]
 
@@ -46,6 +50,11 @@ func DecodeTransactionFromAbi(data []byte, abiData []byte) (*Transaction, error)
 		return nil, fmt.Errorf("failed to get method by id: %s", err)
 	}
 
+	return decodeTransaction(method, data)
+}
+
+// decodeTransaction decodes the arguments of the transaction data calling the given method.
+func decodeTransaction(method *abi.Method, data []byte) (*Transaction, error) {
 	inputsSigData := data[4:]
 	inputsMap := make(map[string]interface{})
 	if err := method.Inputs.UnpackIntoMap(inputsMap, inputsSigData); err != nil {
@@ -59,7 +68,7 @@ func DecodeTransactionFromAbi(data []byte, abiData []byte) (*Transaction, error)
 
 	return &Transaction{
 		Abi:            txAbi,
-		SignatureBytes: methodSigData,
+		SignatureBytes: data[:4],
 		Signature:      method.String(),
 		Name:           method.Name,
 		Method:         method,
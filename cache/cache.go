@@ -0,0 +1,24 @@
+package cache
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// Cache stores the values by their keys for the given time to live, or until evicted when the time to live is zero.
+type Cache interface {
+	// Get returns the cached value, or ErrNotFound when the key is not cached.
+	Get(ctx context.Context, key string) ([]byte, error)
+
+	// Set caches the value for the given time to live, without expiration when the time to live is zero.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+
+	// Delete removes the key from the cache.
+	Delete(ctx context.Context, key string) error
+}
+
+// Key joins the parts of the key with the separator used across the caches.
+func Key(parts ...string) string {
+	return strings.Join(parts, "::")
+}
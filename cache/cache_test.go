@@ -0,0 +1,170 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache(t *testing.T) {
+	disk, err := NewDiskCache(t.TempDir())
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		cache Cache
+	}{
+		{name: "LRU", cache: NewLRUCache(10)},
+		{name: "Disk", cache: disk},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			_, err := tt.cache.Get(ctx, "missing")
+			assert.ErrorIs(t, err, ErrNotFound)
+
+			require.NoError(t, tt.cache.Set(ctx, "key", []byte("value"), 0))
+			value, err := tt.cache.Get(ctx, "key")
+			require.NoError(t, err)
+			assert.Equal(t, []byte("value"), value)
+
+			require.NoError(t, tt.cache.Set(ctx, "key", []byte("updated"), 0))
+			value, err = tt.cache.Get(ctx, "key")
+			require.NoError(t, err)
+			assert.Equal(t, []byte("updated"), value)
+
+			require.NoError(t, tt.cache.Set(ctx, "expiring", []byte("value"), time.Millisecond))
+			time.Sleep(5 * time.Millisecond)
+			_, err = tt.cache.Get(ctx, "expiring")
+			assert.ErrorIs(t, err, ErrNotFound)
+
+			require.NoError(t, tt.cache.Delete(ctx, "key"))
+			_, err = tt.cache.Get(ctx, "key")
+			assert.ErrorIs(t, err, ErrNotFound)
+			require.NoError(t, tt.cache.Delete(ctx, "key"))
+		})
+	}
+}
+
+func TestLRUCacheEviction(t *testing.T) {
+	ctx := context.Background()
+	cache := NewLRUCache(3)
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, cache.Set(ctx, fmt.Sprint(i), []byte{byte(i)}, 0))
+	}
+
+	// Reading the oldest entry makes the second one the least recently used.
+	_, err := cache.Get(ctx, "0")
+	require.NoError(t, err)
+
+	require.NoError(t, cache.Set(ctx, "3", []byte{3}, 0))
+	assert.Equal(t, 3, cache.Len())
+
+	_, err = cache.Get(ctx, "1")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	for _, key := range []string{"0", "2", "3"} {
+		_, err := cache.Get(ctx, key)
+		assert.NoError(t, err, key)
+	}
+}
+
+func TestDiskCachePersistence(t *testing.T) {
+	ctx := context.Background()
+	path := t.TempDir()
+
+	cache, err := NewDiskCache(path)
+	require.NoError(t, err)
+	require.NoError(t, cache.Set(ctx, "key", []byte("value"), 0))
+
+	reopened, err := NewDiskCache(path)
+	require.NoError(t, err)
+
+	value, err := reopened.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value"), value)
+}
+
+func TestNamespace(t *testing.T) {
+	ctx := context.Background()
+	lru := NewLRUCache(10)
+
+	first := NewNamespace(lru, "first", nil)
+	second := NewNamespace(lru, "second", nil)
+
+	require.NoError(t, first.SetJSON(ctx, "key", map[string]int{"value": 1}, Finalized))
+
+	var result map[string]int
+	require.NoError(t, first.GetJSON(ctx, "key", &result))
+	assert.Equal(t, map[string]int{"value": 1}, result)
+
+	assert.ErrorIs(t, second.GetJSON(ctx, "key", &result), ErrNotFound)
+
+	value, err := lru.Get(ctx, "first::key")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"value":1}`, string(value))
+
+	var disabled *Namespace
+	assert.False(t, disabled.IsEnabled())
+	assert.NoError(t, disabled.SetJSON(ctx, "key", 1, Finalized))
+	assert.ErrorIs(t, disabled.GetJSON(ctx, "key", &result), ErrNotFound)
+	assert.False(t, NewNamespace(nil, "empty", nil).IsEnabled())
+}
+
+func TestTTLOptions(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        *TTLOptions
+		finality    Finality
+		expected    time.Duration
+		blockNumber uint64
+		latest      uint64
+		blockFinal  Finality
+	}{
+		{name: "Default Finalized", finality: Finalized, expected: 0, blockNumber: 100, latest: 164, blockFinal: Finalized},
+		{name: "Default Unfinalized", finality: Unfinalized, expected: DefaultUnfinalizedTTL, blockNumber: 100, latest: 163, blockFinal: Unfinalized},
+		{name: "Default Mutable", finality: Mutable, expected: DefaultMutableTTL, blockNumber: 100, latest: 100, blockFinal: Unfinalized},
+		{
+			name:        "Configured",
+			opts:        &TTLOptions{Mutable: time.Second, Unfinalized: 2 * time.Second, Finalized: time.Hour, Confirmations: 2},
+			finality:    Finalized,
+			expected:    time.Hour,
+			blockNumber: 100,
+			latest:      102,
+			blockFinal:  Finalized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.opts.GetTTL(tt.finality))
+			assert.Equal(t, tt.blockFinal, tt.opts.GetBlockFinality(tt.blockNumber, tt.latest))
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	cache, err := New(context.Background(), &Options{Backend: BackendLRU})
+	require.NoError(t, err)
+	assert.IsType(t, &LRUCache{}, cache)
+
+	cache, err = New(context.Background(), &Options{Backend: BackendDisk, Path: t.TempDir()})
+	require.NoError(t, err)
+	assert.IsType(t, &DiskCache{}, cache)
+
+	_, err = New(context.Background(), &Options{Backend: "unknown"})
+	assert.ErrorIs(t, err, ErrBackendNotSupported)
+
+	_, err = New(context.Background(), &Options{Backend: BackendRedis})
+	assert.Error(t, err)
+
+	_, err = New(context.Background(), nil)
+	assert.Error(t, err)
+}
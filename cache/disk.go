@@ -0,0 +1,106 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DiskCache caches the entries in the files of the local directory, so they survive the restarts. Every entry
+// is the file named by the hash of its key, holding the expiration time followed by the value.
+type DiskCache struct {
+	path string
+}
+
+// NewDiskCache creates the cache in the given directory, creating the directory when it does not exist.
+func NewDiskCache(path string) (*DiskCache, error) {
+	if path == "" {
+		return nil, errors.New("cache path not set")
+	}
+
+	if err := os.MkdirAll(path, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	return &DiskCache{path: path}, nil
+}
+
+// GetPath returns the directory of the cache.
+func (c *DiskCache) GetPath() string {
+	return c.path
+}
+
+// Get returns the cached value, or ErrNotFound when the key is not cached.
+func (c *DiskCache) Get(ctx context.Context, key string) ([]byte, error) {
+	data, err := os.ReadFile(c.file(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read cache entry: %w", err)
+	}
+
+	if len(data) < 8 {
+		return nil, ErrNotFound
+	}
+
+	if expiresAt := int64(binary.BigEndian.Uint64(data[:8])); expiresAt != 0 && time.Now().UnixNano() > expiresAt {
+		_ = os.Remove(c.file(key))
+		return nil, ErrNotFound
+	}
+
+	return data[8:], nil
+}
+
+// Set caches the value for the given time to live, without expiration when the time to live is zero. The entry
+// is written to the temporary file first, so the readers never see it partially written.
+func (c *DiskCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	var expiresAt int64
+	if ttl > 0 {
+		expiresAt = time.Now().Add(ttl).UnixNano()
+	}
+
+	data := make([]byte, 8, 8+len(value))
+	binary.BigEndian.PutUint64(data, uint64(expiresAt))
+	data = append(data, value...)
+
+	tmp, err := os.CreateTemp(c.path, ".entry-*")
+	if err != nil {
+		return fmt.Errorf("failed to create cache entry: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), c.file(key)); err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes the key from the cache.
+func (c *DiskCache) Delete(ctx context.Context, key string) error {
+	if err := os.Remove(c.file(key)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete cache entry: %w", err)
+	}
+	return nil
+}
+
+// file returns the path of the file of the key.
+func (c *DiskCache) file(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return filepath.Join(c.path, hex.EncodeToString(hash[:]))
+}
@@ -0,0 +1,8 @@
+// Package cache provides the pluggable cache shared by the providers, the RPC clients and the compiler.
+// Cache is implemented by the Redis, in-memory LRU and on-disk backends, while Namespace prefixes the keys
+// of the single consumer and picks the time to live of the entries by the finality of the cached data, so the
+// data that never changes, such as the content addressed metadata or the state of the finalized blocks, is
+// kept until evicted and the contracts already seen can be analyzed again without the network. The RPC
+// clients cache only the reads at the given block, while the reads at the latest block, such as the deployed
+// code the contracts package reads through Client.CodeAt, change with every block and always go to the node.
+package cache
@@ -0,0 +1,9 @@
+package cache
+
+import "errors"
+
+// ErrNotFound is returned when the key is not cached or its entry expired.
+var ErrNotFound = errors.New("cache entry not found")
+
+// ErrBackendNotSupported is returned when the configured cache backend is not supported.
+var ErrBackendNotSupported = errors.New("cache backend not supported")
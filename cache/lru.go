@@ -0,0 +1,104 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// LRUCache caches the entries in memory, evicting the least recently used entries once full.
+type LRUCache struct {
+	mu      sync.Mutex
+	size    int
+	entries map[string]*list.Element
+	order   *list.List // Entries from the most to the least recently used.
+}
+
+// lruEntry is the entry of the in-memory cache.
+type lruEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time // Zero for no expiration.
+}
+
+// NewLRUCache creates the in-memory cache holding up to the given number of entries.
+func NewLRUCache(size int) *LRUCache {
+	if size <= 0 {
+		size = DefaultSize
+	}
+
+	return &LRUCache{
+		size:    size,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// Len returns the number of the cached entries, including the expired ones not evicted yet.
+func (c *LRUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// Get returns the cached value, or ErrNotFound when the key is not cached.
+func (c *LRUCache) Get(ctx context.Context, key string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	entry := element.Value.(*lruEntry)
+	if !entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt) {
+		c.remove(element)
+		return nil, ErrNotFound
+	}
+
+	c.order.MoveToFront(element)
+	return entry.value, nil
+}
+
+// Set caches the value for the given time to live, without expiration when the time to live is zero.
+func (c *LRUCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := &lruEntry{key: key, value: value}
+	if ttl > 0 {
+		entry.expiresAt = time.Now().Add(ttl)
+	}
+
+	if element, ok := c.entries[key]; ok {
+		element.Value = entry
+		c.order.MoveToFront(element)
+		return nil
+	}
+
+	c.entries[key] = c.order.PushFront(entry)
+	for c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+
+	return nil
+}
+
+// Delete removes the key from the cache.
+func (c *LRUCache) Delete(ctx context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		c.remove(element)
+	}
+	return nil
+}
+
+// remove removes the entry of the element. It must be called with the lock held.
+func (c *LRUCache) remove(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.entries, element.Value.(*lruEntry).key)
+}
@@ -0,0 +1,87 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/goccy/go-json"
+)
+
+// Namespace is the part of the cache used by the single consumer. It prefixes the keys with its name and caches
+// the entries for the time to live of the finality of their data. Namespace of the nil cache caches nothing, so
+// the consumers do not have to check whether the cache is configured.
+type Namespace struct {
+	cache Cache
+	name  string
+	ttl   *TTLOptions
+}
+
+// NewNamespace creates the namespace of the cache, using the default time to live options when nil.
+func NewNamespace(cache Cache, name string, ttl *TTLOptions) *Namespace {
+	return &Namespace{cache: cache, name: name, ttl: ttl}
+}
+
+// GetName returns the name the keys of the namespace are prefixed with.
+func (n *Namespace) GetName() string {
+	return n.name
+}
+
+// GetTTLOptions returns the time to live options of the namespace.
+func (n *Namespace) GetTTLOptions() *TTLOptions {
+	return n.ttl
+}
+
+// IsEnabled reports whether the namespace caches anything.
+func (n *Namespace) IsEnabled() bool {
+	return n != nil && n.cache != nil
+}
+
+// Get returns the cached value of the key, or ErrNotFound when the key is not cached.
+func (n *Namespace) Get(ctx context.Context, key string) ([]byte, error) {
+	if !n.IsEnabled() {
+		return nil, ErrNotFound
+	}
+	return n.cache.Get(ctx, Key(n.name, key))
+}
+
+// Set caches the value of the key for the time to live of the given finality.
+func (n *Namespace) Set(ctx context.Context, key string, value []byte, finality Finality) error {
+	if !n.IsEnabled() {
+		return nil
+	}
+	return n.cache.Set(ctx, Key(n.name, key), value, n.ttl.GetTTL(finality))
+}
+
+// Delete removes the key from the cache.
+func (n *Namespace) Delete(ctx context.Context, key string) error {
+	if !n.IsEnabled() {
+		return nil
+	}
+	return n.cache.Delete(ctx, Key(n.name, key))
+}
+
+// GetJSON decodes the cached JSON value of the key into the result, returning ErrNotFound when the key is not cached.
+func (n *Namespace) GetJSON(ctx context.Context, key string, result interface{}) error {
+	value, err := n.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(value, result); err != nil {
+		return fmt.Errorf("failed to unmarshal cache entry: %w", err)
+	}
+	return nil
+}
+
+// SetJSON caches the JSON encoded value of the key for the time to live of the given finality.
+func (n *Namespace) SetJSON(ctx context.Context, key string, value interface{}, finality Finality) error {
+	if !n.IsEnabled() {
+		return nil
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache entry: %w", err)
+	}
+	return n.Set(ctx, key, encoded, finality)
+}
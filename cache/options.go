@@ -0,0 +1,159 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// DefaultMutableTTL is the time the data that may change at any time is cached for.
+	DefaultMutableTTL = time.Hour
+
+	// DefaultUnfinalizedTTL is the time the data of the blocks that may still be reorganized is cached for.
+	DefaultUnfinalizedTTL = time.Minute
+
+	// DefaultConfirmations is the number of the confirmations the block is considered finalized after.
+	DefaultConfirmations = 64
+
+	// DefaultSize is the number of the entries the in-memory cache holds.
+	DefaultSize = 10000
+)
+
+// Backend is the storage of the cached entries.
+type Backend string
+
+const (
+	// BackendRedis caches the entries in Redis.
+	BackendRedis Backend = "redis"
+
+	// BackendLRU caches the entries in memory, evicting the least recently used ones.
+	BackendLRU Backend = "lru"
+
+	// BackendDisk caches the entries in the files of the local directory.
+	BackendDisk Backend = "disk"
+)
+
+// Finality describes whether and how the cached data may change.
+type Finality int
+
+const (
+	// Mutable data may change at any time, such as the latest state or the explorer listings.
+	Mutable Finality = iota
+
+	// Unfinalized data changes only when the block it was read at is reorganized.
+	Unfinalized
+
+	// Finalized data never changes, such as the content addressed metadata, the verified sources or the
+	// state of the finalized blocks.
+	Finalized
+)
+
+// TTLOptions defines the time to live of the entries by the finality of the cached data.
+type TTLOptions struct {
+	Mutable       time.Duration `mapstructure:"mutable" yaml:"mutable" json:"mutable"`                   // Time the mutable data is cached for.
+	Unfinalized   time.Duration `mapstructure:"unfinalized" yaml:"unfinalized" json:"unfinalized"`       // Time the unfinalized data is cached for.
+	Finalized     time.Duration `mapstructure:"finalized" yaml:"finalized" json:"finalized"`             // Time the finalized data is cached for, until evicted when zero.
+	Confirmations uint64        `mapstructure:"confirmations" yaml:"confirmations" json:"confirmations"` // Number of the confirmations the block is considered finalized after.
+}
+
+// GetTTL returns the time to live of the data of the given finality, zero for no expiration.
+func (o *TTLOptions) GetTTL(finality Finality) time.Duration {
+	switch finality {
+	case Finalized:
+		if o == nil {
+			return 0
+		}
+		return o.Finalized
+	case Unfinalized:
+		if o == nil || o.Unfinalized <= 0 {
+			return DefaultUnfinalizedTTL
+		}
+		return o.Unfinalized
+	default:
+		if o == nil || o.Mutable <= 0 {
+			return DefaultMutableTTL
+		}
+		return o.Mutable
+	}
+}
+
+// GetConfirmations returns the number of the confirmations the block is considered finalized after.
+func (o *TTLOptions) GetConfirmations() uint64 {
+	if o == nil || o.Confirmations == 0 {
+		return DefaultConfirmations
+	}
+	return o.Confirmations
+}
+
+// GetBlockFinality returns the finality of the data read at the block, given the latest block number.
+func (o *TTLOptions) GetBlockFinality(blockNumber uint64, latest uint64) Finality {
+	if latest >= blockNumber+o.GetConfirmations() {
+		return Finalized
+	}
+	return Unfinalized
+}
+
+// RedisOptions defines the connection to Redis.
+type RedisOptions struct {
+	Addr     string `mapstructure:"addr" yaml:"addr" json:"addr"`             // Address of the Redis server.
+	Password string `mapstructure:"password" yaml:"password" json:"password"` // Password of the Redis server.
+	DB       int    `mapstructure:"db" yaml:"db" json:"db"`                   // Database of the Redis server.
+}
+
+// Options defines the cache backend and the time to live of the entries.
+type Options struct {
+	Backend Backend       `mapstructure:"backend" yaml:"backend" json:"backend"` // Storage of the cached entries.
+	Redis   *RedisOptions `mapstructure:"redis" yaml:"redis" json:"redis"`       // Connection to Redis of the redis backend.
+	Size    int           `mapstructure:"size" yaml:"size" json:"size"`          // Number of the entries of the lru backend.
+	Path    string        `mapstructure:"path" yaml:"path" json:"path"`          // Directory of the disk backend.
+	TTL     *TTLOptions   `mapstructure:"ttl" yaml:"ttl" json:"ttl"`             // Time to live of the entries by the finality.
+}
+
+// GetSize returns the number of the entries of the lru backend.
+func (o *Options) GetSize() int {
+	if o == nil || o.Size <= 0 {
+		return DefaultSize
+	}
+	return o.Size
+}
+
+// GetTTL returns the time to live options of the entries.
+func (o *Options) GetTTL() *TTLOptions {
+	if o == nil {
+		return nil
+	}
+	return o.TTL
+}
+
+// New creates the cache of the configured backend.
+func New(ctx context.Context, opts *Options) (Cache, error) {
+	if opts == nil {
+		return nil, fmt.Errorf("cache options not set")
+	}
+
+	switch opts.Backend {
+	case BackendRedis:
+		if opts.Redis == nil || opts.Redis.Addr == "" {
+			return nil, fmt.Errorf("redis address not set")
+		}
+
+		client := redis.NewClient(&redis.Options{
+			Addr:     opts.Redis.Addr,
+			Password: opts.Redis.Password,
+			DB:       opts.Redis.DB,
+		})
+		if err := client.Ping(ctx).Err(); err != nil {
+			return nil, fmt.Errorf("failed to connect to redis: %w", err)
+		}
+		return NewRedisCache(client), nil
+	case BackendLRU:
+		return NewLRUCache(opts.GetSize()), nil
+	case BackendDisk:
+		return NewDiskCache(opts.Path)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrBackendNotSupported, opts.Backend)
+	}
+}
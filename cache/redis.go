@@ -0,0 +1,43 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisCache caches the entries in Redis.
+type RedisCache struct {
+	client *redis.Client
+}
+
+// NewRedisCache creates the cache backed by the Redis client.
+func NewRedisCache(client *redis.Client) *RedisCache {
+	return &RedisCache{client: client}
+}
+
+// GetClient returns the Redis client of the cache.
+func (c *RedisCache) GetClient() *redis.Client {
+	return c.client
+}
+
+// Get returns the cached value, or ErrNotFound when the key is not cached.
+func (c *RedisCache) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := c.client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrNotFound
+	}
+	return value, err
+}
+
+// Set caches the value for the given time to live, without expiration when the time to live is zero.
+func (c *RedisCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return c.client.Set(ctx, key, value, ttl).Err()
+}
+
+// Delete removes the key from the cache.
+func (c *RedisCache) Delete(ctx context.Context, key string) error {
+	return c.client.Del(ctx, key).Err()
+}
@@ -0,0 +1,326 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/unpackdev/solgo/cache"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultBatchWindow is the time the reads are collected for before the batch is sent.
+	DefaultBatchWindow = 2 * time.Millisecond
+
+	// DefaultMaxBatchSize is the number of the reads the batch is sent at without waiting for the window.
+	DefaultMaxBatchSize = 100
+)
+
+// BatchCaller sends the JSON-RPC batch requests. It is satisfied by Client and rpc.Client.
+type BatchCaller interface {
+	BatchCallContext(ctx context.Context, batch []rpc.BatchElem) error
+}
+
+// BatchOptions defines the options of the batcher.
+type BatchOptions struct {
+	Window       time.Duration `mapstructure:"window" yaml:"window" json:"window"`                   // Time the reads are collected for before the batch is sent.
+	MaxBatchSize int           `mapstructure:"maxBatchSize" yaml:"maxBatchSize" json:"maxBatchSize"` // Number of the reads the batch is sent at without waiting for the window.
+}
+
+// GetWindow returns the time the reads are collected for before the batch is sent.
+func (o *BatchOptions) GetWindow() time.Duration {
+	if o == nil || o.Window <= 0 {
+		return DefaultBatchWindow
+	}
+	return o.Window
+}
+
+// GetMaxBatchSize returns the number of the reads the batch is sent at without waiting for the window.
+func (o *BatchOptions) GetMaxBatchSize() int {
+	if o == nil || o.MaxBatchSize <= 0 {
+		return DefaultMaxBatchSize
+	}
+	return o.MaxBatchSize
+}
+
+// Batcher coalesces the concurrent JSON-RPC reads into the batch requests. Reads are collected until the
+// batch window passes or the batch is full, and each caller waits only for the result of its own read.
+// The code and storage read at the given block are cached, until evicted once the block is finalized.
+type Batcher struct {
+	ctx     context.Context
+	caller  BatchCaller
+	opts    *BatchOptions
+	mu      sync.Mutex
+	pending []*batchCall
+	timer   *time.Timer
+	cache   *cache.Namespace
+	headMu  sync.Mutex
+	head    uint64    // Latest block number, used to tell whether the read block is finalized.
+	headAt  time.Time // Time the latest block number was read at.
+}
+
+// batchCall is the read waiting in the batch.
+type batchCall struct {
+	elem rpc.BatchElem
+	done chan struct{}
+}
+
+// NewBatcher creates the batcher sending the batches with the caller. Batches are sent within the given
+// context, so the context of the single read cancels only the waiting for its result.
+func NewBatcher(ctx context.Context, caller BatchCaller, opts *BatchOptions) (*Batcher, error) {
+	if caller == nil {
+		return nil, errors.New("batch caller is not set")
+	}
+
+	if opts == nil {
+		opts = &BatchOptions{}
+	}
+
+	return &Batcher{
+		ctx:    ctx,
+		caller: caller,
+		opts:   opts,
+	}, nil
+}
+
+// GetMaxBatchSize returns the number of the reads the batch is sent at without waiting for the window. Callers
+// fanning the reads out bound the concurrent reads by it, so a single batch is filled at a time.
+func (b *Batcher) GetMaxBatchSize() int {
+	return b.opts.GetMaxBatchSize()
+}
+
+// Call performs the JSON-RPC call of the given method as the part of the batch, and decodes its result
+// into the result argument.
+func (b *Batcher) Call(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	call := &batchCall{
+		elem: rpc.BatchElem{Method: method, Args: args, Result: new(json.RawMessage)},
+		done: make(chan struct{}),
+	}
+
+	b.mu.Lock()
+	b.pending = append(b.pending, call)
+	if len(b.pending) >= b.opts.GetMaxBatchSize() {
+		batch := b.take()
+		b.mu.Unlock()
+		go b.send(batch)
+	} else {
+		if b.timer == nil {
+			b.timer = time.AfterFunc(b.opts.GetWindow(), b.flush)
+		}
+		b.mu.Unlock()
+	}
+
+	select {
+	case <-call.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	if call.elem.Error != nil {
+		return call.elem.Error
+	}
+
+	raw := *call.elem.Result.(*json.RawMessage)
+	if result == nil || len(raw) == 0 {
+		return nil
+	}
+	return json.Unmarshal(raw, result)
+}
+
+// CallContract executes the message call as the part of the batch, at the given block number or at the
+// latest block when the block number is nil.
+func (b *Batcher) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	var result hexutil.Bytes
+	if err := b.Call(ctx, &result, "eth_call", toCallArg(msg), toBlockNumArg(blockNumber)); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// StorageAt returns the value of the storage slot of the account as the part of the batch.
+func (b *Batcher) StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) ([]byte, error) {
+	cacheKey := cache.Key("storage", account.Hex(), key.Hex(), toBlockNumArg(blockNumber))
+	return b.cachedRead(ctx, cacheKey, blockNumber, func() ([]byte, error) {
+		var result hexutil.Bytes
+		if err := b.Call(ctx, &result, "eth_getStorageAt", account, key, toBlockNumArg(blockNumber)); err != nil {
+			return nil, err
+		}
+		return result, nil
+	})
+}
+
+// CodeAt returns the code of the account as the part of the batch.
+func (b *Batcher) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error) {
+	cacheKey := cache.Key("code", account.Hex(), toBlockNumArg(blockNumber))
+	return b.cachedRead(ctx, cacheKey, blockNumber, func() ([]byte, error) {
+		var result hexutil.Bytes
+		if err := b.Call(ctx, &result, "eth_getCode", account, toBlockNumArg(blockNumber)); err != nil {
+			return nil, err
+		}
+		return result, nil
+	})
+}
+
+// cachedRead returns the cached result of the read at the given block, performing the read on the cache miss.
+// Reads at the latest block or the block tags change with every block and are not cached. The latest block
+// number telling the finality of the read block is requested in the same batch as the read.
+func (b *Batcher) cachedRead(ctx context.Context, key string, blockNumber *big.Int, read func() ([]byte, error)) ([]byte, error) {
+	if !b.cache.IsEnabled() || blockNumber == nil || blockNumber.Sign() < 0 {
+		return read()
+	}
+
+	if cached, err := b.cache.Get(ctx, key); err == nil {
+		return cached, nil
+	}
+
+	var head uint64
+	var headErr error
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		head, headErr = b.headNumber(ctx)
+	}()
+
+	result, err := read()
+	wg.Wait()
+
+	if err != nil || headErr != nil {
+		return result, err
+	}
+
+	finality := b.cache.GetTTLOptions().GetBlockFinality(blockNumber.Uint64(), head)
+	if err := b.cache.Set(ctx, key, result, finality); err != nil {
+		zap.L().Warn("failed to cache read", zap.String("key", key), zap.Error(err))
+	}
+
+	return result, nil
+}
+
+// headNumber returns the latest block number, read again once the time unfinalized data is cached for passes.
+func (b *Batcher) headNumber(ctx context.Context) (uint64, error) {
+	b.headMu.Lock()
+	if !b.headAt.IsZero() && time.Since(b.headAt) < b.cache.GetTTLOptions().GetTTL(cache.Unfinalized) {
+		head := b.head
+		b.headMu.Unlock()
+		return head, nil
+	}
+	b.headMu.Unlock()
+
+	var head hexutil.Uint64
+	if err := b.Call(ctx, &head, "eth_blockNumber"); err != nil {
+		return 0, err
+	}
+
+	b.headMu.Lock()
+	defer b.headMu.Unlock()
+
+	if uint64(head) > b.head {
+		b.head = uint64(head)
+	}
+	b.headAt = time.Now()
+	return b.head, nil
+}
+
+// flush sends the reads collected within the batch window.
+func (b *Batcher) flush() {
+	b.mu.Lock()
+	batch := b.take()
+	b.mu.Unlock()
+
+	if len(batch) > 0 {
+		b.send(batch)
+	}
+}
+
+// take removes the pending reads from the batcher. It must be called with the lock held.
+func (b *Batcher) take() []*batchCall {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+
+	batch := b.pending
+	b.pending = nil
+	return batch
+}
+
+// send sends the batch request, failing every read of the batch when the request itself fails.
+func (b *Batcher) send(batch []*batchCall) {
+	elems := make([]rpc.BatchElem, len(batch))
+	for i, call := range batch {
+		elems[i] = call.elem
+	}
+
+	err := b.caller.BatchCallContext(b.ctx, elems)
+
+	for i, call := range batch {
+		call.elem.Error = elems[i].Error
+		if err != nil {
+			call.elem.Error = fmt.Errorf("failed to send batch request: %w", err)
+		}
+		close(call.done)
+	}
+}
+
+// toBlockNumArg returns the JSON-RPC block argument of the block number, the latest block when nil.
+func toBlockNumArg(number *big.Int) string {
+	if number == nil {
+		return "latest"
+	}
+
+	if number.Sign() >= 0 {
+		return hexutil.EncodeBig(number)
+	}
+
+	// Negative numbers stand for the block tags such as pending or finalized.
+	return rpc.BlockNumber(number.Int64()).String()
+}
+
+// toCallArg returns the JSON-RPC call argument of the message.
+func toCallArg(msg ethereum.CallMsg) interface{} {
+	arg := map[string]interface{}{
+		"from": msg.From,
+		"to":   msg.To,
+	}
+
+	if len(msg.Data) > 0 {
+		arg["input"] = hexutil.Bytes(msg.Data)
+	}
+
+	if msg.Value != nil {
+		arg["value"] = (*hexutil.Big)(msg.Value)
+	}
+
+	if msg.Gas != 0 {
+		arg["gas"] = hexutil.Uint64(msg.Gas)
+	}
+
+	if msg.GasPrice != nil {
+		arg["gasPrice"] = (*hexutil.Big)(msg.GasPrice)
+	}
+
+	if msg.GasFeeCap != nil {
+		arg["maxFeePerGas"] = (*hexutil.Big)(msg.GasFeeCap)
+	}
+
+	if msg.GasTipCap != nil {
+		arg["maxPriorityFeePerGas"] = (*hexutil.Big)(msg.GasTipCap)
+	}
+
+	if msg.AccessList != nil {
+		arg["accessList"] = msg.AccessList
+	}
+
+	return arg
+}
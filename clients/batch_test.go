@@ -0,0 +1,155 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unpackdev/solgo/cache"
+)
+
+// mockBatchCaller serves the storage slots and the latest block number, recording the sizes of the batches
+// and the number of the calls of every method.
+type mockBatchCaller struct {
+	mu      sync.Mutex
+	batches []int
+	methods map[string]int
+	fail    bool
+}
+
+func (c *mockBatchCaller) BatchCallContext(ctx context.Context, batch []rpc.BatchElem) error {
+	c.mu.Lock()
+	c.batches = append(c.batches, len(batch))
+	if c.methods == nil {
+		c.methods = make(map[string]int)
+	}
+	for _, elem := range batch {
+		c.methods[elem.Method]++
+	}
+	c.mu.Unlock()
+
+	if c.fail {
+		return errors.New("connection reset")
+	}
+
+	for i := range batch {
+		if batch[i].Method == "eth_blockNumber" {
+			*batch[i].Result.(*json.RawMessage) = json.RawMessage(`"0x100"`)
+			continue
+		}
+
+		slot := batch[i].Args[1].(common.Hash)
+		if slot == (common.Hash{}) {
+			batch[i].Error = errors.New("missing trie node")
+			continue
+		}
+
+		raw, _ := json.Marshal(hexutil.Bytes(slot.Bytes()))
+		*batch[i].Result.(*json.RawMessage) = raw
+	}
+	return nil
+}
+
+func TestBatcher(t *testing.T) {
+	tests := []struct {
+		name         string
+		opts         *BatchOptions
+		fail         bool
+		reads        int
+		batches      []int
+		expectedErr  string
+		missingSlots bool
+	}{
+		{name: "Single Batch", reads: 5, batches: []int{5}},
+		{name: "Full Batches", opts: &BatchOptions{MaxBatchSize: 2}, reads: 4, batches: []int{2, 2}},
+		{name: "Failed Read", reads: 3, batches: []int{3}, missingSlots: true, expectedErr: "missing trie node"},
+		{name: "Failed Batch", reads: 3, batches: []int{3}, fail: true, expectedErr: "failed to send batch request: connection reset"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			caller := &mockBatchCaller{fail: tt.fail}
+			batcher, err := NewBatcher(context.Background(), caller, tt.opts)
+			require.NoError(t, err)
+
+			var wg sync.WaitGroup
+			results := make([][]byte, tt.reads)
+			errs := make([]error, tt.reads)
+
+			for i := 0; i < tt.reads; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					slot := common.BigToHash(common.Big1)
+					if tt.missingSlots && i == 0 {
+						slot = common.Hash{}
+					}
+					results[i], errs[i] = batcher.StorageAt(context.Background(), common.Address{}, slot, nil)
+				}()
+			}
+			wg.Wait()
+
+			assert.Equal(t, tt.batches, caller.batches)
+
+			for i := 0; i < tt.reads; i++ {
+				if tt.expectedErr != "" && (tt.fail || i == 0) {
+					assert.EqualError(t, errs[i], tt.expectedErr)
+					continue
+				}
+				require.NoError(t, errs[i])
+				assert.Equal(t, common.BigToHash(common.Big1).Bytes(), results[i])
+			}
+		})
+	}
+
+	_, err := NewBatcher(context.Background(), nil, nil)
+	assert.Error(t, err)
+}
+
+func TestBatcherCache(t *testing.T) {
+	tests := []struct {
+		name         string
+		blockNumber  *big.Int
+		storageReads int
+	}{
+		{name: "Finalized Block", blockNumber: big.NewInt(1), storageReads: 1},
+		{name: "Unfinalized Block", blockNumber: big.NewInt(250), storageReads: 1},
+		{name: "Latest Block", storageReads: 3},
+		{name: "Block Tag", blockNumber: big.NewInt(int64(rpc.PendingBlockNumber)), storageReads: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			caller := &mockBatchCaller{}
+			batcher, err := NewBatcher(context.Background(), caller, nil)
+			require.NoError(t, err)
+
+			store := cache.NewLRUCache(10)
+			batcher.cache = cache.NewNamespace(store, "clients", nil)
+
+			slot := common.BigToHash(common.Big1)
+			for i := 0; i < 3; i++ {
+				value, err := batcher.StorageAt(context.Background(), common.Address{}, slot, tt.blockNumber)
+				require.NoError(t, err)
+				assert.Equal(t, slot.Bytes(), value)
+			}
+
+			assert.Equal(t, tt.storageReads, caller.methods["eth_getStorageAt"])
+			if tt.storageReads == 1 {
+				assert.Equal(t, 1, store.Len())
+				assert.Equal(t, 1, caller.methods["eth_blockNumber"])
+			} else {
+				assert.Equal(t, 0, store.Len())
+				assert.Equal(t, 0, caller.methods["eth_blockNumber"])
+			}
+		})
+	}
+}
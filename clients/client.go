@@ -4,16 +4,25 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
+	"strings"
+	"time"
 
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/unpackdev/solgo/utils"
+	"go.uber.org/zap"
 )
 
 // Client wraps the Ethereum client with additional context and options.
 // It provides methods to retrieve client-specific configurations and to close the client connection.
+// Requests to the HTTP endpoints are rate limited and tracked in the client stats.
 type Client struct {
-	ctx  context.Context
-	opts *Node
+	ctx     context.Context
+	opts    *Node
+	stats   *Stats
+	limiter *utils.RateLimiter
+	tracked bool // Whether the requests are tracked by the HTTP transport.
 	*ethclient.Client
 }
 
@@ -21,19 +30,49 @@ type Client struct {
 // It returns an error if the endpoint URL is not set, if there's an issue initializing the Ethereum client,
 // or if there's a mismatch between the provided network ID and the actual network ID.
 func NewClient(ctx context.Context, opts *Node) (*Client, error) {
+	var limiter *utils.RateLimiter
+	if opts.GetRateLimit() > 0 {
+		limiter = utils.NewRateLimiter(opts.GetRateLimit(), time.Second)
+	}
+
+	return newClient(ctx, opts, limiter, newStats(DefaultMaxErrorRate, DefaultMinRequests))
+}
+
+// newClient initializes a new Ethereum client sharing the rate limiter of its endpoint, if any.
+func newClient(ctx context.Context, opts *Node, limiter *utils.RateLimiter, stats *Stats) (*Client, error) {
 	if opts.Endpoint == "" {
 		return nil, errors.New("endpoint URL not set")
 	}
 
-	ethClient, err := ethclient.DialContext(ctx, opts.Endpoint)
+	toReturn := &Client{
+		ctx:     ctx,
+		opts:    opts,
+		stats:   stats,
+		limiter: limiter,
+	}
+
+	var rpcClient *rpc.Client
+	var err error
+
+	if strings.HasPrefix(opts.Endpoint, "http://") || strings.HasPrefix(opts.Endpoint, "https://") {
+		toReturn.tracked = true
+		httpClient := &http.Client{Transport: &trackingTransport{client: toReturn, base: http.DefaultTransport}}
+		rpcClient, err = rpc.DialOptions(ctx, opts.Endpoint, rpc.WithHTTPClient(httpClient))
+	} else {
+		rpcClient, err = rpc.DialContext(ctx, opts.Endpoint)
+	}
+
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize Ethereum client: %v", err)
 	}
+	toReturn.Client = ethclient.NewClient(rpcClient)
 
-	if networkId, err := ethClient.NetworkID(ctx); err != nil {
+	if networkId, err := toReturn.NetworkID(ctx); err != nil {
+		toReturn.Close()
 		return nil, fmt.Errorf("failed to initialize Ethereum client: %v", err)
 	} else {
 		if networkId.Int64() != opts.GetNetworkID() {
+			toReturn.Close()
 			return nil, fmt.Errorf(
 				"failed to initialize Ethereum client due to network IDs mismatch %d->%d",
 				opts.GetNetworkID(), networkId.Int64(),
@@ -41,11 +80,7 @@ func NewClient(ctx context.Context, opts *Node) (*Client, error) {
 		}
 	}
 
-	return &Client{
-		ctx:    ctx,
-		opts:   opts,
-		Client: ethClient,
-	}, nil
+	return toReturn, nil
 }
 
 // GetNetworkID retrieves the network ID for the client.
@@ -83,6 +118,56 @@ func (c *Client) GetRpcClient() *rpc.Client {
 	return c.Client.Client()
 }
 
+// CallContext performs the JSON-RPC call of the given method, such as the tracing methods the Ethereum client
+// does not wrap, and decodes its result into the result argument.
+func (c *Client) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	return c.GetRpcClient().CallContext(ctx, result, method, args...)
+}
+
+// BatchCallContext sends the calls of the batch in the single JSON-RPC batch request.
+func (c *Client) BatchCallContext(ctx context.Context, batch []rpc.BatchElem) error {
+	return c.GetRpcClient().BatchCallContext(ctx, batch)
+}
+
+// GetStats retrieves the latency, error rate, load and health of the client endpoint.
+func (c *Client) GetStats() *Stats {
+	return c.stats
+}
+
+// IsHealthy reports whether the client endpoint is healthy, that is not ejected from the pool.
+func (c *Client) IsHealthy() bool {
+	return c.stats.IsHealthy()
+}
+
+// track executes the request, applying the rate limit and recording its outcome in the client stats,
+// unless the HTTP transport already does so for every request.
+func (c *Client) track(ctx context.Context, fn func() error) error {
+	if c.tracked {
+		return fn()
+	}
+
+	if c.limiter != nil {
+		if err := c.limiter.Wait(ctx); err != nil {
+			return err
+		}
+	}
+
+	start := time.Now()
+	c.stats.begin()
+	err := fn()
+
+	if c.stats.end(time.Since(start), err) {
+		zap.L().Warn(
+			"ejected unhealthy client endpoint",
+			zap.Error(err),
+			zap.String("group", c.GetGroup()),
+			zap.String("type", c.GetType()),
+		)
+	}
+
+	return err
+}
+
 // Close gracefully closes the Ethereum client connection.
 func (c *Client) Close() {
 	c.Client.Close()
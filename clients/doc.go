@@ -5,6 +5,16 @@
 // on various criteria, such as group and type, in a round-robin fashion. It also provides
 // functionality to close all clients in the pool.
 //
+// The pool tracks the latency and error rate of every endpoint, ejects the unhealthy ones until
+// the health checks find them recovered, and applies the per-endpoint rate limits. Clients are
+// picked round robin or by the lowest load or latency, and the idempotent calls are retried on
+// the other nodes, including the ones of the failover group.
+//
+// The Batcher coalesces the concurrent reads into the JSON-RPC batch requests, and the Multicaller
+// aggregates the concurrent contract calls through Multicall3, or through the deployless multicall
+// where Multicall3 is not deployed, so the storage slots, token properties and binding calls take
+// a single round trip.
+//
 // Additionally, the package provides a Client structure that wraps the Ethereum client
 // with additional context and options. This structure offers methods to retrieve various
 // details about the client, such as its network ID, group, type, and endpoint.
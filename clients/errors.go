@@ -13,3 +13,6 @@ var ErrOptionsNotSet = errors.New("configuration options not set")
 
 // ErrNodesNotSet is returned when the configuration nodes are not set.
 var ErrNodesNotSet = errors.New("configuration nodes not set")
+
+// ErrClientNotFound is returned when the pool has no client of the requested group and type.
+var ErrClientNotFound = errors.New("client not found")
@@ -0,0 +1,217 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"go.uber.org/zap"
+)
+
+// statsSmoothing is the weight of the latest request in the moving averages of the latency and error rate.
+const statsSmoothing = 0.2
+
+// JSON-RPC error code returned by the providers when the request limit is exceeded.
+const limitExceededCode = -32005
+
+// Stats tracks the latency, error rate and load of the client endpoint, and whether the endpoint is healthy.
+// Endpoints whose error rate reaches the limit are ejected until the health check finds them recovered.
+type Stats struct {
+	mu           sync.RWMutex
+	inflight     atomic.Int64
+	requests     uint64
+	failures     uint64
+	samples      int
+	errorRate    float64
+	latency      time.Duration
+	healthy      bool
+	ejectedAt    time.Time
+	maxErrorRate float64
+	minRequests  int
+}
+
+// newStats creates the stats of the healthy endpoint, ejected once the error rate reaches the given limit
+// over at least the given number of requests.
+func newStats(maxErrorRate float64, minRequests int) *Stats {
+	return &Stats{
+		healthy:      true,
+		maxErrorRate: maxErrorRate,
+		minRequests:  minRequests,
+	}
+}
+
+// GetRequests returns the number of the requests made to the endpoint.
+func (s *Stats) GetRequests() uint64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.requests
+}
+
+// GetFailures returns the number of the requests to the endpoint that failed.
+func (s *Stats) GetFailures() uint64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.failures
+}
+
+// GetErrorRate returns the moving average of the failed requests, between zero and one.
+func (s *Stats) GetErrorRate() float64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.errorRate
+}
+
+// GetLatency returns the moving average of the latency of the successful requests, zero until one succeeds,
+// meaning the latency is not known yet.
+func (s *Stats) GetLatency() time.Duration {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.latency
+}
+
+// GetInflight returns the number of the requests to the endpoint in progress.
+func (s *Stats) GetInflight() int64 {
+	return s.inflight.Load()
+}
+
+// IsHealthy reports whether the endpoint is healthy, that is not ejected.
+func (s *Stats) IsHealthy() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.healthy
+}
+
+// GetEjectedAt returns the time the endpoint was last ejected, or failed the health check while ejected.
+func (s *Stats) GetEjectedAt() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.ejectedAt
+}
+
+// begin records the start of the request.
+func (s *Stats) begin() {
+	s.inflight.Add(1)
+}
+
+// end records the outcome of the request and reports whether it ejected the endpoint. Requests canceled by
+// the caller say nothing about the endpoint and are not recorded.
+func (s *Stats) end(latency time.Duration, err error) bool {
+	s.inflight.Add(-1)
+
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests++
+	s.samples++
+
+	if err != nil {
+		s.failures++
+		s.errorRate += statsSmoothing * (1 - s.errorRate)
+
+		if !s.healthy {
+			s.ejectedAt = time.Now()
+			return false
+		}
+
+		if s.samples >= s.minRequests && s.errorRate >= s.maxErrorRate {
+			s.healthy = false
+			s.ejectedAt = time.Now()
+			return true
+		}
+		return false
+	}
+
+	s.errorRate -= statsSmoothing * s.errorRate
+	if s.latency == 0 {
+		s.latency = latency
+	} else {
+		s.latency += time.Duration(statsSmoothing * float64(latency-s.latency))
+	}
+
+	return false
+}
+
+// readmit marks the ejected endpoint healthy again, starting its error rate afresh.
+func (s *Stats) readmit() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.healthy = true
+	s.samples = 0
+	s.errorRate = 0
+}
+
+// IsRetryable reports whether the failed call may succeed on another endpoint, as it failed because of the
+// endpoint rather than the call itself: network failures, timeouts, rate limits and server errors.
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, rpc.ErrClientQuit) {
+		return true
+	}
+
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == http.StatusTooManyRequests || httpErr.StatusCode >= http.StatusInternalServerError
+	}
+
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		return rpcErr.ErrorCode() == limitExceededCode
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// trackingTransport applies the rate limit of the HTTP endpoint and records the outcome of every request
+// in the stats of the client.
+type trackingTransport struct {
+	client *Client
+	base   http.RoundTripper
+}
+
+// RoundTrip waits for the rate limit and executes the request, recording its latency and failure. Responses
+// with the rate limit or server error status are recorded as failures.
+func (t *trackingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.client.limiter != nil {
+		if err := t.client.limiter.Wait(req.Context()); err != nil {
+			return nil, err
+		}
+	}
+
+	start := time.Now()
+	t.client.stats.begin()
+
+	resp, err := t.base.RoundTrip(req)
+
+	failure := err
+	if err == nil && (resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError) {
+		failure = fmt.Errorf("unexpected http status: %s", resp.Status)
+	}
+
+	if t.client.stats.end(time.Since(start), failure) {
+		zap.L().Warn(
+			"ejected unhealthy client endpoint",
+			zap.Error(failure),
+			zap.String("group", t.client.GetGroup()),
+			zap.String("type", t.client.GetType()),
+		)
+	}
+
+	return resp, err
+}
@@ -0,0 +1,258 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockNode is the JSON-RPC endpoint serving the network ID and the block number, which can be made to fail.
+type mockNode struct {
+	*httptest.Server
+	failing  atomic.Bool
+	requests atomic.Int64
+}
+
+func newMockNode(t *testing.T) *mockNode {
+	node := &mockNode{}
+	node.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		node.requests.Add(1)
+		if node.failing.Load() {
+			http.Error(w, "service unavailable", http.StatusServiceUnavailable)
+			return
+		}
+
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		result := "0x10"
+		if req.Method == "net_version" {
+			result = "1"
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": result})
+	}))
+	t.Cleanup(node.Close)
+	return node
+}
+
+func newTestPool(t *testing.T, opts *Options) *ClientPool {
+	opts.HealthCheckInterval = -1
+	pool, err := NewClientPool(context.Background(), opts)
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+	return pool
+}
+
+func blockNumber(ctx context.Context, client *Client) error {
+	_, err := client.BlockNumber(ctx)
+	return err
+}
+
+func TestClientPoolRoundRobin(t *testing.T) {
+	node := newMockNode(t)
+	pool := newTestPool(t, &Options{
+		Nodes: []Node{{Group: "eth", Type: "full", Endpoint: node.URL, ConcurrentClients: 3, NetworkId: 1}},
+	})
+
+	picked := make([]*Client, 6)
+	for i := range picked {
+		picked[i] = pool.GetClient("eth", "full")
+	}
+
+	assert.NotSame(t, picked[0], picked[1])
+	assert.NotSame(t, picked[1], picked[2])
+	assert.NotSame(t, picked[0], picked[2])
+	assert.Equal(t, picked[:3], picked[3:])
+
+	assert.Nil(t, pool.GetClient("eth", "missing"))
+	assert.Same(t, picked[0].GetStats(), picked[1].GetStats())
+}
+
+func TestClientPoolFailover(t *testing.T) {
+	primary, failover := newMockNode(t), newMockNode(t)
+	pool := newTestPool(t, &Options{
+		Nodes: []Node{
+			{Group: "eth", Type: "full", FailoverGroup: "eth", FailoverType: "archive", Endpoint: primary.URL, ConcurrentClients: 1, NetworkId: 1},
+			{Group: "eth", Type: "archive", FailoverGroup: "eth", FailoverType: "full", Endpoint: failover.URL, ConcurrentClients: 1, NetworkId: 1},
+		},
+		MaxErrorRate:     0.3,
+		MinRequests:      2,
+		EjectionDuration: time.Millisecond,
+		MaxRetries:       1,
+	})
+	ctx := context.Background()
+	client := pool.GetClient("eth", "full")
+
+	// Calls failed because of the endpoint are retried on the failover node.
+	primary.failing.Store(true)
+	for i := 0; i < 2; i++ {
+		require.NoError(t, pool.Do(ctx, "eth", "full", blockNumber))
+	}
+	assert.Equal(t, uint64(2), client.GetStats().GetFailures())
+	assert.False(t, client.IsHealthy())
+
+	// Ejected node is skipped while the healthy failover node is available.
+	requests := primary.requests.Load()
+	require.NoError(t, pool.Do(ctx, "eth", "full", blockNumber))
+	assert.Equal(t, requests, primary.requests.Load())
+
+	// Failed health check keeps the node ejected, the successful one re-admits it.
+	time.Sleep(2 * time.Millisecond)
+	pool.CheckHealth(ctx)
+	assert.False(t, client.IsHealthy())
+
+	primary.failing.Store(false)
+	time.Sleep(2 * time.Millisecond)
+	pool.CheckHealth(ctx)
+	assert.True(t, client.IsHealthy())
+	assert.Zero(t, client.GetStats().GetErrorRate())
+	assert.Same(t, client, pool.GetClient("eth", "full"))
+
+	// Calls failed for their own reasons are not retried.
+	calls := 0
+	err := pool.Do(ctx, "eth", "full", func(ctx context.Context, client *Client) error {
+		calls++
+		return errors.New("execution reverted")
+	})
+	assert.EqualError(t, err, "execution reverted")
+	assert.Equal(t, 1, calls)
+
+	err = pool.Do(ctx, "eth", "missing", blockNumber)
+	assert.ErrorIs(t, err, ErrClientNotFound)
+}
+
+func TestClientPoolRetriesEndpoints(t *testing.T) {
+	primary, failover := newMockNode(t), newMockNode(t)
+	pool := newTestPool(t, &Options{
+		Nodes: []Node{
+			{Group: "eth", Type: "full", FailoverGroup: "eth", FailoverType: "archive", Endpoint: primary.URL, ConcurrentClients: 3, NetworkId: 1},
+			{Group: "eth", Type: "archive", Endpoint: failover.URL, ConcurrentClients: 1, NetworkId: 1},
+		},
+		MaxErrorRate: 1,
+		MinRequests:  100,
+		MaxRetries:   2,
+	})
+
+	// Clients sharing the failed endpoint are skipped, the retry goes to the failover endpoint.
+	primary.failing.Store(true)
+	primaryRequests, failoverRequests := primary.requests.Load(), failover.requests.Load()
+
+	endpoints := make([]string, 0)
+	err := pool.Do(context.Background(), "eth", "full", func(ctx context.Context, client *Client) error {
+		endpoints = append(endpoints, client.GetEndpoint())
+		return blockNumber(ctx, client)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{primary.URL, failover.URL}, endpoints)
+	assert.Equal(t, primaryRequests+1, primary.requests.Load())
+	assert.Equal(t, failoverRequests+1, failover.requests.Load())
+}
+
+func TestClientPoolSelection(t *testing.T) {
+	tests := []struct {
+		name      string
+		selection SelectionStrategy
+		prepare   func(slow, fast *Client)
+	}{
+		{
+			name:      "Least Loaded",
+			selection: LeastLoadedSelection,
+			prepare: func(slow, fast *Client) {
+				slow.GetStats().begin()
+			},
+		},
+		{
+			name:      "Lowest Latency",
+			selection: LowestLatencySelection,
+			prepare: func(slow, fast *Client) {
+				slow.GetStats().end(100*time.Millisecond, nil)
+				fast.GetStats().end(10*time.Millisecond, nil)
+			},
+		},
+		{
+			name:      "Lowest Latency Unknown",
+			selection: LowestLatencySelection,
+			prepare: func(slow, fast *Client) {
+				fast.GetStats().end(10*time.Millisecond, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first, second := newMockNode(t), newMockNode(t)
+			pool := newTestPool(t, &Options{
+				Nodes: []Node{
+					{Group: "eth", Type: "full", Endpoint: first.URL, ConcurrentClients: 1, NetworkId: 1},
+					{Group: "eth", Type: "archive", Endpoint: second.URL, ConcurrentClients: 1, NetworkId: 1},
+				},
+				Selection: tt.selection,
+			})
+
+			slow, fast := pool.GetClient("eth", "full"), pool.GetClient("eth", "archive")
+			slow.GetStats().latency, fast.GetStats().latency = 0, 0
+			tt.prepare(slow, fast)
+
+			for i := 0; i < 3; i++ {
+				assert.Same(t, fast, pool.GetClientByGroup("eth"))
+			}
+		})
+	}
+}
+
+func TestClientRateLimit(t *testing.T) {
+	node := newMockNode(t)
+	pool := newTestPool(t, &Options{
+		Nodes: []Node{{Group: "eth", Type: "full", Endpoint: node.URL, ConcurrentClients: 2, NetworkId: 1, RateLimit: 2}},
+	})
+
+	// Both clients share the limit of the endpoint, already used up by their network ID requests.
+	start := time.Now()
+	require.NoError(t, blockNumber(context.Background(), pool.GetClient("eth", "full")))
+	assert.GreaterOrEqual(t, time.Since(start), 500*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Error(t, blockNumber(ctx, pool.GetClient("eth", "full")))
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "Nil", err: nil},
+		{name: "Canceled", err: context.Canceled},
+		{name: "Deadline Exceeded", err: context.DeadlineExceeded, expected: true},
+		{name: "Unexpected EOF", err: io.ErrUnexpectedEOF, expected: true},
+		{name: "Too Many Requests", err: rpc.HTTPError{StatusCode: http.StatusTooManyRequests}, expected: true},
+		{name: "Bad Gateway", err: rpc.HTTPError{StatusCode: http.StatusBadGateway}, expected: true},
+		{name: "Unauthorized", err: rpc.HTTPError{StatusCode: http.StatusUnauthorized}},
+		{name: "Reverted", err: errors.New("execution reverted")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, IsRetryable(tt.err))
+		})
+	}
+}
@@ -0,0 +1,396 @@
+package clients
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Multicall3Address is the address Multicall3 is deployed at on most of the networks.
+var Multicall3Address = common.HexToAddress("0xcA11bde05977b3631167028862bE2a173976CA11")
+
+// DefaultMaxMulticalls is the number of the calls the aggregate is sent at without waiting for the window.
+const DefaultMaxMulticalls = 100
+
+// multicall3ABI is the aggregate3 function of Multicall3.
+const multicall3ABI = `[{"inputs":[{"components":[{"name":"target","type":"address"},{"name":"allowFailure","type":"bool"},{"name":"callData","type":"bytes"}],"name":"calls","type":"tuple[]"}],"name":"aggregate3","outputs":[{"components":[{"name":"success","type":"bool"},{"name":"returnData","type":"bytes"}],"name":"returnData","type":"tuple[]"}],"stateMutability":"payable","type":"function"}]`
+
+// deploylessMulticallCode is the init code executing the calls appended to it, each encoded as the 20 bytes
+// target, the 32 bytes length of the call data and the call data itself. It is executed by eth_call without
+// the recipient, so the calls are aggregated on the networks and at the blocks without Multicall3. For every
+// call it returns the 32 bytes success flag, the 32 bytes length of the return data and the return data itself.
+// The results are returned as the code of the created contract, so they are subject to the contract size limit
+// and the larger aggregates fail, falling back to the calls executed one by one.
+//
+//	PUSH2 0x5f PUSH1 0                                      ; code offset of the calls, memory offset of the results
+//	loop: JUMPDEST CODESIZE DUP3 LT ISZERO PUSH2 end JUMPI  ; stop once all the calls are executed
+//	PUSH1 32 DUP3 DUP3 CODECOPY DUP1 MLOAD PUSH1 96 SHR     ; target
+//	PUSH1 32 DUP4 PUSH1 20 ADD DUP4 CODECOPY DUP2 MLOAD     ; call data length
+//	DUP1 DUP5 PUSH1 52 ADD DUP5 PUSH1 64 ADD CODECOPY       ; call data copied past the result header
+//	PUSH1 0 PUSH1 0 DUP3 DUP6 PUSH1 64 ADD PUSH1 0 DUP7 GAS CALL
+//	DUP4 MSTORE RETURNDATASIZE DUP4 PUSH1 32 ADD MSTORE     ; success flag and return data length
+//	RETURNDATASIZE PUSH1 0 DUP5 PUSH1 64 ADD RETURNDATACOPY ; return data over the call data
+//	PUSH1 52 ADD DUP4 ADD SWAP3 POP POP                     ; next call
+//	RETURNDATASIZE ADD PUSH1 64 ADD PUSH2 loop JUMP         ; next result
+//	end: JUMPDEST PUSH1 0 RETURN
+var deploylessMulticallCode = common.FromHex(
+	"0x61005f60005b3882101561005b576020828239805160601c60208360140183398151808460340184604001396000600082856040016000865af183523d83602001523d6000846040013e60340183019250503d01604001610005565b6000f3",
+)
+
+// ErrCallReverted is returned for the aggregated call that reverted.
+var ErrCallReverted = errors.New("execution reverted")
+
+// MulticallOptions defines the options of the multicaller.
+type MulticallOptions struct {
+	Address  common.Address `mapstructure:"address" yaml:"address" json:"address"`    // Address of Multicall3, the canonical one when zero.
+	Window   time.Duration  `mapstructure:"window" yaml:"window" json:"window"`       // Time the calls are collected for before the aggregate is sent.
+	MaxCalls int            `mapstructure:"maxCalls" yaml:"maxCalls" json:"maxCalls"` // Number of the calls the aggregate is sent at without waiting for the window.
+}
+
+// GetAddress returns the address of Multicall3.
+func (o *MulticallOptions) GetAddress() common.Address {
+	if o == nil || o.Address == (common.Address{}) {
+		return Multicall3Address
+	}
+	return o.Address
+}
+
+// GetWindow returns the time the calls are collected for before the aggregate is sent.
+func (o *MulticallOptions) GetWindow() time.Duration {
+	if o == nil || o.Window <= 0 {
+		return DefaultBatchWindow
+	}
+	return o.Window
+}
+
+// GetMaxCalls returns the number of the calls the aggregate is sent at without waiting for the window.
+func (o *MulticallOptions) GetMaxCalls() int {
+	if o == nil || o.MaxCalls <= 0 {
+		return DefaultMaxMulticalls
+	}
+	return o.MaxCalls
+}
+
+// Multicaller coalesces the concurrent contract calls at the same block into the single call through
+// Multicall3, or through the deployless multicall where Multicall3 is not deployed. Calls depending on
+// the sender, value or gas are not aggregated, but still batched, as are the aggregates that fail as a whole.
+type Multicaller struct {
+	ctx        context.Context
+	batcher    *Batcher
+	opts       *MulticallOptions
+	abi        abi.ABI
+	mu         sync.Mutex
+	pending    map[string]*multicallBatch
+	deployedAt *big.Int // Lowest block Multicall3 is known to be deployed at.
+	latest     *bool    // Whether Multicall3 is deployed at the latest block, nil until checked.
+}
+
+// multicallBatch is the batch of the calls at the same block.
+type multicallBatch struct {
+	blockNumber *big.Int
+	calls       []*multicall
+	timer       *time.Timer
+}
+
+// multicall is the call waiting in the batch.
+type multicall struct {
+	target common.Address
+	data   []byte
+	result []byte
+	err    error
+	done   chan struct{}
+}
+
+// NewMulticaller creates the multicaller executing the aggregated calls through the batcher.
+func NewMulticaller(ctx context.Context, batcher *Batcher, opts *MulticallOptions) (*Multicaller, error) {
+	if batcher == nil {
+		return nil, errors.New("batcher is not set")
+	}
+
+	parsedABI, err := abi.JSON(strings.NewReader(multicall3ABI))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse multicall abi: %w", err)
+	}
+
+	if opts == nil {
+		opts = &MulticallOptions{}
+	}
+
+	return &Multicaller{
+		ctx:     ctx,
+		batcher: batcher,
+		opts:    opts,
+		abi:     parsedABI,
+		pending: make(map[string]*multicallBatch),
+	}, nil
+}
+
+// GetMaxCalls returns the number of the calls the aggregate is sent at without waiting for the window. Callers
+// fanning the calls out bound the concurrent calls by it, so a single aggregate is filled at a time.
+func (m *Multicaller) GetMaxCalls() int {
+	return m.opts.GetMaxCalls()
+}
+
+// CallContract executes the message call at the given block number, or at the latest block when the block
+// number is nil, aggregated with the other calls made at the same block meanwhile.
+func (m *Multicaller) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	if !aggregatable(msg) {
+		return m.batcher.CallContract(ctx, msg, blockNumber)
+	}
+
+	call := &multicall{target: *msg.To, data: msg.Data, done: make(chan struct{})}
+	key := toBlockNumArg(blockNumber)
+
+	m.mu.Lock()
+	batch, ok := m.pending[key]
+	if !ok {
+		batch = &multicallBatch{blockNumber: blockNumber}
+		m.pending[key] = batch
+		batch.timer = time.AfterFunc(m.opts.GetWindow(), func() {
+			m.flush(key, batch)
+		})
+	}
+	batch.calls = append(batch.calls, call)
+	full := len(batch.calls) >= m.opts.GetMaxCalls()
+	m.mu.Unlock()
+
+	if full {
+		go m.flush(key, batch)
+	}
+
+	select {
+	case <-call.done:
+		return call.result, call.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// flush sends the batch unless it was already sent.
+func (m *Multicaller) flush(key string, batch *multicallBatch) {
+	m.mu.Lock()
+	if m.pending[key] != batch {
+		m.mu.Unlock()
+		return
+	}
+	delete(m.pending, key)
+	batch.timer.Stop()
+	m.mu.Unlock()
+
+	m.send(batch)
+}
+
+// send executes the calls of the batch. When the aggregate fails as a whole, the calls are executed one by
+// one, still batched, so the failure of the single call does not fail the rest.
+func (m *Multicaller) send(batch *multicallBatch) {
+	defer func() {
+		for _, call := range batch.calls {
+			close(call.done)
+		}
+	}()
+
+	if len(batch.calls) > 1 {
+		if err := m.aggregate(m.ctx, batch); err == nil {
+			return
+		}
+	}
+
+	var wg sync.WaitGroup
+	for _, call := range batch.calls {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			call.result, call.err = m.batcher.CallContract(m.ctx, ethereum.CallMsg{To: &call.target, Data: call.data}, batch.blockNumber)
+			call.err = revertError(call.err)
+		}()
+	}
+	wg.Wait()
+}
+
+// aggregate executes the calls of the batch in the single call, through Multicall3 where it is deployed.
+func (m *Multicaller) aggregate(ctx context.Context, batch *multicallBatch) error {
+	deployed, err := m.isDeployed(ctx, batch.blockNumber)
+	if err != nil {
+		return err
+	}
+
+	if deployed {
+		return m.aggregate3(ctx, batch)
+	}
+	return m.aggregateDeployless(ctx, batch)
+}
+
+// aggregate3 executes the calls through Multicall3.
+func (m *Multicaller) aggregate3(ctx context.Context, batch *multicallBatch) error {
+	type call3 struct {
+		Target       common.Address
+		AllowFailure bool
+		CallData     []byte
+	}
+
+	calls := make([]call3, len(batch.calls))
+	for i, call := range batch.calls {
+		calls[i] = call3{Target: call.target, AllowFailure: true, CallData: call.data}
+	}
+
+	data, err := m.abi.Pack("aggregate3", calls)
+	if err != nil {
+		return fmt.Errorf("failed to pack aggregate3: %w", err)
+	}
+
+	address := m.opts.GetAddress()
+	output, err := m.batcher.CallContract(ctx, ethereum.CallMsg{To: &address, Data: data}, batch.blockNumber)
+	if err != nil {
+		return fmt.Errorf("failed to call aggregate3: %w", err)
+	}
+
+	unpacked, err := m.abi.Unpack("aggregate3", output)
+	if err != nil {
+		return fmt.Errorf("failed to unpack aggregate3: %w", err)
+	}
+
+	results := *abi.ConvertType(unpacked[0], new([]struct {
+		Success    bool
+		ReturnData []byte
+	})).(*[]struct {
+		Success    bool
+		ReturnData []byte
+	})
+
+	if len(results) != len(batch.calls) {
+		return fmt.Errorf("aggregate3 returned %d results for %d calls", len(results), len(batch.calls))
+	}
+
+	for i, result := range results {
+		batch.calls[i].setResult(result.Success, result.ReturnData)
+	}
+
+	return nil
+}
+
+// aggregateDeployless executes the calls through the deployless multicall.
+func (m *Multicaller) aggregateDeployless(ctx context.Context, batch *multicallBatch) error {
+	data := append([]byte{}, deploylessMulticallCode...)
+	for _, call := range batch.calls {
+		data = append(data, call.target.Bytes()...)
+		data = append(data, common.BigToHash(big.NewInt(int64(len(call.data)))).Bytes()...)
+		data = append(data, call.data...)
+	}
+
+	output, err := m.batcher.CallContract(ctx, ethereum.CallMsg{Data: data}, batch.blockNumber)
+	if err != nil {
+		return fmt.Errorf("failed to call deployless multicall: %w", err)
+	}
+
+	results := make([]struct {
+		success    bool
+		returnData []byte
+	}, len(batch.calls))
+
+	for i := range results {
+		if len(output) < 64 {
+			return fmt.Errorf("deployless multicall returned %d results for %d calls", i, len(batch.calls))
+		}
+
+		length := binary.BigEndian.Uint64(output[56:64])
+		if uint64(len(output)-64) < length {
+			return errors.New("deployless multicall returned truncated results")
+		}
+
+		results[i].success = output[31] == 1
+		results[i].returnData = output[64 : 64+length]
+		output = output[64+length:]
+	}
+
+	for i, result := range results {
+		batch.calls[i].setResult(result.success, result.returnData)
+	}
+
+	return nil
+}
+
+// isDeployed reports whether Multicall3 is deployed at the given block. Contracts stay deployed, so the
+// lowest block Multicall3 is found at is remembered, as is whether it is deployed at the latest block.
+func (m *Multicaller) isDeployed(ctx context.Context, blockNumber *big.Int) (bool, error) {
+	historical := blockNumber != nil && blockNumber.Sign() >= 0
+
+	m.mu.Lock()
+	if !historical && m.latest != nil {
+		deployed := *m.latest
+		m.mu.Unlock()
+		return deployed, nil
+	}
+
+	if historical && m.deployedAt != nil && blockNumber.Cmp(m.deployedAt) >= 0 {
+		m.mu.Unlock()
+		return true, nil
+	}
+	m.mu.Unlock()
+
+	code, err := m.batcher.CodeAt(ctx, m.opts.GetAddress(), blockNumber)
+	if err != nil {
+		return false, fmt.Errorf("failed to get multicall code: %w", err)
+	}
+	deployed := len(code) > 0
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !historical {
+		m.latest = &deployed
+	} else if deployed && (m.deployedAt == nil || blockNumber.Cmp(m.deployedAt) < 0) {
+		m.deployedAt = new(big.Int).Set(blockNumber)
+	}
+
+	return deployed, nil
+}
+
+// setResult sets the outcome of the aggregated call.
+func (c *multicall) setResult(success bool, returnData []byte) {
+	if success {
+		c.result = returnData
+		return
+	}
+
+	if reason, err := abi.UnpackRevert(returnData); err == nil {
+		c.err = fmt.Errorf("%w: %s", ErrCallReverted, reason)
+		return
+	}
+	c.err = ErrCallReverted
+}
+
+// revertError returns ErrCallReverted, along with the revert reason when known, for the call reverted by the node,
+// so the calls executed one by one fail the same way as the aggregated ones. Other errors are returned as they are.
+func revertError(err error) error {
+	var rpcErr rpc.Error
+	if !errors.As(err, &rpcErr) || !strings.HasPrefix(rpcErr.Error(), ErrCallReverted.Error()) {
+		return err
+	}
+
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		if data, ok := dataErr.ErrorData().(string); ok {
+			if reason, err := abi.UnpackRevert(common.FromHex(data)); err == nil {
+				return fmt.Errorf("%w: %s", ErrCallReverted, reason)
+			}
+		}
+	}
+
+	return ErrCallReverted
+}
+
+// aggregatable reports whether the call can be aggregated, that is it does not depend on the sender, value or gas.
+func aggregatable(msg ethereum.CallMsg) bool {
+	return msg.To != nil && msg.From == (common.Address{}) && (msg.Value == nil || msg.Value.Sign() == 0) &&
+		msg.Gas == 0 && msg.GasPrice == nil && msg.GasFeeCap == nil && msg.GasTipCap == nil && msg.AccessList == nil
+}
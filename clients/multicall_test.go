@@ -0,0 +1,272 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vm/runtime"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	// echoAddress returns the call data.
+	echoAddress = common.HexToAddress("0x1000000000000000000000000000000000000001")
+
+	// revertAddress reverts with the call data.
+	revertAddress = common.HexToAddress("0x1000000000000000000000000000000000000002")
+)
+
+// mockEVM executes the calls in the in-memory EVM, serving aggregate3 of Multicall3 when it is deployed.
+type mockEVM struct {
+	t        *testing.T
+	mu       sync.Mutex
+	state    *state.StateDB
+	abi      abi.ABI
+	calls    int
+	getCodes int
+}
+
+func newMockEVM(t *testing.T, multicall3 bool) *mockEVM {
+	statedb, err := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	require.NoError(t, err)
+
+	statedb.SetCode(echoAddress, common.FromHex("0x366000600037366000f3"))
+	statedb.SetCode(revertAddress, common.FromHex("0x366000600037366000fd"))
+	if multicall3 {
+		// Calls to Multicall3 are served by the mock, the code only marks it deployed.
+		statedb.SetCode(Multicall3Address, []byte{0x00})
+	}
+
+	parsedABI, err := abi.JSON(strings.NewReader(multicall3ABI))
+	require.NoError(t, err)
+
+	return &mockEVM{t: t, state: statedb, abi: parsedABI}
+}
+
+func (e *mockEVM) BatchCallContext(ctx context.Context, batch []rpc.BatchElem) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for i := range batch {
+		var output []byte
+		var err error
+
+		switch batch[i].Method {
+		case "eth_getCode":
+			e.getCodes++
+			output = e.state.GetCode(batch[i].Args[0].(common.Address))
+		case "eth_call":
+			e.calls++
+			arg := batch[i].Args[0].(map[string]interface{})
+			to, _ := arg["to"].(*common.Address)
+			input, _ := arg["input"].(hexutil.Bytes)
+			output, err = e.call(to, input)
+			if errors.Is(err, vm.ErrExecutionReverted) {
+				// Nodes report reverts as the JSON-RPC errors carrying the revert data.
+				err = &revertRPCError{data: hexutil.Encode(output)}
+			}
+		default:
+			err = errors.New("method not found")
+		}
+
+		if err != nil {
+			batch[i].Error = err
+			continue
+		}
+
+		raw, err := json.Marshal(hexutil.Bytes(output))
+		require.NoError(e.t, err)
+		*batch[i].Result.(*json.RawMessage) = raw
+	}
+
+	return nil
+}
+
+// revertRPCError is the JSON-RPC error of the reverted call, as returned by the nodes.
+type revertRPCError struct {
+	data string
+}
+
+func (e *revertRPCError) Error() string          { return "execution reverted" }
+func (e *revertRPCError) ErrorCode() int         { return 3 }
+func (e *revertRPCError) ErrorData() interface{} { return e.data }
+
+func (e *mockEVM) call(to *common.Address, input []byte) ([]byte, error) {
+	cfg := &runtime.Config{State: e.state}
+
+	if to == nil {
+		output, _, _, err := runtime.Create(input, cfg)
+		return output, err
+	}
+
+	if *to != Multicall3Address {
+		output, _, err := runtime.Call(*to, input, cfg)
+		return output, err
+	}
+
+	args, err := e.abi.Methods["aggregate3"].Inputs.Unpack(input[4:])
+	if err != nil {
+		return nil, err
+	}
+
+	calls := *abi.ConvertType(args[0], new([]struct {
+		Target       common.Address
+		AllowFailure bool
+		CallData     []byte
+	})).(*[]struct {
+		Target       common.Address
+		AllowFailure bool
+		CallData     []byte
+	})
+
+	type result struct {
+		Success    bool
+		ReturnData []byte
+	}
+
+	results := make([]result, len(calls))
+	for i, call := range calls {
+		output, _, err := runtime.Call(call.Target, call.CallData, cfg)
+		results[i] = result{Success: err == nil, ReturnData: output}
+	}
+
+	return e.abi.Methods["aggregate3"].Outputs.Pack(results)
+}
+
+func TestMulticaller(t *testing.T) {
+	revertData, err := (abi.Arguments{{Type: abi.Type{T: abi.StringTy}}}).Pack("boom")
+	require.NoError(t, err)
+	revertData = append(common.FromHex("0x08c379a0"), revertData...)
+
+	sender := common.HexToAddress("0x2000000000000000000000000000000000000001")
+
+	tests := []struct {
+		name       string
+		multicall3 bool
+		msgs       []ethereum.CallMsg
+		expected   [][]byte
+		errs       []string
+		calls      int
+	}{
+		{
+			name: "Deployless",
+			msgs: []ethereum.CallMsg{
+				{To: &echoAddress, Data: []byte{0x01}},
+				{To: &echoAddress, Data: common.FromHex("0x0203040506070809")},
+				{To: &echoAddress},
+			},
+			expected: [][]byte{{0x01}, common.FromHex("0x0203040506070809"), {}},
+			calls:    1,
+		},
+		{
+			name: "Deployless Revert",
+			msgs: []ethereum.CallMsg{
+				{To: &echoAddress, Data: []byte{0x01}},
+				{To: &revertAddress, Data: revertData},
+			},
+			expected: [][]byte{{0x01}, nil},
+			errs:     []string{"", "execution reverted: boom"},
+			calls:    1,
+		},
+		{
+			name:       "Multicall3",
+			multicall3: true,
+			msgs: []ethereum.CallMsg{
+				{To: &echoAddress, Data: []byte{0x01}},
+				{To: &echoAddress, Data: common.FromHex("0x0203")},
+				{To: &revertAddress, Data: []byte{0x04}},
+			},
+			expected: [][]byte{{0x01}, common.FromHex("0x0203"), nil},
+			errs:     []string{"", "", "execution reverted"},
+			calls:    1,
+		},
+		{
+			name: "Single Revert",
+			msgs: []ethereum.CallMsg{
+				{To: &revertAddress, Data: revertData},
+			},
+			expected: [][]byte{nil},
+			errs:     []string{"execution reverted: boom"},
+			calls:    1,
+		},
+		{
+			name: "Not Aggregatable",
+			msgs: []ethereum.CallMsg{
+				{To: &echoAddress, Data: []byte{0x01}},
+				{From: sender, To: &echoAddress, Data: []byte{0x02}},
+			},
+			expected: [][]byte{{0x01}, {0x02}},
+			calls:    2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evm := newMockEVM(t, tt.multicall3)
+
+			batcher, err := NewBatcher(context.Background(), evm, nil)
+			require.NoError(t, err)
+
+			multicaller, err := NewMulticaller(context.Background(), batcher, nil)
+			require.NoError(t, err)
+
+			var wg sync.WaitGroup
+			results := make([][]byte, len(tt.msgs))
+			errs := make([]error, len(tt.msgs))
+
+			for i, msg := range tt.msgs {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					results[i], errs[i] = multicaller.CallContract(context.Background(), msg, big.NewInt(0))
+				}()
+			}
+			wg.Wait()
+
+			for i := range tt.msgs {
+				if tt.errs != nil && tt.errs[i] != "" {
+					assert.EqualError(t, errs[i], tt.errs[i])
+					continue
+				}
+				require.NoError(t, errs[i])
+				assert.Equal(t, hexutil.Bytes(tt.expected[i]), hexutil.Bytes(results[i]))
+			}
+
+			assert.Equal(t, tt.calls, evm.calls)
+		})
+	}
+}
+
+func TestMulticallerDeployedCache(t *testing.T) {
+	evm := newMockEVM(t, true)
+
+	batcher, err := NewBatcher(context.Background(), evm, nil)
+	require.NoError(t, err)
+
+	multicaller, err := NewMulticaller(context.Background(), batcher, nil)
+	require.NoError(t, err)
+
+	blocks := []*big.Int{nil, nil, big.NewInt(10), big.NewInt(20), big.NewInt(5)}
+	for _, block := range blocks {
+		deployed, err := multicaller.isDeployed(context.Background(), block)
+		require.NoError(t, err)
+		assert.True(t, deployed)
+	}
+
+	// Latest block and block 10 are checked, block 20 is known from block 10, block 5 is lower.
+	assert.Equal(t, 3, evm.getCodes)
+}
@@ -1,9 +1,79 @@
 package clients
 
+import (
+	"time"
+
+	"github.com/unpackdev/solgo/cache"
+)
+
+// SelectionStrategy determines how the client is picked among the healthy clients of the group and type.
+type SelectionStrategy string
+
+const (
+	// RoundRobinSelection picks the clients in turns.
+	RoundRobinSelection SelectionStrategy = "round_robin"
+
+	// LeastLoadedSelection picks the client with the fewest requests in progress.
+	LeastLoadedSelection SelectionStrategy = "least_loaded"
+
+	// LowestLatencySelection picks the client with the lowest average latency.
+	LowestLatencySelection SelectionStrategy = "lowest_latency"
+)
+
+const (
+	// DefaultHealthCheckInterval is the interval the clients are health checked at.
+	DefaultHealthCheckInterval = 30 * time.Second
+
+	// DefaultHealthCheckTimeout is the timeout of the single health check.
+	DefaultHealthCheckTimeout = 5 * time.Second
+
+	// DefaultEjectionDuration is the minimum duration the unhealthy client is ejected for.
+	DefaultEjectionDuration = 30 * time.Second
+
+	// DefaultMaxErrorRate is the error rate the client is ejected at.
+	DefaultMaxErrorRate = 0.5
+
+	// DefaultMinRequests is the number of the requests the error rate is measured over before the client is ejected.
+	DefaultMinRequests = 10
+
+	// DefaultMaxRetries is the number of the times the failed call is retried on another client.
+	DefaultMaxRetries = 2
+)
+
 // Options represents the configuration options for network nodes.
 type Options struct {
 	// Nodes is a slice of Node representing the network nodes.
 	Nodes []Node `mapstructure:"nodes" yaml:"nodes" json:"nodes"`
+
+	// Selection is the strategy the clients are picked with, the round robin when empty.
+	Selection SelectionStrategy `mapstructure:"selection" yaml:"selection" json:"selection"`
+
+	// HealthCheckInterval is the interval the clients are health checked at. Negative interval disables the health checks.
+	HealthCheckInterval time.Duration `mapstructure:"healthCheckInterval" yaml:"healthCheckInterval" json:"healthCheckInterval"`
+
+	// HealthCheckTimeout is the timeout of the single health check.
+	HealthCheckTimeout time.Duration `mapstructure:"healthCheckTimeout" yaml:"healthCheckTimeout" json:"healthCheckTimeout"`
+
+	// EjectionDuration is the minimum duration the unhealthy client is ejected for before it is health checked again.
+	EjectionDuration time.Duration `mapstructure:"ejectionDuration" yaml:"ejectionDuration" json:"ejectionDuration"`
+
+	// MaxErrorRate is the moving average of the failed requests, between zero and one, the client is ejected at.
+	MaxErrorRate float64 `mapstructure:"maxErrorRate" yaml:"maxErrorRate" json:"maxErrorRate"`
+
+	// MinRequests is the number of the requests the error rate is measured over before the client is ejected.
+	MinRequests int `mapstructure:"minRequests" yaml:"minRequests" json:"minRequests"`
+
+	// MaxRetries is the number of the times the failed call is retried on another client. Negative disables the retries.
+	MaxRetries int `mapstructure:"maxRetries" yaml:"maxRetries" json:"maxRetries"`
+
+	// Batch represents the options of the batchers coalescing the reads of the groups into the batch requests.
+	Batch *BatchOptions `mapstructure:"batch" yaml:"batch" json:"batch"`
+
+	// Multicall represents the options of the multicallers aggregating the contract calls of the groups.
+	Multicall *MulticallOptions `mapstructure:"multicall" yaml:"multicall" json:"multicall"`
+
+	// Cache represents the options of the cache of the immutable reads, such as the code and storage at the block.
+	Cache *cache.Options `mapstructure:"cache" yaml:"cache" json:"cache"`
 }
 
 // GetNodes returns the slice of network nodes from the Options.
@@ -11,6 +81,62 @@ func (o *Options) GetNodes() []Node {
 	return o.Nodes
 }
 
+// GetSelection returns the strategy the clients are picked with.
+func (o *Options) GetSelection() SelectionStrategy {
+	if o.Selection == "" {
+		return RoundRobinSelection
+	}
+	return o.Selection
+}
+
+// GetHealthCheckInterval returns the interval the clients are health checked at, non-positive when disabled.
+func (o *Options) GetHealthCheckInterval() time.Duration {
+	if o.HealthCheckInterval == 0 {
+		return DefaultHealthCheckInterval
+	}
+	return o.HealthCheckInterval
+}
+
+// GetHealthCheckTimeout returns the timeout of the single health check.
+func (o *Options) GetHealthCheckTimeout() time.Duration {
+	if o.HealthCheckTimeout <= 0 {
+		return DefaultHealthCheckTimeout
+	}
+	return o.HealthCheckTimeout
+}
+
+// GetEjectionDuration returns the minimum duration the unhealthy client is ejected for.
+func (o *Options) GetEjectionDuration() time.Duration {
+	if o.EjectionDuration <= 0 {
+		return DefaultEjectionDuration
+	}
+	return o.EjectionDuration
+}
+
+// GetMaxErrorRate returns the error rate the client is ejected at.
+func (o *Options) GetMaxErrorRate() float64 {
+	if o.MaxErrorRate <= 0 {
+		return DefaultMaxErrorRate
+	}
+	return o.MaxErrorRate
+}
+
+// GetMinRequests returns the number of the requests the error rate is measured over.
+func (o *Options) GetMinRequests() int {
+	if o.MinRequests <= 0 {
+		return DefaultMinRequests
+	}
+	return o.MinRequests
+}
+
+// GetMaxRetries returns the number of the times the failed call is retried on another client.
+func (o *Options) GetMaxRetries() int {
+	if o.MaxRetries == 0 {
+		return DefaultMaxRetries
+	}
+	return max(o.MaxRetries, 0)
+}
+
 // Node represents the configuration and details of a network node.
 type Node struct {
 	// Group represents the group name of the node.
@@ -33,6 +159,9 @@ type Node struct {
 
 	// ConcurrentClients represents the number of concurrent clients for the node.
 	ConcurrentClients int `mapstructure:"concurrentClients" yaml:"concurrentClients" json:"concurrentClients"`
+
+	// RateLimit represents the number of the requests per second the node accepts, shared by its concurrent clients and unlimited when zero.
+	RateLimit int `mapstructure:"rateLimit" yaml:"rateLimit" json:"rateLimit"`
 }
 
 // GetGroup returns the group name of the node.
@@ -69,3 +198,8 @@ func (n *Node) GetFailoverType() string {
 func (n *Node) GetConcurrentClientsNumber() int {
 	return n.ConcurrentClients
 }
+
+// GetRateLimit returns the number of the requests per second the node accepts.
+func (n *Node) GetRateLimit() int {
+	return n.RateLimit
+}
@@ -3,41 +3,89 @@ package clients
 import (
 	"context"
 	"errors"
+	"fmt"
+	"math"
 	"math/big"
+	"sort"
 	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/unpackdev/solgo/cache"
+	"github.com/unpackdev/solgo/utils"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
 )
 
+// endpoint holds the rate limiter and stats shared by the clients connected to the same endpoint.
+type endpoint struct {
+	limiter *utils.RateLimiter
+	stats   *Stats
+}
+
 // ClientPool manages a pool of Ethereum clients for different networks and types.
 // It provides methods to retrieve clients based on various criteria and to close all clients in the pool.
+// Clients are picked among the healthy ones, while the unhealthy endpoints are ejected until the periodic
+// health check finds them recovered.
 type ClientPool struct {
-	ctx           context.Context
-	opts          *Options
-	clients       map[string][]*Client
-	next          uint32
-	groupTypeNext map[string]uint32
+	ctx       context.Context
+	cancel    context.CancelFunc
+	opts      *Options
+	mu        sync.RWMutex
+	clients   map[string][]*Client
+	endpoints map[string]*endpoint
+	counters  map[string]*atomic.Uint32
+	batchers  map[string]*Batcher
+	callers   map[string]*Multicaller
+	cache     cache.Cache
+}
+
+// groupCaller sends the batch requests with the clients of the pool group.
+type groupCaller struct {
+	pool  *ClientPool
+	group string
+}
+
+// BatchCallContext sends the batch request with the client of the group.
+func (g *groupCaller) BatchCallContext(ctx context.Context, batch []rpc.BatchElem) error {
+	client := g.pool.GetClientByGroup(g.group)
+	if client == nil {
+		return fmt.Errorf("%w: %s", ErrClientNotFound, g.group)
+	}
+	return client.BatchCallContext(ctx, batch)
 }
 
 // Len returns the number of clients in the pool.
 func (c *ClientPool) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 	return len(c.clients)
 }
 
-// GetClients returns all clients in the pool.
+// GetClients returns all clients in the pool. The map is a copy, safe to iterate while the pool is updated.
 func (c *ClientPool) GetClients() map[string][]*Client {
-	return c.clients
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	toReturn := make(map[string][]*Client, len(c.clients))
+	for key, clients := range c.clients {
+		toReturn[key] = append([]*Client(nil), clients...)
+	}
+	return toReturn
 }
 
-// GetClient retrieves a client based on the group and type in a round-robin fashion.
+// GetClient retrieves a healthy client based on the group and type, picked by the selection strategy of the
+// pool. When all the clients are unhealthy, one of them is returned regardless.
 func (c *ClientPool) GetClient(group, typ string) *Client {
 	key := group + "_" + typ
-	current := c.groupTypeNext[key]
-	n := atomic.AddUint32(&current, 1)
-	return c.clients[key][(int(n)-1)%len(c.clients[key])]
+
+	c.mu.RLock()
+	clients := c.clients[key]
+	c.mu.RUnlock()
+
+	return c.pick(key, clients)
 }
 
 // GetClientByGroupAndType retrieves a client based on the group and type in a round-robin fashion.
@@ -46,32 +94,276 @@ func (c *ClientPool) GetClientByGroupAndType(group, typ string) *Client {
 	return c.GetClient(group, typ)
 }
 
-// GetClientByGroup retrieves a client based on the group in a round-robin fashion.
+// GetClientByGroup retrieves a client based on the group, picked by the selection strategy of the pool.
 // It aggregates all clients within the specified group and returns one of them.
 func (c *ClientPool) GetClientByGroup(group string) *Client {
 	var allClientsInGroup []*Client
 
-	// Aggregate all clients within the specified group
-	for key, clients := range c.clients {
+	c.mu.RLock()
+	keys := make([]string, 0, len(c.clients))
+	for key := range c.clients {
 		if strings.HasPrefix(key, group+"_") {
-			allClientsInGroup = append(allClientsInGroup, clients...)
+			keys = append(keys, key)
+		}
+	}
+
+	// Aggregate all clients within the specified group, in the same order every time
+	sort.Strings(keys)
+	for _, key := range keys {
+		allClientsInGroup = append(allClientsInGroup, c.clients[key]...)
+	}
+	c.mu.RUnlock()
+
+	return c.pick(group, allClientsInGroup)
+}
+
+// GetBatcher retrieves the batcher coalescing the concurrent reads made with the clients of the group into
+// the batch requests. The batcher is created on the first use and shared afterwards.
+func (c *ClientPool) GetBatcher(group string) *Batcher {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if batcher, ok := c.batchers[group]; ok {
+		return batcher
+	}
+
+	batcher, _ := NewBatcher(c.ctx, &groupCaller{pool: c, group: group}, c.opts.Batch)
+	batcher.cache = cache.NewNamespace(c.cache, cache.Key("clients", group), c.opts.Cache.GetTTL())
+	c.batchers[group] = batcher
+	return batcher
+}
+
+// GetCache returns the cache of the pool, nil when the cache is not configured. It may be shared with the other
+// components caching their data, such as the providers.
+func (c *ClientPool) GetCache() cache.Cache {
+	return c.cache
+}
+
+// GetMulticaller retrieves the multicaller aggregating the concurrent contract calls made with the clients
+// of the group. The multicaller is created on the first use and shared afterwards.
+func (c *ClientPool) GetMulticaller(group string) *Multicaller {
+	batcher := c.GetBatcher(group)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if caller, ok := c.callers[group]; ok {
+		return caller
+	}
+
+	caller, _ := NewMulticaller(c.ctx, batcher, c.opts.Multicall)
+	c.callers[group] = caller
+	return caller
+}
+
+// Do calls the function with the client of the group and type, retrying the calls failed because of the
+// endpoint on the other clients, including the ones of the failover group and type, up to the maximum
+// number of retries. Each endpoint is attempted once, whichever of its clients is picked. The function must
+// therefore be idempotent. The error of the last attempt is returned.
+func (c *ClientPool) Do(ctx context.Context, group, typ string, fn func(ctx context.Context, client *Client) error) error {
+	tried := make(map[*Stats]bool)
+	var lastErr error
+
+	for attempt := 0; attempt <= c.opts.GetMaxRetries(); attempt++ {
+		client := c.next(group, typ, tried)
+		if client == nil {
+			break
+		}
+		tried[client.GetStats()] = true
+
+		err := client.track(ctx, func() error {
+			return fn(ctx, client)
+		})
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+
+		if ctx.Err() != nil || !IsRetryable(err) {
+			return err
+		}
+
+		zap.L().Debug(
+			"retrying failed call on another client",
+			zap.Error(err),
+			zap.String("group", client.GetGroup()),
+			zap.String("type", client.GetType()),
+			zap.Int("attempt", attempt+1),
+		)
+	}
+
+	if lastErr == nil {
+		return fmt.Errorf("%w: %s_%s", ErrClientNotFound, group, typ)
+	}
+
+	return lastErr
+}
+
+// CheckHealth probes every endpoint of the pool for the latest block number. Failed probes count towards
+// the ejection of the endpoint, while the ejected endpoints are probed once the ejection duration passes
+// and re-admitted when the probe succeeds.
+func (c *ClientPool) CheckHealth(ctx context.Context) {
+	c.mu.RLock()
+	probes := make(map[*Stats]*Client)
+	for _, clients := range c.clients {
+		for _, client := range clients {
+			probes[client.GetStats()] = client
+		}
+	}
+	c.mu.RUnlock()
+
+	var wg sync.WaitGroup
+	for stats, client := range probes {
+		if !stats.IsHealthy() && time.Since(stats.GetEjectedAt()) < c.opts.GetEjectionDuration() {
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			probeCtx, cancel := context.WithTimeout(ctx, c.opts.GetHealthCheckTimeout())
+			defer cancel()
+
+			err := client.track(probeCtx, func() error {
+				_, err := client.BlockNumber(probeCtx)
+				return err
+			})
+
+			if err == nil && !stats.IsHealthy() {
+				stats.readmit()
+				zap.L().Info(
+					"re-admitted recovered client endpoint",
+					zap.String("group", client.GetGroup()),
+					zap.String("type", client.GetType()),
+				)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// runHealthChecks checks the health of the endpoints at the interval until the pool is closed.
+func (c *ClientPool) runHealthChecks(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			c.CheckHealth(c.ctx)
+		case <-c.ctx.Done():
+			return
+		}
+	}
+}
+
+// next returns the client the call is attempted with, skipping the clients of the endpoints already tried,
+// identified by their shared stats. Healthy clients of the group and type come first, then the healthy
+// clients of the failover group and type, then the rest.
+func (c *ClientPool) next(group, typ string, tried map[*Stats]bool) *Client {
+	key := group + "_" + typ
+
+	c.mu.RLock()
+	primary := untried(c.clients[key], tried)
+	var failover []*Client
+	failoverKey := ""
+	if clients := c.clients[key]; len(clients) > 0 && clients[0].GetFailoverGroup() != "" {
+		failoverKey = clients[0].GetFailoverGroup() + "_" + clients[0].GetFailoverType()
+		if failoverKey != key {
+			failover = untried(c.clients[failoverKey], tried)
 		}
 	}
+	c.mu.RUnlock()
+
+	if clients := healthy(primary); len(clients) > 0 {
+		return c.pick(key, clients)
+	}
+
+	if clients := healthy(failover); len(clients) > 0 {
+		return c.pick(failoverKey, clients)
+	}
+
+	if len(primary) > 0 {
+		return c.pick(key, primary)
+	}
+
+	return c.pick(failoverKey, failover)
+}
 
-	// If no clients are found for the group, return nil
-	if len(allClientsInGroup) == 0 {
+// pick returns the client picked among the healthy clients by the selection strategy, or among all of them
+// when none is healthy. Clients tied by the strategy are picked in turns.
+func (c *ClientPool) pick(key string, clients []*Client) *Client {
+	if len(clients) == 0 {
 		return nil
 	}
 
-	// Use atomic operation to get the next client index for the group in a round-robin fashion
-	current := c.groupTypeNext[group]
-	n := atomic.AddUint32(&current, 1)
+	candidates := healthy(clients)
+	if len(candidates) == 0 {
+		candidates = clients
+	}
 
-	// Calculate the client index based on the current value of n
-	clientIndex := (int(n) - 1) % len(allClientsInGroup)
+	switch c.opts.GetSelection() {
+	case LeastLoadedSelection:
+		candidates = lowest(candidates, func(client *Client) int64 {
+			return client.GetStats().GetInflight()
+		})
+	case LowestLatencySelection:
+		candidates = lowest(candidates, func(client *Client) int64 {
+			// Latency of the endpoint without the successful request yet is unknown, hence picked last.
+			if latency := client.GetStats().GetLatency(); latency > 0 {
+				return int64(latency)
+			}
+			return math.MaxInt64
+		})
+	}
 
-	// Return the client at the calculated index
-	return allClientsInGroup[clientIndex]
+	n := c.counter(key).Add(1)
+	return candidates[(int(n)-1)%len(candidates)]
+}
+
+// counter returns the round-robin counter of the key.
+func (c *ClientPool) counter(key string) *atomic.Uint32 {
+	c.mu.RLock()
+	counter, ok := c.counters[key]
+	c.mu.RUnlock()
+	if ok {
+		return counter
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if counter, ok = c.counters[key]; !ok {
+		counter = new(atomic.Uint32)
+		c.counters[key] = counter
+	}
+	return counter
+}
+
+// endpoint returns the rate limiter and stats shared by the clients of the node endpoint.
+func (c *ClientPool) endpoint(node *Node) *endpoint {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if toReturn, ok := c.endpoints[node.GetEndpoint()]; ok {
+		return toReturn
+	}
+
+	toReturn := &endpoint{stats: newStats(c.opts.GetMaxErrorRate(), c.opts.GetMinRequests())}
+	if node.GetRateLimit() > 0 {
+		toReturn.limiter = utils.NewRateLimiter(node.GetRateLimit(), time.Second)
+	}
+
+	c.endpoints[node.GetEndpoint()] = toReturn
+	return toReturn
+}
+
+// addClient adds the client to the clients of the group and type.
+func (c *ClientPool) addClient(key string, client *Client) {
+	c.mu.Lock()
+	c.clients[key] = append(c.clients[key], client)
+	c.mu.Unlock()
 }
 
 // GetClientDescriptionByNetworkId retrieves the group and type of a client based on the network ID.
@@ -85,8 +377,13 @@ func (c *ClientPool) GetClientDescriptionByNetworkId(networkId *big.Int) (string
 	return "", ""
 }
 
-// Close gracefully closes all the clients in the pool.
+// Close gracefully closes all the clients in the pool and stops the health checks.
 func (c *ClientPool) Close() {
+	c.cancel()
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
 	for _, clients := range c.clients {
 		for _, client := range clients {
 			client.Close()
@@ -104,23 +401,21 @@ func (c *ClientPool) RegisterClient(ctx context.Context, networkId uint64, group
 		return errors.New("concurrentClientsNumber must be greater than 0")
 	}
 
-	mutex := sync.Mutex{}
 	g, ctx := errgroup.WithContext(ctx)
 	key := group + "_" + typ
 
 	for i := 0; i < concurrentClientsNumber; i++ {
 		g.Go(func() error {
 			node := Node{Endpoint: endpoint, Group: group, Type: typ, NetworkId: int(networkId), ConcurrentClients: concurrentClientsNumber}
-			client, err := NewClient(ctx, &node)
+			shared := c.endpoint(&node)
+			client, err := newClient(ctx, &node, shared.limiter, shared.stats)
 			if err != nil {
 				return err
 			}
 
 			// Additional checks or configurations for the client can be added here
 
-			mutex.Lock()
-			c.clients[key] = append(c.clients[key], client)
-			mutex.Unlock()
+			c.addClient(key, client)
 			return nil
 		})
 	}
@@ -135,11 +430,8 @@ func (c *ClientPool) RegisterClient(ctx context.Context, networkId uint64, group
 // NewClientPool initializes a new ClientPool with the given options.
 // It returns an error if the options are not set, if there are no nodes specified in the options,
 // or if there's an issue with any of the nodes' configurations.
+// Unless disabled in the options, the endpoints are health checked in the background until the pool is closed.
 func NewClientPool(ctx context.Context, opts *Options) (*ClientPool, error) {
-	clients := make(map[string][]*Client)
-	mutex := sync.Mutex{}
-	g, ctx := errgroup.WithContext(ctx)
-
 	if opts == nil {
 		return nil, ErrOptionsNotSet
 	}
@@ -148,19 +440,45 @@ func NewClientPool(ctx context.Context, opts *Options) (*ClientPool, error) {
 		return nil, ErrNodesNotSet
 	} */
 
+	poolCtx, cancel := context.WithCancel(ctx)
+	toReturn := &ClientPool{
+		ctx:       poolCtx,
+		cancel:    cancel,
+		opts:      opts,
+		clients:   make(map[string][]*Client),
+		endpoints: make(map[string]*endpoint),
+		counters:  make(map[string]*atomic.Uint32),
+		batchers:  make(map[string]*Batcher),
+		callers:   make(map[string]*Multicaller),
+	}
+
+	if opts.Cache != nil {
+		store, err := cache.New(poolCtx, opts.Cache)
+		if err != nil {
+			cancel()
+			return nil, fmt.Errorf("failed to create cache: %w", err)
+		}
+		toReturn.cache = store
+	}
+
+	g, gctx := errgroup.WithContext(ctx)
+
 	for _, node := range opts.GetNodes() {
 		if node.GetEndpoint() == "" {
+			cancel()
 			return nil, ErrClientURLNotSet
 		}
 
 		if node.GetConcurrentClientsNumber() == 0 {
+			cancel()
 			return nil, ErrConcurrentClientsNotSet
 		}
 
 		for i := 0; i < node.GetConcurrentClientsNumber(); i++ {
 			node := node // Shadow variable for goroutine
 			g.Go(func() error {
-				client, err := NewClient(ctx, &node)
+				shared := toReturn.endpoint(&node)
+				client, err := newClient(gctx, &node, shared.limiter, shared.stats)
 				if err != nil {
 					return err
 				}
@@ -171,10 +489,7 @@ func NewClientPool(ctx context.Context, opts *Options) (*ClientPool, error) {
 					return errors.New("network ID mismatch")
 				}
 
-				key := node.GetGroup() + "_" + node.GetType()
-				mutex.Lock()
-				clients[key] = append(clients[key], client)
-				mutex.Unlock()
+				toReturn.addClient(node.GetGroup()+"_"+node.GetType(), client)
 				return nil
 			})
 		}
@@ -182,14 +497,53 @@ func NewClientPool(ctx context.Context, opts *Options) (*ClientPool, error) {
 
 	if err := g.Wait(); err != nil {
 		zap.L().Error("failed to initialize ethereum client", zap.Error(err))
+		toReturn.Close()
 		return nil, err
 	}
 
-	return &ClientPool{
-		ctx:           ctx,
-		opts:          opts,
-		clients:       clients,
-		next:          0,
-		groupTypeNext: make(map[string]uint32),
-	}, nil
+	if interval := opts.GetHealthCheckInterval(); interval > 0 {
+		go toReturn.runHealthChecks(interval)
+	}
+
+	return toReturn, nil
+}
+
+// untried returns the clients of the endpoints not tried yet.
+func untried(clients []*Client, tried map[*Stats]bool) []*Client {
+	toReturn := make([]*Client, 0, len(clients))
+	for _, client := range clients {
+		if !tried[client.GetStats()] {
+			toReturn = append(toReturn, client)
+		}
+	}
+	return toReturn
+}
+
+// healthy returns the healthy clients.
+func healthy(clients []*Client) []*Client {
+	toReturn := make([]*Client, 0, len(clients))
+	for _, client := range clients {
+		if client.IsHealthy() {
+			toReturn = append(toReturn, client)
+		}
+	}
+	return toReturn
+}
+
+// lowest returns the clients with the lowest value of the metric.
+func lowest(clients []*Client, metric func(client *Client) int64) []*Client {
+	toReturn := make([]*Client, 0, len(clients))
+	var best int64
+
+	for _, client := range clients {
+		value := metric(client)
+		if len(toReturn) == 0 || value < best {
+			best = value
+			toReturn = append(toReturn[:0], client)
+		} else if value == best {
+			toReturn = append(toReturn, client)
+		}
+	}
+
+	return toReturn
 }
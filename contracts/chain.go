@@ -11,7 +11,7 @@ import (
 
 // DiscoverChainInfo retrieves information about the contract's deployment chain, including transaction, receipt, and block details.
 // If `otsLookup` is true, it queries the contract creator's information using the provided context. If `otsLookup` is false or
-// if the creator's information is not available, it queries the contract creation transaction hash using etherscan.
+// if the creator's information is not available, it queries the contract creation transaction hash using the explorer provider.
 // It then fetches the transaction, receipt, and block information associated with the contract deployment from the blockchain.
 // This method populates the contract descriptor with the retrieved information.
 func (c *Contract) DiscoverChainInfo(ctx context.Context, otsLookup bool) error {
@@ -32,7 +32,7 @@ func (c *Contract) DiscoverChainInfo(ctx context.Context, otsLookup bool) error
 	if info == nil || info.CreationHash == utils.ZeroHash {
 		// Prior to continuing with the unpacking of the contract, we want to make sure that we can reach properly
 		// contract transaction and associated creation block. If we can't, we're not going to unpack it.
-		cInfo, err := c.etherscan.QueryContractCreationTx(ctx, c.addr)
+		cInfo, err := c.explorer.QueryContractCreationTx(ctx, c.addr)
 		if err != nil {
 			return fmt.Errorf("failed to query contract creation block and tx hash: %w", err)
 		}
@@ -11,7 +11,8 @@ import (
 	"github.com/unpackdev/solgo/clients"
 	"github.com/unpackdev/solgo/metadata"
 	"github.com/unpackdev/solgo/providers/bitquery"
-	"github.com/unpackdev/solgo/providers/etherscan"
+	"github.com/unpackdev/solgo/providers/explorer"
+	"github.com/unpackdev/solgo/providers/sourcify"
 	"github.com/unpackdev/solgo/storage"
 	"github.com/unpackdev/solgo/tokens"
 	"github.com/unpackdev/solgo/utils"
@@ -39,7 +40,8 @@ type Contract struct {
 	descriptor   *Descriptor
 	token        *tokens.Token
 	bqp          *bitquery.Provider
-	etherscan    *etherscan.Provider
+	explorer     explorer.Provider
+	sourcify     *sourcify.Provider
 	compiler     *solc.Solc
 	bindings     *bindings.Manager
 	tokenBind    *bindings.Token
@@ -50,7 +52,7 @@ type Contract struct {
 // NewContract creates a new instance of Contract for a given Ethereum address and network.
 // It initializes the contract's context, metadata, and associated blockchain clients.
 // The function validates the contract's existence and its bytecode before creation.
-func NewContract(ctx context.Context, network utils.Network, clientPool *clients.ClientPool, stor *storage.Storage, bqp *bitquery.Provider, etherscan *etherscan.Provider, compiler *solc.Solc, bindManager *bindings.Manager, ipfsProvider metadata.Provider, addr common.Address) (*Contract, error) {
+func NewContract(ctx context.Context, network utils.Network, clientPool *clients.ClientPool, stor *storage.Storage, bqp *bitquery.Provider, explorer explorer.Provider, compiler *solc.Solc, bindManager *bindings.Manager, ipfsProvider metadata.Provider, addr common.Address) (*Contract, error) {
 	if clientPool == nil {
 		return nil, fmt.Errorf("client pool is nil")
 	}
@@ -87,7 +89,7 @@ func NewContract(ctx context.Context, network utils.Network, clientPool *clients
 		client:     client,
 		addr:       addr,
 		bqp:        bqp,
-		etherscan:  etherscan,
+		explorer:   explorer,
 		compiler:   compiler,
 		descriptor: &Descriptor{
 			Network:         network,
@@ -115,6 +117,12 @@ func (c *Contract) GetNetwork() utils.Network {
 	return c.network
 }
 
+// SetSourcify sets the Sourcify provider the verified sources are looked up in when the explorer does not
+// have them.
+func (c *Contract) SetSourcify(provider *sourcify.Provider) {
+	c.sourcify = provider
+}
+
 // GetDeployedBytecode returns the deployed bytecode of the contract.
 // This bytecode is the compiled contract code that exists on the Ethereum blockchain.
 func (c *Contract) GetDeployedBytecode() []byte {
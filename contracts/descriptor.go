@@ -8,7 +8,7 @@ import (
 	"github.com/unpackdev/solgo/bytecode"
 	"github.com/unpackdev/solgo/detector"
 	"github.com/unpackdev/solgo/ir"
-	"github.com/unpackdev/solgo/providers/etherscan"
+	"github.com/unpackdev/solgo/providers/explorer"
 	"github.com/unpackdev/solgo/tokens"
 	"github.com/unpackdev/solgo/utils"
 )
@@ -40,9 +40,9 @@ type Descriptor struct {
 
 	// SourcesRaw is the raw sources from Etherscan|BscScan|etc. Should not be used anywhere except in
 	// the contract discovery process.
-	SourcesRaw     *etherscan.Contract `json:"-"`
-	Sources        *solgo.Sources      `json:"sources,omitempty"`
-	SourceProvider string              `json:"source_provider,omitempty"`
+	SourcesRaw     *explorer.Contract `json:"-"`
+	Sources        *solgo.Sources     `json:"sources,omitempty"`
+	SourceProvider string             `json:"source_provider,omitempty"`
 
 	// Source detection related fields.
 	Detector    *detector.Detector    `json:"-"`
@@ -145,11 +145,12 @@ func (d *Descriptor) GetSources() *solgo.Sources {
 }
 
 // GetSourcesRaw returns the raw contract source as obtained from external providers like Etherscan.
-func (d *Descriptor) GetSourcesRaw() *etherscan.Contract {
+func (d *Descriptor) GetSourcesRaw() *explorer.Contract {
 	return d.SourcesRaw
 }
 
 // GetSourceProvider returns the name of the source provider, indicating where the contract source was obtained from.
+// Sources from Sourcify carry the match type as well, such as sourcify:full_match.
 func (d *Descriptor) GetSourceProvider() string {
 	return d.SourceProvider
 }
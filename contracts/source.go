@@ -9,7 +9,7 @@ import (
 	"time"
 
 	"github.com/unpackdev/solgo"
-	"github.com/unpackdev/solgo/providers/etherscan"
+	"github.com/unpackdev/solgo/providers/explorer"
 	"go.uber.org/zap"
 )
 
@@ -20,20 +20,25 @@ import (
 //
 // This method implements a retry mechanism to handle rate limiting by the provider. It logs
 // and returns errors encountered during the process, except for cases where the contract
-// source code is not found or not verified, which are considered non-critical errors.
+// source code is not found or not verified, which are considered non-critical errors. Such contracts,
+// and all of them when no explorer is configured, are looked up in Sourcify when it is set.
 func (c *Contract) DiscoverSourceCode(ctx context.Context) error {
 	select {
 	case <-ctx.Done():
 		return nil
 	default:
-		var response *etherscan.Contract // Assuming ScanResponse is the type returned by ScanContract
+		if c.explorer == nil && c.sourcify != nil {
+			return c.DiscoverSourcifySourceCode(ctx)
+		}
+
+		var response *explorer.Contract
 		var err error
 
 		// Retry mechanism
 		const maxRetries = 10
 		for i := 0; i < maxRetries; i++ {
 			dCtx, dCancel := context.WithTimeout(ctx, 15*time.Second)
-			response, err = c.etherscan.ScanContract(dCtx, c.addr)
+			response, err = c.explorer.ScanContract(dCtx, c.addr)
 			if err != nil {
 				if strings.Contains(err.Error(), "Max rate limit reached") ||
 					strings.Contains(err.Error(), "context deadline exceeded") {
@@ -41,6 +46,10 @@ func (c *Contract) DiscoverSourceCode(ctx context.Context) error {
 					time.Sleep(time.Duration(i*1000) * time.Millisecond)
 					dCancel()
 					continue
+				} else if c.sourcify != nil && (strings.Contains(err.Error(), "not found") ||
+					strings.Contains(err.Error(), "not verified")) {
+					dCancel()
+					return c.DiscoverSourcifySourceCode(ctx)
 				} else if !strings.Contains(err.Error(), "not found") &&
 					!strings.Contains(err.Error(), "not verified") {
 					zap.L().Error(
@@ -51,7 +60,7 @@ func (c *Contract) DiscoverSourceCode(ctx context.Context) error {
 					)
 				}
 				dCancel()
-				return fmt.Errorf("failed to scan contract source code from %s: %s", c.etherscan.ProviderName(), err)
+				return fmt.Errorf("failed to scan contract source code from %s: %s", c.explorer.ProviderName(), err)
 			}
 			dCancel()
 			break // Exit loop if ScanContract is successful
@@ -59,7 +68,7 @@ func (c *Contract) DiscoverSourceCode(ctx context.Context) error {
 
 		// Handle the case when all retries fail
 		if err != nil {
-			return fmt.Errorf("after %d retries, failed to scan contract source code from %s: %s", maxRetries, c.etherscan.ProviderName(), err)
+			return fmt.Errorf("after %d retries, failed to scan contract source code from %s: %s", maxRetries, c.explorer.ProviderName(), err)
 		}
 
 		c.descriptor.SourcesRaw = response
@@ -107,15 +116,31 @@ func (c *Contract) DiscoverSourceCode(ctx context.Context) error {
 			return err
 		}
 
+		// Plain single file source codes do not carry compiler settings, so we are building them
+		// from the etherscan response in order to preserve them for reproducible compilation.
+		if sources.Settings == nil {
+			sources.Settings = &solgo.StandardJsonSettings{
+				EvmVersion: strings.ToLower(c.descriptor.SourcesRaw.EVMVersion),
+				Optimizer: &solgo.StandardJsonOptimizer{
+					Enabled: optimized,
+					Runs:    int(optimizationRuns),
+				},
+			}
+
+			if sources.Settings.EvmVersion == "default" {
+				sources.Settings.EvmVersion = ""
+			}
+		}
+
 		c.descriptor.Name = response.Name
 		c.descriptor.CompilerVersion = c.descriptor.SourcesRaw.CompilerVersion
 		c.descriptor.Optimized = optimized
 		c.descriptor.OptimizationRuns = optimizationRuns
 		c.descriptor.EVMVersion = c.descriptor.SourcesRaw.EVMVersion
 		c.descriptor.ABI = c.descriptor.SourcesRaw.ABI
-		c.descriptor.SourceProvider = c.etherscan.ProviderName()
+		c.descriptor.SourceProvider = c.explorer.ProviderName()
 		c.descriptor.Verified = true
-		c.descriptor.VerificationProvider = c.etherscan.ProviderName()
+		c.descriptor.VerificationProvider = c.explorer.ProviderName()
 
 		proxy, _ := strconv.ParseBool(response.Proxy)
 		c.descriptor.Proxy = proxy
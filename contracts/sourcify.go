@@ -0,0 +1,66 @@
+package contracts
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/goccy/go-json"
+	"go.uber.org/zap"
+)
+
+// DiscoverSourcifySourceCode retrieves the verified sources of the contract from Sourcify and enriches the
+// contract's descriptor with them and with the compiler settings of their metadata. The match type is recorded
+// as the source provider, such as sourcify:full_match, as the partial matches may differ from the original
+// sources in the comments and names.
+func (c *Contract) DiscoverSourcifySourceCode(ctx context.Context) error {
+	if c.sourcify == nil {
+		return fmt.Errorf("sourcify provider is not set")
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil
+	default:
+		match, err := c.sourcify.GetMatch(ctx, c.descriptor.NetworkID.Uint64(), c.addr)
+		if err != nil {
+			return fmt.Errorf("failed to discover contract source code from %s: %w", c.sourcify.ProviderName(), err)
+		}
+
+		sources := match.GetSources()
+		if err := sources.SortContracts(); err != nil {
+			zap.L().Error(
+				"failed to sort sourcify contract sources",
+				zap.Error(err),
+				zap.String("network", c.network.String()),
+				zap.String("contract_address", c.addr.String()),
+			)
+			return fmt.Errorf("failure while doing topological contract sorting: %s", err)
+		}
+
+		abi, err := json.Marshal(match.Metadata.Output.Abi)
+		if err != nil {
+			return fmt.Errorf("failed to marshal sourcify contract abi: %w", err)
+		}
+
+		// Explorers report the compiler versions with the v prefix, so we keep them the same.
+		compilerVersion := match.Metadata.Compiler.Version
+		if compilerVersion != "" && !strings.HasPrefix(compilerVersion, "v") {
+			compilerVersion = "v" + compilerVersion
+		}
+
+		c.descriptor.Sources = sources
+		c.descriptor.Name = match.GetName()
+		c.descriptor.License = strings.TrimSpace(match.GetLicense())
+		c.descriptor.CompilerVersion = compilerVersion
+		c.descriptor.Optimized = match.Metadata.Settings.Optimizer.Enabled
+		c.descriptor.OptimizationRuns = uint64(match.Metadata.Settings.Optimizer.Runs)
+		c.descriptor.EVMVersion = match.Metadata.Settings.EvmVersion
+		c.descriptor.ABI = string(abi)
+		c.descriptor.SourceProvider = match.GetSourceProvider()
+		c.descriptor.Verified = true
+		c.descriptor.VerificationProvider = c.sourcify.ProviderName()
+
+		return nil
+	}
+}
@@ -0,0 +1,110 @@
+# @version ^0.3.9
+# @license MIT
+"""
+@title Bare-bones Token implementation
+@notice Based on the ERC-20 token standard as defined at
+        https://eips.ethereum.org/EIPS/eip-20
+"""
+
+from vyper.interfaces import ERC20
+from vyper.interfaces import ERC20Detailed
+
+implements: ERC20
+implements: ERC20Detailed
+
+event Transfer:
+    sender: indexed(address)
+    receiver: indexed(address)
+    value: uint256
+
+event Approval:
+    owner: indexed(address)
+    spender: indexed(address)
+    value: uint256
+
+struct Checkpoint:
+    block_number: uint256
+    balance: uint256
+
+name: public(String[32])
+symbol: public(String[32])
+decimals: public(uint8)
+
+# NOTE: By declaring `balanceOf` as public, vyper automatically generates a 'balanceOf()' getter
+#       method to allow access to account balances.
+balanceOf: public(HashMap[address, uint256])
+allowance: public(HashMap[address, HashMap[address, uint256]])
+totalSupply: public(uint256)
+minter: address
+checkpoints: HashMap[address, DynArray[Checkpoint, 128]]
+MAX_SUPPLY: constant(uint256) = 10 ** 30
+
+
+@external
+def __init__(_name: String[32], _symbol: String[32], _decimals: uint8, _supply: uint256):
+    init_supply: uint256 = _supply * 10 ** convert(_decimals, uint256)
+    self.name = _name
+    self.symbol = _symbol
+    self.decimals = _decimals
+    self.balanceOf[msg.sender] = init_supply
+    self.totalSupply = init_supply
+    self.minter = msg.sender
+    log Transfer(empty(address), msg.sender, init_supply)
+
+
+@external
+def transfer(_to : address, _value : uint256) -> bool:
+    """
+    @dev Transfer token for a specified address
+    @param _to The address to transfer to.
+    @param _value The amount to be transferred.
+    """
+    self.balanceOf[msg.sender] -= _value
+    self.balanceOf[_to] += _value
+    log Transfer(msg.sender, _to, _value)
+    return True
+
+
+@external
+def transferFrom(_from : address, _to : address, _value : uint256) -> bool:
+    self.balanceOf[_from] -= _value
+    self.balanceOf[_to] += _value
+    self.allowance[_from][msg.sender] -= _value
+    log Transfer(_from, _to, _value)
+    return True
+
+
+@external
+def approve(_spender : address, _value : uint256) -> bool:
+    self.allowance[msg.sender][_spender] = _value
+    log Approval(msg.sender, _spender, _value)
+    return True
+
+
+@external
+@view
+def getCheckpoint(
+    _owner: address,
+    _index: uint256
+) -> Checkpoint:
+    return self.checkpoints[_owner][_index]
+
+
+@external
+def mint(_to: address, _value: uint256):
+    assert msg.sender == self.minter
+    assert _to != empty(address)
+    self._mint(_to, _value)
+
+
+@internal
+def _mint(_to: address, _value: uint256):
+    self.totalSupply += _value
+    self.balanceOf[_to] += _value
+    log Transfer(empty(address), _to, _value)
+
+
+@external
+@payable
+def __default__():
+    pass
@@ -14,6 +14,7 @@ import (
 	"github.com/unpackdev/solgo/ir"
 	"github.com/unpackdev/solgo/opcode"
 	"github.com/unpackdev/solgo/standards"
+	"github.com/unpackdev/solgo/validation"
 )
 
 // Detector is a utility structure that provides functionalities to detect and analyze
@@ -98,6 +99,23 @@ func (d *Detector) GetSolc() *solc.Solc {
 	return d.solc
 }
 
+// GetStandardJsonInput returns the Detector sources exported as solc Standard JSON Input,
+// including compiler settings attached to the sources.
+func (d *Detector) GetStandardJsonInput() (*solgo.StandardJsonInput, error) {
+	return d.sources.ToStandardJSON()
+}
+
+// CompileStandardJSON compiles the Detector sources as solc Standard JSON Input using the provided
+// compiler version and returns typed Standard JSON Output.
+func (d *Detector) CompileStandardJSON(version string) (*solgo.StandardJsonOutput, error) {
+	input, err := d.GetStandardJsonInput()
+	if err != nil {
+		return nil, err
+	}
+
+	return validation.CompileStandardJSON(d.ctx, d.solc, version, input)
+}
+
 // GetOpcodes returns the opcodes decompiler from provided byte array.
 // It decompiles the bytecode of the contract, transaction, log to EVM opcodes.
 func (d *Detector) GetOpcodes(data []byte) (*opcode.Decompiler, error) {
@@ -0,0 +1,143 @@
+package indexer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/unpackdev/solgo/clients"
+)
+
+// RangeTooLargeMessages are the fragments of the errors the nodes and providers return when the requested
+// block range, or the number of logs within it, exceeds their limits.
+var RangeTooLargeMessages = []string{
+	"query returned more than",
+	"block range is too wide",
+	"maximum block range",
+	"block range limit",
+	"range is too large",
+	"range too large",
+	"too many blocks",
+	"response size exceeded",
+	"response too large",
+	"limit exceeded",
+	"log response size",
+	"query timeout exceeded",
+}
+
+// Backend provides the logs and headers of the chain. It is satisfied by clients.Client, as well as by the
+// go-ethereum ethclient.Client.
+type Backend interface {
+	FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
+// PoolBackend implements the Backend on top of the client pool, spreading the log requests over all the clients
+// of the group. Headers are requested from the single client, so the nodes lagging behind or following another
+// fork are not mistaken for the reorganizations of the chain.
+type PoolBackend struct {
+	pool  *clients.ClientPool
+	group string
+
+	mu     sync.Mutex
+	pinned *clients.Client // Client the headers are requested from, nil until the first header is requested.
+}
+
+// NewPoolBackend creates the Backend using the clients of the group from the client pool.
+func NewPoolBackend(pool *clients.ClientPool, group string) (*PoolBackend, error) {
+	if pool == nil {
+		return nil, ErrInvalidBackend
+	}
+
+	return &PoolBackend{pool: pool, group: group}, nil
+}
+
+// FilterLogs requests the logs matching the query from the next client of the group.
+func (b *PoolBackend) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	client, err := b.client()
+	if err != nil {
+		return nil, err
+	}
+	return client.FilterLogs(ctx, query)
+}
+
+// HeaderByNumber requests the header of the block from the pinned client of the group. Another client is pinned
+// once the pinned one fails the request, other than for the block it does not know, or becomes unhealthy.
+func (b *PoolBackend) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	client, err := b.pinnedClient()
+	if err != nil {
+		return nil, err
+	}
+
+	header, err := client.HeaderByNumber(ctx, number)
+	if err != nil && !errors.Is(err, ethereum.NotFound) {
+		b.unpin(client)
+	}
+	return header, err
+}
+
+// GetPinnedClient returns the client the headers are requested from, nil when none is pinned yet.
+func (b *PoolBackend) GetPinnedClient() *clients.Client {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.pinned
+}
+
+// client returns the next client of the group.
+func (b *PoolBackend) client() (*clients.Client, error) {
+	client := b.pool.GetClientByGroup(b.group)
+	if client == nil {
+		return nil, fmt.Errorf("%w: group %s", ErrClientNotFound, b.group)
+	}
+	return client, nil
+}
+
+// pinnedClient returns the pinned client, pinning the next client of the group when none is pinned or the pinned
+// one is unhealthy.
+func (b *PoolBackend) pinnedClient() (*clients.Client, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.pinned != nil && b.pinned.IsHealthy() {
+		return b.pinned, nil
+	}
+
+	client, err := b.client()
+	if err != nil {
+		return nil, err
+	}
+
+	b.pinned = client
+	return client, nil
+}
+
+// unpin releases the client, unless another client was pinned in the meantime.
+func (b *PoolBackend) unpin(client *clients.Client) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.pinned == client {
+		b.pinned = nil
+	}
+}
+
+// IsRangeTooLarge returns true if the error reports the requested block range exceeds the limits of the node.
+func IsRangeTooLarge(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	message := strings.ToLower(err.Error())
+	for _, fragment := range RangeTooLargeMessages {
+		if strings.Contains(message, fragment) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,10 @@
+// Package indexer provides the historical event log indexer with chain reorganization handling.
+//
+// The LogIndexer backfills the logs of the configured contracts and topics through eth_getLogs,
+// splitting the block ranges the node refuses to serve at once, and then follows the chain head.
+// Blocks within the configured number of confirmations are tracked by their hashes, and when the
+// chain reorganizes, the events of the abandoned blocks are rolled back before the canonical ones
+// are indexed. Logs are decoded through bytecode.DecodeLogFromAbi and persisted, together with the
+// checkpoint the indexing resumes from, through the pluggable Sink interface, which is implemented
+// in memory and on disk.
+package indexer
@@ -0,0 +1,22 @@
+package indexer
+
+import "errors"
+
+var (
+	// ErrInvalidBackend is returned when the indexer is created without the backend.
+	ErrInvalidBackend = errors.New("invalid indexer backend")
+
+	// ErrInvalidSink is returned when the indexer is created without the sink.
+	ErrInvalidSink = errors.New("invalid indexer sink")
+
+	// ErrClientNotFound is returned when the client pool has no client of the configured group.
+	ErrClientNotFound = errors.New("client not found")
+
+	// ErrReorgTooDeep is returned when the chain reorganization reaches beyond the blocks tracked by the indexer,
+	// meaning the indexed events of the finalized blocks are no longer canonical.
+	ErrReorgTooDeep = errors.New("chain reorganization deeper than the tracked blocks")
+
+	// ErrInconsistentLogs is returned when the logs do not belong to the blocks the indexer tracks, which happens
+	// when the chain reorganizes while the logs are requested.
+	ErrInconsistentLogs = errors.New("logs are inconsistent with the tracked blocks")
+)
@@ -0,0 +1,375 @@
+package indexer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/unpackdev/solgo/bytecode"
+)
+
+const (
+	// DefaultBatchSize is the number of blocks the logs are requested for at once.
+	DefaultBatchSize = 2000
+
+	// DefaultPollInterval is the interval the chain head is polled at once the indexer caught up.
+	DefaultPollInterval = 12 * time.Second
+
+	// batchGrowthStreak is the number of the successful ranges after which the reduced batch size is doubled.
+	batchGrowthStreak = 10
+)
+
+// Options configures the LogIndexer.
+type Options struct {
+	Addresses     []common.Address `mapstructure:"addresses" yaml:"addresses" json:"addresses"`             // Contracts the logs are indexed for, all contracts when empty.
+	Topics        [][]common.Hash  `mapstructure:"topics" yaml:"topics" json:"topics"`                      // Topics the logs are filtered by, as in eth_getLogs.
+	ABI           string           `mapstructure:"abi" yaml:"abi" json:"abi"`                               // JSON ABI the logs are decoded with, logs are not decoded when empty.
+	FromBlock     uint64           `mapstructure:"from_block" yaml:"from_block" json:"from_block"`          // First block to index.
+	ToBlock       uint64           `mapstructure:"to_block" yaml:"to_block" json:"to_block"`                // Last block to index, the chain head is followed when zero.
+	Confirmations uint64           `mapstructure:"confirmations" yaml:"confirmations" json:"confirmations"` // Blocks on top of the block for it to be finalized, zero disables reorg detection.
+	BatchSize     uint64           `mapstructure:"batch_size" yaml:"batch_size" json:"batch_size"`          // Maximum number of blocks the logs are requested for at once.
+	PollInterval  time.Duration    `mapstructure:"poll_interval" yaml:"poll_interval" json:"poll_interval"` // Interval the chain head is polled at.
+}
+
+// LogIndexer indexes the historical logs and follows the chain head, rolling back the events of the blocks
+// reorganized out of the chain.
+type LogIndexer struct {
+	mu         sync.Mutex
+	backend    Backend
+	sink       Sink
+	opts       Options
+	abi        []byte
+	batchSize  uint64
+	streak     int
+	checkpoint *Checkpoint
+}
+
+// NewLogIndexer creates a new LogIndexer resuming from the checkpoint stored in the sink.
+func NewLogIndexer(ctx context.Context, backend Backend, sink Sink, opts *Options) (*LogIndexer, error) {
+	if backend == nil {
+		return nil, ErrInvalidBackend
+	}
+
+	if sink == nil {
+		return nil, ErrInvalidSink
+	}
+
+	toReturn := &LogIndexer{backend: backend, sink: sink}
+	if opts != nil {
+		toReturn.opts = *opts
+	}
+
+	if toReturn.opts.BatchSize == 0 {
+		toReturn.opts.BatchSize = DefaultBatchSize
+	}
+
+	if toReturn.opts.PollInterval == 0 {
+		toReturn.opts.PollInterval = DefaultPollInterval
+	}
+
+	if toReturn.opts.ToBlock != 0 && toReturn.opts.ToBlock < toReturn.opts.FromBlock {
+		return nil, fmt.Errorf("invalid block range %d-%d", toReturn.opts.FromBlock, toReturn.opts.ToBlock)
+	}
+
+	if toReturn.opts.ABI != "" {
+		if _, err := abi.JSON(bytes.NewReader([]byte(toReturn.opts.ABI))); err != nil {
+			return nil, fmt.Errorf("failed to parse abi: %w", err)
+		}
+		toReturn.abi = []byte(toReturn.opts.ABI)
+	}
+
+	checkpoint, err := sink.GetCheckpoint(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get checkpoint: %w", err)
+	}
+
+	toReturn.batchSize = toReturn.opts.BatchSize
+	toReturn.checkpoint = checkpoint
+	return toReturn, nil
+}
+
+// GetCheckpoint returns the indexing progress, or nil when nothing was indexed yet.
+func (i *LogIndexer) GetCheckpoint() *Checkpoint {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	return i.checkpoint
+}
+
+// Run indexes the logs until the last block of the options is finalized, or until the context is cancelled
+// when the chain head is followed. Logs requested while the chain reorganizes are requested again at the next
+// poll, while the other errors stop the indexer, which can be run again to resume from the checkpoint.
+func (i *LogIndexer) Run(ctx context.Context) error {
+	for {
+		done, err := i.Sync(ctx)
+		if err != nil && !errors.Is(err, ErrInconsistentLogs) {
+			return err
+		}
+
+		if done {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(i.opts.PollInterval):
+		}
+	}
+}
+
+// Sync performs the single indexing pass. It rolls back the events of the reorganized blocks and indexes the
+// blocks up to the current chain head, or the last block of the options. It returns true once the last block of
+// the options is indexed and finalized.
+func (i *LogIndexer) Sync(ctx context.Context) (bool, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	header, err := i.backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to get chain head: %w", err)
+	}
+	head := header.Number.Uint64()
+
+	if err := i.handleReorg(ctx); err != nil {
+		return false, err
+	}
+
+	finalized := uint64(0)
+	if head > i.opts.Confirmations {
+		finalized = head - i.opts.Confirmations
+	}
+
+	target := head
+	if i.opts.ToBlock != 0 && i.opts.ToBlock < target {
+		target = i.opts.ToBlock
+	}
+
+	from := i.opts.FromBlock
+	if i.checkpoint != nil {
+		from = i.checkpoint.BlockNumber + 1
+	}
+
+	for from <= target {
+		logs, to, err := i.fetchLogs(ctx, from, min(from+i.batchSize-1, target))
+		if err != nil {
+			return false, err
+		}
+
+		blocks, err := i.fetchBlocks(ctx, max(from, finalized), to)
+		if err != nil {
+			return false, err
+		}
+
+		events, err := i.buildEvents(logs, blocks)
+		if err != nil {
+			return false, err
+		}
+
+		checkpoint := &Checkpoint{BlockNumber: to, Finalized: min(to, finalized)}
+		if i.checkpoint != nil {
+			checkpoint.Blocks = append(checkpoint.Blocks, i.checkpoint.Blocks...)
+		}
+		checkpoint.Blocks = pruneBlocks(append(checkpoint.Blocks, blocks...), checkpoint.Finalized)
+
+		if err := i.sink.Write(ctx, events, checkpoint); err != nil {
+			return false, fmt.Errorf("failed to write events of blocks %d-%d: %w", from, to, err)
+		}
+		i.checkpoint = checkpoint
+
+		// Prevents the overflow of the last range ending at the maximum block number.
+		if to == target {
+			break
+		}
+		from = to + 1
+	}
+
+	// Blocks indexed by the previous passes are finalized as the chain head advances.
+	if i.checkpoint != nil && i.checkpoint.Finalized < min(i.checkpoint.BlockNumber, finalized) {
+		checkpoint := &Checkpoint{
+			BlockNumber: i.checkpoint.BlockNumber,
+			Finalized:   min(i.checkpoint.BlockNumber, finalized),
+		}
+		checkpoint.Blocks = pruneBlocks(i.checkpoint.Blocks, checkpoint.Finalized)
+
+		if err := i.sink.Write(ctx, nil, checkpoint); err != nil {
+			return false, fmt.Errorf("failed to write checkpoint: %w", err)
+		}
+		i.checkpoint = checkpoint
+	}
+
+	done := i.opts.ToBlock != 0 && i.checkpoint != nil && i.checkpoint.Finalized >= i.opts.ToBlock
+	return done, nil
+}
+
+// handleReorg compares the tracked blocks with the canonical chain and rolls back the events of the blocks
+// after the latest tracked block that is still canonical.
+func (i *LogIndexer) handleReorg(ctx context.Context) error {
+	if i.checkpoint == nil || len(i.checkpoint.Blocks) == 0 {
+		return nil
+	}
+
+	blocks := i.checkpoint.Blocks
+	for j := len(blocks) - 1; j >= 0; j-- {
+		canonical, err := i.isCanonical(ctx, blocks[j])
+		if err != nil {
+			return err
+		}
+
+		if canonical {
+			if j == len(blocks)-1 {
+				return nil
+			}
+			return i.rollback(ctx, blocks[j].Number)
+		}
+	}
+
+	// The finalized block is tracked once the indexer followed the chain head past it, so the reorganization of
+	// the finalized block can be detected.
+	if blocks[0].Number <= i.checkpoint.Finalized {
+		return fmt.Errorf("%w: block %d is no longer canonical", ErrReorgTooDeep, blocks[0].Number)
+	}
+
+	return i.rollback(ctx, i.checkpoint.Finalized)
+}
+
+// isCanonical returns true if the block is part of the canonical chain.
+func (i *LogIndexer) isCanonical(ctx context.Context, block BlockRef) (bool, error) {
+	header, err := i.backend.HeaderByNumber(ctx, new(big.Int).SetUint64(block.Number))
+	if err != nil {
+		// Chain might be reorganized to the shorter one.
+		if errors.Is(err, ethereum.NotFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get header of block %d: %w", block.Number, err)
+	}
+
+	return header.Hash() == block.Hash, nil
+}
+
+// rollback removes the events of the blocks after the block number from the sink.
+func (i *LogIndexer) rollback(ctx context.Context, blockNumber uint64) error {
+	checkpoint := &Checkpoint{
+		BlockNumber: blockNumber,
+		Finalized:   min(i.checkpoint.Finalized, blockNumber),
+		Blocks:      make([]BlockRef, 0),
+	}
+
+	for _, block := range i.checkpoint.Blocks {
+		if block.Number <= blockNumber {
+			checkpoint.Blocks = append(checkpoint.Blocks, block)
+		}
+	}
+
+	if err := i.sink.Rollback(ctx, checkpoint); err != nil {
+		return fmt.Errorf("failed to roll back events after block %d: %w", blockNumber, err)
+	}
+
+	i.checkpoint = checkpoint
+	return nil
+}
+
+// fetchLogs requests the logs of the blocks, halving the range while the node reports it is too large. It
+// returns the logs together with the last block of the range they were requested for. The batch size follows
+// the successful ranges, so the following ranges start from the size the node accepts, and grows back towards
+// the configured batch size after the streak of the successful ranges.
+func (i *LogIndexer) fetchLogs(ctx context.Context, from uint64, to uint64) ([]types.Log, uint64, error) {
+	for {
+		logs, err := i.backend.FilterLogs(ctx, ethereum.FilterQuery{
+			FromBlock: new(big.Int).SetUint64(from),
+			ToBlock:   new(big.Int).SetUint64(to),
+			Addresses: i.opts.Addresses,
+			Topics:    i.opts.Topics,
+		})
+		if err == nil {
+			if i.streak++; i.streak >= batchGrowthStreak && i.batchSize < i.opts.BatchSize {
+				i.batchSize, i.streak = min(i.batchSize*2, i.opts.BatchSize), 0
+			}
+			return logs, to, nil
+		}
+
+		if !IsRangeTooLarge(err) || from == to {
+			return nil, 0, fmt.Errorf("failed to filter logs of blocks %d-%d: %w", from, to, err)
+		}
+
+		to = from + (to-from)/2
+		i.batchSize, i.streak = to-from+1, 0
+	}
+}
+
+// fetchBlocks requests the headers of the blocks to track them until they are finalized.
+func (i *LogIndexer) fetchBlocks(ctx context.Context, from uint64, to uint64) ([]BlockRef, error) {
+	toReturn := make([]BlockRef, 0)
+	if i.opts.Confirmations == 0 {
+		return toReturn, nil
+	}
+
+	for number := from; number <= to; number++ {
+		header, err := i.backend.HeaderByNumber(ctx, new(big.Int).SetUint64(number))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get header of block %d: %w", number, err)
+		}
+
+		toReturn = append(toReturn, BlockRef{Number: number, Hash: header.Hash()})
+	}
+
+	return toReturn, nil
+}
+
+// buildEvents sorts and decodes the logs, verifying the logs of the tracked blocks belong to them.
+func (i *LogIndexer) buildEvents(logs []types.Log, blocks []BlockRef) ([]*Event, error) {
+	hashes := make(map[uint64]common.Hash, len(blocks))
+	for _, block := range blocks {
+		hashes[block.Number] = block.Hash
+	}
+
+	sort.Slice(logs, func(a, b int) bool {
+		if logs[a].BlockNumber != logs[b].BlockNumber {
+			return logs[a].BlockNumber < logs[b].BlockNumber
+		}
+		return logs[a].Index < logs[b].Index
+	})
+
+	toReturn := make([]*Event, 0, len(logs))
+	for j := range logs {
+		if logs[j].Removed {
+			continue
+		}
+
+		if hash, ok := hashes[logs[j].BlockNumber]; ok && hash != logs[j].BlockHash {
+			return nil, fmt.Errorf("%w: log %d of block %d", ErrInconsistentLogs, logs[j].Index, logs[j].BlockNumber)
+		}
+
+		event := &Event{Log: logs[j]}
+		if i.abi != nil {
+			// Logs of the events missing from the ABI are kept undecoded.
+			if decoded, err := bytecode.DecodeLogFromAbi(&logs[j], i.abi); err == nil {
+				event.Decoded = decoded
+			}
+		}
+
+		toReturn = append(toReturn, event)
+	}
+
+	return toReturn, nil
+}
+
+// pruneBlocks returns the blocks from the finalized block on, which are still tracked for reorganizations.
+func pruneBlocks(blocks []BlockRef, finalized uint64) []BlockRef {
+	toReturn := make([]BlockRef, 0, len(blocks))
+	for _, block := range blocks {
+		if block.Number >= finalized {
+			toReturn = append(toReturn, block)
+		}
+	}
+	return toReturn
+}
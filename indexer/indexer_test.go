@@ -0,0 +1,404 @@
+package indexer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unpackdev/solgo/clients"
+)
+
+const transferABI = `[{"type":"event","name":"Transfer","anonymous":false,"inputs":[{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"value","type":"uint256","indexed":false}]}]`
+
+var (
+	indexedContract = common.HexToAddress("0x00000000000000000000000000000000000000cc")
+	transferTopic   = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+)
+
+// mockChain is the chain of headers and logs that can be extended and reorganized. Logs are assigned the hash of
+// the canonical block when requested.
+type mockChain struct {
+	mu       sync.Mutex
+	headers  []*types.Header
+	logs     map[uint64][]types.Log
+	maxRange uint64
+	queries  [][2]uint64
+	failures int
+	mangle   bool
+}
+
+func newMockChain(length uint64) *mockChain {
+	toReturn := &mockChain{logs: make(map[uint64][]types.Log)}
+	toReturn.extend(length+1, 0)
+	return toReturn
+}
+
+// extend appends the blocks of the fork on top of the chain.
+func (c *mockChain) extend(count uint64, fork byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for j := uint64(0); j < count; j++ {
+		header := &types.Header{Number: big.NewInt(int64(len(c.headers))), Extra: []byte{fork}}
+		if len(c.headers) > 0 {
+			header.ParentHash = c.headers[len(c.headers)-1].Hash()
+		}
+		c.headers = append(c.headers, header)
+	}
+}
+
+// reorg replaces the blocks from the block number with the blocks of the fork, dropping their logs.
+func (c *mockChain) reorg(from uint64, count uint64, fork byte) {
+	c.mu.Lock()
+	for number := range c.logs {
+		if number >= from {
+			delete(c.logs, number)
+		}
+	}
+	c.headers = c.headers[:from]
+	c.mu.Unlock()
+
+	c.extend(count, fork)
+}
+
+// addTransfer adds the Transfer log of the value to the block.
+func (c *mockChain) addTransfer(number uint64, value int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.logs[number] = append(c.logs[number], types.Log{
+		Address:     indexedContract,
+		Topics:      []common.Hash{transferTopic, common.BytesToHash([]byte{1}), common.BytesToHash([]byte{2})},
+		Data:        common.BigToHash(big.NewInt(value)).Bytes(),
+		BlockNumber: number,
+		Index:       uint(len(c.logs[number])),
+	})
+}
+
+func (c *mockChain) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	from, to := query.FromBlock.Uint64(), query.ToBlock.Uint64()
+	if c.maxRange != 0 && to-from+1 > c.maxRange {
+		c.failures++
+		return nil, fmt.Errorf("query returned more than 10000 results")
+	}
+	c.queries = append(c.queries, [2]uint64{from, to})
+
+	toReturn := make([]types.Log, 0)
+	// Returned from the latest block, as the indexer must not rely on the order of the logs.
+	for number := to; number >= from && number < uint64(len(c.headers)); number-- {
+		for _, log := range c.logs[number] {
+			log.BlockHash = c.headers[number].Hash()
+			if c.mangle {
+				log.BlockHash = common.Hash{}
+			}
+			toReturn = append(toReturn, log)
+		}
+		if number == 0 {
+			break
+		}
+	}
+	return toReturn, nil
+}
+
+func (c *mockChain) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if number == nil {
+		return c.headers[len(c.headers)-1], nil
+	}
+
+	if number.Uint64() >= uint64(len(c.headers)) {
+		return nil, ethereum.NotFound
+	}
+	return c.headers[number.Uint64()], nil
+}
+
+// values returns the values of the Transfer events in the order they are stored.
+func values(t *testing.T, events []*Event) []int64 {
+	toReturn := make([]int64, 0, len(events))
+	for _, event := range events {
+		toReturn = append(toReturn, new(big.Int).SetBytes(event.Log.Data).Int64())
+	}
+	return toReturn
+}
+
+func TestLogIndexerBackfill(t *testing.T) {
+	tests := []struct {
+		name      string
+		batchSize uint64
+		maxRange  uint64
+		queries   int
+		failures  int
+	}{
+		{name: "Single Range", batchSize: 200, queries: 1},
+		{name: "Batched Ranges", batchSize: 25, queries: 4},
+		{name: "Split Ranges", batchSize: 100, maxRange: 30, queries: 4, failures: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chain := newMockChain(120)
+			chain.maxRange = tt.maxRange
+			chain.addTransfer(10, 1)
+			chain.addTransfer(10, 2)
+			chain.addTransfer(55, 3)
+			chain.addTransfer(99, 4)
+			chain.addTransfer(110, 5)
+
+			sink := NewMemorySink()
+			indexer, err := NewLogIndexer(context.Background(), chain, sink, &Options{
+				Addresses: []common.Address{indexedContract},
+				Topics:    [][]common.Hash{{transferTopic}},
+				ABI:       transferABI,
+				ToBlock:   99,
+				BatchSize: tt.batchSize,
+			})
+			require.NoError(t, err)
+
+			done, err := indexer.Sync(context.Background())
+			require.NoError(t, err)
+			assert.True(t, done)
+			assert.Len(t, chain.queries, tt.queries)
+			assert.Equal(t, tt.failures, chain.failures)
+			for _, query := range chain.queries {
+				if tt.maxRange != 0 {
+					assert.LessOrEqual(t, query[1]-query[0]+1, tt.maxRange)
+				}
+			}
+
+			events := sink.GetEvents()
+			assert.Equal(t, []int64{1, 2, 3, 4}, values(t, events))
+			assert.Equal(t, events, sink.GetFinalizedEvents())
+			require.NotNil(t, events[0].Decoded)
+			assert.Equal(t, "Transfer", events[0].Decoded.Name)
+
+			checkpoint := indexer.GetCheckpoint()
+			assert.Equal(t, uint64(99), checkpoint.BlockNumber)
+			assert.Equal(t, uint64(99), checkpoint.Finalized)
+			assert.Empty(t, checkpoint.Blocks)
+		})
+	}
+}
+
+func TestLogIndexerReorg(t *testing.T) {
+	chain := newMockChain(30)
+	chain.addTransfer(20, 1)
+	chain.addTransfer(27, 2)
+	chain.addTransfer(28, 3)
+
+	sink := NewMemorySink()
+	indexer, err := NewLogIndexer(context.Background(), chain, sink, &Options{Confirmations: 5})
+	require.NoError(t, err)
+
+	done, err := indexer.Sync(context.Background())
+	require.NoError(t, err)
+	assert.False(t, done)
+	assert.Equal(t, []int64{1, 2, 3}, values(t, sink.GetEvents()))
+	assert.Equal(t, []int64{1}, values(t, sink.GetFinalizedEvents()))
+	assert.Len(t, indexer.GetCheckpoint().Blocks, 6)
+
+	// Blocks from 28 on are replaced by the longer fork, with the log moved to the later block.
+	chain.reorg(28, 4, 1)
+	chain.addTransfer(29, 4)
+
+	_, err = indexer.Sync(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 2, 4}, values(t, sink.GetEvents()))
+	assert.Equal(t, uint64(31), indexer.GetCheckpoint().BlockNumber)
+	assert.Equal(t, uint64(26), indexer.GetCheckpoint().Finalized)
+
+	// Chain reorganized to the shorter fork, dropping the rest of the unfinalized blocks.
+	chain.reorg(27, 2, 2)
+
+	_, err = indexer.Sync(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1}, values(t, sink.GetEvents()))
+	assert.Equal(t, uint64(28), indexer.GetCheckpoint().BlockNumber)
+
+	// Blocks are finalized as the chain head advances.
+	chain.extend(10, 2)
+	_, err = indexer.Sync(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(38), indexer.GetCheckpoint().BlockNumber)
+	assert.Equal(t, uint64(33), indexer.GetCheckpoint().Finalized)
+	assert.Equal(t, uint64(33), indexer.GetCheckpoint().Blocks[0].Number)
+
+	// Reorganization of the finalized blocks cannot be handled.
+	chain.reorg(30, 10, 3)
+	_, err = indexer.Sync(context.Background())
+	assert.ErrorIs(t, err, ErrReorgTooDeep)
+}
+
+func TestLogIndexerInconsistentLogs(t *testing.T) {
+	chain := newMockChain(10)
+	chain.addTransfer(8, 1)
+	chain.mangle = true
+
+	sink := NewMemorySink()
+	indexer, err := NewLogIndexer(context.Background(), chain, sink, &Options{Confirmations: 5})
+	require.NoError(t, err)
+
+	_, err = indexer.Sync(context.Background())
+	assert.ErrorIs(t, err, ErrInconsistentLogs)
+	assert.Nil(t, indexer.GetCheckpoint())
+
+	chain.mangle = false
+	_, err = indexer.Sync(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1}, values(t, sink.GetEvents()))
+}
+
+func TestLogIndexerRun(t *testing.T) {
+	chain := newMockChain(50)
+	chain.addTransfer(40, 1)
+
+	indexer, err := NewLogIndexer(context.Background(), chain, NewMemorySink(), &Options{ToBlock: 45, Confirmations: 10, PollInterval: 1})
+	require.NoError(t, err)
+
+	go func() {
+		// Finalizes the last block while the indexer polls the chain head.
+		chain.extend(10, 0)
+	}()
+
+	require.NoError(t, indexer.Run(context.Background()))
+	assert.GreaterOrEqual(t, indexer.GetCheckpoint().Finalized, uint64(45))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	following, err := NewLogIndexer(context.Background(), chain, NewMemorySink(), &Options{PollInterval: 1})
+	require.NoError(t, err)
+	assert.ErrorIs(t, following.Run(ctx), context.Canceled)
+}
+
+func TestNewLogIndexer(t *testing.T) {
+	chain := newMockChain(1)
+
+	_, err := NewLogIndexer(context.Background(), nil, NewMemorySink(), nil)
+	assert.ErrorIs(t, err, ErrInvalidBackend)
+
+	_, err = NewLogIndexer(context.Background(), chain, nil, nil)
+	assert.ErrorIs(t, err, ErrInvalidSink)
+
+	_, err = NewLogIndexer(context.Background(), chain, NewMemorySink(), &Options{ABI: "invalid"})
+	assert.Error(t, err)
+
+	_, err = NewLogIndexer(context.Background(), chain, NewMemorySink(), &Options{FromBlock: 10, ToBlock: 5})
+	assert.Error(t, err)
+}
+
+func TestIsRangeTooLarge(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected bool
+	}{
+		{err: nil, expected: false},
+		{err: errors.New("query returned more than 10000 results"), expected: true},
+		{err: errors.New("Log response size exceeded. You can make eth_getLogs requests with up to a 2K block range"), expected: true},
+		{err: errors.New("exceed maximum block range: 5000"), expected: true},
+		{err: errors.New("connection refused"), expected: false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, IsRangeTooLarge(tt.err), fmt.Sprint(tt.err))
+	}
+}
+
+// newHeaderNode serves the headers of its own fork, which can be made to fail, along with the network ID.
+func newHeaderNode(t *testing.T, fork string, failing *atomic.Bool) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() {
+			http.Error(w, "service unavailable", http.StatusServiceUnavailable)
+			return
+		}
+
+		var req struct {
+			ID     json.RawMessage   `json:"id"`
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		var result interface{} = "1"
+		if req.Method == "eth_getBlockByNumber" {
+			var number hexutil.Big
+			require.NoError(t, json.Unmarshal(req.Params[0], &number))
+			result = &types.Header{Number: number.ToInt(), Difficulty: big.NewInt(0), Extra: []byte(fork)}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": result}))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestPoolBackend(t *testing.T) {
+	var firstFailing, secondFailing atomic.Bool
+	first, second := newHeaderNode(t, "first", &firstFailing), newHeaderNode(t, "second", &secondFailing)
+
+	pool, err := clients.NewClientPool(context.Background(), &clients.Options{
+		Nodes: []clients.Node{
+			{Group: "eth", Type: "full", Endpoint: first.URL, ConcurrentClients: 1, NetworkId: 1},
+			{Group: "eth", Type: "full", Endpoint: second.URL, ConcurrentClients: 1, NetworkId: 1},
+		},
+		HealthCheckInterval: -1,
+	})
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+
+	backend, err := NewPoolBackend(pool, "eth")
+	require.NoError(t, err)
+	assert.Nil(t, backend.GetPinnedClient())
+
+	// Headers come from the single node, even though the clients of the group are picked in turns.
+	header, err := backend.HeaderByNumber(context.Background(), big.NewInt(1))
+	require.NoError(t, err)
+	pinned := backend.GetPinnedClient()
+	require.NotNil(t, pinned)
+
+	for i := 0; i < 4; i++ {
+		next, err := backend.HeaderByNumber(context.Background(), big.NewInt(1))
+		require.NoError(t, err)
+		assert.Equal(t, header.Hash(), next.Hash())
+	}
+	assert.Same(t, pinned, backend.GetPinnedClient())
+
+	// The failing node is unpinned, and the headers come from the other node from then on.
+	failing, other := &firstFailing, "second"
+	if pinned.GetEndpoint() == second.URL {
+		failing, other = &secondFailing, "first"
+	}
+	failing.Store(true)
+
+	_, err = backend.HeaderByNumber(context.Background(), big.NewInt(1))
+	assert.Error(t, err)
+	assert.Nil(t, backend.GetPinnedClient())
+
+	for attempt := 0; attempt < 2; attempt++ {
+		header, err = backend.HeaderByNumber(context.Background(), big.NewInt(1))
+		if err == nil {
+			break
+		}
+	}
+	require.NoError(t, err)
+	assert.Equal(t, []byte(other), header.Extra)
+	assert.NotSame(t, pinned, backend.GetPinnedClient())
+}
@@ -0,0 +1,354 @@
+package indexer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/goccy/go-json"
+	"github.com/unpackdev/solgo/bytecode"
+	"github.com/unpackdev/solgo/utils"
+)
+
+// Event represents the indexed log, decoded when the log is described by the indexer ABI.
+type Event struct {
+	Log     types.Log     `json:"log"`               // Raw log as returned by the node.
+	Decoded *bytecode.Log `json:"decoded,omitempty"` // Decoded log, nil when the log is not described by the ABI.
+}
+
+// BlockRef identifies the block by its number and hash.
+type BlockRef struct {
+	Number uint64      `json:"number"` // Number of the block.
+	Hash   common.Hash `json:"hash"`   // Hash of the block.
+}
+
+// Checkpoint is the indexing progress the indexer resumes from.
+type Checkpoint struct {
+	BlockNumber uint64     `json:"block_number"` // Last indexed block.
+	Finalized   uint64     `json:"finalized"`    // Last indexed block with the required number of confirmations.
+	Blocks      []BlockRef `json:"blocks"`       // Indexed blocks that are not finalized yet, in ascending order.
+}
+
+// Sink persists the indexed events together with the checkpoint. Sinks have to store the events and the
+// checkpoint atomically, or recover the events consistent with the checkpoint after the failure.
+type Sink interface {
+	// Write stores the events of the blocks up to the block of the checkpoint, and the checkpoint itself.
+	Write(ctx context.Context, events []*Event, checkpoint *Checkpoint) error
+
+	// Rollback removes the events of the blocks after the block of the checkpoint, which were reorganized out of
+	// the chain, and stores the checkpoint.
+	Rollback(ctx context.Context, checkpoint *Checkpoint) error
+
+	// GetCheckpoint returns the stored checkpoint, or nil when nothing was indexed yet.
+	GetCheckpoint(ctx context.Context) (*Checkpoint, error)
+}
+
+// MemorySink keeps the indexed events in memory.
+type MemorySink struct {
+	mu         sync.RWMutex
+	events     []*Event
+	checkpoint *Checkpoint
+}
+
+// NewMemorySink creates the empty in-memory sink.
+func NewMemorySink() *MemorySink {
+	return &MemorySink{events: make([]*Event, 0)}
+}
+
+// Write appends the events and stores the checkpoint.
+func (s *MemorySink) Write(ctx context.Context, events []*Event, checkpoint *Checkpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.events = append(s.events, events...)
+	s.checkpoint = checkpoint
+	return nil
+}
+
+// Rollback removes the events after the block of the checkpoint and stores the checkpoint.
+func (s *MemorySink) Rollback(ctx context.Context, checkpoint *Checkpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.events = filterEvents(s.events, checkpoint.BlockNumber)
+	s.checkpoint = checkpoint
+	return nil
+}
+
+// GetCheckpoint returns the stored checkpoint.
+func (s *MemorySink) GetCheckpoint(ctx context.Context) (*Checkpoint, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.checkpoint, nil
+}
+
+// GetEvents returns all the stored events, including the events of the blocks that are not finalized yet.
+func (s *MemorySink) GetEvents() []*Event {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	toReturn := make([]*Event, len(s.events))
+	copy(toReturn, s.events)
+	return toReturn
+}
+
+// GetFinalizedEvents returns the stored events of the finalized blocks.
+func (s *MemorySink) GetFinalizedEvents() []*Event {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.checkpoint == nil {
+		return []*Event{}
+	}
+	return filterEvents(s.events, s.checkpoint.Finalized)
+}
+
+// FileSink stores the indexed events in the directory, as the JSON lines of the events file next to the
+// checkpoint file.
+type FileSink struct {
+	mu             sync.Mutex
+	eventsPath     string
+	checkpointPath string
+}
+
+// NewFileSink creates the sink storing the events in the directory, which is created when it does not exist.
+// Events written after the stored checkpoint, left behind by the interrupted write, are removed, along with the
+// incomplete trailing line of the event the write was interrupted in.
+func NewFileSink(dir string) (*FileSink, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create sink directory: %w", err)
+	}
+
+	toReturn := &FileSink{
+		eventsPath:     filepath.Join(dir, "events.jsonl"),
+		checkpointPath: filepath.Join(dir, "checkpoint.json"),
+	}
+
+	checkpoint, err := toReturn.GetCheckpoint(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	if checkpoint == nil && !fileExists(toReturn.eventsPath) {
+		return toReturn, nil
+	}
+
+	events, partial, err := toReturn.readEvents()
+	if err != nil {
+		return nil, err
+	}
+
+	kept := make([]*Event, 0)
+	if checkpoint != nil {
+		kept = filterEvents(events, checkpoint.BlockNumber)
+	}
+
+	if partial || len(kept) != len(events) {
+		if err := toReturn.rewriteEvents(kept); err != nil {
+			return nil, err
+		}
+	}
+
+	return toReturn, nil
+}
+
+// Write appends the events to the events file and replaces the checkpoint file.
+func (s *FileSink) Write(ctx context.Context, events []*Event, checkpoint *Checkpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.OpenFile(s.eventsPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open events file: %w", err)
+	}
+
+	if err := writeEvents(file, events); err != nil {
+		file.Close()
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to close events file: %w", err)
+	}
+
+	return s.saveCheckpoint(checkpoint)
+}
+
+// Rollback rewrites the events file without the events after the block of the checkpoint and replaces the
+// checkpoint file.
+func (s *FileSink) Rollback(ctx context.Context, checkpoint *Checkpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	events, _, err := s.readEvents()
+	if err != nil {
+		return err
+	}
+
+	// The checkpoint is stored first, so the events left behind by the interrupted rollback are removed on open.
+	if err := s.saveCheckpoint(checkpoint); err != nil {
+		return err
+	}
+
+	return s.rewriteEvents(filterEvents(events, checkpoint.BlockNumber))
+}
+
+// GetCheckpoint reads the checkpoint file, returning nil when it does not exist.
+func (s *FileSink) GetCheckpoint(ctx context.Context) (*Checkpoint, error) {
+	data, err := os.ReadFile(s.checkpointPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+
+	toReturn := &Checkpoint{}
+	if err := json.Unmarshal(data, toReturn); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal checkpoint: %w", err)
+	}
+
+	return toReturn, nil
+}
+
+// GetEvents reads all the stored events.
+func (s *FileSink) GetEvents() ([]*Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	events, _, err := s.readEvents()
+	return events, err
+}
+
+// readEvents reads the events file, returning no events when it does not exist. The trailing line without the
+// line break is the event the interrupted write left incomplete, so it is skipped, and reported by the returned
+// boolean.
+func (s *FileSink) readEvents() ([]*Event, bool, error) {
+	toReturn := make([]*Event, 0)
+
+	file, err := os.Open(s.eventsPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return toReturn, false, nil
+		}
+		return nil, false, fmt.Errorf("failed to open events file: %w", err)
+	}
+	defer file.Close()
+
+	reader := bufio.NewReaderSize(file, 64*1024)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			return toReturn, len(line) > 0, nil
+		}
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to read events file: %w", err)
+		}
+
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+
+		// Numbers of the decoded data are kept as json.Number, as the integers exceed the float precision.
+		decoder := json.NewDecoder(bytes.NewReader(line))
+		decoder.UseNumber()
+
+		event := &Event{}
+		if err := decoder.Decode(event); err != nil {
+			return nil, false, fmt.Errorf("failed to unmarshal event: %w", err)
+		}
+		toReturn = append(toReturn, event)
+	}
+}
+
+// rewriteEvents replaces the events file with the events atomically.
+func (s *FileSink) rewriteEvents(events []*Event) error {
+	tmpPath := s.eventsPath + ".tmp"
+
+	file, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open events file: %w", err)
+	}
+
+	if err := writeEvents(file, events); err != nil {
+		file.Close()
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to close events file: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, s.eventsPath); err != nil {
+		return fmt.Errorf("failed to replace events file: %w", err)
+	}
+
+	return nil
+}
+
+// saveCheckpoint replaces the checkpoint file atomically.
+func (s *FileSink) saveCheckpoint(checkpoint *Checkpoint) error {
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return fmt.Errorf("failed to marshal checkpoint: %w", err)
+	}
+
+	tmpPath := s.checkpointPath + ".tmp"
+	if err := utils.WriteToFile(tmpPath, data); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, s.checkpointPath); err != nil {
+		return fmt.Errorf("failed to replace checkpoint: %w", err)
+	}
+
+	return nil
+}
+
+// writeEvents writes the events as the JSON lines.
+func writeEvents(file *os.File, events []*Event) error {
+	writer := bufio.NewWriter(file)
+	for _, event := range events {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("failed to marshal event: %w", err)
+		}
+
+		if _, err := writer.Write(append(data, '\n')); err != nil {
+			return fmt.Errorf("failed to write event: %w", err)
+		}
+	}
+
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("failed to write events: %w", err)
+	}
+
+	return nil
+}
+
+// filterEvents returns the events of the blocks up to the block number.
+func filterEvents(events []*Event, blockNumber uint64) []*Event {
+	toReturn := make([]*Event, 0, len(events))
+	for _, event := range events {
+		if event.Log.BlockNumber <= blockNumber {
+			toReturn = append(toReturn, event)
+		}
+	}
+	return toReturn
+}
+
+// fileExists returns true if the file exists.
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,145 @@
+package indexer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSinks(t *testing.T) {
+	event := func(blockNumber uint64) *Event {
+		return &Event{Log: types.Log{Address: indexedContract, Topics: []common.Hash{transferTopic}, Data: []byte{}, BlockNumber: blockNumber}}
+	}
+
+	tests := []struct {
+		name string
+		sink func(t *testing.T) Sink
+	}{
+		{
+			name: "Memory Sink",
+			sink: func(t *testing.T) Sink { return NewMemorySink() },
+		},
+		{
+			name: "File Sink",
+			sink: func(t *testing.T) Sink {
+				sink, err := NewFileSink(filepath.Join(t.TempDir(), "events"))
+				require.NoError(t, err)
+				return sink
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			sink := tt.sink(t)
+
+			checkpoint, err := sink.GetCheckpoint(ctx)
+			require.NoError(t, err)
+			assert.Nil(t, checkpoint)
+
+			require.NoError(t, sink.Write(ctx, []*Event{event(1), event(5)}, &Checkpoint{BlockNumber: 5, Finalized: 3}))
+			require.NoError(t, sink.Write(ctx, []*Event{event(6), event(9)}, &Checkpoint{BlockNumber: 10, Finalized: 5}))
+			require.NoError(t, sink.Rollback(ctx, &Checkpoint{BlockNumber: 7, Finalized: 5}))
+
+			checkpoint, err = sink.GetCheckpoint(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, &Checkpoint{BlockNumber: 7, Finalized: 5}, checkpoint)
+
+			var events []*Event
+			switch sink := sink.(type) {
+			case *MemorySink:
+				events = sink.GetEvents()
+				assert.Len(t, sink.GetFinalizedEvents(), 2)
+			case *FileSink:
+				events, err = sink.GetEvents()
+				require.NoError(t, err)
+			}
+
+			require.Len(t, events, 3)
+			for j, blockNumber := range []uint64{1, 5, 6} {
+				assert.Equal(t, blockNumber, events[j].Log.BlockNumber)
+			}
+		})
+	}
+}
+
+func TestFileSinkRecovery(t *testing.T) {
+	dir := t.TempDir()
+
+	sink, err := NewFileSink(dir)
+	require.NoError(t, err)
+	require.NoError(t, sink.Write(context.Background(), []*Event{{Log: types.Log{BlockNumber: 3, Topics: []common.Hash{}, Data: []byte{}}}}, &Checkpoint{BlockNumber: 3}))
+
+	// Events appended by the write interrupted before its checkpoint was stored.
+	stray, err := json.Marshal(&Event{Log: types.Log{BlockNumber: 4, Topics: []common.Hash{}, Data: []byte{}}})
+	require.NoError(t, err)
+	file, err := os.OpenFile(filepath.Join(dir, "events.jsonl"), os.O_APPEND|os.O_WRONLY, 0600)
+	require.NoError(t, err)
+	_, err = file.Write(append(stray, '\n'))
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	reopened, err := NewFileSink(dir)
+	require.NoError(t, err)
+	events, err := reopened.GetEvents()
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, uint64(3), events[0].Log.BlockNumber)
+
+	// Event the write was interrupted in, without its line break.
+	file, err = os.OpenFile(filepath.Join(dir, "events.jsonl"), os.O_APPEND|os.O_WRONLY, 0600)
+	require.NoError(t, err)
+	_, err = file.Write(stray[:len(stray)/2])
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	reopened, err = NewFileSink(dir)
+	require.NoError(t, err)
+	require.NoError(t, reopened.Write(context.Background(), []*Event{{Log: types.Log{BlockNumber: 5, Topics: []common.Hash{}, Data: []byte{}}}}, &Checkpoint{BlockNumber: 5}))
+
+	events, err = reopened.GetEvents()
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, uint64(3), events[0].Log.BlockNumber)
+	assert.Equal(t, uint64(5), events[1].Log.BlockNumber)
+}
+
+func TestLogIndexerFileSinkResume(t *testing.T) {
+	dir := t.TempDir()
+	chain := newMockChain(40)
+	chain.addTransfer(10, 1)
+	chain.addTransfer(30, 2)
+
+	sink, err := NewFileSink(dir)
+	require.NoError(t, err)
+	indexer, err := NewLogIndexer(context.Background(), chain, sink, &Options{ABI: transferABI, ToBlock: 20})
+	require.NoError(t, err)
+	_, err = indexer.Sync(context.Background())
+	require.NoError(t, err)
+
+	sink, err = NewFileSink(dir)
+	require.NoError(t, err)
+	resumed, err := NewLogIndexer(context.Background(), chain, sink, &Options{ABI: transferABI, ToBlock: 40})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(20), resumed.GetCheckpoint().BlockNumber)
+
+	done, err := resumed.Sync(context.Background())
+	require.NoError(t, err)
+	assert.True(t, done)
+
+	events, err := sink.GetEvents()
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 2}, values(t, events))
+
+	// Decoded values are kept as exact numbers when read back.
+	require.NotNil(t, events[1].Decoded)
+	assert.Equal(t, json.Number("2"), events[1].Decoded.Data["value"])
+}
@@ -0,0 +1,286 @@
+package ir
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	ast_pb "github.com/unpackdev/protos/dist/go/ast"
+	"github.com/unpackdev/solgo/ast"
+	"github.com/unpackdev/solgo/utils"
+)
+
+// AssemblyFindingKind represents the kind of the issue discovered within the inline assembly (Yul) block.
+type AssemblyFindingKind string
+
+const (
+	// AssemblyUncheckedCall is reported when the success flag of call, delegatecall, staticcall or callcode
+	// is discarded through pop() or stored into a variable that is never read.
+	AssemblyUncheckedCall AssemblyFindingKind = "unchecked_call"
+
+	// AssemblyUnsafeMemoryWrite is reported when memory is written at fixed offsets outside of the scratch
+	// space, the free memory pointer or the zero slot are overwritten or the scratch space is overflown.
+	AssemblyUnsafeMemoryWrite AssemblyFindingKind = "unsafe_memory_write"
+
+	// AssemblyReturnDataCopyMisuse is reported when returndatacopy copies data without checking the
+	// returndatasize() or when it copies out of the return data bounds.
+	AssemblyReturnDataCopyMisuse AssemblyFindingKind = "returndatacopy_misuse"
+)
+
+// String returns the string representation of the AssemblyFindingKind.
+func (k AssemblyFindingKind) String() string {
+	return string(k)
+}
+
+// eip1967Slots maps the EIP-1967 proxy storage slots, defined as keccak256(label) - 1, to their names.
+var eip1967Slots = map[string]string{
+	eip1967Slot("eip1967.proxy.implementation"): "implementation",
+	eip1967Slot("eip1967.proxy.admin"):          "admin",
+	eip1967Slot("eip1967.proxy.beacon"):         "beacon",
+	eip1967Slot("eip1967.proxy.rollback"):       "rollback",
+}
+
+// eip1967Slot returns the hex encoded EIP-1967 storage slot for the provided label.
+func eip1967Slot(label string) string {
+	slot := new(big.Int).SetBytes(utils.Keccak256([]byte(label)))
+	return common.BigToHash(slot.Sub(slot, big.NewInt(1))).Hex()
+}
+
+// AssemblyFinding represents an issue discovered within the inline assembly block.
+type AssemblyFinding struct {
+	Kind    AssemblyFindingKind `json:"kind"`    // Kind of the finding.
+	Opcode  string              `json:"opcode"`  // Opcode (Yul builtin) the finding relates to.
+	Message string              `json:"message"` // Human readable description of the finding.
+	Src     ast.SrcNode         `json:"src"`     // Source location of the offending call.
+}
+
+// GetKind returns the kind of the finding.
+func (f *AssemblyFinding) GetKind() AssemblyFindingKind {
+	return f.Kind
+}
+
+// GetOpcode returns the opcode the finding relates to.
+func (f *AssemblyFinding) GetOpcode() string {
+	return f.Opcode
+}
+
+// GetMessage returns the description of the finding.
+func (f *AssemblyFinding) GetMessage() string {
+	return f.Message
+}
+
+// GetSrc returns the source location of the finding.
+func (f *AssemblyFinding) GetSrc() ast.SrcNode {
+	return f.Src
+}
+
+// AssemblyStorageAccess represents a raw storage read or write (sload, sstore, tload, tstore) performed
+// within the inline assembly block, resolved to the storage variable or well known slot where possible.
+type AssemblyStorageAccess struct {
+	Opcode     string      `json:"opcode"`                // Opcode performing the access.
+	Slot       string      `json:"slot,omitempty"`        // Hex encoded slot, set when the slot could be computed.
+	VariableId int64       `json:"variable_id,omitempty"` // Id of the state variable occupying the slot.
+	Variable   string      `json:"variable,omitempty"`    // Name of the state variable occupying the slot.
+	Standard   string      `json:"standard,omitempty"`    // Standard defining the slot, e.g. EIP1967.
+	Label      string      `json:"label,omitempty"`       // Name of the slot within the standard, e.g. implementation.
+	Derived    bool        `json:"derived"`               // Slot is derived from the variable slot, e.g. mapping value or array element.
+	Src        ast.SrcNode `json:"src"`                   // Source location of the access.
+}
+
+// GetOpcode returns the opcode performing the access.
+func (a *AssemblyStorageAccess) GetOpcode() string {
+	return a.Opcode
+}
+
+// GetSlot returns the hex encoded slot or an empty string if the slot could not be computed.
+func (a *AssemblyStorageAccess) GetSlot() string {
+	return a.Slot
+}
+
+// GetVariableId returns the id of the state variable occupying the slot.
+func (a *AssemblyStorageAccess) GetVariableId() int64 {
+	return a.VariableId
+}
+
+// GetVariable returns the name of the state variable occupying the slot.
+func (a *AssemblyStorageAccess) GetVariable() string {
+	return a.Variable
+}
+
+// GetStandard returns the standard defining the slot, if any.
+func (a *AssemblyStorageAccess) GetStandard() string {
+	return a.Standard
+}
+
+// GetLabel returns the name of the slot within the standard, if any.
+func (a *AssemblyStorageAccess) GetLabel() string {
+	return a.Label
+}
+
+// IsDerived returns true if the slot is derived from the variable slot, e.g. mapping value or array element.
+func (a *AssemblyStorageAccess) IsDerived() bool {
+	return a.Derived
+}
+
+// IsTransient returns true if the access targets the transient storage.
+func (a *AssemblyStorageAccess) IsTransient() bool {
+	return a.Opcode == "tload" || a.Opcode == "tstore"
+}
+
+// IsResolved returns true if the access is matched against the state variable or well known slot.
+func (a *AssemblyStorageAccess) IsResolved() bool {
+	return a.Variable != "" || a.Standard != ""
+}
+
+// GetSrc returns the source location of the access.
+func (a *AssemblyStorageAccess) GetSrc() ast.SrcNode {
+	return a.Src
+}
+
+// AssemblyBlock represents the summary of the inline assembly block found within the function.
+type AssemblyBlock struct {
+	Unit          *ast.Yul                 `json:"ast"`
+	Id            int64                    `json:"id"`             // Id of the assembly statement.
+	NodeType      ast_pb.NodeType          `json:"node_type"`      // Type of the assembly statement node.
+	MemorySafe    bool                     `json:"memory_safe"`    // Block is annotated as memory-safe.
+	StorageReads  []*AssemblyStorageAccess `json:"storage_reads"`  // Storage and transient storage reads.
+	StorageWrites []*AssemblyStorageAccess `json:"storage_writes"` // Storage and transient storage writes.
+	Calls         []string                 `json:"calls"`          // External call and contract creation opcodes used.
+	Findings      []*AssemblyFinding       `json:"findings"`       // Issues discovered within the block.
+	Src           ast.SrcNode              `json:"src"`            // Source location of the block.
+}
+
+// GetAST returns the underlying AST node of the assembly block.
+func (a *AssemblyBlock) GetAST() *ast.Yul {
+	return a.Unit
+}
+
+// GetId returns the id of the assembly statement.
+func (a *AssemblyBlock) GetId() int64 {
+	return a.Id
+}
+
+// GetNodeType returns the type of the assembly statement node.
+func (a *AssemblyBlock) GetNodeType() ast_pb.NodeType {
+	return a.NodeType
+}
+
+// IsMemorySafe returns true if the block is annotated as memory-safe.
+func (a *AssemblyBlock) IsMemorySafe() bool {
+	return a.MemorySafe
+}
+
+// GetStorageReads returns the storage reads performed within the block.
+func (a *AssemblyBlock) GetStorageReads() []*AssemblyStorageAccess {
+	return a.StorageReads
+}
+
+// GetStorageWrites returns the storage writes performed within the block.
+func (a *AssemblyBlock) GetStorageWrites() []*AssemblyStorageAccess {
+	return a.StorageWrites
+}
+
+// GetCalls returns the external call and contract creation opcodes used within the block.
+func (a *AssemblyBlock) GetCalls() []string {
+	return a.Calls
+}
+
+// GetFindings returns the issues discovered within the block.
+func (a *AssemblyBlock) GetFindings() []*AssemblyFinding {
+	return a.Findings
+}
+
+// GetFindingsByKind returns the issues of the provided kind discovered within the block.
+func (a *AssemblyBlock) GetFindingsByKind(kind AssemblyFindingKind) []*AssemblyFinding {
+	toReturn := make([]*AssemblyFinding, 0)
+	for _, finding := range a.Findings {
+		if finding.Kind == kind {
+			toReturn = append(toReturn, finding)
+		}
+	}
+	return toReturn
+}
+
+// GetSrc returns the source location of the block.
+func (a *AssemblyBlock) GetSrc() ast.SrcNode {
+	return a.Src
+}
+
+// processAssembly analyzes inline assembly blocks of every function, constructor, fallback and receive within
+// the RootSourceUnit and attaches the read/write summaries and findings to them. Blocks of the modifiers are
+// attached to every callable invoking the modifier, as their code is executed as part of it.
+func (b *Builder) processAssembly(root *RootSourceUnit) {
+	if b.astBuilder == nil {
+		return
+	}
+
+	constants := make(map[int64]*StateVariable)
+	for _, contract := range root.GetContracts() {
+		for _, stateVar := range contract.GetStateVariables() {
+			if stateVar.IsConstant() {
+				constants[stateVar.GetId()] = stateVar
+			}
+		}
+	}
+
+	var source []rune
+	if b.sources != nil {
+		source = []rune(b.sources.GetCombinedSource())
+	}
+
+	for _, contract := range root.GetContracts() {
+		analyzer := newAssemblyAnalyzer(root, contract, constants, source)
+
+		for _, function := range contract.GetFunctions() {
+			if function.GetAST() != nil {
+				function.Assembly = b.analyzeCallable(analyzer, function.GetAST().GetBody(), function.GetModifiers())
+			}
+		}
+
+		if constructor := contract.GetConstructor(); constructor != nil && constructor.GetAST() != nil {
+			constructor.Assembly = b.analyzeCallable(analyzer, constructor.GetAST().GetBody(), constructor.GetModifiers())
+		}
+
+		if fallback := contract.GetFallback(); fallback != nil && fallback.GetAST() != nil {
+			fallback.Assembly = b.analyzeCallable(analyzer, fallback.GetAST().GetBody(), fallback.GetModifiers())
+		}
+
+		if receive := contract.GetReceive(); receive != nil && receive.GetAST() != nil {
+			receive.Assembly = b.analyzeCallable(analyzer, receive.GetAST().GetBody(), receive.GetModifiers())
+		}
+	}
+}
+
+// analyzeCallable analyzes the assembly blocks of the modifiers invoked by the callable, followed by the blocks
+// of the callable body itself.
+func (b *Builder) analyzeCallable(analyzer *assemblyAnalyzer, body *ast.BodyNode, modifiers []*Modifier) []*AssemblyBlock {
+	var toReturn []*AssemblyBlock
+
+	for _, modifier := range modifiers {
+		if definition := analyzer.getModifier(modifier.GetName()); definition != nil {
+			toReturn = append(toReturn, b.analyzeBody(analyzer, definition.GetBody())...)
+		}
+	}
+
+	return append(toReturn, b.analyzeBody(analyzer, body)...)
+}
+
+// analyzeBody analyzes the assembly blocks found within the body.
+func (b *Builder) analyzeBody(analyzer *assemblyAnalyzer, body *ast.BodyNode) []*AssemblyBlock {
+	var toReturn []*AssemblyBlock
+	if body == nil {
+		return toReturn
+	}
+
+	_, _ = b.astBuilder.GetTree().ExecuteCustomTypeVisit(
+		body.GetNodes(),
+		ast_pb.NodeType_ASSEMBLY_STATEMENT,
+		func(node ast.Node[ast.NodeType]) (bool, error) {
+			if yul, ok := node.(*ast.Yul); ok {
+				toReturn = append(toReturn, analyzer.analyze(body, yul))
+			}
+			return true, nil
+		},
+	)
+
+	return toReturn
+}
@@ -0,0 +1,967 @@
+package ir
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	ast_pb "github.com/unpackdev/protos/dist/go/ast"
+	"github.com/unpackdev/solgo/ast"
+	"github.com/unpackdev/solgo/utils"
+)
+
+var (
+	// wordModulus is used to wrap around constant arithmetic at the 256 bit boundary, same as the EVM does.
+	wordModulus = new(big.Int).Lsh(big.NewInt(1), 256)
+
+	// scratchSpaceEnd, freeMemoryPointer and zeroSlot are the Solidity reserved memory offsets.
+	scratchSpaceEnd   = big.NewInt(0x40)
+	freeMemoryPointer = big.NewInt(0x40)
+	zeroSlot          = big.NewInt(0x60)
+	reservedMemoryEnd = big.NewInt(0x80)
+)
+
+// yulValue is the abstract value of the Yul expression as tracked by the assembly analyzer.
+type yulValue struct {
+	constant       *big.Int       // Known constant value.
+	variable       *StateVariable // Storage variable the value (slot) originates from.
+	derived        bool           // Slot is derived from the variable slot, e.g. mapping value or array element.
+	freePointer    bool           // Value originates from the free memory pointer.
+	returnDataSize bool           // Value is the returndatasize().
+	call           string         // External call opcode when the value is its success flag.
+}
+
+// pendingCall is the success flag of an external call stored into the Yul variable and not yet checked.
+type pendingCall struct {
+	opcode string
+	src    ast.SrcNode
+}
+
+// assemblyAnalyzer evaluates inline assembly blocks of a single contract.
+type assemblyAnalyzer struct {
+	root      *RootSourceUnit
+	contracts []*Contract              // Linearized contract along with its base contracts, most base first.
+	source    []rune                   // Combined source the AST source locations point into, if known.
+	constants map[int64]*StateVariable // Constant state variables across all of the contracts, by id.
+	variables map[string]*StateVariable
+	layout    map[string]int64         // Storage slots of the state variables, by name.
+	slots     map[int64]*StateVariable // First state variable occupying the storage slot.
+}
+
+// assemblyScope holds the state of the single assembly block (or Yul function) evaluation.
+type assemblyScope struct {
+	block          *AssemblyBlock
+	locals         map[string]ast.Node[ast.NodeType] // Solidity local variables initial values.
+	values         map[string]*yulValue              // Yul variables values.
+	declared       map[string]bool                   // Yul variables declared within the block.
+	memory         map[string]*yulValue              // Words stored at constant memory offsets.
+	pending        map[string]*pendingCall           // Unchecked external call success flags.
+	checkedRetSize bool                              // returndatasize() was inspected since the last call.
+}
+
+// newAssemblyAnalyzer creates a new analyzer for the contract and computes its storage layout.
+func newAssemblyAnalyzer(root *RootSourceUnit, contract *Contract, constants map[int64]*StateVariable, source []rune) *assemblyAnalyzer {
+	toReturn := &assemblyAnalyzer{
+		root:      root,
+		contracts: linearizeContracts(root, contract),
+		source:    source,
+		constants: constants,
+		variables: make(map[string]*StateVariable),
+		layout:    make(map[string]int64),
+		slots:     make(map[int64]*StateVariable),
+	}
+
+	toReturn.computeLayout(toReturn.contracts)
+	return toReturn
+}
+
+// linearizeContracts returns the contract along with its base contracts, most base first, which is
+// the order in which the state variables are placed into the storage.
+func linearizeContracts(root *RootSourceUnit, contract *Contract) []*Contract {
+	linearization := c3Linearization(root, contract, make(map[*Contract]bool))
+
+	toReturn := make([]*Contract, 0, len(linearization))
+	for i := len(linearization) - 1; i >= 0; i-- {
+		toReturn = append(toReturn, linearization[i])
+	}
+
+	return toReturn
+}
+
+// c3Linearization returns the C3 linearization of the contract, most derived first, the same way the
+// Solidity compiler resolves the inheritance. Base contracts are listed from the most base to the most
+// derived one, hence they are merged in the reverse order. Contracts that cannot be found within the
+// root are skipped, as is the remainder of the inconsistent hierarchy the compiler would reject.
+func c3Linearization(root *RootSourceUnit, contract *Contract, visiting map[*Contract]bool) []*Contract {
+	if contract == nil || visiting[contract] {
+		return nil
+	}
+	visiting[contract] = true
+	defer delete(visiting, contract)
+
+	sequences := make([][]*Contract, 0)
+	bases := make([]*Contract, 0)
+	baseContracts := contract.GetBaseContracts()
+	for i := len(baseContracts) - 1; i >= 0; i-- {
+		if baseContracts[i].GetBaseName() == nil {
+			continue
+		}
+
+		base := root.GetContractByName(baseContracts[i].GetBaseName().Name)
+		if base == nil || base == contract {
+			continue
+		}
+
+		bases = append(bases, base)
+		sequences = append(sequences, c3Linearization(root, base, visiting))
+	}
+	sequences = append(sequences, bases)
+
+	toReturn := []*Contract{contract}
+	for {
+		var head *Contract
+		for _, sequence := range sequences {
+			if len(sequence) > 0 && !inSequenceTails(sequence[0], sequences) {
+				head = sequence[0]
+				break
+			}
+		}
+
+		if head == nil {
+			return toReturn
+		}
+
+		toReturn = append(toReturn, head)
+		for i, sequence := range sequences {
+			if len(sequence) > 0 && sequence[0] == head {
+				sequences[i] = sequence[1:]
+			}
+		}
+	}
+}
+
+// inSequenceTails reports whether the contract appears in the tail of any of the sequences.
+func inSequenceTails(contract *Contract, sequences [][]*Contract) bool {
+	for _, sequence := range sequences {
+		for i := 1; i < len(sequence); i++ {
+			if sequence[i] == contract {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// getModifier returns the definition of the modifier of the name, looked up from the most derived contract to the
+// most base one, so that the overriding modifiers take precedence.
+func (a *assemblyAnalyzer) getModifier(name string) *ast.ModifierDefinition {
+	for i := len(a.contracts) - 1; i >= 0; i-- {
+		if a.contracts[i].GetAST() == nil || a.contracts[i].GetAST().GetContract() == nil {
+			continue
+		}
+
+		for _, node := range a.contracts[i].GetAST().GetContract().GetNodes() {
+			if modifier, ok := node.(*ast.ModifierDefinition); ok && modifier.GetName() == name {
+				return modifier
+			}
+		}
+	}
+	return nil
+}
+
+// slotAllocator places the values into the consecutive storage slots. Values smaller than 32 bytes are packed
+// together, while the values occupying whole slots, such as mappings, arrays and structs, always start and end
+// the slot.
+type slotAllocator struct {
+	slot int64 // Current slot.
+	used int64 // Bytes of the current slot in use.
+}
+
+// allocate places the value of the size, in bytes, and returns the slot it starts at.
+func (s *slotAllocator) allocate(size int64, whole bool) int64 {
+	if s.used > 0 && (whole || s.used+size > 32) {
+		s.slot++
+		s.used = 0
+	}
+
+	start := s.slot
+	if whole {
+		s.slot += (size + 31) / 32
+		s.used = 0
+	} else {
+		s.used += size
+	}
+
+	return start
+}
+
+// count returns the number of the slots in use.
+func (s *slotAllocator) count() int64 {
+	if s.used > 0 {
+		return s.slot + 1
+	}
+	return s.slot
+}
+
+// computeLayout computes the storage slots of the state variables, following the storage layout rules of the
+// Solidity compiler.
+func (a *assemblyAnalyzer) computeLayout(contracts []*Contract) {
+	allocator := &slotAllocator{}
+
+	for _, contract := range contracts {
+		for _, stateVar := range contract.GetStateVariables() {
+			a.variables[stateVar.GetName()] = stateVar
+
+			if stateVar.IsConstant() || stateVar.GetStateMutability() == ast_pb.Mutability_IMMUTABLE {
+				continue
+			}
+
+			var typeName *ast.TypeName
+			if stateVar.GetAST() != nil {
+				typeName = stateVar.GetAST().GetTypeName()
+			}
+
+			size, whole := a.storageSize(a.declaredType(stateVar.GetTypeDescription(), typeName), typeName, 0)
+			slot := allocator.allocate(size, whole)
+
+			a.layout[stateVar.GetName()] = slot
+			if _, exists := a.slots[slot]; !exists {
+				a.slots[slot] = stateVar
+			}
+		}
+	}
+}
+
+// declaredType returns the type string of the state variable or struct member. Type descriptions of the static
+// arrays describe their length expression rather than the array, hence their type is taken from the source.
+func (a *assemblyAnalyzer) declaredType(description *ast.TypeDescription, typeName *ast.TypeName) string {
+	if typeName != nil && typeName.GetSrc().Start >= 0 && typeName.GetSrc().End < int64(len(a.source)) &&
+		typeName.GetSrc().Start <= typeName.GetSrc().End {
+		declared := strings.Join(strings.Fields(string(a.source[typeName.GetSrc().Start:typeName.GetSrc().End+1])), "")
+		if strings.HasSuffix(declared, "]") && !strings.HasSuffix(declared, "[]") {
+			return declared
+		}
+	}
+
+	if description == nil {
+		return ""
+	}
+
+	return description.GetString()
+}
+
+// storageSize returns the size of the type in the storage, in bytes, and whether it occupies whole slots.
+// Sizes of the value types come from the storage size of their type names, while static arrays and structs
+// are sized out of their elements and members.
+func (a *assemblyAnalyzer) storageSize(typeString string, typeName *ast.TypeName, depth int) (int64, bool) {
+	typeString = strings.TrimSpace(typeString)
+	for _, suffix := range []string{" storage ref", " storage pointer", " storage"} {
+		typeString = strings.TrimSuffix(typeString, suffix)
+	}
+
+	switch {
+	case depth > 16:
+		return 32, true
+	case strings.HasPrefix(typeString, "mapping("), typeString == "string", typeString == "bytes":
+		return 32, true
+	case strings.HasSuffix(typeString, "]"):
+		open := strings.LastIndex(typeString, "[")
+		if open < 0 {
+			return 32, true
+		}
+
+		length := a.arrayLength(typeString[open+1 : len(typeString)-1])
+		if length <= 0 {
+			// Dynamic arrays occupy the single slot holding their length.
+			return 32, true
+		}
+
+		elementSize, elementWhole := a.storageSize(typeString[:open], nil, depth+1)
+		if elementWhole || elementSize > 16 {
+			return length * ((elementSize + 31) / 32) * 32, true
+		}
+
+		perSlot := 32 / elementSize
+		return ((length + perSlot - 1) / perSlot) * 32, true
+	case strings.HasPrefix(typeString, "struct "), a.getStruct(typeString) != nil:
+		structNode := a.getStruct(strings.TrimPrefix(typeString, "struct "))
+		if structNode == nil {
+			return 32, true
+		}
+
+		allocator := &slotAllocator{}
+		for _, member := range structNode.GetMembers() {
+			var memberTypeName *ast.TypeName
+			if member.GetAST() != nil {
+				memberTypeName = member.GetAST().GetTypeName()
+			}
+			allocator.allocate(a.storageSize(a.declaredType(member.GetTypeDescription(), memberTypeName), memberTypeName, depth+1))
+		}
+
+		return max(allocator.count(), 1) * 32, true
+	case strings.HasPrefix(typeString, "enum "), a.getEnum(typeString) != nil:
+		return 1, false
+	case strings.HasPrefix(typeString, "contract "), strings.HasPrefix(typeString, "address"),
+		a.root.GetContractByName(typeString) != nil:
+		return 20, false
+	}
+
+	// Array elements and struct members carry no type names of their own, they are sized as the elementary types.
+	if typeName == nil {
+		typeName = &ast.TypeName{NodeType: ast_pb.NodeType_ELEMENTARY_TYPE_NAME, Name: typeString}
+	}
+
+	if bits, found := typeName.StorageSize(); found && bits > 0 && bits < 256 {
+		return bits / 8, false
+	}
+
+	return 32, false
+}
+
+// arrayLength returns the length of the static array, either the number or the constant, or zero when it is not
+// known.
+func (a *assemblyAnalyzer) arrayLength(length string) int64 {
+	if value, err := strconv.ParseInt(length, 0, 64); err == nil {
+		return value
+	}
+
+	if constant, found := a.variables[length]; found && constant.IsConstant() && constant.GetAST() != nil {
+		value := a.evalSolidity(a.newScope(nil, nil), constant.GetAST().GetInitialValue(), 0)
+		if value != nil && value.IsInt64() {
+			return value.Int64()
+		}
+	}
+
+	return 0
+}
+
+// getEnum returns the enum of the canonical name, e.g. "Contract.Enum", or of the name within any of the
+// contracts of the root.
+func (a *assemblyAnalyzer) getEnum(name string) *Enum {
+	for _, contract := range a.root.GetContracts() {
+		for _, enum := range contract.GetEnums() {
+			if enum.GetCanonicalName() == name || enum.GetName() == name {
+				return enum
+			}
+		}
+	}
+	return nil
+}
+
+// getStruct returns the struct of the canonical name, e.g. "Contract.Struct", or of the name within any of the
+// contracts of the root.
+func (a *assemblyAnalyzer) getStruct(name string) *Struct {
+	for _, contract := range a.root.GetContracts() {
+		for _, structNode := range contract.GetStructs() {
+			if structNode.GetCanonicalName() == name || structNode.GetName() == name {
+				return structNode
+			}
+		}
+	}
+	return nil
+}
+
+// analyze evaluates the assembly block within the body of the function, constructor, fallback, receive or
+// modifier and returns its summary.
+func (a *assemblyAnalyzer) analyze(body *ast.BodyNode, yul *ast.Yul) *AssemblyBlock {
+	block := &AssemblyBlock{
+		Unit:          yul,
+		Id:            yul.GetId(),
+		NodeType:      yul.GetType(),
+		MemorySafe:    yul.IsMemorySafe(),
+		StorageReads:  make([]*AssemblyStorageAccess, 0),
+		StorageWrites: make([]*AssemblyStorageAccess, 0),
+		Calls:         make([]string, 0),
+		Findings:      make([]*AssemblyFinding, 0),
+		Src:           yul.GetSrc(),
+	}
+
+	scope := a.newScope(block, solidityLocals(body))
+	for _, statement := range yul.GetNodes() {
+		a.analyzeStatement(scope, statement)
+	}
+	a.flushPending(scope)
+
+	return block
+}
+
+// newScope creates a new evaluation scope for the block.
+func (a *assemblyAnalyzer) newScope(block *AssemblyBlock, locals map[string]ast.Node[ast.NodeType]) *assemblyScope {
+	return &assemblyScope{
+		block:    block,
+		locals:   locals,
+		values:   make(map[string]*yulValue),
+		declared: make(map[string]bool),
+		memory:   make(map[string]*yulValue),
+		pending:  make(map[string]*pendingCall),
+	}
+}
+
+// solidityLocals returns the initial values of the Solidity local variables declared within the body.
+// Assembly blocks commonly copy slot constants into the local variables before using them.
+func solidityLocals(body *ast.BodyNode) map[string]ast.Node[ast.NodeType] {
+	toReturn := make(map[string]ast.Node[ast.NodeType])
+
+	var collect func(nodes []ast.Node[ast.NodeType])
+	collect = func(nodes []ast.Node[ast.NodeType]) {
+		for _, node := range nodes {
+			switch n := node.(type) {
+			case nil, *ast.Yul:
+				continue
+			case *ast.VariableDeclaration:
+				if len(n.GetDeclarations()) == 1 && n.GetInitialValue() != nil {
+					toReturn[n.GetDeclarations()[0].GetName()] = n.GetInitialValue()
+				}
+			default:
+				collect(node.GetNodes())
+			}
+		}
+	}
+	collect(body.GetNodes())
+
+	return toReturn
+}
+
+// analyzeStatement evaluates the Yul statement.
+func (a *assemblyAnalyzer) analyzeStatement(scope *assemblyScope, node ast.Node[ast.NodeType]) {
+	switch statement := node.(type) {
+	case *ast.YulStatement:
+		for _, child := range statement.GetNodes() {
+			a.analyzeStatement(scope, child)
+		}
+	case *ast.YulBlockStatement:
+		for _, child := range statement.GetStatements() {
+			a.analyzeStatement(scope, child)
+		}
+	case *ast.YulVariable:
+		value := a.eval(scope, statement.GetValue())
+		for _, variable := range statement.GetVariables() {
+			scope.declared[variable.GetName()] = true
+			a.assign(scope, variable.GetName(), value, len(statement.GetVariables()) == 1, variable.GetSrc())
+		}
+	case *ast.YulAssignment:
+		value := a.eval(scope, statement.GetValue())
+		for _, variable := range statement.GetVariableNames() {
+			a.assign(scope, variable.GetName(), value, len(statement.GetVariableNames()) == 1, variable.GetSrc())
+		}
+	case *ast.YulFunctionCallStatement, *ast.YulExpressionStatement:
+		a.eval(scope, statement)
+	case *ast.YulIfStatement:
+		a.eval(scope, statement.GetCondition())
+		a.analyzeStatement(scope, statement.GetBody())
+	case *ast.YulSwitchStatement:
+		a.eval(scope, statement.GetExpression())
+		for _, switchCase := range statement.GetCases() {
+			if caseStatement, ok := switchCase.(*ast.YulSwitchCaseStatement); ok {
+				a.analyzeStatement(scope, caseStatement.GetBody())
+			}
+		}
+	case *ast.YulForStatement:
+		a.analyzeStatement(scope, statement.GetPre())
+		a.eval(scope, statement.GetCondition())
+		a.analyzeStatement(scope, statement.GetBody())
+		a.analyzeStatement(scope, statement.GetPost())
+	case *ast.YulFunctionDefinition:
+		// Yul functions have their own variables, while the block summary is shared.
+		functionScope := a.newScope(scope.block, scope.locals)
+		for _, argument := range statement.GetArguments() {
+			functionScope.declared[argument.GetName()] = true
+		}
+		for _, ret := range statement.GetReturnParameters() {
+			// Return variables escape the function, hence they are not tracked as declared.
+			functionScope.values[ret.GetName()] = &yulValue{}
+		}
+		a.analyzeStatement(functionScope, statement.GetBody())
+		a.flushPending(functionScope)
+	}
+}
+
+// assign stores the value into the Yul variable and tracks unchecked external call success flags.
+func (a *assemblyAnalyzer) assign(scope *assemblyScope, name string, value *yulValue, single bool, src ast.SrcNode) {
+	if previous, ok := scope.pending[name]; ok {
+		a.report(scope, AssemblyUncheckedCall, previous.opcode, previous.src,
+			fmt.Sprintf("success flag of %s stored in `%s` is overwritten before it is checked", previous.opcode, name),
+		)
+		delete(scope.pending, name)
+	}
+
+	if !single {
+		scope.values[name] = &yulValue{}
+		return
+	}
+
+	scope.values[name] = value
+
+	// Success flags assigned to the Solidity variables escape the block and are checked outside of it.
+	if value.call != "" && scope.declared[name] {
+		scope.pending[name] = &pendingCall{opcode: value.call, src: src}
+	}
+}
+
+// flushPending reports all of the success flags that were never checked within the scope.
+func (a *assemblyAnalyzer) flushPending(scope *assemblyScope) {
+	for name, pending := range scope.pending {
+		a.report(scope, AssemblyUncheckedCall, pending.opcode, pending.src,
+			fmt.Sprintf("success flag of %s stored in `%s` is never checked", pending.opcode, name),
+		)
+	}
+	scope.pending = make(map[string]*pendingCall)
+}
+
+// eval evaluates the Yul expression into its abstract value.
+func (a *assemblyAnalyzer) eval(scope *assemblyScope, node ast.Node[ast.NodeType]) *yulValue {
+	switch expression := node.(type) {
+	case *ast.YulExpressionStatement:
+		return a.eval(scope, expression.GetExpression())
+	case *ast.YulLiteralStatement:
+		return &yulValue{constant: yulLiteralValue(expression)}
+	case *ast.YulIdentifier:
+		return a.readIdentifier(scope, expression.GetName())
+	case *ast.YulFunctionCallStatement:
+		return a.evalCall(scope, expression)
+	}
+
+	return &yulValue{}
+}
+
+// yulLiteralValue returns the numeric value of the Yul literal or nil if it's not numeric.
+func yulLiteralValue(literal *ast.YulLiteralStatement) *big.Int {
+	switch literal.GetKind() {
+	case ast_pb.NodeType_HEX_NUMBER:
+		value, ok := new(big.Int).SetString(strings.TrimPrefix(literal.GetHexValue(), "0x"), 16)
+		if ok {
+			return value
+		}
+	case ast_pb.NodeType_DECIMAL_NUMBER:
+		value, ok := new(big.Int).SetString(literal.GetValue(), 10)
+		if ok {
+			return value
+		}
+	case ast_pb.NodeType_BOOLEAN:
+		if literal.GetValue() == "true" {
+			return big.NewInt(1)
+		}
+		return big.NewInt(0)
+	}
+
+	return nil
+}
+
+// readIdentifier resolves the value of the identifier referenced within the Yul expression. Identifiers
+// resolve to Yul variables, `x.slot` storage references, Solidity local variables and constants.
+func (a *assemblyAnalyzer) readIdentifier(scope *assemblyScope, name string) *yulValue {
+	delete(scope.pending, name)
+
+	if value, ok := scope.values[name]; ok {
+		return value
+	}
+
+	if base, ok := strings.CutSuffix(name, ".slot"); ok {
+		if slot, found := a.layout[base]; found {
+			return &yulValue{constant: big.NewInt(slot), variable: a.variables[base]}
+		}
+		return &yulValue{}
+	}
+
+	if initial, found := scope.locals[name]; found {
+		return &yulValue{constant: a.evalSolidity(scope, initial, 0)}
+	}
+
+	if stateVar, found := a.variables[name]; found && stateVar.IsConstant() && stateVar.GetAST() != nil {
+		return &yulValue{constant: a.evalSolidity(scope, stateVar.GetAST().GetInitialValue(), 0)}
+	}
+
+	return &yulValue{}
+}
+
+// evalCall evaluates the Yul builtin call, records the storage accesses and reports the findings.
+func (a *assemblyAnalyzer) evalCall(scope *assemblyScope, call *ast.YulFunctionCallStatement) *yulValue {
+	if call.GetFunctionName() == nil {
+		return &yulValue{}
+	}
+
+	opcode := call.GetFunctionName().GetName()
+	checkedRetSize := scope.checkedRetSize
+
+	args := make([]*yulValue, len(call.GetArguments()))
+	for i, argument := range call.GetArguments() {
+		args[i] = a.eval(scope, argument)
+	}
+
+	arg := func(i int) *yulValue {
+		if i < len(args) {
+			return args[i]
+		}
+		return &yulValue{}
+	}
+
+	switch opcode {
+	case "add", "sub", "mul", "div", "mod", "and", "or", "xor", "shl", "shr":
+		return evalArithmetic(opcode, arg(0), arg(1))
+
+	case "not":
+		if arg(0).constant != nil {
+			return &yulValue{constant: new(big.Int).Sub(new(big.Int).Sub(wordModulus, big.NewInt(1)), arg(0).constant)}
+		}
+
+	case "keccak256":
+		return a.evalKeccak(scope, arg(0), arg(1))
+
+	case "mload":
+		if offset := arg(0).constant; offset != nil {
+			if offset.Cmp(freeMemoryPointer) == 0 {
+				return &yulValue{freePointer: true}
+			}
+			if word, ok := scope.memory[offset.String()]; ok {
+				return word
+			}
+		}
+
+	case "sload", "tload":
+		scope.block.StorageReads = append(scope.block.StorageReads, a.storageAccess(opcode, arg(0), call.GetSrc()))
+
+	case "sstore", "tstore":
+		scope.block.StorageWrites = append(scope.block.StorageWrites, a.storageAccess(opcode, arg(0), call.GetSrc()))
+
+	case "mstore":
+		a.checkMemoryWrite(scope, opcode, arg(0), big.NewInt(32), arg(1), call.GetSrc())
+		if offset := arg(0).constant; offset != nil {
+			scope.memory[offset.String()] = arg(1)
+		} else if !arg(0).freePointer {
+			scope.memory = make(map[string]*yulValue)
+		}
+
+	case "mstore8":
+		a.checkMemoryWrite(scope, opcode, arg(0), big.NewInt(1), arg(1), call.GetSrc())
+		a.invalidateMemory(scope, arg(0))
+
+	case "calldatacopy", "codecopy", "mcopy", "datacopy":
+		a.checkMemoryWrite(scope, opcode, arg(0), arg(2).constant, nil, call.GetSrc())
+		a.invalidateMemory(scope, arg(0))
+
+	case "extcodecopy":
+		a.checkMemoryWrite(scope, opcode, arg(1), arg(3).constant, nil, call.GetSrc())
+		a.invalidateMemory(scope, arg(1))
+
+	case "returndatacopy":
+		a.checkMemoryWrite(scope, opcode, arg(0), arg(2).constant, nil, call.GetSrc())
+		a.checkReturnDataCopy(scope, arg(1), arg(2), checkedRetSize, call.GetSrc())
+		a.invalidateMemory(scope, arg(0))
+
+	case "returndatasize":
+		scope.checkedRetSize = true
+		return &yulValue{returnDataSize: true}
+
+	case "call", "delegatecall", "staticcall", "callcode":
+		a.appendCall(scope, opcode)
+		scope.checkedRetSize = false
+		return &yulValue{call: opcode}
+
+	case "create", "create2":
+		a.appendCall(scope, opcode)
+		scope.checkedRetSize = false
+
+	case "pop":
+		if flag := arg(0).call; flag != "" {
+			a.report(scope, AssemblyUncheckedCall, flag, call.GetSrc(),
+				fmt.Sprintf("success flag of %s is discarded with pop()", flag),
+			)
+		}
+	}
+
+	return &yulValue{}
+}
+
+// evalArithmetic evaluates the arithmetic and bitwise builtins. Constant operands are folded while the
+// slot provenance is propagated otherwise, e.g. add(keccak256(...), index) remains derived from the variable.
+func evalArithmetic(opcode string, left *yulValue, right *yulValue) *yulValue {
+	if left.constant != nil && right.constant != nil {
+		result := new(big.Int)
+		switch opcode {
+		case "add":
+			result.Add(left.constant, right.constant)
+		case "sub":
+			result.Sub(left.constant, right.constant)
+		case "mul":
+			result.Mul(left.constant, right.constant)
+		case "div":
+			if right.constant.Sign() == 0 {
+				return &yulValue{constant: result}
+			}
+			result.Div(left.constant, right.constant)
+		case "mod":
+			if right.constant.Sign() == 0 {
+				return &yulValue{constant: result}
+			}
+			result.Mod(left.constant, right.constant)
+		case "and":
+			result.And(left.constant, right.constant)
+		case "or":
+			result.Or(left.constant, right.constant)
+		case "xor":
+			result.Xor(left.constant, right.constant)
+		case "shl":
+			if left.constant.Cmp(big.NewInt(256)) >= 0 {
+				return &yulValue{constant: result}
+			}
+			result.Lsh(right.constant, uint(left.constant.Uint64()))
+		case "shr":
+			if left.constant.Cmp(big.NewInt(256)) >= 0 {
+				return &yulValue{constant: result}
+			}
+			result.Rsh(right.constant, uint(left.constant.Uint64()))
+		}
+
+		return &yulValue{constant: result.Mod(result, wordModulus)}
+	}
+
+	toReturn := &yulValue{}
+	if opcode == "add" || opcode == "sub" {
+		toReturn.freePointer = left.freePointer || right.freePointer
+		for _, operand := range []*yulValue{left, right} {
+			if operand.variable != nil {
+				toReturn.variable = operand.variable
+				toReturn.derived = true
+			}
+		}
+	}
+
+	return toReturn
+}
+
+// evalKeccak evaluates the keccak256 builtin. Hashing the slot stored into the memory is how the mapping
+// value, keccak256(key . slot), and the dynamic array data, keccak256(slot), locations are computed.
+func (a *assemblyAnalyzer) evalKeccak(scope *assemblyScope, offset *yulValue, length *yulValue) *yulValue {
+	if offset.constant == nil || length.constant == nil {
+		return &yulValue{}
+	}
+
+	words := new(big.Int).Div(length.constant, big.NewInt(32))
+	if words.Sign() == 0 || words.Cmp(big.NewInt(2)) > 0 {
+		return &yulValue{}
+	}
+
+	// Slot is the last word hashed.
+	slotOffset := new(big.Int).Add(offset.constant, new(big.Int).Mul(new(big.Int).Sub(words, big.NewInt(1)), big.NewInt(32)))
+	word, ok := scope.memory[slotOffset.String()]
+	if !ok {
+		return &yulValue{}
+	}
+
+	variable := word.variable
+	if variable == nil && word.constant != nil && word.constant.IsInt64() {
+		variable = a.slots[word.constant.Int64()]
+	}
+
+	if variable == nil {
+		return &yulValue{}
+	}
+
+	return &yulValue{variable: variable, derived: true}
+}
+
+// storageAccess builds the storage access out of the evaluated slot, matching it against the storage layout
+// and the EIP-1967 slots.
+func (a *assemblyAnalyzer) storageAccess(opcode string, slot *yulValue, src ast.SrcNode) *AssemblyStorageAccess {
+	toReturn := &AssemblyStorageAccess{
+		Opcode:  opcode,
+		Derived: slot.derived,
+		Src:     src,
+	}
+
+	if slot.constant != nil {
+		toReturn.Slot = common.BigToHash(slot.constant).Hex()
+
+		if label, ok := eip1967Slots[toReturn.Slot]; ok {
+			toReturn.Standard = "EIP1967"
+			toReturn.Label = label
+			return toReturn
+		}
+	}
+
+	// Transient storage does not share the layout with the regular storage.
+	if toReturn.IsTransient() {
+		return toReturn
+	}
+
+	variable := slot.variable
+	if variable == nil && slot.constant != nil && slot.constant.IsInt64() {
+		variable = a.slots[slot.constant.Int64()]
+	}
+
+	if variable != nil {
+		toReturn.VariableId = variable.GetId()
+		toReturn.Variable = variable.GetName()
+	}
+
+	return toReturn
+}
+
+// checkMemoryWrite reports writes to the fixed memory offsets which are not memory-safe, as defined by the
+// Solidity documentation: writes beyond the scratch space, over the free memory pointer or the zero slot and
+// writes to the fixed offsets that were never allocated through the free memory pointer.
+func (a *assemblyAnalyzer) checkMemoryWrite(scope *assemblyScope, opcode string, offset *yulValue, length *big.Int, value *yulValue, src ast.SrcNode) {
+	if offset.constant == nil {
+		return
+	}
+
+	var message string
+	switch {
+	case offset.constant.Cmp(scratchSpaceEnd) < 0:
+		if length == nil {
+			message = fmt.Sprintf("%s copies data of unbounded size into the scratch space", opcode)
+		} else if new(big.Int).Add(offset.constant, length).Cmp(scratchSpaceEnd) > 0 {
+			message = fmt.Sprintf("%s writes past the end of the scratch space (0x00-0x3f)", opcode)
+		}
+	case offset.constant.Cmp(zeroSlot) < 0:
+		// Updating the free memory pointer with the value derived from it is how memory gets allocated.
+		if opcode != "mstore" || offset.constant.Cmp(freeMemoryPointer) != 0 || value == nil || !value.freePointer {
+			message = fmt.Sprintf("%s overwrites the free memory pointer at 0x40", opcode)
+		}
+	case offset.constant.Cmp(reservedMemoryEnd) < 0:
+		message = fmt.Sprintf("%s overwrites the zero slot at 0x60", opcode)
+	default:
+		message = fmt.Sprintf(
+			"%s writes to the fixed memory offset 0x%x which is not allocated through the free memory pointer",
+			opcode, offset.constant,
+		)
+	}
+
+	if message == "" {
+		return
+	}
+
+	if scope.block.MemorySafe {
+		message += " while the block is annotated as memory-safe"
+	}
+
+	a.report(scope, AssemblyUnsafeMemoryWrite, opcode, src, message)
+}
+
+// checkReturnDataCopy reports returndatacopy calls that copy the data without checking its size first
+// or copy returndatasize() bytes from the non-zero offset, which always reverts.
+func (a *assemblyAnalyzer) checkReturnDataCopy(scope *assemblyScope, offset *yulValue, length *yulValue, checkedRetSize bool, src ast.SrcNode) {
+	switch {
+	case length.returnDataSize:
+		if offset.constant != nil && offset.constant.Sign() != 0 {
+			a.report(scope, AssemblyReturnDataCopyMisuse, "returndatacopy", src,
+				fmt.Sprintf("returndatacopy copies returndatasize() bytes from the offset 0x%x, which is out of bounds and always reverts", offset.constant),
+			)
+		}
+	case !checkedRetSize:
+		a.report(scope, AssemblyReturnDataCopyMisuse, "returndatacopy", src,
+			"returndatacopy copies data without checking returndatasize() and reverts when the return data is shorter",
+		)
+	}
+}
+
+// invalidateMemory removes the tracked memory words after the memory is written with unknown data.
+func (a *assemblyAnalyzer) invalidateMemory(scope *assemblyScope, offset *yulValue) {
+	if offset.constant != nil {
+		delete(scope.memory, offset.constant.String())
+		return
+	}
+
+	if !offset.freePointer {
+		scope.memory = make(map[string]*yulValue)
+	}
+}
+
+// appendCall records the external call or contract creation opcode used within the block.
+func (a *assemblyAnalyzer) appendCall(scope *assemblyScope, opcode string) {
+	for _, existing := range scope.block.Calls {
+		if existing == opcode {
+			return
+		}
+	}
+	scope.block.Calls = append(scope.block.Calls, opcode)
+}
+
+// report appends the finding to the assembly block.
+func (a *assemblyAnalyzer) report(scope *assemblyScope, kind AssemblyFindingKind, opcode string, src ast.SrcNode, message string) {
+	scope.block.Findings = append(scope.block.Findings, &AssemblyFinding{
+		Kind:    kind,
+		Opcode:  opcode,
+		Message: message,
+		Src:     src,
+	})
+}
+
+// evalSolidity evaluates the constant Solidity expression, such as the initial value of the slot constant:
+// literals, references to other constants, type conversions, keccak256 of the string literal and
+// the basic arithmetic, e.g. bytes32(uint256(keccak256("eip1967.proxy.implementation")) - 1).
+func (a *assemblyAnalyzer) evalSolidity(scope *assemblyScope, node ast.Node[ast.NodeType], depth int) *big.Int {
+	if node == nil || depth > 16 {
+		return nil
+	}
+
+	switch expression := node.(type) {
+	case *ast.PrimaryExpression:
+		if expression.GetKind() == ast_pb.NodeType_STRING || expression.GetKind() == ast_pb.NodeType_HEX_STRING {
+			return nil
+		}
+
+		if expression.GetValue() != "" {
+			if value, ok := new(big.Int).SetString(expression.GetValue(), 0); ok {
+				return value
+			}
+			return nil
+		}
+
+		if constant, ok := a.constants[expression.GetReferencedDeclaration()]; ok && constant.GetAST() != nil {
+			return a.evalSolidity(scope, constant.GetAST().GetInitialValue(), depth+1)
+		}
+
+		if stateVar, ok := a.variables[expression.GetName()]; ok && stateVar.IsConstant() && stateVar.GetAST() != nil {
+			return a.evalSolidity(scope, stateVar.GetAST().GetInitialValue(), depth+1)
+		}
+
+		if initial, ok := scope.locals[expression.GetName()]; ok {
+			return a.evalSolidity(scope, initial, depth+1)
+		}
+
+	case *ast.FunctionCall:
+		callee, ok := expression.GetExpression().(*ast.PrimaryExpression)
+		if !ok || len(expression.GetArguments()) != 1 {
+			return nil
+		}
+
+		argument := expression.GetArguments()[0]
+		if callee.GetName() == "keccak256" {
+			if literal, ok := argument.(*ast.PrimaryExpression); ok && literal.GetKind() == ast_pb.NodeType_STRING {
+				return new(big.Int).SetBytes(utils.Keccak256([]byte(literal.GetValue())))
+			}
+			return nil
+		}
+
+		// Elementary type conversions, e.g. bytes32(...) or uint256(...), do not change the word value.
+		if callee.GetTypeName() != nil {
+			return a.evalSolidity(scope, argument, depth+1)
+		}
+
+	case *ast.BinaryOperation:
+		left := a.evalSolidity(scope, expression.GetLeftExpression(), depth+1)
+		right := a.evalSolidity(scope, expression.GetRightExpression(), depth+1)
+		if left == nil || right == nil {
+			return nil
+		}
+
+		result := new(big.Int)
+		switch expression.GetOperator() {
+		case ast_pb.Operator_ADDITION:
+			result.Add(left, right)
+		case ast_pb.Operator_SUBTRACTION:
+			result.Sub(left, right)
+		case ast_pb.Operator_MULTIPLICATION:
+			result.Mul(left, right)
+		default:
+			return nil
+		}
+
+		return result.Mod(result, wordModulus)
+	}
+
+	return nil
+}
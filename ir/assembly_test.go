@@ -0,0 +1,386 @@
+package ir
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unpackdev/solgo"
+)
+
+func TestAssemblyAnalyzer(t *testing.T) {
+	testCases := []struct {
+		name             string
+		content          string
+		contract         string
+		function         string
+		memorySafe       bool
+		expectedReads    []*AssemblyStorageAccess
+		expectedWrites   []*AssemblyStorageAccess
+		expectedCalls    []string
+		expectedFindings map[AssemblyFindingKind]int
+	}{
+		{
+			name: "EIP-1967 Slots And Storage Layout",
+			content: `// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+
+contract Proxy {
+    bytes32 internal constant _IMPLEMENTATION_SLOT = bytes32(uint256(keccak256("eip1967.proxy.implementation")) - 1);
+    bytes32 internal constant _ADMIN_SLOT = 0xb53127684a568b3173ae13b9f8a6016e243e63b6e8ee1178d6a717850b5d6103;
+
+    uint256 public value;
+    mapping(address => uint256) balances;
+
+    function upgrade(address impl) external {
+        bytes32 slot = _IMPLEMENTATION_SLOT;
+        assembly ("memory-safe") {
+            sstore(slot, impl)
+            let admin := sload(_ADMIN_SLOT)
+            let current := sload(value.slot)
+            sstore(0x01, current)
+            mstore(0x00, impl)
+            mstore(0x20, balances.slot)
+            let bal := sload(keccak256(0x00, 0x40))
+        }
+    }
+}`,
+			contract:   "Proxy",
+			function:   "upgrade",
+			memorySafe: true,
+			expectedReads: []*AssemblyStorageAccess{
+				{Opcode: "sload", Slot: "0xb53127684a568b3173ae13b9f8a6016e243e63b6e8ee1178d6a717850b5d6103", Standard: "EIP1967", Label: "admin"},
+				{Opcode: "sload", Slot: "0x0000000000000000000000000000000000000000000000000000000000000000", Variable: "value"},
+				{Opcode: "sload", Variable: "balances", Derived: true},
+			},
+			expectedWrites: []*AssemblyStorageAccess{
+				{Opcode: "sstore", Slot: "0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc", Standard: "EIP1967", Label: "implementation"},
+				{Opcode: "sstore", Slot: "0x0000000000000000000000000000000000000000000000000000000000000001", Variable: "balances"},
+			},
+			expectedCalls:    []string{},
+			expectedFindings: map[AssemblyFindingKind]int{},
+		},
+		{
+			name: "Inherited And Packed Layout",
+			content: `// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+
+contract Base {
+    uint128 first;
+    uint128 second;
+}
+
+contract Token is Base {
+    address owner;
+    bool paused;
+    uint256 supply;
+
+    function read() external view returns (uint256 result) {
+        assembly {
+            result := sload(2)
+            let o := sload(owner.slot)
+            tstore(0, 1)
+        }
+    }
+}`,
+			contract: "Token",
+			function: "read",
+			expectedReads: []*AssemblyStorageAccess{
+				{Opcode: "sload", Slot: "0x0000000000000000000000000000000000000000000000000000000000000002", Variable: "supply"},
+				{Opcode: "sload", Slot: "0x0000000000000000000000000000000000000000000000000000000000000001", Variable: "owner"},
+			},
+			expectedWrites: []*AssemblyStorageAccess{
+				{Opcode: "tstore", Slot: "0x0000000000000000000000000000000000000000000000000000000000000000"},
+			},
+			expectedCalls:    []string{},
+			expectedFindings: map[AssemblyFindingKind]int{},
+		},
+		{
+			name: "C3 Linearized Layout With Structs And Static Arrays",
+			content: `// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+
+contract B {
+    uint256 b;
+}
+
+contract C {
+    uint256 c;
+}
+
+contract D is C, B {
+    uint256 d;
+}
+
+contract E is B, D {
+    struct Position {
+        uint128 amount;
+        uint128 price;
+        address owner;
+        uint64[3] marks;
+    }
+
+    Position position;
+    uint8[40] small;
+    uint256[2] pair;
+    bool flag;
+    uint256 last;
+
+    function read() external view returns (uint256 result) {
+        assembly {
+            result := sload(b.slot)
+            result := sload(pair.slot)
+            result := sload(10)
+            result := sload(last.slot)
+        }
+    }
+}`,
+			contract: "E",
+			function: "read",
+			expectedReads: []*AssemblyStorageAccess{
+				{Opcode: "sload", Slot: "0x0000000000000000000000000000000000000000000000000000000000000001", Variable: "b"},
+				{Opcode: "sload", Slot: "0x0000000000000000000000000000000000000000000000000000000000000008", Variable: "pair"},
+				{Opcode: "sload", Slot: "0x000000000000000000000000000000000000000000000000000000000000000a", Variable: "flag"},
+				{Opcode: "sload", Slot: "0x000000000000000000000000000000000000000000000000000000000000000b", Variable: "last"},
+			},
+			expectedWrites:   []*AssemblyStorageAccess{},
+			expectedCalls:    []string{},
+			expectedFindings: map[AssemblyFindingKind]int{},
+		},
+		{
+			name: "Checked Delegatecall Forwarding",
+			content: `// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+
+contract Forwarder {
+    function forward(address implementation) external {
+        assembly {
+            calldatacopy(0, 0, calldatasize())
+            let result := delegatecall(gas(), implementation, 0, calldatasize(), 0, 0)
+            returndatacopy(0, 0, returndatasize())
+            switch result
+            case 0 {
+                revert(0, returndatasize())
+            }
+            default {
+                return(0, returndatasize())
+            }
+        }
+    }
+}`,
+			contract:       "Forwarder",
+			function:       "forward",
+			expectedReads:  []*AssemblyStorageAccess{},
+			expectedWrites: []*AssemblyStorageAccess{},
+			expectedCalls:  []string{"delegatecall"},
+			expectedFindings: map[AssemblyFindingKind]int{
+				AssemblyUnsafeMemoryWrite: 2,
+			},
+		},
+		{
+			name: "Unchecked Calls And Unsafe Memory",
+			content: `// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+
+contract Unsafe {
+    function execute(address target, uint256 amount) external {
+        assembly {
+            let ptr := mload(0x40)
+            mstore(0x40, add(ptr, 0x20))
+            mstore(ptr, amount)
+            pop(call(gas(), target, amount, 0, 0, 0, 0))
+            let success := staticcall(gas(), target, 0, 0, 0, 0)
+            returndatacopy(ptr, 0, 0x20)
+            returndatacopy(ptr, 0x20, returndatasize())
+            mstore(0x40, 0)
+            mstore(0x60, 1)
+            mstore(0x80, amount)
+            mstore(0x20, 0x40)
+            mstore(0x21, 0x40)
+        }
+    }
+}`,
+			contract:       "Unsafe",
+			function:       "execute",
+			expectedReads:  []*AssemblyStorageAccess{},
+			expectedWrites: []*AssemblyStorageAccess{},
+			expectedCalls:  []string{"call", "staticcall"},
+			expectedFindings: map[AssemblyFindingKind]int{
+				AssemblyUncheckedCall:        2,
+				AssemblyReturnDataCopyMisuse: 2,
+				AssemblyUnsafeMemoryWrite:    4,
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			builder, err := NewBuilderFromSources(context.TODO(), &solgo.Sources{
+				SourceUnits: []*solgo.SourceUnit{
+					{
+						Name:    testCase.contract,
+						Path:    testCase.contract + ".sol",
+						Content: testCase.content,
+					},
+				},
+				EntrySourceUnitName: testCase.contract,
+			})
+			require.NoError(t, err)
+			require.Empty(t, builder.Parse())
+			require.NoError(t, builder.Build())
+
+			contract := builder.GetRoot().GetContractByName(testCase.contract)
+			require.NotNil(t, contract)
+
+			var function *Function
+			for _, fn := range contract.GetFunctions() {
+				if fn.GetName() == testCase.function {
+					function = fn
+				}
+			}
+			require.NotNil(t, function)
+			require.True(t, function.HasAssembly())
+			require.Len(t, function.GetAssembly(), 1)
+
+			block := function.GetAssembly()[0]
+			assert.NotNil(t, block.GetAST())
+			assert.NotNil(t, block.GetSrc())
+			assert.Equal(t, testCase.memorySafe, block.IsMemorySafe())
+			assert.Equal(t, testCase.expectedCalls, block.GetCalls())
+
+			assertAccesses := func(expected []*AssemblyStorageAccess, actual []*AssemblyStorageAccess) {
+				require.Len(t, actual, len(expected))
+				for i, access := range expected {
+					assert.Equal(t, access.Opcode, actual[i].GetOpcode())
+					assert.Equal(t, access.Slot, actual[i].GetSlot())
+					assert.Equal(t, access.Variable, actual[i].GetVariable())
+					assert.Equal(t, access.Standard, actual[i].GetStandard())
+					assert.Equal(t, access.Label, actual[i].GetLabel())
+					assert.Equal(t, access.Derived, actual[i].IsDerived())
+					if access.Variable != "" {
+						assert.NotZero(t, actual[i].GetVariableId())
+					}
+				}
+			}
+			assertAccesses(testCase.expectedReads, block.GetStorageReads())
+			assertAccesses(testCase.expectedWrites, block.GetStorageWrites())
+
+			total := 0
+			for kind, count := range testCase.expectedFindings {
+				assert.Len(t, block.GetFindingsByKind(kind), count, kind.String())
+				total += count
+			}
+			assert.Len(t, block.GetFindings(), total)
+
+			for _, finding := range block.GetFindings() {
+				assert.NotEmpty(t, finding.GetOpcode())
+				assert.NotEmpty(t, finding.GetMessage())
+				assert.NotZero(t, finding.GetSrc().GetLine())
+			}
+		})
+	}
+}
+
+func TestAssemblyCallables(t *testing.T) {
+	content := `// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+
+contract Guarded {
+    uint256 locked;
+    uint256 counter;
+
+    modifier nonReentrant() {
+        assembly {
+            if sload(locked.slot) {
+                revert(0, 0)
+            }
+        }
+        _;
+    }
+
+    constructor() {
+        assembly {
+            sstore(counter.slot, 1)
+        }
+    }
+
+    fallback() external payable {
+        assembly {
+            sstore(counter.slot, 2)
+        }
+    }
+
+    receive() external payable {
+        assembly {
+            sstore(counter.slot, 3)
+        }
+    }
+
+    function bump() external nonReentrant {
+        counter += 1;
+    }
+}`
+
+	builder, err := NewBuilderFromSources(context.TODO(), &solgo.Sources{
+		SourceUnits: []*solgo.SourceUnit{
+			{
+				Name:    "Guarded",
+				Path:    "Guarded.sol",
+				Content: content,
+			},
+		},
+		EntrySourceUnitName: "Guarded",
+	})
+	require.NoError(t, err)
+	require.Empty(t, builder.Parse())
+	require.NoError(t, builder.Build())
+
+	contract := builder.GetRoot().GetContractByName("Guarded")
+	require.NotNil(t, contract)
+	require.NotNil(t, contract.GetConstructor())
+	require.NotNil(t, contract.GetFallback())
+	require.NotNil(t, contract.GetReceive())
+
+	var bump *Function
+	for _, fn := range contract.GetFunctions() {
+		if fn.GetName() == "bump" {
+			bump = fn
+		}
+	}
+	require.NotNil(t, bump)
+
+	testCases := []struct {
+		name     string
+		blocks   []*AssemblyBlock
+		opcode   string
+		variable string
+		write    bool
+	}{
+		{name: "Constructor", blocks: contract.GetConstructor().GetAssembly(), opcode: "sstore", variable: "counter", write: true},
+		{name: "Fallback", blocks: contract.GetFallback().GetAssembly(), opcode: "sstore", variable: "counter", write: true},
+		{name: "Receive", blocks: contract.GetReceive().GetAssembly(), opcode: "sstore", variable: "counter", write: true},
+		{name: "Modifier", blocks: bump.GetAssembly(), opcode: "sload", variable: "locked"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			require.Len(t, testCase.blocks, 1)
+
+			accesses := testCase.blocks[0].GetStorageReads()
+			if testCase.write {
+				accesses = testCase.blocks[0].GetStorageWrites()
+			}
+
+			require.Len(t, accesses, 1)
+			assert.Equal(t, testCase.opcode, accesses[0].GetOpcode())
+			assert.Equal(t, testCase.variable, accesses[0].GetVariable())
+		})
+	}
+}
+
+func TestEip1967Slots(t *testing.T) {
+	assert.Equal(t, "implementation", eip1967Slots["0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc"])
+	assert.Equal(t, "admin", eip1967Slots["0xb53127684a568b3173ae13b9f8a6016e243e63b6e8ee1178d6a717850b5d6103"])
+	assert.Equal(t, "beacon", eip1967Slots["0xa3f0ad74e5423aebfd80d3ef4346578335a9a72aeaee59ff6cb3582b35133d50"])
+}
@@ -74,6 +74,32 @@ func NewBuilderFromJSON(ctx context.Context, data []byte) (*Builder, error) {
 	}, nil
 }
 
+// NewBuilderFromRoot creates a new IR builder out of an already lowered IR root. It is used by frontends
+// of languages other than Solidity, such as Vyper, which produce the IR without going through the AST builder.
+// Contract standards are discovered right away as there is nothing left to be parsed or built.
+func NewBuilderFromRoot(ctx context.Context, sources *solgo.Sources, root *RootSourceUnit) (*Builder, error) {
+	if root == nil {
+		return nil, errors.New("root needed to initialize ir builder")
+	}
+
+	if !standards.StandardsLoaded() {
+		if err := standards.LoadStandards(); err != nil {
+			return nil, err
+		}
+	}
+
+	builder := &Builder{
+		ctx:     ctx,
+		sources: sources,
+		root:    root,
+	}
+
+	root.builder = builder
+	builder.processEips(root)
+
+	return builder, nil
+}
+
 // GetParser returns the underlying solgo parser.
 func (b *Builder) GetParser() *solgo.Parser {
 	return b.parser
@@ -92,6 +118,11 @@ func (b *Builder) GetSources() *solgo.Sources {
 // Parse processes the sources using the parser and the AST builder and returns
 // any encountered errors.
 func (b *Builder) Parse() (errs []error) {
+	// Builders created out of JSON or an already lowered IR root have nothing to parse.
+	if b.parser == nil || b.astBuilder == nil {
+		return nil
+	}
+
 	if syntaxErrs := b.parser.Parse(); syntaxErrs != nil {
 		for _, syntaxErr := range syntaxErrs {
 			errs = append(errs, syntaxErr.Error())
@@ -132,6 +163,10 @@ func (b *Builder) ToProtoPretty() ([]byte, error) {
 
 // Build constructs the IR from the sources.
 func (b *Builder) Build() error {
+	if b.astBuilder == nil {
+		return nil
+	}
+
 	if root := b.GetAstBuilder().GetRoot(); root != nil {
 		b.root = b.processRoot(root)
 	}
@@ -21,6 +21,7 @@ type Constructor struct {
 	Modifiers        []*Modifier       `json:"modifiers"`
 	Parameters       []*Parameter      `json:"parameters"`
 	ReturnStatements []*Parameter      `json:"return"`
+	Assembly         []*AssemblyBlock  `json:"assembly,omitempty"`
 }
 
 // GetAST returns the underlying ast.Constructor.
@@ -73,6 +74,12 @@ func (f *Constructor) GetModifiers() []*Modifier {
 	return f.Modifiers
 }
 
+// GetAssembly returns the summaries of the inline assembly blocks found within the constructor body and the
+// modifiers it invokes.
+func (f *Constructor) GetAssembly() []*AssemblyBlock {
+	return f.Assembly
+}
+
 // GetParameters returns the parameters of the constructor.
 func (f *Constructor) GetParameters() []*Parameter {
 	return f.Parameters
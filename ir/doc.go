@@ -1,2 +1,6 @@
 // Package ir provides an intermediate representation for the AST of a Solidity contract.
+//
+// Inline assembly blocks are analyzed while the IR is built. Raw sload and sstore slots are resolved against
+// the storage layout and the EIP-1967 slots, unchecked call results, memory-unsafe writes and returndatacopy
+// misuse are reported, and the storage read and write summary of every block is attached to its function.
 package ir
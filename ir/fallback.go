@@ -21,6 +21,7 @@ type Fallback struct {
 	Overrides        []*Override       `json:"overrides"`
 	Parameters       []*Parameter      `json:"parameters"`
 	ReturnStatements []*Parameter      `json:"return"`
+	Assembly         []*AssemblyBlock  `json:"assembly,omitempty"`
 }
 
 // GetAST returns the AST (Abstract Syntax Tree) for the fallback function definition.
@@ -73,6 +74,12 @@ func (f *Fallback) GetModifiers() []*Modifier {
 	return f.Modifiers
 }
 
+// GetAssembly returns the summaries of the inline assembly blocks found within the fallback function body and
+// the modifiers it invokes.
+func (f *Fallback) GetAssembly() []*AssemblyBlock {
+	return f.Assembly
+}
+
 // GetOverrides returns the overrides applied to the fallback function.
 func (f *Fallback) GetOverrides() []*Override {
 	return f.Overrides
@@ -24,6 +24,7 @@ type Function struct {
 	Parameters              []*Parameter      `json:"parameters"`
 	Body                    *Body             `json:"body"`
 	ReturnStatements        []*Parameter      `json:"return"`
+	Assembly                []*AssemblyBlock  `json:"assembly,omitempty"`
 	Src                     ast.SrcNode       `json:"src"`
 }
 
@@ -107,6 +108,17 @@ func (f *Function) GetBody() *Body {
 	return f.Body
 }
 
+// GetAssembly returns the summaries of the inline assembly blocks found within the function body and the
+// modifiers it invokes.
+func (f *Function) GetAssembly() []*AssemblyBlock {
+	return f.Assembly
+}
+
+// HasAssembly returns whether the function body contains inline assembly blocks.
+func (f *Function) HasAssembly() bool {
+	return len(f.Assembly) > 0
+}
+
 // GetSrc returns the source code of the function.
 func (f *Function) GetSrc() ast.SrcNode {
 	return f.Src
@@ -1,6 +1,8 @@
 package ir
 
 import (
+	"errors"
+
 	"github.com/ethereum/go-ethereum/common"
 	ast_pb "github.com/unpackdev/protos/dist/go/ast"
 	ir_pb "github.com/unpackdev/protos/dist/go/ir"
@@ -188,6 +190,11 @@ func (r *RootSourceUnit) SetContractType(standard standards.Standard) {
 	case standards.ERC1967, standards.ERC1820:
 		r.appendContractType("proxy")
 		r.appendContractType("upgradeable")
+	case standards.ERC4626:
+		r.appendContractType("token")
+		r.appendContractType("vault")
+	case standards.ERC4337, standards.ERC6551:
+		r.appendContractType("account")
 	}
 }
 
@@ -232,6 +239,10 @@ func (r *RootSourceUnit) ToProto() *ir_pb.Root {
 // This function facilitates traversal of the AST, allowing for operations such as analysis, modification, or inspection to be
 // performed on each node.
 func (r *RootSourceUnit) Walk(nodeVisitor *ast.NodeVisitor) error {
+	if r.builder == nil || r.builder.GetAstBuilder() == nil {
+		return errors.New("root source unit has no underlying ast to walk")
+	}
+
 	return r.builder.GetAstBuilder().GetTree().Walk(nodeVisitor)
 }
 
@@ -278,6 +289,9 @@ func (b *Builder) processRoot(root *ast.RootNode) *RootSourceUnit {
 		}
 	}
 
+	// Analysis of the inline assembly blocks, their storage accesses and potential issues.
+	b.processAssembly(rootNode)
+
 	// Discovery and processing of the contract standards (EIPs)
 	b.processEips(rootNode)
 
@@ -1,6 +1,9 @@
 package ir
 
 import (
+	"strings"
+
+	ast_pb "github.com/unpackdev/protos/dist/go/ast"
 	ir_pb "github.com/unpackdev/protos/dist/go/ir"
 	"github.com/unpackdev/solgo/standards"
 )
@@ -88,12 +91,23 @@ func (b *Builder) processEips(root *RootSourceUnit) {
 			}
 
 			contract.Functions = append(contract.Functions, standards.Function{
-				Name:    function.GetName(),
-				Inputs:  inputs,
-				Outputs: outputs,
+				Name:            function.GetName(),
+				Inputs:          inputs,
+				Outputs:         outputs,
+				StateMutability: getStandardsStateMutability(function.GetStateMutability()),
 			})
 		}
 
+		// Public state variables of Vyper contracts are exposed through compiler generated getters and are
+		// therefore part of the contract interface, e.g. `balanceOf` in Vyper ERC20 implementations.
+		if unit.GetLanguage() == LanguageVyper {
+			for _, stateVar := range unit.GetStateVariables() {
+				if stateVar.GetVisibility() == ast_pb.Visibility_PUBLIC {
+					contract.Functions = append(contract.Functions, getStateVariableGetter(unit, stateVar))
+				}
+			}
+		}
+
 		for _, event := range unit.GetEvents() {
 			inputs := make([]standards.Input, 0)
 
@@ -135,3 +149,83 @@ func (b *Builder) processEips(root *RootSourceUnit) {
 	}
 
 }
+
+// getStateVariableGetter builds the standards function matcher out of the public state variable getter.
+// Mapping keys and array indexes become getter inputs, while the innermost value is the getter output. Structs
+// are returned as the tuple of their members.
+func getStateVariableGetter(unit *Contract, stateVar *StateVariable) standards.Function {
+	toReturn := standards.Function{
+		Name:            stateVar.GetName(),
+		Inputs:          make([]standards.Input, 0),
+		Outputs:         make([]standards.Output, 0),
+		StateMutability: "view",
+	}
+
+	if stateVar.GetTypeDescription() == nil {
+		return toReturn
+	}
+
+	typeName := strings.ReplaceAll(stateVar.GetTypeDescription().GetString(), " ", "")
+	for {
+		if strings.HasPrefix(typeName, "mapping(") && strings.HasSuffix(typeName, ")") {
+			parts := strings.SplitN(strings.TrimSuffix(strings.TrimPrefix(typeName, "mapping("), ")"), "=>", 2)
+			if len(parts) != 2 {
+				break
+			}
+
+			toReturn.Inputs = append(toReturn.Inputs, standards.Input{Type: parts[0]})
+			typeName = parts[1]
+			continue
+		}
+
+		// Arrays, both dynamic and static, are indexed by the uint256 input.
+		if strings.HasSuffix(typeName, "]") {
+			if idx := strings.LastIndex(typeName, "["); idx > 0 {
+				toReturn.Inputs = append(toReturn.Inputs, standards.Input{Type: "uint256"})
+				typeName = typeName[:idx]
+				continue
+			}
+		}
+
+		break
+	}
+
+	toReturn.Outputs = append(toReturn.Outputs, standards.Output{Type: getStructTupleType(unit, typeName)})
+	return toReturn
+}
+
+// getStructTupleType returns the tuple type of the struct of the contract, made of the types of its members, or the
+// type as it is when it is not the struct.
+func getStructTupleType(unit *Contract, typeName string) string {
+	if !strings.HasPrefix(typeName, "struct") {
+		return typeName
+	}
+
+	name := strings.TrimPrefix(typeName, "struct")
+	for _, structNode := range unit.GetStructs() {
+		if structNode.GetCanonicalName() != name && structNode.GetName() != name {
+			continue
+		}
+
+		members := make([]string, 0, len(structNode.GetMembers()))
+		for _, member := range structNode.GetMembers() {
+			memberType := strings.ReplaceAll(member.GetTypeDescription().GetString(), " ", "")
+			members = append(members, getStructTupleType(unit, memberType))
+		}
+
+		return "(" + strings.Join(members, ",") + ")"
+	}
+
+	return typeName
+}
+
+// getStandardsStateMutability returns the function state mutability as it is described within the ABI,
+// or an empty string when the mutability is not known.
+func getStandardsStateMutability(mutability ast_pb.Mutability) string {
+	switch mutability {
+	case ast_pb.Mutability_PAYABLE, ast_pb.Mutability_NONPAYABLE, ast_pb.Mutability_VIEW, ast_pb.Mutability_PURE:
+		return strings.ToLower(mutability.String())
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,87 @@
+package ir
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	ast_pb "github.com/unpackdev/protos/dist/go/ast"
+	"github.com/unpackdev/solgo/ast"
+	"github.com/unpackdev/solgo/standards"
+)
+
+func TestStateVariableGetter(t *testing.T) {
+	unit := &Contract{
+		Name:     "Token",
+		Language: LanguageVyper,
+		Structs: []*Struct{
+			{
+				Name:          "Checkpoint",
+				CanonicalName: "Token.Checkpoint",
+				Members: []*Parameter{
+					{Name: "block", TypeDescription: &ast.TypeDescription{TypeString: "uint256"}},
+					{Name: "amount", TypeDescription: &ast.TypeDescription{TypeString: "uint256"}},
+				},
+			},
+		},
+	}
+
+	testCases := []struct {
+		name            string
+		typeString      string
+		expectedInputs  []standards.Input
+		expectedOutputs []standards.Output
+	}{
+		{
+			name:            "Value",
+			typeString:      "uint256",
+			expectedInputs:  []standards.Input{},
+			expectedOutputs: []standards.Output{{Type: "uint256"}},
+		},
+		{
+			name:            "Mapping",
+			typeString:      "mapping(address => mapping(address => uint256))",
+			expectedInputs:  []standards.Input{{Type: "address"}, {Type: "address"}},
+			expectedOutputs: []standards.Output{{Type: "uint256"}},
+		},
+		{
+			name:            "Dynamic Array",
+			typeString:      "address[]",
+			expectedInputs:  []standards.Input{{Type: "uint256"}},
+			expectedOutputs: []standards.Output{{Type: "address"}},
+		},
+		{
+			name:            "Mapping Of Static Arrays",
+			typeString:      "mapping(address => uint256[3])",
+			expectedInputs:  []standards.Input{{Type: "address"}, {Type: "uint256"}},
+			expectedOutputs: []standards.Output{{Type: "uint256"}},
+		},
+		{
+			name:            "Struct",
+			typeString:      "mapping(address => struct Token.Checkpoint)",
+			expectedInputs:  []standards.Input{{Type: "address"}},
+			expectedOutputs: []standards.Output{{Type: "(uint256,uint256)"}},
+		},
+		{
+			name:            "Unknown Struct",
+			typeString:      "struct Other.Checkpoint",
+			expectedInputs:  []standards.Input{},
+			expectedOutputs: []standards.Output{{Type: "structOther.Checkpoint"}},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			stateVar := &StateVariable{
+				Name:            "value",
+				Visibility:      ast_pb.Visibility_PUBLIC,
+				TypeDescription: &ast.TypeDescription{TypeString: testCase.typeString},
+			}
+
+			getter := getStateVariableGetter(unit, stateVar)
+			assert.Equal(t, "value", getter.Name)
+			assert.Equal(t, "view", getter.StateMutability)
+			assert.Equal(t, testCase.expectedInputs, getter.Inputs)
+			assert.Equal(t, testCase.expectedOutputs, getter.Outputs)
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/unpackdev/solgo/cache"
+	"go.uber.org/zap"
+)
+
+// CachedProvider caches the metadata retrieved by the underlying provider. Metadata is content addressed, so it
+// never changes and is cached as the finalized data.
+type CachedProvider struct {
+	ctx      context.Context  // The context to be used in cache operations.
+	provider Provider         // The provider the metadata is retrieved from on the cache miss.
+	cache    *cache.Namespace // The cache of the metadata.
+}
+
+// NewCachedProvider creates the provider caching the metadata retrieved by the given provider in the cache.
+// If the provider is nil, it returns an error.
+func NewCachedProvider(ctx context.Context, provider Provider, store cache.Cache) (Provider, error) {
+	if provider == nil {
+		return nil, ErrInvalidProvider
+	}
+
+	return Provider(&CachedProvider{
+		ctx:      ctx,
+		provider: provider,
+		cache:    cache.NewNamespace(store, "metadata", nil),
+	}), nil
+}
+
+// GetMetadataByCID retrieves the metadata of a contract by its CID (Content Identifier), from the cache when
+// it was retrieved before. Failures to write the cache are logged and do not fail the retrieval.
+func (p *CachedProvider) GetMetadataByCID(cid string) (*ContractMetadata, error) {
+	var cached ContractMetadata
+	if err := p.cache.GetJSON(p.ctx, cid, &cached); err == nil {
+		return &cached, nil
+	} else if !errors.Is(err, cache.ErrNotFound) {
+		return nil, fmt.Errorf("failed to read cached metadata: %w", err)
+	}
+
+	metadata, err := p.provider.GetMetadataByCID(cid)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.cache.SetJSON(p.ctx, cid, metadata, cache.Finalized); err != nil {
+		zap.L().Warn("failed to cache contract metadata", zap.String("cid", cid), zap.Error(err))
+	}
+
+	return metadata, nil
+}
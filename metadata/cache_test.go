@@ -0,0 +1,55 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unpackdev/solgo/cache"
+)
+
+func TestCachedProvider(t *testing.T) {
+	_, err := NewCachedProvider(context.Background(), nil, nil)
+	assert.True(t, errors.Is(err, ErrInvalidProvider))
+
+	tests := []struct {
+		name  string
+		cache cache.Cache
+		cats  int
+	}{
+		{name: "Cached", cache: cache.NewLRUCache(10), cats: 1},
+		{name: "No Cache", cats: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cats := 0
+			ipfsProvider, err := NewIpfsProvider(context.Background(), &MockShell{
+				CatFunc: func(path string) (io.ReadCloser, error) {
+					cats++
+					return io.NopCloser(strings.NewReader(`{"language":"Solidity","sources":{"contract.sol":{"content":"contract Contract {}"}}}`)), nil
+				},
+			})
+			require.NoError(t, err)
+
+			provider, err := NewCachedProvider(context.Background(), ipfsProvider, tt.cache)
+			require.NoError(t, err)
+
+			for i := 0; i < 3; i++ {
+				metadata, err := provider.GetMetadataByCID("ipfs://QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG")
+				require.NoError(t, err)
+				assert.Equal(t, "Solidity", metadata.Language)
+				assert.Equal(t, "contract Contract {}", metadata.Sources["contract.sol"].Content)
+			}
+
+			assert.Equal(t, tt.cats, cats)
+
+			_, err = provider.GetMetadataByCID("invalidCID")
+			assert.Error(t, err)
+		})
+	}
+}
@@ -1,2 +1,6 @@
 // Package metadata provides functionality for interacting with IPFS/SWARM and retrieving contract metadata.
+// Metadata is retrieved through the IPFS node, the IPFS and Swarm HTTP gateways or the local content addressed
+// store, and verified against the IPFS or Swarm hash solc embeds in the bytecode, so a malicious gateway cannot
+// hand out forged sources. The Swarm gateways resolve the bzz-raw://<hex> URLs returned by the GetBzzr0RawURL
+// and GetBzzr1RawURL methods of bytecode.Metadata.
 package metadata
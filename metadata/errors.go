@@ -5,4 +5,8 @@ import "errors"
 var (
 	ErrInvalidIpfsClient      = errors.New("invalid ipfs client provided")
 	ErrIpfsClientNotAvailable = errors.New("ipfs client seems not to be available. please check your ipfs daemon")
+	ErrInvalidProvider        = errors.New("invalid metadata provider provided")
+	ErrInvalidEndpoint        = errors.New("invalid gateway endpoint provided")
+	ErrUnsupportedURI         = errors.New("unsupported content uri")
+	ErrHashMismatch           = errors.New("content does not match its hash")
 )
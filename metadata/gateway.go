@@ -0,0 +1,164 @@
+package metadata
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+// GatewayProvider retrieves the contract metadata over HTTP, from an IPFS gateway or a Swarm gateway.
+// Gateways are not trusted, so the metadata is verified against the hash of its URI and the sources
+// against their keccak256 hashes before they are returned.
+type GatewayProvider struct {
+	ctx      context.Context // The context to be used in HTTP requests.
+	client   *http.Client    // The HTTP client of the gateway.
+	endpoint string          // The base URL of the gateway.
+	scheme   string          // The scheme of the content the gateway serves, ipfs or bzz.
+}
+
+// NewGatewayProvider creates the provider retrieving the metadata from the IPFS HTTP gateway, such as
+// https://ipfs.io, by the ipfs:// URIs. If the endpoint is empty, it returns an error.
+func NewGatewayProvider(ctx context.Context, endpoint string) (Provider, error) {
+	return newGatewayProvider(ctx, endpoint, "ipfs")
+}
+
+// NewSwarmProvider creates the provider retrieving the metadata from the Swarm HTTP gateway by the bzz-raw://
+// URIs, the hashes solc embeds as bzzr0 or bzzr1. If the endpoint is empty, it returns an error.
+func NewSwarmProvider(ctx context.Context, endpoint string) (Provider, error) {
+	return newGatewayProvider(ctx, endpoint, "bzz")
+}
+
+func newGatewayProvider(ctx context.Context, endpoint string, scheme string) (Provider, error) {
+	if endpoint == "" {
+		return nil, ErrInvalidEndpoint
+	}
+
+	return Provider(&GatewayProvider{
+		ctx: ctx,
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+			Transport: &http.Transport{
+				DialContext: (&net.Dialer{
+					Timeout:   5 * time.Second,
+					KeepAlive: 30 * time.Second,
+				}).DialContext,
+				TLSHandshakeTimeout: 5 * time.Second,
+				MaxIdleConnsPerHost: 10,
+				IdleConnTimeout:     90 * time.Second,
+			},
+		},
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		scheme:   scheme,
+	}), nil
+}
+
+// GetMetadataByCID retrieves the metadata of a contract by its content URI, along with the sources it does not
+// embed, retrieved by their URLs the gateway serves.
+func (p *GatewayProvider) GetMetadataByCID(cid string) (*ContractMetadata, error) {
+	data, err := p.fetch(cid)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err := decodeMetadata(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := resolveSources(metadata, func(name string, source ContractSource) ([]byte, error) {
+		err := fmt.Errorf("no %s url", p.scheme)
+		for _, url := range source.Urls {
+			var content []byte
+			if content, err = p.fetch(url); err == nil {
+				return content, nil
+			}
+		}
+		return nil, err
+	}); err != nil {
+		return nil, err
+	}
+
+	return metadata, nil
+}
+
+// fetch retrieves the content of the URI from the gateway and verifies it against the hash of the URI.
+func (p *GatewayProvider) fetch(uri string) ([]byte, error) {
+	scheme, hash, err := ParseURI(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	if scheme != p.scheme {
+		return nil, fmt.Errorf("%w: %s is not served by the %s gateway", ErrUnsupportedURI, uri, p.scheme)
+	}
+
+	url := fmt.Sprintf("%s/ipfs/%s", p.endpoint, hash)
+	if scheme == "bzz" {
+		url = fmt.Sprintf("%s/bzz-raw:/%s/", p.endpoint, hash)
+	}
+
+	req, err := http.NewRequestWithContext(p.ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send HTTP request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected gateway response status: %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if err := VerifyContent(uri, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// decodeMetadata unmarshals the metadata, keeping its raw content.
+func decodeMetadata(data []byte) (*ContractMetadata, error) {
+	var toReturn ContractMetadata
+	if err := json.Unmarshal(data, &toReturn); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal contract metadata: %w", err)
+	}
+	toReturn.Raw = string(data)
+
+	return &toReturn, nil
+}
+
+// resolveSources fills in the content of the sources the metadata does not embed, retrieved with the fetch
+// function, and checks all of them against their keccak256 hashes.
+func resolveSources(metadata *ContractMetadata, fetch func(name string, source ContractSource) ([]byte, error)) error {
+	for name, source := range metadata.Sources {
+		if source.Content == "" && len(source.Urls) > 0 {
+			content, err := fetch(name, source)
+			if err != nil {
+				return fmt.Errorf("failed to retrieve source %s: %w", name, err)
+			}
+			source.Content = string(content)
+		}
+
+		if err := verifySource(name, source); err != nil {
+			return err
+		}
+
+		metadata.Sources[name] = source
+	}
+
+	return nil
+}
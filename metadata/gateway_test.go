@@ -0,0 +1,147 @@
+package metadata
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSource = "// SPDX-License-Identifier: MIT\npragma solidity ^0.8.0;\n\ncontract Token {}\n"
+
+// testContent is the content addressed metadata and source served by the stand-in gateways.
+type testContent struct {
+	metadata    []byte
+	metadataURI string
+	sourceURI   string
+	files       map[string][]byte // Content by the path of the gateway.
+}
+
+// newTestContent returns the metadata referencing the source by the URL of the scheme, ipfs or bzz.
+func newTestContent(scheme string) *testContent {
+	content := &testContent{files: make(map[string][]byte)}
+
+	sourcePath, sourceURI := "/ipfs/"+IpfsHash([]byte(testSource)), "dweb:/ipfs/"+IpfsHash([]byte(testSource))
+	if scheme == "bzz" {
+		hash := hex.EncodeToString(Bzzr1Hash([]byte(testSource)))
+		sourcePath, sourceURI = "/bzz-raw:/"+hash+"/", "bzz-raw://"+hash
+	}
+	content.sourceURI = sourceURI
+	content.files[sourcePath] = []byte(testSource)
+
+	content.metadata = []byte(fmt.Sprintf(
+		`{"language":"Solidity","sources":{"contracts/Token.sol":{"keccak256":"%s","urls":["%s"]}}}`,
+		crypto.Keccak256Hash([]byte(testSource)).Hex(), sourceURI,
+	))
+
+	metadataPath, metadataURI := "/ipfs/"+IpfsHash(content.metadata), "ipfs://"+IpfsHash(content.metadata)
+	if scheme == "bzz" {
+		hash := hex.EncodeToString(Bzzr0Hash(content.metadata))
+		metadataPath, metadataURI = "/bzz-raw:/"+hash+"/", "bzz-raw://"+hash
+	}
+	content.metadataURI = metadataURI
+	content.files[metadataPath] = content.metadata
+
+	return content
+}
+
+// serve starts the stand-in gateway serving the content, counting the requests.
+func (c *testContent) serve(t *testing.T, requests *int) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		data, ok := c.files[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(data)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestGatewayProvider(t *testing.T) {
+	_, err := NewGatewayProvider(context.Background(), "")
+	assert.ErrorIs(t, err, ErrInvalidEndpoint)
+
+	tests := []struct {
+		name        string
+		scheme      string
+		newProvider func(ctx context.Context, endpoint string) (Provider, error)
+	}{
+		{name: "IPFS Gateway", scheme: "ipfs", newProvider: NewGatewayProvider},
+		{name: "Swarm Gateway", scheme: "bzz", newProvider: NewSwarmProvider},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := newTestContent(tt.scheme)
+
+			requests := 0
+			srv := content.serve(t, &requests)
+
+			provider, err := tt.newProvider(context.Background(), srv.URL)
+			require.NoError(t, err)
+
+			metadata, err := provider.GetMetadataByCID(content.metadataURI)
+			require.NoError(t, err)
+			assert.Equal(t, "Solidity", metadata.Language)
+			assert.Equal(t, string(content.metadata), metadata.Raw)
+			assert.Equal(t, testSource, metadata.Sources["contracts/Token.sol"].Content)
+			assert.Equal(t, 2, requests)
+
+			// Forged content served by the gateway is rejected.
+			for path := range content.files {
+				content.files[path] = []byte(strings.Replace(string(content.files[path]), "Token", "Forged", 1))
+			}
+			_, err = provider.GetMetadataByCID(content.metadataURI)
+			assert.ErrorIs(t, err, ErrHashMismatch)
+
+			_, err = provider.GetMetadataByCID("https://example.com/metadata.json")
+			assert.ErrorIs(t, err, ErrUnsupportedURI)
+		})
+	}
+
+	// Gateways serve the content of their scheme only.
+	content := newTestContent("ipfs")
+	provider, err := NewSwarmProvider(context.Background(), "http://127.0.0.1:0")
+	require.NoError(t, err)
+	_, err = provider.GetMetadataByCID(content.metadataURI)
+	assert.ErrorIs(t, err, ErrUnsupportedURI)
+}
+
+func TestVerifiedProvider(t *testing.T) {
+	_, err := NewVerifiedProvider(nil)
+	assert.ErrorIs(t, err, ErrInvalidProvider)
+
+	content := newTestContent("ipfs")
+	ipfsProvider, err := NewIpfsProvider(context.Background(), &MockShell{
+		CatFunc: func(path string) (io.ReadCloser, error) {
+			if data, ok := content.files[path]; ok {
+				return io.NopCloser(bytes.NewReader(data)), nil
+			}
+			return io.NopCloser(bytes.NewReader(content.metadata)), nil
+		},
+	})
+	require.NoError(t, err)
+
+	provider, err := NewVerifiedProvider(ipfsProvider)
+	require.NoError(t, err)
+
+	metadata, err := provider.GetMetadataByCID(content.metadataURI)
+	require.NoError(t, err)
+	assert.Equal(t, testSource, metadata.Sources["contracts/Token.sol"].Content)
+
+	// Metadata served for the other content is rejected.
+	_, err = provider.GetMetadataByCID("ipfs://" + IpfsHash([]byte("other")))
+	assert.ErrorIs(t, err, ErrHashMismatch)
+}
@@ -0,0 +1,219 @@
+package metadata
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	cid "github.com/ipfs/go-cid"
+	"github.com/mr-tron/base58"
+)
+
+// ipfsChunkSize is the size of the chunks IPFS splits the files into by default, the one solc assumes.
+const ipfsChunkSize = 256 * 1024
+
+// swarmChunkSize is the size of the Swarm chunks.
+const swarmChunkSize = 0x1000
+
+// IpfsHash returns the IPFS hash, the CIDv0, of the data added to IPFS as the UnixFS file with the default
+// chunking, as solc computes it for the metadata hash embedded in the bytecode.
+func IpfsHash(data []byte) string {
+	return base58.Encode(ipfsMultihash(data))
+}
+
+// ipfsMultihash returns the sha2-256 multihash of the root node of the UnixFS file of the data.
+func ipfsMultihash(data []byte) []byte {
+	type chunk struct {
+		hash   []byte // Multihash of the leaf node.
+		size   int    // Size of the encoded leaf node.
+		length int    // Length of the data in the leaf node.
+	}
+
+	var chunks []chunk
+	for offset := 0; offset == 0 || offset < len(data); offset += ipfsChunkSize {
+		part := data[offset:min(offset+ipfsChunkSize, len(data))]
+
+		// UnixFS Data of the file node: Type File, Data and filesize.
+		unixfs := []byte{0x08, 0x02}
+		if len(part) > 0 {
+			unixfs = appendBytesField(unixfs, 0x12, part)
+		}
+		unixfs = binary.AppendUvarint(append(unixfs, 0x18), uint64(len(part)))
+
+		// PBNode with the Data only.
+		node := appendBytesField(nil, 0x0a, unixfs)
+		chunks = append(chunks, chunk{hash: sha256Multihash(node), size: len(node), length: len(part)})
+	}
+
+	if len(chunks) == 1 {
+		return chunks[0].hash
+	}
+
+	// Root of the chunks links them by the hash, empty name and the size of the leaf, followed by the UnixFS
+	// Data with the total size and the sizes of the chunks.
+	var node []byte
+	unixfs := binary.AppendUvarint([]byte{0x08, 0x02, 0x18}, uint64(len(data)))
+	for _, c := range chunks {
+		link := appendBytesField(nil, 0x0a, c.hash)
+		link = append(link, 0x12, 0x00)
+		link = binary.AppendUvarint(append(link, 0x18), uint64(c.size))
+		node = appendBytesField(node, 0x12, link)
+		unixfs = binary.AppendUvarint(append(unixfs, 0x20), uint64(c.length))
+	}
+	node = appendBytesField(node, 0x0a, unixfs)
+
+	return sha256Multihash(node)
+}
+
+// appendBytesField appends the length delimited protobuf field with the given tag.
+func appendBytesField(b []byte, tag byte, value []byte) []byte {
+	b = binary.AppendUvarint(append(b, tag), uint64(len(value)))
+	return append(b, value...)
+}
+
+// sha256Multihash returns the sha2-256 multihash of the data.
+func sha256Multihash(data []byte) []byte {
+	sum := sha256.Sum256(data)
+	return append([]byte{0x12, 0x20}, sum[:]...)
+}
+
+// Bzzr0Hash returns the Swarm hash of the data as solc computes it for the bzzr0 metadata hash, the keccak256
+// chunk tree of the Swarm version 0.
+func Bzzr0Hash(data []byte) []byte {
+	if len(data) <= swarmChunkSize {
+		return swarmSpanHash(len(data), data)
+	}
+
+	span := swarmChunkSize
+	for span*(swarmChunkSize/32) < len(data) {
+		span *= swarmChunkSize / 32
+	}
+
+	var children []byte
+	for offset := 0; offset < len(data); offset += span {
+		children = append(children, Bzzr0Hash(data[offset:min(offset+span, len(data))])...)
+	}
+
+	return swarmSpanHash(len(data), children)
+}
+
+// Bzzr1Hash returns the Swarm hash of the data as solc computes it for the bzzr1 metadata hash, the binary
+// merkle tree chunk hash of the Swarm version 1.
+func Bzzr1Hash(data []byte) []byte {
+	return swarmChunkHash(data, false)
+}
+
+// swarmChunkHash returns the binary merkle tree hash of the chunk, the one of the chunk tree when the data
+// does not fit the single chunk.
+func swarmChunkHash(data []byte, forceHigherLevel bool) []byte {
+	var chunk []byte
+	switch {
+	case len(data) < swarmChunkSize:
+		chunk = append(append(chunk, data...), make([]byte, swarmChunkSize-len(data))...)
+	case len(data) == swarmChunkSize && !forceHigherLevel:
+		chunk = data
+	default:
+		span := swarmChunkSize
+		for span*(swarmChunkSize/32) < len(data) {
+			span *= swarmChunkSize / 32
+		}
+
+		// The data exactly the size of the chunk still needs the level of the chunk hashes below the root.
+		forceHigher := span > swarmChunkSize
+		for offset := 0; offset < len(data); offset += span {
+			chunk = append(chunk, swarmChunkHash(data[offset:min(offset+span, len(data))], forceHigher)...)
+		}
+		chunk = append(chunk, make([]byte, swarmChunkSize-len(chunk))...)
+	}
+
+	return swarmSpanHash(len(data), bmtHash(chunk))
+}
+
+// bmtHash returns the binary merkle tree hash of the data.
+func bmtHash(data []byte) []byte {
+	if len(data) <= 64 {
+		return crypto.Keccak256(data)
+	}
+	mid := len(data) / 2
+	return crypto.Keccak256(bmtHash(data[:mid]), bmtHash(data[mid:]))
+}
+
+// swarmSpanHash returns the keccak256 hash of the data prefixed by the little endian size it spans.
+func swarmSpanHash(span int, data []byte) []byte {
+	prefix := binary.LittleEndian.AppendUint64(nil, uint64(span))
+	return crypto.Keccak256(prefix, data)
+}
+
+// ParseURI splits the content URI, such as ipfs://<cid>, dweb:/ipfs/<cid> or bzz-raw://<hash>, into its
+// scheme, ipfs or bzz, and the hash of the content.
+func ParseURI(uri string) (string, string, error) {
+	for prefix, scheme := range map[string]string{
+		"ipfs://":     "ipfs",
+		"dweb:/ipfs/": "ipfs",
+		"bzz-raw://":  "bzz",
+		"bzzr0://":    "bzz",
+		"bzzr1://":    "bzz",
+		"bzz://":      "bzz",
+	} {
+		if hash, ok := strings.CutPrefix(uri, prefix); ok && hash != "" {
+			return scheme, strings.TrimSuffix(hash, "/"), nil
+		}
+	}
+	return "", "", fmt.Errorf("%w: %s", ErrUnsupportedURI, uri)
+}
+
+// VerifyContent checks that the content hashes to the hash of its URI. IPFS content is checked against the
+// multihash of its CID, while Swarm content is checked against both the bzzr0 and bzzr1 hashes, as the URI
+// does not tell them apart. It returns ErrHashMismatch when the content was forged.
+func VerifyContent(uri string, content []byte) error {
+	scheme, hash, err := ParseURI(uri)
+	if err != nil {
+		return err
+	}
+
+	switch scheme {
+	case "ipfs":
+		c, err := cid.Decode(hash)
+		if err != nil {
+			return fmt.Errorf("invalid IPFS hash: %w", err)
+		}
+
+		// Raw leaves are hashed as they are, the rest as the UnixFS files.
+		expected := ipfsMultihash(content)
+		if c.Prefix().Codec == cid.Raw {
+			expected = sha256Multihash(content)
+		}
+
+		if !bytes.Equal(c.Hash(), expected) {
+			return fmt.Errorf("%w: %s", ErrHashMismatch, uri)
+		}
+	default:
+		expected, err := hex.DecodeString(strings.TrimPrefix(hash, "0x"))
+		if err != nil {
+			return fmt.Errorf("invalid Swarm hash: %w", err)
+		}
+
+		if !bytes.Equal(expected, Bzzr1Hash(content)) && !bytes.Equal(expected, Bzzr0Hash(content)) {
+			return fmt.Errorf("%w: %s", ErrHashMismatch, uri)
+		}
+	}
+
+	return nil
+}
+
+// verifySource checks that the content of the source matches its keccak256 hash, when the metadata has it.
+func verifySource(name string, source ContractSource) error {
+	if source.Keccak256 == "" {
+		return nil
+	}
+
+	if !strings.EqualFold(crypto.Keccak256Hash([]byte(source.Content)).Hex(), source.Keccak256) {
+		return fmt.Errorf("%w: source %s", ErrHashMismatch, name)
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package metadata
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	cid "github.com/ipfs/go-cid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIpfsHash(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{name: "Empty", data: []byte{}, want: "QmbFMke1KXqnYyBBWxB74N4c5SBnJMVAiMNRcGu6x1AwQH"},
+		{name: "Hello World", data: []byte("hello world\n"), want: "QmT78zSuBmuS4z925WZfrqQ1qHaJ56DQaTfyMUF7F8ff5o"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IpfsHash(tt.data))
+		})
+	}
+
+	// Data over the chunk size is hashed as the root of the chunks.
+	large := bytes.Repeat([]byte{0x01}, ipfsChunkSize+1)
+	assert.NotEqual(t, IpfsHash(large), IpfsHash(large[:ipfsChunkSize]))
+	assert.Equal(t, IpfsHash(large), IpfsHash(bytes.Repeat([]byte{0x01}, ipfsChunkSize+1)))
+}
+
+func TestSwarmHash(t *testing.T) {
+	// Data up to the single chunk is hashed with its span by both versions.
+	assert.Equal(t, "011b4d03dd8c01f1049143cf9c4c817e4b167f1d1b83e5c6f0f10d89ba1e7bce", hex.EncodeToString(Bzzr0Hash(nil)))
+	assert.Len(t, Bzzr1Hash(nil), 32)
+	assert.NotEqual(t, Bzzr0Hash(nil), Bzzr1Hash(nil))
+
+	// Data over the single chunk is hashed as the tree of the chunks.
+	large := bytes.Repeat([]byte{0x01}, swarmChunkSize*2+1)
+	assert.NotEqual(t, Bzzr0Hash(large), Bzzr0Hash(large[:swarmChunkSize*2]))
+	assert.NotEqual(t, Bzzr1Hash(large), Bzzr1Hash(large[:swarmChunkSize*2]))
+	assert.NotEqual(t, Bzzr1Hash(large[:swarmChunkSize]), Bzzr1Hash(large[:swarmChunkSize-1]))
+}
+
+func TestVerifyContent(t *testing.T) {
+	content := []byte(`{"language":"Solidity"}`)
+
+	cidV0, err := cid.Decode(IpfsHash(content))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		uri     string
+		wantErr error
+	}{
+		{name: "IPFS CIDv0", uri: "ipfs://" + IpfsHash(content)},
+		{name: "IPFS CIDv1", uri: "ipfs://" + cid.NewCidV1(cid.DagProtobuf, cidV0.Hash()).String()},
+		{name: "IPFS Raw Leaves", uri: "dweb:/ipfs/" + cid.NewCidV1(cid.Raw, sha256Multihash(content)).String()},
+		{name: "Bzzr0", uri: "bzz-raw://" + hex.EncodeToString(Bzzr0Hash(content))},
+		{name: "Bzzr1", uri: "bzz-raw://" + hex.EncodeToString(Bzzr1Hash(content))},
+		{name: "IPFS Mismatch", uri: "ipfs://" + IpfsHash([]byte("forged")), wantErr: ErrHashMismatch},
+		{name: "Swarm Mismatch", uri: "bzz-raw://" + hex.EncodeToString(Bzzr1Hash([]byte("forged"))), wantErr: ErrHashMismatch},
+		{name: "Unsupported", uri: "https://example.com/metadata.json", wantErr: ErrUnsupportedURI},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyContent(tt.uri, content)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
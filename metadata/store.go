@@ -0,0 +1,190 @@
+package metadata
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	cid "github.com/ipfs/go-cid"
+	"github.com/mr-tron/base58"
+)
+
+// LocalStore is the content addressed store of the metadata and sources in the local directory. The metadata
+// is stored by the hash of its URI and the sources by their keccak256 hashes, all of them verified against
+// their hashes both when they are stored and when they are read back.
+type LocalStore struct {
+	ctx      context.Context // The context of the store.
+	path     string          // The directory of the store.
+	upstream Provider        // The provider the metadata missing in the store is retrieved from, if any.
+}
+
+// NewLocalStore creates the store in the given directory, creating the directory when it does not exist. The
+// metadata missing in the store is retrieved from the upstream provider and stored, unless it is nil.
+func NewLocalStore(ctx context.Context, path string, upstream Provider) (*LocalStore, error) {
+	if path == "" {
+		return nil, errors.New("store path not set")
+	}
+
+	if err := os.MkdirAll(path, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create store directory: %w", err)
+	}
+
+	return &LocalStore{
+		ctx:      ctx,
+		path:     path,
+		upstream: upstream,
+	}, nil
+}
+
+// GetPath returns the directory of the store.
+func (s *LocalStore) GetPath() string {
+	return s.path
+}
+
+// GetMetadataByCID retrieves the metadata of a contract by its content URI from the store, along with the
+// sources it does not embed. The metadata missing in the store is retrieved from the upstream provider and
+// stored, failing when it does not match its hash.
+func (s *LocalStore) GetMetadataByCID(cid string) (*ContractMetadata, error) {
+	data, err := s.Get(cid)
+	if errors.Is(err, os.ErrNotExist) && s.upstream != nil {
+		return s.pull(cid)
+	} else if err != nil {
+		return nil, err
+	}
+
+	metadata, err := decodeMetadata(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := resolveSources(metadata, func(name string, source ContractSource) ([]byte, error) {
+		return os.ReadFile(s.sourceFile(source.Keccak256))
+	}); err != nil {
+		return nil, err
+	}
+
+	return metadata, nil
+}
+
+// Get returns the content of the URI from the store, an error wrapping os.ErrNotExist when it is not stored.
+func (s *LocalStore) Get(uri string) ([]byte, error) {
+	file, err := s.file(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", uri, err)
+	}
+
+	if err := VerifyContent(uri, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// Put stores the content of the URI, failing with ErrHashMismatch when it does not match the hash of the URI.
+func (s *LocalStore) Put(uri string, data []byte) error {
+	if err := VerifyContent(uri, data); err != nil {
+		return err
+	}
+
+	file, err := s.file(uri)
+	if err != nil {
+		return err
+	}
+
+	return s.write(file, data)
+}
+
+// PutSource stores the content of the source by its keccak256 hash.
+func (s *LocalStore) PutSource(content []byte) error {
+	return s.write(s.sourceFile(crypto.Keccak256Hash(content).Hex()), content)
+}
+
+// pull retrieves the metadata from the upstream provider and stores it along with its sources.
+func (s *LocalStore) pull(uri string) (*ContractMetadata, error) {
+	metadata, err := s.upstream.GetMetadataByCID(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.Put(uri, []byte(metadata.Raw)); err != nil {
+		return nil, err
+	}
+
+	for name, source := range metadata.Sources {
+		if err := verifySource(name, source); err != nil {
+			return nil, err
+		}
+
+		if err := s.PutSource([]byte(source.Content)); err != nil {
+			return nil, err
+		}
+	}
+
+	return metadata, nil
+}
+
+// file returns the path of the file of the URI, the same one for the CIDv0 and CIDv1 of the content.
+func (s *LocalStore) file(uri string) (string, error) {
+	scheme, hash, err := ParseURI(uri)
+	if err != nil {
+		return "", err
+	}
+
+	if scheme == "ipfs" {
+		c, err := cid.Decode(hash)
+		if err != nil {
+			return "", fmt.Errorf("invalid IPFS hash: %w", err)
+		}
+		hash = base58.Encode(c.Hash())
+	} else {
+		hash = strings.ToLower(strings.TrimPrefix(hash, "0x"))
+		if _, err := hex.DecodeString(hash); err != nil {
+			return "", fmt.Errorf("invalid Swarm hash: %w", err)
+		}
+	}
+
+	return filepath.Join(s.path, scheme, hash), nil
+}
+
+// sourceFile returns the path of the file of the source with the given keccak256 hash.
+func (s *LocalStore) sourceFile(keccak256 string) string {
+	return filepath.Join(s.path, "keccak256", strings.ToLower(strings.TrimPrefix(keccak256, "0x")))
+}
+
+// write writes the file through the temporary file, so the readers never see it partially written.
+func (s *LocalStore) write(file string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return fmt.Errorf("failed to create store directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(file), ".entry-*")
+	if err != nil {
+		return fmt.Errorf("failed to create store entry: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write store entry: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write store entry: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), file); err != nil {
+		return fmt.Errorf("failed to write store entry: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package metadata
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStore(t *testing.T) {
+	_, err := NewLocalStore(context.Background(), "", nil)
+	assert.Error(t, err)
+
+	content := newTestContent("ipfs")
+
+	requests := 0
+	srv := content.serve(t, &requests)
+
+	gateway, err := NewGatewayProvider(context.Background(), srv.URL)
+	require.NoError(t, err)
+
+	path := t.TempDir()
+	store, err := NewLocalStore(context.Background(), path, gateway)
+	require.NoError(t, err)
+	assert.Equal(t, path, store.GetPath())
+
+	metadata, err := store.GetMetadataByCID(content.metadataURI)
+	require.NoError(t, err)
+	assert.Equal(t, testSource, metadata.Sources["contracts/Token.sol"].Content)
+	assert.Equal(t, 2, requests)
+
+	// Stored metadata and sources are read back without the upstream provider.
+	offline, err := NewLocalStore(context.Background(), path, nil)
+	require.NoError(t, err)
+
+	metadata, err = offline.GetMetadataByCID(content.metadataURI)
+	require.NoError(t, err)
+	assert.Equal(t, string(content.metadata), metadata.Raw)
+	assert.Equal(t, testSource, metadata.Sources["contracts/Token.sol"].Content)
+	assert.Equal(t, 2, requests)
+
+	_, err = offline.GetMetadataByCID("ipfs://" + IpfsHash([]byte("missing")))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	// Content not matching its hash is neither stored nor read back.
+	assert.ErrorIs(t, offline.Put(content.sourceURI, []byte("forged")), ErrHashMismatch)
+
+	file, err := offline.file(content.metadataURI)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(file, []byte(`{"language":"Forged"}`), 0o644))
+
+	_, err = offline.GetMetadataByCID(content.metadataURI)
+	assert.ErrorIs(t, err, ErrHashMismatch)
+}
@@ -38,6 +38,7 @@ type ContractMetadata struct {
 		CompilationTarget map[string]string `json:"compilationTarget"`
 		Libraries         interface{}       `json:"libraries"`
 		Remappings        []string          `json:"remappings"`
+		ViaIR             bool              `json:"viaIR"`
 		Metadata          struct {
 			BytecodeHash      string `json:"bytecodeHash"`
 			UseLiteralContent bool   `json:"useLiteralContent"`
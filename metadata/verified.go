@@ -0,0 +1,40 @@
+package metadata
+
+import "fmt"
+
+// VerifiedProvider verifies the metadata retrieved by the underlying provider, such as the IPFS provider
+// talking to the remote node, against the hash of its URI, and its sources against their keccak256 hashes.
+type VerifiedProvider struct {
+	provider Provider // The provider the metadata is retrieved from.
+}
+
+// NewVerifiedProvider creates the provider verifying the metadata retrieved by the given provider.
+// If the provider is nil, it returns an error.
+func NewVerifiedProvider(provider Provider) (Provider, error) {
+	if provider == nil {
+		return nil, ErrInvalidProvider
+	}
+
+	return Provider(&VerifiedProvider{provider: provider}), nil
+}
+
+// GetMetadataByCID retrieves the metadata of a contract by its content URI, failing with ErrHashMismatch when
+// the metadata or any of its sources does not match its hash.
+func (p *VerifiedProvider) GetMetadataByCID(cid string) (*ContractMetadata, error) {
+	metadata, err := p.provider.GetMetadataByCID(cid)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := VerifyContent(cid, []byte(metadata.Raw)); err != nil {
+		return nil, fmt.Errorf("failed to verify contract metadata: %w", err)
+	}
+
+	for name, source := range metadata.Sources {
+		if err := verifySource(name, source); err != nil {
+			return nil, fmt.Errorf("failed to verify contract metadata: %w", err)
+		}
+	}
+
+	return metadata, nil
+}
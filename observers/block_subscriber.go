@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"math/big"
+	"sync"
 	"sync/atomic"
 
 	"github.com/ethereum/go-ethereum"
@@ -27,6 +28,8 @@ type BlockSubscriber struct {
 	client *clients.ClientPool   // The client pool for accessing blockchain clients.
 	active atomic.Bool           // Flag to indicate if the subscriber is active.
 	sub    ethereum.Subscription // The Ethereum subscription object.
+	mu     sync.Mutex            // Guards the cancellation of the resumable subscription.
+	cancel context.CancelFunc    // Cancels the resumable subscription.
 }
 
 // NewBlockSubscriber creates a new block subscriber with the given context and client pool.
@@ -169,6 +172,14 @@ func (b *BlockSubscriber) Close() error {
 		if b.sub != nil {
 			b.sub.Unsubscribe()
 		}
+
+		b.mu.Lock()
+		if b.cancel != nil {
+			b.cancel()
+			b.cancel = nil
+		}
+		b.mu.Unlock()
+
 		b.active.Store(false)
 	}
 
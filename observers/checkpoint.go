@@ -0,0 +1,111 @@
+package observers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/goccy/go-json"
+	"github.com/unpackdev/solgo/utils"
+)
+
+// BlockRef identifies the block by its number and hash.
+type BlockRef struct {
+	Number uint64      `json:"number"` // Number of the block.
+	Hash   common.Hash `json:"hash"`   // Hash of the block.
+}
+
+// BlockCheckpoint is the progress of the resumable subscription, the last processed block together with the
+// recent blocks the chain reorganizations are resolved against.
+type BlockCheckpoint struct {
+	Number uint64      `json:"number"` // Number of the last processed block.
+	Hash   common.Hash `json:"hash"`   // Hash of the last processed block.
+	Recent []BlockRef  `json:"recent"` // Recently processed blocks in ascending order, ending with the last one.
+}
+
+// CheckpointStore persists the progress of the resumable subscription.
+type CheckpointStore interface {
+	// Load returns the stored checkpoint, or nil when no block was processed yet.
+	Load(ctx context.Context) (*BlockCheckpoint, error)
+
+	// Save stores the checkpoint, replacing the previous one.
+	Save(ctx context.Context, checkpoint *BlockCheckpoint) error
+}
+
+// MemoryCheckpointStore keeps the checkpoint in memory, so the subscription resumes only within the process.
+type MemoryCheckpointStore struct {
+	mu         sync.RWMutex
+	checkpoint *BlockCheckpoint
+}
+
+// NewMemoryCheckpointStore creates the empty in-memory checkpoint store.
+func NewMemoryCheckpointStore() *MemoryCheckpointStore {
+	return &MemoryCheckpointStore{}
+}
+
+// Load returns the stored checkpoint.
+func (s *MemoryCheckpointStore) Load(ctx context.Context) (*BlockCheckpoint, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.checkpoint, nil
+}
+
+// Save stores the checkpoint.
+func (s *MemoryCheckpointStore) Save(ctx context.Context, checkpoint *BlockCheckpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.checkpoint = checkpoint
+	return nil
+}
+
+// FileCheckpointStore keeps the checkpoint in the JSON file.
+type FileCheckpointStore struct {
+	path string
+}
+
+// NewFileCheckpointStore creates the checkpoint store persisting the checkpoint to the file at the path.
+func NewFileCheckpointStore(path string) *FileCheckpointStore {
+	return &FileCheckpointStore{path: path}
+}
+
+// Load reads the checkpoint file, returning nil when it does not exist.
+func (s *FileCheckpointStore) Load(ctx context.Context) (*BlockCheckpoint, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read block checkpoint: %w", err)
+	}
+
+	toReturn := &BlockCheckpoint{}
+	if err := json.Unmarshal(data, toReturn); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal block checkpoint: %w", err)
+	}
+
+	return toReturn, nil
+}
+
+// Save replaces the checkpoint file atomically.
+func (s *FileCheckpointStore) Save(ctx context.Context, checkpoint *BlockCheckpoint) error {
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return fmt.Errorf("failed to marshal block checkpoint: %w", err)
+	}
+
+	tmpPath := s.path + ".tmp"
+	if err := utils.WriteToFile(tmpPath, data); err != nil {
+		return fmt.Errorf("failed to write block checkpoint: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		return fmt.Errorf("failed to replace block checkpoint: %w", err)
+	}
+
+	return nil
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/unpackdev/solgo/clients"
 	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
 )
 
 // Contract represents a blockchain contract with its associated details.
@@ -23,16 +24,18 @@ type Contract struct {
 	Block           *types.Block       `json:"block"`         // Block in which the contract transaction was included.
 	Transaction     *types.Transaction `json:"transaction"`   // Transaction that created the contract.
 	Receipt         *types.Receipt     `json:"receipt"`       // Receipt of the contract creation transaction.
+	Creation        *Creation          `json:"creation"`      // Creation details such as the deployer, factory and salt.
 }
 
 // ContractSubscriberOptions defines the options for configuring a contract subscriber.
 type ContractSubscriberOptions struct {
-	NetworkID        int64    `mapstructure:"network_id" yaml:"network_id" json:"network_id"`                         // The network ID of the Ethereum blockchain.
-	Group            string   `mapstructure:"group" yaml:"group" json:"group"`                                        // The group identifier for the blockchain client.
-	Type             string   `mapstructure:"type" yaml:"type" json:"type"`                                           // The type of the blockchain client.
-	Head             bool     `mapstructure:"head" yaml:"head" json:"head"`                                           // If true, subscribes to the latest block. Otherwise, subscribes to a range.
-	StartBlockNumber *big.Int `mapstructure:"start_block_number" yaml:"start_block_number" json:"start_block_number"` // Starting block number for the subscription.
-	EndBlockNumber   *big.Int `mapstructure:"end_block_number" yaml:"end_block_number" json:"end_block_number"`       // Ending block number for the subscription.
+	NetworkID        int64           `mapstructure:"network_id" yaml:"network_id" json:"network_id"`                         // The network ID of the Ethereum blockchain.
+	Group            string          `mapstructure:"group" yaml:"group" json:"group"`                                        // The group identifier for the blockchain client.
+	Type             string          `mapstructure:"type" yaml:"type" json:"type"`                                           // The type of the blockchain client.
+	Head             bool            `mapstructure:"head" yaml:"head" json:"head"`                                           // If true, subscribes to the latest block. Otherwise, subscribes to a range.
+	StartBlockNumber *big.Int        `mapstructure:"start_block_number" yaml:"start_block_number" json:"start_block_number"` // Starting block number for the subscription.
+	EndBlockNumber   *big.Int        `mapstructure:"end_block_number" yaml:"end_block_number" json:"end_block_number"`       // Ending block number for the subscription.
+	Discovery        DiscoveryMethod `mapstructure:"discovery" yaml:"discovery" json:"discovery"`                            // Method the created contracts are discovered with, the auto discovery when empty.
 }
 
 // ContractSubscriber provides methods to subscribe to and interact with Ethereum contracts.
@@ -68,6 +71,11 @@ func (b *ContractSubscriber) Subscribe(opts *ContractSubscriberOptions, contract
 		return errors.New("client not found")
 	}
 
+	discoverer, err := NewCreationDiscoverer(client, opts.Discovery)
+	if err != nil {
+		return err
+	}
+
 	if opts.Head {
 		headerCh := make(chan *types.Header)
 		sub, err := client.SubscribeNewHead(b.ctx, headerCh)
@@ -87,12 +95,12 @@ func (b *ContractSubscriber) Subscribe(opts *ContractSubscriberOptions, contract
 					zap.L().Error(
 						"failure while searching for block",
 						zap.Error(err),
-						zap.Int64("block_number", block.Number().Int64()),
+						zap.Int64("block_number", header.Number.Int64()),
 					)
 					continue
 				}
 
-				contracts, err := b.discoverContracts(block, opts)
+				contracts, err := b.discoverContracts(discoverer, client, block, opts)
 				if err != nil {
 					zap.L().Error(
 						"failure while searching for block contracts",
@@ -149,7 +157,7 @@ func (b *ContractSubscriber) Subscribe(opts *ContractSubscriberOptions, contract
 				continue
 			}
 
-			contracts, err := b.discoverContracts(block, opts)
+			contracts, err := b.discoverContracts(discoverer, client, block, opts)
 			if err != nil {
 				zap.L().Error(
 					"failure while searching for block contracts",
@@ -173,46 +181,57 @@ func (b *ContractSubscriber) Subscribe(opts *ContractSubscriberOptions, contract
 }
 
 // discoverContracts searches for contracts within a given block based on the provided options.
-// It returns a list of discovered contracts.
-func (b *ContractSubscriber) discoverContracts(block *types.Block, opts *ContractSubscriberOptions) ([]*Contract, error) {
-	contracts := make([]*Contract, 0)
+// It returns a list of discovered contracts, including the ones created by the factory contracts.
+func (b *ContractSubscriber) discoverContracts(discoverer *CreationDiscoverer, backend DiscoveryBackend, block *types.Block, opts *ContractSubscriberOptions) ([]*Contract, error) {
+	creations, err := discoverer.Discover(b.ctx, block)
+	if err != nil {
+		return nil, err
+	}
 
-	errCh := make(chan error)
+	var mutex sync.Mutex
+	receipts := make(map[common.Hash]*types.Receipt)
+	g, ctx := errgroup.WithContext(b.ctx)
 
-	var wg sync.WaitGroup
+	for _, creation := range creations {
+		txHash := creation.TransactionHash
 
-	for _, tx := range block.Transactions() {
-		wg.Add(1)
-		go func(tx *types.Transaction) {
-			defer wg.Done()
-			client := b.client.GetClientByGroupAndType(opts.Group, opts.Type)
+		mutex.Lock()
+		_, ok := receipts[txHash]
+		receipts[txHash] = nil
+		mutex.Unlock()
+		if ok {
+			continue
+		}
 
-			receipt, err := client.TransactionReceipt(b.ctx, tx.Hash())
+		g.Go(func() error {
+			receipt, err := backend.TransactionReceipt(ctx, txHash)
 			if err != nil {
-				errCh <- err
-				return
+				return err
 			}
 
-			if receipt.Status == types.ReceiptStatusSuccessful && receipt.ContractAddress != (common.Address{}) {
-				contracts = append(contracts, &Contract{
-					NetworkID:       opts.NetworkID,
-					NetworkGroup:    opts.Group,
-					NetworkType:     opts.Type,
-					ContractAddress: receipt.ContractAddress,
-					Block:           block,
-					Transaction:     tx,
-					Receipt:         receipt,
-				})
-			}
-		}(tx)
+			mutex.Lock()
+			receipts[txHash] = receipt
+			mutex.Unlock()
+			return nil
+		})
 	}
 
-	wg.Wait()
-
-	close(errCh)
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
 
-	if len(errCh) > 0 {
-		return contracts, <-errCh
+	contracts := make([]*Contract, 0, len(creations))
+	for _, creation := range creations {
+		contracts = append(contracts, &Contract{
+			NetworkID:       opts.NetworkID,
+			NetworkGroup:    opts.Group,
+			NetworkType:     opts.Type,
+			ContractAddress: creation.Address,
+			Block:           block,
+			Transaction:     block.Transaction(creation.TransactionHash),
+			Receipt:         receipts[creation.TransactionHash],
+			Creation:        creation,
+		})
 	}
 
 	return contracts, nil
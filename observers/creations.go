@@ -0,0 +1,501 @@
+package observers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+)
+
+// DiscoveryMethod is the method the contracts created in the block are discovered with.
+type DiscoveryMethod string
+
+const (
+	// AutoDiscovery tries the tracing methods in order, falling back to the code diff.
+	AutoDiscovery DiscoveryMethod = "auto"
+
+	// DebugTraceDiscovery walks the call frames of debug_traceBlockByHash with the callTracer.
+	DebugTraceDiscovery DiscoveryMethod = "debug_trace"
+
+	// TraceBlockDiscovery walks the create traces of trace_block.
+	TraceBlockDiscovery DiscoveryMethod = "trace_block"
+
+	// CodeDiffDiscovery compares the code of the addresses touched by the transactions before and after the block.
+	CodeDiffDiscovery DiscoveryMethod = "code_diff"
+)
+
+// String returns the string representation of the discovery method.
+func (m DiscoveryMethod) String() string {
+	return string(m)
+}
+
+// CreationType is the opcode the contract was created with.
+type CreationType string
+
+const (
+	// CreateCreation is the contract created by the CREATE opcode, or by the transaction itself.
+	CreateCreation CreationType = "CREATE"
+
+	// Create2Creation is the contract created by the CREATE2 opcode.
+	Create2Creation CreationType = "CREATE2"
+)
+
+// String returns the string representation of the creation type.
+func (t CreationType) String() string {
+	return string(t)
+}
+
+// JSON-RPC error codes of the methods the node does not provide.
+const (
+	methodNotFoundCode     = -32601
+	methodNotSupportedCode = -32004
+)
+
+// DiscoveryBackend provides the traces, receipts and code the contract creations are discovered from.
+// It is satisfied by clients.Client.
+type DiscoveryBackend interface {
+	CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+	CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error)
+}
+
+// Creation describes the contract created in the block, either by the transaction or by the factory contract.
+type Creation struct {
+	Address          common.Address  `json:"address"`           // Address of the created contract.
+	TransactionHash  common.Hash     `json:"transaction_hash"`  // Hash of the transaction the contract was created in.
+	TransactionIndex int             `json:"transaction_index"` // Index of the transaction in the block.
+	Deployer         common.Address  `json:"deployer"`          // Sender of the transaction the contract was created in.
+	Factory          common.Address  `json:"factory"`           // Contract that created the contract, zero when created by the transaction itself.
+	Type             CreationType    `json:"type"`              // Opcode the contract was created with, empty when unknown.
+	Salt             *common.Hash    `json:"salt"`              // Salt of the CREATE2 creation, nil when it cannot be recovered.
+	InitCodeHash     common.Hash     `json:"init_code_hash"`    // Hash of the init code, zero when unknown.
+	Method           DiscoveryMethod `json:"method"`            // Method the creation was discovered with.
+}
+
+// CreationDiscoverer discovers the contracts created in the blocks, including the ones created by the factories.
+// In the auto mode, the tracing methods the node does not provide are remembered and not requested again.
+type CreationDiscoverer struct {
+	backend     DiscoveryBackend
+	method      DiscoveryMethod
+	mu          sync.Mutex
+	unsupported map[DiscoveryMethod]bool
+}
+
+// NewCreationDiscoverer creates the discoverer of the contract creations using the given method, the auto
+// discovery when empty.
+func NewCreationDiscoverer(backend DiscoveryBackend, method DiscoveryMethod) (*CreationDiscoverer, error) {
+	if backend == nil {
+		return nil, errors.New("discovery backend is not set")
+	}
+
+	if method == "" {
+		method = AutoDiscovery
+	}
+
+	switch method {
+	case AutoDiscovery, DebugTraceDiscovery, TraceBlockDiscovery, CodeDiffDiscovery:
+	default:
+		return nil, fmt.Errorf("unknown discovery method: %s", method)
+	}
+
+	return &CreationDiscoverer{
+		backend:     backend,
+		method:      method,
+		unsupported: make(map[DiscoveryMethod]bool),
+	}, nil
+}
+
+// GetMethod returns the method the creations are discovered with.
+func (d *CreationDiscoverer) GetMethod() DiscoveryMethod {
+	return d.method
+}
+
+// Discover returns the contracts successfully created in the block, in the order of their creation.
+// Contracts created by the reverted calls are not reported.
+func (d *CreationDiscoverer) Discover(ctx context.Context, block *types.Block) ([]*Creation, error) {
+	if block == nil {
+		return nil, errors.New("block is not set")
+	}
+
+	if d.method != AutoDiscovery {
+		return d.discover(ctx, block, d.method)
+	}
+
+	for _, method := range []DiscoveryMethod{DebugTraceDiscovery, TraceBlockDiscovery} {
+		d.mu.Lock()
+		unsupported := d.unsupported[method]
+		d.mu.Unlock()
+		if unsupported {
+			continue
+		}
+
+		creations, err := d.discover(ctx, block, method)
+		if err == nil {
+			return creations, nil
+		}
+
+		if isMethodUnsupported(err) {
+			d.mu.Lock()
+			d.unsupported[method] = true
+			d.mu.Unlock()
+		}
+
+		zap.L().Debug(
+			"failure while discovering contract creations, falling back",
+			zap.Error(err),
+			zap.String("method", method.String()),
+			zap.Uint64("block_number", block.NumberU64()),
+		)
+	}
+
+	return d.discover(ctx, block, CodeDiffDiscovery)
+}
+
+// discover returns the creations discovered with the given method.
+func (d *CreationDiscoverer) discover(ctx context.Context, block *types.Block, method DiscoveryMethod) ([]*Creation, error) {
+	switch method {
+	case DebugTraceDiscovery:
+		return d.discoverFromCallTraces(ctx, block)
+	case TraceBlockDiscovery:
+		return d.discoverFromBlockTraces(ctx, block)
+	default:
+		return d.discoverFromCodeDiff(ctx, block)
+	}
+}
+
+// callFrame is the call frame reported by the callTracer.
+type callFrame struct {
+	Type  string          `json:"type"`
+	From  common.Address  `json:"from"`
+	To    *common.Address `json:"to,omitempty"`
+	Input hexutil.Bytes   `json:"input"`
+	Error string          `json:"error,omitempty"`
+	Calls []*callFrame    `json:"calls,omitempty"`
+}
+
+// callTraceResult is the callTracer result of the single transaction.
+type callTraceResult struct {
+	TxHash common.Hash `json:"txHash"`
+	Result *callFrame  `json:"result"`
+	Error  string      `json:"error,omitempty"`
+}
+
+// discoverFromCallTraces walks the call frames of the block transactions.
+func (d *CreationDiscoverer) discoverFromCallTraces(ctx context.Context, block *types.Block) ([]*Creation, error) {
+	var results []*callTraceResult
+	if err := d.backend.CallContext(ctx, &results, "debug_traceBlockByHash", block.Hash(), map[string]interface{}{"tracer": "callTracer"}); err != nil {
+		return nil, fmt.Errorf("failed to trace block %d: %w", block.NumberU64(), err)
+	}
+
+	txs := block.Transactions()
+	if len(results) != len(txs) {
+		return nil, fmt.Errorf("traced %d transactions of the %d in block %d", len(results), len(txs), block.NumberU64())
+	}
+
+	toReturn := make([]*Creation, 0)
+	for i, result := range results {
+		if result.Error != "" || result.Result == nil {
+			return nil, fmt.Errorf("failed to trace transaction %s: %s", txs[i].Hash().Hex(), result.Error)
+		}
+
+		root := result.Result
+		var walk func(frame *callFrame, parentInput []byte, depth int)
+		walk = func(frame *callFrame, parentInput []byte, depth int) {
+			// State changes of the reverted frame, the created contracts included, are discarded.
+			if frame.Error != "" {
+				return
+			}
+
+			creationType := CreationType(strings.ToUpper(frame.Type))
+			if (creationType == CreateCreation || creationType == Create2Creation) && frame.To != nil {
+				creation := &Creation{
+					Address:          *frame.To,
+					TransactionHash:  txs[i].Hash(),
+					TransactionIndex: i,
+					Deployer:         root.From,
+					Type:             creationType,
+					InitCodeHash:     crypto.Keccak256Hash(frame.Input),
+					Method:           DebugTraceDiscovery,
+				}
+
+				if depth > 0 {
+					creation.Factory = frame.From
+				}
+
+				if creationType == Create2Creation {
+					creation.Salt = findSalt(frame.From, creation.Address, creation.InitCodeHash, parentInput)
+				}
+
+				toReturn = append(toReturn, creation)
+			}
+
+			for _, call := range frame.Calls {
+				walk(call, frame.Input, depth+1)
+			}
+		}
+		walk(root, nil, 0)
+	}
+
+	return toReturn, nil
+}
+
+// blockTraceAction is the action of the trace reported by trace_block.
+type blockTraceAction struct {
+	From           common.Address `json:"from"`
+	Input          hexutil.Bytes  `json:"input,omitempty"`
+	Init           hexutil.Bytes  `json:"init,omitempty"`
+	CreationMethod string         `json:"creationMethod,omitempty"`
+}
+
+// blockTraceResult is the result of the trace reported by trace_block.
+type blockTraceResult struct {
+	Address *common.Address `json:"address,omitempty"`
+}
+
+// blockTrace is the trace reported by trace_block.
+type blockTrace struct {
+	Type                string            `json:"type"`
+	Action              blockTraceAction  `json:"action"`
+	Result              *blockTraceResult `json:"result"`
+	Error               string            `json:"error,omitempty"`
+	TraceAddress        []int             `json:"traceAddress"`
+	TransactionHash     *common.Hash      `json:"transactionHash"`
+	TransactionPosition *int              `json:"transactionPosition"`
+}
+
+// input returns the data the traced call or creation was executed with.
+func (t *blockTrace) input() []byte {
+	if t.Type == "create" {
+		return t.Action.Init
+	}
+	return t.Action.Input
+}
+
+// discoverFromBlockTraces walks the create traces of the block transactions.
+func (d *CreationDiscoverer) discoverFromBlockTraces(ctx context.Context, block *types.Block) ([]*Creation, error) {
+	var traces []*blockTrace
+	if err := d.backend.CallContext(ctx, &traces, "trace_block", hexutil.EncodeBig(block.Number())); err != nil {
+		return nil, fmt.Errorf("failed to trace block %d: %w", block.NumberU64(), err)
+	}
+
+	// Traces are grouped by the transactions and identified by their positions in the call tree.
+	byTx := make(map[int]map[string]*blockTrace)
+	for _, trace := range traces {
+		if trace.TransactionPosition == nil || trace.TransactionHash == nil {
+			continue
+		}
+
+		if _, ok := byTx[*trace.TransactionPosition]; !ok {
+			byTx[*trace.TransactionPosition] = make(map[string]*blockTrace)
+		}
+		byTx[*trace.TransactionPosition][traceKey(trace.TraceAddress)] = trace
+	}
+
+	toReturn := make([]*Creation, 0)
+	for _, trace := range traces {
+		if trace.Type != "create" || trace.Result == nil || trace.Result.Address == nil || trace.TransactionPosition == nil {
+			continue
+		}
+
+		txTraces := byTx[*trace.TransactionPosition]
+		if reverted(txTraces, trace.TraceAddress) {
+			continue
+		}
+
+		root, ok := txTraces[traceKey(nil)]
+		if !ok {
+			return nil, fmt.Errorf("missing root trace of transaction %s", trace.TransactionHash.Hex())
+		}
+
+		creation := &Creation{
+			Address:          *trace.Result.Address,
+			TransactionHash:  *trace.TransactionHash,
+			TransactionIndex: *trace.TransactionPosition,
+			Deployer:         root.Action.From,
+			Type:             CreateCreation,
+			InitCodeHash:     crypto.Keccak256Hash(trace.Action.Init),
+			Method:           TraceBlockDiscovery,
+		}
+
+		if len(trace.TraceAddress) > 0 {
+			creation.Factory = trace.Action.From
+
+			var parentInput []byte
+			if parent, ok := txTraces[traceKey(trace.TraceAddress[:len(trace.TraceAddress)-1])]; ok {
+				parentInput = parent.input()
+			}
+			creation.Salt = findSalt(creation.Factory, creation.Address, creation.InitCodeHash, parentInput)
+		}
+
+		// Not every client reports the creation method, so the recovered salt tells the CREATE2 apart as well.
+		if strings.EqualFold(trace.Action.CreationMethod, "create2") || creation.Salt != nil {
+			creation.Type = Create2Creation
+		}
+
+		toReturn = append(toReturn, creation)
+	}
+
+	return toReturn, nil
+}
+
+// discoverFromCodeDiff compares the code of the addresses touched by the block transactions before and after
+// the block. Contracts created by the transactions themselves are fully described by their receipts, while the
+// ones created by the factories are found only when they emit logs or are the transaction recipients, and are
+// reported without the creation type, salt and init code hash.
+func (d *CreationDiscoverer) discoverFromCodeDiff(ctx context.Context, block *types.Block) ([]*Creation, error) {
+	txs := block.Transactions()
+	receipts := make([]*types.Receipt, len(txs))
+
+	g, gctx := errgroup.WithContext(ctx)
+	for i, tx := range txs {
+		g.Go(func() error {
+			receipt, err := d.backend.TransactionReceipt(gctx, tx.Hash())
+			if err != nil {
+				return fmt.Errorf("failed to get receipt of transaction %s: %w", tx.Hash().Hex(), err)
+			}
+			receipts[i] = receipt
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	parentNumber := new(big.Int).Sub(block.Number(), big.NewInt(1))
+	seen := make(map[common.Address]bool)
+	toReturn := make([]*Creation, 0)
+
+	for i, tx := range txs {
+		receipt := receipts[i]
+		if receipt.Status != types.ReceiptStatusSuccessful {
+			continue
+		}
+
+		deployer, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to recover sender of transaction %s: %w", tx.Hash().Hex(), err)
+		}
+
+		if receipt.ContractAddress != (common.Address{}) {
+			seen[receipt.ContractAddress] = true
+			toReturn = append(toReturn, &Creation{
+				Address:          receipt.ContractAddress,
+				TransactionHash:  tx.Hash(),
+				TransactionIndex: i,
+				Deployer:         deployer,
+				Type:             CreateCreation,
+				InitCodeHash:     crypto.Keccak256Hash(tx.Data()),
+				Method:           CodeDiffDiscovery,
+			})
+		}
+
+		touched := make([]common.Address, 0, len(receipt.Logs)+1)
+		if tx.To() != nil {
+			touched = append(touched, *tx.To())
+		}
+		for _, log := range receipt.Logs {
+			touched = append(touched, log.Address)
+		}
+
+		for _, address := range touched {
+			if seen[address] {
+				continue
+			}
+			seen[address] = true
+
+			created, err := d.isCreated(ctx, address, parentNumber, block.Number())
+			if err != nil {
+				return nil, err
+			}
+
+			if created {
+				creation := &Creation{
+					Address:          address,
+					TransactionHash:  tx.Hash(),
+					TransactionIndex: i,
+					Deployer:         deployer,
+					Method:           CodeDiffDiscovery,
+				}
+
+				if tx.To() != nil && *tx.To() != address {
+					creation.Factory = *tx.To()
+				}
+
+				toReturn = append(toReturn, creation)
+			}
+		}
+	}
+
+	return toReturn, nil
+}
+
+// isCreated reports whether the address has no code before the block and has code after it.
+func (d *CreationDiscoverer) isCreated(ctx context.Context, address common.Address, parentNumber, blockNumber *big.Int) (bool, error) {
+	code, err := d.backend.CodeAt(ctx, address, blockNumber)
+	if err != nil {
+		return false, fmt.Errorf("failed to get code of %s: %w", address.Hex(), err)
+	}
+
+	if len(code) == 0 {
+		return false, nil
+	}
+
+	code, err = d.backend.CodeAt(ctx, address, parentNumber)
+	if err != nil {
+		return false, fmt.Errorf("failed to get code of %s: %w", address.Hex(), err)
+	}
+
+	return len(code) == 0, nil
+}
+
+// findSalt recovers the salt of the CREATE2 creation from the input of the call that executed it, which holds
+// the salt whenever the factory is given it verbatim. Nil is returned when no word of the input derives the address.
+func findSalt(factory common.Address, address common.Address, initCodeHash common.Hash, input []byte) *common.Hash {
+	for i := 0; i+common.HashLength <= len(input); i++ {
+		salt := common.BytesToHash(input[i : i+common.HashLength])
+		if crypto.CreateAddress2(factory, salt, initCodeHash.Bytes()) == address {
+			return &salt
+		}
+	}
+	return nil
+}
+
+// traceKey returns the key identifying the trace by its position in the call tree.
+func traceKey(traceAddress []int) string {
+	parts := make([]string, len(traceAddress))
+	for i, position := range traceAddress {
+		parts[i] = strconv.Itoa(position)
+	}
+	return strings.Join(parts, ",")
+}
+
+// reverted reports whether the trace, or any of the calls it was executed within, failed.
+func reverted(traces map[string]*blockTrace, traceAddress []int) bool {
+	for i := 0; i <= len(traceAddress); i++ {
+		if trace, ok := traces[traceKey(traceAddress[:i])]; ok && trace.Error != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// isMethodUnsupported reports whether the error is returned by the node that does not provide the method.
+func isMethodUnsupported(err error) bool {
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		return rpcErr.ErrorCode() == methodNotFoundCode || rpcErr.ErrorCode() == methodNotSupportedCode
+	}
+	return false
+}
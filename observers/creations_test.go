@@ -0,0 +1,321 @@
+package observers
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rpcError is the JSON-RPC error returned by the mock backend.
+type rpcError struct {
+	code int
+}
+
+func (e *rpcError) Error() string {
+	return "the method does not exist/is not available"
+}
+
+func (e *rpcError) ErrorCode() int {
+	return e.code
+}
+
+// mockDiscoveryBackend serves the traces, receipts and code of the single block.
+type mockDiscoveryBackend struct {
+	mu       sync.Mutex
+	traces   map[string]interface{}
+	receipts map[common.Hash]*types.Receipt
+	code     map[uint64]map[common.Address][]byte
+	calls    map[string]int
+}
+
+func (b *mockDiscoveryBackend) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	b.mu.Lock()
+	b.calls[method]++
+	traces, ok := b.traces[method]
+	b.mu.Unlock()
+
+	if !ok {
+		return &rpcError{code: methodNotFoundCode}
+	}
+
+	raw, err := json.Marshal(traces)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, result)
+}
+
+func (b *mockDiscoveryBackend) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if receipt, ok := b.receipts[txHash]; ok {
+		return receipt, nil
+	}
+	return nil, ethereum.NotFound
+}
+
+func (b *mockDiscoveryBackend) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.code[blockNumber.Uint64()][account], nil
+}
+
+// creationFixture is the block deploying the contract directly and the pair through the factory, together
+// with the failed deployment through the same factory.
+type creationFixture struct {
+	block     *types.Block
+	deployer  common.Address
+	factory   common.Address
+	contract  common.Address
+	pair      common.Address
+	salt      common.Hash
+	initCode  []byte
+	pairCode  []byte
+	backend   *mockDiscoveryBackend
+	reverted  common.Address
+	factoryIn []byte
+}
+
+func newCreationFixture(t *testing.T) *creationFixture {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	signer := types.LatestSignerForChainID(big.NewInt(1))
+	fixture := &creationFixture{
+		deployer: crypto.PubkeyToAddress(key.PublicKey),
+		factory:  common.HexToAddress("0x5C69bEe701ef814a2B6a3EDD4B1652CB9cc5aA6f"),
+		salt:     common.HexToHash("0x0000000000000000000000000000000000000000000000000000000000abcdef"),
+		initCode: common.FromHex("0x6080604052348015600f57600080fd5b50"),
+		pairCode: common.FromHex("0x60806040526001"),
+		reverted: common.HexToAddress("0x00000000000000000000000000000000000000aa"),
+	}
+	fixture.contract = crypto.CreateAddress(fixture.deployer, 0)
+	fixture.pair = crypto.CreateAddress2(fixture.factory, fixture.salt, crypto.Keccak256(fixture.pairCode))
+
+	// Calldata of the factory call passes the salt after the selector.
+	fixture.factoryIn = append(common.FromHex("0x12345678"), fixture.salt.Bytes()...)
+
+	deployTx, err := types.SignTx(types.NewContractCreation(0, big.NewInt(0), 1_000_000, big.NewInt(1), fixture.initCode), signer, key)
+	require.NoError(t, err)
+	factoryTx, err := types.SignTx(types.NewTransaction(1, fixture.factory, big.NewInt(0), 1_000_000, big.NewInt(1), fixture.factoryIn), signer, key)
+	require.NoError(t, err)
+
+	header := &types.Header{Number: big.NewInt(100), Difficulty: big.NewInt(0)}
+	fixture.block = types.NewBlock(header, []*types.Transaction{deployTx, factoryTx}, nil, nil, trie.NewStackTrie(nil))
+
+	contract, pair, reverted := fixture.contract, fixture.pair, fixture.reverted
+	fixture.backend = &mockDiscoveryBackend{
+		traces: map[string]interface{}{
+			"debug_traceBlockByHash": []*callTraceResult{
+				{
+					TxHash: deployTx.Hash(),
+					Result: &callFrame{Type: "CREATE", From: fixture.deployer, To: &contract, Input: fixture.initCode},
+				},
+				{
+					TxHash: factoryTx.Hash(),
+					Result: &callFrame{
+						Type:  "CALL",
+						From:  fixture.deployer,
+						To:    &fixture.factory,
+						Input: fixture.factoryIn,
+						Calls: []*callFrame{
+							{Type: "CREATE2", From: fixture.factory, To: &pair, Input: fixture.pairCode},
+							{
+								Type:  "CALL",
+								From:  fixture.factory,
+								To:    &fixture.factory,
+								Error: "execution reverted",
+								Calls: []*callFrame{
+									{Type: "CREATE", From: fixture.factory, To: &reverted, Input: fixture.pairCode},
+								},
+							},
+						},
+					},
+				},
+			},
+			"trace_block": []*blockTrace{
+				blockTraceOf("create", 0, deployTx.Hash(), []int{}, blockTraceAction{From: fixture.deployer, Init: fixture.initCode}, &contract, ""),
+				blockTraceOf("call", 1, factoryTx.Hash(), []int{}, blockTraceAction{From: fixture.deployer, Input: fixture.factoryIn}, nil, ""),
+				blockTraceOf("create", 1, factoryTx.Hash(), []int{0}, blockTraceAction{From: fixture.factory, Init: fixture.pairCode}, &pair, ""),
+				blockTraceOf("call", 1, factoryTx.Hash(), []int{1}, blockTraceAction{From: fixture.factory}, nil, "Reverted"),
+				blockTraceOf("create", 1, factoryTx.Hash(), []int{1, 0}, blockTraceAction{From: fixture.factory, Init: fixture.pairCode}, &reverted, ""),
+				{Type: "reward", Action: blockTraceAction{From: fixture.deployer}},
+			},
+		},
+		receipts: map[common.Hash]*types.Receipt{
+			deployTx.Hash(): {Status: types.ReceiptStatusSuccessful, TxHash: deployTx.Hash(), ContractAddress: contract},
+			factoryTx.Hash(): {
+				Status: types.ReceiptStatusSuccessful,
+				TxHash: factoryTx.Hash(),
+				Logs:   []*types.Log{{Address: fixture.factory}, {Address: pair}},
+			},
+		},
+		code: map[uint64]map[common.Address][]byte{
+			99:  {fixture.factory: []byte{0x01}},
+			100: {fixture.factory: []byte{0x01}, contract: []byte{0x02}, pair: []byte{0x03}},
+		},
+		calls: make(map[string]int),
+	}
+
+	return fixture
+}
+
+func blockTraceOf(typ string, position int, txHash common.Hash, traceAddress []int, action blockTraceAction, address *common.Address, err string) *blockTrace {
+	toReturn := &blockTrace{
+		Type:                typ,
+		Action:              action,
+		Error:               err,
+		TraceAddress:        traceAddress,
+		TransactionHash:     &txHash,
+		TransactionPosition: &position,
+	}
+
+	if err == "" {
+		toReturn.Result = &blockTraceResult{Address: address}
+	}
+	return toReturn
+}
+
+func TestCreationDiscoverer(t *testing.T) {
+	// Contract deployed by the transaction and the pair deployed by the factory, as reported by each method.
+	traced := func(f *creationFixture, method DiscoveryMethod) []*Creation {
+		return []*Creation{
+			{Address: f.contract, TransactionIndex: 0, Deployer: f.deployer, Type: CreateCreation, InitCodeHash: crypto.Keccak256Hash(f.initCode), Method: method},
+			{Address: f.pair, TransactionIndex: 1, Deployer: f.deployer, Factory: f.factory, Type: Create2Creation, Salt: &f.salt, InitCodeHash: crypto.Keccak256Hash(f.pairCode), Method: method},
+		}
+	}
+	diffed := func(f *creationFixture) []*Creation {
+		return []*Creation{
+			{Address: f.contract, TransactionIndex: 0, Deployer: f.deployer, Type: CreateCreation, InitCodeHash: crypto.Keccak256Hash(f.initCode), Method: CodeDiffDiscovery},
+			{Address: f.pair, TransactionIndex: 1, Deployer: f.deployer, Factory: f.factory, Method: CodeDiffDiscovery},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		method    DiscoveryMethod
+		remove    []string
+		expected  func(f *creationFixture) []*Creation
+		wantCalls map[string]int
+	}{
+		{
+			name:     "Debug Trace",
+			method:   DebugTraceDiscovery,
+			expected: func(f *creationFixture) []*Creation { return traced(f, DebugTraceDiscovery) },
+		},
+		{
+			name:     "Trace Block",
+			method:   TraceBlockDiscovery,
+			expected: func(f *creationFixture) []*Creation { return traced(f, TraceBlockDiscovery) },
+		},
+		{
+			name:     "Code Diff",
+			method:   CodeDiffDiscovery,
+			expected: diffed,
+		},
+		{
+			name:      "Auto Falls Back To Trace Block",
+			method:    AutoDiscovery,
+			remove:    []string{"debug_traceBlockByHash"},
+			expected:  func(f *creationFixture) []*Creation { return traced(f, TraceBlockDiscovery) },
+			wantCalls: map[string]int{"debug_traceBlockByHash": 1, "trace_block": 2},
+		},
+		{
+			name:      "Auto Falls Back To Code Diff",
+			method:    AutoDiscovery,
+			remove:    []string{"debug_traceBlockByHash", "trace_block"},
+			expected:  diffed,
+			wantCalls: map[string]int{"debug_traceBlockByHash": 1, "trace_block": 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fixture := newCreationFixture(t)
+			for _, method := range tt.remove {
+				delete(fixture.backend.traces, method)
+			}
+
+			expected := tt.expected(fixture)
+			for _, creation := range expected {
+				creation.TransactionHash = fixture.block.Transactions()[creation.TransactionIndex].Hash()
+			}
+
+			discoverer, err := NewCreationDiscoverer(fixture.backend, tt.method)
+			require.NoError(t, err)
+
+			// Unsupported methods are requested only by the first discovery.
+			for i := 0; i < 2; i++ {
+				creations, err := discoverer.Discover(context.Background(), fixture.block)
+				require.NoError(t, err)
+				assert.Equal(t, expected, creations)
+			}
+
+			if tt.wantCalls != nil {
+				assert.Equal(t, tt.wantCalls, fixture.backend.calls)
+			}
+		})
+	}
+}
+
+func TestNewCreationDiscoverer(t *testing.T) {
+	discoverer, err := NewCreationDiscoverer(&mockDiscoveryBackend{}, "")
+	require.NoError(t, err)
+	assert.Equal(t, AutoDiscovery, discoverer.GetMethod())
+
+	_, err = NewCreationDiscoverer(&mockDiscoveryBackend{}, "unknown")
+	assert.Error(t, err)
+
+	_, err = NewCreationDiscoverer(nil, AutoDiscovery)
+	assert.Error(t, err)
+}
+
+func TestDiscoverContracts(t *testing.T) {
+	fixture := newCreationFixture(t)
+	discoverer, err := NewCreationDiscoverer(fixture.backend, DebugTraceDiscovery)
+	require.NoError(t, err)
+
+	subscriber, err := NewContractSubscriber(context.Background(), nil)
+	require.NoError(t, err)
+
+	opts := &ContractSubscriberOptions{NetworkID: 1, Group: "ethereum", Type: "archive"}
+	contracts, err := subscriber.discoverContracts(discoverer, fixture.backend, fixture.block, opts)
+	require.NoError(t, err)
+	require.Len(t, contracts, 2)
+
+	for i, contract := range contracts {
+		tx := fixture.block.Transactions()[i]
+		assert.Equal(t, int64(1), contract.NetworkID)
+		assert.Equal(t, contract.Creation.Address, contract.ContractAddress)
+		assert.Equal(t, tx, contract.Transaction)
+		assert.Equal(t, fixture.backend.receipts[tx.Hash()], contract.Receipt)
+	}
+	assert.Equal(t, fixture.factory, contracts[1].Creation.Factory)
+}
+
+func TestFindSalt(t *testing.T) {
+	factory := common.HexToAddress("0x5C69bEe701ef814a2B6a3EDD4B1652CB9cc5aA6f")
+	salt := common.HexToHash("0x01")
+	initCodeHash := crypto.Keccak256Hash([]byte{0x60, 0x00})
+	address := crypto.CreateAddress2(factory, salt, initCodeHash.Bytes())
+
+	// Salt is found at any offset of the input.
+	input := append(append([]byte{0xde, 0xad, 0xbe}, salt.Bytes()...), 0xef)
+	found := findSalt(factory, address, initCodeHash, input)
+	require.NotNil(t, found)
+	assert.Equal(t, salt, *found)
+
+	assert.Nil(t, findSalt(factory, address, initCodeHash, []byte{0x01}))
+	assert.Nil(t, findSalt(factory, common.Address{}, initCodeHash, input))
+}
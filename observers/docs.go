@@ -1,5 +1,7 @@
 // Package observers provides tools for managing and interacting with Ethereum onchain data such as blocks, transactions, events and logs.
 // It provides a BlockSubscriber structure that allows for subscribing to block headers based on various criteria, such as the latest block or a range of blocks.
+// Subscriptions persist the last processed block, so they resume and backfill the missed blocks after restarts and reconnects, and report the reorganizations detected by the parent hashes as the removed blocks.
 // It also provides a ContractSubscriber structure that allows for subscribing to old or new contracts based on various criteria, such as the latest blocks or a range of blocks.
+// Contracts created by the factories are discovered from the debug_traceBlockByHash or trace_block traces, falling back to the code diff of the touched addresses, along with the deployer, factory, salt and init code hash of every creation.
 // The package is designed to be flexible and efficient, ensuring that Ethereum onchain data can be easily accessed based on the specific needs of the application.
 package observers
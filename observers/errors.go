@@ -0,0 +1,12 @@
+package observers
+
+import "errors"
+
+var (
+	// ErrSubscriberActive is returned when the subscription is started on the subscriber that is already active.
+	ErrSubscriberActive = errors.New("block subscriber is already active")
+
+	// ErrReorgTooDeep is returned when the chain reorganization reaches beyond the recent blocks tracked by the
+	// subscriber, so the common ancestor of the chains cannot be found.
+	ErrReorgTooDeep = errors.New("chain reorganization deeper than the tracked blocks")
+)
@@ -0,0 +1,385 @@
+package observers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultReorgDepth is the number of the recent blocks the chain reorganizations are resolved against.
+	DefaultReorgDepth = 64
+
+	// DefaultPollInterval is the interval the chain head is polled at by the polling subscriptions.
+	DefaultPollInterval = 3 * time.Second
+
+	// DefaultReconnectDelay is the delay before the interrupted subscription reconnects.
+	DefaultReconnectDelay = time.Second
+)
+
+// ChainBackend provides the headers and blocks of the chain. It is satisfied by clients.Client.
+type ChainBackend interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	BlockByHash(ctx context.Context, hash common.Hash) (*types.Block, error)
+	SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error)
+}
+
+// ResumableSubscriberOptions defines the options for configuring the resumable block subscription.
+type ResumableSubscriberOptions struct {
+	Group            string          `mapstructure:"group" yaml:"group" json:"group"`                                        // The group of the blockchain client.
+	Type             string          `mapstructure:"type" yaml:"type" json:"type"`                                           // The type of the blockchain client.
+	StartBlockNumber *big.Int        `mapstructure:"start_block_number" yaml:"start_block_number" json:"start_block_number"` // The first block when there is no checkpoint, the chain head when nil.
+	FullBlocks       bool            `mapstructure:"full_blocks" yaml:"full_blocks" json:"full_blocks"`                      // Flag to indicate if the blocks are fetched together with the headers.
+	Polling          bool            `mapstructure:"polling" yaml:"polling" json:"polling"`                                  // Flag to indicate if the chain head is polled instead of subscribed to.
+	PollInterval     time.Duration   `mapstructure:"poll_interval" yaml:"poll_interval" json:"poll_interval"`                // The interval the chain head is polled at.
+	ReconnectDelay   time.Duration   `mapstructure:"reconnect_delay" yaml:"reconnect_delay" json:"reconnect_delay"`          // The delay before the interrupted subscription reconnects.
+	ReorgDepth       int             `mapstructure:"reorg_depth" yaml:"reorg_depth" json:"reorg_depth"`                      // The number of the recent blocks tracked for the chain reorganizations.
+	Store            CheckpointStore `mapstructure:"-" yaml:"-" json:"-"`                                                    // The store of the checkpoint, kept in memory when nil.
+}
+
+// BlockEvent is the notification of the block added to, or removed from, the canonical chain.
+type BlockEvent struct {
+	Number  uint64        `json:"number"`          // Number of the block.
+	Hash    common.Hash   `json:"hash"`            // Hash of the block.
+	Header  *types.Header `json:"header"`          // Header of the block, nil for the removed blocks processed before the subscription resumed.
+	Block   *types.Block  `json:"block,omitempty"` // Block with the transactions, when the full blocks are requested for the added blocks.
+	Removed bool          `json:"removed"`         // Flag to indicate if the block was reorganized out of the chain.
+}
+
+// SubscribeResumable subscribes to the canonical chain, emitting the event of every block added to it, and the
+// removed event of every block reorganized out of it, newest first, before the blocks replacing them. The
+// subscription resumes from the checkpoint of the store, backfilling the blocks missed while disconnected, and
+// reconnects until the subscriber is closed. Events are sent one at a time, so the subscription advances only
+// as fast as the channel is read, and the checkpoint is saved once the event is received, so the events are
+// delivered at least once. Endpoints without the subscriptions are polled.
+func (b *BlockSubscriber) SubscribeResumable(opts *ResumableSubscriberOptions, eventCh chan<- *BlockEvent) error {
+	if opts == nil {
+		return errors.New("resumable subscriber options are not set")
+	}
+
+	return b.subscribeResumable(func() (ChainBackend, error) {
+		client := b.client.GetClientByGroupAndType(opts.Group, opts.Type)
+		if client == nil {
+			return nil, errors.New("client not found")
+		}
+		return client, nil
+	}, opts, eventCh)
+}
+
+// subscribeResumable runs the resumable subscription, connecting to the backend on every reconnect.
+func (b *BlockSubscriber) subscribeResumable(connect func() (ChainBackend, error), opts *ResumableSubscriberOptions, eventCh chan<- *BlockEvent) error {
+	if !b.active.CompareAndSwap(false, true) {
+		return ErrSubscriberActive
+	}
+	defer b.active.Store(false)
+
+	ctx, cancel := context.WithCancel(b.ctx)
+	defer cancel()
+
+	b.mu.Lock()
+	b.cancel = cancel
+	b.mu.Unlock()
+
+	stream, err := newBlockStream(ctx, opts, eventCh)
+	if err != nil {
+		return err
+	}
+
+	for {
+		err := stream.run(ctx, connect)
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		if errors.Is(err, ErrReorgTooDeep) {
+			return err
+		}
+
+		zap.L().Warn(
+			"resumable block subscription interrupted, reconnecting",
+			zap.Error(err),
+			zap.Uint64("last_block_number", stream.lastNumber()),
+		)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(stream.opts.ReconnectDelay):
+		}
+	}
+}
+
+// blockStream follows the canonical chain from the checkpoint, emitting the block events.
+type blockStream struct {
+	opts    ResumableSubscriberOptions
+	eventCh chan<- *BlockEvent
+	recent  []BlockRef
+	headers map[common.Hash]*types.Header
+}
+
+// newBlockStream creates the stream resuming from the checkpoint of the store.
+func newBlockStream(ctx context.Context, opts *ResumableSubscriberOptions, eventCh chan<- *BlockEvent) (*blockStream, error) {
+	toReturn := &blockStream{
+		opts:    *opts,
+		eventCh: eventCh,
+		recent:  make([]BlockRef, 0),
+		headers: make(map[common.Hash]*types.Header),
+	}
+
+	if toReturn.opts.PollInterval == 0 {
+		toReturn.opts.PollInterval = DefaultPollInterval
+	}
+
+	if toReturn.opts.ReconnectDelay == 0 {
+		toReturn.opts.ReconnectDelay = DefaultReconnectDelay
+	}
+
+	if toReturn.opts.ReorgDepth <= 0 {
+		toReturn.opts.ReorgDepth = DefaultReorgDepth
+	}
+
+	if toReturn.opts.Store == nil {
+		toReturn.opts.Store = NewMemoryCheckpointStore()
+	}
+
+	checkpoint, err := toReturn.opts.Store.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if checkpoint != nil {
+		toReturn.recent = append(toReturn.recent, checkpoint.Recent...)
+		if len(toReturn.recent) == 0 || toReturn.recent[len(toReturn.recent)-1].Hash != checkpoint.Hash {
+			toReturn.recent = append(toReturn.recent, BlockRef{Number: checkpoint.Number, Hash: checkpoint.Hash})
+		}
+	}
+
+	return toReturn, nil
+}
+
+// run connects to the backend and follows the chain head until the subscription is interrupted.
+func (s *blockStream) run(ctx context.Context, connect func() (ChainBackend, error)) error {
+	backend, err := connect()
+	if err != nil {
+		return err
+	}
+
+	var sub ethereum.Subscription
+	headCh := make(chan *types.Header, 1)
+
+	if !s.opts.Polling {
+		sub, err = backend.SubscribeNewHead(ctx, headCh)
+		if err != nil && !errors.Is(err, rpc.ErrNotificationsUnsupported) {
+			return fmt.Errorf("failed to subscribe to new heads: %w", err)
+		}
+
+		if sub != nil {
+			defer sub.Unsubscribe()
+		}
+	}
+
+	// Backfills the blocks missed while disconnected.
+	if err := s.sync(ctx, backend); err != nil {
+		return err
+	}
+
+	if sub == nil {
+		ticker := time.NewTicker(s.opts.PollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := s.sync(ctx, backend); err != nil {
+					return err
+				}
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+
+	for {
+		select {
+		case <-headCh:
+			// Heads are only the signal to catch up, so the heads received meanwhile are dropped.
+			drain(headCh)
+			if err := s.sync(ctx, backend); err != nil {
+				return err
+			}
+		case err := <-sub.Err():
+			if err == nil {
+				err = errors.New("subscription closed")
+			}
+			return err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// sync emits the events of the blocks up to the chain head, resolving the reorganizations by the parent hashes.
+func (s *blockStream) sync(ctx context.Context, backend ChainBackend) error {
+	head, err := backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get chain head: %w", err)
+	}
+
+	next := head.Number.Uint64()
+	if last := s.last(); last != nil {
+		next = last.Number + 1
+	} else if s.opts.StartBlockNumber != nil {
+		next = s.opts.StartBlockNumber.Uint64()
+	}
+
+	for next <= head.Number.Uint64() {
+		header, err := backend.HeaderByNumber(ctx, new(big.Int).SetUint64(next))
+		if err != nil {
+			// Chain is reorganized to the shorter one, the blocks are processed once it grows again.
+			if errors.Is(err, ethereum.NotFound) {
+				return nil
+			}
+			return fmt.Errorf("failed to get header of block %d: %w", next, err)
+		}
+
+		if last := s.last(); last != nil && header.ParentHash != last.Hash {
+			ancestor, err := s.rewind(ctx, backend)
+			if err != nil {
+				return err
+			}
+			next = ancestor + 1
+			continue
+		}
+
+		if err := s.add(ctx, backend, header); err != nil {
+			return err
+		}
+		next++
+	}
+
+	return nil
+}
+
+// rewind emits the removed events of the recent blocks that are no longer canonical, newest first, and returns
+// the number of the common ancestor of the chains.
+func (s *blockStream) rewind(ctx context.Context, backend ChainBackend) (uint64, error) {
+	for len(s.recent) > 0 {
+		last := s.recent[len(s.recent)-1]
+
+		header, err := backend.HeaderByNumber(ctx, new(big.Int).SetUint64(last.Number))
+		if err != nil && !errors.Is(err, ethereum.NotFound) {
+			return 0, fmt.Errorf("failed to get header of block %d: %w", last.Number, err)
+		}
+
+		if header != nil && header.Hash() == last.Hash {
+			return last.Number, nil
+		}
+
+		if len(s.recent) == 1 {
+			break
+		}
+
+		if err := s.send(ctx, &BlockEvent{Number: last.Number, Hash: last.Hash, Header: s.headers[last.Hash], Removed: true}); err != nil {
+			return 0, err
+		}
+
+		delete(s.headers, last.Hash)
+		s.recent = s.recent[:len(s.recent)-1]
+		if err := s.save(ctx); err != nil {
+			return 0, err
+		}
+	}
+
+	return 0, fmt.Errorf("%w: block %d is no longer canonical", ErrReorgTooDeep, s.lastNumber())
+}
+
+// add emits the event of the block added to the chain and tracks it.
+func (s *blockStream) add(ctx context.Context, backend ChainBackend, header *types.Header) error {
+	event := &BlockEvent{Number: header.Number.Uint64(), Hash: header.Hash(), Header: header}
+
+	if s.opts.FullBlocks {
+		block, err := backend.BlockByHash(ctx, event.Hash)
+		if err != nil {
+			return fmt.Errorf("failed to get block %d: %w", event.Number, err)
+		}
+		event.Block = block
+	}
+
+	if err := s.send(ctx, event); err != nil {
+		return err
+	}
+
+	s.recent = append(s.recent, BlockRef{Number: event.Number, Hash: event.Hash})
+	s.headers[event.Hash] = header
+
+	for len(s.recent) > s.opts.ReorgDepth {
+		delete(s.headers, s.recent[0].Hash)
+		s.recent = s.recent[1:]
+	}
+
+	return s.save(ctx)
+}
+
+// send delivers the event, waiting until it is received.
+func (s *blockStream) send(ctx context.Context, event *BlockEvent) error {
+	select {
+	case s.eventCh <- event:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// save stores the checkpoint of the last processed block.
+func (s *blockStream) save(ctx context.Context) error {
+	last := s.last()
+	if last == nil {
+		return nil
+	}
+
+	checkpoint := &BlockCheckpoint{
+		Number: last.Number,
+		Hash:   last.Hash,
+		Recent: append([]BlockRef{}, s.recent...),
+	}
+
+	if err := s.opts.Store.Save(ctx, checkpoint); err != nil {
+		return fmt.Errorf("failed to save block checkpoint: %w", err)
+	}
+	return nil
+}
+
+// last returns the last processed block, or nil when no block was processed yet.
+func (s *blockStream) last() *BlockRef {
+	if len(s.recent) == 0 {
+		return nil
+	}
+	return &s.recent[len(s.recent)-1]
+}
+
+// lastNumber returns the number of the last processed block, or zero when no block was processed yet.
+func (s *blockStream) lastNumber() uint64 {
+	if last := s.last(); last != nil {
+		return last.Number
+	}
+	return 0
+}
+
+// drain discards the heads waiting in the channel.
+func drain(headCh chan *types.Header) {
+	for {
+		select {
+		case <-headCh:
+		default:
+			return
+		}
+	}
+}
@@ -0,0 +1,343 @@
+package observers
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockChain is the chain backend serving the canonical headers, which can be extended and reorganized.
+type mockChain struct {
+	mu              sync.Mutex
+	headers         []*types.Header
+	fork            byte
+	noNotifications bool
+	subs            []*mockSubscription
+	connects        int
+}
+
+func newMockChain(length int) *mockChain {
+	toReturn := &mockChain{}
+	toReturn.extend(length)
+	return toReturn
+}
+
+// extend appends the blocks to the canonical chain and notifies the subscriptions.
+func (c *mockChain) extend(count int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i := 0; i < count; i++ {
+		header := &types.Header{Number: big.NewInt(int64(len(c.headers))), Extra: []byte{c.fork}, Difficulty: big.NewInt(0)}
+		if len(c.headers) > 0 {
+			header.ParentHash = c.headers[len(c.headers)-1].Hash()
+		}
+		c.headers = append(c.headers, header)
+	}
+
+	for _, sub := range c.subs {
+		sub.notify(c.headers[len(c.headers)-1])
+	}
+}
+
+// reorg replaces the blocks from the given number with the new fork of the given length.
+func (c *mockChain) reorg(from uint64, length int) {
+	c.mu.Lock()
+	c.headers = c.headers[:from]
+	c.fork++
+	c.mu.Unlock()
+
+	c.extend(length)
+}
+
+// hash returns the hash of the canonical block.
+func (c *mockChain) hash(number uint64) common.Hash {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.headers[number].Hash()
+}
+
+// disconnect fails the active subscriptions.
+func (c *mockChain) disconnect() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, sub := range c.subs {
+		sub.errCh <- errors.New("connection reset")
+	}
+	c.subs = nil
+}
+
+func (c *mockChain) connect() (ChainBackend, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.connects++
+	return c, nil
+}
+
+func (c *mockChain) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if number == nil {
+		return c.headers[len(c.headers)-1], nil
+	}
+
+	if number.Uint64() >= uint64(len(c.headers)) {
+		return nil, ethereum.NotFound
+	}
+	return c.headers[number.Uint64()], nil
+}
+
+func (c *mockChain) BlockByHash(ctx context.Context, hash common.Hash) (*types.Block, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, header := range c.headers {
+		if header.Hash() == hash {
+			return types.NewBlockWithHeader(header), nil
+		}
+	}
+	return nil, ethereum.NotFound
+}
+
+func (c *mockChain) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.noNotifications {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+
+	sub := &mockSubscription{ch: ch, errCh: make(chan error, 1)}
+	c.subs = append(c.subs, sub)
+	return sub, nil
+}
+
+// mockSubscription is the head subscription of the mock chain.
+type mockSubscription struct {
+	ch    chan<- *types.Header
+	errCh chan error
+	once  sync.Once
+}
+
+func (s *mockSubscription) notify(header *types.Header) {
+	select {
+	case s.ch <- header:
+	default:
+	}
+}
+
+func (s *mockSubscription) Err() <-chan error {
+	return s.errCh
+}
+
+func (s *mockSubscription) Unsubscribe() {
+	s.once.Do(func() {
+		close(s.errCh)
+	})
+}
+
+// startResumable runs the resumable subscription in the background, returning the channel of its result.
+func startResumable(t *testing.T, chain *mockChain, opts *ResumableSubscriberOptions, eventCh chan *BlockEvent) (*BlockSubscriber, chan error) {
+	subscriber, err := NewBlockSubscriber(context.Background(), nil)
+	require.NoError(t, err)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- subscriber.subscribeResumable(chain.connect, opts, eventCh)
+	}()
+
+	return subscriber, done
+}
+
+// expectEvents receives the events, asserting their numbers, hashes and removal flags.
+func expectEvents(t *testing.T, eventCh chan *BlockEvent, expected ...*BlockEvent) {
+	for _, want := range expected {
+		select {
+		case event := <-eventCh:
+			assert.Equal(t, want.Number, event.Number)
+			assert.Equal(t, want.Hash, event.Hash)
+			assert.Equal(t, want.Removed, event.Removed)
+			if !event.Removed {
+				require.NotNil(t, event.Header)
+				assert.Equal(t, event.Hash, event.Header.Hash())
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for block %d", want.Number)
+		}
+	}
+}
+
+// added returns the expected events of the canonical blocks in the range.
+func added(chain *mockChain, from, to uint64) []*BlockEvent {
+	toReturn := make([]*BlockEvent, 0)
+	for number := from; number <= to; number++ {
+		toReturn = append(toReturn, &BlockEvent{Number: number, Hash: chain.hash(number)})
+	}
+	return toReturn
+}
+
+func TestResumableSubscriber(t *testing.T) {
+	tests := []struct {
+		name            string
+		polling         bool
+		noNotifications bool
+	}{
+		{name: "Subscription"},
+		{name: "Polling", polling: true},
+		{name: "Notifications Unsupported", noNotifications: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chain := newMockChain(11)
+			chain.noNotifications = tt.noNotifications
+
+			store := NewMemoryCheckpointStore()
+			eventCh := make(chan *BlockEvent)
+			subscriber, done := startResumable(t, chain, &ResumableSubscriberOptions{
+				StartBlockNumber: big.NewInt(5),
+				Polling:          tt.polling,
+				PollInterval:     time.Millisecond,
+				Store:            store,
+			}, eventCh)
+
+			expectEvents(t, eventCh, added(chain, 5, 10)...)
+
+			chain.extend(1)
+			expectEvents(t, eventCh, added(chain, 11, 11)...)
+
+			removed := []*BlockEvent{
+				{Number: 11, Hash: chain.hash(11), Removed: true},
+				{Number: 10, Hash: chain.hash(10), Removed: true},
+			}
+			chain.reorg(10, 3)
+			expectEvents(t, eventCh, removed...)
+			expectEvents(t, eventCh, added(chain, 10, 12)...)
+
+			require.NoError(t, subscriber.Close())
+			require.NoError(t, <-done)
+			assert.False(t, subscriber.IsActive())
+
+			checkpoint, err := store.Load(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, uint64(12), checkpoint.Number)
+			assert.Equal(t, chain.hash(12), checkpoint.Hash)
+			assert.Len(t, checkpoint.Recent, 8)
+		})
+	}
+}
+
+func TestResumableSubscriberResume(t *testing.T) {
+	chain := newMockChain(6)
+	store := NewFileCheckpointStore(filepath.Join(t.TempDir(), "checkpoint.json"))
+	opts := &ResumableSubscriberOptions{StartBlockNumber: big.NewInt(3), Polling: true, PollInterval: time.Millisecond, Store: store}
+
+	eventCh := make(chan *BlockEvent)
+	subscriber, done := startResumable(t, chain, opts, eventCh)
+	expectEvents(t, eventCh, added(chain, 3, 5)...)
+	require.NoError(t, subscriber.Close())
+	require.NoError(t, <-done)
+
+	// Blocks are reorganized and added while the subscriber is stopped.
+	removed := &BlockEvent{Number: 5, Hash: chain.hash(5), Removed: true}
+	chain.reorg(5, 3)
+
+	subscriber, done = startResumable(t, chain, opts, eventCh)
+	expectEvents(t, eventCh, removed)
+	expectEvents(t, eventCh, added(chain, 5, 7)...)
+	require.NoError(t, subscriber.Close())
+	require.NoError(t, <-done)
+}
+
+func TestResumableSubscriberReconnect(t *testing.T) {
+	chain := newMockChain(3)
+	eventCh := make(chan *BlockEvent)
+	subscriber, done := startResumable(t, chain, &ResumableSubscriberOptions{
+		FullBlocks:     true,
+		ReconnectDelay: time.Millisecond,
+	}, eventCh)
+
+	// Without the checkpoint and the start block, the subscription starts from the chain head.
+	expectEvents(t, eventCh, added(chain, 2, 2)...)
+
+	chain.disconnect()
+	chain.extend(2)
+
+	// Blocks added while disconnected are backfilled after reconnecting.
+	for _, want := range added(chain, 3, 4) {
+		event := <-eventCh
+		assert.Equal(t, want.Hash, event.Hash)
+		require.NotNil(t, event.Block)
+		assert.Equal(t, want.Hash, event.Block.Hash())
+	}
+
+	require.NoError(t, subscriber.Close())
+	require.NoError(t, <-done)
+
+	chain.mu.Lock()
+	assert.Equal(t, 2, chain.connects)
+	chain.mu.Unlock()
+}
+
+func TestResumableSubscriberReorgTooDeep(t *testing.T) {
+	chain := newMockChain(5)
+	eventCh := make(chan *BlockEvent, 10)
+	_, done := startResumable(t, chain, &ResumableSubscriberOptions{
+		StartBlockNumber: big.NewInt(0),
+		Polling:          true,
+		PollInterval:     time.Millisecond,
+		ReorgDepth:       2,
+	}, eventCh)
+
+	expectEvents(t, eventCh, added(chain, 0, 4)...)
+
+	removed := &BlockEvent{Number: 4, Hash: chain.hash(4), Removed: true}
+	chain.reorg(2, 4)
+	expectEvents(t, eventCh, removed)
+
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, ErrReorgTooDeep)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the subscription to fail")
+	}
+}
+
+func TestResumableSubscriberBackPressure(t *testing.T) {
+	chain := newMockChain(10)
+	store := NewMemoryCheckpointStore()
+	eventCh := make(chan *BlockEvent)
+	subscriber, done := startResumable(t, chain, &ResumableSubscriberOptions{
+		StartBlockNumber: big.NewInt(0),
+		Polling:          true,
+		PollInterval:     time.Millisecond,
+		Store:            store,
+	}, eventCh)
+
+	expectEvents(t, eventCh, added(chain, 0, 1)...)
+	time.Sleep(20 * time.Millisecond)
+
+	// Subscription waits for the next event to be received before advancing.
+	checkpoint, err := store.Load(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), checkpoint.Number)
+
+	err = subscriber.subscribeResumable(chain.connect, &ResumableSubscriberOptions{}, eventCh)
+	assert.ErrorIs(t, err, ErrSubscriberActive)
+
+	require.NoError(t, subscriber.Close())
+	require.NoError(t, <-done)
+}
@@ -0,0 +1,115 @@
+package opcode
+
+import (
+	"bytes"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// GetFunctionSelectors returns the 4-byte function selectors, hex encoded with the 0x prefix, that the
+// contract dispatcher compares the calldata selector against. Both the legacy Solidity dispatcher
+// (DUP1 PUSH4 <selector> EQ PUSH2 <destination> JUMPI), the via-IR variant with the selector pushed before
+// the DUP and the Vyper dispatcher comparing selectors with XOR are recognized. Selectors with leading zero
+// bytes are pushed with shorter PUSH instructions by the optimizer and are accepted only in the legacy
+// DUP1 form to avoid picking up small constants compared within the function bodies.
+// Selectors are returned in the order of appearance without duplicates. Decompile must be called first.
+func (d *Decompiler) GetFunctionSelectors() []string {
+	toReturn := make([]string, 0)
+	seen := make(map[string]bool)
+
+	for i, instruction := range d.instructions {
+		if !instruction.OpCode.IsPush() || len(instruction.Args) == 0 || len(instruction.Args) > 4 {
+			continue
+		}
+
+		if !d.isSelectorComparison(i) {
+			continue
+		}
+
+		selector := "0x" + common.Bytes2Hex(common.LeftPadBytes(instruction.Args, 4))
+		if !seen[selector] {
+			seen[selector] = true
+			toReturn = append(toReturn, selector)
+		}
+	}
+
+	return toReturn
+}
+
+// isSelectorComparison reports whether the push instruction at the provided index is followed by the
+// dispatcher comparison and the conditional jump into the function body.
+func (d *Decompiler) isSelectorComparison(index int) bool {
+	push := d.instructions[index]
+	next := index + 1
+
+	// Via-IR and Vyper dispatchers push the selector first and duplicate the calldata selector afterwards.
+	if push.OpCode == PUSH4 && next < len(d.instructions) && d.instructions[next].OpCode.IsDup() {
+		next++
+	} else if index == 0 || !d.instructions[index-1].OpCode.IsDup() {
+		return false
+	} else if push.OpCode != PUSH4 && d.instructions[index-1].OpCode != DUP1 {
+		return false
+	}
+
+	if next >= len(d.instructions) {
+		return false
+	}
+
+	switch d.instructions[next].OpCode {
+	case EQ:
+	case XOR, SUB:
+		if push.OpCode != PUSH4 {
+			return false
+		}
+	default:
+		return false
+	}
+
+	// The comparison is followed by the destination push and the jump, optionally with ISZERO in between.
+	for offset := next + 1; offset < len(d.instructions) && offset <= next+3; offset++ {
+		switch code := d.instructions[offset].OpCode; {
+		case code == JUMPI:
+			return true
+		case code == ISZERO, code.IsPush():
+			continue
+		default:
+			return false
+		}
+	}
+
+	return false
+}
+
+// GetEventTopics returns the 32-byte constants pushed with PUSH32 that look like event signature hashes,
+// hex encoded with the 0x prefix. Solidity and Vyper push the event topic as PUSH32 right before the LOG
+// instruction. Constants with four or more leading or trailing zero (or 0xff) bytes, such as masks,
+// padded selectors and short strings, are not considered hashes and are skipped.
+// Topics are returned in the order of appearance without duplicates. Decompile must be called first.
+func (d *Decompiler) GetEventTopics() []string {
+	toReturn := make([]string, 0)
+	seen := make(map[string]bool)
+
+	for _, instruction := range d.GetInstructionsByOpCode(PUSH32) {
+		if len(instruction.Args) != 32 || !looksLikeHash(instruction.Args) {
+			continue
+		}
+
+		topic := common.BytesToHash(instruction.Args).Hex()
+		if !seen[topic] {
+			seen[topic] = true
+			toReturn = append(toReturn, topic)
+		}
+	}
+
+	return toReturn
+}
+
+// looksLikeHash reports whether the 32-byte constant does not start or end with four zero or 0xff bytes.
+func looksLikeHash(value []byte) bool {
+	for _, pad := range [][]byte{bytes.Repeat([]byte{0x00}, 4), bytes.Repeat([]byte{0xff}, 4)} {
+		if bytes.HasPrefix(value, pad) || bytes.HasSuffix(value, pad) {
+			return false
+		}
+	}
+	return true
+}
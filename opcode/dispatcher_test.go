@@ -0,0 +1,71 @@
+package opcode
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecompiler_GetFunctionSelectors(t *testing.T) {
+	tests := []struct {
+		name     string
+		bytecode string
+		expected []string
+	}{
+		{
+			name:     "Legacy Solidity Dispatcher",
+			bytecode: "8063a9059cbb1461001057" + "8063095ea7b31461002057" + "8063a9059cbb1461001057",
+			expected: []string{"0xa9059cbb", "0x095ea7b3"},
+		},
+		{
+			name:     "Selector With Leading Zero Byte",
+			bytecode: "8062fdd58e1461001057",
+			expected: []string{"0x00fdd58e"},
+		},
+		{
+			name:     "Via-IR Dispatcher",
+			bytecode: "63a9059cbb811461001057",
+			expected: []string{"0xa9059cbb"},
+		},
+		{
+			name:     "Vyper Dispatcher",
+			bytecode: "63a9059cbb81186100105715",
+			expected: []string{"0xa9059cbb"},
+		},
+		{
+			name:     "Constant Comparisons",
+			bytecode: "6001811461001057" + "8160011461001057" + "8063a9059cbb1160001057",
+			expected: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decompiler, err := NewDecompiler(context.TODO(), common.FromHex(tt.bytecode))
+			require.NoError(t, err)
+			require.NoError(t, decompiler.Decompile())
+			assert.Equal(t, tt.expected, decompiler.GetFunctionSelectors())
+		})
+	}
+}
+
+func TestDecompiler_GetEventTopics(t *testing.T) {
+	transfer := "ddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"
+	approval := "8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925"
+
+	bytecode := "7f" + transfer + "a3" +
+		"7f" + approval + "a3" +
+		"7f" + strings.Repeat("ff", 32) + "16" +
+		"7f" + "08c379a0" + strings.Repeat("00", 28) + "52" +
+		"7f" + transfer + "a3"
+
+	decompiler, err := NewDecompiler(context.TODO(), common.FromHex(bytecode))
+	require.NoError(t, err)
+	require.NoError(t, decompiler.Decompile())
+
+	assert.Equal(t, []string{"0x" + transfer, "0x" + approval}, decompiler.GetEventTopics())
+}
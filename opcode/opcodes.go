@@ -36,6 +36,12 @@ func (op OpCode) IsPush() bool {
 	return PUSH1 <= op && op <= PUSH32
 }
 
+// IsDup checks if the given opcode is a DUP opcode.
+// DUP opcodes, ranging from DUP1 to DUP16, duplicate the n-th stack item onto the top of the stack.
+func (op OpCode) IsDup() bool {
+	return DUP1 <= op && op <= DUP16
+}
+
 // IsJump determines if an opcode corresponds to a jump operation.
 // Jump operations in the EVM allow for altering the sequence of execution.
 // This method checks for three specific jump-related opcodes: JUMP, JUMPI, and JUMPDEST.
@@ -0,0 +1,54 @@
+package pricing
+
+import (
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+// FactoryV2ABI is the subset of the Uniswap V2 factory interface used to discover the pairs.
+const FactoryV2ABI = `[
+	{"type":"function","name":"getPair","stateMutability":"view","inputs":[{"name":"tokenA","type":"address"},{"name":"tokenB","type":"address"}],"outputs":[{"name":"pair","type":"address"}]}
+]`
+
+// PairV2ABI is the subset of the Uniswap V2 pair interface used to read the reserves.
+const PairV2ABI = `[
+	{"type":"function","name":"getReserves","stateMutability":"view","inputs":[],"outputs":[{"name":"reserve0","type":"uint112"},{"name":"reserve1","type":"uint112"},{"name":"blockTimestampLast","type":"uint32"}]}
+]`
+
+// FactoryV3ABI is the subset of the Uniswap V3 factory interface used to discover the pools.
+const FactoryV3ABI = `[
+	{"type":"function","name":"getPool","stateMutability":"view","inputs":[{"name":"tokenA","type":"address"},{"name":"tokenB","type":"address"},{"name":"fee","type":"uint24"}],"outputs":[{"name":"pool","type":"address"}]}
+]`
+
+// PoolV3ABI is the subset of the Uniswap V3 pool interface used to read the price, liquidity and the
+// initialized ticks.
+const PoolV3ABI = `[
+	{"type":"function","name":"slot0","stateMutability":"view","inputs":[],"outputs":[{"name":"sqrtPriceX96","type":"uint160"},{"name":"tick","type":"int24"},{"name":"observationIndex","type":"uint16"},{"name":"observationCardinality","type":"uint16"},{"name":"observationCardinalityNext","type":"uint16"},{"name":"feeProtocol","type":"uint8"},{"name":"unlocked","type":"bool"}]},
+	{"type":"function","name":"liquidity","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint128"}]},
+	{"type":"function","name":"tickSpacing","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"int24"}]},
+	{"type":"function","name":"tickBitmap","stateMutability":"view","inputs":[{"name":"wordPosition","type":"int16"}],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"ticks","stateMutability":"view","inputs":[{"name":"tick","type":"int24"}],"outputs":[{"name":"liquidityGross","type":"uint128"},{"name":"liquidityNet","type":"int128"},{"name":"feeGrowthOutside0X128","type":"uint256"},{"name":"feeGrowthOutside1X128","type":"uint256"},{"name":"tickCumulativeOutside","type":"int56"},{"name":"secondsPerLiquidityOutsideX128","type":"uint160"},{"name":"secondsOutside","type":"uint32"},{"name":"initialized","type":"bool"}]}
+]`
+
+// TokenABI is the subset of the ERC-20 interface used to read the pool balances.
+const TokenABI = `[
+	{"type":"function","name":"balanceOf","stateMutability":"view","inputs":[{"name":"account","type":"address"}],"outputs":[{"name":"","type":"uint256"}]}
+]`
+
+var (
+	factoryV2ABI = mustParseABI(FactoryV2ABI)
+	pairV2ABI    = mustParseABI(PairV2ABI)
+	factoryV3ABI = mustParseABI(FactoryV3ABI)
+	poolV3ABI    = mustParseABI(PoolV3ABI)
+	tokenABI     = mustParseABI(TokenABI)
+)
+
+// mustParseABI parses the ABI definition known to be valid.
+func mustParseABI(definition string) abi.ABI {
+	toReturn, err := abi.JSON(strings.NewReader(definition))
+	if err != nil {
+		panic(err)
+	}
+	return toReturn
+}
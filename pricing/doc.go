@@ -0,0 +1,11 @@
+// Package pricing provides on-chain token pricing using the decentralized exchange pools.
+//
+// The Pricer discovers the Uniswap V2 compatible pairs and Uniswap V3 compatible pools of the
+// token through the exchange factories, reads their reserves, slot0 and liquidity through any
+// Ethereum client (clients.Client satisfies the Caller interface), and prices the token against
+// wrapped ether and stablecoins using the utils/entities types. Prices quoted in wrapped ether
+// are routed through the most liquid wrapped ether and stablecoin pool to the USD valuation,
+// which includes the liquidity of the pool and the price impact of the given trade size. Swaps through
+// the V3 pools cross the initialized ticks read around the current tick, and the trades leaving that
+// range are rejected rather than approximated.
+package pricing
@@ -0,0 +1,20 @@
+package pricing
+
+import "errors"
+
+var (
+	// ErrInvalidCaller is returned when the pricer is created without the contract caller.
+	ErrInvalidCaller = errors.New("invalid contract caller")
+
+	// ErrUnsupportedChain is returned when the pricer has no default exchanges and quote tokens for the chain.
+	ErrUnsupportedChain = errors.New("chain not supported by the pricer")
+
+	// ErrPoolNotFound is returned when the token has no pool with liquidity on any of the exchanges.
+	ErrPoolNotFound = errors.New("pool not found")
+
+	// ErrTokenNotInPool is returned when the token priced or swapped is not one of the pool tokens.
+	ErrTokenNotInPool = errors.New("token not in pool")
+
+	// ErrInsufficientLiquidity is returned when the pool cannot fill the trade.
+	ErrInsufficientLiquidity = errors.New("insufficient liquidity")
+)
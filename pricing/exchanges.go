@@ -0,0 +1,53 @@
+package pricing
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/unpackdev/solgo/utils/entities"
+)
+
+// PoolVersion is the version of the Uniswap protocol the exchange is compatible with.
+type PoolVersion string
+
+const (
+	// V2 pools hold the reserves of both tokens and price them by the constant product formula.
+	V2 PoolVersion = "v2"
+
+	// V3 pools concentrate the liquidity within the tick ranges and track the square root of the price.
+	V3 PoolVersion = "v3"
+)
+
+// FeeDenominator is the denominator of the pool fees, which are expressed in hundredths of a basis point.
+const FeeDenominator = 1_000_000
+
+// DefaultV2Fee is the 0.3% swap fee of the Uniswap V2 pairs.
+const DefaultV2Fee = 3000
+
+// DefaultV3FeeTiers are the fee tiers enabled on the Uniswap V3 factory.
+var DefaultV3FeeTiers = []uint32{100, 500, 3000, 10000}
+
+// TickBitmapWords is the number of the V3 tick bitmap words read on each side of the word of the current tick,
+// each word covering 256 initializable ticks.
+const TickBitmapWords = 1
+
+// Exchange describes the decentralized exchange the pools are discovered on.
+type Exchange struct {
+	Name     string         `json:"name"`      // Name of the exchange.
+	Version  PoolVersion    `json:"version"`   // Protocol version the exchange is compatible with.
+	Factory  common.Address `json:"factory"`   // Factory the pools are discovered through.
+	Fee      uint32         `json:"fee"`       // Swap fee of the V2 pairs, in hundredths of a basis point.
+	FeeTiers []uint32       `json:"fee_tiers"` // Fee tiers of the V3 pools, in hundredths of a basis point.
+}
+
+// Exchanges are the known exchanges by the chain identifier.
+var Exchanges = map[uint][]Exchange{
+	1: {
+		{Name: "Uniswap V2", Version: V2, Factory: common.HexToAddress("0x5C69bEe701ef814a2B6a3EDD4B1652CB9cc5aA6f"), Fee: DefaultV2Fee},
+		{Name: "SushiSwap", Version: V2, Factory: common.HexToAddress("0xC0AEe478e3658e2610c3F7A4A2E1777cE9e4f2Ac"), Fee: DefaultV2Fee},
+		{Name: "Uniswap V3", Version: V3, Factory: common.HexToAddress("0x1F98431c8aD98523631AE4a59f267346ea31F984"), FeeTiers: DefaultV3FeeTiers},
+	},
+}
+
+// Stablecoins are the known USD pegged tokens by the chain identifier, assumed to be worth exactly one dollar.
+var Stablecoins = map[uint][]*entities.Token{
+	1: {entities.USDC[1], entities.USDT[1], entities.DAI[1]},
+}
@@ -0,0 +1,256 @@
+package pricing
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/unpackdev/solgo/utils/entities"
+)
+
+// Pool represents the Uniswap V2 compatible pair or Uniswap V3 compatible pool of two tokens.
+type Pool struct {
+	Exchange     string          `json:"exchange"`       // Name of the exchange the pool belongs to.
+	Version      PoolVersion     `json:"version"`        // Protocol version of the pool.
+	Address      common.Address  `json:"address"`        // Address of the pool.
+	Token0       *entities.Token `json:"token0"`         // Token sorting before the other token.
+	Token1       *entities.Token `json:"token1"`         // Token sorting after the other token.
+	Fee          uint32          `json:"fee"`            // Swap fee, in hundredths of a basis point.
+	Reserve0     *big.Int        `json:"reserve0"`       // Reserve of token0, the token balance of the V3 pools.
+	Reserve1     *big.Int        `json:"reserve1"`       // Reserve of token1, the token balance of the V3 pools.
+	SqrtPriceX96 *big.Int        `json:"sqrt_price_x96"` // Square root of the token0 price in Q64.96, V3 pools only.
+	Tick         int             `json:"tick"`           // Current tick, V3 pools only.
+	Liquidity    *big.Int        `json:"liquidity"`      // Liquidity within the current tick range, V3 pools only.
+	TickSpacing  int             `json:"tick_spacing"`   // Spacing of the initializable ticks, V3 pools only.
+	TickLower    int             `json:"tick_lower"`     // Lowest tick of the range the initialized ticks are read in, V3 pools only.
+	TickUpper    int             `json:"tick_upper"`     // Highest tick of the range the initialized ticks are read in, V3 pools only.
+	Ticks        []Tick          `json:"ticks"`          // Initialized ticks within the range read, sorted by the index, V3 pools only.
+}
+
+// Tick represents the initialized tick of the Uniswap V3 pool.
+type Tick struct {
+	Index        int      `json:"index"`         // Index of the tick.
+	LiquidityNet *big.Int `json:"liquidity_net"` // Liquidity added when the price crosses the tick upwards.
+}
+
+// Swap represents the outcome of swapping the tokens through the pool.
+type Swap struct {
+	TokenIn           *entities.Token   `json:"token_in"`             // Token sold to the pool.
+	TokenOut          *entities.Token   `json:"token_out"`            // Token bought from the pool.
+	AmountIn          *big.Int          `json:"amount_in"`            // Amount of the token sold.
+	AmountOut         *big.Int          `json:"amount_out"`           // Amount of the token bought.
+	ExecutionPrice    *entities.Price   `json:"-"`                    // Price the trade is executed at, including the fee.
+	PriceImpact       *entities.Percent `json:"-"`                    // Difference between the mid price and the execution price.
+	SqrtPriceX96After *big.Int          `json:"sqrt_price_x96_after"` // Square root price after the swap, V3 pools only.
+	TickAfter         int               `json:"tick_after"`           // Tick after the swap, V3 pools only.
+}
+
+// Involves returns true if the token is one of the pool tokens.
+func (p *Pool) Involves(token *entities.Token) bool {
+	return token.Equal(p.Token0) || token.Equal(p.Token1)
+}
+
+// GetOtherToken returns the pool token paired with the given token.
+func (p *Pool) GetOtherToken(token *entities.Token) (*entities.Token, error) {
+	switch {
+	case token.Equal(p.Token0):
+		return p.Token1, nil
+	case token.Equal(p.Token1):
+		return p.Token0, nil
+	default:
+		return nil, fmt.Errorf("%w: %s in %s", ErrTokenNotInPool, token.Address.Hex(), p.Address.Hex())
+	}
+}
+
+// GetReserve returns the reserve of the pool token.
+func (p *Pool) GetReserve(token *entities.Token) (*big.Int, error) {
+	switch {
+	case token.Equal(p.Token0):
+		return p.Reserve0, nil
+	case token.Equal(p.Token1):
+		return p.Reserve1, nil
+	default:
+		return nil, fmt.Errorf("%w: %s in %s", ErrTokenNotInPool, token.Address.Hex(), p.Address.Hex())
+	}
+}
+
+// MidPrice returns the current price of the base token in the other pool token, excluding the fee.
+func (p *Pool) MidPrice(base *entities.Token) (*entities.Price, error) {
+	var price *entities.Price
+
+	switch p.Version {
+	case V3:
+		if p.SqrtPriceX96 == nil || p.SqrtPriceX96.Sign() == 0 {
+			return nil, fmt.Errorf("%w: pool %s is not initialized", ErrInsufficientLiquidity, p.Address.Hex())
+		}
+		price = entities.NewPrice(p.Token0, p.Token1, Q192, new(big.Int).Mul(p.SqrtPriceX96, p.SqrtPriceX96))
+	default:
+		if p.Reserve0 == nil || p.Reserve1 == nil || p.Reserve0.Sign() == 0 || p.Reserve1.Sign() == 0 {
+			return nil, fmt.Errorf("%w: pair %s has no reserves", ErrInsufficientLiquidity, p.Address.Hex())
+		}
+		price = entities.NewPrice(p.Token0, p.Token1, p.Reserve0, p.Reserve1)
+	}
+
+	switch {
+	case base.Equal(p.Token0):
+		return price, nil
+	case base.Equal(p.Token1):
+		return price.Invert(), nil
+	default:
+		return nil, fmt.Errorf("%w: %s in %s", ErrTokenNotInPool, base.Address.Hex(), p.Address.Hex())
+	}
+}
+
+// GetAmountOut calculates the swap of the amount of the input token through the pool, together with its
+// price impact. V3 swaps cross the initialized ticks within the range the ticks are read in, and the trades
+// moving the price out of that range fail with ErrInsufficientLiquidity, as the liquidity past it is not known.
+func (p *Pool) GetAmountOut(tokenIn *entities.Token, amountIn *big.Int) (*Swap, error) {
+	tokenOut, err := p.GetOtherToken(tokenIn)
+	if err != nil {
+		return nil, err
+	}
+
+	if amountIn == nil || amountIn.Sign() <= 0 {
+		return nil, fmt.Errorf("invalid amount in: %v", amountIn)
+	}
+
+	midPrice, err := p.MidPrice(tokenIn)
+	if err != nil {
+		return nil, err
+	}
+
+	toReturn := &Swap{
+		TokenIn:  tokenIn,
+		TokenOut: tokenOut,
+		AmountIn: new(big.Int).Set(amountIn),
+	}
+
+	switch p.Version {
+	case V3:
+		if err := p.swapV3(toReturn); err != nil {
+			return nil, err
+		}
+	default:
+		if err := p.swapV2(toReturn); err != nil {
+			return nil, err
+		}
+	}
+
+	if toReturn.AmountOut.Sign() == 0 {
+		return nil, fmt.Errorf("%w: swap of %s in %s returns nothing", ErrInsufficientLiquidity, amountIn, p.Address.Hex())
+	}
+
+	toReturn.ExecutionPrice = entities.NewPrice(tokenIn, tokenOut, toReturn.AmountIn, toReturn.AmountOut)
+
+	// Price impact is (quoted - out) / quoted, with the amount quoted at the mid price.
+	quoted, err := midPrice.Quote(entities.FromRawAmount(tokenIn, amountIn))
+	if err != nil {
+		return nil, err
+	}
+	toReturn.PriceImpact = entities.NewPercent(
+		new(big.Int).Sub(quoted.Numerator, new(big.Int).Mul(toReturn.AmountOut, quoted.Denominator)),
+		quoted.Numerator,
+	)
+
+	return toReturn, nil
+}
+
+// swapV2 calculates the swap by the constant product formula of the Uniswap V2 pairs.
+func (p *Pool) swapV2(swap *Swap) error {
+	reserveIn, _ := p.GetReserve(swap.TokenIn)
+	reserveOut, _ := p.GetReserve(swap.TokenOut)
+
+	amountInWithFee := new(big.Int).Mul(swap.AmountIn, big.NewInt(int64(FeeDenominator-p.Fee)))
+	numerator := new(big.Int).Mul(amountInWithFee, reserveOut)
+	denominator := new(big.Int).Mul(reserveIn, big.NewInt(FeeDenominator))
+	denominator.Add(denominator, amountInWithFee)
+
+	swap.AmountOut = numerator.Div(numerator, denominator)
+	return nil
+}
+
+// swapV3 calculates the swap of the Uniswap V3 pools, stepping through the initialized ticks and adjusting the
+// liquidity by the liquidity net of each tick crossed.
+func (p *Pool) swapV3(swap *Swap) error {
+	if p.Liquidity == nil || p.Liquidity.Sign() == 0 {
+		return fmt.Errorf("%w: pool %s has no liquidity in range", ErrInsufficientLiquidity, p.Address.Hex())
+	}
+
+	zeroForOne := swap.TokenIn.Equal(p.Token0)
+	lower, upper := p.tickRange()
+
+	tick := p.Tick
+	sqrtPriceX96 := new(big.Int).Set(p.SqrtPriceX96)
+	liquidity := new(big.Int).Set(p.Liquidity)
+	remaining := new(big.Int).Set(swap.AmountIn)
+	swap.AmountOut = new(big.Int)
+
+	for remaining.Sign() > 0 {
+		next, liquidityNet := p.nextTick(tick, zeroForOne, lower, upper)
+		sqrtTargetX96 := GetSqrtRatioAtTick(next)
+
+		sqrtNextX96, amountIn, amountOut, feeAmount := computeSwapStep(sqrtPriceX96, sqrtTargetX96, liquidity, remaining, p.Fee, zeroForOne)
+		remaining.Sub(remaining, amountIn).Sub(remaining, feeAmount)
+		swap.AmountOut.Add(swap.AmountOut, amountOut)
+		sqrtPriceX96 = sqrtNextX96
+
+		if sqrtNextX96.Cmp(sqrtTargetX96) != 0 {
+			tick = GetTickAtSqrtRatio(sqrtNextX96)
+			break
+		}
+
+		if liquidityNet == nil && remaining.Sign() > 0 {
+			return fmt.Errorf("%w: swap of %s moves %s out of the tick range %d to %d", ErrInsufficientLiquidity, swap.AmountIn, p.Address.Hex(), lower, upper)
+		}
+
+		if zeroForOne {
+			tick = next - 1
+			if liquidityNet != nil {
+				liquidity.Sub(liquidity, liquidityNet)
+			}
+		} else {
+			tick = next
+			if liquidityNet != nil {
+				liquidity.Add(liquidity, liquidityNet)
+			}
+		}
+
+		if liquidity.Sign() < 0 {
+			return fmt.Errorf("%w: liquidity of %s is negative past tick %d", ErrInsufficientLiquidity, p.Address.Hex(), next)
+		}
+	}
+
+	swap.SqrtPriceX96After = sqrtPriceX96
+	swap.TickAfter = tick
+	return nil
+}
+
+// tickRange returns the range of the ticks the swap may move the price within. When the initialized ticks
+// are not read around the current tick, the range is limited to the current tick.
+func (p *Pool) tickRange() (int, int) {
+	if p.TickLower > p.Tick || p.TickUpper <= p.Tick {
+		return p.Tick, p.Tick + 1
+	}
+	return max(p.TickLower, MinTick), min(p.TickUpper, MaxTick)
+}
+
+// nextTick returns the next initialized tick strictly within the range in the swap direction, at or below
+// the current tick when selling token0 and above it otherwise, together with its liquidity net. When there is
+// no such tick, the bound of the range is returned with the nil liquidity net.
+func (p *Pool) nextTick(tick int, zeroForOne bool, lower int, upper int) (int, *big.Int) {
+	if zeroForOne {
+		for i := len(p.Ticks) - 1; i >= 0; i-- {
+			if p.Ticks[i].Index <= tick && p.Ticks[i].Index > lower {
+				return p.Ticks[i].Index, p.Ticks[i].LiquidityNet
+			}
+		}
+		return lower, nil
+	}
+
+	for _, initialized := range p.Ticks {
+		if initialized.Index > tick && initialized.Index < upper {
+			return initialized.Index, initialized.LiquidityNet
+		}
+	}
+	return upper, nil
+}
@@ -0,0 +1,220 @@
+package pricing
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unpackdev/solgo/utils/entities"
+)
+
+func TestPoolGetAmountOut(t *testing.T) {
+	tokenA := entities.NewToken(1, common.HexToAddress("0x000000000000000000000000000000000000000a"), 18, "A", "Token A")
+	tokenB := entities.NewToken(1, common.HexToAddress("0x000000000000000000000000000000000000000b"), 18, "B", "Token B")
+	tokenC := entities.NewToken(1, common.HexToAddress("0x000000000000000000000000000000000000000c"), 18, "C", "Token C")
+
+	ether := new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+	units := func(amount int64) *big.Int {
+		return new(big.Int).Mul(big.NewInt(amount), ether)
+	}
+
+	v2Pool := &Pool{Version: V2, Token0: tokenA, Token1: tokenB, Fee: DefaultV2Fee, Reserve0: units(1000), Reserve1: units(1000)}
+	v3Pool := &Pool{
+		Version:      V3,
+		Token0:       tokenA,
+		Token1:       tokenB,
+		Fee:          3000,
+		SqrtPriceX96: Q96,
+		Liquidity:    units(1_000_000),
+		TickSpacing:  60,
+		TickLower:    -15360,
+		TickUpper:    15360,
+	}
+
+	tests := []struct {
+		name        string
+		pool        *Pool
+		tokenIn     *entities.Token
+		amountIn    *big.Int
+		amountOut   *big.Int
+		priceImpact float64
+		tickAfter   func(tick int) bool
+		wantErr     error
+	}{
+		{
+			name:        "V2 Constant Product",
+			pool:        v2Pool,
+			tokenIn:     tokenA,
+			amountIn:    units(10),
+			amountOut:   new(big.Int).SetUint64(9871580343970612988),
+			priceImpact: 1.2842,
+		},
+		{
+			name:        "V2 Reversed Direction",
+			pool:        v2Pool,
+			tokenIn:     tokenB,
+			amountIn:    units(10),
+			amountOut:   new(big.Int).SetUint64(9871580343970612988),
+			priceImpact: 1.2842,
+		},
+		{
+			name:        "V3 Zero For One",
+			pool:        v3Pool,
+			tokenIn:     tokenA,
+			amountIn:    units(1),
+			priceImpact: 0.3,
+			tickAfter:   func(tick int) bool { return tick < 0 },
+		},
+		{
+			name:        "V3 One For Zero",
+			pool:        v3Pool,
+			tokenIn:     tokenB,
+			amountIn:    units(1),
+			priceImpact: 0.3,
+			tickAfter:   func(tick int) bool { return tick >= 0 },
+		},
+		{
+			name:     "V3 Without Liquidity",
+			pool:     &Pool{Version: V3, Token0: tokenA, Token1: tokenB, Fee: 3000, SqrtPriceX96: Q96, Liquidity: big.NewInt(0)},
+			tokenIn:  tokenA,
+			amountIn: units(1),
+			wantErr:  ErrInsufficientLiquidity,
+		},
+		{
+			name:     "Token Not In Pool",
+			pool:     v2Pool,
+			tokenIn:  tokenC,
+			amountIn: units(1),
+			wantErr:  ErrTokenNotInPool,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			swap, err := tt.pool.GetAmountOut(tt.tokenIn, tt.amountIn)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			if tt.amountOut != nil {
+				assert.Equal(t, tt.amountOut, swap.AmountOut)
+			}
+			assert.InDelta(t, tt.priceImpact, toFloat(swap.PriceImpact.Fraction)*100, 1e-3)
+			assert.True(t, swap.TokenOut.Equal(tt.pool.Token0) || swap.TokenOut.Equal(tt.pool.Token1))
+			assert.False(t, swap.TokenOut.Equal(tt.tokenIn))
+
+			if tt.tickAfter != nil {
+				assert.True(t, tt.tickAfter(swap.TickAfter))
+				assert.NotNil(t, swap.SqrtPriceX96After)
+			}
+		})
+	}
+}
+
+func TestPoolSwapV3Ticks(t *testing.T) {
+	tokenA := entities.NewToken(1, common.HexToAddress("0x000000000000000000000000000000000000000a"), 18, "A", "Token A")
+	tokenB := entities.NewToken(1, common.HexToAddress("0x000000000000000000000000000000000000000b"), 18, "B", "Token B")
+
+	ether := new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+	units := func(amount int64) *big.Int {
+		return new(big.Int).Mul(big.NewInt(amount), ether)
+	}
+
+	newPool := func(ticks ...Tick) *Pool {
+		return &Pool{
+			Version:      V3,
+			Token0:       tokenA,
+			Token1:       tokenB,
+			Fee:          3000,
+			SqrtPriceX96: Q96,
+			Liquidity:    units(1_000_000),
+			TickSpacing:  60,
+			TickLower:    -15360,
+			TickUpper:    15360,
+			Ticks:        ticks,
+		}
+	}
+
+	// Half of the liquidity is out of range past the ticks -60 and 60.
+	crossed := newPool(
+		Tick{Index: -60, LiquidityNet: units(500_000)},
+		Tick{Index: 60, LiquidityNet: new(big.Int).Neg(units(500_000))},
+	)
+
+	tests := []struct {
+		name      string
+		pool      *Pool
+		tokenIn   *entities.Token
+		amountIn  *big.Int
+		tickAfter func(tick int) bool
+		lessOut   bool
+		wantErr   error
+	}{
+		{
+			name:      "Within Range",
+			pool:      crossed,
+			tokenIn:   tokenA,
+			amountIn:  units(1000),
+			tickAfter: func(tick int) bool { return tick < 0 && tick >= -60 },
+		},
+		{
+			name:      "Zero For One Crossing Tick",
+			pool:      crossed,
+			tokenIn:   tokenA,
+			amountIn:  units(5000),
+			tickAfter: func(tick int) bool { return tick < -60 },
+			lessOut:   true,
+		},
+		{
+			name:      "One For Zero Crossing Tick",
+			pool:      crossed,
+			tokenIn:   tokenB,
+			amountIn:  units(5000),
+			tickAfter: func(tick int) bool { return tick >= 60 },
+			lessOut:   true,
+		},
+		{
+			name:     "Leaving Tick Range",
+			pool:     newPool(),
+			tokenIn:  tokenA,
+			amountIn: units(2_000_000),
+			wantErr:  ErrInsufficientLiquidity,
+		},
+		{
+			name:     "Liquidity Out Of Range Past Tick",
+			pool:     newPool(Tick{Index: -60, LiquidityNet: units(1_000_000)}),
+			tokenIn:  tokenA,
+			amountIn: units(5000),
+			wantErr:  ErrInsufficientLiquidity,
+		},
+		{
+			name:     "Ticks Not Read",
+			pool:     &Pool{Version: V3, Token0: tokenA, Token1: tokenB, Fee: 3000, SqrtPriceX96: Q96, Liquidity: units(1_000_000)},
+			tokenIn:  tokenA,
+			amountIn: units(1),
+			wantErr:  ErrInsufficientLiquidity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			swap, err := tt.pool.GetAmountOut(tt.tokenIn, tt.amountIn)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.True(t, tt.tickAfter(swap.TickAfter), "tick after %d", swap.TickAfter)
+
+			// Crossing out of the range of the half of the liquidity pays out less than the full liquidity.
+			uncrossed, err := newPool().GetAmountOut(tt.tokenIn, tt.amountIn)
+			require.NoError(t, err)
+			assert.Equal(t, tt.lessOut, swap.AmountOut.Cmp(uncrossed.AmountOut) < 0)
+		})
+	}
+}
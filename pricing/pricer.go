@@ -0,0 +1,414 @@
+package pricing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/unpackdev/solgo/utils/entities"
+)
+
+// Caller executes the read-only contract calls. It is satisfied by clients.Client, as well as by the
+// go-ethereum ethclient.Client.
+type Caller interface {
+	CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error)
+}
+
+// Options configures the Pricer. Fields that are not set default to the known values of the chain.
+type Options struct {
+	Exchanges   []Exchange        `json:"exchanges"`    // Exchanges the pools are discovered on.
+	WETH        *entities.Token   `json:"weth"`         // Wrapped native currency the tokens are commonly paired with.
+	Stablecoins []*entities.Token `json:"stablecoins"`  // USD pegged tokens the prices are routed to.
+	BlockNumber *big.Int          `json:"block_number"` // Block the pools are read at, latest when nil.
+}
+
+// Valuation represents the USD price of the token, read from the most liquid pool of the token.
+type Valuation struct {
+	Token        *entities.Token  `json:"token"`          // Valuated token.
+	Quote        *entities.Token  `json:"quote"`          // Token the price is quoted in, the wrapped native currency or the stablecoin.
+	Pool         *Pool            `json:"pool"`           // Most liquid pool the price is read from.
+	Route        []common.Address `json:"route"`          // Tokens the price is routed through to the stablecoin.
+	Price        *entities.Price  `json:"-"`              // Mid price of the token in the quote token.
+	PriceUSD     float64          `json:"price_usd"`      // Mid price of the token in USD.
+	LiquidityUSD float64          `json:"liquidity_usd"`  // Value of both reserves of the pool in USD.
+	Swap         *Swap            `json:"swap,omitempty"` // Sale of the trade size through the pool, when the trade size is given.
+	PriceImpact  float64          `json:"price_impact"`   // Price impact percentage of selling the trade size, including the fee.
+	Pools        []*Pool          `json:"pools"`          // All the pools of the token paired with the quote tokens.
+}
+
+// Pricer prices the tokens using the pools of the decentralized exchanges.
+type Pricer struct {
+	caller  Caller
+	chainID uint
+	opts    Options
+}
+
+// NewPricer creates a new Pricer of the chain. Options that are not set, or nil options, default to the
+// known exchanges, wrapped native currency and stablecoins of the chain.
+func NewPricer(caller Caller, chainID uint, opts *Options) (*Pricer, error) {
+	if caller == nil {
+		return nil, ErrInvalidCaller
+	}
+
+	toReturn := &Pricer{caller: caller, chainID: chainID}
+	if opts != nil {
+		toReturn.opts = *opts
+	}
+
+	if len(toReturn.opts.Exchanges) == 0 {
+		toReturn.opts.Exchanges = Exchanges[chainID]
+	}
+
+	if toReturn.opts.WETH == nil {
+		toReturn.opts.WETH = entities.WETH9[chainID]
+	}
+
+	if len(toReturn.opts.Stablecoins) == 0 {
+		toReturn.opts.Stablecoins = Stablecoins[chainID]
+	}
+
+	if len(toReturn.opts.Exchanges) == 0 || toReturn.opts.WETH == nil || len(toReturn.opts.Stablecoins) == 0 {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedChain, chainID)
+	}
+
+	return toReturn, nil
+}
+
+// GetChainID returns the identifier of the chain the pricer prices the tokens on.
+func (p *Pricer) GetChainID() uint {
+	return p.chainID
+}
+
+// GetOptions returns the options of the pricer, including the defaults of the chain.
+func (p *Pricer) GetOptions() Options {
+	return p.opts
+}
+
+// FindPools discovers the pools of the two tokens with liquidity on all the exchanges, including every fee
+// tier of the V3 exchanges.
+func (p *Pricer) FindPools(ctx context.Context, tokenA *entities.Token, tokenB *entities.Token) ([]*Pool, error) {
+	sortsBefore, err := tokenA.SortsBefore(tokenB)
+	if err != nil {
+		return nil, err
+	}
+
+	token0, token1 := tokenA, tokenB
+	if !sortsBefore {
+		token0, token1 = tokenB, tokenA
+	}
+
+	toReturn := make([]*Pool, 0)
+	for _, exchange := range p.opts.Exchanges {
+		switch exchange.Version {
+		case V2:
+			pool, err := p.findV2Pool(ctx, exchange, token0, token1)
+			if err != nil {
+				return nil, fmt.Errorf("failed to find %s pair: %w", exchange.Name, err)
+			}
+			if pool != nil {
+				toReturn = append(toReturn, pool)
+			}
+		case V3:
+			for _, fee := range exchange.FeeTiers {
+				pool, err := p.findV3Pool(ctx, exchange, token0, token1, fee)
+				if err != nil {
+					return nil, fmt.Errorf("failed to find %s pool with fee %d: %w", exchange.Name, fee, err)
+				}
+				if pool != nil {
+					toReturn = append(toReturn, pool)
+				}
+			}
+		default:
+			return nil, fmt.Errorf("unsupported version %q of exchange %s", exchange.Version, exchange.Name)
+		}
+	}
+
+	return toReturn, nil
+}
+
+// findV2Pool reads the pair of the sorted tokens, returning nil when the pair does not exist or has no reserves.
+func (p *Pricer) findV2Pool(ctx context.Context, exchange Exchange, token0 *entities.Token, token1 *entities.Token) (*Pool, error) {
+	results, err := p.call(ctx, factoryV2ABI, exchange.Factory, "getPair", token0.Address, token1.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	address := results[0].(common.Address)
+	if address == (common.Address{}) {
+		return nil, nil
+	}
+
+	results, err = p.call(ctx, pairV2ABI, address, "getReserves")
+	if err != nil {
+		return nil, err
+	}
+
+	toReturn := &Pool{
+		Exchange: exchange.Name,
+		Version:  V2,
+		Address:  address,
+		Token0:   token0,
+		Token1:   token1,
+		Fee:      exchange.Fee,
+		Reserve0: results[0].(*big.Int),
+		Reserve1: results[1].(*big.Int),
+	}
+
+	if toReturn.Reserve0.Sign() == 0 || toReturn.Reserve1.Sign() == 0 {
+		return nil, nil
+	}
+
+	return toReturn, nil
+}
+
+// findV3Pool reads the pool of the sorted tokens and the fee tier, returning nil when the pool does not exist
+// or has no liquidity in the current tick range.
+func (p *Pricer) findV3Pool(ctx context.Context, exchange Exchange, token0 *entities.Token, token1 *entities.Token, fee uint32) (*Pool, error) {
+	results, err := p.call(ctx, factoryV3ABI, exchange.Factory, "getPool", token0.Address, token1.Address, new(big.Int).SetUint64(uint64(fee)))
+	if err != nil {
+		return nil, err
+	}
+
+	address := results[0].(common.Address)
+	if address == (common.Address{}) {
+		return nil, nil
+	}
+
+	toReturn := &Pool{
+		Exchange: exchange.Name,
+		Version:  V3,
+		Address:  address,
+		Token0:   token0,
+		Token1:   token1,
+		Fee:      fee,
+	}
+
+	if results, err = p.call(ctx, poolV3ABI, address, "slot0"); err != nil {
+		return nil, err
+	}
+	toReturn.SqrtPriceX96 = results[0].(*big.Int)
+	toReturn.Tick = int(results[1].(*big.Int).Int64())
+
+	if results, err = p.call(ctx, poolV3ABI, address, "liquidity"); err != nil {
+		return nil, err
+	}
+	toReturn.Liquidity = results[0].(*big.Int)
+
+	if toReturn.SqrtPriceX96.Sign() == 0 || toReturn.Liquidity.Sign() == 0 {
+		return nil, nil
+	}
+
+	if toReturn.Reserve0, err = p.balanceOf(ctx, token0, address); err != nil {
+		return nil, err
+	}
+
+	if toReturn.Reserve1, err = p.balanceOf(ctx, token1, address); err != nil {
+		return nil, err
+	}
+
+	if err := p.readTicks(ctx, toReturn); err != nil {
+		return nil, err
+	}
+
+	return toReturn, nil
+}
+
+// readTicks reads the initialized ticks of the V3 pool from the tick bitmap words around the word of the
+// current tick, setting the range they are read in so the swaps leaving it are rejected.
+func (p *Pricer) readTicks(ctx context.Context, pool *Pool) error {
+	results, err := p.call(ctx, poolV3ABI, pool.Address, "tickSpacing")
+	if err != nil {
+		return err
+	}
+
+	pool.TickSpacing = int(results[0].(*big.Int).Int64())
+	if pool.TickSpacing <= 0 {
+		return fmt.Errorf("invalid tick spacing %d of pool %s", pool.TickSpacing, pool.Address.Hex())
+	}
+
+	compressed := pool.Tick / pool.TickSpacing
+	if pool.Tick < 0 && pool.Tick%pool.TickSpacing != 0 {
+		compressed--
+	}
+
+	word := compressed >> 8
+	pool.TickLower = max((word-TickBitmapWords)*256*pool.TickSpacing, MinTick)
+	pool.TickUpper = min((word+TickBitmapWords+1)*256*pool.TickSpacing, MaxTick)
+	pool.Ticks = make([]Tick, 0)
+
+	for position := word - TickBitmapWords; position <= word+TickBitmapWords; position++ {
+		if position < math.MinInt16 || position > math.MaxInt16 {
+			continue
+		}
+
+		if results, err = p.call(ctx, poolV3ABI, pool.Address, "tickBitmap", int16(position)); err != nil {
+			return err
+		}
+
+		bitmap := results[0].(*big.Int)
+		for bit := 0; bit < 256; bit++ {
+			if bitmap.Bit(bit) == 0 {
+				continue
+			}
+
+			index := (position*256 + bit) * pool.TickSpacing
+			if results, err = p.call(ctx, poolV3ABI, pool.Address, "ticks", big.NewInt(int64(index))); err != nil {
+				return err
+			}
+			pool.Ticks = append(pool.Ticks, Tick{Index: index, LiquidityNet: results[1].(*big.Int)})
+		}
+	}
+
+	return nil
+}
+
+// Valuate prices the token in USD using the most liquid pool of the token paired with the stablecoins or the
+// wrapped native currency, whose price is routed through its most liquid stablecoin pool. When the trade size
+// is given, the sale of the trade size of the token through the pool is calculated together with its impact.
+func (p *Pricer) Valuate(ctx context.Context, token *entities.Token, tradeSize *big.Int) (*Valuation, error) {
+	if token == nil {
+		return nil, errors.New("token is nil")
+	}
+
+	var best *Valuation
+	pools := make([]*Pool, 0)
+
+	consider := func(quote *entities.Token, quoteUSD float64, route []common.Address) error {
+		candidates, err := p.FindPools(ctx, token, quote)
+		if err != nil {
+			return err
+		}
+
+		for _, pool := range candidates {
+			valuation, err := valuate(pool, token, quote, quoteUSD)
+			if err != nil {
+				return err
+			}
+
+			valuation.Route = route
+			if best == nil || valuation.LiquidityUSD > best.LiquidityUSD {
+				best = valuation
+			}
+		}
+
+		pools = append(pools, candidates...)
+		return nil
+	}
+
+	for _, stable := range p.opts.Stablecoins {
+		if token.Equal(stable) {
+			continue
+		}
+
+		if err := consider(stable, 1, []common.Address{token.Address, stable.Address}); err != nil {
+			return nil, err
+		}
+	}
+
+	if !token.Equal(p.opts.WETH) {
+		weth, err := p.Valuate(ctx, p.opts.WETH, nil)
+		if err != nil && !errors.Is(err, ErrPoolNotFound) {
+			return nil, fmt.Errorf("failed to valuate %s: %w", p.opts.WETH.Symbol(), err)
+		}
+
+		// Without the stablecoin pool of the wrapped native currency, its pairs cannot be valuated in USD.
+		if weth != nil {
+			route := append([]common.Address{token.Address}, weth.Route...)
+			if err := consider(p.opts.WETH, weth.PriceUSD, route); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if best == nil {
+		return nil, fmt.Errorf("%w: %s", ErrPoolNotFound, token.Address.Hex())
+	}
+
+	best.Pools = pools
+
+	if tradeSize != nil && tradeSize.Sign() > 0 {
+		swap, err := best.Pool.GetAmountOut(token, tradeSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to calculate swap of %s: %w", tradeSize, err)
+		}
+
+		best.Swap = swap
+		best.PriceImpact = toFloat(swap.PriceImpact.Fraction) * 100
+	}
+
+	return best, nil
+}
+
+// valuate prices the token in USD using the pool paired with the quote token of the given USD price.
+func valuate(pool *Pool, token *entities.Token, quote *entities.Token, quoteUSD float64) (*Valuation, error) {
+	price, err := pool.MidPrice(token)
+	if err != nil {
+		return nil, err
+	}
+
+	priceUSD := toFloat(price.Fraction.Multiply(price.Scalar)) * quoteUSD
+
+	tokenReserve, _ := pool.GetReserve(token)
+	quoteReserve, _ := pool.GetReserve(quote)
+
+	return &Valuation{
+		Token:        token,
+		Quote:        quote,
+		Pool:         pool,
+		Price:        price,
+		PriceUSD:     priceUSD,
+		LiquidityUSD: toUnits(tokenReserve, token.Decimals())*priceUSD + toUnits(quoteReserve, quote.Decimals())*quoteUSD,
+	}, nil
+}
+
+// balanceOf reads the token balance of the account.
+func (p *Pricer) balanceOf(ctx context.Context, token *entities.Token, account common.Address) (*big.Int, error) {
+	results, err := p.call(ctx, tokenABI, token.Address, "balanceOf", account)
+	if err != nil {
+		return nil, err
+	}
+	return results[0].(*big.Int), nil
+}
+
+// call executes the read-only call of the contract method and unpacks its results.
+func (p *Pricer) call(ctx context.Context, contract abi.ABI, to common.Address, method string, args ...interface{}) ([]interface{}, error) {
+	data, err := contract.Pack(method, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack %s: %w", method, err)
+	}
+
+	output, err := p.caller.CallContract(ctx, ethereum.CallMsg{To: &to, Data: data}, p.opts.BlockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call %s of %s: %w", method, to.Hex(), err)
+	}
+
+	toReturn, err := contract.Unpack(method, output)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unpack %s of %s: %w", method, to.Hex(), err)
+	}
+
+	return toReturn, nil
+}
+
+// toFloat converts the fraction to the floating point number.
+func toFloat(fraction *entities.Fraction) float64 {
+	if fraction.Denominator.Sign() == 0 {
+		return 0
+	}
+
+	toReturn, _ := new(big.Rat).SetFrac(fraction.Numerator, fraction.Denominator).Float64()
+	return toReturn
+}
+
+// toUnits converts the raw amount of the token with the given decimals to the floating point number of units.
+func toUnits(amount *big.Int, decimals uint) float64 {
+	if amount == nil {
+		return 0
+	}
+	return toFloat(entities.NewFraction(amount, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)))
+}
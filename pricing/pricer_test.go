@@ -0,0 +1,233 @@
+package pricing
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unpackdev/solgo/utils/entities"
+)
+
+// mockCaller answers the contract calls with the handler of the called contract.
+type mockCaller struct {
+	handlers map[common.Address]func(method string, args []interface{}) []interface{}
+	err      error
+}
+
+func (c *mockCaller) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	handler, ok := c.handlers[*call.To]
+	if !ok {
+		return nil, errors.New("execution reverted")
+	}
+
+	for _, contract := range []abi.ABI{factoryV2ABI, pairV2ABI, factoryV3ABI, poolV3ABI, tokenABI} {
+		method, err := contract.MethodById(call.Data[:4])
+		if err != nil {
+			continue
+		}
+
+		args, err := method.Inputs.Unpack(call.Data[4:])
+		if err != nil {
+			return nil, err
+		}
+
+		results := handler(method.Name, args)
+		if results == nil {
+			return nil, errors.New("execution reverted")
+		}
+		return method.Outputs.Pack(results...)
+	}
+
+	return nil, errors.New("unknown method")
+}
+
+var (
+	pricedToken   = entities.NewToken(1, common.HexToAddress("0x1000000000000000000000000000000000000001"), 18, "TKN", "Token")
+	unpairedToken = entities.NewToken(1, common.HexToAddress("0x2000000000000000000000000000000000000002"), 18, "NONE", "Unpaired")
+	tokenWethPair = common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	wethUsdcPair  = common.HexToAddress("0x00000000000000000000000000000000000000a2")
+	tokenUsdcPool = common.HexToAddress("0x00000000000000000000000000000000000000a3")
+)
+
+func newMockCaller() *mockCaller {
+	weth, usdc := entities.WETH9[1].Address, entities.USDC[1].Address
+	exchanges := Exchanges[1]
+
+	pairs := map[[2]common.Address]common.Address{
+		{pricedToken.Address, weth}: tokenWethPair,
+		{usdc, weth}:                wethUsdcPair,
+	}
+
+	units := func(amount int64, decimals int64) *big.Int {
+		return new(big.Int).Mul(big.NewInt(amount), new(big.Int).Exp(big.NewInt(10), big.NewInt(decimals), nil))
+	}
+
+	reserves := func(reserve0, reserve1 *big.Int) func(string, []interface{}) []interface{} {
+		return func(method string, args []interface{}) []interface{} {
+			return []interface{}{reserve0, reserve1, uint32(0)}
+		}
+	}
+
+	balances := func(balance *big.Int) func(string, []interface{}) []interface{} {
+		return func(method string, args []interface{}) []interface{} {
+			if args[0].(common.Address) == tokenUsdcPool {
+				return []interface{}{balance}
+			}
+			return []interface{}{big.NewInt(0)}
+		}
+	}
+
+	return &mockCaller{
+		handlers: map[common.Address]func(string, []interface{}) []interface{}{
+			exchanges[0].Factory: func(method string, args []interface{}) []interface{} {
+				return []interface{}{pairs[[2]common.Address{args[0].(common.Address), args[1].(common.Address)}]}
+			},
+			exchanges[1].Factory: func(method string, args []interface{}) []interface{} {
+				return []interface{}{common.Address{}}
+			},
+			exchanges[2].Factory: func(method string, args []interface{}) []interface{} {
+				if args[0].(common.Address) == pricedToken.Address && args[1].(common.Address) == usdc && args[2].(*big.Int).Int64() == 3000 {
+					return []interface{}{tokenUsdcPool}
+				}
+				return []interface{}{common.Address{}}
+			},
+			tokenWethPair: reserves(units(1_000_000, 18), units(100, 18)),
+			wethUsdcPair:  reserves(units(2_000_000, 6), units(1000, 18)),
+			tokenUsdcPool: func(method string, args []interface{}) []interface{} {
+				switch method {
+				case "slot0":
+					// Price of 0.25 USDC per token, 2.5e-13 in the raw amounts.
+					sqrtPriceX96 := new(big.Int).Div(Q96, big.NewInt(2_000_000))
+					tick := big.NewInt(int64(GetTickAtSqrtRatio(sqrtPriceX96)))
+					return []interface{}{sqrtPriceX96, tick, uint16(0), uint16(0), uint16(0), uint8(0), true}
+				case "tickSpacing":
+					return []interface{}{big.NewInt(60)}
+				case "tickBitmap":
+					return []interface{}{big.NewInt(0)}
+				}
+				return []interface{}{big.NewInt(1_000_000_000_000_000)}
+			},
+			pricedToken.Address: balances(units(2000, 18)),
+			usdc:                balances(units(500, 6)),
+		},
+	}
+}
+
+func TestPricerValuate(t *testing.T) {
+	weth, usdc := entities.WETH9[1], entities.USDC[1]
+	v3Only := &Options{Exchanges: Exchanges[1][2:]}
+
+	tests := []struct {
+		name         string
+		opts         *Options
+		token        *entities.Token
+		tradeSize    *big.Int
+		quote        *entities.Token
+		version      PoolVersion
+		route        []common.Address
+		priceUSD     float64
+		liquidityUSD float64
+		priceImpact  float64
+		pools        int
+		wantErr      error
+	}{
+		{
+			name:         "Routed Through WETH",
+			token:        pricedToken,
+			tradeSize:    new(big.Int).Mul(big.NewInt(10_000), big.NewInt(1e18)),
+			quote:        weth,
+			version:      V2,
+			route:        []common.Address{pricedToken.Address, weth.Address, usdc.Address},
+			priceUSD:     0.2,
+			liquidityUSD: 400_000,
+			priceImpact:  1.2842,
+			pools:        2,
+		},
+		{
+			name:         "WETH",
+			token:        weth,
+			quote:        usdc,
+			version:      V2,
+			route:        []common.Address{weth.Address, usdc.Address},
+			priceUSD:     2000,
+			liquidityUSD: 4_000_000,
+			pools:        1,
+		},
+		{
+			name:         "V3 Stablecoin Pool",
+			opts:         v3Only,
+			token:        pricedToken,
+			tradeSize:    big.NewInt(1e18),
+			quote:        usdc,
+			version:      V3,
+			route:        []common.Address{pricedToken.Address, usdc.Address},
+			priceUSD:     0.25,
+			liquidityUSD: 1000,
+			priceImpact:  0.35,
+			pools:        1,
+		},
+		{
+			name:    "No Pools",
+			token:   unpairedToken,
+			wantErr: ErrPoolNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pricer, err := NewPricer(newMockCaller(), 1, tt.opts)
+			require.NoError(t, err)
+
+			valuation, err := pricer.Valuate(context.Background(), tt.token, tt.tradeSize)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.True(t, tt.quote.Equal(valuation.Quote))
+			assert.Equal(t, tt.version, valuation.Pool.Version)
+			assert.Equal(t, tt.route, valuation.Route)
+			assert.InEpsilon(t, tt.priceUSD, valuation.PriceUSD, 1e-6)
+			assert.InEpsilon(t, tt.liquidityUSD, valuation.LiquidityUSD, 1e-6)
+			assert.InDelta(t, tt.priceImpact, valuation.PriceImpact, 1e-3)
+			assert.Len(t, valuation.Pools, tt.pools)
+
+			if tt.tradeSize != nil {
+				require.NotNil(t, valuation.Swap)
+				assert.Equal(t, tt.tradeSize, valuation.Swap.AmountIn)
+			}
+		})
+	}
+}
+
+func TestNewPricer(t *testing.T) {
+	_, err := NewPricer(nil, 1, nil)
+	assert.ErrorIs(t, err, ErrInvalidCaller)
+
+	_, err = NewPricer(newMockCaller(), 12345, nil)
+	assert.ErrorIs(t, err, ErrUnsupportedChain)
+
+	pricer, err := NewPricer(newMockCaller(), 12345, &Options{
+		Exchanges:   Exchanges[1],
+		WETH:        entities.WETH9[1],
+		Stablecoins: Stablecoins[1],
+	})
+	require.NoError(t, err)
+	assert.Equal(t, uint(12345), pricer.GetChainID())
+
+	failing, err := NewPricer(&mockCaller{err: errors.New("rpc failure")}, 1, nil)
+	require.NoError(t, err)
+	_, err = failing.Valuate(context.Background(), pricedToken, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,205 @@
+package pricing
+
+import (
+	"math/big"
+
+	"github.com/unpackdev/solgo/utils/entities"
+)
+
+const (
+	// MinTick is the minimum tick of the Uniswap V3 pools, the price of 1.0001^MinTick.
+	MinTick = -887272
+
+	// MaxTick is the maximum tick of the Uniswap V3 pools, the price of 1.0001^MaxTick.
+	MaxTick = 887272
+)
+
+var (
+	// Q96 is the 2^96 scale of the Q64.96 fixed point square root prices.
+	Q96 = new(big.Int).Lsh(big.NewInt(1), 96)
+
+	// Q192 is the 2^192 scale of the prices squared from the Q64.96 square root prices.
+	Q192 = new(big.Int).Lsh(big.NewInt(1), 192)
+
+	// MinSqrtRatio is the square root price at MinTick.
+	MinSqrtRatio = big.NewInt(4295128739)
+
+	// MaxSqrtRatio is the square root price at MaxTick.
+	MaxSqrtRatio, _ = new(big.Int).SetString("1461446703485210103287273052203988822378723970342", 10)
+)
+
+// sqrtRatioMultipliers are the Q128.128 square roots of 1/1.0001^(2^i), for the bits of the absolute tick.
+var sqrtRatioMultipliers = []*big.Int{
+	hexInt("fffcb933bd6fad37aa2d162d1a594001"),
+	hexInt("fff97272373d413259a46990580e213a"),
+	hexInt("fff2e50f5f656932ef12357cf3c7fdcc"),
+	hexInt("ffe5caca7e10e4e61c3624eaa0941cd0"),
+	hexInt("ffcb9843d60f6159c9db58835c926644"),
+	hexInt("ff973b41fa98c081472e6896dfb254c0"),
+	hexInt("ff2ea16466c96a3843ec78b326b52861"),
+	hexInt("fe5dee046a99a2a811c461f1969c3053"),
+	hexInt("fcbe86c7900a88aedcffc83b479aa3a4"),
+	hexInt("f987a7253ac413176f2b074cf7815e54"),
+	hexInt("f3392b0822b70005940c7a398e4b70f3"),
+	hexInt("e7159475a2c29b7443b29c7fa6e889d9"),
+	hexInt("d097f3bdfd2022b8845ad8f792aa5825"),
+	hexInt("a9f746462d870fdf8a65dc1f90e061e5"),
+	hexInt("70d869a156d2a1b890bb3df62baf32f7"),
+	hexInt("31be135f97d08fd981231505542fcfa6"),
+	hexInt("9aa508b5b7a84e1c677de54f3e99bc9"),
+	hexInt("5d6af8dedb81196699c329225ee604"),
+	hexInt("2216e584f5fa1ea926041bedfe98"),
+	hexInt("48a170391f7dc42444e8fa2"),
+}
+
+// GetSqrtRatioAtTick returns the Q64.96 square root price at the tick, as calculated by the Uniswap V3
+// TickMath library. Ticks outside of MinTick and MaxTick are clamped.
+func GetSqrtRatioAtTick(tick int) *big.Int {
+	tick = max(MinTick, min(tick, MaxTick))
+
+	absTick := tick
+	if absTick < 0 {
+		absTick = -absTick
+	}
+
+	ratio := new(big.Int).Lsh(big.NewInt(1), 128)
+	for i, multiplier := range sqrtRatioMultipliers {
+		if absTick&(1<<i) != 0 {
+			ratio.Mul(ratio, multiplier)
+			ratio.Rsh(ratio, 128)
+		}
+	}
+
+	if tick > 0 {
+		ratio.Div(entities.MaxUint256, ratio)
+	}
+
+	// Rounds up from Q128.128 to Q64.96, so that the tick of the returned price is the given tick.
+	toReturn := new(big.Int).Rsh(ratio, 32)
+	if new(big.Int).And(ratio, big.NewInt(0xffffffff)).Sign() != 0 {
+		toReturn.Add(toReturn, big.NewInt(1))
+	}
+
+	return toReturn
+}
+
+// GetTickAtSqrtRatio returns the greatest tick whose square root price is less than or equal to the given
+// Q64.96 square root price.
+func GetTickAtSqrtRatio(sqrtPriceX96 *big.Int) int {
+	low, high := MinTick, MaxTick
+	for low < high {
+		mid := low + (high-low+1)/2
+		if GetSqrtRatioAtTick(mid).Cmp(sqrtPriceX96) <= 0 {
+			low = mid
+		} else {
+			high = mid - 1
+		}
+	}
+	return low
+}
+
+// getNextSqrtPriceFromInput returns the square root price after swapping the amount of the input token
+// within the liquidity, rounding in favor of the pool as the Uniswap V3 SqrtPriceMath library does.
+func getNextSqrtPriceFromInput(sqrtPriceX96 *big.Int, liquidity *big.Int, amountIn *big.Int, zeroForOne bool) *big.Int {
+	if zeroForOne {
+		// liquidity * sqrtPrice / (liquidity + amountIn * sqrtPrice), rounded up.
+		numerator := new(big.Int).Lsh(liquidity, 96)
+		denominator := new(big.Int).Add(numerator, new(big.Int).Mul(amountIn, sqrtPriceX96))
+		return divRoundingUp(new(big.Int).Mul(numerator, sqrtPriceX96), denominator)
+	}
+
+	// sqrtPrice + amountIn / liquidity, rounded down.
+	quotient := new(big.Int).Div(new(big.Int).Lsh(amountIn, 96), liquidity)
+	return quotient.Add(quotient, sqrtPriceX96)
+}
+
+// getAmount0Delta returns the amount of token0 between the square root prices within the liquidity,
+// rounded up for the amounts paid to the pool and down for the amounts paid by it.
+func getAmount0Delta(sqrtRatioA *big.Int, sqrtRatioB *big.Int, liquidity *big.Int, roundUp bool) *big.Int {
+	if sqrtRatioA.Cmp(sqrtRatioB) > 0 {
+		sqrtRatioA, sqrtRatioB = sqrtRatioB, sqrtRatioA
+	}
+
+	numerator := new(big.Int).Lsh(liquidity, 96)
+	numerator.Mul(numerator, new(big.Int).Sub(sqrtRatioB, sqrtRatioA))
+	if roundUp {
+		return divRoundingUp(divRoundingUp(numerator, sqrtRatioB), sqrtRatioA)
+	}
+
+	numerator.Div(numerator, sqrtRatioB)
+	return numerator.Div(numerator, sqrtRatioA)
+}
+
+// getAmount1Delta returns the amount of token1 between the square root prices within the liquidity,
+// rounded up for the amounts paid to the pool and down for the amounts paid by it.
+func getAmount1Delta(sqrtRatioA *big.Int, sqrtRatioB *big.Int, liquidity *big.Int, roundUp bool) *big.Int {
+	if sqrtRatioA.Cmp(sqrtRatioB) > 0 {
+		sqrtRatioA, sqrtRatioB = sqrtRatioB, sqrtRatioA
+	}
+
+	toReturn := new(big.Int).Mul(liquidity, new(big.Int).Sub(sqrtRatioB, sqrtRatioA))
+	if roundUp {
+		return divRoundingUp(toReturn, Q96)
+	}
+	return toReturn.Rsh(toReturn, 96)
+}
+
+// computeSwapStep calculates the exact input swap of the remaining amount, including the fee, from the square
+// root price towards the target within the liquidity, as the Uniswap V3 SwapMath library does. It returns the
+// square root price reached, the amount of the input token swapped, the amount of the output token and the fee.
+func computeSwapStep(sqrtPriceX96 *big.Int, sqrtTargetX96 *big.Int, liquidity *big.Int, remaining *big.Int, fee uint32, zeroForOne bool) (*big.Int, *big.Int, *big.Int, *big.Int) {
+	remainingLessFee := new(big.Int).Mul(remaining, big.NewInt(int64(FeeDenominator-fee)))
+	remainingLessFee.Div(remainingLessFee, big.NewInt(FeeDenominator))
+
+	var amountIn *big.Int
+	if zeroForOne {
+		amountIn = getAmount0Delta(sqrtTargetX96, sqrtPriceX96, liquidity, true)
+	} else {
+		amountIn = getAmount1Delta(sqrtPriceX96, sqrtTargetX96, liquidity, true)
+	}
+
+	sqrtNextX96 := sqrtTargetX96
+	reached := remainingLessFee.Cmp(amountIn) >= 0
+	if !reached {
+		sqrtNextX96 = getNextSqrtPriceFromInput(sqrtPriceX96, liquidity, remainingLessFee, zeroForOne)
+	}
+
+	var amountOut *big.Int
+	if zeroForOne {
+		if !reached {
+			amountIn = getAmount0Delta(sqrtNextX96, sqrtPriceX96, liquidity, true)
+		}
+		amountOut = getAmount1Delta(sqrtNextX96, sqrtPriceX96, liquidity, false)
+	} else {
+		if !reached {
+			amountIn = getAmount1Delta(sqrtPriceX96, sqrtNextX96, liquidity, true)
+		}
+		amountOut = getAmount0Delta(sqrtPriceX96, sqrtNextX96, liquidity, false)
+	}
+
+	// The rest of the amount is taken as the fee when the target is not reached.
+	if !reached {
+		return sqrtNextX96, amountIn, amountOut, new(big.Int).Sub(remaining, amountIn)
+	}
+
+	feeAmount := divRoundingUp(new(big.Int).Mul(amountIn, big.NewInt(int64(fee))), big.NewInt(int64(FeeDenominator-fee)))
+	return sqrtNextX96, amountIn, amountOut, feeAmount
+}
+
+// divRoundingUp divides the numbers, rounding the quotient up.
+func divRoundingUp(a *big.Int, b *big.Int) *big.Int {
+	quotient, remainder := new(big.Int).QuoRem(a, b, new(big.Int))
+	if remainder.Sign() != 0 {
+		quotient.Add(quotient, big.NewInt(1))
+	}
+	return quotient
+}
+
+// hexInt parses the hexadecimal constant known to be valid.
+func hexInt(value string) *big.Int {
+	toReturn, ok := new(big.Int).SetString(value, 16)
+	if !ok {
+		panic("invalid hexadecimal constant " + value)
+	}
+	return toReturn
+}
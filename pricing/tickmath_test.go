@@ -0,0 +1,45 @@
+package pricing
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/unpackdev/solgo/utils/entities"
+)
+
+func TestTickMath(t *testing.T) {
+	tests := []struct {
+		name      string
+		tick      int
+		sqrtRatio *big.Int
+	}{
+		{name: "Min Tick", tick: MinTick, sqrtRatio: MinSqrtRatio},
+		{name: "Max Tick", tick: MaxTick, sqrtRatio: MaxSqrtRatio},
+		{name: "Zero Tick", tick: 0, sqrtRatio: Q96},
+		{name: "Positive Tick", tick: 50},
+		{name: "Negative Tick", tick: -50},
+		{name: "Large Tick", tick: 200000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqrtRatio := GetSqrtRatioAtTick(tt.tick)
+			if tt.sqrtRatio != nil {
+				assert.Equal(t, tt.sqrtRatio, sqrtRatio)
+			}
+
+			price := toFloat(entities.NewFraction(new(big.Int).Mul(sqrtRatio, sqrtRatio), Q192))
+			expected := math.Pow(1.0001, float64(tt.tick))
+			assert.InEpsilon(t, expected, price, 1e-9)
+
+			assert.Equal(t, tt.tick, GetTickAtSqrtRatio(sqrtRatio))
+			if tt.tick < MaxTick {
+				// Prices between the ticks belong to the lower tick.
+				between := new(big.Int).Add(sqrtRatio, big.NewInt(1))
+				assert.Equal(t, tt.tick, GetTickAtSqrtRatio(between))
+			}
+		})
+	}
+}
@@ -3,25 +3,36 @@ package bitquery
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"github.com/goccy/go-json"
 	"net/http"
 	"time"
+
+	"github.com/unpackdev/solgo/cache"
 )
 
 // Provider represents a client for the blockchain data service,
 // configured with options and capable of making requests.
 type Provider struct {
-	ctx    context.Context // The context for request cancellation and deadlines.
-	opts   *Options        // Configuration options for the data service.
-	client *http.Client    // The HTTP client used for making requests.
+	ctx    context.Context  // The context for request cancellation and deadlines.
+	opts   *Options         // Configuration options for the data service.
+	client *http.Client     // The HTTP client used for making requests.
+	cache  *cache.Namespace // Cache of the query responses.
 }
 
-// NewProvider initializes and returns a new Provider instance.
+// NewProvider initializes and returns a new Provider instance, without caching the responses.
 // It validates the provided Options to ensure necessary configurations are set.
 //
 // Returns an error if the Options are nil, or if essential options like Endpoint or Key are not configured.
 func NewProvider(ctx context.Context, opts *Options) (*Provider, error) {
+	return NewProviderWithCache(ctx, nil, opts)
+}
+
+// NewProviderWithCache initializes and returns a new Provider instance caching the responses in the given cache,
+// or not at all when the cache is nil. It validates the provided Options the same way as NewProvider.
+func NewProviderWithCache(ctx context.Context, store cache.Cache, opts *Options) (*Provider, error) {
 	if opts == nil {
 		return nil, errors.New("bitquery provider is not configured")
 	}
@@ -38,6 +49,7 @@ func NewProvider(ctx context.Context, opts *Options) (*Provider, error) {
 		ctx:    ctx,
 		opts:   opts,
 		client: &http.Client{Timeout: time.Second * 30},
+		cache:  cache.NewNamespace(store, "bitquery", nil),
 	}, nil
 }
 
@@ -54,6 +66,14 @@ func (b *Provider) GetContractCreationInfo(ctx context.Context, query map[string
 		return nil, errors.New("failed to marshal query: " + err.Error())
 	}
 
+	queryHash := sha256.Sum256(jsonData)
+	cacheKey := cache.Key("creation", hex.EncodeToString(queryHash[:]))
+
+	var cached *ContractCreationInfo
+	if err := b.cache.GetJSON(ctx, cacheKey, &cached); err == nil {
+		return cached, nil
+	}
+
 	req, err := http.NewRequestWithContext(ctx, "POST", b.opts.Endpoint, bytes.NewBuffer(jsonData))
 	if err != nil {
 		return nil, errors.New("failed to create request: " + err.Error())
@@ -77,5 +97,12 @@ func (b *Provider) GetContractCreationInfo(ctx context.Context, query map[string
 		return nil, errors.New("failed to decode response: " + err.Error())
 	}
 
+	// Contracts are created only once, while the empty response may only mean the creation is not indexed yet.
+	if len(info.Data.SmartContractCreation.SmartContractCalls) > 0 {
+		if err := b.cache.SetJSON(ctx, cacheKey, &info, cache.Finalized); err != nil {
+			return nil, errors.New("failed to write to cache: " + err.Error())
+		}
+	}
+
 	return &info, nil
 }
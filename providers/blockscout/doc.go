@@ -0,0 +1,4 @@
+// Package blockscout provides a client for the Etherscan compatible API of the Blockscout explorers,
+// normalizing the verified contracts it returns into the format of the Etherscan responses, so Provider
+// implements explorer.Provider and can be used in place of the Etherscan provider.
+package blockscout
@@ -0,0 +1,46 @@
+package blockscout
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/unpackdev/solgo/cache"
+)
+
+// Options holds the configuration settings for the Blockscout client.
+type Options struct {
+	// Endpoint is the base URL of the Blockscout API of the chain, such as https://eth.blockscout.com/api.
+	Endpoint string `json:"endpoint" yaml:"endpoint" mapstructure:"endpoint"`
+
+	// ChainID is the chain of the instance, namespacing the cached responses. The responses are namespaced by the
+	// endpoint when not set.
+	ChainID uint64 `json:"chainId" yaml:"chainId" mapstructure:"chainId"`
+
+	// RateLimit specifies the maximum number of requests per second allowed per API key, unlimited when zero.
+	RateLimit int `json:"rateLimit" yaml:"rateLimit" mapstructure:"rateLimit"`
+
+	// Keys contains the optional API keys raising the rate limits of the instance, rotated in turns.
+	Keys []string `json:"keys" yaml:"keys" mapstructure:"keys"`
+}
+
+// Validate checks the integrity and completeness of the Options settings.
+func (o *Options) Validate() error {
+	if o == nil {
+		return errors.New("blockscout provider is not configured")
+	}
+
+	if o.Endpoint == "" {
+		return errors.New("endpoint is required but not set")
+	}
+
+	return nil
+}
+
+// GetNamespace returns the namespace of the cached responses of the instance, distinct for every chain.
+func (o *Options) GetNamespace() string {
+	if o.ChainID != 0 {
+		return cache.Key(ProviderName, strconv.FormatUint(o.ChainID, 10))
+	}
+	return cache.Key(ProviderName, strings.TrimSuffix(o.Endpoint, "/"))
+}
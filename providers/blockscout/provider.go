@@ -0,0 +1,185 @@
+package blockscout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/goccy/go-json"
+	"github.com/unpackdev/solgo/cache"
+	"github.com/unpackdev/solgo/metadata"
+	"github.com/unpackdev/solgo/providers/explorer"
+)
+
+// ProviderName is the name of the Blockscout provider.
+const ProviderName = "blockscout"
+
+// notVerified is the ABI Blockscout returns for the contracts without the verified source code.
+const notVerified = "Contract source code not verified"
+
+// Provider is the client of the Blockscout API. Listings, ABI and contract creation lookups are provided by
+// the embedded explorer client, so the provider implements explorer.Provider.
+type Provider struct {
+	*explorer.Client
+
+	ctx  context.Context // The context for controlling cancellations and timeouts.
+	opts *Options        // The configuration options for the provider.
+}
+
+// NewProvider creates the Blockscout provider, caching the responses that do not change in the given cache,
+// or not at all when the cache is nil. It returns an error if the provided options are invalid.
+func NewProvider(ctx context.Context, store cache.Cache, opts *Options) (*Provider, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	client, err := explorer.NewClient(ctx, store, &explorer.Options{
+		Name:      ProviderName,
+		Endpoint:  opts.Endpoint,
+		Keys:      opts.Keys,
+		RateLimit: opts.RateLimit,
+		Namespace: opts.GetNamespace(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Provider{
+		Client: client,
+		ctx:    ctx,
+		opts:   opts,
+	}, nil
+}
+
+// Source is the additional source file of the verified contract.
+type Source struct {
+	Filename   string `json:"Filename"`   // Path of the source file.
+	SourceCode string `json:"SourceCode"` // Content of the source file.
+}
+
+// Contract is the verified contract as returned by the Blockscout API.
+type Contract struct {
+	SourceCode           string          `json:"SourceCode"`            // Source code of the main file of the contract.
+	ABI                  string          `json:"ABI"`                   // JSON ABI of the contract.
+	Name                 string          `json:"ContractName"`          // Name of the contract.
+	CompilerVersion      string          `json:"CompilerVersion"`       // Version of the compiler.
+	OptimizationUsed     string          `json:"OptimizationUsed"`      // Whether the optimizer was enabled, "true" or "false".
+	OptimizationRuns     json.RawMessage `json:"OptimizationRuns"`      // Number of the optimizer runs, a number or a string.
+	ConstructorArguments string          `json:"ConstructorArguments"`  // Constructor arguments of the deployment.
+	EVMVersion           string          `json:"EVMVersion"`            // Target EVM version.
+	FileName             string          `json:"FileName"`              // Path of the main file of the contract.
+	IsProxy              string          `json:"IsProxy"`               // Whether the contract is the proxy, "true" or "false".
+	Implementation       string          `json:"ImplementationAddress"` // Implementation of the proxy.
+	AdditionalSources    []Source        `json:"AdditionalSources"`     // Source files other than the main one.
+	CompilerSettings     json.RawMessage `json:"CompilerSettings"`      // Standard JSON settings of the compilation.
+	LicenseType          string          `json:"LicenseType"`           // License of the source code.
+}
+
+// ScanContract retrieves the verified source code and compiler settings of the contract, normalized into the
+// format of the Etherscan responses: the single file source code is returned as is, while the multiple files
+// are returned as the metadata with the sources and settings. Verified sources do not change, so they are
+// cached as the finalized data.
+func (p *Provider) ScanContract(ctx context.Context, addr common.Address) (*explorer.Contract, error) {
+	cacheKey := cache.Key("getsourcecode", addr.Hex())
+
+	var cached *explorer.Contract
+	if err := p.GetCache().GetJSON(ctx, cacheKey, &cached); err == nil {
+		return cached, nil
+	} else if !errors.Is(err, cache.ErrNotFound) {
+		return nil, fmt.Errorf("failed to unmarshal %s response: %s", p.ProviderName(), err)
+	}
+
+	var contracts []Contract
+	if err := p.GetSourceCode(ctx, addr, &contracts); err != nil {
+		return nil, err
+	}
+
+	if len(contracts) == 0 {
+		return nil, fmt.Errorf("contract not found")
+	}
+
+	contract := contracts[0]
+	if contract.ABI == "" || contract.ABI == notVerified {
+		return nil, fmt.Errorf("contract source code not verified")
+	}
+
+	toReturn := &explorer.Contract{
+		SourceCode:           contract.SourceCode,
+		ABI:                  contract.ABI,
+		Name:                 contract.Name,
+		CompilerVersion:      contract.CompilerVersion,
+		OptimizationUsed:     contract.OptimizationUsed,
+		Runs:                 strings.Trim(string(contract.OptimizationRuns), `"`),
+		ConstructorArguments: contract.ConstructorArguments,
+		EVMVersion:           contract.EVMVersion,
+		LicenseType:          contract.LicenseType,
+		Proxy:                contract.IsProxy,
+		Implementation:       contract.Implementation,
+	}
+
+	if len(contract.AdditionalSources) > 0 {
+		cm := contract.toMetadata()
+		toReturn.SourceCode = cm
+
+		// Runs are not always listed for the contracts verified with the Standard JSON settings.
+		if toReturn.Runs == "" || toReturn.Runs == "null" {
+			toReturn.Runs = strconv.Itoa(cm.Settings.Optimizer.Runs)
+		}
+	}
+
+	if toReturn.OptimizationUsed == "" {
+		toReturn.OptimizationUsed = "false"
+	}
+
+	if toReturn.Runs == "" || toReturn.Runs == "null" {
+		toReturn.Runs = "0"
+	}
+
+	if toReturn.Proxy == "" {
+		toReturn.Proxy = "false"
+	}
+
+	if err := p.GetCache().SetJSON(ctx, cacheKey, toReturn, cache.Finalized); err != nil {
+		return nil, fmt.Errorf("failed to write to cache: %s", err)
+	}
+
+	return toReturn, nil
+}
+
+// toMetadata returns the metadata of the contract verified with the multiple source files.
+func (c *Contract) toMetadata() metadata.ContractMetadata {
+	cm := metadata.ContractMetadata{
+		Language: "Solidity",
+		Sources:  make(map[string]metadata.ContractSource),
+	}
+	cm.Compiler.Version = c.CompilerVersion
+
+	if len(c.CompilerSettings) > 0 {
+		// Settings are the Standard JSON ones, which are also the ones of the metadata.
+		_ = json.Unmarshal(c.CompilerSettings, &cm.Settings)
+	}
+
+	if cm.Settings.EvmVersion == "" && c.EVMVersion != "default" {
+		cm.Settings.EvmVersion = strings.ToLower(c.EVMVersion)
+	}
+
+	if len(c.CompilerSettings) == 0 {
+		cm.Settings.Optimizer.Enabled = c.OptimizationUsed == "true" || c.OptimizationUsed == "1"
+		_, _ = fmt.Sscan(strings.Trim(string(c.OptimizationRuns), `"`), &cm.Settings.Optimizer.Runs)
+	}
+
+	fileName := c.FileName
+	if fileName == "" {
+		fileName = c.Name + ".sol"
+	}
+	cm.Sources[fileName] = metadata.ContractSource{Content: c.SourceCode}
+
+	for _, source := range c.AdditionalSources {
+		cm.Sources[source.Filename] = metadata.ContractSource{Content: source.SourceCode}
+	}
+
+	return cm
+}
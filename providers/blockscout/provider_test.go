@@ -0,0 +1,130 @@
+package blockscout
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unpackdev/solgo/cache"
+	"github.com/unpackdev/solgo/metadata"
+	"github.com/unpackdev/solgo/providers/explorer"
+)
+
+func TestScanContract(t *testing.T) {
+	single := common.HexToAddress("0x01")
+	multiple := common.HexToAddress("0x02")
+	unverified := common.HexToAddress("0x03")
+
+	responses := map[string]string{
+		single.Hex(): `[{"SourceCode":"contract Token {}","ABI":"[]","ContractName":"Token","CompilerVersion":"v0.8.19+commit.7dd6d404",` +
+			`"OptimizationUsed":"true","OptimizationRuns":200,"EVMVersion":"default","IsProxy":"false"}]`,
+		multiple.Hex(): `[{"SourceCode":"import \"./Base.sol\"; contract Proxy is Base {}","ABI":"[]","ContractName":"Proxy",` +
+			`"CompilerVersion":"v0.8.19+commit.7dd6d404","OptimizationUsed":"true","EVMVersion":"paris","FileName":"contracts/Proxy.sol",` +
+			`"IsProxy":"true","ImplementationAddress":"0x0000000000000000000000000000000000000004",` +
+			`"AdditionalSources":[{"Filename":"contracts/Base.sol","SourceCode":"contract Base {}"}],` +
+			`"CompilerSettings":{"optimizer":{"enabled":true,"runs":1000},"evmVersion":"paris"}}]`,
+		unverified.Hex(): `[{"Address":"` + unverified.Hex() + `","ABI":"Contract source code not verified"}]`,
+	}
+
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, "getsourcecode", r.URL.Query().Get("action"))
+		fmt.Fprint(w, `{"status":"1","message":"OK","result":`+responses[r.URL.Query().Get("address")]+`}`)
+	}))
+	defer srv.Close()
+
+	provider, err := NewProvider(context.Background(), cache.NewLRUCache(10), &Options{Endpoint: srv.URL})
+	require.NoError(t, err)
+
+	var _ explorer.Provider = provider
+	assert.Equal(t, ProviderName, provider.ProviderName())
+
+	contract, err := provider.ScanContract(context.Background(), single)
+	require.NoError(t, err)
+	assert.Equal(t, "contract Token {}", contract.SourceCode)
+	assert.Equal(t, "Token", contract.Name)
+	assert.Equal(t, "true", contract.OptimizationUsed)
+	assert.Equal(t, "200", contract.Runs)
+	assert.Equal(t, "false", contract.Proxy)
+
+	contract, err = provider.ScanContract(context.Background(), multiple)
+	require.NoError(t, err)
+	assert.Equal(t, "1000", contract.Runs)
+	assert.Equal(t, "true", contract.Proxy)
+	assert.Equal(t, "0x0000000000000000000000000000000000000004", contract.Implementation)
+
+	cm, ok := contract.SourceCode.(metadata.ContractMetadata)
+	require.True(t, ok)
+	assert.Equal(t, "Solidity", cm.Language)
+	assert.Equal(t, "v0.8.19+commit.7dd6d404", cm.Compiler.Version)
+	assert.True(t, cm.Settings.Optimizer.Enabled)
+	assert.Equal(t, 1000, cm.Settings.Optimizer.Runs)
+	assert.Equal(t, "paris", cm.Settings.EvmVersion)
+	require.Len(t, cm.Sources, 2)
+	assert.Equal(t, "contract Base {}", cm.Sources["contracts/Base.sol"].Content)
+	assert.Contains(t, cm.Sources["contracts/Proxy.sol"].Content, "contract Proxy is Base")
+
+	_, err = provider.ScanContract(context.Background(), unverified)
+	assert.EqualError(t, err, "contract source code not verified")
+
+	// Verified contracts are served from the cache.
+	_, err = provider.ScanContract(context.Background(), single)
+	require.NoError(t, err)
+	assert.Equal(t, 3, requests)
+
+	_, err = NewProvider(context.Background(), nil, &Options{})
+	assert.Error(t, err)
+}
+
+func TestScanContractSharedCache(t *testing.T) {
+	addr := common.HexToAddress("0x01")
+
+	newServer := func(name string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"status":"1","message":"OK","result":[{"SourceCode":"contract `+name+` {}","ABI":"[]","ContractName":"`+name+`"}]}`)
+		}))
+	}
+
+	first := newServer("First")
+	defer first.Close()
+	second := newServer("Second")
+	defer second.Close()
+
+	testCases := []struct {
+		name   string
+		first  *Options
+		second *Options
+	}{
+		{
+			name:   "Endpoints",
+			first:  &Options{Endpoint: first.URL},
+			second: &Options{Endpoint: second.URL},
+		},
+		{
+			name:   "Chains",
+			first:  &Options{Endpoint: first.URL, ChainID: 1},
+			second: &Options{Endpoint: second.URL, ChainID: 10},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			store := cache.NewLRUCache(10)
+
+			for i, opts := range []*Options{testCase.first, testCase.second} {
+				provider, err := NewProvider(context.Background(), store, opts)
+				require.NoError(t, err)
+
+				contract, err := provider.ScanContract(context.Background(), addr)
+				require.NoError(t, err)
+				assert.Equal(t, []string{"First", "Second"}[i], contract.Name)
+			}
+		})
+	}
+}
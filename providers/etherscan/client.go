@@ -2,12 +2,10 @@ package etherscan
 
 import (
 	"context"
-	"fmt"
-	"github.com/unpackdev/solgo/utils"
-	"sync/atomic"
-	"time"
 
 	"github.com/redis/go-redis/v9"
+	"github.com/unpackdev/solgo/cache"
+	"github.com/unpackdev/solgo/providers/explorer"
 )
 
 // ErrorResponse represents the standard error response format returned by Etherscan's API.
@@ -17,61 +15,55 @@ type ErrorResponse struct {
 	Result  string `json:"result"`  // The result field, typically empty in error responses.
 }
 
-// Provider encapsulates the logic for interacting with the Etherscan API,
-// including handling API keys and caching responses.
+// Provider encapsulates the logic for interacting with the Etherscan API, including handling API keys
+// and caching responses. Listings, ABI and contract creation lookups are provided by the embedded explorer
+// client, so the provider implements explorer.Provider.
 type Provider struct {
-	ctx         context.Context    // The context for controlling cancellations and timeouts.
-	opts        *Options           // The configuration options for the provider.
-	cache       *redis.Client      // A Redis client for caching API responses.
-	keyIndex    int32              // An atomic counter for round-robin API key selection.
-	rateLimiter *utils.RateLimiter // Helper to reduce lookups onto the etherscan a.k.a rate limit based on a subscription type.
+	*explorer.Client
+
+	ctx  context.Context // The context for controlling cancellations and timeouts.
+	opts *Options        // The configuration options for the provider.
+}
+
+// NewProvider initializes a new EtherScanProvider with specified options, caching the responses in the Redis
+// client, or not at all when the client is nil. It returns an error if the provided options are invalid or
+// incomplete. Use NewProviderWithCache to cache the responses in any other cache.
+func NewProvider(ctx context.Context, client *redis.Client, opts *Options) (*Provider, error) {
+	var store cache.Cache
+	if client != nil {
+		store = cache.NewRedisCache(client)
+	}
+
+	return NewProviderWithCache(ctx, store, opts)
 }
 
-// NewProvider initializes a new EtherScanProvider with specified options and cache.
-// It returns an error if the provided options are invalid or incomplete.
-func NewProvider(ctx context.Context, cache *redis.Client, opts *Options) (*Provider, error) {
+// NewProviderWithCache initializes a new EtherScanProvider with specified options and cache. Responses are not
+// cached when the cache is nil. It returns an error if the provided options are invalid or incomplete.
+func NewProviderWithCache(ctx context.Context, store cache.Cache, opts *Options) (*Provider, error) {
 	if err := opts.Validate(); err != nil {
 		return nil, err
 	}
 
+	client, err := explorer.NewClient(ctx, store, &explorer.Options{
+		Name:      opts.Provider.String(),
+		Endpoint:  opts.Endpoint,
+		Keys:      opts.Keys,
+		RateLimit: opts.RateLimit,
+		ChainID:   opts.ChainID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	return &Provider{
-		ctx:         ctx,
-		opts:        opts,
-		cache:       cache,
-		keyIndex:    0,
-		rateLimiter: utils.NewRateLimiter(opts.RateLimit*len(opts.Keys), 1*time.Second),
+		Client: client,
+		ctx:    ctx,
+		opts:   opts,
 	}, nil
 }
 
-// ProviderName returns the name of the provider as specified in the options.
-func (e *Provider) ProviderName() string {
-	return e.opts.Provider.String()
-}
-
-// GetRateLimiter returns the instantiated rate limiter
-func (e *Provider) GetRateLimiter() *utils.RateLimiter {
-	return e.rateLimiter
-}
-
 // CacheKey generates a unique cache key for storing and retrieving API responses.
-// The key is composed using the API method and path.
+// The key is composed using the API method and path, and is namespaced by the provider name in the cache.
 func (e *Provider) CacheKey(method string, path string) string {
-	return fmt.Sprintf(
-		"etherscan::%s::%s",
-		method,
-		path,
-	)
-}
-
-// GetNextKey selects the next API key to use for a request in a round-robin fashion.
-// This method ensures even distribution of request load across all configured API keys.
-func (e *Provider) GetNextKey() string {
-	// Atomically increment the keyIndex and get the next index
-	nextIndex := atomic.AddInt32(&e.keyIndex, 1)
-
-	// Ensure the index wraps around the length of the keys slice
-	keyCount := int32(len(e.opts.Keys))
-	selectedIndex := nextIndex % keyCount
-
-	return e.opts.Keys[selectedIndex]
+	return cache.Key(method, path)
 }
@@ -5,45 +5,17 @@ import (
 	"errors"
 	"fmt"
 	"github.com/goccy/go-json"
-	"io"
-	"net"
-	"net/http"
 	"strings"
-	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/unpackdev/solgo/cache"
 	"github.com/unpackdev/solgo/metadata"
+	"github.com/unpackdev/solgo/providers/explorer"
 )
 
 // Contract represents the detailed information of a smart contract
 // including its source code, ABI, and other metadata as returned by the Etherscan API.
-type Contract struct {
-	SourceCode           interface{} `json:"SourceCode"`           // The source code of the contract. Can be a plain string or a structured metadata object.
-	ABI                  string      `json:"ABI"`                  // The ABI (Application Binary Interface) of the contract in JSON format.
-	Name                 string      `json:"ContractName"`         // The name of the contract.
-	CompilerVersion      string      `json:"CompilerVersion"`      // The version of the Solidity compiler used to compile the contract.
-	OptimizationUsed     string      `json:"OptimizationUsed"`     // Indicates if optimization was used during compilation.
-	Runs                 string      `json:"Runs"`                 // The number of runs specified for the optimizer.
-	ConstructorArguments string      `json:"ConstructorArguments"` // The constructor arguments used when deploying the contract.
-	EVMVersion           string      `json:"EVMVersion"`           // The version of the Ethereum Virtual Machine (EVM) for which the contract was compiled.
-	Library              string      `json:"Library"`              // Specifies any library used in the contract.
-	LicenseType          string      `json:"LicenseType"`          // The license type under which the contract source code is provided.
-	Proxy                string      `json:"Proxy"`                // Indicates if the contract is a proxy contract.
-	Implementation       string      `json:"Implementation"`       // The address of the implementation contract, if this is a proxy contract.
-	SwarmSource          string      `json:"SwarmSource"`          // The Swarm source of the contract's metadata.
-}
-
-// MarshalBinary implements the encoding.BinaryMarshaler interface for Contract.
-// It returns the JSON encoding of the contract.
-func (c Contract) MarshalBinary() ([]byte, error) {
-	return json.Marshal(c)
-}
-
-// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface for Contract.
-// It parses a JSON-encoded contract and stores the result in the Contract.
-func (c Contract) UnmarshalBinary(data []byte) error {
-	return json.Unmarshal(data, c)
-}
+type Contract = explorer.Contract
 
 // ContractResponse encapsulates the response structure for contract queries
 // made to the Etherscan API.
@@ -62,75 +34,23 @@ type ContractResponse struct {
 func (e *Provider) ScanContract(ctx context.Context, addr common.Address) (*Contract, error) {
 	cacheKey := e.CacheKey("getsourcecode_method_1", addr.Hex())
 
-	if e.cache != nil {
-		if result, err := e.cache.Exists(e.ctx, cacheKey).Result(); err == nil && result == 1 {
-			if result, err := e.cache.Get(e.ctx, cacheKey).Result(); err == nil {
-				var response *Contract
-				if err := json.Unmarshal([]byte(result), &response); err != nil {
-					return nil, fmt.Errorf("failed to unmarshal %s response: %s", e.ProviderName(), err)
-				}
-				return response, nil
-			}
-		}
+	var cached *Contract
+	if err := e.GetCache().GetJSON(ctx, cacheKey, &cached); err == nil {
+		return cached, nil
+	} else if !errors.Is(err, cache.ErrNotFound) {
+		return nil, fmt.Errorf("failed to unmarshal %s response: %s", e.ProviderName(), err)
 	}
 
-	// Wait for the next token to be available
-	e.rateLimiter.WaitForToken()
-
-	url := fmt.Sprintf(
-		"%s?module=contract&action=getsourcecode&address=%s&apikey=%s",
-		e.opts.Endpoint, addr.Hex(), e.GetNextKey(),
-	)
-
-	customHttpClient := &http.Client{
-		// Timeout for the whole request, including dialing, reading the response, etc.
-		Timeout: 15 * time.Second,
-		Transport: &http.Transport{
-			// Timeout for the connection to be established
-			DialContext: (&net.Dialer{
-				Timeout:   5 * time.Second,
-				KeepAlive: 30 * time.Second,
-			}).DialContext,
-			// Timeout for the TLS handshake
-			TLSHandshakeTimeout: 5 * time.Second,
-			// Max idle connections per host
-			MaxIdleConnsPerHost: 10,
-			// Idle connection timeout
-			IdleConnTimeout: 90 * time.Second,
-		},
+	var contracts []Contract
+	if err := e.GetSourceCode(ctx, addr, &contracts); err != nil {
+		return nil, err
 	}
 
-	// Create a new request with context
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %s", err)
-	}
-
-	resp, err := customHttpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send HTTP request: %s", err)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %s", err)
-	}
-
-	if strings.Contains(string(body), "NOTOK") {
-		var contractResponse ErrorResponse
-		if err := json.Unmarshal(body, &contractResponse); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal error response: %s", err)
-		}
-		return nil, errors.New(contractResponse.Result)
-	}
-
-	var contractResponse ContractResponse
-	if err := json.Unmarshal(body, &contractResponse); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal etherscan response: %s", err)
+	if len(contracts) == 0 {
+		return nil, fmt.Errorf("contract not found")
 	}
 
-	toReturn := contractResponse.Result[0]
+	toReturn := contracts[0]
 
 	if toReturn.ABI == "Contract source code not verified" {
 		return nil, fmt.Errorf("contract source code not verified")
@@ -168,10 +88,9 @@ func (e *Provider) ScanContract(ctx context.Context, addr common.Address) (*Cont
 		return nil, fmt.Errorf("contract not found")
 	}
 
-	if e.cache != nil {
-		if err := e.cache.Set(e.ctx, cacheKey, toReturn, 1*time.Hour).Err(); err != nil {
-			return nil, fmt.Errorf("failed to write to cache: %s", err)
-		}
+	// Verified sources do not change, so they are cached as the finalized data.
+	if err := e.GetCache().SetJSON(ctx, cacheKey, toReturn, cache.Finalized); err != nil {
+		return nil, fmt.Errorf("failed to write to cache: %s", err)
 	}
 
 	return &toReturn, nil
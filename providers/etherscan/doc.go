@@ -1,3 +1,4 @@
-// Package etherscan provides a client for interacting with Etherscan's API,
-// enabling the retrieval of blockchain data and supporting rate-limited API key rotation.
+// Package etherscan provides a client for interacting with Etherscan's API, both the single chain one and
+// the multichain V2 one selecting the chain by its ID, enabling the retrieval of blockchain data and supporting
+// rate-limited API key rotation. Provider implements explorer.Provider.
 package etherscan
@@ -2,6 +2,9 @@ package etherscan
 
 import "errors"
 
+// EtherscanV2Endpoint is the endpoint of the Etherscan V2 API serving all the supported chains by the chain ID.
+const EtherscanV2Endpoint = "https://api.etherscan.io/v2/api"
+
 // Options holds the configuration settings for an etherscan client.
 // These settings define how the client interacts with the blockchain explorer APIs.
 type Options struct {
@@ -19,12 +22,20 @@ type Options struct {
 	// Keys contains a list of API keys used for authenticating requests to the blockchain explorer API.
 	// The client can rotate through these keys to manage rate limits.
 	Keys []string `json:"keys" yaml:"keys" mapstructure:"keys"`
+
+	// ChainID specifies the chain of the multichain Etherscan V2 API, sent with every request when set.
+	// Single chain explorers, such as the Etherscan V1 API or BscScan, leave it unset.
+	ChainID uint64 `json:"chainId" yaml:"chainId" mapstructure:"chainId"`
 }
 
 // Validate checks the integrity and completeness of the Options settings.
 // It ensures that all necessary configurations are properly set and valid,
 // including non-empty Endpoint, at least one API key, and a supported Provider.
 func (o *Options) Validate() error {
+	if o == nil {
+		return errors.New("etherscan provider is not configured")
+	}
+
 	if o.Endpoint == "" {
 		return errors.New("endpoint is required but not set")
 	}
@@ -0,0 +1,408 @@
+package explorer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/goccy/go-json"
+	"github.com/unpackdev/solgo/cache"
+	"github.com/unpackdev/solgo/utils"
+)
+
+// DefaultTimeout is the time the request to the explorer may take when the timeout is not set.
+const DefaultTimeout = 15 * time.Second
+
+// notVerified is the result the explorers return for the contracts without the verified source code.
+const notVerified = "Contract source code not verified"
+
+// Options defines the explorer API the client talks to.
+type Options struct {
+	// Name is the name of the explorer, used to namespace the cached responses.
+	Name string `mapstructure:"name" yaml:"name" json:"name"`
+
+	// Endpoint is the base URL of the explorer API.
+	Endpoint string `mapstructure:"endpoint" yaml:"endpoint" json:"endpoint"`
+
+	// Keys are the API keys rotated in turns, and on the rate limit. Requests are sent without the key when empty.
+	Keys []string `mapstructure:"keys" yaml:"keys" json:"keys"`
+
+	// RateLimit is the number of the requests per second allowed per API key, unlimited when zero.
+	RateLimit int `mapstructure:"rateLimit" yaml:"rateLimit" json:"rateLimit"`
+
+	// ChainID is the chain the requests are made for, sent with every request to the multichain APIs when set.
+	ChainID uint64 `mapstructure:"chainId" yaml:"chainId" json:"chainId"`
+
+	// Timeout is the time the single request may take.
+	Timeout time.Duration `mapstructure:"timeout" yaml:"timeout" json:"timeout"`
+
+	// Namespace is the namespace of the cached responses, the name along with the chain ID when empty. It must be
+	// distinct for every chain the explorers share the cache for.
+	Namespace string `mapstructure:"namespace" yaml:"namespace" json:"namespace"`
+}
+
+// GetTimeout returns the time the single request may take.
+func (o *Options) GetTimeout() time.Duration {
+	if o.Timeout <= 0 {
+		return DefaultTimeout
+	}
+	return o.Timeout
+}
+
+// Client is the client of the Etherscan compatible explorer API. It implements the listings, ABI and contract
+// creation lookups shared by the explorers, leaving the parsing of the verified source code to them.
+type Client struct {
+	ctx         context.Context
+	opts        *Options
+	http        *http.Client
+	cache       *cache.Namespace
+	keyIndex    int32              // An atomic counter for round-robin API key selection.
+	rateLimiter *utils.RateLimiter // Limits the requests to the rate allowed for all the API keys, nil when unlimited.
+}
+
+// NewClient creates the client of the explorer API, caching the responses that do not change in the given cache.
+func NewClient(ctx context.Context, store cache.Cache, opts *Options) (*Client, error) {
+	if opts == nil || opts.Endpoint == "" {
+		return nil, ErrEndpointNotSet
+	}
+
+	namespace := opts.Namespace
+	if namespace == "" {
+		namespace = opts.Name
+	}
+	if opts.Namespace == "" && opts.ChainID != 0 {
+		namespace = cache.Key(opts.Name, strconv.FormatUint(opts.ChainID, 10))
+	}
+
+	client := &Client{
+		ctx:  ctx,
+		opts: opts,
+		http: &http.Client{
+			// Timeout for the whole request, including dialing, reading the response, etc.
+			Timeout: opts.GetTimeout(),
+			Transport: &http.Transport{
+				DialContext: (&net.Dialer{
+					Timeout:   5 * time.Second,
+					KeepAlive: 30 * time.Second,
+				}).DialContext,
+				TLSHandshakeTimeout: 5 * time.Second,
+				MaxIdleConnsPerHost: 10,
+				IdleConnTimeout:     90 * time.Second,
+			},
+		},
+		cache: cache.NewNamespace(store, namespace, nil),
+	}
+
+	if opts.RateLimit > 0 {
+		client.rateLimiter = utils.NewRateLimiter(opts.RateLimit*max(len(opts.Keys), 1), time.Second)
+	}
+
+	return client, nil
+}
+
+// ProviderName returns the name of the explorer.
+func (c *Client) ProviderName() string {
+	return c.opts.Name
+}
+
+// GetOptions returns the options of the client.
+func (c *Client) GetOptions() *Options {
+	return c.opts
+}
+
+// GetCache returns the cache namespace of the explorer.
+func (c *Client) GetCache() *cache.Namespace {
+	return c.cache
+}
+
+// GetRateLimiter returns the rate limiter of the client, nil when the requests are not limited.
+func (c *Client) GetRateLimiter() *utils.RateLimiter {
+	return c.rateLimiter
+}
+
+// GetNextKey selects the next API key to use for a request in a round-robin fashion, empty when no keys are set.
+func (c *Client) GetNextKey() string {
+	if len(c.opts.Keys) == 0 {
+		return ""
+	}
+
+	nextIndex := atomic.AddInt32(&c.keyIndex, 1)
+	return c.opts.Keys[int(nextIndex)%len(c.opts.Keys)]
+}
+
+// response is the envelope of the explorer API responses.
+type response struct {
+	Status  string          `json:"status"`
+	Message string          `json:"message"`
+	Result  json.RawMessage `json:"result"`
+}
+
+// Call sends the request of the API module and action given by the parameters and decodes its result into
+// the result argument. Requests rejected because of the rate limit or the API key are retried with the other
+// API keys. Empty listings are returned as the empty result rather than the error.
+func (c *Client) Call(ctx context.Context, params url.Values, result interface{}) error {
+	attempts := max(len(c.opts.Keys), 1)
+
+	var err error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if err = c.call(ctx, params, result); err == nil {
+			return nil
+		}
+
+		if !errors.Is(err, ErrRateLimited) && !errors.Is(err, ErrInvalidKey) {
+			return err
+		}
+	}
+
+	return err
+}
+
+// call sends the single request with the next API key.
+func (c *Client) call(ctx context.Context, params url.Values, result interface{}) error {
+	if c.rateLimiter != nil {
+		if err := c.rateLimiter.Wait(ctx); err != nil {
+			return err
+		}
+	}
+
+	query := url.Values{}
+	for key, values := range params {
+		query[key] = values
+	}
+
+	if c.opts.ChainID != 0 {
+		query.Set("chainid", strconv.FormatUint(c.opts.ChainID, 10))
+	}
+
+	if key := c.GetNextKey(); key != "" {
+		query.Set("apikey", key)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.opts.Endpoint+"?"+query.Encode(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send HTTP request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return fmt.Errorf("%w: %s", ErrRateLimited, resp.Status)
+	} else if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected %s response status: %s", c.opts.Name, resp.Status)
+	}
+
+	var envelope response
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return fmt.Errorf("failed to unmarshal %s response: %w", c.opts.Name, err)
+	}
+
+	if envelope.Status != "1" {
+		// Empty listings are reported with the zero status and the empty result, such as "No transactions found".
+		if len(envelope.Result) > 0 && envelope.Result[0] == '[' {
+			return json.Unmarshal(envelope.Result, result)
+		}
+
+		var message string
+		if err := json.Unmarshal(envelope.Result, &message); err != nil || message == "" {
+			message = envelope.Message
+		}
+
+		switch lower := strings.ToLower(message); {
+		case strings.Contains(lower, "rate limit"):
+			return fmt.Errorf("%w: %s", ErrRateLimited, message)
+		case strings.Contains(lower, "invalid api key"), strings.Contains(lower, "missing/invalid api key"):
+			return fmt.Errorf("%w: %s", ErrInvalidKey, message)
+		default:
+			return errors.New(message)
+		}
+	}
+
+	if err := json.Unmarshal(envelope.Result, result); err != nil {
+		return fmt.Errorf("failed to unmarshal %s result: %w", c.opts.Name, err)
+	}
+
+	return nil
+}
+
+// GetSourceCode decodes the verified source code of the contract, in the format of the explorer, into the
+// result argument.
+func (c *Client) GetSourceCode(ctx context.Context, addr common.Address, result interface{}) error {
+	return c.Call(ctx, url.Values{
+		"module":  {"contract"},
+		"action":  {"getsourcecode"},
+		"address": {addr.Hex()},
+	}, result)
+}
+
+// QueryContractCreationTx returns the creator and the creation transaction of the contract. Contracts are
+// created only once, so the creation is cached as the finalized data.
+func (c *Client) QueryContractCreationTx(ctx context.Context, addr common.Address) (*ContractCreation, error) {
+	cacheKey := cache.Key("getcontractcreation", addr.Hex())
+
+	var cached *ContractCreation
+	if err := c.cache.GetJSON(ctx, cacheKey, &cached); err == nil {
+		return cached, nil
+	} else if !errors.Is(err, cache.ErrNotFound) {
+		return nil, fmt.Errorf("failed to unmarshal %s response: %s", c.opts.Name, err)
+	}
+
+	var creations []*ContractCreation
+	if err := c.Call(ctx, url.Values{
+		"module":            {"contract"},
+		"action":            {"getcontractcreation"},
+		"contractaddresses": {addr.Hex()},
+	}, &creations); err != nil {
+		return nil, err
+	}
+
+	if len(creations) == 0 {
+		return nil, fmt.Errorf("contract creation of %s not found", addr.Hex())
+	}
+
+	if err := c.cache.SetJSON(ctx, cacheKey, creations[0], cache.Finalized); err != nil {
+		return nil, fmt.Errorf("failed to write to cache: %s", err)
+	}
+
+	return creations[0], nil
+}
+
+// GetABI returns the JSON ABI of the verified contract. Verified contracts do not change, so the ABI is cached
+// as the finalized data.
+func (c *Client) GetABI(ctx context.Context, addr common.Address) (string, error) {
+	cacheKey := cache.Key("getabi", addr.Hex())
+	if cached, err := c.cache.Get(ctx, cacheKey); err == nil {
+		return string(cached), nil
+	}
+
+	var abi string
+	if err := c.Call(ctx, url.Values{
+		"module":  {"contract"},
+		"action":  {"getabi"},
+		"address": {addr.Hex()},
+	}, &abi); err != nil {
+		return "", err
+	}
+
+	if err := c.cache.Set(ctx, cacheKey, []byte(abi), cache.Finalized); err != nil {
+		return "", fmt.Errorf("failed to write to cache: %s", err)
+	}
+
+	return abi, nil
+}
+
+// IsVerified reports whether the source code of the contract is verified.
+func (c *Client) IsVerified(ctx context.Context, addr common.Address) (bool, error) {
+	if _, err := c.GetABI(ctx, addr); err != nil {
+		if strings.Contains(err.Error(), notVerified) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// GetTransactions returns the page of the transactions of the account.
+func (c *Client) GetTransactions(ctx context.Context, addr common.Address, page *Page) ([]*Transaction, error) {
+	var transactions []*Transaction
+	if err := c.Call(ctx, c.listParams("account", "txlist", addr, page), &transactions); err != nil {
+		return nil, err
+	}
+	return transactions, nil
+}
+
+// GetInternalTransactions returns the page of the internal transactions of the account.
+func (c *Client) GetInternalTransactions(ctx context.Context, addr common.Address, page *Page) ([]*InternalTransaction, error) {
+	var transactions []*InternalTransaction
+	if err := c.Call(ctx, c.listParams("account", "txlistinternal", addr, page), &transactions); err != nil {
+		return nil, err
+	}
+	return transactions, nil
+}
+
+// GetLogs returns the page of the event logs matching the query. The block range of the page is ignored in
+// favor of the one of the query.
+func (c *Client) GetLogs(ctx context.Context, query *LogsQuery, page *Page) ([]*Log, error) {
+	if query == nil {
+		query = &LogsQuery{}
+	}
+
+	if len(query.Topics) > 4 {
+		return nil, fmt.Errorf("too many log topics: %d", len(query.Topics))
+	}
+
+	params := url.Values{
+		"module":    {"logs"},
+		"action":    {"getLogs"},
+		"fromBlock": {strconv.FormatUint(query.FromBlock, 10)},
+		"toBlock":   {"latest"},
+		"page":      {strconv.Itoa(page.GetPage())},
+		"offset":    {strconv.Itoa(page.GetOffset())},
+	}
+
+	if query.ToBlock != 0 {
+		params.Set("toBlock", strconv.FormatUint(query.ToBlock, 10))
+	}
+
+	if query.Address != (common.Address{}) {
+		params.Set("address", query.Address.Hex())
+	}
+
+	for i, topic := range query.Topics {
+		if topic == nil {
+			continue
+		}
+		params.Set(fmt.Sprintf("topic%d", i), topic.Hex())
+
+		for j := 0; j < i; j++ {
+			if query.Topics[j] != nil {
+				params.Set(fmt.Sprintf("topic%d_%d_opr", j, i), "and")
+			}
+		}
+	}
+
+	var logs []*Log
+	if err := c.Call(ctx, params, &logs); err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
+// listParams returns the parameters of the account listing of the page.
+func (c *Client) listParams(module string, action string, addr common.Address, page *Page) url.Values {
+	params := url.Values{
+		"module":  {module},
+		"action":  {action},
+		"address": {addr.Hex()},
+		"page":    {strconv.Itoa(page.GetPage())},
+		"offset":  {strconv.Itoa(page.GetOffset())},
+		"sort":    {string(page.GetSort())},
+	}
+
+	// Listings are made up to the latest block when the end block is not sent.
+	if page != nil {
+		params.Set("startblock", strconv.FormatUint(page.StartBlock, 10))
+		if page.EndBlock != 0 {
+			params.Set("endblock", strconv.FormatUint(page.EndBlock, 10))
+		}
+	}
+
+	return params
+}
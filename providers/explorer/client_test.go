@@ -0,0 +1,264 @@
+package explorer
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unpackdev/solgo/cache"
+)
+
+// server is the local stand-in of the explorer API, answering the requests with the handler and recording
+// their parameters.
+type server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	requests []url.Values
+}
+
+func newServer(t *testing.T, handler func(query url.Values) string) *server {
+	s := &server{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests = append(s.requests, r.URL.Query())
+		s.mu.Unlock()
+		fmt.Fprint(w, handler(r.URL.Query()))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *server) getRequests() []url.Values {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]url.Values{}, s.requests...)
+}
+
+func TestClientCall(t *testing.T) {
+	addr := common.HexToAddress("0x1f9840a85d5aF5bf1D1762F925BDADdC4201F984")
+
+	testCases := []struct {
+		name      string
+		keys      []string
+		chainID   uint64
+		handler   func(query url.Values) string
+		wantErr   error
+		wantMsg   string
+		wantTxs   int
+		wantKeys  []string
+		wantChain string
+	}{
+		{
+			name:    "Etherscan V2",
+			keys:    []string{"first"},
+			chainID: 8453,
+			handler: func(query url.Values) string {
+				return `{"status":"1","message":"OK","result":[{"hash":"0x01","blockNumber":"10"}]}`
+			},
+			wantTxs:   1,
+			wantKeys:  []string{"first"},
+			wantChain: "8453",
+		},
+		{
+			name: "Key Rotation On Rate Limit",
+			keys: []string{"first", "second"},
+			handler: func(query url.Values) string {
+				if query.Get("apikey") == "second" {
+					return `{"status":"0","message":"NOTOK","result":"Max rate limit reached"}`
+				}
+				return `{"status":"1","message":"OK","result":[{"hash":"0x01"},{"hash":"0x02"}]}`
+			},
+			wantTxs:  2,
+			wantKeys: []string{"second", "first"},
+		},
+		{
+			name: "Invalid Keys",
+			keys: []string{"first", "second"},
+			handler: func(query url.Values) string {
+				return `{"status":"0","message":"NOTOK","result":"Invalid API Key"}`
+			},
+			wantErr:  ErrInvalidKey,
+			wantKeys: []string{"second", "first"},
+		},
+		{
+			name: "Empty Listing",
+			handler: func(query url.Values) string {
+				return `{"status":"0","message":"No transactions found","result":[]}`
+			},
+			wantKeys: []string{""},
+		},
+		{
+			name: "Error Message",
+			handler: func(query url.Values) string {
+				return `{"status":"0","message":"NOTOK","result":"Error! Invalid address format"}`
+			},
+			wantMsg:  "Error! Invalid address format",
+			wantKeys: []string{""},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			srv := newServer(t, testCase.handler)
+
+			client, err := NewClient(context.Background(), nil, &Options{
+				Name:     "test",
+				Endpoint: srv.URL,
+				Keys:     testCase.keys,
+				ChainID:  testCase.chainID,
+			})
+			require.NoError(t, err)
+
+			txs, err := client.GetTransactions(context.Background(), addr, nil)
+			switch {
+			case testCase.wantErr != nil:
+				assert.ErrorIs(t, err, testCase.wantErr)
+			case testCase.wantMsg != "":
+				assert.EqualError(t, err, testCase.wantMsg)
+			default:
+				require.NoError(t, err)
+				assert.Len(t, txs, testCase.wantTxs)
+			}
+
+			requests := srv.getRequests()
+			require.Len(t, requests, len(testCase.wantKeys))
+			for i, request := range requests {
+				assert.Equal(t, "account", request.Get("module"))
+				assert.Equal(t, "txlist", request.Get("action"))
+				assert.Equal(t, addr.Hex(), request.Get("address"))
+				assert.Equal(t, testCase.wantKeys[i], request.Get("apikey"))
+				assert.Equal(t, testCase.wantChain, request.Get("chainid"))
+			}
+		})
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	addr := common.HexToAddress("0x1f9840a85d5aF5bf1D1762F925BDADdC4201F984")
+
+	// Five internal transactions served in the pages of two.
+	srv := newServer(t, func(query url.Values) string {
+		page, _ := strconv.Atoi(query.Get("page"))
+		offset, _ := strconv.Atoi(query.Get("offset"))
+
+		result := "["
+		for i := (page - 1) * offset; i < min(page*offset, 5); i++ {
+			if i > (page-1)*offset {
+				result += ","
+			}
+			result += fmt.Sprintf(`{"hash":"0x%02x","blockNumber":"%d","isError":"0"}`, i, 100+i)
+		}
+		return `{"status":"1","message":"OK","result":` + result + `]}`
+	})
+
+	client, err := NewClient(context.Background(), nil, &Options{Name: "test", Endpoint: srv.URL})
+	require.NoError(t, err)
+
+	txs, err := Paginate(context.Background(), &Page{Offset: 2, StartBlock: 100, Sort: SortDesc},
+		func(ctx context.Context, page *Page) ([]*InternalTransaction, error) {
+			return client.GetInternalTransactions(ctx, addr, page)
+		},
+	)
+	require.NoError(t, err)
+	require.Len(t, txs, 5)
+
+	for i, tx := range txs {
+		assert.Equal(t, uint64(100+i), tx.GetBlockNumber())
+		assert.False(t, tx.IsFailed())
+	}
+
+	requests := srv.getRequests()
+	require.Len(t, requests, 3)
+	for i, request := range requests {
+		assert.Equal(t, "txlistinternal", request.Get("action"))
+		assert.Equal(t, strconv.Itoa(i+1), request.Get("page"))
+		assert.Equal(t, "2", request.Get("offset"))
+		assert.Equal(t, "100", request.Get("startblock"))
+		assert.Equal(t, "desc", request.Get("sort"))
+		assert.False(t, request.Has("endblock"))
+	}
+}
+
+func TestClientGetLogs(t *testing.T) {
+	addr := common.HexToAddress("0x1f9840a85d5aF5bf1D1762F925BDADdC4201F984")
+	transfer := common.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")
+	to := common.HexToHash("0x01")
+
+	srv := newServer(t, func(query url.Values) string {
+		return `{"status":"1","message":"OK","result":[{"address":"` + addr.Hex() + `","topics":["` + transfer.Hex() + `"],` +
+			`"data":"0x01","blockNumber":"0x0a","logIndex":"0x","transactionIndex":"0x02","transactionHash":"0x03"}]}`
+	})
+
+	client, err := NewClient(context.Background(), nil, &Options{Name: "test", Endpoint: srv.URL})
+	require.NoError(t, err)
+
+	logs, err := client.GetLogs(context.Background(), &LogsQuery{
+		Address:   addr,
+		FromBlock: 10,
+		Topics:    []*common.Hash{&transfer, nil, &to},
+	}, nil)
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+
+	log, err := logs[0].ToLog()
+	require.NoError(t, err)
+	assert.Equal(t, addr, log.Address)
+	assert.Equal(t, []common.Hash{transfer}, log.Topics)
+	assert.Equal(t, []byte{0x01}, log.Data)
+	assert.Equal(t, uint64(10), log.BlockNumber)
+	assert.Equal(t, uint(0), log.Index)
+	assert.Equal(t, uint(2), log.TxIndex)
+
+	requests := srv.getRequests()
+	require.Len(t, requests, 1)
+	assert.Equal(t, "logs", requests[0].Get("module"))
+	assert.Equal(t, "getLogs", requests[0].Get("action"))
+	assert.Equal(t, "10", requests[0].Get("fromBlock"))
+	assert.Equal(t, "latest", requests[0].Get("toBlock"))
+	assert.Equal(t, transfer.Hex(), requests[0].Get("topic0"))
+	assert.False(t, requests[0].Has("topic1"))
+	assert.Equal(t, to.Hex(), requests[0].Get("topic2"))
+	assert.Equal(t, "and", requests[0].Get("topic0_2_opr"))
+	assert.False(t, requests[0].Has("topic1_2_opr"))
+
+	_, err = client.GetLogs(context.Background(), &LogsQuery{Topics: make([]*common.Hash, 5)}, nil)
+	assert.Error(t, err)
+}
+
+func TestClientVerification(t *testing.T) {
+	verified := common.HexToAddress("0x01")
+	unverified := common.HexToAddress("0x02")
+
+	srv := newServer(t, func(query url.Values) string {
+		if query.Get("address") == unverified.Hex() {
+			return `{"status":"0","message":"NOTOK","result":"Contract source code not verified"}`
+		}
+		return `{"status":"1","message":"OK","result":"[]"}`
+	})
+
+	client, err := NewClient(context.Background(), cache.NewLRUCache(10), &Options{Name: "test", Endpoint: srv.URL})
+	require.NoError(t, err)
+
+	ok, err := client.IsVerified(context.Background(), verified)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = client.IsVerified(context.Background(), unverified)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// The ABI of the verified contract is served from the cache.
+	abi, err := client.GetABI(context.Background(), verified)
+	require.NoError(t, err)
+	assert.Equal(t, "[]", abi)
+	assert.Len(t, srv.getRequests(), 2)
+}
@@ -0,0 +1,6 @@
+// Package explorer defines the Provider interface of the blockchain explorers, such as Etherscan and Blockscout,
+// together with the Client of their Etherscan compatible API. Client rotates the API keys, respects the rate
+// limits and paginates the transaction, internal transaction and log listings, while the explorers implement
+// the Provider on top of it, parsing the explorer specific responses such as the verified source code. The
+// Etherscan V2 API selects the chain by its ID, so a single Etherscan provider serves all the chains.
+package explorer
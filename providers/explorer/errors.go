@@ -0,0 +1,12 @@
+package explorer
+
+import "errors"
+
+// ErrRateLimited is returned when the explorer rejects the request because of the rate limit of the API key.
+var ErrRateLimited = errors.New("explorer rate limit reached")
+
+// ErrInvalidKey is returned when the explorer rejects the API key.
+var ErrInvalidKey = errors.New("invalid explorer API key")
+
+// ErrEndpointNotSet is returned when the explorer endpoint is not configured.
+var ErrEndpointNotSet = errors.New("explorer endpoint is required but not set")
@@ -0,0 +1,120 @@
+package explorer
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// DefaultPageSize is the number of the records requested per page when the page size is not set.
+const DefaultPageSize = 1000
+
+// Provider is the interface of the blockchain explorers providing the verified contracts and the account
+// history, implemented for the Etherscan and Blockscout APIs.
+type Provider interface {
+	// ProviderName returns the name of the explorer.
+	ProviderName() string
+
+	// ScanContract returns the verified source code and compiler settings of the contract.
+	ScanContract(ctx context.Context, addr common.Address) (*Contract, error)
+
+	// QueryContractCreationTx returns the creator and the creation transaction of the contract.
+	QueryContractCreationTx(ctx context.Context, addr common.Address) (*ContractCreation, error)
+
+	// GetABI returns the JSON ABI of the verified contract.
+	GetABI(ctx context.Context, addr common.Address) (string, error)
+
+	// IsVerified reports whether the source code of the contract is verified.
+	IsVerified(ctx context.Context, addr common.Address) (bool, error)
+
+	// GetTransactions returns the page of the transactions of the account.
+	GetTransactions(ctx context.Context, addr common.Address, page *Page) ([]*Transaction, error)
+
+	// GetInternalTransactions returns the page of the internal transactions of the account.
+	GetInternalTransactions(ctx context.Context, addr common.Address, page *Page) ([]*InternalTransaction, error)
+
+	// GetLogs returns the page of the event logs matching the query.
+	GetLogs(ctx context.Context, query *LogsQuery, page *Page) ([]*Log, error)
+}
+
+// Sort is the order of the listed records by their block number.
+type Sort string
+
+const (
+	// SortAsc lists the oldest records first.
+	SortAsc Sort = "asc"
+
+	// SortDesc lists the newest records first.
+	SortDesc Sort = "desc"
+)
+
+// Page selects the page of the listing and the block range it is listed from. Explorers limit the number
+// of the records reachable by the pagination, so the larger listings need the narrower block ranges.
+type Page struct {
+	Page       int    // Number of the page, starting at one.
+	Offset     int    // Number of the records per page.
+	StartBlock uint64 // First block of the listing.
+	EndBlock   uint64 // Last block of the listing, the latest block when zero.
+	Sort       Sort   // Order of the records, ascending when empty.
+}
+
+// GetPage returns the number of the page, the first page when not set.
+func (p *Page) GetPage() int {
+	if p == nil || p.Page <= 0 {
+		return 1
+	}
+	return p.Page
+}
+
+// GetOffset returns the number of the records per page.
+func (p *Page) GetOffset() int {
+	if p == nil || p.Offset <= 0 {
+		return DefaultPageSize
+	}
+	return p.Offset
+}
+
+// GetSort returns the order of the records.
+func (p *Page) GetSort() Sort {
+	if p == nil || p.Sort == "" {
+		return SortAsc
+	}
+	return p.Sort
+}
+
+// LogsQuery filters the event logs by the emitting contract and the topics.
+type LogsQuery struct {
+	Address   common.Address // Contract emitting the logs, any when zero.
+	FromBlock uint64         // First block of the logs.
+	ToBlock   uint64         // Last block of the logs, the latest block when zero.
+	Topics    []*common.Hash // Topics by their position, up to four, any topic where nil. Topics are combined with AND.
+}
+
+// Paginate fetches the pages of the listing, starting at the given page, until the page is not full and
+// returns the records of all of them.
+func Paginate[T any](ctx context.Context, page *Page, fetch func(ctx context.Context, page *Page) ([]T, error)) ([]T, error) {
+	current := Page{}
+	if page != nil {
+		current = *page
+	}
+	current.Page = page.GetPage()
+	current.Offset = page.GetOffset()
+
+	var records []T
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		batch, err := fetch(ctx, &current)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, batch...)
+
+		if len(batch) < current.Offset {
+			return records, nil
+		}
+		current.Page++
+	}
+}
@@ -0,0 +1,216 @@
+package explorer
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/goccy/go-json"
+)
+
+// Contract represents the detailed information of a smart contract
+// including its source code, ABI, and other metadata as returned by the explorer API.
+type Contract struct {
+	SourceCode           interface{} `json:"SourceCode"`           // The source code of the contract. Can be a plain string or a structured metadata object.
+	ABI                  string      `json:"ABI"`                  // The ABI (Application Binary Interface) of the contract in JSON format.
+	Name                 string      `json:"ContractName"`         // The name of the contract.
+	CompilerVersion      string      `json:"CompilerVersion"`      // The version of the Solidity compiler used to compile the contract.
+	OptimizationUsed     string      `json:"OptimizationUsed"`     // Indicates if optimization was used during compilation.
+	Runs                 string      `json:"Runs"`                 // The number of runs specified for the optimizer.
+	ConstructorArguments string      `json:"ConstructorArguments"` // The constructor arguments used when deploying the contract.
+	EVMVersion           string      `json:"EVMVersion"`           // The version of the Ethereum Virtual Machine (EVM) for which the contract was compiled.
+	Library              string      `json:"Library"`              // Specifies any library used in the contract.
+	LicenseType          string      `json:"LicenseType"`          // The license type under which the contract source code is provided.
+	Proxy                string      `json:"Proxy"`                // Indicates if the contract is a proxy contract.
+	Implementation       string      `json:"Implementation"`       // The address of the implementation contract, if this is a proxy contract.
+	SwarmSource          string      `json:"SwarmSource"`          // The Swarm source of the contract's metadata.
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface for Contract.
+// It returns the JSON encoding of the contract.
+func (c Contract) MarshalBinary() ([]byte, error) {
+	return json.Marshal(c)
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface for Contract.
+// It parses a JSON-encoded contract and stores the result in the Contract.
+func (c Contract) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, c)
+}
+
+// ContractCreation represents the creation details of a smart contract, including
+// its address, creator's address, and the transaction hash of the creation transaction.
+type ContractCreation struct {
+	Address         string `json:"contractAddress"` // The smart contract address.
+	CreatorAddress  string `json:"contractCreator"` // The address of the creator of the contract.
+	TransactionHash string `json:"txHash"`          // The hash of the transaction that created the contract.
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler to provide binary encoding for a ContractCreation.
+func (c *ContractCreation) MarshalBinary() ([]byte, error) {
+	return json.Marshal(c)
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler to provide binary decoding for a ContractCreation.
+func (c *ContractCreation) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, c)
+}
+
+// GetTransactionHash returns the Ethereum transaction hash of the contract creation as a common.Hash.
+func (c *ContractCreation) GetTransactionHash() common.Hash {
+	return common.HexToHash(c.TransactionHash)
+}
+
+// Transaction represents the transaction of the account as listed by the explorer.
+type Transaction struct {
+	BlockNumber       string `json:"blockNumber"`       // Number of the block of the transaction.
+	TimeStamp         string `json:"timeStamp"`         // Unix time of the block of the transaction.
+	Hash              string `json:"hash"`              // Hash of the transaction.
+	Nonce             string `json:"nonce"`             // Nonce of the sender.
+	BlockHash         string `json:"blockHash"`         // Hash of the block of the transaction.
+	TransactionIndex  string `json:"transactionIndex"`  // Index of the transaction in the block.
+	From              string `json:"from"`              // Sender of the transaction.
+	To                string `json:"to"`                // Recipient of the transaction, empty for the contract creation.
+	Value             string `json:"value"`             // Value transferred in wei.
+	Gas               string `json:"gas"`               // Gas limit of the transaction.
+	GasPrice          string `json:"gasPrice"`          // Gas price of the transaction in wei.
+	IsError           string `json:"isError"`           // "1" when the execution failed.
+	TxReceiptStatus   string `json:"txreceipt_status"`  // Status of the receipt, "1" for success.
+	Input             string `json:"input"`             // Call data of the transaction.
+	ContractAddress   string `json:"contractAddress"`   // Address of the created contract, if any.
+	CumulativeGasUsed string `json:"cumulativeGasUsed"` // Gas used by the block up to and including the transaction.
+	GasUsed           string `json:"gasUsed"`           // Gas used by the transaction.
+	Confirmations     string `json:"confirmations"`     // Number of the confirmations of the transaction.
+	MethodID          string `json:"methodId"`          // Selector of the called method.
+	FunctionName      string `json:"functionName"`      // Signature of the called function, when known to the explorer.
+}
+
+// GetHash returns the hash of the transaction.
+func (t *Transaction) GetHash() common.Hash {
+	return common.HexToHash(t.Hash)
+}
+
+// GetBlockNumber returns the number of the block of the transaction.
+func (t *Transaction) GetBlockNumber() uint64 {
+	number, _ := strconv.ParseUint(t.BlockNumber, 10, 64)
+	return number
+}
+
+// GetValue returns the value transferred by the transaction in wei.
+func (t *Transaction) GetValue() *big.Int {
+	value, ok := new(big.Int).SetString(t.Value, 10)
+	if !ok {
+		return new(big.Int)
+	}
+	return value
+}
+
+// IsFailed reports whether the execution of the transaction failed.
+func (t *Transaction) IsFailed() bool {
+	return t.IsError == "1"
+}
+
+// InternalTransaction represents the internal transaction, that is the message call or creation made by the
+// contract, as listed by the explorer.
+type InternalTransaction struct {
+	BlockNumber     string `json:"blockNumber"`     // Number of the block of the transaction.
+	TimeStamp       string `json:"timeStamp"`       // Unix time of the block of the transaction.
+	Hash            string `json:"hash"`            // Hash of the parent transaction.
+	From            string `json:"from"`            // Caller of the internal transaction.
+	To              string `json:"to"`              // Callee of the internal transaction, empty for the creation.
+	Value           string `json:"value"`           // Value transferred in wei.
+	ContractAddress string `json:"contractAddress"` // Address of the created contract, if any.
+	Input           string `json:"input"`           // Call data of the internal transaction.
+	Type            string `json:"type"`            // Type of the call, such as call, delegatecall or create.
+	Gas             string `json:"gas"`             // Gas provided to the internal transaction.
+	GasUsed         string `json:"gasUsed"`         // Gas used by the internal transaction.
+	TraceID         string `json:"traceId"`         // Position of the internal transaction in the call trace.
+	IsError         string `json:"isError"`         // "1" when the execution failed.
+	ErrCode         string `json:"errCode"`         // Error of the failed execution.
+}
+
+// GetHash returns the hash of the parent transaction.
+func (t *InternalTransaction) GetHash() common.Hash {
+	return common.HexToHash(t.Hash)
+}
+
+// GetBlockNumber returns the number of the block of the transaction.
+func (t *InternalTransaction) GetBlockNumber() uint64 {
+	number, _ := strconv.ParseUint(t.BlockNumber, 10, 64)
+	return number
+}
+
+// GetValue returns the value transferred by the internal transaction in wei.
+func (t *InternalTransaction) GetValue() *big.Int {
+	value, ok := new(big.Int).SetString(t.Value, 10)
+	if !ok {
+		return new(big.Int)
+	}
+	return value
+}
+
+// IsFailed reports whether the execution of the internal transaction failed.
+func (t *InternalTransaction) IsFailed() bool {
+	return t.IsError == "1"
+}
+
+// Log represents the event log as listed by the explorer, with the numbers encoded in hex.
+type Log struct {
+	Address          string   `json:"address"`          // Contract emitting the log.
+	Topics           []string `json:"topics"`           // Topics of the log.
+	Data             string   `json:"data"`             // Data of the log.
+	BlockNumber      string   `json:"blockNumber"`      // Number of the block of the log.
+	BlockHash        string   `json:"blockHash"`        // Hash of the block of the log.
+	TimeStamp        string   `json:"timeStamp"`        // Unix time of the block of the log.
+	LogIndex         string   `json:"logIndex"`         // Index of the log in the block.
+	TransactionHash  string   `json:"transactionHash"`  // Hash of the transaction emitting the log.
+	TransactionIndex string   `json:"transactionIndex"` // Index of the transaction in the block.
+}
+
+// ToLog converts the listed log into the log as returned by the node.
+func (l *Log) ToLog() (*types.Log, error) {
+	data, err := hexutil.Decode(l.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode log data: %w", err)
+	}
+
+	log := &types.Log{
+		Address:   common.HexToAddress(l.Address),
+		Data:      data,
+		BlockHash: common.HexToHash(l.BlockHash),
+		TxHash:    common.HexToHash(l.TransactionHash),
+	}
+
+	for _, topic := range l.Topics {
+		log.Topics = append(log.Topics, common.HexToHash(topic))
+	}
+
+	if log.BlockNumber, err = parseQuantity(l.BlockNumber); err != nil {
+		return nil, fmt.Errorf("failed to decode log block number: %w", err)
+	}
+
+	index, err := parseQuantity(l.LogIndex)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode log index: %w", err)
+	}
+	log.Index = uint(index)
+
+	txIndex, err := parseQuantity(l.TransactionIndex)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode log transaction index: %w", err)
+	}
+	log.TxIndex = uint(txIndex)
+
+	return log, nil
+}
+
+// parseQuantity parses the hex quantity of the explorer, which may have the leading zeros, the empty one as zero.
+func parseQuantity(s string) (uint64, error) {
+	if s == "" || s == "0x" {
+		return 0, nil
+	}
+	return strconv.ParseUint(s, 0, 64)
+}
@@ -0,0 +1,6 @@
+// Package sourcify provides a client for the verified sources of the Sourcify repository, read either from
+// the local mirror of the repository directory or from the Sourcify HTTP API. Matches are turned into
+// solgo.Sources through their metadata, making Sourcify the self-hostable source of truth next to the explorers.
+// Contracts fall back to it when the explorer has no verified sources, recording the match type as the source
+// provider.
+package sourcify
@@ -0,0 +1,8 @@
+package sourcify
+
+import "errors"
+
+var (
+	ErrNotFound       = errors.New("contract not found in sourcify repository")
+	ErrSourceMismatch = errors.New("source content does not match its keccak256 hash")
+)
@@ -0,0 +1,34 @@
+package sourcify
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// getLocalMatch reads the match from the local repository mirror, laid out as
+// <path>/<match type>/<chain id>/<address>/metadata.json with the sources in the sources directory next to it.
+func (p *Provider) getLocalMatch(chainID uint64, addr common.Address) (*Match, error) {
+	for _, matchType := range []MatchType{MatchFull, MatchPartial} {
+		dir := filepath.Join(p.opts.Path, string(matchType), strconv.FormatUint(chainID, 10), addr.Hex())
+
+		raw, err := os.ReadFile(filepath.Join(dir, "metadata.json"))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to read %s metadata: %w", ProviderName, err)
+		}
+
+		return newMatch(matchType, chainID, addr, raw, func(path string) ([]byte, error) {
+			// Rooting the path keeps the relative source paths within the sources directory.
+			return os.ReadFile(filepath.Join(dir, "sources", filepath.Clean(string(filepath.Separator)+filepath.FromSlash(path))))
+		})
+	}
+
+	return nil, fmt.Errorf("%w: %s on chain %d", ErrNotFound, addr.Hex(), chainID)
+}
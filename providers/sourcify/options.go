@@ -0,0 +1,47 @@
+package sourcify
+
+import (
+	"errors"
+	"time"
+)
+
+// DefaultEndpoint is the endpoint of the public Sourcify HTTP API.
+const DefaultEndpoint = "https://sourcify.dev/server"
+
+// DefaultTimeout is the time the request to the Sourcify HTTP API may take when the timeout is not set.
+const DefaultTimeout = 15 * time.Second
+
+// Options holds the configuration settings for the Sourcify provider. Local repository is used when its path
+// is set, the HTTP API otherwise.
+type Options struct {
+	// Path is the directory of the local repository mirror, the one containing the full_match and
+	// partial_match directories.
+	Path string `json:"path" yaml:"path" mapstructure:"path"`
+
+	// Endpoint is the base URL of the Sourcify HTTP API, such as https://sourcify.dev/server.
+	Endpoint string `json:"endpoint" yaml:"endpoint" mapstructure:"endpoint"`
+
+	// Timeout is the time the single request to the HTTP API may take.
+	Timeout time.Duration `json:"timeout" yaml:"timeout" mapstructure:"timeout"`
+}
+
+// GetTimeout returns the time the single request to the HTTP API may take.
+func (o *Options) GetTimeout() time.Duration {
+	if o.Timeout <= 0 {
+		return DefaultTimeout
+	}
+	return o.Timeout
+}
+
+// Validate checks the integrity and completeness of the Options settings.
+func (o *Options) Validate() error {
+	if o == nil {
+		return errors.New("sourcify provider is not configured")
+	}
+
+	if o.Path == "" && o.Endpoint == "" {
+		return errors.New("either path or endpoint is required but none is set")
+	}
+
+	return nil
+}
@@ -0,0 +1,186 @@
+package sourcify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/goccy/go-json"
+	"github.com/unpackdev/solgo"
+	"github.com/unpackdev/solgo/cache"
+	"github.com/unpackdev/solgo/metadata"
+)
+
+// ProviderName is the name of the Sourcify provider.
+const ProviderName = "sourcify"
+
+// MatchType is the kind of the verification of the contract by Sourcify.
+type MatchType string
+
+const (
+	// MatchFull is the match of both the bytecode and the metadata, so the sources are the exact ones.
+	MatchFull MatchType = "full_match"
+
+	// MatchPartial is the match of the bytecode only, so the sources may differ in the comments or names.
+	MatchPartial MatchType = "partial_match"
+)
+
+// Match is the contract verified by Sourcify, with the metadata of its compilation and the content of its sources.
+type Match struct {
+	Type     MatchType                  `json:"type"`
+	ChainID  uint64                     `json:"chain_id"`
+	Address  common.Address             `json:"address"`
+	Metadata *metadata.ContractMetadata `json:"metadata"`
+}
+
+// IsFull reports whether the sources are the exact ones the contract was compiled from.
+func (m *Match) IsFull() bool {
+	return m.Type == MatchFull
+}
+
+// GetSourceProvider returns the name of the source provider including the match type, such as
+// sourcify:full_match.
+func (m *Match) GetSourceProvider() string {
+	return fmt.Sprintf("%s:%s", ProviderName, m.Type)
+}
+
+// GetName returns the name of the contract the metadata was compiled for.
+func (m *Match) GetName() string {
+	for _, name := range m.Metadata.Settings.CompilationTarget {
+		return name
+	}
+	return ""
+}
+
+// GetLicense returns the license of the source of the contract the metadata was compiled for.
+func (m *Match) GetLicense() string {
+	for path := range m.Metadata.Settings.CompilationTarget {
+		return m.Metadata.Sources[path].License
+	}
+	return ""
+}
+
+// GetSources returns the sources of the match along with the compiler settings of the metadata.
+func (m *Match) GetSources() *solgo.Sources {
+	return solgo.NewSourcesFromMetadata(m.Metadata)
+}
+
+// Provider retrieves the verified contracts from the local Sourcify repository mirror or the Sourcify HTTP API.
+type Provider struct {
+	ctx   context.Context
+	opts  *Options
+	http  *http.Client
+	cache *cache.Namespace
+}
+
+// NewProvider creates the Sourcify provider, caching the matches in the given cache, or not at all when the
+// cache is nil. It returns an error if the provided options are invalid.
+func NewProvider(ctx context.Context, store cache.Cache, opts *Options) (*Provider, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &Provider{
+		ctx:  ctx,
+		opts: opts,
+		http: &http.Client{
+			Timeout: opts.GetTimeout(),
+			Transport: &http.Transport{
+				DialContext: (&net.Dialer{
+					Timeout:   5 * time.Second,
+					KeepAlive: 30 * time.Second,
+				}).DialContext,
+				TLSHandshakeTimeout: 5 * time.Second,
+				MaxIdleConnsPerHost: 10,
+				IdleConnTimeout:     90 * time.Second,
+			},
+		},
+		cache: cache.NewNamespace(store, ProviderName, nil),
+	}, nil
+}
+
+// ProviderName returns the name of the provider.
+func (p *Provider) ProviderName() string {
+	return ProviderName
+}
+
+// GetOptions returns the options of the provider.
+func (p *Provider) GetOptions() *Options {
+	return p.opts
+}
+
+// GetMatch returns the verified contract of the chain, preferring the full match over the partial one.
+// It returns ErrNotFound when the contract is not verified by Sourcify. Full matches never change, so they
+// are cached as the finalized data, while the partial ones may still be verified as full.
+func (p *Provider) GetMatch(ctx context.Context, chainID uint64, addr common.Address) (*Match, error) {
+	cacheKey := cache.Key(strconv.FormatUint(chainID, 10), addr.Hex())
+
+	var cached *Match
+	if err := p.cache.GetJSON(ctx, cacheKey, &cached); err == nil {
+		return cached, nil
+	} else if !errors.Is(err, cache.ErrNotFound) {
+		return nil, fmt.Errorf("failed to unmarshal %s match: %s", ProviderName, err)
+	}
+
+	var match *Match
+	var err error
+	if p.opts.Path != "" {
+		match, err = p.getLocalMatch(chainID, addr)
+	} else {
+		match, err = p.getRemoteMatch(ctx, chainID, addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	finality := cache.Mutable
+	if match.IsFull() {
+		finality = cache.Finalized
+	}
+
+	if err := p.cache.SetJSON(ctx, cacheKey, match, finality); err != nil {
+		return nil, fmt.Errorf("failed to write to cache: %s", err)
+	}
+
+	return match, nil
+}
+
+// newMatch decodes the metadata of the match and fills in the content of its sources, read with the source
+// function by their path when the metadata does not embed them. Contents are checked against their hashes.
+func newMatch(matchType MatchType, chainID uint64, addr common.Address, raw []byte, source func(path string) ([]byte, error)) (*Match, error) {
+	var md metadata.ContractMetadata
+	if err := json.Unmarshal(raw, &md); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s metadata: %w", ProviderName, err)
+	}
+	md.Raw = string(raw)
+
+	for path, src := range md.Sources {
+		if src.Content == "" {
+			content, err := source(path)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read source %s: %w", path, err)
+			}
+			src.Content = string(content)
+		}
+
+		if src.Keccak256 != "" && !strings.EqualFold(crypto.Keccak256Hash([]byte(src.Content)).Hex(), src.Keccak256) {
+			return nil, fmt.Errorf("%w: %s", ErrSourceMismatch, path)
+		}
+
+		md.Sources[path] = src
+	}
+
+	return &Match{
+		Type:     matchType,
+		ChainID:  chainID,
+		Address:  addr,
+		Metadata: &md,
+	}, nil
+}
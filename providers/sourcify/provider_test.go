@@ -0,0 +1,183 @@
+package sourcify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unpackdev/solgo/cache"
+)
+
+const (
+	tokenSource = "// SPDX-License-Identifier: MIT\npragma solidity ^0.8.0;\n\nimport \"./Base.sol\";\n\ncontract Token is Base {}\n"
+	baseSource  = "// SPDX-License-Identifier: MIT\npragma solidity ^0.8.0;\n\ncontract Base {}\n"
+)
+
+// testMetadata returns the metadata of the Token contract referencing its sources by their hashes.
+func testMetadata(t *testing.T, baseHash string) []byte {
+	md := map[string]interface{}{
+		"version":  1,
+		"language": "Solidity",
+		"compiler": map[string]string{"version": "0.8.19+commit.7dd6d404"},
+		"settings": map[string]interface{}{
+			"compilationTarget": map[string]string{"contracts/Token.sol": "Token"},
+			"evmVersion":        "paris",
+			"optimizer":         map[string]interface{}{"enabled": true, "runs": 200},
+		},
+		"output": map[string]interface{}{"abi": []interface{}{}},
+		"sources": map[string]interface{}{
+			"contracts/Token.sol": map[string]string{"keccak256": crypto.Keccak256Hash([]byte(tokenSource)).Hex(), "license": "MIT"},
+			"contracts/Base.sol":  map[string]string{"keccak256": baseHash, "license": "MIT"},
+		},
+	}
+
+	raw, err := json.Marshal(md)
+	require.NoError(t, err)
+	return raw
+}
+
+// writeRepository lays out the match of the contract in the local repository mirror.
+func writeRepository(t *testing.T, root string, matchType MatchType, chainID uint64, addr common.Address, raw []byte) {
+	dir := filepath.Join(root, string(matchType), fmt.Sprint(chainID), addr.Hex())
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "sources", "contracts"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "metadata.json"), raw, 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "sources", "contracts", "Token.sol"), []byte(tokenSource), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "sources", "contracts", "Base.sol"), []byte(baseSource), 0644))
+}
+
+func TestProviderLocal(t *testing.T) {
+	full := common.HexToAddress("0x01")
+	partial := common.HexToAddress("0x02")
+	tampered := common.HexToAddress("0x03")
+	baseHash := crypto.Keccak256Hash([]byte(baseSource)).Hex()
+
+	root := t.TempDir()
+	writeRepository(t, root, MatchFull, 1, full, testMetadata(t, baseHash))
+	writeRepository(t, root, MatchPartial, 1, partial, testMetadata(t, baseHash))
+	writeRepository(t, root, MatchFull, 1, tampered, testMetadata(t, common.Hash{}.Hex()))
+
+	testCases := []struct {
+		name         string
+		addr         common.Address
+		chainID      uint64
+		wantType     MatchType
+		wantProvider string
+		wantErr      error
+	}{
+		{
+			name:         "Full Match",
+			addr:         full,
+			chainID:      1,
+			wantType:     MatchFull,
+			wantProvider: "sourcify:full_match",
+		},
+		{
+			name:         "Partial Match",
+			addr:         partial,
+			chainID:      1,
+			wantType:     MatchPartial,
+			wantProvider: "sourcify:partial_match",
+		},
+		{
+			name:    "Source Hash Mismatch",
+			addr:    tampered,
+			chainID: 1,
+			wantErr: ErrSourceMismatch,
+		},
+		{
+			name:    "Other Chain",
+			addr:    full,
+			chainID: 56,
+			wantErr: ErrNotFound,
+		},
+	}
+
+	provider, err := NewProvider(context.Background(), nil, &Options{Path: root})
+	require.NoError(t, err)
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			match, err := provider.GetMatch(context.Background(), testCase.chainID, testCase.addr)
+			if testCase.wantErr != nil {
+				assert.ErrorIs(t, err, testCase.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, testCase.wantType, match.Type)
+			assert.Equal(t, testCase.wantProvider, match.GetSourceProvider())
+			assert.Equal(t, "Token", match.GetName())
+			assert.Equal(t, "MIT", match.GetLicense())
+			assert.NotEmpty(t, match.Metadata.Raw)
+
+			sources := match.GetSources()
+			assert.Equal(t, "Token", sources.EntrySourceUnitName)
+			require.Len(t, sources.GetUnits(), 2)
+			assert.Equal(t, tokenSource, sources.GetSourceUnitByPath("contracts/Token.sol").GetContent())
+			assert.Equal(t, baseSource, sources.GetSourceUnitByPath("contracts/Base.sol").GetContent())
+			require.NotNil(t, sources.Settings)
+			assert.Equal(t, "paris", sources.Settings.EvmVersion)
+			assert.True(t, sources.Settings.Optimizer.Enabled)
+			assert.Equal(t, 200, sources.Settings.Optimizer.Runs)
+		})
+	}
+}
+
+func TestProviderRemote(t *testing.T) {
+	addr := common.HexToAddress("0x1f9840a85d5aF5bf1D1762F925BDADdC4201F984")
+	prefix := "/home/data/repository/contracts/full_match/1/" + addr.Hex()
+	raw := testMetadata(t, crypto.Keccak256Hash([]byte(baseSource)).Hex())
+
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/files/any/1/"+addr.Hex() {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error":"Files have not been found!"}`)
+			return
+		}
+
+		response, err := json.Marshal(filesResponse{
+			Status: "full",
+			Files: []file{
+				{Name: "metadata.json", Path: prefix + "/metadata.json", Content: string(raw)},
+				{Name: "Token.sol", Path: prefix + "/sources/contracts/Token.sol", Content: tokenSource},
+				{Name: "Base.sol", Path: prefix + "/sources/contracts/Base.sol", Content: baseSource},
+			},
+		})
+		require.NoError(t, err)
+		w.Write(response)
+	}))
+	defer srv.Close()
+
+	provider, err := NewProvider(context.Background(), cache.NewLRUCache(10), &Options{Endpoint: srv.URL})
+	require.NoError(t, err)
+
+	match, err := provider.GetMatch(context.Background(), 1, addr)
+	require.NoError(t, err)
+	assert.True(t, match.IsFull())
+	assert.Equal(t, tokenSource, match.Metadata.Sources["contracts/Token.sol"].Content)
+	assert.Equal(t, baseSource, match.Metadata.Sources["contracts/Base.sol"].Content)
+
+	// Full matches are served from the cache.
+	cached, err := provider.GetMatch(context.Background(), 1, addr)
+	require.NoError(t, err)
+	assert.Equal(t, match.GetSourceProvider(), cached.GetSourceProvider())
+	assert.Equal(t, tokenSource, cached.Metadata.Sources["contracts/Token.sol"].Content)
+	assert.Equal(t, 1, requests)
+
+	_, err = provider.GetMatch(context.Background(), 56, addr)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, err = NewProvider(context.Background(), nil, &Options{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,85 @@
+package sourcify
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/goccy/go-json"
+)
+
+// file is the file of the match as returned by the Sourcify HTTP API.
+type file struct {
+	Name    string `json:"name"`    // Base name of the file.
+	Path    string `json:"path"`    // Path of the file in the repository.
+	Content string `json:"content"` // Content of the file.
+}
+
+// filesResponse is the response of the files endpoint of the Sourcify HTTP API.
+type filesResponse struct {
+	Status string `json:"status"` // Match type, full or partial.
+	Files  []file `json:"files"`  // Metadata and the sources of the match.
+}
+
+// getRemoteMatch retrieves the full or partial match of the contract from the Sourcify HTTP API.
+func (p *Provider) getRemoteMatch(ctx context.Context, chainID uint64, addr common.Address) (*Match, error) {
+	endpoint := fmt.Sprintf("%s/files/any/%d/%s", strings.TrimSuffix(p.opts.Endpoint, "/"), chainID, addr.Hex())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := p.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send HTTP request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: %s on chain %d", ErrNotFound, addr.Hex(), chainID)
+	} else if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected %s response status: %s", ProviderName, resp.Status)
+	}
+
+	var response filesResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s response: %w", ProviderName, err)
+	}
+
+	matchType := MatchPartial
+	if response.Status == "full" {
+		matchType = MatchFull
+	}
+
+	// Sources are stored under the sources directory of the contract, by the paths of the metadata.
+	var raw []byte
+	sources := make(map[string]string)
+	for _, f := range response.Files {
+		if idx := strings.Index(f.Path, addr.Hex()+"/sources/"); idx >= 0 {
+			sources[f.Path[idx+len(addr.Hex()+"/sources/"):]] = f.Content
+		} else if f.Name == "metadata.json" {
+			raw = []byte(f.Content)
+		}
+	}
+
+	if raw == nil {
+		return nil, fmt.Errorf("%s metadata of %s not found", ProviderName, addr.Hex())
+	}
+
+	return newMatch(matchType, chainID, addr, raw, func(path string) ([]byte, error) {
+		content, ok := sources[strings.TrimPrefix(path, "/")]
+		if !ok {
+			return nil, fmt.Errorf("source not found")
+		}
+		return []byte(content), nil
+	})
+}
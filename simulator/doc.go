@@ -0,0 +1,12 @@
+// Package simulator provides an in-process EVM for simulating transactions against chain state.
+//
+// The state is either forked from a live network, lazily reading accounts, code and storage
+// through an Ethereum client (clients.Client satisfies the Backend interface), or kept locally
+// in memory as a stand-in for tests and offline analysis. Transactions executed by the Simulator
+// never leave the process and can be reverted through snapshots.
+//
+// On top of the EVM, the package simulates buying and selling ERC-20 tokens through Uniswap V2
+// compatible routers. The trade simulation measures the effective buy and sell taxes and detects
+// blocked sells, maximum transaction and wallet limits, as well as wallets blacklisted upon
+// buying, which are commonly used by honeypot tokens.
+package simulator
@@ -0,0 +1,21 @@
+package simulator
+
+import "errors"
+
+var (
+	// ErrInvalidBackend is returned when the state cannot be forked because the backend is not provided.
+	ErrInvalidBackend = errors.New("invalid simulation backend")
+
+	// ErrInvalidState is returned when the simulator is created without the state.
+	ErrInvalidState = errors.New("invalid simulation state")
+
+	// ErrUnsupportedOperation is returned by the forked state for the trie operations it cannot provide,
+	// such as iterating over the nodes or generating proofs.
+	ErrUnsupportedOperation = errors.New("operation not supported by the forked state")
+
+	// ErrInvalidTradeOptions is returned when the trade simulation options are incomplete.
+	ErrInvalidTradeOptions = errors.New("invalid trade options")
+
+	// ErrCallFailed is returned when the read-only contract call required by the simulation reverts.
+	ErrCallFailed = errors.New("contract call failed")
+)
@@ -0,0 +1,244 @@
+package simulator
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+)
+
+// DefaultGasLimit is the gas limit of the simulated block and transactions.
+const DefaultGasLimit = 30_000_000
+
+// Result represents the outcome of the transaction executed by the Simulator.
+type Result struct {
+	ReturnData []byte       `json:"return_data"` // Data returned by the call, or the revert data.
+	GasUsed    uint64       `json:"gas_used"`    // Gas used by the transaction.
+	Reverted   bool         `json:"reverted"`    // Whether the transaction failed.
+	Reason     string       `json:"reason"`      // Revert reason or the EVM error of the failed transaction.
+	Logs       []*types.Log `json:"logs"`        // Logs emitted by the transaction.
+}
+
+// Simulator executes transactions in the in-process EVM on top of the forked or local state.
+// Transactions are executed with zero gas price, so the balances change only by the transferred value.
+type Simulator struct {
+	state     *state.StateDB
+	header    *types.Header
+	config    *params.ChainConfig
+	vmConfig  vm.Config
+	txIndex   int
+	snapshots []*state.StateDB
+}
+
+// NewSimulator creates a new Simulator executing transactions on top of the state in the block described
+// by the header, following the mainnet rules. When the header is nil, the simulation runs in the block
+// following the genesis at the current time, with all the forks enabled from the genesis.
+func NewSimulator(statedb *state.StateDB, header *types.Header) (*Simulator, error) {
+	if statedb == nil {
+		return nil, ErrInvalidState
+	}
+
+	config := params.MainnetChainConfig
+	if header == nil {
+		config = params.AllDevChainProtocolChanges
+		header = &types.Header{
+			Number:     big.NewInt(1),
+			Time:       uint64(time.Now().Unix()),
+			GasLimit:   DefaultGasLimit,
+			Difficulty: big.NewInt(0),
+			BaseFee:    big.NewInt(0),
+		}
+	}
+
+	return &Simulator{
+		state:    statedb,
+		header:   header,
+		config:   config,
+		vmConfig: vm.Config{NoBaseFee: true},
+	}, nil
+}
+
+// NewForkedSimulator creates a new Simulator on top of the state forked from the backend at the given block
+// number, or at the latest block when the block number is nil.
+func NewForkedSimulator(ctx context.Context, backend Backend, blockNumber *big.Int) (*Simulator, error) {
+	if backend == nil {
+		return nil, ErrInvalidBackend
+	}
+
+	header, err := backend.HeaderByNumber(ctx, blockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch block header: %w", err)
+	}
+
+	statedb, err := NewForkedState(ctx, backend, header.Number)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fork state: %w", err)
+	}
+
+	return NewSimulator(statedb, header)
+}
+
+// NewLocalSimulator creates a new Simulator on top of the empty in-memory state.
+func NewLocalSimulator() (*Simulator, error) {
+	statedb, err := NewLocalState()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create local state: %w", err)
+	}
+
+	return NewSimulator(statedb, nil)
+}
+
+// GetState returns the state the transactions are executed on.
+func (s *Simulator) GetState() *state.StateDB {
+	return s.state
+}
+
+// GetHeader returns the header of the block the transactions are executed in.
+func (s *Simulator) GetHeader() *types.Header {
+	return s.header
+}
+
+// GetChainConfig returns the chain configuration determining the enabled forks.
+func (s *Simulator) GetChainConfig() *params.ChainConfig {
+	return s.config
+}
+
+// SetChainConfig sets the chain configuration determining the enabled forks.
+func (s *Simulator) SetChainConfig(config *params.ChainConfig) {
+	s.config = config
+}
+
+// Snapshot returns the identifier of the current state revision. Revisions span multiple transactions,
+// so unlike the state journal they are kept as the copies of the state.
+func (s *Simulator) Snapshot() int {
+	s.snapshots = append(s.snapshots, s.state.Copy())
+	return len(s.snapshots) - 1
+}
+
+// RevertToSnapshot reverts all the state changes made since the given revision, discarding the revision
+// together with the ones taken after it. The state returned by GetState is replaced by the reverted one.
+func (s *Simulator) RevertToSnapshot(revision int) {
+	if revision < 0 || revision >= len(s.snapshots) {
+		panic(fmt.Errorf("revision id %v cannot be reverted", revision))
+	}
+
+	s.state = s.snapshots[revision]
+	s.snapshots = s.snapshots[:revision]
+}
+
+// GetBalance returns the native currency balance of the account.
+func (s *Simulator) GetBalance(address common.Address) *big.Int {
+	return s.state.GetBalance(address).ToBig()
+}
+
+// SetBalance sets the native currency balance of the account.
+func (s *Simulator) SetBalance(address common.Address, balance *big.Int) {
+	s.state.SetBalance(address, uint256.MustFromBig(balance))
+}
+
+// SetCode sets the code of the account.
+func (s *Simulator) SetCode(address common.Address, code []byte) {
+	s.state.SetCode(address, code)
+}
+
+// Call executes the transaction sending the value and data from the account to the contract. The failed
+// transaction is not considered an error and is reported through the result, while errors are returned
+// when the transaction cannot be executed at all or the forked state cannot be read from the backend.
+func (s *Simulator) Call(ctx context.Context, from common.Address, to common.Address, value *big.Int, data []byte) (*Result, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if value == nil {
+		value = big.NewInt(0)
+	}
+
+	msg := &core.Message{
+		From:              from,
+		To:                &to,
+		Value:             value,
+		GasLimit:          s.header.GasLimit,
+		GasPrice:          big.NewInt(0),
+		GasFeeCap:         big.NewInt(0),
+		GasTipCap:         big.NewInt(0),
+		Data:              data,
+		SkipAccountChecks: true,
+	}
+
+	s.txIndex++
+	txHash := crypto.Keccak256Hash(from.Bytes(), to.Bytes(), big.NewInt(int64(s.txIndex)).Bytes())
+	s.state.SetTxContext(txHash, s.txIndex)
+
+	evm := vm.NewEVM(s.blockContext(), core.NewEVMTxContext(msg), s.state, s.config, s.vmConfig)
+	result, err := core.ApplyMessage(evm, msg, new(core.GasPool).AddGas(math.MaxUint64))
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply message: %w", err)
+	}
+
+	if err := s.state.Error(); err != nil {
+		return nil, fmt.Errorf("failed to read state: %w", err)
+	}
+
+	toReturn := &Result{
+		ReturnData: result.ReturnData,
+		GasUsed:    result.UsedGas,
+		Reverted:   result.Failed(),
+		Logs:       s.state.GetLogs(txHash, s.header.Number.Uint64(), common.Hash{}),
+	}
+
+	// Every call is a transaction of its own, finalised the same way the block transactions are.
+	s.state.Finalise(true)
+
+	if result.Failed() {
+		toReturn.Reason = result.Err.Error()
+		if reason, err := abi.UnpackRevert(result.Revert()); err == nil {
+			toReturn.Reason = reason
+		}
+	}
+
+	return toReturn, nil
+}
+
+// blockContext returns the context of the block the transactions are executed in.
+func (s *Simulator) blockContext() vm.BlockContext {
+	toReturn := vm.BlockContext{
+		CanTransfer: core.CanTransfer,
+		Transfer:    core.Transfer,
+		GetHash: func(n uint64) common.Hash {
+			return crypto.Keccak256Hash(new(big.Int).SetUint64(n).Bytes())
+		},
+		Coinbase:    s.header.Coinbase,
+		GasLimit:    s.header.GasLimit,
+		BlockNumber: new(big.Int).Set(s.header.Number),
+		Time:        s.header.Time,
+		Difficulty:  new(big.Int),
+		BaseFee:     new(big.Int),
+		BlobBaseFee: new(big.Int),
+	}
+
+	if s.header.Difficulty != nil {
+		toReturn.Difficulty.Set(s.header.Difficulty)
+	}
+
+	if s.header.BaseFee != nil {
+		toReturn.BaseFee.Set(s.header.BaseFee)
+	}
+
+	if toReturn.Difficulty.Sign() == 0 {
+		random := s.header.MixDigest
+		toReturn.Random = &random
+	}
+
+	return toReturn
+}
@@ -0,0 +1,301 @@
+package simulator
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/ethereum/go-ethereum/trie/trienode"
+	"github.com/ethereum/go-ethereum/triedb"
+	"github.com/holiman/uint256"
+)
+
+// forkedStorageRoot is the storage root assigned to the accounts read from the backend. It makes the state
+// open the storage trie of such accounts, which in turn reads the storage slots from the backend.
+var forkedStorageRoot = crypto.Keccak256Hash([]byte("solgo.simulator.forked.storage"))
+
+// Backend provides the chain state the simulation is forked from. It is satisfied by clients.Client,
+// as well as by the go-ethereum ethclient.Client.
+type Backend interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error)
+	NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error)
+	CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error)
+	StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) ([]byte, error)
+}
+
+// NewForkedState creates the state forked from the backend at the given block number, or at the latest
+// block when the block number is nil. Accounts, code and storage slots are read from the backend the first
+// time the simulation touches them, while all the writes are kept in memory.
+func NewForkedState(ctx context.Context, backend Backend, blockNumber *big.Int) (*state.StateDB, error) {
+	if backend == nil {
+		return nil, ErrInvalidBackend
+	}
+
+	return state.New(types.EmptyRootHash, newForkedDatabase(ctx, backend, blockNumber), nil)
+}
+
+// NewLocalState creates the empty in-memory state, used as the stand-in for the chain state when the
+// simulation does not need to be forked from the network.
+func NewLocalState() (*state.StateDB, error) {
+	return state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+}
+
+// forkedDatabase implements the state.Database reading the accounts and storage from the backend.
+type forkedDatabase struct {
+	ctx         context.Context
+	backend     Backend
+	blockNumber *big.Int
+	disk        ethdb.Database
+	triedb      *triedb.Database
+	mu          sync.RWMutex
+	accounts    map[common.Address]*types.StateAccount
+	storage     map[common.Address]map[common.Hash][]byte
+	code        map[common.Hash][]byte
+}
+
+// newForkedDatabase creates the database forked from the backend at the given block number.
+func newForkedDatabase(ctx context.Context, backend Backend, blockNumber *big.Int) *forkedDatabase {
+	disk := rawdb.NewMemoryDatabase()
+	return &forkedDatabase{
+		ctx:         ctx,
+		backend:     backend,
+		blockNumber: blockNumber,
+		disk:        disk,
+		triedb:      triedb.NewDatabase(disk, nil),
+		accounts:    make(map[common.Address]*types.StateAccount),
+		storage:     make(map[common.Address]map[common.Hash][]byte),
+		code:        make(map[common.Hash][]byte),
+	}
+}
+
+// OpenTrie opens the account trie reading the accounts from the backend.
+func (db *forkedDatabase) OpenTrie(root common.Hash) (state.Trie, error) {
+	return &forkedTrie{db: db, root: root}, nil
+}
+
+// OpenStorageTrie opens the storage trie of the account. Storage of the accounts read from the backend is
+// read from the backend as well, while the accounts created during the simulation start with empty storage.
+func (db *forkedDatabase) OpenStorageTrie(stateRoot common.Hash, address common.Address, root common.Hash, _ state.Trie) (state.Trie, error) {
+	return &forkedTrie{db: db, root: root, address: &address, forked: root == forkedStorageRoot}, nil
+}
+
+// CopyTrie returns an independent copy of the trie. Forked tries do not hold any state of their own.
+func (db *forkedDatabase) CopyTrie(t state.Trie) state.Trie {
+	if forked, ok := t.(*forkedTrie); ok {
+		toReturn := *forked
+		return &toReturn
+	}
+	panic(fmt.Errorf("unknown trie type %T", t))
+}
+
+// ContractCode returns the code of the contract, reading it from the backend if it was not seen before.
+func (db *forkedDatabase) ContractCode(address common.Address, codeHash common.Hash) ([]byte, error) {
+	db.mu.RLock()
+	code, ok := db.code[codeHash]
+	db.mu.RUnlock()
+	if ok {
+		return code, nil
+	}
+
+	code, err := db.backend.CodeAt(db.ctx, address, db.blockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch code of %s: %w", address.Hex(), err)
+	}
+
+	db.storeCode(code)
+	return code, nil
+}
+
+// ContractCodeSize returns the size of the contract code.
+func (db *forkedDatabase) ContractCodeSize(address common.Address, codeHash common.Hash) (int, error) {
+	code, err := db.ContractCode(address, codeHash)
+	return len(code), err
+}
+
+// DiskDB returns the in-memory key-value database backing the forked state.
+func (db *forkedDatabase) DiskDB() ethdb.KeyValueStore {
+	return db.disk
+}
+
+// TrieDB returns the in-memory trie database backing the forked state.
+func (db *forkedDatabase) TrieDB() *triedb.Database {
+	return db.triedb
+}
+
+// storeCode caches the contract code by its hash and returns the hash.
+func (db *forkedDatabase) storeCode(code []byte) common.Hash {
+	codeHash := crypto.Keccak256Hash(code)
+
+	db.mu.Lock()
+	db.code[codeHash] = code
+	db.mu.Unlock()
+
+	return codeHash
+}
+
+// forkedTrie implements the state.Trie reading the accounts, or the storage of the single account, from
+// the backend. The state keeps the writes in its own objects, so updates are accepted without being stored.
+type forkedTrie struct {
+	db      *forkedDatabase
+	root    common.Hash
+	address *common.Address // Owner of the storage trie, nil for the account trie.
+	forked  bool            // Whether the storage slots are read from the backend.
+}
+
+// GetKey returns nil as the forked trie does not keep the preimages.
+func (t *forkedTrie) GetKey([]byte) []byte {
+	return nil
+}
+
+// GetAccount reads the balance, nonce and code of the account from the backend. Accounts without any
+// of them do not exist and nil is returned. Accounts are read only once, as the state discarded by the
+// simulator revisions reads them again.
+func (t *forkedTrie) GetAccount(address common.Address) (*types.StateAccount, error) {
+	t.db.mu.RLock()
+	account, ok := t.db.accounts[address]
+	t.db.mu.RUnlock()
+	if ok {
+		return copyAccount(account), nil
+	}
+
+	account, err := t.fetchAccount(address)
+	if err != nil {
+		return nil, err
+	}
+
+	t.db.mu.Lock()
+	t.db.accounts[address] = account
+	t.db.mu.Unlock()
+
+	return copyAccount(account), nil
+}
+
+// fetchAccount reads the account from the backend.
+func (t *forkedTrie) fetchAccount(address common.Address) (*types.StateAccount, error) {
+	ctx, backend, blockNumber := t.db.ctx, t.db.backend, t.db.blockNumber
+
+	balance, err := backend.BalanceAt(ctx, address, blockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch balance of %s: %w", address.Hex(), err)
+	}
+
+	nonce, err := backend.NonceAt(ctx, address, blockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch nonce of %s: %w", address.Hex(), err)
+	}
+
+	code, err := backend.CodeAt(ctx, address, blockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch code of %s: %w", address.Hex(), err)
+	}
+
+	if balance.Sign() == 0 && nonce == 0 && len(code) == 0 {
+		return nil, nil
+	}
+
+	codeHash := types.EmptyCodeHash
+	if len(code) > 0 {
+		codeHash = t.db.storeCode(code)
+	}
+
+	return &types.StateAccount{
+		Nonce:    nonce,
+		Balance:  uint256.MustFromBig(balance),
+		Root:     forkedStorageRoot,
+		CodeHash: codeHash.Bytes(),
+	}, nil
+}
+
+// GetStorage reads the storage slot of the account from the backend.
+func (t *forkedTrie) GetStorage(address common.Address, key []byte) ([]byte, error) {
+	if !t.forked {
+		return nil, nil
+	}
+
+	slot := common.BytesToHash(key)
+
+	t.db.mu.RLock()
+	value, ok := t.db.storage[address][slot]
+	t.db.mu.RUnlock()
+	if ok {
+		return value, nil
+	}
+
+	value, err := t.db.backend.StorageAt(t.db.ctx, address, slot, t.db.blockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch storage slot %x of %s: %w", key, address.Hex(), err)
+	}
+	value = common.TrimLeftZeroes(value)
+
+	t.db.mu.Lock()
+	if _, ok := t.db.storage[address]; !ok {
+		t.db.storage[address] = make(map[common.Hash][]byte)
+	}
+	t.db.storage[address][slot] = value
+	t.db.mu.Unlock()
+
+	return value, nil
+}
+
+// UpdateAccount accepts the account update, which is kept by the state.
+func (t *forkedTrie) UpdateAccount(address common.Address, account *types.StateAccount) error {
+	return nil
+}
+
+// UpdateStorage accepts the storage update, which is kept by the state.
+func (t *forkedTrie) UpdateStorage(address common.Address, key, value []byte) error {
+	return nil
+}
+
+// DeleteAccount accepts the account deletion, which is kept by the state.
+func (t *forkedTrie) DeleteAccount(address common.Address) error {
+	return nil
+}
+
+// DeleteStorage accepts the storage deletion, which is kept by the state.
+func (t *forkedTrie) DeleteStorage(address common.Address, key []byte) error {
+	return nil
+}
+
+// UpdateContractCode caches the code of the contract deployed during the simulation.
+func (t *forkedTrie) UpdateContractCode(address common.Address, codeHash common.Hash, code []byte) error {
+	t.db.storeCode(code)
+	return nil
+}
+
+// Hash returns the root the trie was opened with, as the forked state is never hashed.
+func (t *forkedTrie) Hash() common.Hash {
+	return t.root
+}
+
+// Commit returns the root the trie was opened with without collecting any nodes.
+func (t *forkedTrie) Commit(collectLeaf bool) (common.Hash, *trienode.NodeSet, error) {
+	return t.root, nil, nil
+}
+
+// NodeIterator is not supported by the forked trie.
+func (t *forkedTrie) NodeIterator(startKey []byte) (trie.NodeIterator, error) {
+	return nil, ErrUnsupportedOperation
+}
+
+// Prove is not supported by the forked trie.
+func (t *forkedTrie) Prove(key []byte, proofDb ethdb.KeyValueWriter) error {
+	return ErrUnsupportedOperation
+}
+
+// copyAccount returns the copy of the account the state is free to modify, or nil for missing accounts.
+func copyAccount(account *types.StateAccount) *types.StateAccount {
+	if account == nil {
+		return nil
+	}
+	return account.Copy()
+}
@@ -0,0 +1,189 @@
+package simulator
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/asm"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// storageReaderCode returns the value of the storage slot zero.
+const storageReaderCode = `
+	PUSH 0
+	SLOAD
+	PUSH 0
+	MSTORE
+	PUSH 0x20
+	PUSH 0
+	RETURN
+`
+
+// storageWriterCode increments the value of the storage slot zero and returns the new value.
+const storageWriterCode = `
+	PUSH 0
+	SLOAD
+	PUSH 1
+	ADD
+	DUP1
+	PUSH 0
+	SSTORE
+	PUSH 0
+	MSTORE
+	PUSH 0x20
+	PUSH 0
+	RETURN
+`
+
+// compileAsm compiles the EVM assembly into the bytecode.
+func compileAsm(t *testing.T, source string) []byte {
+	compiler := asm.NewCompiler(false)
+	compiler.Feed(asm.Lex([]byte(source), false))
+
+	code, errs := compiler.Compile()
+	require.Empty(t, errs)
+	return common.FromHex(code)
+}
+
+// mockBackend serves the chain state from memory and counts the requests.
+type mockBackend struct {
+	balances map[common.Address]*big.Int
+	code     map[common.Address][]byte
+	storage  map[common.Address]map[common.Hash]common.Hash
+	requests int
+	err      error
+}
+
+func newMockBackend() *mockBackend {
+	return &mockBackend{
+		balances: make(map[common.Address]*big.Int),
+		code:     make(map[common.Address][]byte),
+		storage:  make(map[common.Address]map[common.Hash]common.Hash),
+	}
+}
+
+func (b *mockBackend) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	if number == nil {
+		number = big.NewInt(19_000_000)
+	}
+
+	return &types.Header{
+		Number:     number,
+		Time:       1710338135,
+		GasLimit:   DefaultGasLimit,
+		Difficulty: big.NewInt(0),
+		BaseFee:    big.NewInt(1_000_000_000),
+	}, b.err
+}
+
+func (b *mockBackend) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	b.requests++
+	if balance, ok := b.balances[account]; ok {
+		return balance, b.err
+	}
+	return big.NewInt(0), b.err
+}
+
+func (b *mockBackend) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+	b.requests++
+	return 0, b.err
+}
+
+func (b *mockBackend) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error) {
+	b.requests++
+	return b.code[account], b.err
+}
+
+func (b *mockBackend) StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) ([]byte, error) {
+	b.requests++
+	value := b.storage[account][key]
+	return value.Bytes(), b.err
+}
+
+func TestForkedState(t *testing.T) {
+	reader := common.HexToAddress("0x1000000000000000000000000000000000000001")
+	writer := common.HexToAddress("0x1000000000000000000000000000000000000002")
+	holder := common.HexToAddress("0x1000000000000000000000000000000000000003")
+	caller := common.HexToAddress("0x1000000000000000000000000000000000000004")
+
+	backend := newMockBackend()
+	backend.code[reader] = compileAsm(t, storageReaderCode)
+	backend.code[writer] = compileAsm(t, storageWriterCode)
+	backend.balances[holder] = big.NewInt(1_000)
+	backend.storage[reader] = map[common.Hash]common.Hash{{}: common.BigToHash(big.NewInt(42))}
+	backend.storage[writer] = map[common.Hash]common.Hash{{}: common.BigToHash(big.NewInt(7))}
+
+	ctx := context.Background()
+	simulator, err := NewForkedSimulator(ctx, backend, nil)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(19_000_000), simulator.GetHeader().Number)
+
+	t.Run("Reads Forked Storage", func(t *testing.T) {
+		result, err := simulator.Call(ctx, caller, reader, nil, nil)
+		require.NoError(t, err)
+		assert.False(t, result.Reverted)
+		assert.Equal(t, big.NewInt(42), new(big.Int).SetBytes(result.ReturnData))
+
+		// Forked values are cached by the state, so the backend is not asked again.
+		requests := backend.requests
+		_, err = simulator.Call(ctx, caller, reader, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, requests, backend.requests)
+	})
+
+	t.Run("Writes Stay Local", func(t *testing.T) {
+		revision := simulator.Snapshot()
+
+		result, err := simulator.Call(ctx, caller, writer, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, big.NewInt(8), new(big.Int).SetBytes(result.ReturnData))
+
+		result, err = simulator.Call(ctx, caller, writer, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, big.NewInt(9), new(big.Int).SetBytes(result.ReturnData))
+		assert.Equal(t, common.BigToHash(big.NewInt(7)), backend.storage[writer][common.Hash{}])
+
+		simulator.RevertToSnapshot(revision)
+
+		result, err = simulator.Call(ctx, caller, writer, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, big.NewInt(8), new(big.Int).SetBytes(result.ReturnData))
+	})
+
+	t.Run("Reads Forked Balances", func(t *testing.T) {
+		assert.Equal(t, big.NewInt(1_000), simulator.GetBalance(holder))
+		assert.True(t, simulator.GetState().Exist(holder))
+		assert.False(t, simulator.GetState().Exist(common.HexToAddress("0x1000000000000000000000000000000000000005")))
+
+		result, err := simulator.Call(ctx, holder, caller, big.NewInt(400), nil)
+		require.NoError(t, err)
+		assert.False(t, result.Reverted)
+		assert.Equal(t, big.NewInt(600), simulator.GetBalance(holder))
+		assert.Equal(t, big.NewInt(400), simulator.GetBalance(caller))
+	})
+
+	t.Run("Backend Errors", func(t *testing.T) {
+		backend.err = errors.New("connection refused")
+		defer func() { backend.err = nil }()
+
+		_, err := simulator.Call(ctx, caller, common.HexToAddress("0x1000000000000000000000000000000000000006"), nil, nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "connection refused")
+	})
+}
+
+func TestNewForkedState(t *testing.T) {
+	_, err := NewForkedState(context.Background(), nil, nil)
+	assert.ErrorIs(t, err, ErrInvalidBackend)
+
+	_, err = NewForkedSimulator(context.Background(), nil, nil)
+	assert.ErrorIs(t, err, ErrInvalidBackend)
+
+	_, err = NewSimulator(nil, nil)
+	assert.ErrorIs(t, err, ErrInvalidState)
+}
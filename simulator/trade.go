@@ -0,0 +1,508 @@
+package simulator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/unpackdev/solgo/utils"
+)
+
+const (
+	// DefaultLimitProbes is the number of times the trade amount is divided by ten after the failed trade,
+	// in order to find out whether the failure was caused by the maximum transaction limit.
+	DefaultLimitProbes = 3
+
+	// WarnTaxThreshold is the buy or sell tax percentage from which the token is considered risky.
+	WarnTaxThreshold = 10.0
+
+	// HighTaxThreshold is the buy or sell tax percentage from which the token is considered unsafe.
+	HighTaxThreshold = 50.0
+
+	// tradeDeadline is the number of seconds after the block time the simulated swaps have to be executed by.
+	tradeDeadline = 3600
+)
+
+// DefaultTrader is the account buying and selling the token when the trade options do not specify one.
+var DefaultTrader = common.HexToAddress("0x000000000000000000000000000000000000c0de")
+
+// TradeOptions configures the simulation of buying and selling the ERC-20 token.
+type TradeOptions struct {
+	Router      common.Address `json:"router"`       // Uniswap V2 compatible router the trades are executed through.
+	Token       common.Address `json:"token"`        // ERC-20 token being traded.
+	Amount      *big.Int       `json:"amount"`       // Amount of the native currency spent on buying the token.
+	Trader      common.Address `json:"trader"`       // Account executing the trades, DefaultTrader when not set.
+	LimitProbes int            `json:"limit_probes"` // Number of limit probes, DefaultLimitProbes when not set.
+}
+
+// Validate checks that the router, token and the positive amount are provided.
+func (o *TradeOptions) Validate() error {
+	if o == nil {
+		return fmt.Errorf("%w: options are not provided", ErrInvalidTradeOptions)
+	}
+
+	if o.Router == utils.ZeroAddress {
+		return fmt.Errorf("%w: router address is not provided", ErrInvalidTradeOptions)
+	}
+
+	if o.Token == utils.ZeroAddress {
+		return fmt.Errorf("%w: token address is not provided", ErrInvalidTradeOptions)
+	}
+
+	if o.Amount == nil || o.Amount.Sign() <= 0 {
+		return fmt.Errorf("%w: amount has to be positive", ErrInvalidTradeOptions)
+	}
+
+	return nil
+}
+
+// TradeResult represents the outcome of the single simulated buy or sell.
+type TradeResult struct {
+	Type        utils.TradeType `json:"type"`             // Whether the token was bought or sold.
+	AmountIn    *big.Int        `json:"amount_in"`        // Amount of the native currency or token spent.
+	ExpectedOut *big.Int        `json:"expected_out"`     // Amount the router quoted for the amount in.
+	AmountOut   *big.Int        `json:"amount_out"`       // Amount actually received by the trader.
+	Tax         float64         `json:"tax"`              // Percentage of the quoted amount the trader did not receive.
+	GasUsed     uint64          `json:"gas_used"`         // Gas used by the swap.
+	Success     bool            `json:"success"`          // Whether the swap succeeded.
+	Reason      string          `json:"reason,omitempty"` // Reason of the failed swap.
+}
+
+// TradeReport represents the outcome of the trade simulation of the token.
+type TradeReport struct {
+	Router          common.Address `json:"router"`           // Router the trades were executed through.
+	Token           common.Address `json:"token"`            // Token being traded.
+	WETH            common.Address `json:"weth"`             // Wrapped native currency the token was paired with.
+	Trader          common.Address `json:"trader"`           // Account executing the trades.
+	Buy             *TradeResult   `json:"buy"`              // Outcome of the buy.
+	Sell            *TradeResult   `json:"sell,omitempty"`   // Outcome of selling the bought tokens.
+	BuyBlocked      bool           `json:"buy_blocked"`      // Whether the token could not be bought at all.
+	SellBlocked     bool           `json:"sell_blocked"`     // Whether the bought tokens could not be sold at all.
+	TransferBlocked bool           `json:"transfer_blocked"` // Whether the trader could not transfer the bought tokens.
+	MaxTransaction  bool           `json:"max_transaction"`  // Whether only the smaller amount could be bought or sold.
+	MaxWallet       bool           `json:"max_wallet"`       // Whether the trader could not buy more, while another account could.
+}
+
+// GetBuyTax returns the buy tax percentage, or zero if the token could not be bought.
+func (r *TradeReport) GetBuyTax() float64 {
+	if r.Buy == nil || !r.Buy.Success {
+		return 0
+	}
+	return r.Buy.Tax
+}
+
+// GetSellTax returns the sell tax percentage, or zero if the token could not be sold.
+func (r *TradeReport) GetSellTax() float64 {
+	if r.Sell == nil || !r.Sell.Success {
+		return 0
+	}
+	return r.Sell.Tax
+}
+
+// IsHoneypot returns true if the bought tokens could be neither sold nor transferred.
+func (r *TradeReport) IsHoneypot() bool {
+	return r.SellBlocked || r.TransferBlocked
+}
+
+// GetSafetyState classifies the token. Honeypots and tokens taxed from HighTaxThreshold are unsafe, while
+// tokens taxed from WarnTaxThreshold or limiting transactions and wallets are risky. The state of tokens
+// that could not be bought is unknown, as it is usually caused by the disabled trading or missing liquidity.
+func (r *TradeReport) GetSafetyState() utils.SafetyStateType {
+	tax := max(r.GetBuyTax(), r.GetSellTax())
+
+	switch {
+	case r.BuyBlocked:
+		return utils.UnknownSafetyState
+	case r.IsHoneypot(), tax >= HighTaxThreshold:
+		return utils.UnsafeSafetyState
+	case tax >= WarnTaxThreshold, r.MaxTransaction, r.MaxWallet:
+		return utils.WarnSafetyState
+	default:
+		return utils.SafeSafetyState
+	}
+}
+
+// GetAntiWhale returns the anti-whale measures detected by the simulation.
+func (r *TradeReport) GetAntiWhale() []utils.AntiWhaleType {
+	toReturn := make([]utils.AntiWhaleType, 0)
+
+	if r.MaxTransaction {
+		toReturn = append(toReturn, utils.AntiWhaleMaxTransaction)
+	}
+
+	if r.MaxWallet {
+		toReturn = append(toReturn, utils.AntiWhaleMaxWallet)
+	}
+
+	return toReturn
+}
+
+// GetBlacklists returns the blacklist categories the token falls into based on the simulation.
+func (r *TradeReport) GetBlacklists() []utils.BlacklistType {
+	toReturn := make([]utils.BlacklistType, 0)
+
+	if r.IsHoneypot() {
+		toReturn = append(toReturn, utils.HoneypotBlacklistType)
+	}
+
+	if max(r.GetBuyTax(), r.GetSellTax()) >= HighTaxThreshold {
+		toReturn = append(toReturn, utils.HighTaxTokenBlacklistType)
+	}
+
+	return toReturn
+}
+
+// SimulateTrades buys the token for the native currency and sells the bought tokens back through the router,
+// measuring the effective taxes as the difference between the quoted and received amounts. Failed trades are
+// retried with smaller amounts to detect the maximum transaction limits, the second buy detects the maximum
+// wallet limits and transferring the bought tokens detects the wallets blacklisted upon buying. The state is
+// reverted once the simulation is done.
+func (s *Simulator) SimulateTrades(ctx context.Context, opts *TradeOptions) (*TradeReport, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	trader := opts.Trader
+	if trader == utils.ZeroAddress {
+		trader = DefaultTrader
+	}
+
+	probes := opts.LimitProbes
+	if probes <= 0 {
+		probes = DefaultLimitProbes
+	}
+
+	revision := s.Snapshot()
+	defer s.RevertToSnapshot(revision)
+
+	report := &TradeReport{
+		Router: opts.Router,
+		Token:  opts.Token,
+		Trader: trader,
+	}
+
+	weth, err := s.view(ctx, trader, opts.Router, "WETH")
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve router weth: %w", err)
+	}
+	report.WETH = weth[0].(common.Address)
+
+	// Trader is funded for the second buy of the maximum wallet detection as well.
+	s.SetBalance(trader, new(big.Int).Mul(opts.Amount, big.NewInt(2)))
+
+	var limited bool
+	report.Buy, limited, err = s.probe(opts.Amount, probes, func(amount *big.Int) (*TradeResult, error) {
+		return s.buy(ctx, report, trader, amount)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !report.Buy.Success {
+		report.BuyBlocked = true
+		return report, nil
+	}
+	report.MaxTransaction = limited
+
+	if report.MaxWallet, err = s.detectMaxWallet(ctx, report, trader); err != nil {
+		return nil, err
+	}
+
+	if report.TransferBlocked, err = s.detectTransferBlocked(ctx, report, trader); err != nil {
+		return nil, err
+	}
+
+	balance, err := s.tokenBalance(ctx, report.Token, trader)
+	if err != nil {
+		return nil, err
+	}
+
+	approval, err := s.transact(ctx, trader, report.Token, tokenABI, "approve", report.Router, math.MaxBig256)
+	if err != nil {
+		return nil, err
+	}
+
+	if approval.Reverted {
+		report.SellBlocked = true
+		report.Sell = &TradeResult{Type: utils.SellTradeType, AmountIn: balance, Reason: approval.Reason}
+		return report, nil
+	}
+
+	report.Sell, limited, err = s.probe(balance, probes, func(amount *big.Int) (*TradeResult, error) {
+		return s.sell(ctx, report, trader, amount)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	report.SellBlocked = !report.Sell.Success
+	report.MaxTransaction = report.MaxTransaction || limited
+
+	return report, nil
+}
+
+// probe executes the trade, dividing the amount by ten after every failed trade until the trade succeeds or
+// the probes run out. It returns the successful trade, or the first failed one, together with the flag telling
+// whether the trade succeeded only with the smaller amount.
+func (s *Simulator) probe(amount *big.Int, probes int, trade func(amount *big.Int) (*TradeResult, error)) (*TradeResult, bool, error) {
+	var failed *TradeResult
+
+	for i := 0; i <= probes && amount.Sign() > 0; i++ {
+		result, err := trade(amount)
+		if err != nil {
+			return nil, false, err
+		}
+
+		if result.Success {
+			return result, failed != nil, nil
+		}
+
+		if failed == nil {
+			failed = result
+		}
+
+		amount = new(big.Int).Div(amount, big.NewInt(10))
+	}
+
+	return failed, false, nil
+}
+
+// detectMaxWallet buys the token for the second time. When the second buy fails, while another account is
+// able to buy the same amount, the failure is caused by the maximum wallet limit.
+func (s *Simulator) detectMaxWallet(ctx context.Context, report *TradeReport, trader common.Address) (bool, error) {
+	revision := s.Snapshot()
+	defer s.RevertToSnapshot(revision)
+
+	second, err := s.buy(ctx, report, trader, report.Buy.AmountIn)
+	if err != nil || second.Success {
+		return false, err
+	}
+
+	other := freshAccount(trader, 1)
+	s.SetBalance(other, report.Buy.AmountIn)
+
+	result, err := s.buy(ctx, report, other, report.Buy.AmountIn)
+	if err != nil {
+		return false, err
+	}
+
+	return result.Success, nil
+}
+
+// detectTransferBlocked transfers a fraction of the bought tokens to another account. Tokens blacklisting their
+// buyers, or disabling transfers between wallets altogether, reject the transfer.
+func (s *Simulator) detectTransferBlocked(ctx context.Context, report *TradeReport, trader common.Address) (bool, error) {
+	revision := s.Snapshot()
+	defer s.RevertToSnapshot(revision)
+
+	amount := new(big.Int).Div(report.Buy.AmountOut, big.NewInt(100))
+	if amount.Sign() == 0 {
+		amount = new(big.Int).Set(report.Buy.AmountOut)
+	}
+
+	result, err := s.transact(ctx, trader, report.Token, tokenABI, "transfer", freshAccount(trader, 2), amount)
+	if err != nil {
+		return false, err
+	}
+
+	return result.Reverted || !returnsTrue(result.ReturnData), nil
+}
+
+// buy swaps the native currency amount for the token and measures the buy tax.
+func (s *Simulator) buy(ctx context.Context, report *TradeReport, trader common.Address, amount *big.Int) (*TradeResult, error) {
+	path := []common.Address{report.WETH, report.Token}
+	toReturn := &TradeResult{Type: utils.BuyTradeType, AmountIn: amount}
+
+	expected, err := s.amountOut(ctx, report.Router, trader, amount, path)
+	if err != nil {
+		return failedTrade(toReturn, err)
+	}
+	toReturn.ExpectedOut = expected
+
+	before, err := s.tokenBalance(ctx, report.Token, trader)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := routerABI.Pack("swapExactETHForTokensSupportingFeeOnTransferTokens", big.NewInt(0), path, trader, s.deadline())
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack buy: %w", err)
+	}
+
+	result, err := s.Call(ctx, trader, report.Router, amount, data)
+	if err != nil {
+		return nil, err
+	}
+
+	toReturn.GasUsed = result.GasUsed
+	if result.Reverted {
+		toReturn.Reason = result.Reason
+		return toReturn, nil
+	}
+
+	after, err := s.tokenBalance(ctx, report.Token, trader)
+	if err != nil {
+		return nil, err
+	}
+
+	toReturn.AmountOut = new(big.Int).Sub(after, before)
+	toReturn.Tax = calculateTax(expected, toReturn.AmountOut)
+	toReturn.Success = true
+	return toReturn, nil
+}
+
+// sell swaps the token amount for the native currency and measures the sell tax.
+func (s *Simulator) sell(ctx context.Context, report *TradeReport, trader common.Address, amount *big.Int) (*TradeResult, error) {
+	path := []common.Address{report.Token, report.WETH}
+	toReturn := &TradeResult{Type: utils.SellTradeType, AmountIn: amount}
+
+	expected, err := s.amountOut(ctx, report.Router, trader, amount, path)
+	if err != nil {
+		return failedTrade(toReturn, err)
+	}
+	toReturn.ExpectedOut = expected
+
+	before := s.GetBalance(trader)
+
+	data, err := routerABI.Pack("swapExactTokensForETHSupportingFeeOnTransferTokens", amount, big.NewInt(0), path, trader, s.deadline())
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack sell: %w", err)
+	}
+
+	result, err := s.Call(ctx, trader, report.Router, nil, data)
+	if err != nil {
+		return nil, err
+	}
+
+	toReturn.GasUsed = result.GasUsed
+	if result.Reverted {
+		toReturn.Reason = result.Reason
+		return toReturn, nil
+	}
+
+	toReturn.AmountOut = new(big.Int).Sub(s.GetBalance(trader), before)
+	toReturn.Tax = calculateTax(expected, toReturn.AmountOut)
+	toReturn.Success = true
+	return toReturn, nil
+}
+
+// amountOut returns the amount the router quotes for swapping the amount along the path.
+func (s *Simulator) amountOut(ctx context.Context, router, from common.Address, amount *big.Int, path []common.Address) (*big.Int, error) {
+	result, err := s.view(ctx, from, router, "getAmountsOut", amount, path)
+	if err != nil {
+		return nil, err
+	}
+
+	amounts, ok := result[0].([]*big.Int)
+	if !ok || len(amounts) != len(path) {
+		return nil, fmt.Errorf("%w: unexpected getAmountsOut result", ErrCallFailed)
+	}
+
+	return amounts[len(amounts)-1], nil
+}
+
+// tokenBalance returns the token balance of the account.
+func (s *Simulator) tokenBalance(ctx context.Context, token, account common.Address) (*big.Int, error) {
+	data, err := tokenABI.Pack("balanceOf", account)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack balanceOf: %w", err)
+	}
+
+	revision := s.Snapshot()
+	defer s.RevertToSnapshot(revision)
+
+	result, err := s.Call(ctx, account, token, nil, data)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Reverted {
+		return nil, fmt.Errorf("%w: balanceOf: %s", ErrCallFailed, result.Reason)
+	}
+
+	unpacked, err := tokenABI.Unpack("balanceOf", result.ReturnData)
+	if err != nil {
+		return nil, fmt.Errorf("%w: balanceOf: %s", ErrCallFailed, err)
+	}
+
+	return unpacked[0].(*big.Int), nil
+}
+
+// view calls the router method, reverting any state changes, and returns the unpacked outputs.
+// Reverted calls are reported as ErrCallFailed.
+func (s *Simulator) view(ctx context.Context, from, router common.Address, method string, args ...any) ([]any, error) {
+	revision := s.Snapshot()
+	defer s.RevertToSnapshot(revision)
+
+	result, err := s.transact(ctx, from, router, routerABI, method, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Reverted {
+		return nil, fmt.Errorf("%w: %s: %s", ErrCallFailed, method, result.Reason)
+	}
+
+	toReturn, err := routerABI.Unpack(method, result.ReturnData)
+	if err != nil || len(toReturn) == 0 {
+		return nil, fmt.Errorf("%w: %s: unexpected result", ErrCallFailed, method)
+	}
+
+	return toReturn, nil
+}
+
+// transact packs the method call and executes it without the value.
+func (s *Simulator) transact(ctx context.Context, from, to common.Address, contract abi.ABI, method string, args ...any) (*Result, error) {
+	data, err := contract.Pack(method, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack %s: %w", method, err)
+	}
+
+	return s.Call(ctx, from, to, nil, data)
+}
+
+// deadline returns the deadline of the simulated swaps.
+func (s *Simulator) deadline() *big.Int {
+	return new(big.Int).SetUint64(s.header.Time + tradeDeadline)
+}
+
+// failedTrade marks the trade as failed when the router could not quote it, returning other errors as is.
+func failedTrade(trade *TradeResult, err error) (*TradeResult, error) {
+	if !errors.Is(err, ErrCallFailed) {
+		return nil, err
+	}
+
+	trade.Reason = err.Error()
+	return trade, nil
+}
+
+// calculateTax returns the percentage of the expected amount that was not received.
+func calculateTax(expected, actual *big.Int) float64 {
+	if expected == nil || expected.Sign() == 0 {
+		return 0
+	}
+
+	missing := new(big.Int).Sub(expected, actual)
+	if missing.Sign() <= 0 {
+		return 0
+	}
+
+	tax, _ := new(big.Float).Quo(new(big.Float).SetInt(missing), new(big.Float).SetInt(expected)).Float64()
+	return tax * 100
+}
+
+// returnsTrue returns true unless the call returned the ABI encoded false. Tokens that do not return
+// anything, such as USDT, are considered successful.
+func returnsTrue(data []byte) bool {
+	return len(data) == 0 || new(big.Int).SetBytes(data).Sign() != 0
+}
+
+// freshAccount derives the account, distinct from the trader, used by the limit and blacklist detection.
+func freshAccount(trader common.Address, n byte) common.Address {
+	return common.BytesToAddress(crypto.Keccak256(trader.Bytes(), []byte{n}))
+}
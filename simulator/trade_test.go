@@ -0,0 +1,596 @@
+package simulator
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unpackdev/solgo/utils"
+)
+
+// tokenCode is the ERC-20 token charging the buy tax (slot 0) and sell tax (slot 1) in basis points on the
+// transfers from and to the pair (slot 5). It enforces the maximum transaction (slot 2) and wallet (slot 3)
+// limits when set, blacklists the buyers when slot 4 is set and rejects all sells when slot 6 is set.
+// Balances are stored at the slot of the account address, the blacklist at the address offset by 2^160.
+const tokenCode = `
+	PUSH 0
+	CALLDATALOAD
+	PUSH 0xe0
+	SHR
+	DUP1
+	PUSH 0x70a08231
+	EQ
+	JUMPI @balance_of
+	DUP1
+	PUSH 0xa9059cbb
+	EQ
+	JUMPI @transfer
+	DUP1
+	PUSH 0x23b872dd
+	EQ
+	JUMPI @transfer_from
+	DUP1
+	PUSH 0x095ea7b3
+	EQ
+	JUMPI @approve
+	JUMP @fail
+
+balance_of:
+	PUSH 0x04
+	CALLDATALOAD
+	SLOAD
+	PUSH 0
+	MSTORE
+	PUSH 0x20
+	PUSH 0
+	RETURN
+
+approve:
+	PUSH 1
+	PUSH 0
+	MSTORE
+	PUSH 0x20
+	PUSH 0
+	RETURN
+
+transfer:
+	CALLER
+	PUSH 0x80
+	MSTORE
+	PUSH 0x04
+	CALLDATALOAD
+	PUSH 0xa0
+	MSTORE
+	PUSH 0x24
+	CALLDATALOAD
+	PUSH 0xc0
+	MSTORE
+	JUMP @move
+
+transfer_from:
+	PUSH 0x04
+	CALLDATALOAD
+	PUSH 0x80
+	MSTORE
+	PUSH 0x24
+	CALLDATALOAD
+	PUSH 0xa0
+	MSTORE
+	PUSH 0x44
+	CALLDATALOAD
+	PUSH 0xc0
+	MSTORE
+	JUMP @move
+
+move:
+	PUSH 0x80
+	MLOAD
+	PUSH 0x10000000000000000000000000000000000000000
+	ADD
+	SLOAD
+	JUMPI @fail
+	PUSH 2
+	SLOAD
+	ISZERO
+	JUMPI @no_max_tx
+	PUSH 2
+	SLOAD
+	PUSH 0xc0
+	MLOAD
+	GT
+	JUMPI @fail
+no_max_tx:
+	PUSH 0
+	PUSH 0xe0
+	MSTORE
+	PUSH 5
+	SLOAD
+	PUSH 0x80
+	MLOAD
+	EQ
+	ISZERO
+	JUMPI @not_buy
+	PUSH 10000
+	PUSH 0
+	SLOAD
+	PUSH 0xc0
+	MLOAD
+	MUL
+	DIV
+	PUSH 0xe0
+	MSTORE
+	PUSH 4
+	SLOAD
+	ISZERO
+	JUMPI @not_buy
+	PUSH 1
+	PUSH 0xa0
+	MLOAD
+	PUSH 0x10000000000000000000000000000000000000000
+	ADD
+	SSTORE
+not_buy:
+	PUSH 5
+	SLOAD
+	PUSH 0xa0
+	MLOAD
+	EQ
+	ISZERO
+	JUMPI @not_sell
+	PUSH 6
+	SLOAD
+	JUMPI @fail
+	PUSH 10000
+	PUSH 1
+	SLOAD
+	PUSH 0xc0
+	MLOAD
+	MUL
+	DIV
+	PUSH 0xe0
+	MSTORE
+not_sell:
+	PUSH 0x80
+	MLOAD
+	SLOAD
+	PUSH 0xc0
+	MLOAD
+	DUP2
+	LT
+	JUMPI @fail
+	PUSH 0xc0
+	MLOAD
+	SWAP1
+	SUB
+	PUSH 0x80
+	MLOAD
+	SSTORE
+	PUSH 0xe0
+	MLOAD
+	PUSH 0xc0
+	MLOAD
+	SUB
+	PUSH 0xa0
+	MLOAD
+	SLOAD
+	ADD
+	DUP1
+	PUSH 0xa0
+	MLOAD
+	SSTORE
+	PUSH 3
+	SLOAD
+	ISZERO
+	JUMPI @ok
+	PUSH 5
+	SLOAD
+	PUSH 0xa0
+	MLOAD
+	EQ
+	JUMPI @ok
+	PUSH 3
+	SLOAD
+	SWAP1
+	GT
+	JUMPI @fail
+ok:
+	PUSH 1
+	PUSH 0
+	MSTORE
+	PUSH 0x20
+	PUSH 0
+	RETURN
+
+fail:
+	PUSH 0
+	DUP1
+	REVERT
+`
+
+// routerCode is the Uniswap V2 like router, acting as the pair of the token (slot 1) and the wrapped native
+// currency (slot 0) at the fixed one to one price. Buys transfer the tokens held by the router, while sells pay
+// out the amount of tokens the router actually received.
+const routerCode = `
+	PUSH 0
+	CALLDATALOAD
+	PUSH 0xe0
+	SHR
+	DUP1
+	PUSH 0xad5c4648
+	EQ
+	JUMPI @weth
+	DUP1
+	PUSH 0xd06ca61f
+	EQ
+	JUMPI @amounts_out
+	DUP1
+	PUSH 0xb6f9de95
+	EQ
+	JUMPI @buy
+	DUP1
+	PUSH 0x791ac947
+	EQ
+	JUMPI @sell
+	PUSH 0
+	DUP1
+	REVERT
+
+weth:
+	PUSH 0
+	SLOAD
+	PUSH 0
+	MSTORE
+	PUSH 0x20
+	PUSH 0
+	RETURN
+
+amounts_out:
+	PUSH 0x20
+	PUSH 0
+	MSTORE
+	PUSH 2
+	PUSH 0x20
+	MSTORE
+	PUSH 0x04
+	CALLDATALOAD
+	DUP1
+	PUSH 0x40
+	MSTORE
+	PUSH 0x60
+	MSTORE
+	PUSH 0x80
+	PUSH 0
+	RETURN
+
+buy:
+	PUSH 0xa9059cbb
+	PUSH 0xe0
+	SHL
+	PUSH 0
+	MSTORE
+	PUSH 0x44
+	CALLDATALOAD
+	PUSH 0x04
+	MSTORE
+	CALLVALUE
+	PUSH 0x24
+	MSTORE
+	PUSH 0x20
+	PUSH 0
+	PUSH 0x44
+	PUSH 0
+	PUSH 0
+	PUSH 1
+	SLOAD
+	GAS
+	CALL
+	ISZERO
+	JUMPI @bubble
+	STOP
+
+sell:
+	PUSH 0x70a08231
+	PUSH 0xe0
+	SHL
+	PUSH 0
+	MSTORE
+	ADDRESS
+	PUSH 0x04
+	MSTORE
+	PUSH 0x20
+	PUSH 0x100
+	PUSH 0x24
+	PUSH 0
+	PUSH 0
+	PUSH 1
+	SLOAD
+	GAS
+	CALL
+	ISZERO
+	JUMPI @bubble
+	PUSH 0x23b872dd
+	PUSH 0xe0
+	SHL
+	PUSH 0
+	MSTORE
+	CALLER
+	PUSH 0x04
+	MSTORE
+	ADDRESS
+	PUSH 0x24
+	MSTORE
+	PUSH 0x04
+	CALLDATALOAD
+	PUSH 0x44
+	MSTORE
+	PUSH 0x20
+	PUSH 0x140
+	PUSH 0x64
+	PUSH 0
+	PUSH 0
+	PUSH 1
+	SLOAD
+	GAS
+	CALL
+	ISZERO
+	JUMPI @bubble
+	PUSH 0x70a08231
+	PUSH 0xe0
+	SHL
+	PUSH 0
+	MSTORE
+	ADDRESS
+	PUSH 0x04
+	MSTORE
+	PUSH 0x20
+	PUSH 0x120
+	PUSH 0x24
+	PUSH 0
+	PUSH 0
+	PUSH 1
+	SLOAD
+	GAS
+	CALL
+	ISZERO
+	JUMPI @bubble
+	PUSH 0x100
+	MLOAD
+	PUSH 0x120
+	MLOAD
+	SUB
+	PUSH 0
+	PUSH 0
+	PUSH 0
+	PUSH 0
+	DUP5
+	PUSH 0x64
+	CALLDATALOAD
+	GAS
+	CALL
+	ISZERO
+	JUMPI @bubble
+	STOP
+
+bubble:
+	RETURNDATASIZE
+	PUSH 0
+	PUSH 0
+	RETURNDATACOPY
+	RETURNDATASIZE
+	PUSH 0
+	REVERT
+`
+
+var (
+	testRouter = common.HexToAddress("0x2000000000000000000000000000000000000001")
+	testToken  = common.HexToAddress("0x2000000000000000000000000000000000000002")
+	testWETH   = common.HexToAddress("0x2000000000000000000000000000000000000003")
+)
+
+// tokenSettings configures the behaviour of the test token.
+type tokenSettings struct {
+	buyTax         int64
+	sellTax        int64
+	maxTransaction *big.Int
+	maxWallet      *big.Int
+	blacklistBuyer bool
+	blockSells     bool
+	liquidity      *big.Int
+}
+
+// newTradeSimulator deploys the test token and router into the local state.
+func newTradeSimulator(t *testing.T, settings tokenSettings) *Simulator {
+	simulator, err := NewLocalSimulator()
+	require.NoError(t, err)
+
+	simulator.SetCode(testRouter, compileAsm(t, routerCode))
+	simulator.SetCode(testToken, compileAsm(t, tokenCode))
+	simulator.SetBalance(testRouter, ether(1_000))
+
+	setSlot := func(address common.Address, slot *big.Int, value *big.Int) {
+		if value != nil {
+			simulator.GetState().SetState(address, common.BigToHash(slot), common.BigToHash(value))
+		}
+	}
+
+	flag := func(enabled bool) *big.Int {
+		if enabled {
+			return big.NewInt(1)
+		}
+		return nil
+	}
+
+	liquidity := settings.liquidity
+	if liquidity == nil {
+		liquidity = ether(1_000)
+	}
+
+	setSlot(testRouter, big.NewInt(0), testWETH.Big())
+	setSlot(testRouter, big.NewInt(1), testToken.Big())
+	setSlot(testToken, big.NewInt(0), big.NewInt(settings.buyTax))
+	setSlot(testToken, big.NewInt(1), big.NewInt(settings.sellTax))
+	setSlot(testToken, big.NewInt(2), settings.maxTransaction)
+	setSlot(testToken, big.NewInt(3), settings.maxWallet)
+	setSlot(testToken, big.NewInt(4), flag(settings.blacklistBuyer))
+	setSlot(testToken, big.NewInt(5), testRouter.Big())
+	setSlot(testToken, big.NewInt(6), flag(settings.blockSells))
+	setSlot(testToken, testRouter.Big(), liquidity)
+
+	return simulator
+}
+
+// ether returns the amount in wei.
+func ether(amount int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(amount), big.NewInt(1e18))
+}
+
+func TestSimulateTrades(t *testing.T) {
+	testCases := []struct {
+		name            string
+		settings        tokenSettings
+		buyTax          float64
+		sellTax         float64
+		buyBlocked      bool
+		sellBlocked     bool
+		transferBlocked bool
+		maxTransaction  bool
+		maxWallet       bool
+		safetyState     utils.SafetyStateType
+		antiWhale       []utils.AntiWhaleType
+		blacklists      []utils.BlacklistType
+	}{
+		{
+			name:        "Safe Token",
+			settings:    tokenSettings{},
+			safetyState: utils.SafeSafetyState,
+			antiWhale:   []utils.AntiWhaleType{},
+			blacklists:  []utils.BlacklistType{},
+		},
+		{
+			name:        "Taxed Token",
+			settings:    tokenSettings{buyTax: 500, sellTax: 1000},
+			buyTax:      5,
+			sellTax:     10,
+			safetyState: utils.WarnSafetyState,
+			antiWhale:   []utils.AntiWhaleType{},
+			blacklists:  []utils.BlacklistType{},
+		},
+		{
+			name:        "High Sell Tax",
+			settings:    tokenSettings{sellTax: 9000},
+			sellTax:     90,
+			safetyState: utils.UnsafeSafetyState,
+			antiWhale:   []utils.AntiWhaleType{},
+			blacklists:  []utils.BlacklistType{utils.HighTaxTokenBlacklistType},
+		},
+		{
+			name:        "Blocked Sells",
+			settings:    tokenSettings{buyTax: 100, blockSells: true},
+			buyTax:      1,
+			sellBlocked: true,
+			safetyState: utils.UnsafeSafetyState,
+			antiWhale:   []utils.AntiWhaleType{},
+			blacklists:  []utils.BlacklistType{utils.HoneypotBlacklistType},
+		},
+		{
+			name:            "Blacklisted Buyer",
+			settings:        tokenSettings{blacklistBuyer: true},
+			sellBlocked:     true,
+			transferBlocked: true,
+			safetyState:     utils.UnsafeSafetyState,
+			antiWhale:       []utils.AntiWhaleType{},
+			blacklists:      []utils.BlacklistType{utils.HoneypotBlacklistType},
+		},
+		{
+			name:           "Maximum Transaction",
+			settings:       tokenSettings{maxTransaction: new(big.Int).Div(ether(1), big.NewInt(2))},
+			maxTransaction: true,
+			safetyState:    utils.WarnSafetyState,
+			antiWhale:      []utils.AntiWhaleType{utils.AntiWhaleMaxTransaction},
+			blacklists:     []utils.BlacklistType{},
+		},
+		{
+			name:        "Maximum Wallet",
+			settings:    tokenSettings{maxWallet: new(big.Int).Div(ether(3), big.NewInt(2))},
+			maxWallet:   true,
+			safetyState: utils.WarnSafetyState,
+			antiWhale:   []utils.AntiWhaleType{utils.AntiWhaleMaxWallet},
+			blacklists:  []utils.BlacklistType{},
+		},
+		{
+			name:        "No Liquidity",
+			settings:    tokenSettings{liquidity: big.NewInt(0)},
+			buyBlocked:  true,
+			safetyState: utils.UnknownSafetyState,
+			antiWhale:   []utils.AntiWhaleType{},
+			blacklists:  []utils.BlacklistType{},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctx := context.Background()
+			simulator := newTradeSimulator(t, testCase.settings)
+
+			report, err := simulator.SimulateTrades(ctx, &TradeOptions{
+				Router: testRouter,
+				Token:  testToken,
+				Amount: ether(1),
+			})
+			require.NoError(t, err)
+			require.NotNil(t, report)
+
+			assert.Equal(t, testWETH, report.WETH)
+			assert.Equal(t, DefaultTrader, report.Trader)
+			assert.InDelta(t, testCase.buyTax, report.GetBuyTax(), 1e-9)
+			assert.InDelta(t, testCase.sellTax, report.GetSellTax(), 1e-9)
+			assert.Equal(t, testCase.buyBlocked, report.BuyBlocked)
+			assert.Equal(t, testCase.sellBlocked, report.SellBlocked)
+			assert.Equal(t, testCase.transferBlocked, report.TransferBlocked)
+			assert.Equal(t, testCase.maxTransaction, report.MaxTransaction)
+			assert.Equal(t, testCase.maxWallet, report.MaxWallet)
+			assert.Equal(t, testCase.safetyState, report.GetSafetyState())
+			assert.Equal(t, testCase.antiWhale, report.GetAntiWhale())
+			assert.Equal(t, testCase.blacklists, report.GetBlacklists())
+
+			if !testCase.buyBlocked {
+				assert.True(t, report.Buy.Success)
+				assert.NotZero(t, report.Buy.GasUsed)
+			}
+
+			// The simulation does not leave any trace in the state.
+			assert.Zero(t, simulator.GetBalance(DefaultTrader).Sign())
+			balance, err := simulator.tokenBalance(ctx, testToken, DefaultTrader)
+			require.NoError(t, err)
+			assert.Zero(t, balance.Sign())
+		})
+	}
+}
+
+func TestTradeOptionsValidate(t *testing.T) {
+	testCases := []struct {
+		name    string
+		opts    *TradeOptions
+		wantErr bool
+	}{
+		{name: "Nil Options", opts: nil, wantErr: true},
+		{name: "Missing Router", opts: &TradeOptions{Token: testToken, Amount: ether(1)}, wantErr: true},
+		{name: "Missing Token", opts: &TradeOptions{Router: testRouter, Amount: ether(1)}, wantErr: true},
+		{name: "Zero Amount", opts: &TradeOptions{Router: testRouter, Token: testToken, Amount: big.NewInt(0)}, wantErr: true},
+		{name: "Valid Options", opts: &TradeOptions{Router: testRouter, Token: testToken, Amount: ether(1)}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := testCase.opts.Validate()
+			if testCase.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidTradeOptions)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,4 @@
+// Package sourcemap decodes solc compressed source maps and maps EVM program counters back to
+// Solidity source locations (file, line, column and jump type), as well as AST nodes back to the
+// instructions generated for them. It is used to attribute traces, reverts and gas usage to source lines.
+package sourcemap
@@ -0,0 +1,8 @@
+package sourcemap
+
+import "errors"
+
+var (
+	ErrEmptySourceMap   = errors.New("source map is not set or empty source map provided")
+	ErrInvalidSourceMap = errors.New("invalid source map entry")
+)
@@ -0,0 +1,273 @@
+package sourcemap
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/unpackdev/solgo"
+	"github.com/unpackdev/solgo/ast"
+	"github.com/unpackdev/solgo/opcode"
+)
+
+// Location represents the source location of a single instruction.
+// Line is 1-based while Column is 0-based, matching the ast.SrcNode convention.
+type Location struct {
+	PC          int                `json:"pc"`
+	Instruction opcode.Instruction `json:"instruction"`
+	Entry       Entry              `json:"entry"`
+	File        string             `json:"file,omitempty"`
+	Line        int                `json:"line,omitempty"`
+	Column      int                `json:"column,omitempty"`
+	Jump        JumpType           `json:"jump"`
+}
+
+// HasSource returns true if the instruction could be attributed to a source file.
+func (l *Location) HasSource() bool {
+	return l.File != ""
+}
+
+// String returns `file:line:column` representation of the location.
+func (l *Location) String() string {
+	if !l.HasSource() {
+		return fmt.Sprintf("0x%04x <unknown>", l.PC)
+	}
+	return fmt.Sprintf("0x%04x %s:%d:%d", l.PC, l.File, l.Line, l.Column)
+}
+
+// Mapper maps program counters to source locations and source ranges back to instructions.
+type Mapper struct {
+	ctx        context.Context
+	sources    *solgo.Sources
+	fileIndex  map[int]string
+	entries    []Entry
+	locations  []*Location
+	byPC       map[int]*Location
+	lineStarts map[string][]int
+}
+
+// NewMapper creates a new Mapper out of the bytecode and its source map. The bytecode is decompiled through
+// the opcode.Decompiler and every instruction is paired with its source map entry. File index maps source
+// map file indexes to source unit paths and can be built with NewFileIndexFromOutput or NewFileIndexFromSources.
+func NewMapper(ctx context.Context, sources *solgo.Sources, fileIndex map[int]string, bytecode []byte, sourceMap string) (*Mapper, error) {
+	if sources == nil {
+		return nil, fmt.Errorf("sources must be provided")
+	}
+
+	if fileIndex == nil {
+		fileIndex = NewFileIndexFromSources(sources)
+	}
+
+	entries, err := Decode(sourceMap)
+	if err != nil {
+		return nil, err
+	}
+
+	decompiler, err := opcode.NewDecompiler(ctx, bytecode)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := decompiler.Decompile(); err != nil {
+		return nil, err
+	}
+
+	mapper := &Mapper{
+		ctx:        ctx,
+		sources:    sources,
+		fileIndex:  fileIndex,
+		entries:    entries,
+		locations:  make([]*Location, 0, len(entries)),
+		byPC:       make(map[int]*Location),
+		lineStarts: make(map[string][]int),
+	}
+
+	// Source map contains one entry per instruction. Everything past the last entry is data,
+	// such as the CBOR metadata, and does not have a source location.
+	for i, instruction := range decompiler.GetInstructions() {
+		if i >= len(entries) {
+			break
+		}
+
+		location := &Location{
+			PC:          instruction.Offset,
+			Instruction: instruction,
+			Entry:       entries[i],
+			Jump:        entries[i].Jump,
+		}
+
+		if entries[i].HasSource() {
+			if unit := mapper.getSourceUnit(entries[i].FileIndex); unit != nil {
+				location.File = unit.GetStandardJsonPath()
+				location.Line, location.Column = mapper.lineColumn(unit, entries[i].Start)
+			}
+		}
+
+		mapper.locations = append(mapper.locations, location)
+		mapper.byPC[instruction.Offset] = location
+	}
+
+	return mapper, nil
+}
+
+// NewFileIndexFromOutput builds the file index out of the source ids found in the Standard JSON Output.
+func NewFileIndexFromOutput(output *solgo.StandardJsonOutput) map[int]string {
+	toReturn := make(map[int]string)
+	for name, source := range output.Sources {
+		toReturn[source.ID] = name
+	}
+	return toReturn
+}
+
+// NewFileIndexFromSources builds the file index out of the sources. Solc assigns source ids in
+// lexicographical order of source unit names, which is replicated here.
+func NewFileIndexFromSources(sources *solgo.Sources) map[int]string {
+	paths := make([]string, 0, len(sources.GetUnits()))
+	for _, unit := range sources.GetUnits() {
+		paths = append(paths, unit.GetStandardJsonPath())
+	}
+	sort.Strings(paths)
+
+	toReturn := make(map[int]string)
+	for i, path := range paths {
+		toReturn[i] = path
+	}
+	return toReturn
+}
+
+// GetContext returns the context associated with the Mapper.
+func (m *Mapper) GetContext() context.Context {
+	return m.ctx
+}
+
+// GetEntries returns decoded source map entries.
+func (m *Mapper) GetEntries() []Entry {
+	return m.entries
+}
+
+// GetLocations returns source locations of all instructions in program counter order.
+func (m *Mapper) GetLocations() []*Location {
+	return m.locations
+}
+
+// GetLocationByPC returns the source location of the instruction at the provided program counter.
+func (m *Mapper) GetLocationByPC(pc int) (*Location, bool) {
+	location, ok := m.byPC[pc]
+	return location, ok
+}
+
+// GetInstructionsForRange returns locations of all instructions generated for the source range
+// [start, end) within the provided file. Offsets are byte offsets within the source file.
+func (m *Mapper) GetInstructionsForRange(file string, start int, end int) []*Location {
+	var toReturn []*Location
+	for _, location := range m.locations {
+		if location.File != file {
+			continue
+		}
+
+		if location.Entry.Start >= start && location.Entry.GetEnd() <= end {
+			toReturn = append(toReturn, location)
+		}
+	}
+	return toReturn
+}
+
+// GetInstructionsForNode returns locations of all instructions generated for the provided AST node.
+// AST nodes are positioned within the combined sources, so their location is first translated into
+// the source unit they belong to.
+func (m *Mapper) GetInstructionsForNode(node ast.Node[ast.NodeType]) ([]*Location, error) {
+	if node == nil {
+		return nil, fmt.Errorf("node must be provided")
+	}
+
+	src := node.GetSrc()
+	unit, start, end, err := m.resolveCombinedRange(int(src.GetStart()), int(src.GetStart()+src.GetLength()))
+	if err != nil {
+		return nil, err
+	}
+
+	return m.GetInstructionsForRange(unit.GetStandardJsonPath(), start, end), nil
+}
+
+// getSourceUnit returns the source unit for the provided source map file index.
+func (m *Mapper) getSourceUnit(fileIndex int) *solgo.SourceUnit {
+	path, ok := m.fileIndex[fileIndex]
+	if !ok {
+		return nil
+	}
+
+	if unit := m.sources.GetSourceUnitByPath(path); unit != nil {
+		return unit
+	}
+
+	for _, unit := range m.sources.GetUnits() {
+		if unit.GetStandardJsonPath() == path || filepath.Base(unit.GetPath()) == filepath.Base(path) {
+			return unit
+		}
+	}
+
+	return nil
+}
+
+// lineColumn returns the 1-based line and 0-based column of the byte offset within the source unit.
+func (m *Mapper) lineColumn(unit *solgo.SourceUnit, offset int) (int, int) {
+	path := unit.GetStandardJsonPath()
+	starts, ok := m.lineStarts[path]
+	if !ok {
+		starts = []int{0}
+		for i := 0; i < len(unit.Content); i++ {
+			if unit.Content[i] == '\n' {
+				starts = append(starts, i+1)
+			}
+		}
+		m.lineStarts[path] = starts
+	}
+
+	line := sort.Search(len(starts), func(i int) bool { return starts[i] > offset }) - 1
+	if line < 0 {
+		line = 0
+	}
+
+	column := offset - starts[line]
+	if offset <= len(unit.Content) {
+		column = utf8.RuneCountInString(unit.Content[starts[line]:offset])
+	}
+
+	return line + 1, column
+}
+
+// resolveCombinedRange translates the character range within combined sources into the source unit and
+// the byte range within it. Combined sources are source units joined by two new lines.
+func (m *Mapper) resolveCombinedRange(start int, end int) (*solgo.SourceUnit, int, int, error) {
+	offset := 0
+	for _, unit := range m.sources.GetUnits() {
+		length := utf8.RuneCountInString(unit.Content)
+		if start >= offset && start < offset+length {
+			localStart := runeToByteOffset(unit.Content, start-offset)
+			localEnd := runeToByteOffset(unit.Content, end-offset)
+			return unit, localStart, localEnd, nil
+		}
+		offset += length + 2
+	}
+
+	return nil, 0, 0, fmt.Errorf("source range %d:%d not found in sources", start, end)
+}
+
+// runeToByteOffset converts the character offset within the content into the byte offset.
+func runeToByteOffset(content string, runeOffset int) int {
+	if runeOffset <= 0 {
+		return 0
+	}
+
+	count := 0
+	for byteOffset := range content {
+		if count == runeOffset {
+			return byteOffset
+		}
+		count++
+	}
+
+	return len(content)
+}
@@ -0,0 +1,75 @@
+package sourcemap
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unpackdev/solgo"
+	"github.com/unpackdev/solgo/ast"
+	"github.com/unpackdev/solgo/opcode"
+)
+
+// srcNode is a minimal AST node used to exercise node to instruction mapping.
+type srcNode struct {
+	ast.BodyNode
+	src ast.SrcNode
+}
+
+func (n *srcNode) GetSrc() ast.SrcNode {
+	return n.src
+}
+
+func TestMapper(t *testing.T) {
+	sources := &solgo.Sources{
+		SourceUnits: []*solgo.SourceUnit{
+			{Name: "Lib", Path: "contracts/Lib.sol", Content: "library Lib {}"},
+			{Name: "Token", Path: "contracts/Token.sol", Content: "contract Token {\n    uint256 x = 1;\n}"},
+		},
+	}
+
+	// PUSH1 0x80 PUSH1 0x40 MSTORE PUSH1 0x01 PUSH1 0x00 SSTORE STOP followed by data.
+	bytecode := []byte{0x60, 0x80, 0x60, 0x40, 0x52, 0x60, 0x01, 0x60, 0x00, 0x55, 0x00, 0xfe, 0xa1}
+	sourceMap := "0:37:1:-:0;;;21:13:1;;;-1:0:-1"
+
+	fileIndex := NewFileIndexFromSources(sources)
+	assert.Equal(t, map[int]string{0: "contracts/Lib.sol", 1: "contracts/Token.sol"}, fileIndex)
+
+	mapper, err := NewMapper(context.Background(), sources, fileIndex, bytecode, sourceMap)
+	require.NoError(t, err)
+	require.NotNil(t, mapper)
+	assert.Equal(t, 7, len(mapper.GetLocations()))
+
+	location, found := mapper.GetLocationByPC(0)
+	require.True(t, found)
+	assert.Equal(t, "contracts/Token.sol", location.File)
+	assert.Equal(t, 1, location.Line)
+	assert.Equal(t, 0, location.Column)
+
+	location, found = mapper.GetLocationByPC(5)
+	require.True(t, found)
+	assert.Equal(t, opcode.PUSH1, location.Instruction.OpCode)
+	assert.Equal(t, 2, location.Line)
+	assert.Equal(t, 4, location.Column)
+	assert.Equal(t, JumpTypeRegular, location.Jump)
+
+	location, found = mapper.GetLocationByPC(10)
+	require.True(t, found)
+	assert.False(t, location.HasSource())
+
+	_, found = mapper.GetLocationByPC(11)
+	assert.False(t, found)
+
+	assignments := mapper.GetInstructionsForRange("contracts/Token.sol", 21, 34)
+	assert.Equal(t, 3, len(assignments))
+
+	// Token starts after the Lib source unit and two new lines within combined sources.
+	node := &srcNode{src: ast.SrcNode{Start: 16 + 21, Length: 13}}
+	locations, err := mapper.GetInstructionsForNode(node)
+	require.NoError(t, err)
+	assert.Equal(t, assignments, locations)
+
+	_, err = mapper.GetInstructionsForNode(&srcNode{src: ast.SrcNode{Start: 1000, Length: 1}})
+	assert.Error(t, err)
+}
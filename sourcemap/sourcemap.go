@@ -0,0 +1,108 @@
+package sourcemap
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// JumpType describes whether an instruction goes into a function, returns from a function or
+// is a regular jump as part of e.g. a loop.
+type JumpType string
+
+const (
+	JumpTypeIn      JumpType = "i"
+	JumpTypeOut     JumpType = "o"
+	JumpTypeRegular JumpType = "-"
+)
+
+// String returns a human readable representation of the jump type.
+func (j JumpType) String() string {
+	switch j {
+	case JumpTypeIn:
+		return "into"
+	case JumpTypeOut:
+		return "out"
+	default:
+		return "regular"
+	}
+}
+
+// Entry represents a single decoded source map entry. There is one entry per instruction.
+// Start and Length are byte offsets within the source file identified by FileIndex.
+// FileIndex is -1 for instructions that cannot be attributed to a source file (e.g. generated code).
+type Entry struct {
+	Start         int      `json:"start"`
+	Length        int      `json:"length"`
+	FileIndex     int      `json:"file_index"`
+	Jump          JumpType `json:"jump"`
+	ModifierDepth int      `json:"modifier_depth"`
+}
+
+// HasSource returns true if the entry can be attributed to a source file.
+func (e Entry) HasSource() bool {
+	return e.FileIndex >= 0 && e.Start >= 0
+}
+
+// GetEnd returns the exclusive end byte offset of the entry within its source file.
+func (e Entry) GetEnd() int {
+	return e.Start + e.Length
+}
+
+// Decode decodes solc compressed source map (`s:l:f:j:m;...`) into one entry per instruction.
+// Empty fields inherit the value of the previous entry, as described in
+// https://docs.soliditylang.org/en/latest/internals/source_mappings.html
+func Decode(sourceMap string) ([]Entry, error) {
+	if strings.TrimSpace(sourceMap) == "" {
+		return nil, ErrEmptySourceMap
+	}
+
+	items := strings.Split(strings.TrimSpace(sourceMap), ";")
+	entries := make([]Entry, 0, len(items))
+
+	previous := Entry{Start: -1, Length: -1, FileIndex: -1, Jump: JumpTypeRegular}
+	for i, item := range items {
+		current := previous
+		fields := strings.Split(item, ":")
+		if len(fields) > 5 {
+			return nil, fmt.Errorf("%w at position %d: %q", ErrInvalidSourceMap, i, item)
+		}
+
+		for fieldIndex, field := range fields {
+			if field == "" {
+				continue
+			}
+
+			if fieldIndex == 3 {
+				switch JumpType(field) {
+				case JumpTypeIn, JumpTypeOut, JumpTypeRegular:
+					current.Jump = JumpType(field)
+				default:
+					return nil, fmt.Errorf("%w at position %d: unknown jump type %q", ErrInvalidSourceMap, i, field)
+				}
+				continue
+			}
+
+			value, err := strconv.Atoi(field)
+			if err != nil {
+				return nil, fmt.Errorf("%w at position %d: %s", ErrInvalidSourceMap, i, err)
+			}
+
+			switch fieldIndex {
+			case 0:
+				current.Start = value
+			case 1:
+				current.Length = value
+			case 2:
+				current.FileIndex = value
+			case 4:
+				current.ModifierDepth = value
+			}
+		}
+
+		entries = append(entries, current)
+		previous = current
+	}
+
+	return entries, nil
+}
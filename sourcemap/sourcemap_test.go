@@ -0,0 +1,60 @@
+package sourcemap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecode(t *testing.T) {
+	testCases := []struct {
+		name      string
+		sourceMap string
+		expected  []Entry
+		wantErr   bool
+	}{
+		{
+			name:      "Empty source map",
+			sourceMap: "",
+			wantErr:   true,
+		},
+		{
+			name:      "Compressed entries inherit previous values",
+			sourceMap: "1:2:1;:9;2:1:2;;3::0:i;:::o:1;-1:0:-1",
+			expected: []Entry{
+				{Start: 1, Length: 2, FileIndex: 1, Jump: JumpTypeRegular},
+				{Start: 1, Length: 9, FileIndex: 1, Jump: JumpTypeRegular},
+				{Start: 2, Length: 1, FileIndex: 2, Jump: JumpTypeRegular},
+				{Start: 2, Length: 1, FileIndex: 2, Jump: JumpTypeRegular},
+				{Start: 3, Length: 1, FileIndex: 0, Jump: JumpTypeIn},
+				{Start: 3, Length: 1, FileIndex: 0, Jump: JumpTypeOut, ModifierDepth: 1},
+				{Start: -1, Length: 0, FileIndex: -1, Jump: JumpTypeOut, ModifierDepth: 1},
+			},
+		},
+		{
+			name:      "Invalid number",
+			sourceMap: "1:a:0",
+			wantErr:   true,
+		},
+		{
+			name:      "Invalid jump type",
+			sourceMap: "1:2:0:x",
+			wantErr:   true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			entries, err := Decode(testCase.sourceMap)
+			if testCase.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expected, entries)
+			assert.False(t, entries[len(entries)-1].HasSource())
+		})
+	}
+}
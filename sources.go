@@ -43,6 +43,18 @@ func GetLocalSourcesPath() string {
 	return localSourcesPath
 }
 
+// getDefaultSourcesPath returns the global local sources path if set, otherwise it falls back
+// to the sources directory shipped alongside the solgo package.
+func getDefaultSourcesPath() string {
+	if GetLocalSourcesPath() != "" {
+		return GetLocalSourcesPath()
+	}
+
+	_, filename, _, _ := runtime.Caller(0)
+	dir := filepath.Dir(filename)
+	return filepath.Clean(filepath.Join(dir, "sources"))
+}
+
 // SourceUnit represents a unit of source code in Solidity. It includes the name, path, and content of the source code.
 type SourceUnit struct {
 	Name    string `yaml:"name" json:"name"`
@@ -93,6 +105,10 @@ type Sources struct {
 	LocalSources         bool          `yaml:"local_sources" json:"local_sources"`
 	MaskLocalSourcesPath bool          `yaml:"mask_local_sources_path" json:"mask_local_sources_path"`
 	LocalSourcesPath     string        `yaml:"local_sources_path" json:"local_sources_path"`
+
+	// Settings are the compiler settings (optimizer, evm version, remappings, libraries...) that the sources
+	// were compiled with. They are populated from standard json inputs and metadata when available.
+	Settings *StandardJsonSettings `yaml:"settings,omitempty" json:"settings,omitempty"`
 }
 
 // ArePrepared returns true if the Sources has been prepared.
@@ -130,15 +146,7 @@ func NewSourcesFromPath(entrySourceUnitName, path string) (*Sources, error) {
 		return nil, fmt.Errorf("path is not a directory: %s", path)
 	}
 
-	var sourcesDir string
-
-	if GetLocalSourcesPath() == "" {
-		_, filename, _, _ := runtime.Caller(0)
-		dir := filepath.Dir(filename)
-		sourcesDir = filepath.Clean(filepath.Join(dir, "sources"))
-	} else {
-		sourcesDir = GetLocalSourcesPath()
-	}
+	sourcesDir := getDefaultSourcesPath()
 
 	sources := &Sources{
 		MaskLocalSourcesPath: true,
@@ -184,20 +192,13 @@ func NewSourcesFromPath(entrySourceUnitName, path string) (*Sources, error) {
 // NewSourcesFromMetadata creates a Sources from a metadata package ContractMetadata.
 // This is a helper function that ensures easier integration when working with the metadata package.
 func NewSourcesFromMetadata(md *metadata.ContractMetadata) *Sources {
-	var sourcesDir string
-
-	if GetLocalSourcesPath() == "" {
-		_, filename, _, _ := runtime.Caller(0)
-		dir := filepath.Dir(filename)
-		sourcesDir = filepath.Clean(filepath.Join(dir, "sources"))
-	} else {
-		sourcesDir = GetLocalSourcesPath()
-	}
+	sourcesDir := getDefaultSourcesPath()
 
 	sources := &Sources{
 		MaskLocalSourcesPath: true,
 		LocalSourcesPath:     sourcesDir,
 		LocalSources:         false,
+		Settings:             NewStandardJsonSettingsFromMetadata(md),
 	}
 
 	// First target is the target of the entry source unit...
@@ -220,15 +221,7 @@ func NewSourcesFromMetadata(md *metadata.ContractMetadata) *Sources {
 }
 
 func NewSourcesFromProto(entryContractName string, sc *sources_pb.Sources) (*Sources, error) {
-	var sourcesDir string
-
-	if GetLocalSourcesPath() == "" {
-		_, filename, _, _ := runtime.Caller(0)
-		dir := filepath.Dir(filename)
-		sourcesDir = filepath.Clean(filepath.Join(dir, "sources"))
-	} else {
-		sourcesDir = GetLocalSourcesPath()
-	}
+	sourcesDir := getDefaultSourcesPath()
 
 	sources := &Sources{
 		MaskLocalSourcesPath: true,
@@ -256,15 +249,7 @@ func NewSourcesFromProto(entryContractName string, sc *sources_pb.Sources) (*Sou
 // This is a helper function that ensures easier integration when working with the EtherScan provider.
 // This includes BscScan, and other equivalent from the same family.
 func NewSourcesFromEtherScan(entryContractName string, sc interface{}) (*Sources, error) {
-	var sourcesDir string
-
-	if GetLocalSourcesPath() == "" {
-		_, filename, _, _ := runtime.Caller(0)
-		dir := filepath.Dir(filename)
-		sourcesDir = filepath.Clean(filepath.Join(dir, "sources"))
-	} else {
-		sourcesDir = GetLocalSourcesPath()
-	}
+	sourcesDir := getDefaultSourcesPath()
 
 	sources := &Sources{
 		MaskLocalSourcesPath: true,
@@ -302,6 +287,8 @@ func NewSourcesFromEtherScan(entryContractName string, sc interface{}) (*Sources
 			})
 		}
 
+		sources.Settings = NewStandardJsonSettingsFromMetadata(&contractMetadata)
+
 	case metadata.ContractMetadata:
 		for name, source := range sourceCode.Sources {
 			sources.AppendSource(&SourceUnit{
@@ -311,6 +298,8 @@ func NewSourcesFromEtherScan(entryContractName string, sc interface{}) (*Sources
 			})
 		}
 
+		sources.Settings = NewStandardJsonSettingsFromMetadata(&sourceCode)
+
 		if err := sources.SortContracts(); err != nil {
 			return nil, fmt.Errorf("failure while doing topological contract sorting: %s", err.Error())
 		}
@@ -0,0 +1,295 @@
+package solgo
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/goccy/go-json"
+	"github.com/unpackdev/solgo/metadata"
+)
+
+// DefaultOutputSelection is the output selection used when exporting Sources as solc Standard JSON Input
+// and no output selection has been explicitly provided. It requests everything required for bytecode
+// verification, source map decoding and storage layout analysis.
+var DefaultOutputSelection = map[string]map[string][]string{
+	"*": {
+		"*": []string{
+			"abi",
+			"metadata",
+			"evm.bytecode",
+			"evm.deployedBytecode",
+			"evm.methodIdentifiers",
+			"storageLayout",
+		},
+		"": []string{"ast"},
+	},
+}
+
+// StandardJsonSource represents a single source entry within the solc Standard JSON Input.
+type StandardJsonSource struct {
+	Content   string   `json:"content,omitempty" yaml:"content,omitempty"`
+	Keccak256 string   `json:"keccak256,omitempty" yaml:"keccak256,omitempty"`
+	Urls      []string `json:"urls,omitempty" yaml:"urls,omitempty"`
+}
+
+// StandardJsonOptimizer represents optimizer settings of the solc Standard JSON Input.
+// Details are kept as a generic map as their shape changes between compiler versions.
+type StandardJsonOptimizer struct {
+	Enabled bool                   `json:"enabled" yaml:"enabled"`
+	Runs    int                    `json:"runs" yaml:"runs"`
+	Details map[string]interface{} `json:"details,omitempty" yaml:"details,omitempty"`
+}
+
+// StandardJsonMetadataSettings represents metadata settings of the solc Standard JSON Input.
+type StandardJsonMetadataSettings struct {
+	UseLiteralContent bool   `json:"useLiteralContent,omitempty" yaml:"useLiteralContent,omitempty"`
+	BytecodeHash      string `json:"bytecodeHash,omitempty" yaml:"bytecodeHash,omitempty"`
+	AppendCBOR        *bool  `json:"appendCBOR,omitempty" yaml:"appendCBOR,omitempty"`
+}
+
+// StandardJsonSettings represents the settings section of the solc Standard JSON Input.
+// It carries everything that matters for reproducible compilation of the sources.
+type StandardJsonSettings struct {
+	Remappings      []string                       `json:"remappings,omitempty" yaml:"remappings,omitempty"`
+	Optimizer       *StandardJsonOptimizer         `json:"optimizer,omitempty" yaml:"optimizer,omitempty"`
+	EvmVersion      string                         `json:"evmVersion,omitempty" yaml:"evmVersion,omitempty"`
+	ViaIR           bool                           `json:"viaIR,omitempty" yaml:"viaIR,omitempty"`
+	Libraries       map[string]map[string]string   `json:"libraries,omitempty" yaml:"libraries,omitempty"`
+	Metadata        *StandardJsonMetadataSettings  `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+	OutputSelection map[string]map[string][]string `json:"outputSelection,omitempty" yaml:"outputSelection,omitempty"`
+}
+
+// StandardJsonInput represents the solc Standard JSON Input.
+// See https://docs.soliditylang.org/en/latest/using-the-compiler.html#input-description
+type StandardJsonInput struct {
+	Language string                        `json:"language" yaml:"language"`
+	Sources  map[string]StandardJsonSource `json:"sources" yaml:"sources"`
+	Settings *StandardJsonSettings         `json:"settings,omitempty" yaml:"settings,omitempty"`
+}
+
+// ToJSON returns the JSON encoding of the Standard JSON Input as accepted by `solc --standard-json`.
+func (i *StandardJsonInput) ToJSON() ([]byte, error) {
+	return json.Marshal(i)
+}
+
+// GetSettings returns the settings of the Standard JSON Input.
+func (i *StandardJsonInput) GetSettings() *StandardJsonSettings {
+	return i.Settings
+}
+
+// NewStandardJsonInputFromBytes decodes solc Standard JSON Input from raw JSON bytes.
+// Etherscan wraps standard JSON responses into double curly braces, which are handled as well.
+func NewStandardJsonInputFromBytes(data []byte) (*StandardJsonInput, error) {
+	trimmed := strings.TrimSpace(string(data))
+	if strings.HasPrefix(trimmed, "{{") && strings.HasSuffix(trimmed, "}}") {
+		trimmed = trimmed[1 : len(trimmed)-1]
+	}
+
+	var input StandardJsonInput
+	if err := json.Unmarshal([]byte(trimmed), &input); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal standard json input: %w", err)
+	}
+
+	if len(input.Sources) == 0 {
+		return nil, errors.New("standard json input does not contain any sources")
+	}
+
+	if input.Language == "" {
+		input.Language = "Solidity"
+	}
+
+	return &input, nil
+}
+
+// NewStandardJsonSettingsFromMetadata builds Standard JSON settings out of the contract metadata.
+// Libraries are accepted in both metadata (`file:Library` => address) and standard json
+// (`file` => `Library` => address) representation.
+func NewStandardJsonSettingsFromMetadata(md *metadata.ContractMetadata) *StandardJsonSettings {
+	if md == nil {
+		return nil
+	}
+
+	settings := &StandardJsonSettings{
+		Remappings: md.Settings.Remappings,
+		EvmVersion: md.Settings.EvmVersion,
+		ViaIR:      md.Settings.ViaIR,
+		Optimizer: &StandardJsonOptimizer{
+			Enabled: md.Settings.Optimizer.Enabled,
+			Runs:    md.Settings.Optimizer.Runs,
+			Details: getOptimizerDetails(md),
+		},
+		Libraries: normalizeLibraries(md.Settings.Libraries),
+	}
+
+	if md.Settings.Metadata.BytecodeHash != "" || md.Settings.Metadata.UseLiteralContent || md.Settings.Metadata.AppendCBOR {
+		settings.Metadata = &StandardJsonMetadataSettings{
+			BytecodeHash:      md.Settings.Metadata.BytecodeHash,
+			UseLiteralContent: md.Settings.Metadata.UseLiteralContent,
+		}
+
+		// AppendCBOR defaults to true in solc and decoding does not tell us whether it was omitted,
+		// so we only forward it when it is explicitly enabled.
+		if md.Settings.Metadata.AppendCBOR {
+			appendCbor := true
+			settings.Metadata.AppendCBOR = &appendCbor
+		}
+	}
+
+	return settings
+}
+
+// getOptimizerDetails returns the optimizer details of the metadata, nil when none of them is set. Decoded
+// metadata does not tell the omitted details from the disabled ones, so the details are only forwarded when at
+// least one of them is enabled. Optimizer steps are not forwarded as the metadata does not decode them.
+func getOptimizerDetails(md *metadata.ContractMetadata) map[string]interface{} {
+	details := md.Settings.Optimizer.Details
+	if details == (metadata.ContractMetadata{}).Settings.Optimizer.Details {
+		return nil
+	}
+
+	return map[string]interface{}{
+		"peephole":          details.Peephole,
+		"inliner":           details.Inliner,
+		"jumpdestRemover":   details.JumpdestRemover,
+		"orderLiterals":     details.OrderLiterals,
+		"deduplicate":       details.Deduplicate,
+		"cse":               details.Cse,
+		"constantOptimizer": details.ConstantOptimizer,
+		"yul":               details.Yul,
+		"yulDetails": map[string]interface{}{
+			"stackAllocation": details.YulDetails.StackAllocation,
+		},
+	}
+}
+
+// cloneOutputSelection returns the deep copy of the output selection, so the exported input can be modified
+// without affecting the selection it was created with.
+func cloneOutputSelection(selection map[string]map[string][]string) map[string]map[string][]string {
+	cloned := make(map[string]map[string][]string, len(selection))
+	for file, contracts := range selection {
+		cloned[file] = make(map[string][]string, len(contracts))
+		for contract, outputs := range contracts {
+			cloned[file][contract] = append([]string{}, outputs...)
+		}
+	}
+	return cloned
+}
+
+// ToStandardJSON exports the Sources as solc Standard JSON Input.
+// Source units are keyed by their path, falling back to `<name>.sol` when path is not known.
+// Settings attached to the sources are carried over and default output selection is applied
+// when none is set.
+func (s *Sources) ToStandardJSON() (*StandardJsonInput, error) {
+	if !s.HasUnits() {
+		return nil, errors.New("no source units found")
+	}
+
+	input := &StandardJsonInput{
+		Language: "Solidity",
+		Sources:  make(map[string]StandardJsonSource),
+		Settings: &StandardJsonSettings{},
+	}
+
+	if s.Settings != nil {
+		settings := *s.Settings
+		input.Settings = &settings
+	}
+
+	if input.Settings.OutputSelection == nil {
+		input.Settings.OutputSelection = cloneOutputSelection(DefaultOutputSelection)
+	}
+
+	for _, unit := range s.SourceUnits {
+		input.Sources[unit.GetStandardJsonPath()] = StandardJsonSource{
+			Content: unit.GetContent(),
+		}
+	}
+
+	return input, nil
+}
+
+// GetSettings returns the compiler settings associated with the Sources, if any.
+func (s *Sources) GetSettings() *StandardJsonSettings {
+	return s.Settings
+}
+
+// SetSettings sets the compiler settings used when exporting the Sources as Standard JSON Input.
+func (s *Sources) SetSettings(settings *StandardJsonSettings) {
+	s.Settings = settings
+}
+
+// GetStandardJsonPath returns the key under which the SourceUnit is placed in the Standard JSON Input.
+func (s *SourceUnit) GetStandardJsonPath() string {
+	if s.Path != "" {
+		return s.Path
+	}
+
+	return s.Name + ".sol"
+}
+
+// NewSourcesFromStandardJSON creates a Sources from solc Standard JSON Input.
+// Compiler settings are preserved on the Sources so that they can be exported back without loss.
+func NewSourcesFromStandardJSON(entrySourceUnitName string, input *StandardJsonInput) (*Sources, error) {
+	if input == nil {
+		return nil, errors.New("standard json input must be provided")
+	}
+
+	sources := &Sources{
+		MaskLocalSourcesPath: true,
+		LocalSourcesPath:     getDefaultSourcesPath(),
+		EntrySourceUnitName:  entrySourceUnitName,
+		LocalSources:         false,
+		Settings:             input.Settings,
+	}
+
+	for name, source := range input.Sources {
+		sources.AppendSource(&SourceUnit{
+			Name:    strings.TrimSuffix(filepath.Base(name), ".sol"),
+			Path:    name,
+			Content: source.Content,
+		})
+	}
+
+	if err := sources.SortContracts(); err != nil {
+		return nil, fmt.Errorf("failure while doing topological contract sorting: %s", err.Error())
+	}
+
+	return sources, nil
+}
+
+// normalizeLibraries converts libraries from any of the known representations into
+// standard json `file` => `Library` => address representation.
+func normalizeLibraries(libraries interface{}) map[string]map[string]string {
+	raw, ok := libraries.(map[string]interface{})
+	if !ok || len(raw) == 0 {
+		return nil
+	}
+
+	toReturn := make(map[string]map[string]string)
+	for key, value := range raw {
+		switch v := value.(type) {
+		case string:
+			file, name := "", key
+			if idx := strings.LastIndex(key, ":"); idx >= 0 {
+				file, name = key[:idx], key[idx+1:]
+			}
+			if _, ok := toReturn[file]; !ok {
+				toReturn[file] = make(map[string]string)
+			}
+			toReturn[file][name] = v
+		case map[string]interface{}:
+			if _, ok := toReturn[key]; !ok {
+				toReturn[key] = make(map[string]string)
+			}
+			for name, addr := range v {
+				if addrStr, ok := addr.(string); ok {
+					toReturn[key][name] = addrStr
+				}
+			}
+		}
+	}
+
+	return toReturn
+}
@@ -0,0 +1,222 @@
+package solgo
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/goccy/go-json"
+)
+
+// StandardJsonSourceLocation represents a location within a source file as reported by the compiler.
+type StandardJsonSourceLocation struct {
+	File  string `json:"file"`
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+}
+
+// StandardJsonError represents an error, warning or info message produced by the compiler.
+type StandardJsonError struct {
+	Component        string                      `json:"component"`
+	ErrorCode        string                      `json:"errorCode"`
+	FormattedMessage string                      `json:"formattedMessage"`
+	Message          string                      `json:"message"`
+	Severity         string                      `json:"severity"`
+	Type             string                      `json:"type"`
+	SourceLocation   *StandardJsonSourceLocation `json:"sourceLocation,omitempty"`
+}
+
+// IsError returns true if the message severity is an error.
+func (e *StandardJsonError) IsError() bool {
+	return e.Severity == "error"
+}
+
+// StandardJsonReference represents a byte range within the bytecode, used for link and immutable references.
+type StandardJsonReference struct {
+	Start  int `json:"start"`
+	Length int `json:"length"`
+}
+
+// StandardJsonBytecode represents `evm.bytecode` and `evm.deployedBytecode` outputs.
+// LinkReferences are keyed by source file and then by library name, while ImmutableReferences
+// are keyed by AST id of the immutable variable and are only present for deployed bytecode.
+type StandardJsonBytecode struct {
+	FunctionDebugData   map[string]interface{}                        `json:"functionDebugData,omitempty"`
+	Object              string                                        `json:"object"`
+	Opcodes             string                                        `json:"opcodes"`
+	SourceMap           string                                        `json:"sourceMap"`
+	GeneratedSources    []json.RawMessage                             `json:"generatedSources,omitempty"`
+	LinkReferences      map[string]map[string][]StandardJsonReference `json:"linkReferences,omitempty"`
+	ImmutableReferences map[string][]StandardJsonReference            `json:"immutableReferences,omitempty"`
+}
+
+// GetObject returns the hex encoded bytecode object without the 0x prefix.
+func (b *StandardJsonBytecode) GetObject() string {
+	return strings.TrimPrefix(b.Object, "0x")
+}
+
+// StandardJsonEvm represents the `evm` output of a contract.
+type StandardJsonEvm struct {
+	Assembly          string               `json:"assembly,omitempty"`
+	Bytecode          StandardJsonBytecode `json:"bytecode"`
+	DeployedBytecode  StandardJsonBytecode `json:"deployedBytecode"`
+	MethodIdentifiers map[string]string    `json:"methodIdentifiers,omitempty"`
+}
+
+// StandardJsonStorage represents a single storage entry of the compiler storage layout output.
+type StandardJsonStorage struct {
+	AstId    int64  `json:"astId"`
+	Contract string `json:"contract"`
+	Label    string `json:"label"`
+	Offset   int64  `json:"offset"`
+	Slot     string `json:"slot"`
+	Type     string `json:"type"`
+}
+
+// StandardJsonStorageType represents a type description of the compiler storage layout output.
+type StandardJsonStorageType struct {
+	Encoding      string                `json:"encoding"`
+	Label         string                `json:"label"`
+	NumberOfBytes string                `json:"numberOfBytes"`
+	Base          string                `json:"base,omitempty"`
+	Key           string                `json:"key,omitempty"`
+	Value         string                `json:"value,omitempty"`
+	Members       []StandardJsonStorage `json:"members,omitempty"`
+}
+
+// StandardJsonStorageLayout represents the `storageLayout` output of a contract.
+type StandardJsonStorageLayout struct {
+	Storage []StandardJsonStorage              `json:"storage"`
+	Types   map[string]StandardJsonStorageType `json:"types"`
+}
+
+// StandardJsonContract represents the compiler output of a single contract.
+type StandardJsonContract struct {
+	Abi           json.RawMessage            `json:"abi,omitempty"`
+	Metadata      string                     `json:"metadata,omitempty"`
+	Evm           StandardJsonEvm            `json:"evm"`
+	StorageLayout *StandardJsonStorageLayout `json:"storageLayout,omitempty"`
+	Devdoc        json.RawMessage            `json:"devdoc,omitempty"`
+	Userdoc       json.RawMessage            `json:"userdoc,omitempty"`
+}
+
+// GetABI parses the contract ABI output into the go-ethereum ABI representation.
+func (c *StandardJsonContract) GetABI() (*abi.ABI, error) {
+	if len(c.Abi) == 0 {
+		return nil, errors.New("contract abi not present in compiler output")
+	}
+
+	parsed, err := abi.JSON(strings.NewReader(string(c.Abi)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse contract abi: %w", err)
+	}
+
+	return &parsed, nil
+}
+
+// GetBytecode returns the creation bytecode output of the contract.
+func (c *StandardJsonContract) GetBytecode() *StandardJsonBytecode {
+	return &c.Evm.Bytecode
+}
+
+// GetDeployedBytecode returns the deployed (runtime) bytecode output of the contract.
+func (c *StandardJsonContract) GetDeployedBytecode() *StandardJsonBytecode {
+	return &c.Evm.DeployedBytecode
+}
+
+// GetStorageLayout returns the storage layout output of the contract.
+func (c *StandardJsonContract) GetStorageLayout() *StandardJsonStorageLayout {
+	return c.StorageLayout
+}
+
+// StandardJsonSourceOutput represents the per source file compiler output.
+// AST is kept in its raw form and can be decoded on demand.
+type StandardJsonSourceOutput struct {
+	ID  int             `json:"id"`
+	AST json.RawMessage `json:"ast,omitempty"`
+}
+
+// StandardJsonOutput represents the solc Standard JSON Output.
+// See https://docs.soliditylang.org/en/latest/using-the-compiler.html#output-description
+type StandardJsonOutput struct {
+	Errors    []StandardJsonError                         `json:"errors,omitempty"`
+	Sources   map[string]StandardJsonSourceOutput         `json:"sources,omitempty"`
+	Contracts map[string]map[string]*StandardJsonContract `json:"contracts,omitempty"`
+}
+
+// NewStandardJsonOutputFromBytes decodes solc Standard JSON Output from raw JSON bytes.
+func NewStandardJsonOutputFromBytes(data []byte) (*StandardJsonOutput, error) {
+	var output StandardJsonOutput
+	if err := json.Unmarshal(data, &output); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal standard json output: %w", err)
+	}
+
+	return &output, nil
+}
+
+// HasErrors returns true if compiler output contains at least one message with error severity.
+func (o *StandardJsonOutput) HasErrors() bool {
+	for _, err := range o.Errors {
+		if err.IsError() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetErrors returns only messages with error severity.
+func (o *StandardJsonOutput) GetErrors() []StandardJsonError {
+	var toReturn []StandardJsonError
+	for _, err := range o.Errors {
+		if err.IsError() {
+			toReturn = append(toReturn, err)
+		}
+	}
+	return toReturn
+}
+
+// GetContract returns the contract output with the given name alongside the source file it was declared in.
+// Name can be a plain contract name or fully qualified `file:Contract` name. Plain names declared in multiple
+// files resolve to the contract of the first file in the lexical order.
+func (o *StandardJsonOutput) GetContract(name string) (*StandardJsonContract, string) {
+	file, contractName := "", name
+	if idx := strings.LastIndex(name, ":"); idx >= 0 {
+		file, contractName = name[:idx], name[idx+1:]
+	}
+
+	if file != "" {
+		contract, ok := o.Contracts[file][contractName]
+		if !ok {
+			return nil, ""
+		}
+		return contract, file
+	}
+
+	sourceFiles := make([]string, 0, len(o.Contracts))
+	for sourceFile := range o.Contracts {
+		sourceFiles = append(sourceFiles, sourceFile)
+	}
+	sort.Strings(sourceFiles)
+
+	for _, sourceFile := range sourceFiles {
+		if contract, ok := o.Contracts[sourceFile][contractName]; ok {
+			return contract, sourceFile
+		}
+	}
+
+	return nil, ""
+}
+
+// GetSourceById returns the source file name for the given source index as used within source maps.
+func (o *StandardJsonOutput) GetSourceById(id int) (string, bool) {
+	for name, source := range o.Sources {
+		if source.ID == id {
+			return name, true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,204 @@
+package solgo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unpackdev/solgo/metadata"
+)
+
+func TestStandardJsonInput(t *testing.T) {
+	testCases := []struct {
+		name          string
+		input         string
+		entry         string
+		expectedUnits int
+		wantErr       bool
+		evmVersion    string
+		viaIR         bool
+		libraries     map[string]map[string]string
+	}{
+		{
+			name: "Standard JSON with settings",
+			input: `{
+				"language": "Solidity",
+				"sources": {
+					"contracts/Token.sol": {"content": "pragma solidity ^0.8.0;\nimport \"./Lib.sol\";\ncontract Token {}"},
+					"contracts/Lib.sol": {"content": "pragma solidity ^0.8.0;\nlibrary Lib {}"}
+				},
+				"settings": {
+					"remappings": ["@openzeppelin/=lib/openzeppelin/"],
+					"optimizer": {"enabled": true, "runs": 200},
+					"evmVersion": "paris",
+					"viaIR": true,
+					"libraries": {"contracts/Lib.sol": {"Lib": "0x0000000000000000000000000000000000000001"}},
+					"metadata": {"bytecodeHash": "ipfs"}
+				}
+			}`,
+			entry:         "Token",
+			expectedUnits: 2,
+			evmVersion:    "paris",
+			viaIR:         true,
+			libraries: map[string]map[string]string{
+				"contracts/Lib.sol": {"Lib": "0x0000000000000000000000000000000000000001"},
+			},
+		},
+		{
+			name:          "Etherscan double braces",
+			input:         `{{"language": "Solidity", "sources": {"A.sol": {"content": "contract A {}"}}}}`,
+			entry:         "A",
+			expectedUnits: 1,
+		},
+		{
+			name:    "No sources",
+			input:   `{"language": "Solidity", "sources": {}}`,
+			wantErr: true,
+		},
+		{
+			name:    "Invalid JSON",
+			input:   `{"language":`,
+			wantErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			input, err := NewStandardJsonInputFromBytes([]byte(testCase.input))
+			if testCase.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, input)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, input)
+
+			sources, err := NewSourcesFromStandardJSON(testCase.entry, input)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expectedUnits, len(sources.GetUnits()))
+
+			exported, err := sources.ToStandardJSON()
+			require.NoError(t, err)
+			assert.Equal(t, len(input.Sources), len(exported.Sources))
+			assert.NotNil(t, exported.Settings.OutputSelection)
+
+			for path, source := range input.Sources {
+				assert.Equal(t, source.Content, exported.Sources[path].Content)
+			}
+
+			if testCase.evmVersion != "" {
+				assert.Equal(t, testCase.evmVersion, exported.Settings.EvmVersion)
+				assert.Equal(t, testCase.viaIR, exported.Settings.ViaIR)
+				assert.Equal(t, testCase.libraries, exported.Settings.Libraries)
+				assert.True(t, exported.Settings.Optimizer.Enabled)
+				assert.Equal(t, 200, exported.Settings.Optimizer.Runs)
+			}
+
+			encoded, err := exported.ToJSON()
+			assert.NoError(t, err)
+			assert.NotEmpty(t, encoded)
+		})
+	}
+}
+
+func TestStandardJsonSettingsFromMetadata(t *testing.T) {
+	md := &metadata.ContractMetadata{}
+	md.Settings.EvmVersion = "shanghai"
+	md.Settings.ViaIR = true
+	md.Settings.Optimizer.Enabled = true
+	md.Settings.Optimizer.Runs = 1000
+	md.Settings.Optimizer.Details.Peephole = true
+	md.Settings.Optimizer.Details.Yul = true
+	md.Settings.Metadata.BytecodeHash = "none"
+	md.Settings.Libraries = map[string]interface{}{
+		"contracts/Lib.sol:Lib": "0x0000000000000000000000000000000000000002",
+	}
+
+	settings := NewStandardJsonSettingsFromMetadata(md)
+	require.NotNil(t, settings)
+	assert.Equal(t, "shanghai", settings.EvmVersion)
+	assert.True(t, settings.ViaIR)
+	assert.Equal(t, 1000, settings.Optimizer.Runs)
+	assert.Equal(t, true, settings.Optimizer.Details["peephole"])
+	assert.Equal(t, false, settings.Optimizer.Details["inliner"])
+	assert.Equal(t, true, settings.Optimizer.Details["yul"])
+	assert.Equal(t, "none", settings.Metadata.BytecodeHash)
+	assert.Equal(t, "0x0000000000000000000000000000000000000002", settings.Libraries["contracts/Lib.sol"]["Lib"])
+
+	// Details are not forwarded when none of them is set.
+	md.Settings.Optimizer.Details = (&metadata.ContractMetadata{}).Settings.Optimizer.Details
+	assert.Nil(t, NewStandardJsonSettingsFromMetadata(md).Optimizer.Details)
+}
+
+func TestStandardJsonDefaultOutputSelection(t *testing.T) {
+	sources := &Sources{
+		SourceUnits: []*SourceUnit{{Name: "Token", Path: "contracts/Token.sol", Content: "contract Token {}"}},
+	}
+
+	input, err := sources.ToStandardJSON()
+	require.NoError(t, err)
+	assert.Equal(t, DefaultOutputSelection, input.Settings.OutputSelection)
+
+	// Modifying the exported selection leaves the default selection intact.
+	input.Settings.OutputSelection["*"]["*"][0] = "evm.assembly"
+	input.Settings.OutputSelection["*"][""] = nil
+	assert.Equal(t, "abi", DefaultOutputSelection["*"]["*"][0])
+	assert.Equal(t, []string{"ast"}, DefaultOutputSelection["*"][""])
+}
+
+func TestStandardJsonOutput(t *testing.T) {
+	raw := `{
+		"errors": [{"severity": "warning", "message": "unused variable"}],
+		"sources": {"contracts/Token.sol": {"id": 0}},
+		"contracts": {
+			"contracts/Token.sol": {
+				"Token": {
+					"abi": [{"type":"function","name":"totalSupply","inputs":[],"outputs":[{"name":"","type":"uint256"}],"stateMutability":"view"}],
+					"evm": {
+						"bytecode": {"object": "6080", "sourceMap": "0:1:0:-:0", "linkReferences": {"contracts/Lib.sol": {"Lib": [{"start": 10, "length": 20}]}}},
+						"deployedBytecode": {"object": "6080", "immutableReferences": {"12": [{"start": 5, "length": 32}]}}
+					},
+					"storageLayout": {
+						"storage": [{"astId": 3, "contract": "contracts/Token.sol:Token", "label": "supply", "offset": 0, "slot": "0", "type": "t_uint256"}],
+						"types": {"t_uint256": {"encoding": "inplace", "label": "uint256", "numberOfBytes": "32"}}
+					}
+				}
+			}
+		}
+	}`
+
+	output, err := NewStandardJsonOutputFromBytes([]byte(raw))
+	require.NoError(t, err)
+	assert.False(t, output.HasErrors())
+	assert.Empty(t, output.GetErrors())
+
+	contract, file := output.GetContract("Token")
+	require.NotNil(t, contract)
+	assert.Equal(t, "contracts/Token.sol", file)
+
+	qualified, _ := output.GetContract("contracts/Token.sol:Token")
+	assert.Equal(t, contract, qualified)
+
+	missing, _ := output.GetContract("contracts/Other.sol:Token")
+	assert.Nil(t, missing)
+
+	// Plain names declared in multiple files always resolve to the same contract.
+	output.Contracts["contracts/A.sol"] = map[string]*StandardJsonContract{"Token": {}}
+	output.Contracts["contracts/Z.sol"] = map[string]*StandardJsonContract{"Token": {}}
+	for i := 0; i < 10; i++ {
+		_, file = output.GetContract("Token")
+		assert.Equal(t, "contracts/A.sol", file)
+	}
+
+	parsedAbi, err := contract.GetABI()
+	require.NoError(t, err)
+	assert.Contains(t, parsedAbi.Methods, "totalSupply")
+
+	assert.Equal(t, 20, contract.GetBytecode().LinkReferences["contracts/Lib.sol"]["Lib"][0].Length)
+	assert.Equal(t, 5, contract.GetDeployedBytecode().ImmutableReferences["12"][0].Start)
+	assert.Equal(t, "supply", contract.GetStorageLayout().Storage[0].Label)
+
+	name, found := output.GetSourceById(0)
+	assert.True(t, found)
+	assert.Equal(t, "contracts/Token.sol", name)
+}
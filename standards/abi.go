@@ -0,0 +1,161 @@
+package standards
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/goccy/go-json"
+)
+
+// abiParameter represents the input or output parameter of the JSON ABI entry.
+type abiParameter struct {
+	Name         string         `json:"name"`
+	Type         string         `json:"type"`
+	InternalType string         `json:"internalType,omitempty"`
+	Indexed      bool           `json:"indexed,omitempty"`
+	Components   []abiParameter `json:"components,omitempty"`
+}
+
+// abiEntry represents the single function, event, error or special function of the JSON ABI.
+type abiEntry struct {
+	Type      string         `json:"type"`
+	Name      string         `json:"name"`
+	Inputs    []abiParameter `json:"inputs"`
+	Outputs   []abiParameter `json:"outputs"`
+	Anonymous bool           `json:"anonymous,omitempty"`
+
+	// StateMutability is provided by solc 0.4.16 and above, older ABIs describe functions through
+	// the constant and payable flags instead.
+	StateMutability string `json:"stateMutability,omitempty"`
+	Constant        bool   `json:"constant,omitempty"`
+	Payable         bool   `json:"payable,omitempty"`
+}
+
+// getStateMutability returns the state mutability of the ABI function entry.
+func (e abiEntry) getStateMutability() string {
+	switch {
+	case e.StateMutability != "":
+		return e.StateMutability
+	case e.Constant:
+		return "view"
+	case e.Payable:
+		return "payable"
+	default:
+		return "nonpayable"
+	}
+}
+
+// NewContractStandardFromABI builds the ContractStandard out of the JSON ABI of the standard interface.
+// Functions and events of the ABI become the standard functions and events, while constructor, errors,
+// fallback and receive entries are ignored as they do not take part in the confidence check.
+// Parameter types are expressed the same way the Solidity compiler describes them, so tuples are
+// represented by their struct name when the ABI carries the internal type.
+func NewContractStandardFromABI(standard Standard, name string, url string, abi []byte) (ContractStandard, error) {
+	entries := make([]abiEntry, 0)
+	if err := json.Unmarshal(abi, &entries); err != nil {
+		return ContractStandard{}, fmt.Errorf("%w: failed to decode abi for standard %s: %s", ErrInvalidDefinition, standard, err)
+	}
+
+	compacted := new(bytes.Buffer)
+	if err := json.Compact(compacted, abi); err != nil {
+		return ContractStandard{}, fmt.Errorf("%w: failed to compact abi for standard %s: %s", ErrInvalidDefinition, standard, err)
+	}
+
+	toReturn := ContractStandard{
+		Name:      name,
+		Url:       url,
+		Type:      standard,
+		ABI:       compacted.String(),
+		Functions: make([]Function, 0),
+		Events:    make([]Event, 0),
+	}
+
+	for _, entry := range entries {
+		switch entry.Type {
+		case "function":
+			inputs := make([]Input, 0, len(entry.Inputs))
+			for _, input := range entry.Inputs {
+				inputs = append(inputs, Input{Type: abiParameterType(input)})
+			}
+
+			outputs := make([]Output, 0, len(entry.Outputs))
+			for _, output := range entry.Outputs {
+				outputs = append(outputs, Output{Type: abiParameterType(output)})
+			}
+
+			fn := newFunction(entry.Name, inputs, outputs)
+			fn.StateMutability = entry.getStateMutability()
+			toReturn.Functions = append(toReturn.Functions, fn)
+		case "event":
+			inputs := make([]Input, 0, len(entry.Inputs))
+			for _, input := range entry.Inputs {
+				inputs = append(inputs, Input{Type: abiParameterType(input), Indexed: input.Indexed})
+			}
+
+			toReturn.Events = append(toReturn.Events, newEvent(entry.Name, inputs, nil))
+		}
+	}
+
+	if len(toReturn.Functions) == 0 && len(toReturn.Events) == 0 {
+		return ContractStandard{}, fmt.Errorf("%w: abi for standard %s has no functions or events", ErrInvalidDefinition, standard)
+	}
+
+	return toReturn, nil
+}
+
+// abiParameterType returns the type of the ABI parameter as it is used within the standard.
+func abiParameterType(param abiParameter) string {
+	if !strings.HasPrefix(param.Type, "tuple") {
+		return param.Type
+	}
+
+	if strings.HasPrefix(param.InternalType, "struct ") {
+		return param.InternalType
+	}
+
+	components := make([]string, 0, len(param.Components))
+	for _, component := range param.Components {
+		components = append(components, abiParameterType(component))
+	}
+
+	return "(" + strings.Join(components, ",") + ")" + strings.TrimPrefix(param.Type, "tuple")
+}
+
+// withStateMutability returns the copy of the contract standard with the state mutability of functions that do not
+// declare it completed from the standard ABI. Functions are looked up by their name and input types, and the
+// standard is returned as it is when the ABI cannot be decoded.
+func withStateMutability(cs ContractStandard) ContractStandard {
+	if cs.ABI == "" {
+		return cs
+	}
+
+	entries := make([]abiEntry, 0)
+	if err := json.Unmarshal([]byte(cs.ABI), &entries); err != nil {
+		return cs
+	}
+
+	mutabilities := make(map[string]string)
+	for _, entry := range entries {
+		if entry.Type != "function" {
+			continue
+		}
+
+		inputs := make([]Input, 0, len(entry.Inputs))
+		for _, input := range entry.Inputs {
+			inputs = append(inputs, Input{Type: abiParameterType(input)})
+		}
+		mutabilities[memberSignature(entry.Name, inputs)] = entry.getStateMutability()
+	}
+
+	functions := make([]Function, 0, len(cs.Functions))
+	for _, fn := range cs.Functions {
+		if fn.StateMutability == "" {
+			fn.StateMutability = mutabilities[memberSignature(fn.Name, fn.Inputs)]
+		}
+		functions = append(functions, fn)
+	}
+	cs.Functions = functions
+
+	return cs
+}
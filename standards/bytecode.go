@@ -0,0 +1,340 @@
+package standards
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/unpackdev/solgo/opcode"
+	"github.com/unpackdev/solgo/utils"
+)
+
+// BytecodeMatcher represents an Ethereum smart contract known only by its runtime bytecode that attempts to confirm
+// to a standard interface. Used while performing a contract standard detection for contracts without verified source.
+type BytecodeMatcher struct {
+	// Name of the contract, typically its address.
+	Name string `json:"name"`
+
+	// Selectors lists the 4-byte function selectors found within the contract dispatcher, e.g. "0xa9059cbb".
+	Selectors []string `json:"selectors"`
+
+	// Topics lists the event signature hashes found within the contract bytecode.
+	Topics []string `json:"topics"`
+
+	// Interfaces lists the ERC-165 interface identifiers the contract confirmed through supportsInterface.
+	Interfaces []string `json:"interfaces"`
+}
+
+// NewBytecodeMatcherFromBytecode decompiles the runtime bytecode and builds the BytecodeMatcher out of the
+// dispatcher selectors and the event topics pushed by the contract.
+func NewBytecodeMatcherFromBytecode(ctx context.Context, name string, bytecode []byte) (*BytecodeMatcher, error) {
+	decompiler, err := opcode.NewDecompiler(ctx, bytecode)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := decompiler.Decompile(); err != nil {
+		return nil, err
+	}
+
+	return &BytecodeMatcher{
+		Name:       name,
+		Selectors:  decompiler.GetFunctionSelectors(),
+		Topics:     decompiler.GetEventTopics(),
+		Interfaces: make([]string, 0),
+	}, nil
+}
+
+// HasSelector returns true if the contract dispatcher contains the provided function selector.
+func (m *BytecodeMatcher) HasSelector(selector string) bool {
+	return containsHex(m.Selectors, selector)
+}
+
+// HasTopic returns true if the contract bytecode contains the provided event topic.
+func (m *BytecodeMatcher) HasTopic(topic string) bool {
+	return containsHex(m.Topics, topic)
+}
+
+// SupportsInterface returns true if the contract confirmed support of the provided ERC-165 interface identifier.
+func (m *BytecodeMatcher) SupportsInterface(interfaceId string) bool {
+	return interfaceId != "" && containsHex(m.Interfaces, interfaceId)
+}
+
+// BytecodeConfidenceCheck checks the confidence of a contract known only by its bytecode against a standard EIP.
+// A function is matched when its selector is found within the dispatcher, or when it is required and the contract
+// confirmed support of the standard ERC-165 interface, and an event is matched when its topic is found within the bytecode.
+// Selectors and topics identify the name and parameter types, while outputs and indexed flags cannot be recovered
+// from the bytecode, so matched entries are scored with all of their tokens. Members are weighted and explained
+// the same way as within the ConfidenceCheck.
+func BytecodeConfidenceCheck(standard EIP, contract *BytecodeMatcher) (Discovery, bool) {
+	toReturn := Discovery{
+		Standard:         standard.GetType(),
+		Confidence:       NoConfidence,
+		ConfidencePoints: 0,
+		Threshold:        NoConfidenceThreshold,
+		MaximumTokens:    standard.TokenCount(),
+		DiscoveredTokens: 0,
+		Contract: &ContractMatcher{
+			Name:      contract.Name,
+			Functions: make([]Function, 0),
+			Events:    make([]Event, 0),
+		},
+	}
+
+	cs := standard.GetStandard()
+	interfaceConfirmed := contract.SupportsInterface(cs.InterfaceId)
+	foundTokenCount := 0
+	score := &scorer{}
+
+	for _, standardFunction := range cs.Functions {
+		tokenCount := FunctionTokenCount(standardFunction)
+		selector, err := FunctionSelector(cs, standardFunction)
+		// Interface identifiers cover the required functions only, so the optional ones must be found in the dispatcher.
+		matched := err == nil && ((interfaceConfirmed && !standardFunction.Optional) || contract.HasSelector(selector))
+
+		switch {
+		case matched:
+			foundTokenCount += tokenCount
+			if standardFunction.Optional {
+				toReturn.MaximumTokens += tokenCount
+			}
+			score.add(Explanation{
+				Kind:             FunctionMember,
+				Name:             standardFunction.Name,
+				Signature:        memberSignature(standardFunction.Name, standardFunction.Inputs),
+				Status:           MatchStatusMatched,
+				Optional:         standardFunction.Optional,
+				MaximumTokens:    tokenCount,
+				DiscoveredTokens: tokenCount,
+			})
+		case err != nil:
+			score.add(explainMissing(FunctionMember, standardFunction.Name, standardFunction.Inputs, standardFunction.Optional, tokenCount, err.Error()))
+		default:
+			score.add(explainMissing(FunctionMember, standardFunction.Name, standardFunction.Inputs, standardFunction.Optional, tokenCount, fmt.Sprintf("selector %s not found in the dispatcher", selector)))
+		}
+
+		toReturn.Contract.Functions = append(toReturn.Contract.Functions, matchedFunction(standardFunction, matched))
+	}
+
+	for _, standardEvent := range cs.Events {
+		tokenCount := EventTokenCount(standardEvent)
+		topic, err := EventTopic(cs, standardEvent)
+		matched := err == nil && contract.HasTopic(topic)
+
+		switch {
+		case matched:
+			foundTokenCount += tokenCount
+			if standardEvent.Optional {
+				toReturn.MaximumTokens += tokenCount
+			}
+			score.add(Explanation{
+				Kind:             EventMember,
+				Name:             standardEvent.Name,
+				Signature:        memberSignature(standardEvent.Name, standardEvent.Inputs),
+				Status:           MatchStatusMatched,
+				Optional:         standardEvent.Optional,
+				MaximumTokens:    tokenCount,
+				DiscoveredTokens: tokenCount,
+			})
+		case err != nil:
+			score.add(explainMissing(EventMember, standardEvent.Name, standardEvent.Inputs, standardEvent.Optional, tokenCount, err.Error()))
+		default:
+			score.add(explainMissing(EventMember, standardEvent.Name, standardEvent.Inputs, standardEvent.Optional, tokenCount, fmt.Sprintf("topic %s not found in the bytecode", topic)))
+		}
+
+		toReturn.Contract.Events = append(toReturn.Contract.Events, matchedEvent(standardEvent, matched))
+	}
+
+	toReturn.DiscoveredTokens = foundTokenCount
+	toReturn.Explanations = score.explanations
+
+	confidencePoints := score.points()
+	level, threshold := cs.GetThresholds().Calculate(confidencePoints)
+	toReturn.Confidence = level
+	toReturn.ConfidencePoints = confidencePoints
+	toReturn.Threshold = threshold
+
+	return toReturn, foundTokenCount > 0
+}
+
+// BytecodeDiscovery checks the contract known only by its bytecode against every registered standard and
+// returns the discoveries of the standards the contract matched to any level, sorted by the standard type.
+func BytecodeDiscovery(contract *BytecodeMatcher) []Discovery {
+	toReturn := make([]Discovery, 0)
+	for _, standard := range GetSortedRegisteredStandards() {
+		if discovery, found := standard.BytecodeConfidenceCheck(contract); found {
+			toReturn = append(toReturn, discovery)
+		}
+	}
+	return toReturn
+}
+
+// FunctionSelector returns the hex encoded 4-byte selector of the standard function. Parameter types that are not
+// canonical ABI types, such as struct names, are resolved against the standard ABI.
+func FunctionSelector(cs ContractStandard, fn Function) (string, error) {
+	types := make([]string, 0, len(fn.Inputs))
+	for _, input := range fn.Inputs {
+		types = append(types, input.Type)
+	}
+
+	signature, err := resolveSignature(cs, fn.Name, types, false)
+	if err != nil {
+		return "", err
+	}
+
+	return "0x" + common.Bytes2Hex(utils.Keccak256([]byte(signature))[:4]), nil
+}
+
+// EventTopic returns the hex encoded topic (signature hash) of the standard event. Parameter types that are not
+// canonical ABI types, such as struct names, are resolved against the standard ABI.
+func EventTopic(cs ContractStandard, event Event) (string, error) {
+	types := make([]string, 0, len(event.Inputs))
+	for _, input := range event.Inputs {
+		types = append(types, input.Type)
+	}
+
+	signature, err := resolveSignature(cs, event.Name, types, true)
+	if err != nil {
+		return "", err
+	}
+
+	return common.BytesToHash(utils.Keccak256([]byte(signature))).Hex(), nil
+}
+
+// resolveSignature builds the canonical signature, e.g. "transfer(address,uint256)", falling back to the
+// standard ABI when any of the parameter types is not a canonical ABI type.
+func resolveSignature(cs ContractStandard, name string, types []string, event bool) (string, error) {
+	canonical := true
+	for _, typeName := range types {
+		if strings.Contains(typeName, " ") {
+			canonical = false
+			break
+		}
+	}
+
+	if canonical {
+		return fmt.Sprintf("%s(%s)", name, strings.Join(types, ",")), nil
+	}
+
+	parsed, err := abi.JSON(strings.NewReader(cs.ABI))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse abi of standard %s: %w", cs.Type, err)
+	}
+
+	if event {
+		for _, abiEvent := range parsed.Events {
+			if abiEvent.RawName == name && len(abiEvent.Inputs) == len(types) {
+				return abiEvent.Sig, nil
+			}
+		}
+	} else {
+		for _, method := range parsed.Methods {
+			if method.RawName == name && len(method.Inputs) == len(types) {
+				return method.Sig, nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("failed to resolve signature of %s in standard %s", name, cs.Type)
+}
+
+// matchedFunction returns the copy of the standard function with the matched flag applied to all of its parts.
+func matchedFunction(fn Function, matched bool) Function {
+	toReturn := Function{
+		Name:     fn.Name,
+		Inputs:   make([]Input, 0, len(fn.Inputs)),
+		Outputs:  make([]Output, 0, len(fn.Outputs)),
+		Matched:  matched,
+		Optional: fn.Optional,
+	}
+
+	for _, input := range fn.Inputs {
+		toReturn.Inputs = append(toReturn.Inputs, Input{Type: input.Type, Indexed: input.Indexed, Matched: matched})
+	}
+
+	for _, output := range fn.Outputs {
+		toReturn.Outputs = append(toReturn.Outputs, Output{Type: output.Type, Matched: matched})
+	}
+
+	return toReturn
+}
+
+// matchedEvent returns the copy of the standard event with the matched flag applied to all of its parts.
+func matchedEvent(event Event, matched bool) Event {
+	fn := matchedFunction(Function{Name: event.Name, Inputs: event.Inputs, Outputs: event.Outputs, Optional: event.Optional}, matched)
+	return Event{Name: fn.Name, Inputs: fn.Inputs, Outputs: fn.Outputs, Matched: fn.Matched, Optional: fn.Optional}
+}
+
+// containsHex reports whether the list contains the provided hex value, ignoring the case.
+func containsHex(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// ReconstructABI builds the JSON ABI of the contract known only by its bytecode out of the entries of the
+// registered standards. Functions are included when their selector is found within the dispatcher and events
+// when their topic is found within the bytecode. Errors of the standards with any function matched are
+// included as well, as their selectors cannot be told apart from the other constants. Entries shared by
+// multiple standards are included once. Standards must be loaded first.
+func (m *BytecodeMatcher) ReconstructABI() ([]byte, error) {
+	entries := make([]json.RawMessage, 0)
+	seen := make(map[string]bool)
+
+	for _, standard := range GetSortedRegisteredStandards() {
+		if standard.GetABI() == "" {
+			continue
+		}
+
+		var rawEntries []json.RawMessage
+		if err := json.Unmarshal([]byte(standard.GetABI()), &rawEntries); err != nil {
+			return nil, fmt.Errorf("failed to parse abi of standard %s: %w", standard.GetType(), err)
+		}
+
+		matched := false
+		var errorEntries []json.RawMessage
+		for _, rawEntry := range rawEntries {
+			parsed, err := abi.JSON(strings.NewReader("[" + string(rawEntry) + "]"))
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse abi of standard %s: %w", standard.GetType(), err)
+			}
+
+			for _, method := range parsed.Methods {
+				if m.HasSelector("0x"+common.Bytes2Hex(method.ID)) && !seen[method.Sig] {
+					seen[method.Sig], matched = true, true
+					entries = append(entries, rawEntry)
+				}
+			}
+
+			for _, event := range parsed.Events {
+				if m.HasTopic(event.ID.Hex()) && !seen["event "+event.Sig] {
+					seen["event "+event.Sig] = true
+					entries = append(entries, rawEntry)
+				}
+			}
+
+			if len(parsed.Errors) > 0 {
+				errorEntries = append(errorEntries, rawEntry)
+			}
+		}
+
+		if !matched {
+			continue
+		}
+
+		for _, rawEntry := range errorEntries {
+			if !seen["error "+string(rawEntry)] {
+				seen["error "+string(rawEntry)] = true
+				entries = append(entries, rawEntry)
+			}
+		}
+	}
+
+	return json.Marshal(entries)
+}
@@ -0,0 +1,183 @@
+package standards
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBytecodeConfidenceCheck(t *testing.T) {
+	content, err := os.ReadFile("../data/tests/bytecode/BinancePegEthereum.bin")
+	require.NoError(t, err)
+
+	bep20, err := NewBytecodeMatcherFromBytecode(context.TODO(), "BinancePegEthereum", common.FromHex(strings.TrimSpace(string(content))))
+	require.NoError(t, err)
+	assert.Contains(t, bep20.Selectors, "0xa9059cbb")
+	assert.Contains(t, bep20.Selectors, "0x06fdde03")
+	assert.Contains(t, bep20.Topics, "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")
+
+	erc721 := NewContract(standards[ERC721])
+	erc2981Definition, err := definitionsFS.ReadFile("definitions/erc2981.json")
+	require.NoError(t, err)
+	definition, err := NewDefinitionFromBytes(erc2981Definition)
+	require.NoError(t, err)
+	erc2981Standard, err := definition.ToContractStandard()
+	require.NoError(t, err)
+	erc2981 := NewContract(erc2981Standard)
+
+	tests := []struct {
+		name               string
+		standard           EIP
+		contract           *BytecodeMatcher
+		expectedLevel      ConfidenceLevel
+		expectedMatch      bool
+		expectedMatchedFns []string
+	}{
+		{
+			name:               "ERC20 From Bytecode",
+			standard:           NewContract(standards[ERC20]),
+			contract:           bep20,
+			expectedLevel:      PerfectConfidence,
+			expectedMatch:      true,
+			expectedMatchedFns: []string{"totalSupply", "balanceOf", "transfer", "transferFrom", "approve", "allowance"},
+		},
+		{
+			name:               "Ownable From Bytecode",
+			standard:           NewContract(standards[OZOWNABLE]),
+			contract:           bep20,
+			expectedLevel:      MediumConfidence,
+			expectedMatch:      true,
+			expectedMatchedFns: []string{"owner", "renounceOwnership", "transferOwnership"},
+		},
+		{
+			name:          "ERC1155 Not Matched",
+			standard:      NewContract(standards[ERC1155]),
+			contract:      bep20,
+			expectedLevel: NoConfidence,
+			expectedMatch: false,
+		},
+		{
+			name:     "ERC721 Confirmed Through ERC-165",
+			standard: erc721,
+			contract: &BytecodeMatcher{
+				Name:       "ERC721 Interface",
+				Interfaces: []string{"0x01ffc9a7", "0x80AC58CD"},
+				Topics: []string{
+					"0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef",
+					"0x8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925",
+					"0x17307eab39ab6107e8899845ad3d59bd9653f200f220920489ca2b5937696c31",
+				},
+			},
+			expectedLevel:      PerfectConfidence,
+			expectedMatch:      true,
+			expectedMatchedFns: []string{"name", "symbol", "totalSupply", "balanceOf", "ownerOf", "transferFrom", "approve", "setApprovalForAll", "getApproved", "isApprovedForAll"},
+		},
+		{
+			name:     "ERC2981 Without Optional Function",
+			standard: erc2981,
+			contract: &BytecodeMatcher{
+				Name:      "ERC2981 Royalties",
+				Selectors: []string{"0x2a55205a"},
+			},
+			expectedLevel:      PerfectConfidence,
+			expectedMatch:      true,
+			expectedMatchedFns: []string{"royaltyInfo"},
+		},
+		{
+			name:     "ERC2981 Confirmed Through ERC-165 Without Optional Function",
+			standard: erc2981,
+			contract: &BytecodeMatcher{
+				Name:       "ERC2981 Interface",
+				Interfaces: []string{"0x2a55205a"},
+			},
+			expectedLevel:      PerfectConfidence,
+			expectedMatch:      true,
+			expectedMatchedFns: []string{"royaltyInfo"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			discovery, found := tt.standard.BytecodeConfidenceCheck(tt.contract)
+			assert.Equal(t, tt.expectedMatch, found)
+			assert.Equal(t, tt.expectedLevel, discovery.Confidence)
+			assert.Equal(t, tt.standard.GetType(), discovery.Standard)
+			assert.NotNil(t, discovery.ToProto())
+			assert.Len(t, discovery.Explanations, len(tt.standard.GetFunctions())+len(tt.standard.GetEvents()))
+
+			matched := make([]string, 0)
+			for _, fn := range discovery.Contract.Functions {
+				if fn.Matched {
+					matched = append(matched, fn.Name)
+				}
+			}
+			assert.ElementsMatch(t, tt.expectedMatchedFns, matched)
+		})
+	}
+}
+
+func TestFunctionSelectorAndEventTopic(t *testing.T) {
+	erc20 := standards[ERC20]
+	selector, err := FunctionSelector(erc20, erc20.Functions[2])
+	require.NoError(t, err)
+	assert.Equal(t, "0xa9059cbb", selector)
+
+	topic, err := EventTopic(erc20, erc20.Events[0])
+	require.NoError(t, err)
+	assert.Equal(t, "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef", topic)
+
+	// Struct parameters are resolved through the standard ABI.
+	data, err := definitionsFS.ReadFile("definitions/erc4337.json")
+	require.NoError(t, err)
+	definition, err := NewDefinitionFromBytes(data)
+	require.NoError(t, err)
+	erc4337, err := definition.ToContractStandard()
+	require.NoError(t, err)
+
+	selector, err = FunctionSelector(erc4337, erc4337.Functions[0])
+	require.NoError(t, err)
+	assert.Equal(t, "0x19822f7c", selector)
+
+	_, err = FunctionSelector(ContractStandard{Type: "TEST", ABI: "[]"}, newFunction("unknown", []Input{{Type: "struct Unknown"}}, nil))
+	assert.Error(t, err)
+}
+
+func TestReconstructABI(t *testing.T) {
+	// Standards are loaded into the registry of their own, so the registry tests start from the empty one.
+	registered := storage
+	storage = make(map[Standard]EIP)
+	defer func() {
+		storage = registered
+	}()
+	require.NoError(t, LoadStandards())
+
+	content, err := os.ReadFile("../data/tests/bytecode/BinancePegEthereum.bin")
+	require.NoError(t, err)
+
+	bep20, err := NewBytecodeMatcherFromBytecode(context.TODO(), "BinancePegEthereum", common.FromHex(strings.TrimSpace(string(content))))
+	require.NoError(t, err)
+
+	abiData, err := bep20.ReconstructABI()
+	require.NoError(t, err)
+
+	parsed, err := abi.JSON(bytes.NewReader(abiData))
+	require.NoError(t, err)
+
+	for _, name := range []string{"transfer", "balanceOf", "owner", "transferOwnership"} {
+		assert.Contains(t, parsed.Methods, name)
+	}
+	assert.Contains(t, parsed.Events, "Transfer")
+	assert.NotContains(t, parsed.Methods, "safeTransferFrom")
+
+	// Nothing is reconstructed out of the bytecode without the dispatcher.
+	abiData, err = (&BytecodeMatcher{}).ReconstructABI()
+	require.NoError(t, err)
+	assert.JSONEq(t, "[]", string(abiData))
+}
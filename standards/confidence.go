@@ -1,6 +1,8 @@
 package standards
 
 import (
+	"fmt"
+
 	eip_pb "github.com/unpackdev/protos/dist/go/eip"
 )
 
@@ -70,24 +72,62 @@ const (
 	NoConfidence ConfidenceLevel = 0
 )
 
-// CalculateDiscoveryConfidence calculates the confidence level and threshold based on the total confidence.
-func CalculateDiscoveryConfidence(totalConfidence float64) (ConfidenceLevel, ConfidenceThreshold) {
+// Thresholds represents the minimum confidence points required to reach each of the confidence levels.
+// Perfect confidence is always reserved for the contracts matching all of the standard tokens.
+type Thresholds struct {
+	// High is the minimum confidence points of the high confidence level.
+	High ConfidenceThreshold `json:"high"`
+
+	// Medium is the minimum confidence points of the medium confidence level.
+	Medium ConfidenceThreshold `json:"medium"`
+
+	// Low is the minimum confidence points of the low confidence level.
+	Low ConfidenceThreshold `json:"low"`
+}
+
+// DefaultThresholds are the confidence thresholds used by standards that do not define their own.
+var DefaultThresholds = Thresholds{
+	High:   HighConfidenceThreshold,
+	Medium: MediumConfidenceThreshold,
+	Low:    LowConfidenceThreshold,
+}
+
+// Validate returns an error unless the thresholds are ordered within the (0, 1] range.
+func (t Thresholds) Validate() error {
+	if t.Low <= NoConfidenceThreshold || t.Low > t.Medium || t.Medium > t.High || t.High > PerfectConfidenceThreshold {
+		return fmt.Errorf("%w: expected 0 < low (%v) <= medium (%v) <= high (%v) <= 1", ErrInvalidThresholds, t.Low, t.Medium, t.High)
+	}
+	return nil
+}
+
+// Calculate calculates the confidence level and threshold based on the total confidence.
+func (t Thresholds) Calculate(totalConfidence float64) (ConfidenceLevel, ConfidenceThreshold) {
 	total := ConfidenceThreshold(totalConfidence)
 	switch {
 	case total == PerfectConfidenceThreshold:
 		return PerfectConfidence, PerfectConfidenceThreshold
-	case total >= HighConfidenceThreshold:
-		return HighConfidence, HighConfidenceThreshold
-	case total >= MediumConfidenceThreshold:
-		return MediumConfidence, MediumConfidenceThreshold
-	case total >= LowConfidenceThreshold:
-		return LowConfidence, LowConfidenceThreshold
+	case total >= t.High:
+		return HighConfidence, t.High
+	case total >= t.Medium:
+		return MediumConfidence, t.Medium
+	case total >= t.Low:
+		return LowConfidence, t.Low
 	default:
 		return NoConfidence, NoConfidenceThreshold
 	}
 }
 
+// CalculateDiscoveryConfidence calculates the confidence level and threshold based on the total confidence
+// using the DefaultThresholds.
+func CalculateDiscoveryConfidence(totalConfidence float64) (ConfidenceLevel, ConfidenceThreshold) {
+	return DefaultThresholds.Calculate(totalConfidence)
+}
+
 // ConfidenceCheck checks the confidence of a contract against a standard EIP.
+// Confidence points are the ratio of the weighted discovered and maximum tokens, where optional functions and
+// events weigh less than mandatory ones and are accounted for only when implemented. Functions returning the
+// wrong types or declaring a contradicting state mutability are penalized. The discovery explains the outcome
+// for each function and event of the standard, and its level is calculated using the standard thresholds.
 func ConfidenceCheck(standard EIP, contract *ContractMatcher) (Discovery, bool) {
 	toReturn := Discovery{
 		Standard:         standard.GetType(),
@@ -105,6 +145,7 @@ func ConfidenceCheck(standard EIP, contract *ContractMatcher) (Discovery, bool)
 	foundTokenCount := 0
 	discoveredFunctions := map[string]bool{}
 	discoveredEvents := map[string]bool{}
+	score := &scorer{}
 
 	for _, standardFunction := range standard.GetFunctions() {
 		contractFn := Function{
@@ -119,12 +160,21 @@ func ConfidenceCheck(standard EIP, contract *ContractMatcher) (Discovery, bool)
 					discoveredFunctions[contractFunction.Name] = true
 					contractFn.Matched = true
 					foundTokenCount += tokensFound
+					score.add(explainFunction(standardFunction, contractFunction, contractFn, tokensFound))
+
+					// Optional functions are accounted for only when implemented.
+					if standardFunction.Optional {
+						toReturn.MaximumTokens += FunctionTokenCount(standardFunction)
+					}
 				}
 			}
 		}
 
+		contractFn.Optional = standardFunction.Optional
+
 		if !contractFn.Matched {
 			contractFn.Matched = false
+			score.add(explainMissing(FunctionMember, standardFunction.Name, standardFunction.Inputs, standardFunction.Optional, FunctionTokenCount(standardFunction), "function not implemented"))
 
 			if standardFunction.Inputs == nil {
 				standardFunction.Inputs = make([]Input, 0)
@@ -156,12 +206,21 @@ func ConfidenceCheck(standard EIP, contract *ContractMatcher) (Discovery, bool)
 					discoveredEvents[contractEvent.Name] = true
 					eventFn.Matched = true
 					foundTokenCount += tokensFound
+					score.add(explainEvent(event, contractEvent, eventFn, tokensFound))
+
+					// Optional events are accounted for only when emitted.
+					if event.Optional {
+						toReturn.MaximumTokens += EventTokenCount(event)
+					}
 				}
 			}
 		}
 
+		eventFn.Optional = event.Optional
+
 		if !eventFn.Matched {
 			eventFn.Matched = false
+			score.add(explainMissing(EventMember, event.Name, event.Inputs, event.Optional, EventTokenCount(event), "event not emitted"))
 
 			if event.Inputs == nil {
 				event.Inputs = make([]Input, 0)
@@ -180,10 +239,12 @@ func ConfidenceCheck(standard EIP, contract *ContractMatcher) (Discovery, bool)
 	}
 
 	toReturn.DiscoveredTokens = foundTokenCount
+	toReturn.Explanations = score.explanations
 
-	// Calculate the total confidence based on the discovered tokens and maximum tokens
-	confidencePoints := float64(foundTokenCount) / float64(standard.TokenCount())
-	level, threshold := CalculateDiscoveryConfidence(confidencePoints)
+	// Calculate the total confidence based on the weighted discovered tokens and maximum tokens
+	cs := standard.GetStandard()
+	confidencePoints := score.points()
+	level, threshold := cs.GetThresholds().Calculate(confidencePoints)
 	toReturn.Confidence = level
 	toReturn.ConfidencePoints = confidencePoints
 	toReturn.Threshold = threshold
@@ -219,8 +280,9 @@ func FunctionConfidenceCheck(standard EIP, fn *Function) (FunctionDiscovery, boo
 	}
 
 	toReturn.DiscoveredTokens = foundTokenCount
+	cs := standard.GetStandard()
 	confidencePoints := float64(foundTokenCount) / float64(maximumTokens)
-	level, threshold := CalculateDiscoveryConfidence(confidencePoints)
+	level, threshold := cs.GetThresholds().Calculate(confidencePoints)
 	toReturn.Confidence = level
 	toReturn.ConfidencePoints = confidencePoints
 	toReturn.Threshold = threshold
@@ -251,11 +313,9 @@ func FunctionMatch(newFn *Function, standardFunction, contractFunction Function)
 
 		for _, sfnOutput := range standardFunction.Outputs {
 			newOutput := Output{Type: sfnOutput.Type}
-			for range standardFunction.Outputs {
-				for _, fnOutput := range contractFunction.Outputs {
-					if _, matched := outputMatch(standardFunction.Outputs, fnOutput); matched {
-						totalTokenCount += 2 // Counting the input match and type match...
-					}
+			for _, fnOutput := range contractFunction.Outputs {
+				if _, matched := outputMatch(standardFunction.Outputs, fnOutput); matched {
+					totalTokenCount += 2 // Counting the output match and type match...
 					newOutput.Matched = true
 					break
 				}
@@ -291,11 +351,9 @@ func EventMatch(newEvent *Event, standardEvent, event Event) (int, bool) {
 
 		for _, seOutput := range standardEvent.Outputs {
 			newOutput := Output{Type: seOutput.Type}
-			for range event.Outputs {
-				for _, fnOutput := range event.Outputs {
-					if _, matched := outputMatch(standardEvent.Outputs, fnOutput); matched {
-						totalTokenCount += 2 // Counting the input match and type match...
-					}
+			for _, fnOutput := range event.Outputs {
+				if _, matched := outputMatch(standardEvent.Outputs, fnOutput); matched {
+					totalTokenCount += 2 // Counting the output match and type match...
 					newOutput.Matched = true
 					break
 				}
@@ -392,26 +392,44 @@ func TestEIPConfidenceDiscovery(t *testing.T) {
 					contract: &ContractMatcher{
 						Name: "ERC1967 Full Match",
 						Functions: []Function{
-							newFunction("setInterfaceImplementer", []Input{{Type: TypeAddress}, {Type: TypeBytes32}, {Type: TypeAddress}}, nil),
-							newFunction("getInterfaceImplementer", []Input{{Type: TypeAddress}, {Type: TypeBytes32}}, []Output{{Type: TypeAddress}}),
-							newFunction("interfaceHash", []Input{{Type: TypeString}}, []Output{{Type: TypeBytes32}}),
-							newFunction("updateERC165Cache", []Input{{Type: TypeAddress}, {Type: TypeBytes32}}, nil),
-							newFunction("implementsERC165InterfaceNoCache", []Input{{Type: TypeAddress}, {Type: TypeBytes32}}, []Output{{Type: TypeBool}}),
-							newFunction("implementsERC165Interface", []Input{{Type: TypeAddress}, {Type: TypeBytes32}}, []Output{{Type: TypeBool}}),
+							newFunction("implementation", nil, []Output{{Type: TypeAddress}}),
+							newFunction("admin", nil, []Output{{Type: TypeAddress}}),
+							newFunction("changeAdmin", []Input{{Type: TypeAddress}}, nil),
+							newFunction("upgradeTo", []Input{{Type: TypeAddress}}, nil),
+							newFunction("upgradeToAndCall", []Input{{Type: TypeAddress}, {Type: TypeBytes}}, nil),
 						},
 						Events: []Event{
-							newEvent("InterfaceImplementerSet", []Input{{Type: TypeAddress, Indexed: true}, {Type: TypeBytes32, Indexed: true}, {Type: TypeAddress, Indexed: true}}, nil),
-							newEvent("AdminChanged", []Input{{Type: TypeAddress, Indexed: true}, {Type: TypeAddress, Indexed: true}}, nil),
+							newEvent("Upgraded", []Input{{Type: TypeAddress, Indexed: true}}, nil),
+							newEvent("AdminChanged", []Input{{Type: TypeAddress}, {Type: TypeAddress}}, nil),
+							newEvent("BeaconUpgraded", []Input{{Type: TypeAddress, Indexed: true}}, nil),
 						},
 					},
 					expectedLevel:        PerfectConfidence,
 					expectedThreshold:    PerfectConfidenceThreshold,
-					standardTokenCount:   67,
-					discoveredTokenCount: 67,
+					standardTokenCount:   36,
+					discoveredTokenCount: 36,
 					shouldMatch:          true,
 					expectedEip:          tests.ReadJsonBytesForTest(t, "eip/eip1967_full_match").Content,
 					expectedProto:        tests.ReadJsonBytesForTest(t, "eip/eip1967_full_match.proto").Content,
 				},
+				{
+					name:       "Events Only Match",
+					outputFile: "eip1967_events_match",
+					contract: &ContractMatcher{
+						Name: "ERC1967 Events Only Match",
+						Events: []Event{
+							newEvent("Upgraded", []Input{{Type: TypeAddress, Indexed: true}}, nil),
+							newEvent("AdminChanged", []Input{{Type: TypeAddress}, {Type: TypeAddress}}, nil),
+						},
+					},
+					expectedLevel:        PerfectConfidence,
+					expectedThreshold:    PerfectConfidenceThreshold,
+					standardTokenCount:   11,
+					discoveredTokenCount: 11,
+					shouldMatch:          true,
+					expectedEip:          tests.ReadJsonBytesForTest(t, "eip/eip1967_events_match").Content,
+					expectedProto:        tests.ReadJsonBytesForTest(t, "eip/eip1967_events_match.proto").Content,
+				},
 			},
 		},
 	}
@@ -577,3 +595,204 @@ func TestEventMatch(t *testing.T) {
 		})
 	}
 }
+
+func TestConfidenceExplanations(t *testing.T) {
+	erc20 := func(balanceOf Function, transfer Event) *ContractMatcher {
+		return &ContractMatcher{
+			Name: "ERC20",
+			Functions: []Function{
+				{Name: "totalSupply", Outputs: []Output{{Type: TypeUint256}}, StateMutability: "view"},
+				balanceOf,
+				{Name: "transfer", Inputs: []Input{{Type: TypeAddress}, {Type: TypeUint256}}, Outputs: []Output{{Type: TypeBool}}, StateMutability: "nonpayable"},
+				{Name: "transferFrom", Inputs: []Input{{Type: TypeAddress}, {Type: TypeAddress}, {Type: TypeUint256}}, Outputs: []Output{{Type: TypeBool}}, StateMutability: "nonpayable"},
+				{Name: "approve", Inputs: []Input{{Type: TypeAddress}, {Type: TypeUint256}}, Outputs: []Output{{Type: TypeBool}}, StateMutability: "nonpayable"},
+			},
+			Events: []Event{
+				transfer,
+				newEvent("Approval", []Input{{Type: TypeAddress, Indexed: true}, {Type: TypeAddress, Indexed: true}, {Type: TypeUint256}}, nil),
+			},
+		}
+	}
+
+	balanceOf := Function{Name: "balanceOf", Inputs: []Input{{Type: TypeAddress}}, Outputs: []Output{{Type: TypeUint256}}, StateMutability: "view"}
+	transfer := newEvent("Transfer", []Input{{Type: TypeAddress, Indexed: true}, {Type: TypeAddress, Indexed: true}, {Type: TypeUint256}}, nil)
+
+	strict := standards[ERC20]
+	strict.Thresholds = &Thresholds{High: 0.95, Medium: 0.9, Low: 0.5}
+
+	tests := []struct {
+		name                 string
+		standard             EIP
+		contract             *ContractMatcher
+		expectedLevel        ConfidenceLevel
+		expectedThreshold    ConfidenceThreshold
+		expectedPoints       float64
+		expectedExplanations []string
+	}{
+		{
+			name:              "Missing Mandatory Function",
+			standard:          NewContract(standards[ERC20]),
+			contract:          erc20(balanceOf, transfer),
+			expectedLevel:     MediumConfidence,
+			expectedThreshold: MediumConfidenceThreshold,
+			expectedPoints:    59.0 / 68.0,
+			expectedExplanations: []string{
+				"function allowance(address,address): missing (function not implemented)",
+			},
+		},
+		{
+			name:     "Wrong Return Type And Mutability",
+			standard: NewContract(standards[ERC20]),
+			contract: func() *ContractMatcher {
+				contract := erc20(Function{Name: "balanceOf", Inputs: []Input{{Type: TypeAddress}}, Outputs: []Output{{Type: TypeBool}}, StateMutability: "nonpayable"}, transfer)
+				contract.Functions = append(contract.Functions, Function{Name: "allowance", Inputs: []Input{{Type: TypeAddress}, {Type: TypeAddress}}, Outputs: []Output{{Type: TypeUint256}}, StateMutability: "pure"})
+				return contract
+			}(),
+			expectedLevel:     HighConfidence,
+			expectedThreshold: HighConfidenceThreshold,
+			expectedPoints:    62.0 / 68.0,
+			expectedExplanations: []string{
+				"function balanceOf(address): mismatched (return value 0 is bool, expected uint256; state mutability is nonpayable, expected view)",
+			},
+		},
+		{
+			name:     "Event Without Indexed Inputs",
+			standard: NewContract(standards[ERC20]),
+			contract: func() *ContractMatcher {
+				contract := erc20(balanceOf, newEvent("Transfer", []Input{{Type: TypeAddress}, {Type: TypeAddress}, {Type: TypeUint256}}, nil))
+				contract.Functions = append(contract.Functions, Function{Name: "allowance", Inputs: []Input{{Type: TypeAddress}, {Type: TypeAddress}}, Outputs: []Output{{Type: TypeUint256}}})
+				return contract
+			}(),
+			expectedLevel:     HighConfidence,
+			expectedThreshold: HighConfidenceThreshold,
+			expectedPoints:    65.0 / 68.0,
+			expectedExplanations: []string{
+				"event Transfer(address,address,uint256): mismatched (input 0 indexed is false, expected true; input 1 indexed is false, expected true)",
+			},
+		},
+		{
+			name:              "Standard Thresholds",
+			standard:          NewContract(strict),
+			contract:          erc20(balanceOf, transfer),
+			expectedLevel:     LowConfidence,
+			expectedThreshold: 0.5,
+			expectedPoints:    59.0 / 68.0,
+			expectedExplanations: []string{
+				"function allowance(address,address): missing (function not implemented)",
+			},
+		},
+		{
+			name:     "Optional Members Weigh Less",
+			standard: NewContract(standards[ERC1967]),
+			contract: &ContractMatcher{
+				Name: "ERC1967",
+				Functions: []Function{
+					{Name: "implementation", Outputs: []Output{{Type: TypeBool}}},
+				},
+				Events: []Event{
+					newEvent("Upgraded", []Input{{Type: TypeAddress, Indexed: true}}, nil),
+					newEvent("AdminChanged", []Input{{Type: TypeAddress}, {Type: TypeAddress}}, nil),
+				},
+			},
+			expectedLevel:     MediumConfidence,
+			expectedThreshold: MediumConfidenceThreshold,
+			expectedPoints:    11 / (11 + OptionalWeight*3),
+			expectedExplanations: []string{
+				"function implementation(): mismatched (return value 0 is bool, expected address)",
+				"function admin(): missing (function not implemented)",
+				"function changeAdmin(address): missing (function not implemented)",
+				"function upgradeTo(address): missing (function not implemented)",
+				"function upgradeToAndCall(address,bytes): missing (function not implemented)",
+				"event BeaconUpgraded(address): missing (event not emitted)",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			discovery, found := tt.standard.ConfidenceCheck(tt.contract)
+			assert.True(t, found)
+			assert.Equal(t, tt.expectedLevel, discovery.Confidence)
+			assert.Equal(t, tt.expectedThreshold, discovery.Threshold)
+			assert.InDelta(t, tt.expectedPoints, discovery.ConfidencePoints, 1e-9)
+			assert.Len(t, discovery.Explanations, len(tt.standard.GetFunctions())+len(tt.standard.GetEvents()))
+
+			explanations := make([]string, 0)
+			for _, explanation := range discovery.Explanations {
+				if explanation.Status != MatchStatusMatched {
+					explanations = append(explanations, explanation.String())
+				}
+				if explanation.Optional {
+					assert.Equal(t, OptionalWeight, explanation.Weight)
+				} else {
+					assert.Equal(t, MandatoryWeight, explanation.Weight)
+				}
+			}
+			assert.Equal(t, tt.expectedExplanations, explanations)
+		})
+	}
+}
+
+func TestThresholds(t *testing.T) {
+	tests := []struct {
+		name              string
+		thresholds        Thresholds
+		points            float64
+		expectedLevel     ConfidenceLevel
+		expectedThreshold ConfidenceThreshold
+		expectedError     bool
+	}{
+		{
+			name:              "Default Perfect",
+			thresholds:        DefaultThresholds,
+			points:            1,
+			expectedLevel:     PerfectConfidence,
+			expectedThreshold: PerfectConfidenceThreshold,
+		},
+		{
+			name:              "Default Medium",
+			thresholds:        DefaultThresholds,
+			points:            0.75,
+			expectedLevel:     MediumConfidence,
+			expectedThreshold: MediumConfidenceThreshold,
+		},
+		{
+			name:              "Custom High",
+			thresholds:        Thresholds{High: 0.7, Medium: 0.4, Low: 0.2},
+			points:            0.75,
+			expectedLevel:     HighConfidence,
+			expectedThreshold: 0.7,
+		},
+		{
+			name:              "Custom No Confidence",
+			thresholds:        Thresholds{High: 0.7, Medium: 0.4, Low: 0.2},
+			points:            0.1,
+			expectedLevel:     NoConfidence,
+			expectedThreshold: NoConfidenceThreshold,
+		},
+		{
+			name:          "Out Of Order",
+			thresholds:    Thresholds{High: 0.4, Medium: 0.7, Low: 0.2},
+			expectedError: true,
+		},
+		{
+			name:          "Zero Low Threshold",
+			thresholds:    Thresholds{High: 0.9, Medium: 0.5},
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.expectedError {
+				assert.ErrorIs(t, tt.thresholds.Validate(), ErrInvalidThresholds)
+				return
+			}
+
+			assert.NoError(t, tt.thresholds.Validate())
+			level, threshold := tt.thresholds.Calculate(tt.points)
+			assert.Equal(t, tt.expectedLevel, level)
+			assert.Equal(t, tt.expectedThreshold, threshold)
+		})
+	}
+}
@@ -23,6 +23,11 @@ func (e *Contract) GetUrl() string {
 	return e.Standard.Url
 }
 
+// GetInterfaceId returns the ERC-165 interface identifier of the standard, if it defines one.
+func (e *Contract) GetInterfaceId() string {
+	return e.Standard.InterfaceId
+}
+
 // GetFunctions returns the functions associated with the standard.
 func (e *Contract) GetFunctions() []Function {
 	return e.Standard.Functions
@@ -50,6 +55,12 @@ func (e *Contract) ConfidenceCheck(contract *ContractMatcher) (Discovery, bool)
 	return ConfidenceCheck(e, contract)
 }
 
+// BytecodeConfidenceCheck performs a confidence check of the contract standard against a contract known only by its
+// bytecode, see BytecodeConfidenceCheck function for details on how the selectors and topics are scored.
+func (e *Contract) BytecodeConfidenceCheck(contract *BytecodeMatcher) (Discovery, bool) {
+	return BytecodeConfidenceCheck(e, contract)
+}
+
 // FunctionConfidenceCheck performs a confidence check on a specific function within the contract standard against a provided
 // function matcher. It assesses whether the function in question matches the criteria defined in the function matcher,
 // returning a FunctionDiscovery struct that details the matching confidence and a boolean indicating if a match was found.
@@ -92,14 +103,23 @@ func (e *Contract) String() string {
 
 // NewContract initializes and returns an instance of the standard.
 // It sets up the standard with its name, type, associated functions, and events.
+// Functions without the state mutability are completed from the standard ABI, if it describes them.
 func NewContract(standard ContractStandard) EIP {
-	return &Contract{Standard: standard}
+	return &Contract{Standard: withStateMutability(standard)}
 }
 
 // GetContractByStandard returns the contract standard by its type.
+// Registered standards take precedence, so the changes applied to them at runtime, such as
+// thresholds set through SetStandardThresholds, are reflected. Standards loaded from definition
+// files are available only through the registry.
 func GetContractByStandard(standard Standard) (EIP, error) {
+	if eip, ok := GetStandard(standard); ok {
+		return eip, nil
+	}
+
 	if standard, ok := standards[standard]; ok {
 		return NewContract(standard), nil
 	}
+
 	return nil, ErrStandardNotFound
 }
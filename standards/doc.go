@@ -1,2 +1,13 @@
 // Package standards provides structures and functions to represent and manipulate Ethereum Improvement Proposals (EIPs) and Ethereum standards.
+//
+// Besides the built-in EIPs, standards such as ERC-4626, ERC-2612, ERC-2981, ERC-4337, ERC-6551, ERC-5805 and
+// Uniswap V3 ship as definition files. Custom standards are loaded at runtime from the JSON or YAML definitions,
+// or imported from the interface ABI, with their functions and events optionally marked as optional.
+//
+// Contracts without verified sources are classified from their bytecode alone, by the dispatcher selectors
+// and event topics, optionally confirmed on-chain through the ERC-165 supportsInterface probing.
+//
+// Confidence scores weigh the mandatory members over the optional ones, penalize the wrong return types and
+// state mutability, and can be tuned with the per-standard thresholds. Every discovery explains which functions
+// and events were matched, missing or mismatched, and why.
 package standards
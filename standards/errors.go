@@ -6,4 +6,10 @@ var (
 
 	// ErrStandardNotFound is returned when a standard is not found.
 	ErrStandardNotFound = errors.New("standard not found")
+
+	// ErrInvalidDefinition is returned when a standard definition or its ABI cannot be used to build the standard.
+	ErrInvalidDefinition = errors.New("invalid standard definition")
+
+	// ErrInvalidThresholds is returned when the confidence thresholds are out of order or out of range.
+	ErrInvalidThresholds = errors.New("invalid confidence thresholds")
 )
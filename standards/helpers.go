@@ -27,6 +27,20 @@ func newEvent(name string, inputs []Input, outputs []Output) Event {
 	}
 }
 
+// newOptionalFunction creates and returns a new Function struct marked as optional within the standard.
+func newOptionalFunction(name string, inputs []Input, outputs []Output) Function {
+	fn := newFunction(name, inputs, outputs)
+	fn.Optional = true
+	return fn
+}
+
+// newOptionalEvent creates and returns a new Event struct marked as optional within the standard.
+func newOptionalEvent(name string, inputs []Input, outputs []Output) Event {
+	event := newEvent(name, inputs, outputs)
+	event.Optional = true
+	return event
+}
+
 // GetProtoStandardFromString converts a string representation of an Ethereum standard
 // to its corresponding protobuf enum value. If the standard is not recognized,
 // it returns an error.
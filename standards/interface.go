@@ -18,6 +18,9 @@ type EIP interface {
 	// GetUrl returns the URL of the Ethereum standard.
 	GetUrl() string
 
+	// GetInterfaceId returns the ERC-165 interface identifier of the Ethereum standard, if it defines one.
+	GetInterfaceId() string
+
 	// IsStagnant returns a boolean indicating whether the Ethereum standard is stagnant.
 	IsStagnant() bool
 
@@ -32,6 +35,10 @@ type EIP interface {
 	// the contract is to any level compliant with the Ethereum standard.
 	ConfidenceCheck(contract *ContractMatcher) (Discovery, bool)
 
+	// BytecodeConfidenceCheck returns a discovery confidence information and a boolean indicating whether
+	// the contract bytecode is to any level compliant with the Ethereum standard.
+	BytecodeConfidenceCheck(contract *BytecodeMatcher) (Discovery, bool)
+
 	// FunctionConfidenceCheck returns a discovery confidence information and a boolean indicating whether
 	// the contract function is to any level compliant with the Ethereum standard.
 	FunctionConfidenceCheck(fn *Function) (FunctionDiscovery, bool)
@@ -0,0 +1,273 @@
+package standards
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/goccy/go-json"
+	"gopkg.in/yaml.v3"
+)
+
+// definitionsFS holds the standards shipped as definition files alongside the package.
+//
+//go:embed definitions/*.json
+var definitionsFS embed.FS
+
+// Definition represents the standard as it is described within the JSON or YAML definition file.
+// The standard functions and events are either listed explicitly or imported out of the standard
+// interface ABI. Functions and events can be marked as optional either on the entry itself or by
+// listing their names within Optional.
+type Definition struct {
+	// Name specifies the name of the contract standard, e.g., "ERC-4626 Tokenized Vaults".
+	Name string `json:"name"`
+
+	// Url specifies the URL of the contract standard.
+	Url string `json:"url"`
+
+	// Type specifies the type of the contract standard, e.g., ERC4626.
+	Type Standard `json:"type"`
+
+	// Stagnant indicates whether the contract standard is stagnant in terms of development.
+	Stagnant bool `json:"stagnant"`
+
+	// ABI specifies the ABI of the standard interface, either as JSON array or JSON encoded string.
+	ABI json.RawMessage `json:"abi,omitempty"`
+
+	// InterfaceId specifies the ERC-165 interface identifier of the standard, if it defines one.
+	InterfaceId string `json:"interface_id,omitempty"`
+
+	// Optional lists names of the functions and events the implementation may omit.
+	Optional []string `json:"optional,omitempty"`
+
+	// Functions lists the standard functions. When empty, functions are imported from the ABI.
+	Functions []Function `json:"functions,omitempty"`
+
+	// Events lists the standard events. When empty, events are imported from the ABI.
+	Events []Event `json:"events,omitempty"`
+
+	// Thresholds overrides the default confidence thresholds of the standard.
+	Thresholds *Thresholds `json:"thresholds,omitempty"`
+}
+
+// ToContractStandard validates the definition and converts it into the ContractStandard.
+func (d *Definition) ToContractStandard() (ContractStandard, error) {
+	if d.Type == "" {
+		return ContractStandard{}, fmt.Errorf("%w: missing standard type", ErrInvalidDefinition)
+	}
+
+	if d.Name == "" {
+		return ContractStandard{}, fmt.Errorf("%w: missing name for standard %s", ErrInvalidDefinition, d.Type)
+	}
+
+	if d.Thresholds != nil {
+		if err := d.Thresholds.Validate(); err != nil {
+			return ContractStandard{}, fmt.Errorf("%w: standard %s: %s", ErrInvalidDefinition, d.Type, err)
+		}
+	}
+
+	abi, err := d.getABI()
+	if err != nil {
+		return ContractStandard{}, err
+	}
+
+	toReturn := ContractStandard{
+		Name:        d.Name,
+		Url:         d.Url,
+		Type:        d.Type,
+		Stagnant:    d.Stagnant,
+		ABI:         string(abi),
+		InterfaceId: d.InterfaceId,
+		Functions:   d.Functions,
+		Events:      d.Events,
+		Thresholds:  d.Thresholds,
+	}
+
+	if len(abi) > 0 {
+		imported, err := NewContractStandardFromABI(d.Type, d.Name, d.Url, abi)
+		if err != nil {
+			return ContractStandard{}, err
+		}
+
+		toReturn.ABI = imported.ABI
+		if len(toReturn.Functions) == 0 && len(toReturn.Events) == 0 {
+			toReturn.Functions = imported.Functions
+			toReturn.Events = imported.Events
+		}
+	}
+
+	if len(toReturn.Functions) == 0 && len(toReturn.Events) == 0 {
+		return ContractStandard{}, fmt.Errorf("%w: standard %s has no functions or events", ErrInvalidDefinition, d.Type)
+	}
+
+	optional := make(map[string]bool, len(d.Optional))
+	for _, name := range d.Optional {
+		optional[name] = true
+	}
+
+	found := make(map[string]bool, len(d.Optional))
+	for i, fn := range toReturn.Functions {
+		if fn.Name == "" {
+			return ContractStandard{}, fmt.Errorf("%w: function without name in standard %s", ErrInvalidDefinition, d.Type)
+		}
+
+		if optional[fn.Name] {
+			toReturn.Functions[i].Optional = true
+			found[fn.Name] = true
+		}
+	}
+
+	for i, event := range toReturn.Events {
+		if event.Name == "" {
+			return ContractStandard{}, fmt.Errorf("%w: event without name in standard %s", ErrInvalidDefinition, d.Type)
+		}
+
+		if optional[event.Name] {
+			toReturn.Events[i].Optional = true
+			found[event.Name] = true
+		}
+	}
+
+	for _, name := range d.Optional {
+		if !found[name] {
+			return ContractStandard{}, fmt.Errorf("%w: optional entry %s not found in standard %s", ErrInvalidDefinition, name, d.Type)
+		}
+	}
+
+	return toReturn, nil
+}
+
+// getABI returns the definition ABI as JSON array, decoding it first if it is provided as a string.
+func (d *Definition) getABI() ([]byte, error) {
+	abi := bytes.TrimSpace(d.ABI)
+	if len(abi) == 0 || bytes.Equal(abi, []byte("null")) {
+		return nil, nil
+	}
+
+	if abi[0] == '"' {
+		var encoded string
+		if err := json.Unmarshal(abi, &encoded); err != nil {
+			return nil, fmt.Errorf("%w: failed to decode abi for standard %s: %s", ErrInvalidDefinition, d.Type, err)
+		}
+		abi = bytes.TrimSpace([]byte(encoded))
+	}
+
+	return abi, nil
+}
+
+// NewDefinitionFromBytes decodes the standard definition from the JSON or YAML encoded content.
+func NewDefinitionFromBytes(data []byte) (*Definition, error) {
+	content := bytes.TrimSpace(data)
+
+	// YAML is converted into JSON first so both formats share the same field names and decoding rules.
+	if len(content) > 0 && content[0] != '{' {
+		var decoded interface{}
+		if err := yaml.Unmarshal(content, &decoded); err != nil {
+			return nil, fmt.Errorf("%w: failed to decode yaml: %s", ErrInvalidDefinition, err)
+		}
+
+		encoded, err := json.Marshal(decoded)
+		if err != nil {
+			return nil, fmt.Errorf("%w: failed to convert yaml: %s", ErrInvalidDefinition, err)
+		}
+		content = encoded
+	}
+
+	toReturn := &Definition{}
+	if err := json.Unmarshal(content, toReturn); err != nil {
+		return nil, fmt.Errorf("%w: failed to decode json: %s", ErrInvalidDefinition, err)
+	}
+
+	return toReturn, nil
+}
+
+// LoadStandardFromBytes decodes the JSON or YAML standard definition and registers it.
+func LoadStandardFromBytes(data []byte) (EIP, error) {
+	definition, err := NewDefinitionFromBytes(data)
+	if err != nil {
+		return nil, err
+	}
+
+	standard, err := definition.ToContractStandard()
+	if err != nil {
+		return nil, err
+	}
+
+	toReturn := NewContract(standard)
+	if err := RegisterStandard(standard.Type, toReturn); err != nil {
+		return nil, err
+	}
+
+	return toReturn, nil
+}
+
+// LoadStandardFromFile reads the JSON or YAML standard definition file and registers the standard.
+func LoadStandardFromFile(path string) (EIP, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+
+	toReturn, err := LoadStandardFromBytes(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load standard from %s: %w", path, err)
+	}
+
+	return toReturn, nil
+}
+
+// LoadStandardsFromDir registers every JSON (.json) and YAML (.yaml, .yml) standard definition found
+// within the provided directory. Loading stops on the first definition that fails to register.
+func LoadStandardsFromDir(dir string) ([]EIP, error) {
+	return loadDefinitions(os.DirFS(dir), ".")
+}
+
+// loadDefinitions registers standard definitions found within the directory of the provided file system.
+func loadDefinitions(fsys fs.FS, dir string) ([]EIP, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	// Entries are sorted so the registration order does not depend on the file system.
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name() < entries[j].Name()
+	})
+
+	toReturn := make([]EIP, 0)
+	for _, entry := range entries {
+		if entry.IsDir() || !isDefinitionFile(entry.Name()) {
+			continue
+		}
+
+		path := filepath.ToSlash(filepath.Join(dir, entry.Name()))
+		data, err := fs.ReadFile(fsys, path)
+		if err != nil {
+			return nil, err
+		}
+
+		standard, err := LoadStandardFromBytes(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load standard from %s: %w", entry.Name(), err)
+		}
+
+		toReturn = append(toReturn, standard)
+	}
+
+	return toReturn, nil
+}
+
+// isDefinitionFile returns true if the file extension denotes the JSON or YAML definition.
+func isDefinitionFile(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".json", ".yaml", ".yml":
+		return true
+	default:
+		return false
+	}
+}
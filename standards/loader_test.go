@@ -0,0 +1,303 @@
+package standards
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	eip_pb "github.com/unpackdev/protos/dist/go/eip"
+)
+
+func TestShippedDefinitions(t *testing.T) {
+	tests := []struct {
+		name              string
+		file              string
+		standard          Standard
+		functions         int
+		events            int
+		optional          []string
+		expectedInputType map[string]string
+	}{
+		{
+			name:      "ERC-2612 Permit",
+			file:      "erc2612.json",
+			standard:  ERC2612,
+			functions: 3,
+			expectedInputType: map[string]string{
+				"permit": TypeAddress,
+			},
+		},
+		{
+			name:      "ERC-2981 Royalties",
+			file:      "erc2981.json",
+			standard:  ERC2981,
+			functions: 2,
+			optional:  []string{"supportsInterface"},
+		},
+		{
+			name:      "ERC-4337 Account",
+			file:      "erc4337.json",
+			standard:  ERC4337,
+			functions: 3,
+			optional:  []string{"entryPoint", "executeUserOp"},
+			expectedInputType: map[string]string{
+				"validateUserOp": "struct PackedUserOperation",
+			},
+		},
+		{
+			name:      "ERC-4626 Vault",
+			file:      "erc4626.json",
+			standard:  ERC4626,
+			functions: 16,
+			events:    2,
+		},
+		{
+			name:      "ERC-5805 Votes",
+			file:      "erc5805.json",
+			standard:  ERC5805,
+			functions: 8,
+			events:    2,
+			optional:  []string{"getPastTotalSupply"},
+		},
+		{
+			name:      "ERC-6551 Account",
+			file:      "erc6551.json",
+			standard:  ERC6551,
+			functions: 4,
+		},
+		{
+			name:      "Uniswap V3 Pool",
+			file:      "uniswapv3.json",
+			standard:  UNISWAPV3,
+			functions: 15,
+			events:    6,
+			optional:  []string{"Flash", "flash", "observe", "increaseObservationCardinalityNext"},
+			expectedInputType: map[string]string{
+				"observe": "uint32[]",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := definitionsFS.ReadFile("definitions/" + tt.file)
+			require.NoError(t, err)
+
+			definition, err := NewDefinitionFromBytes(data)
+			require.NoError(t, err)
+
+			standard, err := definition.ToContractStandard()
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.standard, standard.Type)
+			assert.NotEmpty(t, standard.Name)
+			assert.NotEmpty(t, standard.Url)
+			assert.NotEmpty(t, standard.ABI)
+			assert.Len(t, standard.Functions, tt.functions)
+			assert.Len(t, standard.Events, tt.events)
+
+			optional := make([]string, 0)
+			matcher := &ContractMatcher{Name: tt.name}
+			for _, fn := range standard.Functions {
+				if fn.Optional {
+					optional = append(optional, fn.Name)
+					continue
+				}
+				matcher.Functions = append(matcher.Functions, fn)
+			}
+			for _, event := range standard.Events {
+				if event.Optional {
+					optional = append(optional, event.Name)
+					continue
+				}
+				matcher.Events = append(matcher.Events, event)
+			}
+			assert.ElementsMatch(t, tt.optional, optional)
+
+			for _, fn := range standard.Functions {
+				if inputType, ok := tt.expectedInputType[fn.Name]; ok {
+					require.NotEmpty(t, fn.Inputs)
+					assert.Equal(t, inputType, fn.Inputs[0].Type)
+				}
+			}
+
+			// Implementation omitting the optional entries still fully matches the standard.
+			contract := NewContract(standard)
+			discovery, found := contract.ConfidenceCheck(matcher)
+			assert.True(t, found)
+			assert.Equal(t, PerfectConfidence, discovery.Confidence)
+			assert.Equal(t, contract.TokenCount(), discovery.MaximumTokens)
+
+			// Standards unknown to the protobuf enum are still convertible.
+			assert.NotNil(t, contract.ToProto())
+			assert.Equal(t, standard.Type.ToProto(), contract.ToProto().GetType())
+		})
+	}
+}
+
+func TestNewContractStandardFromABI(t *testing.T) {
+	tests := []struct {
+		name             string
+		abi              string
+		expectedInputs   []Input
+		expectedOutputs  []Output
+		expectedEvents   int
+		expectedError    bool
+		expectedFunction string
+	}{
+		{
+			name:             "Struct Tuple",
+			abi:              `[{"type":"function","name":"handle","inputs":[{"name":"op","type":"tuple","internalType":"struct Operation","components":[{"name":"to","type":"address"},{"name":"value","type":"uint256"}]}],"outputs":[{"name":"","type":"bool"}]}]`,
+			expectedFunction: "handle",
+			expectedInputs:   []Input{{Type: "struct Operation"}},
+			expectedOutputs:  []Output{{Type: TypeBool}},
+		},
+		{
+			name: "Anonymous Tuple Array And Event",
+			abi: `[
+				{"type":"function","name":"batch","inputs":[{"name":"calls","type":"tuple[]","components":[{"name":"target","type":"address"},{"name":"data","type":"bytes"}]}],"outputs":[]},
+				{"type":"event","name":"Executed","inputs":[{"name":"target","type":"address","indexed":true}]},
+				{"type":"error","name":"Failed","inputs":[]},
+				{"type":"constructor","inputs":[]}
+			]`,
+			expectedFunction: "batch",
+			expectedInputs:   []Input{{Type: "(address,bytes)[]"}},
+			expectedOutputs:  []Output{},
+			expectedEvents:   1,
+		},
+		{
+			name:          "Invalid ABI",
+			abi:           `{"type":"function"}`,
+			expectedError: true,
+		},
+		{
+			name:          "Empty ABI",
+			abi:           `[{"type":"constructor","inputs":[]}]`,
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			standard, err := NewContractStandardFromABI(Standard("TEST"), tt.name, "", []byte(tt.abi))
+			if tt.expectedError {
+				assert.ErrorIs(t, err, ErrInvalidDefinition)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, standard.Functions, 1)
+			assert.Equal(t, tt.expectedFunction, standard.Functions[0].Name)
+			assert.Equal(t, tt.expectedInputs, standard.Functions[0].Inputs)
+			assert.Equal(t, tt.expectedOutputs, standard.Functions[0].Outputs)
+			assert.Len(t, standard.Events, tt.expectedEvents)
+			assert.NotContains(t, standard.ABI, "\n")
+		})
+	}
+}
+
+func TestLoadStandardsFromDir(t *testing.T) {
+	dir := t.TempDir()
+
+	yamlDefinition := `name: Test YAML Standard
+url: https://example.com/yaml
+type: TESTYAML
+functions:
+  - name: ping
+    inputs:
+      - type: address
+    outputs:
+      - type: bool
+  - name: version
+    outputs:
+      - type: string
+    optional: true
+events:
+  - name: Pinged
+    inputs:
+      - type: address
+        indexed: true
+`
+	jsonDefinition := `{
+	"name": "Test JSON Standard",
+	"type": "TESTJSON",
+	"optional": ["Ponged"],
+	"abi": "[{\"type\":\"function\",\"name\":\"pong\",\"inputs\":[],\"outputs\":[]},{\"type\":\"event\",\"name\":\"Ponged\",\"inputs\":[]}]"
+}`
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.yaml"), []byte(yamlDefinition), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b.json"), []byte(jsonDefinition), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("not a definition"), 0600))
+
+	loaded, err := LoadStandardsFromDir(dir)
+	require.NoError(t, err)
+	require.Len(t, loaded, 2)
+
+	yamlStandard, exists := GetStandard(Standard("TESTYAML"))
+	require.True(t, exists)
+	assert.Equal(t, "Test YAML Standard", yamlStandard.GetName())
+	require.Len(t, yamlStandard.GetFunctions(), 2)
+	assert.False(t, yamlStandard.GetFunctions()[0].Optional)
+	assert.True(t, yamlStandard.GetFunctions()[1].Optional)
+	assert.Equal(t, []Input{{Type: TypeAddress, Indexed: true}}, yamlStandard.GetEvents()[0].Inputs)
+	assert.Equal(t, eip_pb.Standard_UNKNOWN, yamlStandard.ToProto().GetType())
+
+	jsonStandard, exists := GetStandard(Standard("TESTJSON"))
+	require.True(t, exists)
+	assert.Equal(t, "pong", jsonStandard.GetFunctions()[0].Name)
+	assert.True(t, jsonStandard.GetEvents()[0].Optional)
+	assert.NotEmpty(t, jsonStandard.GetABI())
+
+	// Registering the same standard twice must fail instead of silently replacing it.
+	_, err = LoadStandardFromFile(filepath.Join(dir, "a.yaml"))
+	assert.EqualError(t, err, "failed to load standard from "+filepath.Join(dir, "a.yaml")+": standard TESTYAML already exists")
+
+	_, err = LoadStandardsFromDir(filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+}
+
+func TestDefinitionValidation(t *testing.T) {
+	tests := []struct {
+		name       string
+		definition string
+	}{
+		{
+			name:       "Missing Type",
+			definition: `{"name": "Missing Type", "functions": [{"name": "ping"}]}`,
+		},
+		{
+			name:       "Missing Name",
+			definition: `{"type": "TESTINVALID", "functions": [{"name": "ping"}]}`,
+		},
+		{
+			name:       "No Functions Or Events",
+			definition: `{"name": "Empty", "type": "TESTINVALID"}`,
+		},
+		{
+			name:       "Unknown Optional Entry",
+			definition: `{"name": "Unknown Optional", "type": "TESTINVALID", "optional": ["pong"], "functions": [{"name": "ping"}]}`,
+		},
+		{
+			name:       "Invalid ABI",
+			definition: `{"name": "Invalid ABI", "type": "TESTINVALID", "abi": "not an abi"}`,
+		},
+		{
+			name:       "Invalid Thresholds",
+			definition: `{"name": "Invalid Thresholds", "type": "TESTINVALID", "thresholds": {"high": 0.5, "medium": 0.9, "low": 0.1}, "functions": [{"name": "ping"}]}`,
+		},
+		{
+			name:       "Invalid YAML",
+			definition: "name: [unterminated",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadStandardFromBytes([]byte(tt.definition))
+			assert.ErrorIs(t, err, ErrInvalidDefinition)
+			assert.False(t, Exists(Standard("TESTINVALID")))
+		})
+	}
+}
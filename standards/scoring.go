@@ -0,0 +1,246 @@
+package standards
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// MandatoryWeight is the weight of the tokens of functions and events every implementation has to provide.
+	MandatoryWeight = 1.0
+
+	// OptionalWeight is the weight of the tokens of optional functions and events. Optional members are scored
+	// only when implemented, and with the lower weight they cannot outweigh missing mandatory members.
+	OptionalWeight = 0.5
+
+	// ReturnTypePenalty is the number of tokens deducted from a matched function for each return value of
+	// the wrong type, as well as for returning more values than the standard defines.
+	ReturnTypePenalty = 2
+
+	// MutabilityPenalty is the number of tokens deducted from a matched function whose state mutability
+	// contradicts the standard, e.g. a state changing balanceOf() or a read-only transfer().
+	MutabilityPenalty = 2
+)
+
+// MemberKind represents the kind of the standard member being explained.
+type MemberKind string
+
+const (
+	// FunctionMember represents the standard function.
+	FunctionMember MemberKind = "function"
+
+	// EventMember represents the standard event.
+	EventMember MemberKind = "event"
+)
+
+// MatchStatus represents the outcome of matching the standard member against the contract.
+type MatchStatus string
+
+const (
+	// MatchStatusMatched means the contract implements the member exactly as the standard defines it.
+	MatchStatusMatched MatchStatus = "matched"
+
+	// MatchStatusMismatched means the contract implements the member, but its signature, return types or
+	// mutability differ from the standard.
+	MatchStatusMismatched MatchStatus = "mismatched"
+
+	// MatchStatusMissing means the contract does not implement the member.
+	MatchStatusMissing MatchStatus = "missing"
+)
+
+// Explanation describes how a single function or event of the standard contributed to the discovery score.
+type Explanation struct {
+	Kind             MemberKind  `json:"kind"`              // Kind of the standard member.
+	Name             string      `json:"name"`              // Name of the standard member.
+	Signature        string      `json:"signature"`         // Signature of the standard member, e.g. "transfer(address,uint256)".
+	Status           MatchStatus `json:"status"`            // Outcome of the matching.
+	Optional         bool        `json:"optional"`          // Whether the member is optional within the standard.
+	Weight           float64     `json:"weight"`            // Weight applied to the member tokens.
+	MaximumTokens    int         `json:"maximum_tokens"`    // Number of tokens of the standard member.
+	DiscoveredTokens int         `json:"discovered_tokens"` // Number of tokens matched by the contract.
+	Penalty          int         `json:"penalty"`           // Number of tokens deducted for the mismatches.
+	Reasons          []string    `json:"reasons,omitempty"` // Reasons of the mismatched or missing status.
+}
+
+// String returns the human readable explanation, e.g. "function allowance(address,address): mismatched (...)".
+func (e Explanation) String() string {
+	toReturn := fmt.Sprintf("%s %s: %s", e.Kind, e.Signature, e.Status)
+	if len(e.Reasons) > 0 {
+		toReturn += " (" + strings.Join(e.Reasons, "; ") + ")"
+	}
+	return toReturn
+}
+
+// scorer accumulates the weighted tokens and explanations of the standard members.
+type scorer struct {
+	discovered   float64
+	maximum      float64
+	explanations []Explanation
+}
+
+// add accounts for the explained member. Missing optional members do not count towards the maximum,
+// while penalties are deducted from the discovered tokens of the member.
+func (s *scorer) add(explanation Explanation) {
+	explanation.Weight = MandatoryWeight
+	if explanation.Optional {
+		explanation.Weight = OptionalWeight
+	}
+
+	if explanation.Status != MatchStatusMissing || !explanation.Optional {
+		s.maximum += explanation.Weight * float64(explanation.MaximumTokens)
+	}
+
+	if scored := explanation.DiscoveredTokens - explanation.Penalty; scored > 0 {
+		s.discovered += explanation.Weight * float64(scored)
+	}
+
+	s.explanations = append(s.explanations, explanation)
+}
+
+// points returns the confidence points as the ratio of the weighted discovered and maximum tokens.
+func (s *scorer) points() float64 {
+	if s.maximum == 0 {
+		return 0
+	}
+	return s.discovered / s.maximum
+}
+
+// explainMissing returns the explanation of the standard member the contract does not implement.
+func explainMissing(kind MemberKind, name string, inputs []Input, optional bool, maximumTokens int, reason string) Explanation {
+	return Explanation{
+		Kind:          kind,
+		Name:          name,
+		Signature:     memberSignature(name, inputs),
+		Status:        MatchStatusMissing,
+		Optional:      optional,
+		MaximumTokens: maximumTokens,
+		Reasons:       []string{reason},
+	}
+}
+
+// explainFunction returns the explanation of the standard function matched by the contract function, where
+// matched is the result of the FunctionMatch holding the matched flags of the standard inputs and outputs.
+func explainFunction(standardFunction, contractFunction, matched Function, discoveredTokens int) Explanation {
+	toReturn := Explanation{
+		Kind:             FunctionMember,
+		Name:             standardFunction.Name,
+		Signature:        memberSignature(standardFunction.Name, standardFunction.Inputs),
+		Optional:         standardFunction.Optional,
+		MaximumTokens:    FunctionTokenCount(standardFunction),
+		DiscoveredTokens: discoveredTokens,
+		Reasons:          inputMismatches(standardFunction.Inputs, contractFunction.Inputs, matched.Inputs, false),
+	}
+
+	for i, output := range matched.Outputs {
+		if output.Matched {
+			continue
+		}
+
+		switch {
+		case i >= len(contractFunction.Outputs):
+			toReturn.Reasons = append(toReturn.Reasons, fmt.Sprintf("missing return value %d of type %s", i, output.Type))
+			toReturn.Penalty += ReturnTypePenalty
+		case isUnknownType(contractFunction.Outputs[i].Type):
+			// Return types that could not be resolved are not held against the contract.
+			toReturn.Reasons = append(toReturn.Reasons, fmt.Sprintf("unknown type of return value %d, expected %s", i, output.Type))
+		default:
+			toReturn.Reasons = append(toReturn.Reasons, fmt.Sprintf("return value %d is %s, expected %s", i, contractFunction.Outputs[i].Type, output.Type))
+			toReturn.Penalty += ReturnTypePenalty
+		}
+	}
+
+	if len(contractFunction.Outputs) > len(standardFunction.Outputs) {
+		toReturn.Reasons = append(toReturn.Reasons, fmt.Sprintf("returns %d values, expected %d", len(contractFunction.Outputs), len(standardFunction.Outputs)))
+		toReturn.Penalty += ReturnTypePenalty
+	}
+
+	if !isMutabilityCompatible(standardFunction.StateMutability, contractFunction.StateMutability) {
+		toReturn.Reasons = append(toReturn.Reasons, fmt.Sprintf("state mutability is %s, expected %s", contractFunction.StateMutability, standardFunction.StateMutability))
+		toReturn.Penalty += MutabilityPenalty
+	}
+
+	toReturn.Status = matchStatus(toReturn)
+	return toReturn
+}
+
+// explainEvent returns the explanation of the standard event matched by the contract event, where matched
+// is the result of the EventMatch holding the matched flags of the standard inputs.
+func explainEvent(standardEvent, contractEvent, matched Event, discoveredTokens int) Explanation {
+	toReturn := Explanation{
+		Kind:             EventMember,
+		Name:             standardEvent.Name,
+		Signature:        memberSignature(standardEvent.Name, standardEvent.Inputs),
+		Optional:         standardEvent.Optional,
+		MaximumTokens:    EventTokenCount(standardEvent),
+		DiscoveredTokens: discoveredTokens,
+		Reasons:          inputMismatches(standardEvent.Inputs, contractEvent.Inputs, matched.Inputs, true),
+	}
+
+	toReturn.Status = matchStatus(toReturn)
+	return toReturn
+}
+
+// inputMismatches lists the standard inputs missing from the contract member and, for events, the inputs
+// whose indexed flag differs from the standard.
+func inputMismatches(standardInputs, contractInputs, matchedInputs []Input, indexed bool) []string {
+	toReturn := make([]string, 0)
+
+	for i, input := range matchedInputs {
+		if !input.Matched {
+			toReturn = append(toReturn, fmt.Sprintf("missing input %d of type %s", i, input.Type))
+		}
+	}
+
+	if len(contractInputs) != len(standardInputs) {
+		toReturn = append(toReturn, fmt.Sprintf("takes %d inputs, expected %d", len(contractInputs), len(standardInputs)))
+	} else if indexed {
+		for i, input := range standardInputs {
+			if input.Indexed != contractInputs[i].Indexed {
+				toReturn = append(toReturn, fmt.Sprintf("input %d indexed is %t, expected %t", i, contractInputs[i].Indexed, input.Indexed))
+			}
+		}
+	}
+
+	return toReturn
+}
+
+// matchStatus returns the status of the member implemented by the contract.
+func matchStatus(explanation Explanation) MatchStatus {
+	if len(explanation.Reasons) > 0 || explanation.DiscoveredTokens < explanation.MaximumTokens {
+		return MatchStatusMismatched
+	}
+	return MatchStatusMatched
+}
+
+// memberSignature returns the signature of the function or event, e.g. "transfer(address,uint256)".
+func memberSignature(name string, inputs []Input) string {
+	types := make([]string, 0, len(inputs))
+	for _, input := range inputs {
+		types = append(types, input.Type)
+	}
+	return fmt.Sprintf("%s(%s)", name, strings.Join(types, ","))
+}
+
+// isUnknownType returns true if the type of the contract parameter could not be resolved.
+func isUnknownType(typeName string) bool {
+	return typeName == "" || typeName == "t_unknown"
+}
+
+// isMutabilityCompatible returns true unless one of the mutabilities is read-only (view or pure) while the other
+// one changes the state (nonpayable or payable). Unknown mutabilities are always compatible.
+func isMutabilityCompatible(expected, actual string) bool {
+	if expected == "" || actual == "" {
+		return true
+	}
+	return isReadOnlyMutability(expected) == isReadOnlyMutability(actual)
+}
+
+// isReadOnlyMutability returns true if the state mutability does not allow state changes.
+func isReadOnlyMutability(mutability string) bool {
+	switch strings.ToLower(mutability) {
+	case "view", "pure":
+		return true
+	default:
+		return false
+	}
+}
@@ -46,11 +46,19 @@ const (
 	ERC2917   Standard = "ERC2917"   // ERC-2917 Interest-Bearing Tokens Standard.
 	ERC3156   Standard = "ERC3156"   // ERC-3156 Flash Loans Standard.
 	ERC3664   Standard = "ERC3664"   // ERC-3664 BitWords Standard.
+	ERC2612   Standard = "ERC2612"   // ERC-2612 Permit Extension for EIP-20 Signed Approvals.
+	ERC2981   Standard = "ERC2981"   // ERC-2981 NFT Royalty Standard.
+	ERC4337   Standard = "ERC4337"   // ERC-4337 Account Abstraction Using Alt Mempool.
+	ERC4626   Standard = "ERC4626"   // ERC-4626 Tokenized Vaults.
+	ERC5805   Standard = "ERC5805"   // ERC-5805 Voting with Delegation.
+	ERC6551   Standard = "ERC6551"   // ERC-6551 Non-fungible Token Bound Accounts.
 	UNISWAPV2 Standard = "UNISWAPV2" // Uniswap V2 Core.
+	UNISWAPV3 Standard = "UNISWAPV3" // Uniswap V3 Core.
 	OZOWNABLE Standard = "OZOWNABLE" // OpenZeppelin Ownable.
 )
 
 // LoadStandards loads list of supported Ethereum EIPs into the registry.
+// Standards shipped as definition files, see definitions directory, are loaded as well.
 func LoadStandards() error {
 	for name, standard := range standards {
 		if err := RegisterStandard(name, NewContract(standard)); err != nil {
@@ -58,5 +66,10 @@ func LoadStandards() error {
 		}
 	}
 
+	if _, err := loadDefinitions(definitionsFS, "definitions"); err != nil {
+		return err
+	}
+
+	loaded = true
 	return nil
 }
@@ -8,6 +8,9 @@ import (
 // storage is a map that holds registered Ethereum standards.
 var storage map[Standard]EIP
 
+// loaded indicates whether the standards shipped with the package were loaded by LoadStandards.
+var loaded bool
+
 // RegisterStandard registers a new Ethereum standard to the storage.
 // If the standard already exists, it returns an error.
 //
@@ -39,6 +42,31 @@ func GetStandard(s Standard) (EIP, bool) {
 	return cs, exists
 }
 
+// SetStandardThresholds overrides the confidence thresholds of a registered Ethereum standard.
+// The standard is re-registered with the thresholds, so the change applies to subsequent confidence checks.
+//
+// Parameters:
+// - s: The Ethereum standard type.
+// - thresholds: The confidence thresholds of the standard.
+//
+// Returns:
+// - error: An error if the standard is not registered or the thresholds are invalid, otherwise nil.
+func SetStandardThresholds(s Standard, thresholds Thresholds) error {
+	if err := thresholds.Validate(); err != nil {
+		return err
+	}
+
+	eip, exists := storage[s]
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrStandardNotFound, s)
+	}
+
+	cs := eip.GetStandard()
+	cs.Thresholds = &thresholds
+	storage[s] = NewContract(cs)
+	return nil
+}
+
 // Exists checks if a given Ethereum standard is registered in the storage.
 //
 // Parameters:
@@ -75,7 +103,8 @@ func GetSortedRegisteredStandards() []EIP {
 	return eips
 }
 
-// StandardsLoaded returns a boolean indicating whether the storage has any registered Ethereum standards.
+// StandardsLoaded returns a boolean indicating whether the standards shipped with the package were loaded by
+// LoadStandards. Standards registered one by one through RegisterStandard do not count.
 func StandardsLoaded() bool {
-	return len(storage) > 1
+	return loaded
 }
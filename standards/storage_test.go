@@ -94,6 +94,39 @@ func TestEIPStorage(t *testing.T) {
 			expectedExists: true,
 			expectedError:  "standard ERC1967 already exists",
 		},
+		{
+			name: "Test ERC4626",
+			standard: func() EIP {
+				standard, err := GetContractByStandard(ERC4626)
+				assert.NoError(t, err)
+				assert.NotNil(t, standard)
+				return standard
+			}(),
+			expectedExists: true,
+			expectedError:  "standard ERC4626 already exists",
+		},
+		{
+			name: "Test ERC5805",
+			standard: func() EIP {
+				standard, err := GetContractByStandard(ERC5805)
+				assert.NoError(t, err)
+				assert.NotNil(t, standard)
+				return standard
+			}(),
+			expectedExists: true,
+			expectedError:  "standard ERC5805 already exists",
+		},
+		{
+			name: "Test UNISWAPV3",
+			standard: func() EIP {
+				standard, err := GetContractByStandard(UNISWAPV3)
+				assert.NoError(t, err)
+				assert.NotNil(t, standard)
+				return standard
+			}(),
+			expectedExists: true,
+			expectedError:  "standard UNISWAPV3 already exists",
+		},
 	}
 
 	for _, tt := range tests {
@@ -162,3 +195,49 @@ func TestEIPStorage(t *testing.T) {
 		})
 	}
 }
+
+func TestSetStandardThresholds(t *testing.T) {
+	if !Exists(ERC20) {
+		assert.NoError(t, LoadStandards())
+	}
+
+	original, _ := GetStandard(ERC20)
+	defer func() {
+		storage[ERC20] = original
+	}()
+
+	thresholds := Thresholds{High: 0.95, Medium: 0.8, Low: 0.3}
+	assert.NoError(t, SetStandardThresholds(ERC20, thresholds))
+
+	standard, err := GetContractByStandard(ERC20)
+	assert.NoError(t, err)
+	cs := standard.GetStandard()
+	assert.Equal(t, thresholds, cs.GetThresholds())
+
+	registered, _ := GetStandard(ERC20)
+	cs = registered.GetStandard()
+	assert.Equal(t, thresholds, cs.GetThresholds())
+	assert.Equal(t, original.GetFunctions(), registered.GetFunctions())
+
+	assert.ErrorIs(t, SetStandardThresholds(ERC20, Thresholds{High: 1.5, Medium: 0.8, Low: 0.3}), ErrInvalidThresholds)
+	assert.ErrorIs(t, SetStandardThresholds(Standard("NOT_REGISTERED"), thresholds), ErrStandardNotFound)
+}
+
+func TestStandardsLoaded(t *testing.T) {
+	registered, wasLoaded := storage, loaded
+	defer func() {
+		storage, loaded = registered, wasLoaded
+	}()
+
+	storage, loaded = make(map[Standard]EIP), false
+	assert.False(t, StandardsLoaded())
+
+	// Standards registered one by one do not mark the shipped standards as loaded.
+	assert.NoError(t, RegisterStandard(ERC20, NewContract(standards[ERC20])))
+	assert.NoError(t, RegisterStandard(ERC721, NewContract(standards[ERC721])))
+	assert.False(t, StandardsLoaded())
+
+	storage = make(map[Standard]EIP)
+	assert.NoError(t, LoadStandards())
+	assert.True(t, StandardsLoaded())
+}
@@ -2,44 +2,19 @@ package standards
 
 // TokenCount calculates and returns the total number of tokens (inputs and outputs)
 // present in the functions and events of a given ContractStandard.
+// Optional functions and events are not counted as the implementation may omit them.
 func TokenCount(cs ContractStandard) int {
 	count := 0
 
 	for _, function := range cs.Functions {
-		count++
-
-		for _, input := range function.Inputs {
-			count++
-			if len(input.Type) > 0 {
-				count++
-			}
-			count++ // Indexed is always counted as it's a boolean
-		}
-
-		for _, output := range function.Outputs {
-			count++
-			if len(output.Type) > 0 {
-				count++
-			}
+		if !function.Optional {
+			count += FunctionTokenCount(function)
 		}
 	}
 
 	for _, event := range cs.Events {
-		count++
-
-		for _, input := range event.Inputs {
-			count++
-			if len(input.Type) > 0 {
-				count++
-			}
-			count++ // Indexed is always counted as it's a boolean
-		}
-
-		for _, output := range event.Outputs {
-			count++
-			if len(output.Type) > 0 {
-				count++
-			}
+		if !event.Optional {
+			count += EventTokenCount(event)
 		}
 	}
 
@@ -71,3 +46,13 @@ func FunctionTokenCount(fn Function) int {
 
 	return count
 }
+
+// EventTokenCount calculates the total number of tokens present in a given Ethereum smart contract event.
+// Events are counted the same way as functions, the event name being the initial token.
+func EventTokenCount(event Event) int {
+	return FunctionTokenCount(Function{
+		Name:    event.Name,
+		Inputs:  event.Inputs,
+		Outputs: event.Outputs,
+	})
+}
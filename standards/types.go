@@ -84,6 +84,14 @@ type Function struct {
 
 	// Matched indicates whether the input has been matched via confidence check.
 	Matched bool `json:"matched"`
+
+	// Optional indicates whether the function may be omitted by the implementation without
+	// lowering the confidence, e.g. ERC-20 name() or ERC-2981 supportsInterface().
+	Optional bool `json:"optional,omitempty"`
+
+	// StateMutability specifies the state mutability of the function, e.g. "view" or "nonpayable".
+	// It is empty when unknown, in which case the mutability is not taken into the confidence check.
+	StateMutability string `json:"state_mutability,omitempty"`
 }
 
 // ToProto converts the Function to its protobuf representation.
@@ -120,6 +128,10 @@ type Event struct {
 
 	// Matched indicates whether the input has been matched via confidence check.
 	Matched bool `json:"matched"`
+
+	// Optional indicates whether the event may be omitted by the implementation without
+	// lowering the confidence.
+	Optional bool `json:"optional,omitempty"`
 }
 
 // ToProto converts the Event to its protobuf representation.
@@ -161,11 +173,26 @@ type ContractStandard struct {
 	// ABI specifies the ABI of the contract standard.
 	ABI string `json:"abi"`
 
+	// InterfaceId specifies the ERC-165 interface identifier of the standard, e.g., "0x80ac58cd" for ERC-721.
+	// It is empty for standards that do not define one.
+	InterfaceId string `json:"interface_id,omitempty"`
+
 	// Functions is a slice of Function structs, representing the functions defined in the contract standard.
 	Functions []Function `json:"functions"`
 
 	// Events is a slice of Event structs, representing the events defined in the contract standard.
 	Events []Event `json:"events"`
+
+	// Thresholds overrides the default confidence thresholds for the standard, if set.
+	Thresholds *Thresholds `json:"thresholds,omitempty"`
+}
+
+// GetThresholds returns the confidence thresholds of the standard, falling back to DefaultThresholds.
+func (cs *ContractStandard) GetThresholds() Thresholds {
+	if cs.Thresholds == nil {
+		return DefaultThresholds
+	}
+	return *cs.Thresholds
 }
 
 // ToProto converts the ContractStandard to its protobuf representation.
@@ -180,15 +207,12 @@ func (cs *ContractStandard) ToProto() *eip_pb.ContractStandard {
 		protoEvents[idx] = event.ToProto()
 	}
 
-	standard, err := GetProtoStandardFromString(cs.Type.String())
-	if err != nil {
-		panic(err)
-	}
-
+	// Standards registered at runtime do not have to be known to the protobuf enum,
+	// in which case they are represented as unknown.
 	return &eip_pb.ContractStandard{
 		Name:      cs.Name,
 		Url:       cs.Url,
-		Type:      standard,
+		Type:      cs.Type.ToProto(),
 		Stagnant:  cs.Stagnant,
 		Functions: protoFunctions,
 		Events:    protoEvents,
@@ -240,13 +264,14 @@ type FunctionMatcher struct {
 
 // Discovery represents the result of attempting to discover a contract standard.
 type Discovery struct {
-	Confidence       ConfidenceLevel     `json:"confidence"`        // Confidence level of the discovery.
-	ConfidencePoints float64             `json:"confidence_points"` // Confidence points of the discovery.
-	Threshold        ConfidenceThreshold `json:"threshold"`         // Threshold level of the discovery.
-	MaximumTokens    int                 `json:"maximum_tokens"`    // Maximum number of tokens in the standard.
-	DiscoveredTokens int                 `json:"discovered_tokens"` // Number of tokens discovered in the standard.
-	Standard         Standard            `json:"standard"`          // Contract standard being scanned.
-	Contract         *ContractMatcher    `json:"contract"`          // Contract including matched functions and events.
+	Confidence       ConfidenceLevel     `json:"confidence"`             // Confidence level of the discovery.
+	ConfidencePoints float64             `json:"confidence_points"`      // Confidence points of the discovery.
+	Threshold        ConfidenceThreshold `json:"threshold"`              // Threshold level of the discovery.
+	MaximumTokens    int                 `json:"maximum_tokens"`         // Maximum number of tokens in the standard.
+	DiscoveredTokens int                 `json:"discovered_tokens"`      // Number of tokens discovered in the standard.
+	Standard         Standard            `json:"standard"`               // Contract standard being scanned.
+	Contract         *ContractMatcher    `json:"contract"`               // Contract including matched functions and events.
+	Explanations     []Explanation       `json:"explanations,omitempty"` // Explanation of the score for each function and event of the standard.
 }
 
 // ToProto converts the Discovery to its protobuf representation.
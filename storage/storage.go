@@ -4,12 +4,14 @@ import (
 	"context"
 	"fmt"
 	"math/big"
+	"sync"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/unpackdev/solgo/cfg"
 	"github.com/unpackdev/solgo/clients"
 	"github.com/unpackdev/solgo/detector"
 	"github.com/unpackdev/solgo/utils"
+	"golang.org/x/sync/errgroup"
 )
 
 // Storage is a struct that encapsulates various components required to interact with Ethereum smart contracts.
@@ -87,32 +89,60 @@ func (s *Storage) _describe(ctx context.Context, addr common.Address, detector *
 	return reader, nil
 }
 
-// populateStorageValues populates storage values for each slot in the descriptor's storage layout.
+// populateStorageValues populates storage values for each slot in the descriptor's storage layout. Distinct slots
+// are read concurrently at the same block so that the reads are coalesced into the batch requests, with no more
+// reads in flight than fit into a single batch.
 func (s *Storage) populateStorageValues(ctx context.Context, reader *Reader, addr common.Address, descriptor *Descriptor, atBlock *big.Int) error {
-	var lastStorageValue []byte
-	var lastSlot int64 = -1
-	var lastBlockNumber *big.Int
+	blockNumber, err := s.getBlockNumber(ctx, atBlock)
+	if err != nil {
+		return err
+	}
 
+	// Slots shared by the packed variables are deduplicated before any of the reads starts, the map is written
+	// only by the reads afterwards.
+	indexes := make([]int64, 0, len(descriptor.GetSlots()))
+	seen := make(map[int64]bool)
 	for _, slot := range descriptor.GetSlots() {
-		if slot.Slot != lastSlot {
-			blockNumber, storageValue, err := s.getStorageValueAt(ctx, addr, slot.Slot, atBlock)
+		if !seen[slot.Slot] {
+			seen[slot.Slot] = true
+			indexes = append(indexes, slot.Slot)
+		}
+	}
+
+	var mu sync.Mutex
+	storageValues := make(map[int64][]byte, len(indexes))
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(s.clientsPool.GetBatcher(s.network.String()).GetMaxBatchSize())
+	for _, index := range indexes {
+		index := index
+		g.Go(func() error {
+			_, storageValue, err := s.getStorageValueAt(gctx, addr, index, blockNumber)
 			if err != nil {
 				return err
 			}
 
-			if descriptor.Block == nil {
-				descriptor.Block = blockNumber
-			}
+			mu.Lock()
+			storageValues[index] = storageValue
+			mu.Unlock()
+			return nil
+		})
+	}
 
-			slot.BlockNumber = blockNumber
-			lastStorageValue = storageValue
-			lastSlot = slot.Slot
-			lastBlockNumber = blockNumber
-		}
+	if err := g.Wait(); err != nil {
+		return err
+	}
 
-		slot.BlockNumber = lastBlockNumber
-		slot.RawValue = common.BytesToHash(lastStorageValue)
-		if err := convertStorageToValue(s, addr, slot, lastStorageValue); err != nil {
+	if descriptor.Block == nil {
+		descriptor.Block = blockNumber
+	}
+
+	for _, slot := range descriptor.GetSlots() {
+		storageValue := storageValues[slot.Slot]
+
+		slot.BlockNumber = blockNumber
+		slot.RawValue = common.BytesToHash(storageValue)
+		if err := convertStorageToValue(s, addr, slot, storageValue); err != nil {
 			return err
 		}
 	}
@@ -120,25 +150,37 @@ func (s *Storage) populateStorageValues(ctx context.Context, reader *Reader, add
 	return nil
 }
 
-// getStorageValueAt retrieves the storage value at a given slot for a contract.
-func (s *Storage) getStorageValueAt(ctx context.Context, contractAddress common.Address, slot int64, blockNumber *big.Int) (*big.Int, []byte, error) {
+// getBlockNumber returns the given block number, or the number of the latest block when nil.
+func (s *Storage) getBlockNumber(ctx context.Context, blockNumber *big.Int) (*big.Int, error) {
+	if blockNumber != nil {
+		return blockNumber, nil
+	}
+
 	client := s.clientsPool.GetClientByGroup(s.network.String())
 	if client == nil {
-		return blockNumber, nil, fmt.Errorf("no client found for network %s", s.network)
+		return nil, fmt.Errorf("no client found for network %s", s.network)
 	}
 
-	if blockNumber == nil {
-		latestHeader, err := client.BlockByNumber(ctx, nil)
-		if err != nil {
-			return blockNumber, nil, fmt.Errorf("failed to get latest block header: %v", err)
-		}
-		blockNumber = latestHeader.Number()
+	latestHeader, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest block header: %v", err)
+	}
+
+	return latestHeader.Number, nil
+}
+
+// getStorageValueAt retrieves the storage value at a given slot for a contract. Reads made concurrently are
+// coalesced into the single batch request.
+func (s *Storage) getStorageValueAt(ctx context.Context, contractAddress common.Address, slot int64, blockNumber *big.Int) (*big.Int, []byte, error) {
+	blockNumber, err := s.getBlockNumber(ctx, blockNumber)
+	if err != nil {
+		return blockNumber, nil, err
 	}
 
 	bigIntIndex := big.NewInt(slot)
 	position := common.BigToHash(bigIntIndex)
 
-	response, err := client.StorageAt(ctx, contractAddress, position, blockNumber)
+	response, err := s.clientsPool.GetBatcher(s.network.String()).StorageAt(ctx, contractAddress, position, blockNumber)
 	return blockNumber, response, err
 }
 
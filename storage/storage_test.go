@@ -2,9 +2,15 @@ package storage
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"io"
 	"math/big"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -176,3 +182,102 @@ func TestStorage(t *testing.T) {
 	}
 
 }
+
+// storageNode serves net_version and eth_getStorageAt, the value of the slot being its own index, and counts
+// the reads of every slot.
+type storageNode struct {
+	mu    sync.Mutex
+	reads map[string]int
+}
+
+func (n *storageNode) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	var requests []map[string]json.RawMessage
+	batch := len(body) > 0 && body[0] == '['
+	if batch {
+		_ = json.Unmarshal(body, &requests)
+	} else {
+		var request map[string]json.RawMessage
+		_ = json.Unmarshal(body, &request)
+		requests = append(requests, request)
+	}
+
+	responses := make([]map[string]interface{}, 0, len(requests))
+	for _, request := range requests {
+		var method string
+		_ = json.Unmarshal(request["method"], &method)
+
+		var result interface{}
+		switch method {
+		case "net_version":
+			result = "1"
+		case "eth_getStorageAt":
+			var params []string
+			_ = json.Unmarshal(request["params"], &params)
+			n.mu.Lock()
+			n.reads[params[1]]++
+			n.mu.Unlock()
+			result = params[1]
+		}
+
+		responses = append(responses, map[string]interface{}{"jsonrpc": "2.0", "id": request["id"], "result": result})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if batch {
+		_ = json.NewEncoder(w).Encode(responses)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(responses[0])
+}
+
+func TestPopulateStorageValues(t *testing.T) {
+	node := &storageNode{reads: make(map[string]int)}
+	server := httptest.NewServer(node)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	pool, err := clients.NewClientPool(ctx, &clients.Options{
+		HealthCheckInterval: -1,
+		Nodes: []clients.Node{
+			{
+				Group:             string(utils.Ethereum),
+				Type:              "mainnet",
+				Endpoint:          server.URL,
+				NetworkId:         1,
+				ConcurrentClients: 1,
+			},
+		},
+	})
+	require.NoError(t, err)
+	defer pool.Close()
+
+	storage, err := NewStorage(ctx, utils.Ethereum, pool, NewDefaultOptions())
+	require.NoError(t, err)
+
+	// Packed variables share the slots, every slot must be read once and its value set on all of its variables.
+	descriptor := &Descriptor{StorageLayout: &StorageLayout{}}
+	for i := 0; i < 64; i++ {
+		descriptor.StorageLayout.Slots = append(descriptor.StorageLayout.Slots, &SlotDescriptor{
+			Name: fmt.Sprintf("value%d", i),
+			Type: "uint128",
+			Slot: int64(i/4 + 1),
+		})
+	}
+
+	err = storage.populateStorageValues(ctx, nil, common.HexToAddress("0x1"), descriptor, big.NewInt(100))
+	require.NoError(t, err)
+
+	assert.Len(t, node.reads, 16)
+	for slot, reads := range node.reads {
+		assert.Equal(t, 1, reads, slot)
+	}
+
+	for _, slot := range descriptor.GetSlots() {
+		assert.Equal(t, big.NewInt(slot.Slot), slot.Value, slot.Name)
+		assert.Equal(t, big.NewInt(100), slot.BlockNumber, slot.Name)
+	}
+}
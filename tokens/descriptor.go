@@ -4,6 +4,9 @@ import (
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/unpackdev/solgo/pricing"
+	"github.com/unpackdev/solgo/simulator"
+	"github.com/unpackdev/solgo/utils"
 	"github.com/unpackdev/solgo/utils/entities"
 )
 
@@ -20,6 +23,19 @@ type Descriptor struct {
 	TotalBurnedSupply *big.Int        `json:"total_burned_supply"` // Supply of tokens that have been burned.
 	LatestBlockNumber *big.Int        `json:"latest_block_number"` // Latest block number processed by the application.
 	Entity            *entities.Token `json:"-"`                   // Associated token entity, not serialized to JSON.
+
+	BuyTax      float64                `json:"buy_tax"`              // Effective buy tax percentage measured by the trade simulation.
+	SellTax     float64                `json:"sell_tax"`             // Effective sell tax percentage measured by the trade simulation.
+	SafetyState utils.SafetyStateType  `json:"safety_state"`         // Safety state of the token determined by the trade simulation.
+	AntiWhale   []utils.AntiWhaleType  `json:"anti_whale"`           // Anti-whale measures detected by the trade simulation.
+	Blacklists  []utils.BlacklistType  `json:"blacklists"`           // Blacklist categories the token falls into.
+	Simulation  *simulator.TradeReport `json:"simulation,omitempty"` // Trade simulation report the safety details are based on.
+
+	Distribution *HolderDistribution `json:"distribution,omitempty"` // Holder distribution replayed from the Transfer logs.
+
+	PriceUSD     float64            `json:"price_usd"`           // USD price of the token read from its most liquid pool.
+	LiquidityUSD float64            `json:"liquidity_usd"`       // USD value of the reserves of the most liquid pool of the token.
+	Valuation    *pricing.Valuation `json:"valuation,omitempty"` // Valuation the price and liquidity are based on.
 }
 
 // GetAddress returns the Ethereum address of the token contract.
@@ -66,3 +82,79 @@ func (d *Descriptor) GetTotalCirculatingSupply() *big.Int {
 
 	return new(big.Int).Sub(d.TotalSupply, d.TotalBurnedSupply)
 }
+
+// GetBuyTax returns the effective buy tax percentage of the token.
+func (d *Descriptor) GetBuyTax() float64 {
+	return d.BuyTax
+}
+
+// GetSellTax returns the effective sell tax percentage of the token.
+func (d *Descriptor) GetSellTax() float64 {
+	return d.SellTax
+}
+
+// GetSafetyState returns the safety state of the token, UnknownSafetyState until the token is simulated.
+func (d *Descriptor) GetSafetyState() utils.SafetyStateType {
+	if d.SafetyState == "" {
+		return utils.UnknownSafetyState
+	}
+	return d.SafetyState
+}
+
+// GetAntiWhale returns the anti-whale measures of the token.
+func (d *Descriptor) GetAntiWhale() []utils.AntiWhaleType {
+	return d.AntiWhale
+}
+
+// GetBlacklists returns the blacklist categories the token falls into.
+func (d *Descriptor) GetBlacklists() []utils.BlacklistType {
+	return d.Blacklists
+}
+
+// IsHoneypot returns true if the trade simulation found the bought tokens could not be sold or transferred.
+func (d *Descriptor) IsHoneypot() bool {
+	return d.Simulation != nil && d.Simulation.IsHoneypot()
+}
+
+// GetSimulation returns the trade simulation report of the token.
+func (d *Descriptor) GetSimulation() *simulator.TradeReport {
+	return d.Simulation
+}
+
+// GetDistribution returns the holder distribution replayed from the Transfer logs, if the holders were indexed.
+func (d *Descriptor) GetDistribution() *HolderDistribution {
+	return d.Distribution
+}
+
+// GetPriceUSD returns the USD price of the token read from its most liquid pool.
+func (d *Descriptor) GetPriceUSD() float64 {
+	return d.PriceUSD
+}
+
+// GetLiquidityUSD returns the USD value of the reserves of the most liquid pool of the token.
+func (d *Descriptor) GetLiquidityUSD() float64 {
+	return d.LiquidityUSD
+}
+
+// GetValuation returns the valuation the price and liquidity of the token are based on.
+func (d *Descriptor) GetValuation() *pricing.Valuation {
+	return d.Valuation
+}
+
+// SetSimulation stores the trade simulation report together with the taxes, safety state, anti-whale measures
+// and blacklist categories derived from it.
+func (d *Descriptor) SetSimulation(report *simulator.TradeReport) {
+	d.Simulation = report
+	d.BuyTax = report.GetBuyTax()
+	d.SellTax = report.GetSellTax()
+	d.SafetyState = report.GetSafetyState()
+	d.AntiWhale = report.GetAntiWhale()
+	d.Blacklists = report.GetBlacklists()
+}
+
+// SetValuation stores the valuation together with the price and liquidity derived from it.
+func (d *Descriptor) SetValuation(valuation *pricing.Valuation) {
+	d.Valuation = valuation
+	d.PriceUSD = valuation.PriceUSD
+	d.LiquidityUSD = valuation.LiquidityUSD
+}
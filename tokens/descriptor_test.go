@@ -0,0 +1,77 @@
+package tokens
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/unpackdev/solgo/pricing"
+	"github.com/unpackdev/solgo/simulator"
+	"github.com/unpackdev/solgo/utils"
+)
+
+func TestDescriptorSetSimulation(t *testing.T) {
+	tests := []struct {
+		name        string
+		report      *simulator.TradeReport
+		buyTax      float64
+		sellTax     float64
+		safetyState utils.SafetyStateType
+		antiWhale   []utils.AntiWhaleType
+		blacklists  []utils.BlacklistType
+		honeypot    bool
+	}{
+		{
+			name: "Taxed Token With Limits",
+			report: &simulator.TradeReport{
+				Buy:            &simulator.TradeResult{Success: true, Tax: 3},
+				Sell:           &simulator.TradeResult{Success: true, Tax: 12},
+				MaxTransaction: true,
+			},
+			buyTax:      3,
+			sellTax:     12,
+			safetyState: utils.WarnSafetyState,
+			antiWhale:   []utils.AntiWhaleType{utils.AntiWhaleMaxTransaction},
+			blacklists:  []utils.BlacklistType{},
+		},
+		{
+			name: "Honeypot",
+			report: &simulator.TradeReport{
+				Buy:         &simulator.TradeResult{Success: true, Tax: 1},
+				Sell:        &simulator.TradeResult{Success: false, Reason: "execution reverted"},
+				SellBlocked: true,
+			},
+			buyTax:      1,
+			safetyState: utils.UnsafeSafetyState,
+			antiWhale:   []utils.AntiWhaleType{},
+			blacklists:  []utils.BlacklistType{utils.HoneypotBlacklistType},
+			honeypot:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			descriptor := &Descriptor{TotalSupply: big.NewInt(1)}
+			assert.Equal(t, utils.UnknownSafetyState, descriptor.GetSafetyState())
+
+			descriptor.SetSimulation(tt.report)
+			assert.Equal(t, tt.buyTax, descriptor.GetBuyTax())
+			assert.Equal(t, tt.sellTax, descriptor.GetSellTax())
+			assert.Equal(t, tt.safetyState, descriptor.GetSafetyState())
+			assert.Equal(t, tt.antiWhale, descriptor.GetAntiWhale())
+			assert.Equal(t, tt.blacklists, descriptor.GetBlacklists())
+			assert.Equal(t, tt.honeypot, descriptor.IsHoneypot())
+			assert.Equal(t, tt.report, descriptor.GetSimulation())
+		})
+	}
+}
+
+func TestDescriptorSetValuation(t *testing.T) {
+	valuation := &pricing.Valuation{PriceUSD: 0.25, LiquidityUSD: 1000}
+
+	descriptor := &Descriptor{}
+	descriptor.SetValuation(valuation)
+	assert.Equal(t, 0.25, descriptor.GetPriceUSD())
+	assert.Equal(t, float64(1000), descriptor.GetLiquidityUSD())
+	assert.Equal(t, valuation, descriptor.GetValuation())
+}
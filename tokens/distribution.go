@@ -0,0 +1,77 @@
+package tokens
+
+import (
+	"math/big"
+	"sort"
+)
+
+// CalculateGini calculates the Gini coefficient of the holder balances, ranging from 0 when all the holders
+// hold the same balance to nearly 1 when the single holder holds the whole supply.
+func CalculateGini(holders []Holder) float64 {
+	balances := make([]*big.Int, 0, len(holders))
+	total := new(big.Int)
+	for _, holder := range holders {
+		if holder.Balance == nil || holder.Balance.Sign() <= 0 {
+			continue
+		}
+		balances = append(balances, holder.Balance)
+		total.Add(total, holder.Balance)
+	}
+
+	if len(balances) == 0 {
+		return 0
+	}
+
+	sort.Slice(balances, func(a, b int) bool {
+		return balances[a].Cmp(balances[b]) < 0
+	})
+
+	// G = 2 * sum(i * x_i) / (n * sum(x)) - (n + 1) / n, with balances sorted ascending and i starting at 1.
+	weighted := new(big.Int)
+	for i, balance := range balances {
+		weighted.Add(weighted, new(big.Int).Mul(big.NewInt(int64(i+1)), balance))
+	}
+
+	n := float64(len(balances))
+	toReturn := 2*ratio(weighted, new(big.Int).Mul(total, big.NewInt(int64(len(balances))))) - (n+1)/n
+	return max(toReturn, 0)
+}
+
+// CalculateNakamoto calculates the Nakamoto coefficient of the holder balances, the smallest number of
+// holders controlling more than half of their total balance.
+func CalculateNakamoto(holders []Holder) int {
+	balances := make([]*big.Int, 0, len(holders))
+	total := new(big.Int)
+	for _, holder := range holders {
+		if holder.Balance == nil || holder.Balance.Sign() <= 0 {
+			continue
+		}
+		balances = append(balances, holder.Balance)
+		total.Add(total, holder.Balance)
+	}
+
+	sort.Slice(balances, func(a, b int) bool {
+		return balances[a].Cmp(balances[b]) > 0
+	})
+
+	controlled := new(big.Int)
+	for i, balance := range balances {
+		controlled.Add(controlled, balance)
+		// Strictly more than half of the total, also for the odd totals.
+		if new(big.Int).Lsh(controlled, 1).Cmp(total) > 0 {
+			return i + 1
+		}
+	}
+
+	return len(balances)
+}
+
+// ratio returns a / b as the floating point number, or 0 when b is zero.
+func ratio(a *big.Int, b *big.Int) float64 {
+	if b == nil || b.Sign() == 0 {
+		return 0
+	}
+
+	toReturn, _ := new(big.Float).Quo(new(big.Float).SetInt(a), new(big.Float).SetInt(b)).Float64()
+	return toReturn
+}
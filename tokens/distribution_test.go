@@ -0,0 +1,39 @@
+package tokens
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHolderConcentration(t *testing.T) {
+	holders := func(balances ...int64) []Holder {
+		toReturn := make([]Holder, 0, len(balances))
+		for _, balance := range balances {
+			toReturn = append(toReturn, Holder{Balance: big.NewInt(balance)})
+		}
+		return toReturn
+	}
+
+	tests := []struct {
+		name     string
+		holders  []Holder
+		gini     float64
+		nakamoto int
+	}{
+		{name: "No Holders", holders: holders(), gini: 0, nakamoto: 0},
+		{name: "Single Holder", holders: holders(100), gini: 0, nakamoto: 1},
+		{name: "Equal Holders", holders: holders(25, 25, 25, 25), gini: 0, nakamoto: 3},
+		{name: "Whale", holders: holders(1, 1, 1, 97), gini: 0.72, nakamoto: 1},
+		{name: "Exact Half", holders: holders(50, 30, 20), gini: 0.2, nakamoto: 2},
+		{name: "Empty Balances Ignored", holders: holders(0, 100, 100), gini: 0, nakamoto: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.gini, CalculateGini(tt.holders), 1e-9)
+			assert.Equal(t, tt.nakamoto, CalculateNakamoto(tt.holders))
+		})
+	}
+}
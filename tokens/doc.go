@@ -1,4 +1,11 @@
 // Package tokens provides a high-level abstraction for interacting with Ethereum tokens.
 // It offers functionalities to create, manipulate, and query token information using
-// smart contract bindings and Ethereum network clients.
+// smart contract bindings and Ethereum network clients. Besides ERC-20 tokens, the package
+// describes ERC-721 and ERC-1155 tokens, including their ERC-2981 royalties and metadata
+// documents fetched through pluggable IPFS, HTTP and data URI resolvers.
+//
+// The HolderIndexer replays the Transfer logs of the ERC-20 token over the block range, in chunks, building the
+// holder balances, the mint and burn history and the circulating supply at any indexed block, together with the
+// concentration metrics such as the Gini and Nakamoto coefficients. The indexed state is checkpointed to disk
+// for the incremental indexing.
 package tokens
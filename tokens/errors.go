@@ -0,0 +1,20 @@
+package tokens
+
+import "errors"
+
+var (
+	// ErrInvalidTokenURI is returned when the token URI cannot be resolved into the metadata document.
+	ErrInvalidTokenURI = errors.New("invalid token uri")
+
+	// ErrUnsupportedURIScheme is returned when no metadata resolver is registered for the token URI scheme.
+	ErrUnsupportedURIScheme = errors.New("unsupported token uri scheme")
+
+	// ErrNotNFT is returned when the contract implements neither the ERC-721 nor the ERC-1155 interface.
+	ErrNotNFT = errors.New("contract is neither erc721 nor erc1155 token")
+
+	// ErrInvalidBlockRange is returned when the requested blocks are out of order or outside of the indexed range.
+	ErrInvalidBlockRange = errors.New("invalid block range")
+
+	// ErrInvalidHolderCheckpoint is returned when the holder checkpoint cannot be decoded or belongs to another token.
+	ErrInvalidHolderCheckpoint = errors.New("invalid holder checkpoint")
+)
@@ -0,0 +1,514 @@
+package tokens
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/goccy/go-json"
+	"github.com/unpackdev/solgo/utils"
+)
+
+// DefaultHolderChunkSize is the number of blocks the holder indexer requests logs for at once.
+const DefaultHolderChunkSize = 5000
+
+// TransferEventTopic is the topic of the ERC-20 and ERC-721 Transfer(address,address,uint256) event.
+var TransferEventTopic = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+
+// LogFilterer is the source of the token Transfer logs. It is satisfied by clients.Client.
+type LogFilterer interface {
+	FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error)
+}
+
+// HolderIndexerOptions configures the HolderIndexer.
+type HolderIndexerOptions struct {
+	ChunkSize      uint64 `json:"chunk_size"`      // Number of blocks requested at once, DefaultHolderChunkSize when not set.
+	CheckpointPath string `json:"checkpoint_path"` // File the indexed state is saved to after every chunk, optional.
+}
+
+// SupplyChange represents the single mint or burn of the token.
+type SupplyChange struct {
+	Type        utils.LogEventType `json:"type"`         // MintLogEventType or BurnLogEventType.
+	BlockNumber uint64             `json:"block_number"` // Block the supply changed in.
+	TxHash      common.Hash        `json:"tx_hash"`      // Transaction the supply changed in.
+	LogIndex    uint               `json:"log_index"`    // Index of the Transfer log within the block.
+	Account     common.Address     `json:"account"`      // Recipient of the minted or sender of the burned tokens.
+	Amount      *big.Int           `json:"amount"`       // Amount of the minted or burned tokens.
+}
+
+// Supply represents the supply of the token at the specific block, as replayed from the Transfer logs.
+type Supply struct {
+	BlockNumber uint64   `json:"block_number"` // Block the supply was calculated at.
+	Minted      *big.Int `json:"minted"`       // Total amount of the minted tokens.
+	Burned      *big.Int `json:"burned"`       // Total amount of the tokens sent to the BurnAddresses.
+	Circulating *big.Int `json:"circulating"`  // Minted tokens that were not burned.
+}
+
+// Holder represents the account holding the token.
+type Holder struct {
+	Address common.Address `json:"address"` // Address of the holder.
+	Balance *big.Int       `json:"balance"` // Balance of the holder.
+	Share   float64        `json:"share"`   // Share of the balance held by all the holders, from 0 to 1.
+}
+
+// HolderDistribution describes how concentrated the balance held by the holders of the token is. It equals the
+// circulating supply when the indexed blocks include all the mints of the token.
+type HolderDistribution struct {
+	BlockNumber     uint64   `json:"block_number"`      // Last block the distribution is based on.
+	Holders         int      `json:"holders"`           // Number of accounts holding the token, excluding BurnAddresses.
+	Gini            float64  `json:"gini"`              // Gini coefficient of the balances, from 0 (equal) to 1 (single holder).
+	Nakamoto        int      `json:"nakamoto"`          // Smallest number of holders controlling the majority of the supply.
+	TopHoldersShare float64  `json:"top_holders_share"` // Share of the balance held by the top holders.
+	TopHolders      []Holder `json:"top_holders"`       // Largest holders of the token.
+
+	// NegativeBalances lists the accounts whose replayed balance is negative, not counted as the holders. They
+	// received tokens before the indexed blocks, or without the Transfer logs, such as the rebasing tokens do.
+	NegativeBalances []Holder `json:"negative_balances,omitempty"`
+}
+
+// HolderCheckpoint is the state of the HolderIndexer, saved to disk to resume indexing incrementally.
+type HolderCheckpoint struct {
+	Address   common.Address              `json:"address"`    // Address of the token.
+	FromBlock uint64                      `json:"from_block"` // First indexed block.
+	LastBlock uint64                      `json:"last_block"` // Last fully indexed block.
+	Balances  map[common.Address]*big.Int `json:"balances"`   // Balances of the accounts at the last block.
+	History   []SupplyChange              `json:"history"`    // Mints and burns in the order they happened.
+}
+
+// HolderIndexer replays the Transfer logs of the ERC-20 token to build the balances of its holders, the mint
+// and burn history and the circulating supply at any indexed block.
+type HolderIndexer struct {
+	mu         sync.RWMutex
+	address    common.Address
+	filterer   LogFilterer
+	opts       HolderIndexerOptions
+	checkpoint *HolderCheckpoint
+}
+
+// NewHolderIndexer creates a new HolderIndexer of the token. When the checkpoint file of the options exists,
+// the indexer resumes from the saved state.
+func NewHolderIndexer(address common.Address, filterer LogFilterer, opts HolderIndexerOptions) (*HolderIndexer, error) {
+	if filterer == nil {
+		return nil, errors.New("log filterer is nil")
+	}
+
+	if opts.ChunkSize == 0 {
+		opts.ChunkSize = DefaultHolderChunkSize
+	}
+
+	toReturn := &HolderIndexer{
+		address:  address,
+		filterer: filterer,
+		opts:     opts,
+	}
+
+	if opts.CheckpointPath != "" {
+		checkpoint, err := LoadHolderCheckpoint(opts.CheckpointPath)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+
+		if checkpoint != nil {
+			if checkpoint.Address != address {
+				return nil, fmt.Errorf("%w: checkpoint of %s", ErrInvalidHolderCheckpoint, checkpoint.Address.Hex())
+			}
+			toReturn.checkpoint = checkpoint
+		}
+	}
+
+	return toReturn, nil
+}
+
+// GetAddress returns the address of the indexed token.
+func (i *HolderIndexer) GetAddress() common.Address {
+	return i.address
+}
+
+// GetLastBlock returns the last fully indexed block and false if nothing was indexed yet.
+func (i *HolderIndexer) GetLastBlock() (uint64, bool) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	if i.checkpoint == nil {
+		return 0, false
+	}
+	return i.checkpoint.LastBlock, true
+}
+
+// Index replays the Transfer logs between the blocks, inclusive, in chunks. Blocks that were already indexed
+// are skipped, so the indexer can be called repeatedly with the growing block range to index incrementally.
+// The checkpoint is saved after every chunk, when the checkpoint path is configured.
+func (i *HolderIndexer) Index(ctx context.Context, fromBlock uint64, toBlock uint64) error {
+	if fromBlock > toBlock {
+		return fmt.Errorf("%w: %d-%d", ErrInvalidBlockRange, fromBlock, toBlock)
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	checkpoint := i.checkpoint
+	if checkpoint == nil {
+		// The new checkpoint is published once its first chunk is indexed.
+		checkpoint = &HolderCheckpoint{
+			Address:   i.address,
+			FromBlock: fromBlock,
+			Balances:  make(map[common.Address]*big.Int),
+			History:   make([]SupplyChange, 0),
+		}
+	} else {
+		if fromBlock < checkpoint.FromBlock {
+			return fmt.Errorf("%w: block %d precedes the first indexed block %d", ErrInvalidBlockRange, fromBlock, checkpoint.FromBlock)
+		}
+
+		if fromBlock > checkpoint.LastBlock+1 {
+			return fmt.Errorf("%w: blocks %d-%d are not indexed", ErrInvalidBlockRange, checkpoint.LastBlock+1, fromBlock-1)
+		}
+
+		fromBlock = checkpoint.LastBlock + 1
+	}
+
+	for start := fromBlock; start <= toBlock; start += i.opts.ChunkSize {
+		end := min(start+i.opts.ChunkSize-1, toBlock)
+
+		logs, err := i.filterer.FilterLogs(ctx, ethereum.FilterQuery{
+			FromBlock: new(big.Int).SetUint64(start),
+			ToBlock:   new(big.Int).SetUint64(end),
+			Addresses: []common.Address{i.address},
+			Topics:    [][]common.Hash{{TransferEventTopic}},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to filter transfer logs of blocks %d-%d: %w", start, end, err)
+		}
+
+		sort.Slice(logs, func(a, b int) bool {
+			if logs[a].BlockNumber != logs[b].BlockNumber {
+				return logs[a].BlockNumber < logs[b].BlockNumber
+			}
+			return logs[a].Index < logs[b].Index
+		})
+
+		for _, log := range logs {
+			checkpoint.apply(log)
+		}
+
+		checkpoint.LastBlock = end
+		i.checkpoint = checkpoint
+
+		if i.opts.CheckpointPath != "" {
+			if err := checkpoint.Save(i.opts.CheckpointPath); err != nil {
+				return err
+			}
+		}
+
+		// Prevents the overflow of the last chunk ending at the maximum block number.
+		if end == toBlock {
+			break
+		}
+	}
+
+	return nil
+}
+
+// apply replays the Transfer log. Removed logs and ERC-721 transfers, which index the token identifier,
+// are ignored.
+func (c *HolderCheckpoint) apply(log types.Log) {
+	if log.Removed || len(log.Topics) != 3 || len(log.Data) != common.HashLength {
+		return
+	}
+
+	from := common.BytesToAddress(log.Topics[1].Bytes())
+	to := common.BytesToAddress(log.Topics[2].Bytes())
+	amount := new(big.Int).SetBytes(log.Data)
+
+	if from != utils.ZeroAddress {
+		c.addBalance(from, new(big.Int).Neg(amount))
+	}
+	c.addBalance(to, amount)
+
+	change := SupplyChange{
+		BlockNumber: log.BlockNumber,
+		TxHash:      log.TxHash,
+		LogIndex:    log.Index,
+		Amount:      amount,
+	}
+
+	switch {
+	case from == utils.ZeroAddress:
+		change.Type, change.Account = utils.MintLogEventType, to
+	case IsBurnAddress(to):
+		change.Type, change.Account = utils.BurnLogEventType, from
+	default:
+		return
+	}
+
+	c.History = append(c.History, change)
+}
+
+// addBalance adds the amount to the balance of the account, forgetting the accounts without the balance.
+func (c *HolderCheckpoint) addBalance(account common.Address, amount *big.Int) {
+	balance, ok := c.Balances[account]
+	if !ok {
+		balance = new(big.Int)
+	}
+
+	balance = new(big.Int).Add(balance, amount)
+	if balance.Sign() == 0 {
+		delete(c.Balances, account)
+		return
+	}
+
+	c.Balances[account] = balance
+}
+
+// BalanceOf returns the balance of the account at the last indexed block.
+func (i *HolderIndexer) BalanceOf(account common.Address) *big.Int {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	if i.checkpoint == nil {
+		return big.NewInt(0)
+	}
+
+	if balance, ok := i.checkpoint.Balances[account]; ok {
+		return new(big.Int).Set(balance)
+	}
+	return big.NewInt(0)
+}
+
+// GetHistory returns the mints and burns of the token in the order they happened.
+func (i *HolderIndexer) GetHistory() []SupplyChange {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	if i.checkpoint == nil {
+		return []SupplyChange{}
+	}
+
+	toReturn := make([]SupplyChange, len(i.checkpoint.History))
+	copy(toReturn, i.checkpoint.History)
+	return toReturn
+}
+
+// GetHolders returns the holders of the token at the last indexed block, excluding the BurnAddresses,
+// sorted from the largest balance.
+func (i *HolderIndexer) GetHolders() []Holder {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	return i.holders()
+}
+
+// holders returns the holders sorted from the largest balance, with shares of the total held balance.
+func (i *HolderIndexer) holders() []Holder {
+	toReturn := make([]Holder, 0)
+	if i.checkpoint == nil {
+		return toReturn
+	}
+
+	total := new(big.Int)
+	for address, balance := range i.checkpoint.Balances {
+		if IsBurnAddress(address) || balance.Sign() <= 0 {
+			continue
+		}
+
+		total.Add(total, balance)
+		toReturn = append(toReturn, Holder{Address: address, Balance: new(big.Int).Set(balance)})
+	}
+
+	sort.Slice(toReturn, func(a, b int) bool {
+		if cmp := toReturn[a].Balance.Cmp(toReturn[b].Balance); cmp != 0 {
+			return cmp > 0
+		}
+		return toReturn[a].Address.Cmp(toReturn[b].Address) < 0
+	})
+
+	for j := range toReturn {
+		toReturn[j].Share = ratio(toReturn[j].Balance, total)
+	}
+
+	return toReturn
+}
+
+// GetNegativeBalances returns the accounts whose balance replayed from the Transfer logs is negative at the last
+// indexed block, sorted from the lowest balance. They are not counted as the holders.
+func (i *HolderIndexer) GetNegativeBalances() []Holder {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	return i.negativeBalances()
+}
+
+// negativeBalances returns the accounts with the negative balance, sorted from the lowest balance.
+func (i *HolderIndexer) negativeBalances() []Holder {
+	toReturn := make([]Holder, 0)
+	if i.checkpoint == nil {
+		return toReturn
+	}
+
+	for address, balance := range i.checkpoint.Balances {
+		if !IsBurnAddress(address) && balance.Sign() < 0 {
+			toReturn = append(toReturn, Holder{Address: address, Balance: new(big.Int).Set(balance)})
+		}
+	}
+
+	sort.Slice(toReturn, func(a, b int) bool {
+		if cmp := toReturn[a].Balance.Cmp(toReturn[b].Balance); cmp != 0 {
+			return cmp < 0
+		}
+		return toReturn[a].Address.Cmp(toReturn[b].Address) < 0
+	})
+
+	return toReturn
+}
+
+// GetDistribution returns the concentration of the balance held at the last indexed block, including the given
+// number of the top holders, none when it is not positive.
+func (i *HolderIndexer) GetDistribution(topHolders int) *HolderDistribution {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	holders := i.holders()
+	toReturn := &HolderDistribution{
+		Holders:    len(holders),
+		Gini:       CalculateGini(holders),
+		Nakamoto:   CalculateNakamoto(holders),
+		TopHolders: holders[:min(max(topHolders, 0), len(holders))],
+	}
+
+	if negative := i.negativeBalances(); len(negative) > 0 {
+		toReturn.NegativeBalances = negative
+	}
+
+	if i.checkpoint != nil {
+		toReturn.BlockNumber = i.checkpoint.LastBlock
+	}
+
+	for _, holder := range toReturn.TopHolders {
+		toReturn.TopHoldersShare += holder.Share
+	}
+
+	return toReturn
+}
+
+// SupplyAt returns the minted, burned and circulating supply at the end of the block. The block has to be
+// within the indexed range.
+func (i *HolderIndexer) SupplyAt(blockNumber uint64) (*Supply, error) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	if i.checkpoint == nil || blockNumber < i.checkpoint.FromBlock || blockNumber > i.checkpoint.LastBlock {
+		return nil, fmt.Errorf("%w: block %d is not indexed", ErrInvalidBlockRange, blockNumber)
+	}
+
+	toReturn := &Supply{
+		BlockNumber: blockNumber,
+		Minted:      new(big.Int),
+		Burned:      new(big.Int),
+	}
+
+	for _, change := range i.checkpoint.History {
+		if change.BlockNumber > blockNumber {
+			break
+		}
+
+		switch change.Type {
+		case utils.MintLogEventType:
+			toReturn.Minted.Add(toReturn.Minted, change.Amount)
+		case utils.BurnLogEventType:
+			toReturn.Burned.Add(toReturn.Burned, change.Amount)
+		}
+	}
+
+	toReturn.Circulating = new(big.Int).Sub(toReturn.Minted, toReturn.Burned)
+	return toReturn, nil
+}
+
+// Save writes the checkpoint to the file, replacing it atomically.
+func (c *HolderCheckpoint) Save(path string) error {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("failed to marshal holder checkpoint: %w", err)
+	}
+
+	tmpPath := path + ".tmp"
+	if err := utils.WriteToFile(tmpPath, data); err != nil {
+		return fmt.Errorf("failed to write holder checkpoint: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to write holder checkpoint: %w", err)
+	}
+
+	return nil
+}
+
+// LoadHolderCheckpoint reads the checkpoint from the file.
+func LoadHolderCheckpoint(path string) (*HolderCheckpoint, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read holder checkpoint: %w", err)
+	}
+
+	toReturn := &HolderCheckpoint{}
+	if err := json.Unmarshal(data, toReturn); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidHolderCheckpoint, err)
+	}
+
+	if toReturn.Balances == nil {
+		toReturn.Balances = make(map[common.Address]*big.Int)
+	}
+
+	return toReturn, nil
+}
+
+// NewHolderIndexer creates the HolderIndexer of the token, reading the Transfer logs from the token's network.
+func (t *Token) NewHolderIndexer(opts HolderIndexerOptions) (*HolderIndexer, error) {
+	client, err := t.GetClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewHolderIndexer(t.descriptor.Address, client, opts)
+}
+
+// IndexHolders indexes the Transfer logs between the blocks using the indexer and stores the holder distribution,
+// including the given number of the top holders, in the descriptor. When the indexed blocks include the mints,
+// which is the case when indexing starts at the token deployment, the total burned supply is replaced by the one
+// replayed from the Transfer logs.
+func (t *Token) IndexHolders(ctx context.Context, indexer *HolderIndexer, fromBlock uint64, toBlock uint64, topHolders int) (*Descriptor, error) {
+	if indexer == nil {
+		return nil, errors.New("holder indexer is nil")
+	}
+
+	if topHolders < 0 {
+		return nil, fmt.Errorf("invalid number of top holders: %d", topHolders)
+	}
+
+	if indexer.GetAddress() != t.descriptor.Address {
+		return nil, fmt.Errorf("holder indexer of %s cannot index %s", indexer.GetAddress().Hex(), t.descriptor.Address.Hex())
+	}
+
+	if err := indexer.Index(ctx, fromBlock, toBlock); err != nil {
+		return nil, fmt.Errorf("failed to index token holders: %w", err)
+	}
+
+	supply, err := indexer.SupplyAt(toBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	if supply.Minted.Sign() > 0 {
+		t.descriptor.TotalBurnedSupply = supply.Burned
+	}
+
+	t.descriptor.Distribution = indexer.GetDistribution(topHolders)
+	return t.descriptor, nil
+}
@@ -0,0 +1,274 @@
+package tokens
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unpackdev/solgo/utils"
+)
+
+var (
+	holderToken = common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	holderAlice = common.HexToAddress("0x00000000000000000000000000000000000a11ce")
+	holderBob   = common.HexToAddress("0x0000000000000000000000000000000000000b0b")
+	holderCarol = common.HexToAddress("0x00000000000000000000000000000000000ca201")
+	holderDead  = common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+)
+
+// mockFilterer returns the logs within the requested block range and records the requested ranges.
+type mockFilterer struct {
+	logs    []types.Log
+	queries [][2]uint64
+	err     error
+}
+
+func (f *mockFilterer) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	from, to := query.FromBlock.Uint64(), query.ToBlock.Uint64()
+	f.queries = append(f.queries, [2]uint64{from, to})
+
+	toReturn := make([]types.Log, 0)
+	// Returned in the reverse order, as the indexer must not rely on the order of the logs.
+	for i := len(f.logs) - 1; i >= 0; i-- {
+		if f.logs[i].BlockNumber >= from && f.logs[i].BlockNumber <= to {
+			toReturn = append(toReturn, f.logs[i])
+		}
+	}
+	return toReturn, nil
+}
+
+func transferLog(block uint64, index uint, from, to common.Address, amount int64) types.Log {
+	return types.Log{
+		Address:     holderToken,
+		Topics:      []common.Hash{TransferEventTopic, common.BytesToHash(from.Bytes()), common.BytesToHash(to.Bytes())},
+		Data:        common.BigToHash(big.NewInt(amount)).Bytes(),
+		BlockNumber: block,
+		Index:       index,
+	}
+}
+
+func holderLogs() []types.Log {
+	nftLog := transferLog(14, 0, holderAlice, holderBob, 1)
+	nftLog.Topics = append(nftLog.Topics, common.BigToHash(big.NewInt(1)))
+	nftLog.Data = nil
+
+	removedLog := transferLog(16, 0, holderAlice, holderBob, 500)
+	removedLog.Removed = true
+
+	return []types.Log{
+		transferLog(10, 0, utils.ZeroAddress, holderAlice, 1000),
+		transferLog(12, 1, holderAlice, holderCarol, 100),
+		transferLog(12, 0, holderAlice, holderBob, 300),
+		nftLog,
+		removedLog,
+		transferLog(20, 0, holderBob, holderDead, 100),
+		transferLog(25, 0, utils.ZeroAddress, holderCarol, 200),
+		transferLog(30, 0, holderCarol, utils.ZeroAddress, 300),
+	}
+}
+
+func TestHolderIndexer(t *testing.T) {
+	tests := []struct {
+		name      string
+		chunkSize uint64
+		ranges    [][2]uint64
+		queries   int
+	}{
+		{
+			name:      "Single Range",
+			chunkSize: 100,
+			ranges:    [][2]uint64{{0, 40}},
+			queries:   1,
+		},
+		{
+			name:      "Chunked Range",
+			chunkSize: 7,
+			ranges:    [][2]uint64{{0, 40}},
+			queries:   6,
+		},
+		{
+			name:      "Incremental Ranges",
+			chunkSize: 10,
+			ranges:    [][2]uint64{{0, 12}, {5, 21}, {22, 40}},
+			queries:   5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filterer := &mockFilterer{logs: holderLogs()}
+			indexer, err := NewHolderIndexer(holderToken, filterer, HolderIndexerOptions{ChunkSize: tt.chunkSize})
+			require.NoError(t, err)
+
+			_, ok := indexer.GetLastBlock()
+			assert.False(t, ok)
+
+			for _, r := range tt.ranges {
+				require.NoError(t, indexer.Index(context.Background(), r[0], r[1]))
+			}
+
+			assert.Len(t, filterer.queries, tt.queries)
+			lastBlock, ok := indexer.GetLastBlock()
+			assert.True(t, ok)
+			assert.Equal(t, uint64(40), lastBlock)
+
+			assert.Equal(t, big.NewInt(600), indexer.BalanceOf(holderAlice))
+			assert.Equal(t, big.NewInt(200), indexer.BalanceOf(holderBob))
+			assert.Equal(t, big.NewInt(0), indexer.BalanceOf(holderCarol))
+			assert.Equal(t, big.NewInt(100), indexer.BalanceOf(holderDead))
+
+			history := indexer.GetHistory()
+			require.Len(t, history, 4)
+			assert.Equal(t, utils.MintLogEventType, history[0].Type)
+			assert.Equal(t, holderAlice, history[0].Account)
+			assert.Equal(t, utils.BurnLogEventType, history[1].Type)
+			assert.Equal(t, holderBob, history[1].Account)
+			assert.Equal(t, utils.MintLogEventType, history[2].Type)
+			assert.Equal(t, utils.BurnLogEventType, history[3].Type)
+			assert.Equal(t, holderCarol, history[3].Account)
+
+			holders := indexer.GetHolders()
+			require.Len(t, holders, 2)
+			assert.Equal(t, holderAlice, holders[0].Address)
+			assert.Equal(t, 0.75, holders[0].Share)
+			assert.Equal(t, holderBob, holders[1].Address)
+			assert.Equal(t, 0.25, holders[1].Share)
+
+			distribution := indexer.GetDistribution(1)
+			assert.Equal(t, uint64(40), distribution.BlockNumber)
+			assert.Equal(t, 2, distribution.Holders)
+			assert.Equal(t, 1, distribution.Nakamoto)
+			assert.Equal(t, 0.75, distribution.TopHoldersShare)
+			assert.Len(t, distribution.TopHolders, 1)
+			assert.InDelta(t, 0.25, distribution.Gini, 1e-9)
+		})
+	}
+}
+
+func TestHolderIndexerNegativeBalances(t *testing.T) {
+	// Indexing past the mint leaves the sender of the transfers with the negative balance.
+	indexer, err := NewHolderIndexer(holderToken, &mockFilterer{logs: holderLogs()}, HolderIndexerOptions{})
+	require.NoError(t, err)
+	require.NoError(t, indexer.Index(context.Background(), 11, 40))
+
+	negative := indexer.GetNegativeBalances()
+	require.Len(t, negative, 1)
+	assert.Equal(t, holderAlice, negative[0].Address)
+	assert.Equal(t, big.NewInt(-400), negative[0].Balance)
+
+	holders := indexer.GetHolders()
+	require.Len(t, holders, 1)
+	assert.Equal(t, holderBob, holders[0].Address)
+	assert.Equal(t, 1.0, holders[0].Share)
+
+	distribution := indexer.GetDistribution(-1)
+	assert.Equal(t, 1, distribution.Holders)
+	assert.Empty(t, distribution.TopHolders)
+	assert.Zero(t, distribution.TopHoldersShare)
+	assert.Equal(t, negative, distribution.NegativeBalances)
+}
+
+func TestHolderIndexerSupplyAt(t *testing.T) {
+	indexer, err := NewHolderIndexer(holderToken, &mockFilterer{logs: holderLogs()}, HolderIndexerOptions{})
+	require.NoError(t, err)
+	require.NoError(t, indexer.Index(context.Background(), 5, 40))
+
+	tests := []struct {
+		name        string
+		block       uint64
+		minted      int64
+		burned      int64
+		circulating int64
+		wantErr     bool
+	}{
+		{name: "Before Mint", block: 5},
+		{name: "After Mint", block: 10, minted: 1000, circulating: 1000},
+		{name: "After Burn", block: 20, minted: 1000, burned: 100, circulating: 900},
+		{name: "After Second Mint", block: 25, minted: 1200, burned: 100, circulating: 1100},
+		{name: "Last Block", block: 40, minted: 1200, burned: 400, circulating: 800},
+		{name: "Before Indexed Range", block: 4, wantErr: true},
+		{name: "After Indexed Range", block: 41, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			supply, err := indexer.SupplyAt(tt.block)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidBlockRange)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, big.NewInt(tt.minted), supply.Minted)
+			assert.Equal(t, big.NewInt(tt.burned), supply.Burned)
+			assert.Equal(t, big.NewInt(tt.circulating), supply.Circulating)
+		})
+	}
+}
+
+func TestHolderIndexerInvalidRanges(t *testing.T) {
+	indexer, err := NewHolderIndexer(holderToken, &mockFilterer{logs: holderLogs()}, HolderIndexerOptions{})
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, indexer.Index(context.Background(), 20, 10), ErrInvalidBlockRange)
+	require.NoError(t, indexer.Index(context.Background(), 10, 20))
+	assert.ErrorIs(t, indexer.Index(context.Background(), 5, 30), ErrInvalidBlockRange)
+	assert.ErrorIs(t, indexer.Index(context.Background(), 25, 30), ErrInvalidBlockRange)
+
+	failing, err := NewHolderIndexer(holderToken, &mockFilterer{err: errors.New("rpc failure")}, HolderIndexerOptions{})
+	require.NoError(t, err)
+	assert.Error(t, failing.Index(context.Background(), 0, 10))
+	_, ok := failing.GetLastBlock()
+	assert.False(t, ok)
+}
+
+func TestHolderIndexerCheckpoint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "holders.json")
+	opts := HolderIndexerOptions{ChunkSize: 10, CheckpointPath: path}
+
+	indexer, err := NewHolderIndexer(holderToken, &mockFilterer{logs: holderLogs()}, opts)
+	require.NoError(t, err)
+	require.NoError(t, indexer.Index(context.Background(), 0, 22))
+
+	filterer := &mockFilterer{logs: holderLogs()}
+	resumed, err := NewHolderIndexer(holderToken, filterer, opts)
+	require.NoError(t, err)
+
+	lastBlock, ok := resumed.GetLastBlock()
+	assert.True(t, ok)
+	assert.Equal(t, uint64(22), lastBlock)
+	assert.Equal(t, indexer.GetHolders(), resumed.GetHolders())
+	assert.Equal(t, indexer.GetHistory(), resumed.GetHistory())
+
+	require.NoError(t, resumed.Index(context.Background(), 0, 40))
+	assert.Equal(t, [][2]uint64{{23, 32}, {33, 40}}, filterer.queries)
+	assert.Equal(t, big.NewInt(0), resumed.BalanceOf(holderCarol))
+
+	_, err = NewHolderIndexer(holderBob, filterer, opts)
+	assert.ErrorIs(t, err, ErrInvalidHolderCheckpoint)
+}
+
+func TestIndexHolders(t *testing.T) {
+	token := &Token{descriptor: &Descriptor{Address: holderToken}}
+	indexer, err := NewHolderIndexer(holderToken, &mockFilterer{logs: holderLogs()}, HolderIndexerOptions{})
+	require.NoError(t, err)
+
+	_, err = token.IndexHolders(context.Background(), indexer, 0, 40, -1)
+	assert.Error(t, err)
+
+	descriptor, err := token.IndexHolders(context.Background(), indexer, 0, 40, 1)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(400), descriptor.TotalBurnedSupply)
+	assert.Len(t, descriptor.Distribution.TopHolders, 1)
+}
@@ -0,0 +1,189 @@
+package tokens
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/unpackdev/solgo/bindings"
+	"github.com/unpackdev/solgo/standards"
+	"github.com/unpackdev/solgo/utils"
+)
+
+// NFT encapsulates the necessary details and operations for interacting with ERC-721 and ERC-1155 tokens.
+type NFT struct {
+	ctx         context.Context   // The context for network requests.
+	network     utils.Network     // The blockchain network the token exists on.
+	networkID   utils.NetworkID   // The numeric ID of the network.
+	bindManager *bindings.Manager // The contract binding manager.
+	nftBind     *bindings.NFT     // The smart contract bindings for the token.
+	resolver    MetadataResolver  // The resolver fetching the token metadata documents.
+	descriptor  *NFTDescriptor    // The token's descriptor containing detailed information.
+}
+
+// NewNFT creates a new NFT instance with the specified parameters and prepares the contract bindings.
+// Metadata documents are fetched through the provided resolver, or through the resolver supporting
+// http, https and data URIs when it is nil.
+func NewNFT(ctx context.Context, network utils.Network, address common.Address, bindManager *bindings.Manager, resolver MetadataResolver) (*NFT, error) {
+	if bindManager == nil {
+		return nil, errors.New("bind manager is nil")
+	}
+
+	if resolver == nil {
+		resolver = NewMetadataResolver(nil, nil)
+	}
+
+	nftBind, err := bindings.NewNFT(ctx, network, bindManager, bindings.DefaultNFTBindOptions(address))
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare bindings: %w", err)
+	}
+
+	return &NFT{
+		ctx:         ctx,
+		network:     network,
+		networkID:   utils.GetNetworkID(network),
+		bindManager: bindManager,
+		nftBind:     nftBind,
+		resolver:    resolver,
+		descriptor: &NFTDescriptor{
+			Address: address,
+		},
+	}, nil
+}
+
+// GetDescriptor returns the NFTDescriptor of the token containing its metadata.
+func (t *NFT) GetDescriptor() *NFTDescriptor {
+	return t.descriptor
+}
+
+// GetNetwork returns the blockchain network the token is associated with.
+func (t *NFT) GetNetwork() utils.Network {
+	return t.network
+}
+
+// GetNetworkID returns the numeric ID of the blockchain network.
+func (t *NFT) GetNetworkID() utils.NetworkID {
+	return t.networkID
+}
+
+// GetBind returns the smart contract bindings for the token.
+func (t *NFT) GetBind() *bindings.NFT {
+	return t.nftBind
+}
+
+// GetResolver returns the resolver fetching the token metadata documents.
+func (t *NFT) GetResolver() MetadataResolver {
+	return t.resolver
+}
+
+// Unpack detects the token standard through ERC-165 and resolves the token's details (name, symbol, total supply
+// of enumerable tokens and ERC-2981 royalty support) at a specific block number. Name and symbol are optional for
+// ERC-1155 tokens and ERC-721 tokens that do not advertise the metadata extension, so failing to resolve them is
+// not considered an error for such contracts.
+func (t *NFT) Unpack(ctx context.Context, atBlock *big.Int) (*NFTDescriptor, error) {
+	address := t.descriptor.Address
+	t.descriptor.BlockNumber = atBlock
+
+	switch {
+	case t.supportsInterface(ctx, bindings.Erc721InterfaceId):
+		t.descriptor.Standard = standards.ERC721
+	case t.supportsInterface(ctx, bindings.Erc1155InterfaceId):
+		t.descriptor.Standard = standards.ERC1155
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrNotNFT, address.Hex())
+	}
+
+	hasMetadata := t.descriptor.IsERC721() && t.supportsInterface(ctx, bindings.Erc721MetadataInterfaceId)
+
+	var err error
+	t.descriptor.Name, err = t.nftBind.GetName(ctx, address)
+	if err != nil && hasMetadata {
+		return nil, fmt.Errorf("failed to resolve token name: %w", err)
+	}
+
+	t.descriptor.Symbol, err = t.nftBind.GetSymbol(ctx, address)
+	if err != nil && hasMetadata {
+		return nil, fmt.Errorf("failed to resolve token symbol: %w", err)
+	}
+
+	t.descriptor.Enumerable = t.descriptor.IsERC721() && t.supportsInterface(ctx, bindings.Erc721EnumerableInterfaceId)
+	if t.descriptor.Enumerable {
+		t.descriptor.TotalSupply, err = t.nftBind.GetTotalSupply(ctx, address)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve token total supply: %w", err)
+		}
+	}
+
+	t.descriptor.Royalties = t.supportsInterface(ctx, bindings.Erc2981InterfaceId)
+
+	return t.descriptor, nil
+}
+
+// ResolveItem resolves the token URI, ERC-721 owner, ERC-2981 royalty for the sale price and the metadata document
+// of the token. The royalty is resolved only when the sale price is provided. Unpack has to be called first.
+func (t *NFT) ResolveItem(ctx context.Context, tokenId *big.Int, salePrice *big.Int) (*NFTItem, error) {
+	address := t.descriptor.Address
+	toReturn := &NFTItem{TokenID: tokenId}
+
+	switch t.descriptor.Standard {
+	case standards.ERC721:
+		owner, err := t.nftBind.OwnerOf(ctx, address, tokenId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve token owner: %w", err)
+		}
+		toReturn.Owner = &owner
+
+		if toReturn.URI, err = t.nftBind.TokenURI(ctx, address, tokenId); err != nil {
+			return nil, fmt.Errorf("failed to resolve token uri: %w", err)
+		}
+	case standards.ERC1155:
+		uri, err := t.nftBind.URI(ctx, address, tokenId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve token uri: %w", err)
+		}
+		toReturn.URI = SubstituteTokenID(uri, tokenId)
+	default:
+		return nil, fmt.Errorf("%w: token %s is not unpacked", ErrNotNFT, address.Hex())
+	}
+
+	if t.descriptor.Royalties && salePrice != nil {
+		receiver, amount, err := t.nftBind.RoyaltyInfo(ctx, address, tokenId, salePrice)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve token royalty: %w", err)
+		}
+
+		toReturn.Royalty = &Royalty{
+			Receiver:  receiver,
+			Amount:    amount,
+			SalePrice: salePrice,
+		}
+	}
+
+	if toReturn.URI != "" {
+		var err error
+		if toReturn.Metadata, err = t.ResolveMetadata(ctx, toReturn.URI); err != nil {
+			return nil, err
+		}
+	}
+
+	return toReturn, nil
+}
+
+// ResolveMetadata fetches the metadata document through the resolver and decodes it.
+func (t *NFT) ResolveMetadata(ctx context.Context, uri string) (*NFTMetadata, error) {
+	data, err := t.resolver.Resolve(ctx, uri)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve token metadata: %w", err)
+	}
+
+	return NewNFTMetadataFromBytes(data)
+}
+
+// supportsInterface checks the ERC-165 interface support. Contracts that do not implement ERC-165 revert,
+// which is treated the same way as not supporting the interface.
+func (t *NFT) supportsInterface(ctx context.Context, interfaceId string) bool {
+	supported, err := t.nftBind.SupportsInterface(ctx, t.descriptor.Address, interfaceId)
+	return err == nil && supported
+}
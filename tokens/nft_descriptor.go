@@ -0,0 +1,157 @@
+package tokens
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/goccy/go-json"
+	"github.com/unpackdev/solgo/standards"
+)
+
+// NFTDescriptor contains detailed information about a specific ERC-721 or ERC-1155 token contract.
+type NFTDescriptor struct {
+	BlockNumber *big.Int           `json:"block_number"` // Block number at which the token info was queried.
+	Address     common.Address     `json:"address"`      // Ethereum address of the token contract.
+	Standard    standards.Standard `json:"standard"`     // Token standard, either ERC721 or ERC1155.
+	Name        string             `json:"name"`         // Name of the token, if the contract exposes it.
+	Symbol      string             `json:"symbol"`       // Symbol of the token, if the contract exposes it.
+	Enumerable  bool               `json:"enumerable"`   // Whether the contract implements the ERC-721 enumerable extension.
+	TotalSupply *big.Int           `json:"total_supply"` // Total supply of the token, available only for enumerable contracts.
+	Royalties   bool               `json:"royalties"`    // Whether the contract implements the ERC-2981 royalty standard.
+}
+
+// GetAddress returns the Ethereum address of the token contract.
+func (d *NFTDescriptor) GetAddress() common.Address {
+	return d.Address
+}
+
+// GetStandard returns the standard of the token, either ERC721 or ERC1155.
+func (d *NFTDescriptor) GetStandard() standards.Standard {
+	return d.Standard
+}
+
+// GetName returns the name of the token.
+func (d *NFTDescriptor) GetName() string {
+	return d.Name
+}
+
+// GetSymbol returns the symbol of the token.
+func (d *NFTDescriptor) GetSymbol() string {
+	return d.Symbol
+}
+
+// GetTotalSupply returns the total supply of the token, or nil when the contract is not enumerable.
+func (d *NFTDescriptor) GetTotalSupply() *big.Int {
+	return d.TotalSupply
+}
+
+// IsERC721 returns true if the token contract is an ERC-721 contract.
+func (d *NFTDescriptor) IsERC721() bool {
+	return d.Standard == standards.ERC721
+}
+
+// IsERC1155 returns true if the token contract is an ERC-1155 contract.
+func (d *NFTDescriptor) IsERC1155() bool {
+	return d.Standard == standards.ERC1155
+}
+
+// Royalty contains the ERC-2981 royalty information of the token for the given sale price.
+type Royalty struct {
+	Receiver  common.Address `json:"receiver"`   // Address receiving the royalty.
+	Amount    *big.Int       `json:"amount"`     // Royalty amount for the sale price.
+	SalePrice *big.Int       `json:"sale_price"` // Sale price the royalty was calculated for.
+}
+
+// NFTItem contains the information of the single token of the ERC-721 or ERC-1155 contract.
+type NFTItem struct {
+	TokenID  *big.Int        `json:"token_id"`           // Identifier of the token.
+	URI      string          `json:"uri"`                // Token URI with the ERC-1155 `{id}` placeholder substituted.
+	Owner    *common.Address `json:"owner,omitempty"`    // Owner of the token, available only for ERC-721 tokens.
+	Royalty  *Royalty        `json:"royalty,omitempty"`  // Royalty information, if the contract implements ERC-2981.
+	Metadata *NFTMetadata    `json:"metadata,omitempty"` // Metadata document the token URI points to.
+}
+
+// NFTAttribute represents the single trait of the token metadata.
+type NFTAttribute struct {
+	TraitType   string `json:"trait_type,omitempty"`
+	DisplayType string `json:"display_type,omitempty"`
+	Value       any    `json:"value"`
+}
+
+// NFTAttributes represents the traits of the token metadata. Traits are accepted both as the list of
+// attributes and as the object mapping trait types to values, which is used by a number of collections.
+type NFTAttributes []NFTAttribute
+
+// UnmarshalJSON decodes the traits out of the attributes list or the trait types object.
+func (a *NFTAttributes) UnmarshalJSON(data []byte) error {
+	content := strings.TrimSpace(string(data))
+
+	switch {
+	case content == "null":
+		return nil
+	case strings.HasPrefix(content, "["):
+		attributes := make([]NFTAttribute, 0)
+		if err := json.Unmarshal(data, &attributes); err != nil {
+			return err
+		}
+		*a = attributes
+	case strings.HasPrefix(content, "{"):
+		traits := make(map[string]any)
+		if err := json.Unmarshal(data, &traits); err != nil {
+			return err
+		}
+
+		attributes := make([]NFTAttribute, 0, len(traits))
+		for traitType, value := range traits {
+			attributes = append(attributes, NFTAttribute{TraitType: traitType, Value: value})
+		}
+
+		sort.Slice(attributes, func(i, j int) bool {
+			return attributes[i].TraitType < attributes[j].TraitType
+		})
+		*a = attributes
+	default:
+		return fmt.Errorf("unexpected attributes %s", content)
+	}
+
+	return nil
+}
+
+// NFTMetadata represents the metadata document of the token as described by the ERC-721 and ERC-1155 metadata
+// JSON schemas, including the commonly used marketplace extensions.
+type NFTMetadata struct {
+	Name            string         `json:"name,omitempty"`
+	Description     string         `json:"description,omitempty"`
+	Image           string         `json:"image,omitempty"`
+	ImageData       string         `json:"image_data,omitempty"`
+	ExternalURL     string         `json:"external_url,omitempty"`
+	AnimationURL    string         `json:"animation_url,omitempty"`
+	BackgroundColor string         `json:"background_color,omitempty"`
+	Decimals        *int           `json:"decimals,omitempty"`
+	Attributes      NFTAttributes  `json:"attributes,omitempty"`
+	Properties      map[string]any `json:"properties,omitempty"`
+	Raw             string         `json:"raw"`
+}
+
+// NewNFTMetadataFromBytes decodes the token metadata document, keeping the raw document as well.
+func NewNFTMetadataFromBytes(data []byte) (*NFTMetadata, error) {
+	toReturn := &NFTMetadata{}
+	if err := json.Unmarshal(data, toReturn); err != nil {
+		return nil, fmt.Errorf("failed to decode nft metadata: %w", err)
+	}
+
+	toReturn.Raw = string(data)
+	return toReturn, nil
+}
+
+// SubstituteTokenID replaces the ERC-1155 `{id}` placeholder of the URI with the lowercase hex encoded token
+// identifier, padded with zeros to 64 characters, as required by the standard.
+func SubstituteTokenID(uri string, id *big.Int) string {
+	if id == nil {
+		return uri
+	}
+	return strings.ReplaceAll(uri, "{id}", fmt.Sprintf("%064x", id))
+}
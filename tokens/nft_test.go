@@ -0,0 +1,104 @@
+package tokens
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubstituteTokenID(t *testing.T) {
+	tests := []struct {
+		name     string
+		uri      string
+		id       *big.Int
+		expected string
+	}{
+		{
+			name:     "ERC1155 Example",
+			uri:      "https://token-cdn-domain/{id}.json",
+			id:       big.NewInt(314592),
+			expected: "https://token-cdn-domain/000000000000000000000000000000000000000000000000000000000004cce0.json",
+		},
+		{
+			name:     "Without Placeholder",
+			uri:      "ipfs://QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG/1",
+			id:       big.NewInt(1),
+			expected: "ipfs://QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG/1",
+		},
+		{
+			name:     "Missing Identifier",
+			uri:      "https://token-cdn-domain/{id}.json",
+			expected: "https://token-cdn-domain/{id}.json",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, SubstituteTokenID(tt.uri, tt.id))
+		})
+	}
+}
+
+func TestNewNFTMetadataFromBytes(t *testing.T) {
+	tests := []struct {
+		name               string
+		data               string
+		expectedName       string
+		expectedImage      string
+		expectedAttributes NFTAttributes
+		wantErr            bool
+	}{
+		{
+			name:          "Attributes List",
+			data:          `{"name":"Token #1","image":"ipfs://QmImage","attributes":[{"trait_type":"Background","value":"Blue"},{"display_type":"number","trait_type":"Level","value":5}]}`,
+			expectedName:  "Token #1",
+			expectedImage: "ipfs://QmImage",
+			expectedAttributes: NFTAttributes{
+				{TraitType: "Background", Value: "Blue"},
+				{TraitType: "Level", DisplayType: "number", Value: float64(5)},
+			},
+		},
+		{
+			name:         "Attributes Object",
+			data:         `{"name":"Token #2","attributes":{"Level":5,"Background":"Blue"}}`,
+			expectedName: "Token #2",
+			expectedAttributes: NFTAttributes{
+				{TraitType: "Background", Value: "Blue"},
+				{TraitType: "Level", Value: float64(5)},
+			},
+		},
+		{
+			name:         "Without Attributes",
+			data:         `{"name":"Token #3","properties":{"rarity":"rare"}}`,
+			expectedName: "Token #3",
+		},
+		{
+			name:    "Invalid Attributes",
+			data:    `{"name":"Token #4","attributes":"rare"}`,
+			wantErr: true,
+		},
+		{
+			name:    "Invalid Document",
+			data:    `<html></html>`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metadata, err := NewNFTMetadataFromBytes([]byte(tt.data))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedName, metadata.Name)
+			assert.Equal(t, tt.expectedImage, metadata.Image)
+			assert.Equal(t, tt.expectedAttributes, metadata.Attributes)
+			assert.Equal(t, tt.data, metadata.Raw)
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package tokens
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/unpackdev/solgo/pricing"
+	"github.com/unpackdev/solgo/utils"
+)
+
+// NewPricer creates the pricer reading the pools from the token's network at the block number of the descriptor,
+// using the known exchanges and quote tokens of the network.
+func (t *Token) NewPricer() (*pricing.Pricer, error) {
+	client, err := t.GetClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return pricing.NewPricer(client, uint(utils.GetNetworkID(t.network)), &pricing.Options{
+		BlockNumber: t.descriptor.BlockNumber,
+	})
+}
+
+// ResolvePrice valuates the token in USD using its most liquid pool and stores the price and liquidity in the
+// descriptor. When the trade size is given, the price impact of selling it is calculated as well. When the
+// pricer is nil, the pricer of the token's network is created.
+func (t *Token) ResolvePrice(ctx context.Context, pricer *pricing.Pricer, tradeSize *big.Int) (*Descriptor, error) {
+	if pricer == nil {
+		var err error
+		if pricer, err = t.NewPricer(); err != nil {
+			return nil, fmt.Errorf("failed to create pricer: %w", err)
+		}
+	}
+
+	valuation, err := pricer.Valuate(ctx, t.GetEntity(), tradeSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve token price: %w", err)
+	}
+
+	t.descriptor.SetValuation(valuation)
+	return t.descriptor, nil
+}
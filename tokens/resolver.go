@@ -0,0 +1,209 @@
+package tokens
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/unpackdev/solgo/metadata"
+)
+
+// MaxMetadataSize limits the size of the metadata document read by the resolvers.
+const MaxMetadataSize = 10 << 20
+
+// MetadataResolver fetches the metadata document the token URI points to.
+type MetadataResolver interface {
+	// Resolve returns the raw content of the document referenced by the URI.
+	Resolve(ctx context.Context, uri string) ([]byte, error)
+}
+
+// IpfsMetadataResolver resolves ipfs:// URIs, as well as /ipfs/ paths, through the IPFS shell.
+type IpfsMetadataResolver struct {
+	shell metadata.Shell
+}
+
+// NewIpfsMetadataResolver creates a new IpfsMetadataResolver using the provided IPFS shell.
+func NewIpfsMetadataResolver(shell metadata.Shell) (*IpfsMetadataResolver, error) {
+	if shell == nil {
+		return nil, metadata.ErrInvalidIpfsClient
+	}
+
+	return &IpfsMetadataResolver{shell: shell}, nil
+}
+
+// Resolve reads the IPFS document referenced by the URI, e.g. "ipfs://QmHash/1.json".
+func (r *IpfsMetadataResolver) Resolve(ctx context.Context, uri string) ([]byte, error) {
+	path := strings.TrimPrefix(uri, "ipfs://")
+	path = strings.TrimPrefix(path, "ipfs/")
+	path = strings.TrimPrefix(path, "/ipfs/")
+	if path == "" {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidTokenURI, uri)
+	}
+
+	type response struct {
+		data []byte
+		err  error
+	}
+
+	// The shell does not accept the context, so the read is abandoned once the context is done.
+	result := make(chan response, 1)
+	go func() {
+		content, err := r.shell.Cat("/ipfs/" + path)
+		if err != nil {
+			result <- response{err: err}
+			return
+		}
+		defer content.Close()
+
+		data, err := io.ReadAll(io.LimitReader(content, MaxMetadataSize))
+		result <- response{data: data, err: err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return nil, fmt.Errorf("failed to read %s from ipfs: %w", uri, ctx.Err())
+	case res := <-result:
+		if res.err != nil {
+			return nil, fmt.Errorf("failed to read %s from ipfs: %w", uri, res.err)
+		}
+		return res.data, nil
+	}
+}
+
+// HttpMetadataResolver resolves http:// and https:// URIs.
+type HttpMetadataResolver struct {
+	client *http.Client
+}
+
+// NewHttpMetadataResolver creates a new HttpMetadataResolver. When the client is nil, a client with
+// a 30 seconds timeout is used.
+func NewHttpMetadataResolver(client *http.Client) *HttpMetadataResolver {
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	return &HttpMetadataResolver{client: client}
+}
+
+// Resolve fetches the document referenced by the URI.
+func (r *HttpMetadataResolver) Resolve(ctx context.Context, uri string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidTokenURI, err)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", uri, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch %s: unexpected status code %d", uri, resp.StatusCode)
+	}
+
+	return io.ReadAll(io.LimitReader(resp.Body, MaxMetadataSize))
+}
+
+// DataMetadataResolver resolves RFC 2397 data: URIs, which are commonly used by on-chain metadata,
+// e.g. "data:application/json;base64,eyJuYW1lIjoiMSJ9".
+type DataMetadataResolver struct{}
+
+// NewDataMetadataResolver creates a new DataMetadataResolver.
+func NewDataMetadataResolver() *DataMetadataResolver {
+	return &DataMetadataResolver{}
+}
+
+// Resolve decodes the data embedded within the URI.
+func (r *DataMetadataResolver) Resolve(ctx context.Context, uri string) ([]byte, error) {
+	if !strings.HasPrefix(uri, "data:") {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidTokenURI, uri)
+	}
+
+	header, data, found := strings.Cut(strings.TrimPrefix(uri, "data:"), ",")
+	if !found {
+		return nil, fmt.Errorf("%w: missing data separator", ErrInvalidTokenURI)
+	}
+
+	if strings.HasSuffix(header, ";base64") {
+		decoded, err := base64.StdEncoding.DecodeString(data)
+		if err != nil {
+			// Some contracts omit the base64 padding.
+			if decoded, err = base64.RawStdEncoding.DecodeString(data); err != nil {
+				return nil, fmt.Errorf("%w: %s", ErrInvalidTokenURI, err)
+			}
+		}
+		return decoded, nil
+	}
+
+	decoded, err := url.PathUnescape(data)
+	if err != nil {
+		// Plenty of contracts embed raw JSON without percent-encoding it.
+		return []byte(data), nil
+	}
+
+	return []byte(decoded), nil
+}
+
+// SchemeMetadataResolver dispatches the URI to the resolver registered for its scheme.
+type SchemeMetadataResolver struct {
+	mu        sync.RWMutex
+	resolvers map[string]MetadataResolver
+}
+
+// NewMetadataResolver creates a new SchemeMetadataResolver resolving http, https and data URIs, as well as
+// ipfs URIs when the IPFS shell is provided. Other schemes, e.g. ar, can be registered through Register.
+func NewMetadataResolver(shell metadata.Shell, client *http.Client) *SchemeMetadataResolver {
+	toReturn := &SchemeMetadataResolver{
+		resolvers: make(map[string]MetadataResolver),
+	}
+
+	httpResolver := NewHttpMetadataResolver(client)
+	toReturn.Register("http", httpResolver)
+	toReturn.Register("https", httpResolver)
+	toReturn.Register("data", NewDataMetadataResolver())
+
+	if ipfsResolver, err := NewIpfsMetadataResolver(shell); err == nil {
+		toReturn.Register("ipfs", ipfsResolver)
+	}
+
+	return toReturn
+}
+
+// Register sets the resolver of the URI scheme, replacing the previously registered one.
+func (r *SchemeMetadataResolver) Register(scheme string, resolver MetadataResolver) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.resolvers[strings.ToLower(scheme)] = resolver
+}
+
+// Resolve fetches the document using the resolver registered for the URI scheme.
+// Paths starting with /ipfs/ are treated as ipfs URIs.
+func (r *SchemeMetadataResolver) Resolve(ctx context.Context, uri string) ([]byte, error) {
+	uri = strings.TrimSpace(uri)
+
+	scheme, _, found := strings.Cut(uri, ":")
+	if strings.HasPrefix(uri, "/ipfs/") {
+		scheme, found = "ipfs", true
+	}
+
+	if !found {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidTokenURI, uri)
+	}
+
+	r.mu.RLock()
+	resolver, ok := r.resolvers[strings.ToLower(scheme)]
+	r.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedURIScheme, scheme)
+	}
+
+	return resolver.Resolve(ctx, uri)
+}
@@ -0,0 +1,148 @@
+package tokens
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockShell is a mock implementation of the metadata.Shell serving the documents by their IPFS path.
+type mockShell struct {
+	documents map[string]string
+}
+
+func (m *mockShell) Cat(path string) (io.ReadCloser, error) {
+	document, ok := m.documents[path]
+	if !ok {
+		return nil, errors.New("document not found")
+	}
+	return io.NopCloser(strings.NewReader(document)), nil
+}
+
+func TestMetadataResolver(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/token/1.json" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"name":"HTTP #1"}`))
+	}))
+	defer server.Close()
+
+	shell := &mockShell{documents: map[string]string{
+		"/ipfs/QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG/1": `{"name":"IPFS #1"}`,
+	}}
+
+	resolver := NewMetadataResolver(shell, server.Client())
+
+	tests := []struct {
+		name        string
+		uri         string
+		expected    string
+		expectedErr error
+		wantErr     bool
+	}{
+		{
+			name:     "IPFS URI",
+			uri:      "ipfs://QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG/1",
+			expected: `{"name":"IPFS #1"}`,
+		},
+		{
+			name:     "IPFS URI With Legacy Prefix",
+			uri:      "ipfs://ipfs/QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG/1",
+			expected: `{"name":"IPFS #1"}`,
+		},
+		{
+			name:     "IPFS Path",
+			uri:      "/ipfs/QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG/1",
+			expected: `{"name":"IPFS #1"}`,
+		},
+		{
+			name:    "IPFS Missing Document",
+			uri:     "ipfs://QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG/2",
+			wantErr: true,
+		},
+		{
+			name:     "HTTP URI",
+			uri:      server.URL + "/token/1.json",
+			expected: `{"name":"HTTP #1"}`,
+		},
+		{
+			name:    "HTTP Not Found",
+			uri:     server.URL + "/token/2.json",
+			wantErr: true,
+		},
+		{
+			name:     "Base64 Data URI",
+			uri:      "data:application/json;base64,eyJuYW1lIjoiRGF0YSAjMSJ9",
+			expected: `{"name":"Data #1"}`,
+		},
+		{
+			name:     "Unpadded Base64 Data URI",
+			uri:      "data:application/json;base64,eyJuYW1lIjoiRGF0YSAjMjIifQ",
+			expected: `{"name":"Data #22"}`,
+		},
+		{
+			name:     "Percent Encoded Data URI",
+			uri:      "data:application/json,%7B%22name%22%3A%22Data%20%233%22%7D",
+			expected: `{"name":"Data #3"}`,
+		},
+		{
+			name:     "Raw Data URI",
+			uri:      `data:application/json;utf8,{"name":"100% Data"}`,
+			expected: `{"name":"100% Data"}`,
+		},
+		{
+			name:        "Unsupported Scheme",
+			uri:         "ar://bNbA3TEQVL60xlgCcqdz4ZPHFZ711cZ3hmkpGttDt_U",
+			expectedErr: ErrUnsupportedURIScheme,
+		},
+		{
+			name:        "Missing Scheme",
+			uri:         "token/1.json",
+			expectedErr: ErrInvalidTokenURI,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := resolver.Resolve(context.TODO(), tt.uri)
+			switch {
+			case tt.expectedErr != nil:
+				assert.ErrorIs(t, err, tt.expectedErr)
+			case tt.wantErr:
+				assert.Error(t, err)
+			default:
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected, string(data))
+			}
+		})
+	}
+}
+
+func TestMetadataResolverRegister(t *testing.T) {
+	resolver := NewMetadataResolver(nil, nil)
+
+	_, err := resolver.Resolve(context.TODO(), "ipfs://QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG")
+	assert.ErrorIs(t, err, ErrUnsupportedURIScheme)
+
+	_, err = NewIpfsMetadataResolver(nil)
+	assert.Error(t, err)
+
+	ipfsResolver, err := NewIpfsMetadataResolver(&mockShell{documents: map[string]string{
+		"/ipfs/QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG": `{}`,
+	}})
+	require.NoError(t, err)
+	resolver.Register("IPFS", ipfsResolver)
+
+	data, err := resolver.Resolve(context.TODO(), "ipfs://QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG")
+	require.NoError(t, err)
+	assert.Equal(t, `{}`, string(data))
+}
@@ -0,0 +1,54 @@
+package tokens
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/unpackdev/solgo/simulator"
+	"github.com/unpackdev/solgo/utils"
+)
+
+// NewSimulator creates the in-process EVM simulator on top of the state forked from the token's network
+// at the given block number, or at the latest block when the block number is nil.
+func (t *Token) NewSimulator(ctx context.Context, atBlock *big.Int) (*simulator.Simulator, error) {
+	client, err := t.GetClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return simulator.NewForkedSimulator(ctx, client, atBlock)
+}
+
+// Simulate buys and sells the token through the router of the trade options, which are applied to the token
+// address, and stores the measured taxes, safety state, anti-whale measures and blacklist categories in the
+// descriptor. When the simulator is nil, the state is forked from the token's network at the block number of
+// the descriptor. The simulator state is left intact.
+func (t *Token) Simulate(ctx context.Context, sim *simulator.Simulator, opts *simulator.TradeOptions) (*Descriptor, error) {
+	if opts == nil {
+		return nil, errors.New("trade options are nil")
+	}
+
+	if sim == nil {
+		var err error
+		if sim, err = t.NewSimulator(ctx, t.descriptor.BlockNumber); err != nil {
+			return nil, fmt.Errorf("failed to create simulator: %w", err)
+		}
+	}
+
+	t.SetInSimulation(true)
+	defer t.SetInSimulation(false)
+	t.simulatorType = utils.EvmSimulator
+
+	tradeOpts := *opts
+	tradeOpts.Token = t.descriptor.Address
+
+	report, err := sim.SimulateTrades(ctx, &tradeOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to simulate token trades: %w", err)
+	}
+
+	t.descriptor.SetSimulation(report)
+	return t.descriptor, nil
+}
@@ -8,28 +8,39 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 )
 
+// BurnAddresses are the well-known addresses tokens are sent to in order to be "burned", or permanently
+// removed from circulation.
+var BurnAddresses = []common.Address{
+	common.HexToAddress("0x0000000000000000000000000000000000000000"),
+	common.HexToAddress("0x000000000000000000000000000000000000dEaD"),
+	common.HexToAddress("0x0000000000000000000000000000000000000001"),
+	common.HexToAddress("0x0000000000000000000000000000000000000002"),
+	common.HexToAddress("0x0000000000000000000000000000000000000003"),
+	common.HexToAddress("0x0000000000000000000000000000000000000004"),
+	common.HexToAddress("0x0000000000000000000000000000000000000005"),
+	common.HexToAddress("0x0000000000000000000000000000000000000006"),
+	common.HexToAddress("0x0000000000000000000000000000000000000007"),
+	common.HexToAddress("0x0000000000000000000000000000000000000008"),
+	common.HexToAddress("0x0000000000000000000000000000000000000009"),
+}
+
+// IsBurnAddress returns true if the address is one of the BurnAddresses.
+func IsBurnAddress(address common.Address) bool {
+	for _, burnAddress := range BurnAddresses {
+		if address == burnAddress {
+			return true
+		}
+	}
+	return false
+}
+
 // CalculateTotalBurnedSupply updates the TotalBurnedSupply field in the token's descriptor
 // by summing the balances of well-known burn addresses. This method assumes these addresses
 // are where tokens are sent to be "burned" or permanently removed from circulation.
 func (t *Token) CalculateTotalBurnedSupply(ctx context.Context) error {
-	burnAddresses := []common.Address{
-		common.HexToAddress("0x0000000000000000000000000000000000000000"),
-		common.HexToAddress("0x000000000000000000000000000000000000dead"),
-		common.HexToAddress("0x000000000000000000000000000000000000dEaD"),
-		common.HexToAddress("0x0000000000000000000000000000000000000001"),
-		common.HexToAddress("0x0000000000000000000000000000000000000002"),
-		common.HexToAddress("0x0000000000000000000000000000000000000003"),
-		common.HexToAddress("0x0000000000000000000000000000000000000004"),
-		common.HexToAddress("0x0000000000000000000000000000000000000005"),
-		common.HexToAddress("0x0000000000000000000000000000000000000006"),
-		common.HexToAddress("0x0000000000000000000000000000000000000007"),
-		common.HexToAddress("0x0000000000000000000000000000000000000008"),
-		common.HexToAddress("0x0000000000000000000000000000000000000009"),
-	}
-
 	totalBurned := big.NewInt(0)
 
-	for _, address := range burnAddresses {
+	for _, address := range BurnAddresses {
 		balance, err := t.ResolveBalance(ctx, t.descriptor.Address, t.tokenBind, address)
 		if err != nil {
 			return fmt.Errorf("failed to resolve token total burned supply for %s : %s", address.Hex(), err)
@@ -12,6 +12,7 @@ import (
 	"github.com/unpackdev/solgo/clients"
 	"github.com/unpackdev/solgo/utils"
 	"github.com/unpackdev/solgo/utils/entities"
+	"golang.org/x/sync/errgroup"
 )
 
 // Token encapsulates the necessary details and operations for interacting with an Ethereum token.
@@ -133,35 +134,49 @@ func (t *Token) GetClient() (*clients.Client, error) {
 
 // Unpack resolves the token's details (name, symbol, decimals, total supply) at a specific block number.
 func (t *Token) Unpack(ctx context.Context, atBlock *big.Int) (*Descriptor, error) {
-	var tokenBinding *bindings.Token
-	var err error
-
 	t.descriptor.BlockNumber = atBlock
 
-	tokenBinding, err = t.GetTokenBind(ctx, t.bindManager)
+	tokenBinding, err := t.GetTokenBind(ctx, t.bindManager)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get token bindings: %w", err)
 	}
 	t.tokenBind = tokenBinding
 
-	t.descriptor.Name, err = t.ResolveName(ctx, t.descriptor.Address, tokenBinding)
-	if err != nil {
-		return nil, fmt.Errorf("failed to resolve token name: %w", err)
-	}
-
-	t.descriptor.Symbol, err = t.ResolveSymbol(ctx, t.descriptor.Address, tokenBinding)
-	if err != nil {
-		return nil, fmt.Errorf("failed to resolve token symbol: %w", err)
-	}
-
-	t.descriptor.Decimals, err = t.ResolveDecimals(ctx, t.descriptor.Address, tokenBinding)
-	if err != nil {
-		return nil, fmt.Errorf("failed to resolve token decimals: %w", err)
-	}
-
-	t.descriptor.TotalSupply, err = t.ResolveTotalSupply(ctx, t.descriptor.Address, tokenBinding)
-	if err != nil {
-		return nil, fmt.Errorf("failed to resolve token total supply: %w", err)
+	// Properties are resolved concurrently so that the calls are aggregated into the single multicall, with no
+	// more calls in flight than fit into a single aggregate.
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(t.clientPool.GetMulticaller(t.network.String()).GetMaxCalls())
+
+	g.Go(func() (err error) {
+		if t.descriptor.Name, err = t.ResolveName(gctx, t.descriptor.Address, tokenBinding); err != nil {
+			return fmt.Errorf("failed to resolve token name: %w", err)
+		}
+		return nil
+	})
+
+	g.Go(func() (err error) {
+		if t.descriptor.Symbol, err = t.ResolveSymbol(gctx, t.descriptor.Address, tokenBinding); err != nil {
+			return fmt.Errorf("failed to resolve token symbol: %w", err)
+		}
+		return nil
+	})
+
+	g.Go(func() (err error) {
+		if t.descriptor.Decimals, err = t.ResolveDecimals(gctx, t.descriptor.Address, tokenBinding); err != nil {
+			return fmt.Errorf("failed to resolve token decimals: %w", err)
+		}
+		return nil
+	})
+
+	g.Go(func() (err error) {
+		if t.descriptor.TotalSupply, err = t.ResolveTotalSupply(gctx, t.descriptor.Address, tokenBinding); err != nil {
+			return fmt.Errorf("failed to resolve token total supply: %w", err)
+		}
+		return nil
+	})
+
+	if err := g.Wait(); err != nil {
+		return nil, err
 	}
 
 	t.descriptor.Entity = t.GetEntity()
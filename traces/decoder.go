@@ -0,0 +1,318 @@
+package traces
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+
+	abi "github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/unpackdev/solgo/bytecode"
+	"github.com/unpackdev/solgo/storage"
+)
+
+var (
+	// errorSelector is the selector of the Error(string) revert.
+	errorSelector = []byte{0x08, 0xc3, 0x79, 0xa0}
+
+	// panicSelector is the selector of the Panic(uint256) revert.
+	panicSelector = []byte{0x4e, 0x48, 0x7b, 0x71}
+)
+
+// contractAbi is the ABI resolved for the address, kept both raw for the bytecode package decoders and parsed.
+type contractAbi struct {
+	raw    []byte
+	parsed *abi.ABI
+}
+
+// Decoder decodes the traces into the annotated call trees, resolving the ABIs and storage layouts of the
+// contracts taking part in the execution. It is safe for concurrent use.
+type Decoder struct {
+	abiResolver    bytecode.AbiResolver
+	layoutResolver LayoutResolver
+
+	mu      sync.Mutex
+	abis    map[common.Address]*contractAbi           // Resolved ABIs by their address, nil when not known.
+	layouts map[common.Address]*storage.StorageLayout // Resolved layouts by their address, nil when not known.
+}
+
+// NewDecoder creates the decoder resolving the ABIs and the storage layouts with the given resolvers. Either
+// of them may be nil, leaving the frames undecoded or the storage writes unnamed.
+func NewDecoder(abiResolver bytecode.AbiResolver, layoutResolver LayoutResolver) *Decoder {
+	return &Decoder{
+		abiResolver:    abiResolver,
+		layoutResolver: layoutResolver,
+		abis:           make(map[common.Address]*contractAbi),
+		layouts:        make(map[common.Address]*storage.StorageLayout),
+	}
+}
+
+// DecodeCallTrace decodes the callTracer output into the annotated call tree. The callTracer reports neither
+// the storage writes nor the keccak256 preimages, so the frames carry no storage writes.
+func (d *Decoder) DecodeCallTrace(trace *CallFrame) (*Frame, error) {
+	if trace == nil {
+		return nil, ErrEmptyTrace
+	}
+
+	root := newCallFrame(trace, nil)
+	if err := d.annotate(root, nil); err != nil {
+		return nil, err
+	}
+
+	return root, nil
+}
+
+// newCallFrame converts the callTracer frame, and the frames of its calls, into the frame of the call tree.
+func newCallFrame(trace *CallFrame, parent *Frame) *Frame {
+	frame := &Frame{
+		Type:    strings.ToUpper(trace.Type),
+		From:    trace.From,
+		Gas:     uint64(trace.Gas),
+		GasUsed: uint64(trace.GasUsed),
+		Input:   trace.Input,
+		Output:  trace.Output,
+		Error:   trace.Error,
+	}
+
+	if trace.To != nil {
+		frame.To = *trace.To
+	}
+
+	if trace.Value != nil {
+		frame.Value = trace.Value.ToInt()
+	}
+
+	frame.Context = frame.To
+	if parent != nil {
+		frame.Depth = parent.Depth + 1
+		if frame.Type == "DELEGATECALL" || frame.Type == "CALLCODE" {
+			frame.Context = parent.Context
+		}
+	}
+
+	for _, log := range trace.Logs {
+		frame.Logs = append(frame.Logs, &Log{
+			Address:  log.Address,
+			Topics:   log.Topics,
+			Data:     log.Data,
+			Position: int(log.Position),
+		})
+	}
+
+	for _, call := range trace.Calls {
+		frame.Calls = append(frame.Calls, newCallFrame(call, frame))
+	}
+
+	return frame
+}
+
+// annotate decodes the method, return values, logs, revert and storage writes of the frame and its calls.
+// Preimages are the keccak256 inputs by their hashes, used to name the slots of the mappings and arrays.
+func (d *Decoder) annotate(frame *Frame, preimages map[common.Hash][]byte) error {
+	codeAbi, err := d.getAbi(frame.To)
+	if err != nil {
+		return err
+	}
+
+	// Calldata not matching the ABI of the code is left undecoded rather than failing the whole tree, and the
+	// init code of the creations carries the constructor arguments of unknown offset.
+	if codeAbi != nil && len(frame.Input) >= 4 && !strings.HasPrefix(frame.Type, "CREATE") {
+		if tx, err := bytecode.DecodeTransactionFromAbi(frame.Input, codeAbi.raw); err == nil {
+			frame.Transaction = tx
+			if !frame.IsReverted() && len(tx.Method.Outputs) > 0 {
+				outputs := make(map[string]interface{})
+				if err := tx.Method.Outputs.UnpackIntoMap(outputs, frame.Output); err == nil {
+					frame.Outputs = outputs
+				}
+			}
+		}
+	}
+
+	for _, log := range frame.Logs {
+		if log.Decoded, err = d.decodeLog(frame, log); err != nil {
+			return err
+		}
+	}
+
+	if frame.IsReverted() {
+		if frame.Revert, err = d.decodeRevert(frame); err != nil {
+			return err
+		}
+	}
+
+	if len(frame.StorageWrites) > 0 {
+		layout, err := d.getLayout(frame.To)
+		if err != nil {
+			return err
+		}
+
+		// Code executed by the delegate calls follows the layout of its own contract, falling back to the layout
+		// of the contract the storage belongs to.
+		if layout == nil && frame.Context != frame.To {
+			if layout, err = d.getLayout(frame.Context); err != nil {
+				return err
+			}
+		}
+
+		if layout != nil {
+			for _, write := range frame.StorageWrites {
+				write.Variable, write.Type = nameSlot(layout, preimages, write.Slot)
+			}
+		}
+	}
+
+	for _, call := range frame.Calls {
+		if err := d.annotate(call, preimages); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// decodeLog decodes the log with the ABI of the frame code, falling back to the ABI of the emitting contract,
+// nil when neither of them declares the event.
+func (d *Decoder) decodeLog(frame *Frame, log *Log) (*bytecode.Log, error) {
+	if len(log.Topics) == 0 {
+		return nil, nil
+	}
+
+	for _, addr := range []common.Address{frame.To, log.Address} {
+		contractAbi, err := d.getAbi(addr)
+		if err != nil {
+			return nil, err
+		}
+
+		if contractAbi == nil {
+			continue
+		}
+
+		decoded, err := bytecode.DecodeLogFromAbi(&types.Log{
+			Address: log.Address,
+			Topics:  log.Topics,
+			Data:    log.Data,
+		}, contractAbi.raw)
+		if err == nil {
+			return decoded, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// decodeRevert decodes the revert data of the frame. Custom errors are looked up in the ABI of the frame code
+// first, then in the ABIs of its calls, as the errors of the inner calls are commonly bubbled up as they are.
+func (d *Decoder) decodeRevert(frame *Frame) (*Revert, error) {
+	data := frame.Output
+	if len(data) < 4 {
+		return &Revert{Kind: RevertUnknown}, nil
+	}
+
+	if bytes.Equal(data[:4], errorSelector) || bytes.Equal(data[:4], panicSelector) {
+		reason, err := abi.UnpackRevert(data)
+		if err != nil {
+			return &Revert{Kind: RevertUnknown}, nil
+		}
+
+		kind := RevertError
+		if bytes.Equal(data[:4], panicSelector) {
+			kind = RevertPanic
+		}
+		return &Revert{Kind: kind, Reason: reason}, nil
+	}
+
+	var revert *Revert
+	var walkErr error
+	frame.Walk(func(call *Frame) bool {
+		contractAbi, err := d.getAbi(call.To)
+		if err != nil {
+			walkErr = err
+			return false
+		}
+
+		if contractAbi == nil {
+			return true
+		}
+
+		for _, abiError := range contractAbi.parsed.Errors {
+			if !bytes.Equal(abiError.ID[:4], data[:4]) {
+				continue
+			}
+
+			inputs := make(map[string]interface{})
+			if err := abiError.Inputs.UnpackIntoMap(inputs, data[4:]); err != nil {
+				continue
+			}
+
+			revert = &Revert{Kind: RevertCustom, Name: abiError.Name, Signature: abiError.Sig, Inputs: inputs}
+			return false
+		}
+
+		return true
+	})
+
+	if walkErr != nil {
+		return nil, walkErr
+	}
+
+	if revert == nil {
+		revert = &Revert{Kind: RevertUnknown}
+	}
+
+	return revert, nil
+}
+
+// getAbi returns the ABI of the address, nil when it is not known.
+func (d *Decoder) getAbi(addr common.Address) (*contractAbi, error) {
+	if d.abiResolver == nil {
+		return nil, nil
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if resolved, ok := d.abis[addr]; ok {
+		return resolved, nil
+	}
+
+	abiData, err := d.abiResolver(addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve abi of %s: %w", addr.Hex(), err)
+	}
+
+	var resolved *contractAbi
+	if abiData != nil {
+		parsed, err := abi.JSON(bytes.NewReader(abiData))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse abi of %s: %s", addr.Hex(), err)
+		}
+		resolved = &contractAbi{raw: abiData, parsed: &parsed}
+	}
+
+	d.abis[addr] = resolved
+	return resolved, nil
+}
+
+// getLayout returns the storage layout of the address, nil when it is not known.
+func (d *Decoder) getLayout(addr common.Address) (*storage.StorageLayout, error) {
+	if d.layoutResolver == nil {
+		return nil, nil
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if layout, ok := d.layouts[addr]; ok {
+		return layout, nil
+	}
+
+	layout, err := d.layoutResolver(addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve storage layout of %s: %w", addr.Hex(), err)
+	}
+
+	d.layouts[addr] = layout
+	return layout, nil
+}
@@ -0,0 +1,320 @@
+package traces
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"os"
+	"strings"
+	"testing"
+
+	abi "github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unpackdev/solgo/bytecode"
+	"github.com/unpackdev/solgo/standards"
+	"github.com/unpackdev/solgo/storage"
+)
+
+// Accounts of the recorded traces. The vault deposits to the recipient, calls the token reverting with the
+// custom error, delegates to the library writing the flag into the vault storage and sends value to the
+// recipient. The relay calls the token and bubbles its revert up.
+var (
+	sender    = common.HexToAddress("0x00000000000000000000000000000000000a11ce")
+	vault     = common.HexToAddress("0x0000000000000000000000000000000000000100")
+	token     = common.HexToAddress("0x0000000000000000000000000000000000000200")
+	library   = common.HexToAddress("0x0000000000000000000000000000000000000300")
+	recipient = common.HexToAddress("0x0000000000000000000000000000000000000400")
+	relay     = common.HexToAddress("0x0000000000000000000000000000000000000500")
+)
+
+var testAbis = map[common.Address][]byte{
+	vault:   []byte(`[{"type":"function","name":"deposit","inputs":[{"name":"to","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"name":"ok","type":"bool"}]},{"type":"event","name":"Deposited","inputs":[{"name":"to","type":"address","indexed":true},{"name":"amount","type":"uint256","indexed":false}]}]`),
+	token:   []byte(`[{"type":"function","name":"transfer","inputs":[{"name":"to","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]},{"type":"error","name":"InsufficientBalance","inputs":[{"name":"available","type":"uint256"},{"name":"required","type":"uint256"}]}]`),
+	library: []byte(`[{"type":"function","name":"setFlag","inputs":[{"name":"flag","type":"uint256"}],"outputs":[{"name":"flag","type":"uint256"}]}]`),
+	relay:   []byte(`[{"type":"function","name":"relay","inputs":[],"outputs":[]}]`),
+}
+
+var testLayouts = map[common.Address]*storage.StorageLayout{
+	vault: {Slots: []*storage.SlotDescriptor{
+		{Name: "totalDeposits", Type: "uint256", Slot: 0},
+		{Name: "balances", Type: "mapping(address => uint256)", Slot: 1},
+	}},
+	library: {Slots: []*storage.SlotDescriptor{
+		{Name: "flag", Type: "uint256", Slot: 2},
+	}},
+}
+
+// messages are the transactions the struct logs were recorded for.
+var messages = map[string]*Message{
+	"deposit": {
+		From:  sender,
+		To:    &vault,
+		Value: big.NewInt(3),
+		Input: common.FromHex("0x47e7ef24000000000000000000000000000000000000000000000000000000000000040000000000000000000000000000000000000000000000000000000000000003e8"),
+		Gas:   1_000_000,
+	},
+	"relay": {
+		From:  sender,
+		To:    &relay,
+		Value: big.NewInt(0),
+		Input: common.FromHex("0xb59589d1"),
+		Gas:   1_000_000,
+	},
+}
+
+func decodeFixture(t *testing.T, decoder *Decoder, name string, structLogs bool) *Frame {
+	if !structLogs {
+		data, err := os.ReadFile("../data/tests/traces/" + name + ".call.json")
+		require.NoError(t, err)
+
+		var trace *CallFrame
+		require.NoError(t, json.Unmarshal(data, &trace))
+
+		frame, err := decoder.DecodeCallTrace(trace)
+		require.NoError(t, err)
+		return frame
+	}
+
+	data, err := os.ReadFile("../data/tests/traces/" + name + ".structlogs.json")
+	require.NoError(t, err)
+
+	var result *ExecutionResult
+	require.NoError(t, json.Unmarshal(data, &result))
+
+	frame, err := decoder.DecodeStructLogs(messages[name], result)
+	require.NoError(t, err)
+	return frame
+}
+
+func TestDecoder(t *testing.T) {
+	tests := []struct {
+		name       string
+		structLogs bool
+	}{
+		{name: "Call Tracer", structLogs: false},
+		{name: "Struct Log Tracer", structLogs: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoder := NewDecoder(bytecode.NewStaticAbiResolver(testAbis), NewStaticLayoutResolver(testLayouts))
+
+			root := decodeFixture(t, decoder, "deposit", tt.structLogs)
+			assert.False(t, root.IsReverted())
+			assert.Equal(t, "deposit", root.GetName())
+			assert.Equal(t, recipient, root.Transaction.Inputs["to"])
+			assert.Equal(t, big.NewInt(1000), root.Transaction.Inputs["amount"])
+			assert.Equal(t, true, root.Outputs["ok"])
+
+			require.Len(t, root.Logs, 1)
+			require.NotNil(t, root.Logs[0].Decoded)
+			assert.Equal(t, "Deposited", root.Logs[0].Decoded.Name)
+			assert.Equal(t, big.NewInt(1000), root.Logs[0].Decoded.Data["amount"])
+			assert.Equal(t, 0, root.Logs[0].Position)
+
+			require.Len(t, root.Calls, 3)
+			transfer, setFlag, payment := root.Calls[0], root.Calls[1], root.Calls[2]
+
+			assert.Equal(t, "CALL", transfer.Type)
+			assert.Equal(t, "transfer", transfer.GetName())
+			assert.True(t, transfer.IsReverted())
+			require.NotNil(t, transfer.Revert)
+			assert.Equal(t, RevertCustom, transfer.Revert.Kind)
+			assert.Equal(t, "InsufficientBalance", transfer.Revert.Name)
+			assert.Equal(t, big.NewInt(1000), transfer.Revert.Inputs["required"])
+
+			assert.Equal(t, "DELEGATECALL", setFlag.Type)
+			assert.Equal(t, library, setFlag.To)
+			assert.Equal(t, vault, setFlag.Context)
+			assert.Equal(t, "setFlag", setFlag.GetName())
+			assert.Equal(t, big.NewInt(7), setFlag.Outputs["flag"])
+
+			assert.Equal(t, recipient, payment.To)
+			assert.Empty(t, payment.GetName())
+			assert.False(t, payment.IsReverted())
+
+			// Transfers of the reverted and delegate calls are left out.
+			assert.Equal(t, []*Transfer{
+				{From: sender, To: vault, Value: big.NewInt(3)},
+				{From: vault, To: recipient, Value: big.NewInt(2)},
+			}, root.GetTransfers())
+
+			// Bubbled up custom errors are decoded with the ABI of the call they originate from.
+			root = decodeFixture(t, decoder, "relay", tt.structLogs)
+			assert.Equal(t, "relay", root.GetName())
+			require.NotNil(t, root.Revert)
+			assert.Equal(t, RevertCustom, root.Revert.Kind)
+			assert.Equal(t, "InsufficientBalance(uint256,uint256)", root.Revert.Signature)
+			assert.Equal(t, big.NewInt(5), root.Revert.Inputs["required"])
+			assert.Empty(t, root.GetTransfers())
+		})
+	}
+}
+
+func TestDecodeStructLogsStorageWrites(t *testing.T) {
+	decoder := NewDecoder(bytecode.NewStaticAbiResolver(testAbis), NewStaticLayoutResolver(testLayouts))
+	root := decodeFixture(t, decoder, "deposit", true)
+
+	balanceSlot := crypto.Keccak256Hash(common.LeftPadBytes(recipient.Bytes(), 32), common.LeftPadBytes([]byte{1}, 32))
+	assert.Equal(t, []*StorageWrite{
+		{Address: vault, Slot: balanceSlot, Value: common.BigToHash(big.NewInt(1000)), Variable: "balances[" + recipient.Hex() + "]", Type: "uint256"},
+		{Address: vault, Slot: common.BigToHash(big.NewInt(0)), Value: common.BigToHash(big.NewInt(1000)), Variable: "totalDeposits", Type: "uint256"},
+		{Address: vault, Slot: common.BigToHash(big.NewInt(2)), Value: common.BigToHash(big.NewInt(7)), Variable: "flag", Type: "uint256"},
+	}, root.GetStorageWrites())
+
+	// Writes of the delegate call are made to the storage of the caller.
+	assert.Len(t, root.Calls[1].StorageWrites, 1)
+	assert.Equal(t, uint64(114241), root.GasUsed)
+	assert.NotZero(t, root.Calls[1].GasUsed)
+
+	// Writes are left unnamed without the layouts.
+	root = decodeFixture(t, NewDecoder(nil, nil), "deposit", true)
+	assert.Empty(t, root.GetName())
+	assert.Empty(t, root.GetStorageWrites()[0].Variable)
+
+	_, err := decoder.DecodeStructLogs(messages["deposit"], &ExecutionResult{})
+	assert.ErrorIs(t, err, ErrEmptyTrace)
+
+	_, err = decoder.DecodeStructLogs(messages["deposit"], &ExecutionResult{StructLogs: []*StructLog{{Op: "PUSH1", Depth: 2}}})
+	assert.ErrorIs(t, err, ErrInvalidTrace)
+
+	_, err = decoder.DecodeCallTrace(nil)
+	assert.ErrorIs(t, err, ErrEmptyTrace)
+}
+
+func TestNameSlot(t *testing.T) {
+	layout := &storage.StorageLayout{Slots: []*storage.SlotDescriptor{
+		{Name: "owner", Type: "address", Slot: 0, Offset: 0},
+		{Name: "paused", Type: "bool", Slot: 0, Offset: 20},
+		{Name: "allowances", Type: "mapping(address => mapping(address => uint256))", Slot: 1},
+		{Name: "holders", Type: "address[]", Slot: 2},
+		{Name: "names", Type: "mapping(string => int256)", Slot: 3},
+		{Name: "debts", Type: "mapping(int256 => uint256)", Slot: 4},
+		{Name: "ranges", Type: "uint256[3][]", Slot: 5},
+		{Name: "flags", Type: "bool[]", Slot: 6},
+		{Name: "positions", Type: "struct Pool.Position[]", Slot: 7},
+	}}
+
+	owner, spender := common.HexToAddress("0x01"), common.HexToAddress("0x02")
+	preimages := make(map[common.Hash][]byte)
+	hash := func(data ...[]byte) common.Hash {
+		image := make([]byte, 0)
+		for _, chunk := range data {
+			image = append(image, chunk...)
+		}
+		preimages[crypto.Keccak256Hash(image)] = image
+		return crypto.Keccak256Hash(image)
+	}
+	word := func(value int64) []byte {
+		return common.BigToHash(big.NewInt(value)).Bytes()
+	}
+
+	ownerAllowances := hash(common.LeftPadBytes(owner.Bytes(), 32), word(1))
+	allowance := hash(common.LeftPadBytes(spender.Bytes(), 32), ownerAllowances.Bytes())
+	holders := hash(word(2))
+	name := hash([]byte("alice"), word(3))
+	ranges := hash(word(5))
+	flags := hash(word(6))
+	positions := hash(word(7))
+	debt := hash(common.BigToHash(new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(5))).Bytes(), word(4))
+
+	tests := []struct {
+		name         string
+		slot         common.Hash
+		expectedName string
+		expectedType string
+	}{
+		{name: "Packed Slot", slot: common.BigToHash(big.NewInt(0)), expectedName: "owner, paused", expectedType: "address, bool"},
+		{name: "Nested Mapping", slot: allowance, expectedName: "allowances[" + owner.Hex() + "][" + spender.Hex() + "]", expectedType: "uint256"},
+		{name: "Array Element", slot: common.BigToHash(new(big.Int).Add(holders.Big(), big.NewInt(3))), expectedName: "holders[3]", expectedType: "address"},
+		{name: "String Key", slot: name, expectedName: `names["alice"]`, expectedType: "int256"},
+		{name: "Struct Member", slot: common.BigToHash(new(big.Int).Add(name.Big(), big.NewInt(2))), expectedName: `names["alice"]+2`, expectedType: "int256"},
+		{name: "Signed Key", slot: debt, expectedName: "debts[-5]", expectedType: "uint256"},
+		{name: "Multi Slot Element", slot: common.BigToHash(new(big.Int).Add(ranges.Big(), big.NewInt(7))), expectedName: "ranges[2]+1", expectedType: "uint256[3]"},
+		{name: "Multi Slot Element Start", slot: common.BigToHash(new(big.Int).Add(ranges.Big(), big.NewInt(6))), expectedName: "ranges[2]", expectedType: "uint256[3]"},
+		{name: "Packed Elements", slot: common.BigToHash(new(big.Int).Add(flags.Big(), big.NewInt(2))), expectedName: "flags[64:96]", expectedType: "bool"},
+		{name: "Unknown Element Size", slot: common.BigToHash(new(big.Int).Add(positions.Big(), big.NewInt(7))), expectedName: "positions[0]+7", expectedType: "struct Pool.Position"},
+		{name: "Unknown Slot", slot: common.BigToHash(big.NewInt(9)), expectedName: "", expectedType: ""},
+		{name: "Unknown Hash", slot: crypto.Keccak256Hash([]byte("unknown")), expectedName: "", expectedType: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			variable, variableType := nameSlot(layout, preimages, tt.slot)
+			assert.Equal(t, tt.expectedName, variable)
+			assert.Equal(t, tt.expectedType, variableType)
+		})
+	}
+}
+
+func TestDecodeRevert(t *testing.T) {
+	stringType, err := abi.NewType("string", "", nil)
+	require.NoError(t, err)
+	reason, err := abi.Arguments{{Type: stringType}}.Pack("not allowed")
+	require.NoError(t, err)
+
+	uintType, err := abi.NewType("uint256", "", nil)
+	require.NoError(t, err)
+	code, err := abi.Arguments{{Type: uintType}}.Pack(big.NewInt(0x11))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		output   []byte
+		expected *Revert
+	}{
+		{name: "Error", output: append(errorSelector, reason...), expected: &Revert{Kind: RevertError, Reason: "not allowed"}},
+		{name: "Panic", output: append(panicSelector, code...), expected: &Revert{Kind: RevertPanic, Reason: "arithmetic underflow or overflow"}},
+		{name: "Empty", output: nil, expected: &Revert{Kind: RevertUnknown}},
+		{name: "Unknown", output: common.FromHex("0xdeadbeef"), expected: &Revert{Kind: RevertUnknown}},
+	}
+
+	decoder := NewDecoder(bytecode.NewStaticAbiResolver(testAbis), nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			revert, err := decoder.decodeRevert(&Frame{To: token, Output: tt.output, Error: "execution reverted"})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, revert)
+		})
+	}
+}
+
+func TestAbiResolvers(t *testing.T) {
+	require.NoError(t, standards.LoadStandards())
+
+	content, err := os.ReadFile("../data/tests/bytecode/BinancePegEthereum.bin")
+	require.NoError(t, err)
+	code := common.FromHex(strings.TrimSpace(string(content)))
+
+	resolver := ChainAbiResolvers(
+		bytecode.NewStaticAbiResolver(testAbis),
+		NewBytecodeAbiResolver(context.TODO(), func(addr common.Address) ([]byte, error) {
+			if addr == token {
+				return code, nil
+			}
+			return nil, nil
+		}),
+	)
+
+	abiData, err := resolver(vault)
+	require.NoError(t, err)
+	assert.Equal(t, testAbis[vault], abiData)
+
+	abiData, err = resolver(token)
+	require.NoError(t, err)
+	assert.Equal(t, testAbis[token], abiData)
+
+	abiData, err = ChainAbiResolvers(NewBytecodeAbiResolver(context.TODO(), func(common.Address) ([]byte, error) { return code, nil }))(token)
+	require.NoError(t, err)
+	parsed, err := abi.JSON(strings.NewReader(string(abiData)))
+	require.NoError(t, err)
+	assert.Contains(t, parsed.Methods, "transfer")
+	assert.Contains(t, parsed.Events, "Transfer")
+
+	abiData, err = resolver(recipient)
+	require.NoError(t, err)
+	assert.Nil(t, abiData)
+}
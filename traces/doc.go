@@ -0,0 +1,8 @@
+// Package traces decodes the debug_traceTransaction output of the callTracer and the struct-log tracer into
+// the call tree annotated with the decoded methods, return values, logs, revert reasons, custom errors, value
+// transfers and storage writes.
+// Frames are decoded with the ABI of their target, resolved from the contracts.Contract or reconstructed out of
+// the contract bytecode, and the storage writes are mapped to the state variable names with the storage layout.
+// Slots of the mapping and dynamic array entries are resolved through the keccak256 preimages recorded by the
+// struct-log tracer.
+package traces
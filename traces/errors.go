@@ -0,0 +1,12 @@
+package traces
+
+import "errors"
+
+var (
+	// ErrEmptyTrace is returned when the trace carries no frame or struct log to decode.
+	ErrEmptyTrace = errors.New("trace is empty")
+
+	// ErrInvalidTrace is returned when the struct logs do not form the valid execution, such as the value
+	// missing on the stack of the call or the depth jumping by more than one frame.
+	ErrInvalidTrace = errors.New("invalid trace")
+)
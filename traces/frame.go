@@ -0,0 +1,158 @@
+package traces
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/unpackdev/solgo/bytecode"
+)
+
+// RevertKind is the kind of the data the frame reverted with.
+type RevertKind string
+
+const (
+	// RevertError is the revert with the Error(string) reason, emitted by require and revert with the message.
+	RevertError RevertKind = "error"
+
+	// RevertPanic is the revert with the Panic(uint256) code, emitted by the failing assertions and arithmetic.
+	RevertPanic RevertKind = "panic"
+
+	// RevertCustom is the revert with the custom error declared by the ABI.
+	RevertCustom RevertKind = "custom"
+
+	// RevertUnknown is the revert with no data, or with the data matching none of the known errors.
+	RevertUnknown RevertKind = "unknown"
+)
+
+// Frame is the call frame of the trace annotated with the decoded method, return values, logs, revert and
+// storage writes. Frames of the calls made by the frame are carried as its children.
+type Frame struct {
+	Type          string                 `json:"type"`                     // CALL, STATICCALL, DELEGATECALL, CALLCODE, CREATE, CREATE2 or SELFDESTRUCT.
+	From          common.Address         `json:"from"`                     // Address making the call.
+	To            common.Address         `json:"to"`                       // Address of the code executed, or the created contract.
+	Context       common.Address         `json:"context"`                  // Address the storage and balance of which the code operates on.
+	Value         *big.Int               `json:"value,omitempty"`          // Value sent along with the call.
+	Gas           uint64                 `json:"gas"`                      // Gas available to the frame.
+	GasUsed       uint64                 `json:"gas_used"`                 // Gas used by the frame, including its calls.
+	Input         []byte                 `json:"input,omitempty"`          // Calldata, or the init code of the creation.
+	Output        []byte                 `json:"output,omitempty"`         // Return or revert data.
+	Error         string                 `json:"error,omitempty"`          // Error the frame failed with, empty when it succeeded.
+	Depth         int                    `json:"depth"`                    // Depth of the frame, zero for the transaction itself.
+	Transaction   *bytecode.Transaction  `json:"transaction,omitempty"`    // Decoded method, nil when the ABI is not known.
+	Outputs       map[string]interface{} `json:"outputs,omitempty"`        // Decoded return values of the method.
+	Revert        *Revert                `json:"revert,omitempty"`         // Decoded revert data, nil when the frame succeeded.
+	Logs          []*Log                 `json:"logs,omitempty"`           // Logs emitted by the frame itself.
+	StorageWrites []*StorageWrite        `json:"storage_writes,omitempty"` // Storage writes made by the frame itself.
+	Calls         []*Frame               `json:"calls,omitempty"`          // Frames of the calls made by the frame.
+}
+
+// Log is the log emitted by the frame, decoded with the ABI of the frame code or of the emitting contract.
+type Log struct {
+	Address  common.Address `json:"address"`
+	Topics   []common.Hash  `json:"topics"`
+	Data     []byte         `json:"data,omitempty"`
+	Position int            `json:"position"`          // Number of the calls made by the frame before the log was emitted.
+	Decoded  *bytecode.Log  `json:"decoded,omitempty"` // Decoded event, nil when the ABI is not known.
+}
+
+// Revert is the decoded revert data of the failed frame.
+type Revert struct {
+	Kind      RevertKind             `json:"kind"`
+	Reason    string                 `json:"reason,omitempty"`    // Message of the Error(string) revert, or the description of the panic code.
+	Name      string                 `json:"name,omitempty"`      // Name of the custom error.
+	Signature string                 `json:"signature,omitempty"` // Signature of the custom error.
+	Inputs    map[string]interface{} `json:"inputs,omitempty"`    // Decoded arguments of the custom error.
+}
+
+// StorageWrite is the storage slot written by the frame, named after the state variable stored in it.
+type StorageWrite struct {
+	Address  common.Address `json:"address"`            // Address the storage of which was written.
+	Slot     common.Hash    `json:"slot"`               // Slot written.
+	Value    common.Hash    `json:"value"`              // Value written to the slot.
+	Variable string         `json:"variable,omitempty"` // Variable stored in the slot, e.g. "balances[0x...]", empty when not known.
+	Type     string         `json:"type,omitempty"`     // Type of the variable stored in the slot.
+}
+
+// Transfer is the transfer of the native currency made by the frame.
+type Transfer struct {
+	From  common.Address `json:"from"`
+	To    common.Address `json:"to"`
+	Value *big.Int       `json:"value"`
+}
+
+// IsReverted reports whether the frame failed, so its state changes, those of its calls included, were discarded.
+func (f *Frame) IsReverted() bool {
+	return f.Error != ""
+}
+
+// GetName returns the name of the decoded method, empty when the frame was not decoded.
+func (f *Frame) GetName() string {
+	if f.Transaction != nil {
+		return f.Transaction.Name
+	}
+	return ""
+}
+
+// Walk calls the visit function for the frame and all of its calls, depth first, in the order of execution.
+// Walking stops when the visit function returns false.
+func (f *Frame) Walk(visit func(frame *Frame) bool) {
+	f.walk(visit)
+}
+
+func (f *Frame) walk(visit func(frame *Frame) bool) bool {
+	if !visit(f) {
+		return false
+	}
+	for _, call := range f.Calls {
+		if !call.walk(visit) {
+			return false
+		}
+	}
+	return true
+}
+
+// GetTransfers returns the transfers of the native currency made by the frame and its calls, in the order of
+// execution. Transfers of the reverted frames are discarded along with the rest of their state changes, and
+// the delegate and static calls transfer nothing, even when the tracer reports the value of their caller.
+func (f *Frame) GetTransfers() []*Transfer {
+	transfers := make([]*Transfer, 0)
+
+	var walk func(frame *Frame)
+	walk = func(frame *Frame) {
+		if frame.IsReverted() {
+			return
+		}
+
+		if frame.Value != nil && frame.Value.Sign() > 0 && frame.Type != "DELEGATECALL" && frame.Type != "STATICCALL" {
+			transfers = append(transfers, &Transfer{From: frame.From, To: frame.Context, Value: frame.Value})
+		}
+
+		for _, call := range frame.Calls {
+			walk(call)
+		}
+	}
+	walk(f)
+
+	return transfers
+}
+
+// GetStorageWrites returns the storage writes made by the frame and its calls that were not reverted, in the
+// order of execution.
+func (f *Frame) GetStorageWrites() []*StorageWrite {
+	writes := make([]*StorageWrite, 0)
+
+	var walk func(frame *Frame)
+	walk = func(frame *Frame) {
+		if frame.IsReverted() {
+			return
+		}
+
+		writes = append(writes, frame.StorageWrites...)
+		for _, call := range frame.Calls {
+			walk(call)
+		}
+	}
+	walk(f)
+
+	return writes
+}
@@ -0,0 +1,93 @@
+package traces
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/unpackdev/solgo/bytecode"
+	"github.com/unpackdev/solgo/contracts"
+	"github.com/unpackdev/solgo/standards"
+	"github.com/unpackdev/solgo/storage"
+)
+
+// LayoutResolver returns the storage layout of the contract at the address, nil when the layout is not known.
+type LayoutResolver func(addr common.Address) (*storage.StorageLayout, error)
+
+// CodeResolver returns the runtime bytecode of the contract at the address, such as the code at the traced block.
+type CodeResolver func(addr common.Address) ([]byte, error)
+
+// NewStaticLayoutResolver returns the resolver of the storage layouts known upfront, by the address of their
+// contracts, e.g. the layouts described with storage.Storage.
+func NewStaticLayoutResolver(layouts map[common.Address]*storage.StorageLayout) LayoutResolver {
+	return func(addr common.Address) (*storage.StorageLayout, error) {
+		return layouts[addr], nil
+	}
+}
+
+// NewContractAbiResolver returns the resolver of the ABIs of the discovered contracts. Contracts without the
+// verified ABI are resolved with the ABI reconstructed out of their deployed bytecode.
+func NewContractAbiResolver(ctx context.Context, discovered ...*contracts.Contract) bytecode.AbiResolver {
+	byAddress := make(map[common.Address]*contracts.Contract, len(discovered))
+	for _, contract := range discovered {
+		byAddress[contract.GetAddress()] = contract
+	}
+
+	return func(addr common.Address) ([]byte, error) {
+		contract, ok := byAddress[addr]
+		if !ok {
+			return nil, nil
+		}
+
+		if contractAbi := contract.GetDescriptor().GetABI(); contractAbi != "" {
+			return []byte(contractAbi), nil
+		}
+
+		return reconstructAbi(ctx, addr, contract.GetDeployedBytecode())
+	}
+}
+
+// NewBytecodeAbiResolver returns the resolver of the ABIs reconstructed out of the bytecode of the contracts,
+// with the functions and events of the standards found within the bytecode. Standards must be loaded first.
+func NewBytecodeAbiResolver(ctx context.Context, code CodeResolver) bytecode.AbiResolver {
+	return func(addr common.Address) ([]byte, error) {
+		runtimeCode, err := code(addr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get code of %s: %w", addr.Hex(), err)
+		}
+
+		return reconstructAbi(ctx, addr, runtimeCode)
+	}
+}
+
+// ChainAbiResolvers returns the resolver asking the resolvers in order, until any of them knows the ABI.
+func ChainAbiResolvers(resolvers ...bytecode.AbiResolver) bytecode.AbiResolver {
+	return func(addr common.Address) ([]byte, error) {
+		for _, resolver := range resolvers {
+			abiData, err := resolver(addr)
+			if err != nil {
+				return nil, err
+			}
+
+			if abiData != nil {
+				return abiData, nil
+			}
+		}
+
+		return nil, nil
+	}
+}
+
+// reconstructAbi reconstructs the ABI out of the runtime bytecode, nil when the account has no code.
+func reconstructAbi(ctx context.Context, addr common.Address, runtimeCode []byte) ([]byte, error) {
+	if len(runtimeCode) == 0 {
+		return nil, nil
+	}
+
+	matcher, err := standards.NewBytecodeMatcherFromBytecode(ctx, addr.Hex(), runtimeCode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompile code of %s: %w", addr.Hex(), err)
+	}
+
+	return matcher.ReconstructABI()
+}
@@ -0,0 +1,220 @@
+package traces
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/unpackdev/solgo/storage"
+)
+
+const (
+	// maxSlotOffset is the largest offset from the keccak256 hash of the base slot that is still considered the
+	// member of the struct or the element of the array stored at it.
+	maxSlotOffset = 1 << 16
+
+	// maxSlotNesting is the deepest nesting of the mappings and arrays that is resolved.
+	maxSlotNesting = 8
+)
+
+// nameSlot returns the name and type of the state variable stored in the slot, empty when not known. Slots of
+// the mapping entries and dynamic array elements are resolved through the keccak256 preimages, e.g.
+// "balances[0x...]" or "holders[3]". Struct members and static array elements stored past the first slot of
+// the entry are suffixed with their slot offset, e.g. "positions[7]+2". Variables packed into the same slot
+// are joined with the comma.
+func nameSlot(layout *storage.StorageLayout, preimages map[common.Hash][]byte, slot common.Hash) (string, string) {
+	return resolveSlot(layout, preimages, new(big.Int).SetBytes(slot.Bytes()), 0)
+}
+
+func resolveSlot(layout *storage.StorageLayout, preimages map[common.Hash][]byte, slot *big.Int, nesting int) (string, string) {
+	if nesting > maxSlotNesting {
+		return "", ""
+	}
+
+	if slot.IsInt64() {
+		var names, types []string
+		for _, descriptor := range layout.GetSlots() {
+			if descriptor != nil && descriptor.Slot == slot.Int64() {
+				names = append(names, descriptor.Name)
+				types = append(types, descriptor.Type)
+			}
+		}
+
+		if len(names) > 0 {
+			return strings.Join(names, ", "), strings.Join(types, ", ")
+		}
+	}
+
+	// Entries are stored at the keccak256 hash of the key and the base slot for the mappings, and of the base
+	// slot alone for the dynamic arrays, strings and bytes. The closest hash below the slot is the entry.
+	var image []byte
+	var offset *big.Int
+	for hash, preimage := range preimages {
+		distance := new(big.Int).Sub(slot, new(big.Int).SetBytes(hash.Bytes()))
+		if distance.Sign() < 0 || distance.Cmp(big.NewInt(maxSlotOffset)) >= 0 {
+			continue
+		}
+
+		if offset == nil || distance.Cmp(offset) < 0 {
+			image, offset = preimage, distance
+		}
+	}
+
+	if image == nil || len(image) < 32 {
+		return "", ""
+	}
+
+	base := new(big.Int).SetBytes(image[len(image)-32:])
+	baseName, baseType := resolveSlot(layout, preimages, base, nesting+1)
+	if baseName == "" {
+		return "", ""
+	}
+
+	if len(image) == 32 {
+		// Long strings and bytes are stored the same way as the dynamic arrays, in the chunks of 32 bytes.
+		if baseType == "string" || baseType == "bytes" {
+			return baseName, baseType
+		}
+		elementType := strings.TrimSuffix(baseType, "[]")
+		return nameElement(baseName, elementType, offset), elementType
+	}
+
+	keyType, valueType := splitMappingType(baseType)
+	name := fmt.Sprintf("%s[%s]", baseName, formatKey(image[:len(image)-32], keyType))
+	if offset.Sign() > 0 {
+		name = fmt.Sprintf("%s+%s", name, offset)
+	}
+
+	return name, valueType
+}
+
+// nameElement names the slot of the dynamic array at the offset from the slot of its first element. Elements
+// spanning multiple slots are suffixed with the slot offset within the element, e.g. "positions[1]+2", and the
+// slots of the packed elements are named with the range of the elements they hold, e.g. "flags[64:96]". The
+// offset is kept from the first element, e.g. "positions[0]+7", when the size of the element is not known.
+func nameElement(name string, elementType string, offset *big.Int) string {
+	size, perSlot, known := elementSize(elementType)
+	switch {
+	case !known:
+		if offset.Sign() == 0 {
+			return fmt.Sprintf("%s[0]", name)
+		}
+		return fmt.Sprintf("%s[0]+%s", name, offset)
+	case perSlot > 1:
+		first := new(big.Int).Mul(offset, big.NewInt(perSlot))
+		return fmt.Sprintf("%s[%s:%s]", name, first, new(big.Int).Add(first, big.NewInt(perSlot)))
+	}
+
+	index, remainder := new(big.Int).QuoRem(offset, big.NewInt(size), new(big.Int))
+	if remainder.Sign() == 0 {
+		return fmt.Sprintf("%s[%s]", name, index)
+	}
+	return fmt.Sprintf("%s[%s]+%s", name, index, remainder)
+}
+
+// elementSize returns the number of slots the element of the type occupies, and the number of the elements
+// packed into the single slot, one for the elements spanning whole slots. It returns false for the structs and
+// the types that are not known.
+func elementSize(typeName string) (int64, int64, bool) {
+	typeName = strings.TrimSpace(typeName)
+
+	switch {
+	case strings.HasPrefix(typeName, "mapping("), typeName == "string", typeName == "bytes",
+		strings.HasSuffix(typeName, "[]"):
+		return 1, 1, true
+	case strings.HasSuffix(typeName, "]"):
+		open := strings.LastIndex(typeName, "[")
+		length, ok := new(big.Int).SetString(typeName[open+1:len(typeName)-1], 10)
+		if open < 0 || !ok || !length.IsInt64() || length.Sign() <= 0 {
+			return 0, 0, false
+		}
+
+		size, perSlot, known := elementSize(typeName[:open])
+		if !known {
+			return 0, 0, false
+		}
+		if perSlot > 1 {
+			return (length.Int64() + perSlot - 1) / perSlot, 1, true
+		}
+		return size * length.Int64(), 1, true
+	}
+
+	bytes, known := valueSize(typeName)
+	if !known {
+		return 0, 0, false
+	}
+	return 1, 32 / bytes, true
+}
+
+// valueSize returns the size of the value type in bytes.
+func valueSize(typeName string) (int64, bool) {
+	bits := func(prefix string) (int64, bool) {
+		if typeName == prefix {
+			return 32, true
+		}
+		size, ok := new(big.Int).SetString(strings.TrimPrefix(typeName, prefix), 10)
+		if !ok || !size.IsInt64() || size.Int64() <= 0 || size.Int64() > 256 || size.Int64()%8 != 0 {
+			return 0, false
+		}
+		return size.Int64() / 8, true
+	}
+
+	switch {
+	case typeName == "bool":
+		return 1, true
+	case typeName == "address", typeName == "address payable", strings.HasPrefix(typeName, "contract "):
+		return 20, true
+	case strings.HasPrefix(typeName, "uint"):
+		return bits("uint")
+	case strings.HasPrefix(typeName, "int"):
+		return bits("int")
+	case strings.HasPrefix(typeName, "bytes"):
+		size, ok := new(big.Int).SetString(strings.TrimPrefix(typeName, "bytes"), 10)
+		if !ok || !size.IsInt64() || size.Int64() <= 0 || size.Int64() > 32 {
+			return 0, false
+		}
+		return size.Int64(), true
+	}
+
+	return 0, false
+}
+
+// splitMappingType returns the key and value types of the mapping type, e.g. "address" and "uint256" for the
+// "mapping(address => uint256)".
+func splitMappingType(typeName string) (string, string) {
+	if !strings.HasPrefix(typeName, "mapping(") || !strings.HasSuffix(typeName, ")") {
+		return "", ""
+	}
+
+	keyType, valueType, found := strings.Cut(typeName[len("mapping("):len(typeName)-1], "=>")
+	if !found {
+		return "", ""
+	}
+
+	return strings.TrimSpace(keyType), strings.TrimSpace(valueType)
+}
+
+// formatKey formats the mapping key by its type, falling back to the hex encoding.
+func formatKey(key []byte, keyType string) string {
+	switch {
+	case keyType == "string":
+		return fmt.Sprintf("%q", key)
+	case len(key) != 32:
+		return "0x" + common.Bytes2Hex(key)
+	case keyType == "address" || strings.HasPrefix(keyType, "contract "):
+		return common.BytesToAddress(key).Hex()
+	case keyType == "bool":
+		return fmt.Sprintf("%t", key[31] != 0)
+	case strings.HasPrefix(keyType, "uint"):
+		return new(big.Int).SetBytes(key).String()
+	case strings.HasPrefix(keyType, "int"):
+		value := new(big.Int).SetBytes(key)
+		if key[0]&0x80 != 0 {
+			value.Sub(value, new(big.Int).Lsh(big.NewInt(1), 256))
+		}
+		return value.String()
+	default:
+		return "0x" + common.Bytes2Hex(key)
+	}
+}
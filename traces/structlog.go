@@ -0,0 +1,327 @@
+package traces
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// maxMemoryRead is the largest memory range read out of the struct log, guarding against the ranges of the
+// steps failing with the out of gas error.
+const maxMemoryRead = 1 << 24
+
+// DecodeStructLogs decodes the struct-log tracer output of the message into the annotated call tree. Frames
+// are reconstructed from the call and create steps, and the storage writes, logs and return data are read
+// from the stack and memory of the steps, so the trace must be recorded with the memory enabled. Storage
+// writes of the mapping and array entries are named through the keccak256 preimages computed by the steps.
+func (d *Decoder) DecodeStructLogs(msg *Message, result *ExecutionResult) (*Frame, error) {
+	if msg == nil || result == nil || len(result.StructLogs) == 0 {
+		return nil, ErrEmptyTrace
+	}
+
+	root, preimages, err := newStructFrame(msg, result)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := d.annotate(root, preimages); err != nil {
+		return nil, err
+	}
+
+	return root, nil
+}
+
+// structFrame is the frame being executed while walking the struct logs.
+type structFrame struct {
+	*Frame
+	gas  uint64     // Gas available at the first step of the frame.
+	last *StructLog // Last step executed by the frame.
+}
+
+// newStructFrame reconstructs the call tree of the struct logs, along with the keccak256 preimages by their
+// hashes.
+func newStructFrame(msg *Message, result *ExecutionResult) (*Frame, map[common.Hash][]byte, error) {
+	root := &Frame{
+		Type:    "CALL",
+		From:    msg.From,
+		Value:   msg.Value,
+		Gas:     msg.Gas,
+		GasUsed: result.Gas,
+		Input:   msg.Input,
+		Output:  common.FromHex(result.ReturnValue),
+	}
+
+	if msg.To != nil {
+		root.To = *msg.To
+	} else {
+		root.Type = "CREATE"
+		root.To = crypto.CreateAddress(msg.From, msg.Nonce)
+	}
+	root.Context = root.To
+
+	if result.Failed {
+		root.Error = "execution reverted"
+	}
+
+	preimages := make(map[common.Hash][]byte)
+	frames := []*structFrame{{Frame: root}}
+
+	// Frame of the call made by the previous step, entered when the next step is one level deeper. Accounts
+	// without code and precompiles execute no steps, so their frames end right away.
+	var pending *Frame
+
+	// Frame whose result was pushed onto the stack of the caller, read at the next step of the caller.
+	var returned *Frame
+
+	for i, log := range result.StructLogs {
+		if pending != nil {
+			if log.Depth == len(frames)+1 {
+				pending.Gas = log.Gas
+				frames = append(frames, &structFrame{Frame: pending, gas: log.Gas})
+			} else {
+				returned = pending
+			}
+			pending = nil
+		}
+
+		for log.Depth < len(frames) && len(frames) > 1 {
+			child := frames[len(frames)-1]
+			frames = frames[:len(frames)-1]
+			if child.last != nil {
+				child.GasUsed = child.gas - child.last.Gas + child.last.GasCost
+			}
+			returned = child.Frame
+		}
+
+		if log.Depth != len(frames) {
+			return nil, nil, fmt.Errorf("%w: step %d at depth %d, expected %d", ErrInvalidTrace, i, log.Depth, len(frames))
+		}
+
+		frame := frames[len(frames)-1]
+		frame.last = log
+		if frame.gas == 0 {
+			frame.gas = log.Gas
+		}
+
+		if returned != nil {
+			if err := setReturned(returned, log); err != nil {
+				return nil, nil, fmt.Errorf("step %d: %w", i, err)
+			}
+			returned = nil
+		}
+
+		// Failing steps take no effect and end the frame.
+		if log.Error != "" {
+			frame.Error = log.Error
+			continue
+		}
+
+		call, err := executeStep(frame.Frame, log, preimages)
+		if err != nil {
+			return nil, nil, fmt.Errorf("step %d: %w", i, err)
+		}
+
+		if call != nil {
+			frame.Calls = append(frame.Calls, call)
+			if call.Type == "SELFDESTRUCT" {
+				continue
+			}
+			pending = call
+		}
+	}
+
+	return root, preimages, nil
+}
+
+// executeStep records the effects of the step on the frame, returning the frame of the call made by the step.
+func executeStep(frame *Frame, log *StructLog, preimages map[common.Hash][]byte) (*Frame, error) {
+	switch op := strings.ToUpper(log.Op); op {
+	case "CALL", "CALLCODE", "DELEGATECALL", "STATICCALL":
+		count := 6
+		if op == "CALL" || op == "CALLCODE" {
+			count = 7
+		}
+
+		values, err := stackValues(log, count)
+		if err != nil {
+			return nil, err
+		}
+
+		call := &Frame{
+			Type:    op,
+			From:    frame.Context,
+			To:      common.BigToAddress(values[1]),
+			Depth:   frame.Depth + 1,
+			Context: common.BigToAddress(values[1]),
+		}
+
+		argsOffset := 2
+		switch op {
+		case "CALL", "CALLCODE":
+			call.Value = values[2]
+			argsOffset = 3
+		case "DELEGATECALL":
+			call.Value = frame.Value
+		}
+
+		if op == "DELEGATECALL" || op == "CALLCODE" {
+			call.Context = frame.Context
+		}
+
+		call.Input = readMemory(log, values[argsOffset], values[argsOffset+1])
+
+		return call, nil
+
+	case "CREATE", "CREATE2":
+		values, err := stackValues(log, 3)
+		if err != nil {
+			return nil, err
+		}
+
+		return &Frame{
+			Type:  op,
+			From:  frame.Context,
+			Depth: frame.Depth + 1,
+			Value: values[0],
+			Input: readMemory(log, values[1], values[2]),
+		}, nil
+
+	case "SELFDESTRUCT":
+		values, err := stackValues(log, 1)
+		if err != nil {
+			return nil, err
+		}
+
+		beneficiary := common.BigToAddress(values[0])
+		return &Frame{
+			Type:    op,
+			From:    frame.Context,
+			To:      beneficiary,
+			Context: beneficiary,
+			Depth:   frame.Depth + 1,
+		}, nil
+
+	case "SSTORE":
+		values, err := stackValues(log, 2)
+		if err != nil {
+			return nil, err
+		}
+
+		frame.StorageWrites = append(frame.StorageWrites, &StorageWrite{
+			Address: frame.Context,
+			Slot:    common.BigToHash(values[0]),
+			Value:   common.BigToHash(values[1]),
+		})
+
+	case "KECCAK256", "SHA3":
+		values, err := stackValues(log, 2)
+		if err != nil {
+			return nil, err
+		}
+
+		if log.Memory != nil {
+			input := readMemory(log, values[0], values[1])
+			preimages[crypto.Keccak256Hash(input)] = input
+		}
+
+	case "LOG0", "LOG1", "LOG2", "LOG3", "LOG4":
+		topics := int(op[3] - '0')
+		values, err := stackValues(log, 2+topics)
+		if err != nil {
+			return nil, err
+		}
+
+		emitted := &Log{
+			Address:  frame.Context,
+			Topics:   make([]common.Hash, 0, topics),
+			Data:     readMemory(log, values[0], values[1]),
+			Position: len(frame.Calls),
+		}
+		for _, topic := range values[2:] {
+			emitted.Topics = append(emitted.Topics, common.BigToHash(topic))
+		}
+		frame.Logs = append(frame.Logs, emitted)
+
+	case "RETURN", "REVERT":
+		values, err := stackValues(log, 2)
+		if err != nil {
+			return nil, err
+		}
+
+		frame.Output = readMemory(log, values[0], values[1])
+		if op == "REVERT" {
+			frame.Error = "execution reverted"
+		}
+	}
+
+	return nil, nil
+}
+
+// setReturned sets the result of the call out of the value pushed onto the stack of the caller, the success
+// flag for the calls and the address of the created contract, zero on failure, for the creations.
+func setReturned(frame *Frame, log *StructLog) error {
+	if frame.Type == "SELFDESTRUCT" {
+		return nil
+	}
+
+	values, err := stackValues(log, 1)
+	if err != nil {
+		return err
+	}
+
+	if strings.HasPrefix(frame.Type, "CREATE") {
+		if values[0].Sign() != 0 {
+			frame.To = common.BigToAddress(values[0])
+			frame.Context = frame.To
+		}
+	}
+
+	if values[0].Sign() == 0 && frame.Error == "" {
+		frame.Error = "execution reverted"
+	}
+
+	return nil
+}
+
+// stackValues returns the values from the top of the stack of the step, the topmost first.
+func stackValues(log *StructLog, count int) ([]*big.Int, error) {
+	values := make([]*big.Int, count)
+	for i := 0; i < count; i++ {
+		if i >= len(log.Stack) {
+			return nil, fmt.Errorf("%w: %s expects %d stack values, got %d", ErrInvalidTrace, log.Op, count, len(log.Stack))
+		}
+
+		value, ok := new(big.Int).SetString(strings.TrimPrefix(log.Stack[len(log.Stack)-1-i], "0x"), 16)
+		if !ok {
+			return nil, fmt.Errorf("%w: invalid stack value %q", ErrInvalidTrace, log.Stack[len(log.Stack)-1-i])
+		}
+		values[i] = value
+	}
+
+	return values, nil
+}
+
+// readMemory reads the range of the memory of the step. Memory past the recorded words reads as zeros, the
+// same way the EVM expands the memory.
+func readMemory(log *StructLog, offset *big.Int, size *big.Int) []byte {
+	if size.Sign() == 0 || !size.IsUint64() || size.Uint64() > maxMemoryRead || !offset.IsUint64() || offset.Uint64() > maxMemoryRead {
+		return []byte{}
+	}
+
+	start, length := offset.Uint64(), size.Uint64()
+	data := make([]byte, length)
+	for i := start / 32; i < uint64(len(log.Memory)) && i*32 < start+length; i++ {
+		word := common.LeftPadBytes(common.FromHex(log.Memory[i]), 32)
+		for j := uint64(0); j < 32; j++ {
+			position := i*32 + j
+			if position >= start && position < start+length {
+				data[position-start] = word[j]
+			}
+		}
+	}
+
+	return data
+}
@@ -0,0 +1,66 @@
+package traces
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// CallFrame is the call frame reported by the callTracer, with the logs when traced with the withLog option.
+type CallFrame struct {
+	Type         string          `json:"type"`
+	From         common.Address  `json:"from"`
+	To           *common.Address `json:"to,omitempty"`
+	Value        *hexutil.Big    `json:"value,omitempty"`
+	Gas          hexutil.Uint64  `json:"gas"`
+	GasUsed      hexutil.Uint64  `json:"gasUsed"`
+	Input        hexutil.Bytes   `json:"input"`
+	Output       hexutil.Bytes   `json:"output,omitempty"`
+	Error        string          `json:"error,omitempty"`
+	RevertReason string          `json:"revertReason,omitempty"`
+	Logs         []*CallLog      `json:"logs,omitempty"`
+	Calls        []*CallFrame    `json:"calls,omitempty"`
+}
+
+// CallLog is the log emitted by the call frame, reported by the callTracer.
+type CallLog struct {
+	Address  common.Address `json:"address"`
+	Topics   []common.Hash  `json:"topics"`
+	Data     hexutil.Bytes  `json:"data"`
+	Position hexutil.Uint   `json:"position"` // Number of the calls made by the frame before the log was emitted.
+}
+
+// StructLog is the single step of the execution reported by the struct-log tracer. Stack values are hex encoded
+// with the 0x prefix, memory is split into the hex encoded 32 bytes words.
+type StructLog struct {
+	Pc      uint64            `json:"pc"`
+	Op      string            `json:"op"`
+	Gas     uint64            `json:"gas"`
+	GasCost uint64            `json:"gasCost"`
+	Depth   int               `json:"depth"`
+	Error   string            `json:"error,omitempty"`
+	Stack   []string          `json:"stack,omitempty"`
+	Memory  []string          `json:"memory,omitempty"`
+	Storage map[string]string `json:"storage,omitempty"`
+}
+
+// ExecutionResult is the result of the transaction traced with the struct-log tracer. Memory must be enabled
+// for the calldata, return values and logs of the frames to be recovered.
+type ExecutionResult struct {
+	Gas         uint64       `json:"gas"`
+	Failed      bool         `json:"failed"`
+	ReturnValue string       `json:"returnValue"`
+	StructLogs  []*StructLog `json:"structLogs"`
+}
+
+// Message is the transaction the struct logs were traced for, as the struct-log tracer does not report the
+// sender, recipient, value or calldata of the transaction itself.
+type Message struct {
+	From  common.Address  `json:"from"`
+	To    *common.Address `json:"to,omitempty"` // Recipient of the transaction, nil for the contract creation.
+	Value *big.Int        `json:"value,omitempty"`
+	Input []byte          `json:"input"`
+	Gas   uint64          `json:"gas"`
+	Nonce uint64          `json:"nonce"` // Nonce of the sender, deriving the address of the contract created.
+}
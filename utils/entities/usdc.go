@@ -11,3 +11,8 @@ var USDC = map[uint]*Token{
 var USDT = map[uint]*Token{
 	1: NewToken(1, common.HexToAddress("0xdac17f958d2ee523a2206206994597c13d831ec7"), 6, "USDT", "USDT Coin"),
 }
+
+// Known DAI implementation addresses
+var DAI = map[uint]*Token{
+	1: NewToken(1, common.HexToAddress("0x6b175474e89094c44da98b954eedeac495271d0f"), 18, "DAI", "Dai Stablecoin"),
+}
@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"context"
 	"sync"
 	"time"
 )
@@ -48,32 +49,32 @@ func (rl *RateLimiter) Allow() bool {
 }
 
 // WaitForToken blocks the caller until a token becomes available. If no tokens are
-// available upon invoking this method, it waits for a signal from a separate goroutine
-// indicating that tokens have been refilled. This method ensures that events respect
-// the rate limit by waiting for permission to proceed rather than immediately returning false.
+// available upon invoking this method, it sleeps until the next refill and tries again.
+// This method ensures that events respect the rate limit by waiting for permission to
+// proceed rather than immediately returning false.
 func (rl *RateLimiter) WaitForToken() {
-	for !rl.Allow() {
-		// Create a channel to receive a signal when tokens are refilled
-		signal := make(chan struct{})
-
-		// Start a goroutine to periodically check for token availability
-		go func() {
-			for {
-				timeToNextRefill := rl.refillTime - time.Since(rl.lastRefill)
-
-				// If time until next refill is greater than 0, sleep for that duration
-				if timeToNextRefill > 0 {
-					time.Sleep(timeToNextRefill)
-				}
+	_ = rl.Wait(context.Background())
+}
 
-				// Send a signal to indicate that tokens are refilled
-				signal <- struct{}{}
-			}
-		}()
+// Wait blocks the caller until a token becomes available or the context is done, in which
+// case the context error is returned. Tokens are refilled to capacity once the refill
+// interval elapses, so the caller sleeps until the next refill whenever the bucket is empty.
+func (rl *RateLimiter) Wait(ctx context.Context) error {
+	for !rl.Allow() {
+		rl.mutex.Lock()
+		timeToNextRefill := rl.refillTime - time.Since(rl.lastRefill)
+		rl.mutex.Unlock()
 
-		// Wait for a signal indicating that tokens are refilled
-		<-signal
+		timer := time.NewTimer(max(timeToNextRefill, time.Millisecond))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
 	}
+
+	return nil
 }
 
 // refill is a helper method that replenishes the tokens based on the time elapsed
@@ -137,6 +137,7 @@ const (
 	NoSimulator    SimulatorType = "no_simulator"
 	AnvilSimulator SimulatorType = "anvil"
 	TraceSimulator SimulatorType = "trace"
+	EvmSimulator   SimulatorType = "evm"
 
 	SimulatorAccountType AccountType = "simulator"
 	SimpleAccountType    AccountType = "simple"
@@ -151,7 +152,9 @@ const (
 	Erc20TokenType  TokenType = "erc20"
 	Erc721TokenType TokenType = "erc721"
 
-	AntiWhalePinksale AntiWhaleType = "pinksale"
+	AntiWhalePinksale       AntiWhaleType = "pinksale"
+	AntiWhaleMaxTransaction AntiWhaleType = "max_transaction"
+	AntiWhaleMaxWallet      AntiWhaleType = "max_wallet"
 
 	BuyTradeType  TradeType = "buy"
 	SellTradeType TradeType = "sell"
@@ -0,0 +1,12 @@
+package validation
+
+import "errors"
+
+// ErrImmutableReferencesUnavailable is returned when the bytecode compiled without the standard json output fails to
+// match only within the zeroed regions immutables are placed in. Such output carries no immutable references, so the
+// immutables cannot be masked and the bytecode has to be verified through the standard json instead.
+var ErrImmutableReferencesUnavailable = errors.New("compiler output carries no immutable references")
+
+// ErrDeployedBytecodeMissing is returned when the compiled entry contract has neither the deployed nor the creation
+// bytecode to match against.
+var ErrDeployedBytecodeMissing = errors.New("compiled entry contract has no bytecode")
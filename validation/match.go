@@ -0,0 +1,414 @@
+package validation
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/unpackdev/solgo"
+)
+
+// MatchType describes how closely the on-chain bytecode matches the compiled bytecode.
+type MatchType string
+
+const (
+	// MatchTypeFull means bytecode matches including the metadata hash, meaning the sources are exactly the same.
+	MatchTypeFull MatchType = "full"
+
+	// MatchTypePartial means bytecode matches only after the CBOR metadata has been stripped.
+	MatchTypePartial MatchType = "partial"
+
+	// MatchTypeNone means bytecode does not match.
+	MatchTypeNone MatchType = "none"
+)
+
+// VerificationMode describes the minimum match type required for bytecode to be considered verified.
+type VerificationMode string
+
+const (
+	// VerificationModeFull requires a full match, including the metadata hash.
+	VerificationModeFull VerificationMode = "full"
+
+	// VerificationModePartial accepts both full and partial matches.
+	VerificationModePartial VerificationMode = "partial"
+)
+
+// Accepts returns true if the provided match type satisfies the verification mode.
+func (m VerificationMode) Accepts(matchType MatchType) bool {
+	switch matchType {
+	case MatchTypeFull:
+		return true
+	case MatchTypePartial:
+		return m == VerificationModePartial
+	default:
+		return false
+	}
+}
+
+// RegionType describes the type of bytecode region.
+type RegionType string
+
+const (
+	RegionTypeCode                 RegionType = "code"
+	RegionTypeMetadata             RegionType = "metadata"
+	RegionTypeImmutable            RegionType = "immutable"
+	RegionTypeLibrary              RegionType = "library"
+	RegionTypeConstructorArguments RegionType = "constructor_arguments"
+)
+
+// BytecodeRegion describes a region of bytecode. Offsets are expressed in bytes and End is exclusive.
+// Expected is the hex encoded on-chain content and Actual is the hex encoded compiled content of the region.
+type BytecodeRegion struct {
+	Type     RegionType `json:"type"`
+	Start    int        `json:"start"`
+	End      int        `json:"end"`
+	Name     string     `json:"name,omitempty"`
+	Expected string     `json:"expected,omitempty"`
+	Actual   string     `json:"actual,omitempty"`
+}
+
+// BytecodeMatch represents the outcome of matching the on-chain bytecode against the compiled bytecode.
+type BytecodeMatch struct {
+	Type                 MatchType         `json:"type"`
+	Creation             bool              `json:"creation"`
+	Masked               []BytecodeRegion  `json:"masked"`                // Regions ignored while comparing (immutables, libraries, metadata).
+	Mismatches           []BytecodeRegion  `json:"mismatches"`            // Regions which differ between on-chain and compiled bytecode.
+	Immutables           map[string]string `json:"immutables,omitempty"`  // On-chain immutable values keyed by AST id.
+	Libraries            map[string]string `json:"libraries,omitempty"`   // On-chain library addresses keyed by `file:Library`.
+	ConstructorArguments string            `json:"constructor_arguments"` // Hex encoded constructor arguments appended to creation bytecode.
+}
+
+// IsMatch returns true if bytecode matched either fully or partially.
+func (m *BytecodeMatch) IsMatch() bool {
+	return m.Type == MatchTypeFull || m.Type == MatchTypePartial
+}
+
+// GetMismatches returns regions which differ between on-chain and compiled bytecode.
+func (m *BytecodeMatch) GetMismatches() []BytecodeRegion {
+	return m.Mismatches
+}
+
+// placeholderRegex matches the current `__$<hash>$__` library placeholders as well as
+// the `__<name>__` placeholders of compilers prior to 0.5.0.
+var placeholderRegex = regexp.MustCompile(`__\$[0-9a-fA-F]{34}\$__|__[A-Za-z0-9_.:/\-]{36}__`)
+
+// MatchRuntimeBytecode matches on-chain runtime bytecode against the compiled deployed bytecode.
+// Immutable references and library link placeholders are masked before comparison. Full match is
+// attempted first, followed by partial match with the CBOR metadata stripped from both sides.
+func MatchRuntimeBytecode(onchain []byte, compiled *solgo.StandardJsonBytecode) (*BytecodeMatch, error) {
+	compiledBytes, err := decodeCompiledObject(compiled)
+	if err != nil {
+		return nil, err
+	}
+
+	match := &BytecodeMatch{
+		Type:       MatchTypeNone,
+		Immutables: make(map[string]string),
+		Libraries:  make(map[string]string),
+	}
+
+	masked := maskReferences(match, onchain, compiledBytes, compiled)
+
+	if bytes.Equal(masked, compiledBytes) {
+		match.Type = MatchTypeFull
+		return match, nil
+	}
+
+	onchainCode, onchainStart := splitAuxdata(masked)
+	compiledCode, compiledStart := splitAuxdata(compiledBytes)
+
+	if onchainStart >= 0 && compiledStart >= 0 && bytes.Equal(onchainCode, compiledCode) {
+		match.Type = MatchTypePartial
+		match.Masked = append(match.Masked, BytecodeRegion{Type: RegionTypeMetadata, Start: compiledStart, End: len(compiledBytes)})
+		match.Mismatches = append(match.Mismatches, BytecodeRegion{
+			Type:     RegionTypeMetadata,
+			Start:    compiledStart,
+			End:      len(compiledBytes),
+			Expected: hex.EncodeToString(masked[onchainStart:]),
+			Actual:   hex.EncodeToString(compiledBytes[compiledStart:]),
+		})
+		return match, nil
+	}
+
+	match.Mismatches = diffRegions(masked, compiledBytes, compiledStart)
+	return match, nil
+}
+
+// MatchCreationBytecode matches on-chain creation bytecode (transaction input) against the compiled creation
+// bytecode. Everything appended after the compiled bytecode is treated as ABI encoded constructor arguments.
+// Metadata embedded within the creation bytecode is located through the compiled deployed bytecode auxdata.
+func MatchCreationBytecode(onchain []byte, compiled *solgo.StandardJsonBytecode, deployed *solgo.StandardJsonBytecode) (*BytecodeMatch, error) {
+	compiledBytes, err := decodeCompiledObject(compiled)
+	if err != nil {
+		return nil, err
+	}
+
+	match := &BytecodeMatch{
+		Type:       MatchTypeNone,
+		Creation:   true,
+		Immutables: make(map[string]string),
+		Libraries:  make(map[string]string),
+	}
+
+	if len(onchain) < len(compiledBytes) {
+		match.Mismatches = diffRegions(onchain, compiledBytes, -1)
+		return match, nil
+	}
+
+	creation := onchain[:len(compiledBytes)]
+	if len(onchain) > len(compiledBytes) {
+		match.ConstructorArguments = hex.EncodeToString(onchain[len(compiledBytes):])
+		match.Masked = append(match.Masked, BytecodeRegion{
+			Type:  RegionTypeConstructorArguments,
+			Start: len(compiledBytes),
+			End:   len(onchain),
+		})
+	}
+
+	masked := maskReferences(match, creation, compiledBytes, compiled)
+
+	if bytes.Equal(masked, compiledBytes) {
+		match.Type = MatchTypeFull
+		return match, nil
+	}
+
+	// Metadata of the creation bytecode is the auxdata of the runtime code, which is embedded within
+	// the creation bytecode, followed by possible constructor code data.
+	metadataStart, metadataEnd := -1, -1
+	if deployed != nil {
+		if deployedBytes, err := decodeCompiledObject(deployed); err == nil {
+			if _, auxStart := splitAuxdata(deployedBytes); auxStart >= 0 {
+				aux := deployedBytes[auxStart:]
+				if idx := bytes.LastIndex(compiledBytes, aux); idx >= 0 {
+					metadataStart, metadataEnd = idx, idx+len(aux)
+				}
+			}
+		}
+	}
+
+	if metadataStart < 0 {
+		if _, auxStart := splitAuxdata(compiledBytes); auxStart >= 0 {
+			metadataStart, metadataEnd = auxStart, len(compiledBytes)
+		}
+	}
+
+	if metadataStart >= 0 {
+		onchainStripped := append(append([]byte{}, masked[:metadataStart]...), masked[metadataEnd:]...)
+		compiledStripped := append(append([]byte{}, compiledBytes[:metadataStart]...), compiledBytes[metadataEnd:]...)
+
+		if bytes.Equal(onchainStripped, compiledStripped) {
+			match.Type = MatchTypePartial
+			match.Masked = append(match.Masked, BytecodeRegion{Type: RegionTypeMetadata, Start: metadataStart, End: metadataEnd})
+			match.Mismatches = append(match.Mismatches, BytecodeRegion{
+				Type:     RegionTypeMetadata,
+				Start:    metadataStart,
+				End:      metadataEnd,
+				Expected: hex.EncodeToString(masked[metadataStart:metadataEnd]),
+				Actual:   hex.EncodeToString(compiledBytes[metadataStart:metadataEnd]),
+			})
+			return match, nil
+		}
+	}
+
+	match.Mismatches = diffRegions(masked, compiledBytes, metadataStart)
+	return match, nil
+}
+
+// legacyBytecode returns the bytecode of the compiler output without the references, such as the combined json,
+// with the link references located at the library placeholders of the object.
+func legacyBytecode(object string) *solgo.StandardJsonBytecode {
+	object = strings.TrimPrefix(object, "0x")
+	toReturn := &solgo.StandardJsonBytecode{Object: object}
+
+	for _, loc := range placeholderRegex.FindAllStringIndex(object, -1) {
+		if loc[0]%2 != 0 {
+			continue
+		}
+
+		if toReturn.LinkReferences == nil {
+			toReturn.LinkReferences = map[string]map[string][]solgo.StandardJsonReference{"": {}}
+		}
+
+		library := strings.Trim(object[loc[0]:loc[1]], "_$")
+		toReturn.LinkReferences[""][library] = append(toReturn.LinkReferences[""][library], solgo.StandardJsonReference{
+			Start:  loc[0] / 2,
+			Length: (loc[1] - loc[0]) / 2,
+		})
+	}
+
+	return toReturn
+}
+
+// mayDifferInImmutables reports whether all of the code mismatches of the match fall within the 32 bytes regions
+// the compiled bytecode has zeroed, where the immutables are placed when their references are unknown.
+func mayDifferInImmutables(match *BytecodeMatch, compiled *solgo.StandardJsonBytecode) bool {
+	compiledBytes, err := decodeCompiledObject(compiled)
+	if err != nil {
+		return false
+	}
+
+	candidates := 0
+	for _, region := range match.Mismatches {
+		if region.Type != RegionTypeCode {
+			continue
+		}
+
+		if region.End-region.Start > 32 || region.End > len(compiledBytes) {
+			return false
+		}
+
+		for _, b := range compiledBytes[region.Start:region.End] {
+			if b != 0 {
+				return false
+			}
+		}
+		candidates++
+	}
+
+	return candidates > 0
+}
+
+// decodeCompiledObject decodes compiled hex bytecode object replacing library placeholders with zero bytes.
+func decodeCompiledObject(compiled *solgo.StandardJsonBytecode) ([]byte, error) {
+	if compiled == nil || compiled.GetObject() == "" {
+		return nil, fmt.Errorf("compiled bytecode object is empty")
+	}
+
+	object := placeholderRegex.ReplaceAllStringFunc(compiled.GetObject(), func(s string) string {
+		return strings.Repeat("0", len(s))
+	})
+
+	decoded, err := hex.DecodeString(object)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode compiled bytecode: %w", err)
+	}
+
+	return decoded, nil
+}
+
+// maskReferences returns a copy of the on-chain bytecode with immutable and library reference regions zeroed,
+// the same way they are left zeroed in the compiled bytecode. Masked regions and extracted values are recorded
+// on the match.
+func maskReferences(match *BytecodeMatch, onchain []byte, compiled []byte, references *solgo.StandardJsonBytecode) []byte {
+	masked := append([]byte{}, onchain...)
+
+	maskRegion := func(regionType RegionType, name string, ref solgo.StandardJsonReference) (string, bool) {
+		start, end := ref.Start, ref.Start+ref.Length
+		if start < 0 || end > len(masked) || end > len(compiled) {
+			return "", false
+		}
+
+		value := hex.EncodeToString(masked[start:end])
+		copy(masked[start:end], compiled[start:end])
+		match.Masked = append(match.Masked, BytecodeRegion{Type: regionType, Start: start, End: end, Name: name})
+		return value, true
+	}
+
+	for astId, refs := range references.ImmutableReferences {
+		for _, ref := range refs {
+			if value, ok := maskRegion(RegionTypeImmutable, astId, ref); ok {
+				match.Immutables[astId] = value
+			}
+		}
+	}
+
+	for file, libraries := range references.LinkReferences {
+		for library, refs := range libraries {
+			name := fmt.Sprintf("%s:%s", file, library)
+			for _, ref := range refs {
+				if value, ok := maskRegion(RegionTypeLibrary, name, ref); ok {
+					match.Libraries[name] = "0x" + value
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(match.Masked, func(i, j int) bool {
+		return match.Masked[i].Start < match.Masked[j].Start
+	})
+
+	return masked
+}
+
+// splitAuxdata splits the bytecode into the code and the CBOR encoded metadata (auxdata) section.
+// The last two bytes of the bytecode contain the big endian length of the CBOR section. It returns
+// the code without auxdata and the offset at which the auxdata begins or -1 when there is none.
+func splitAuxdata(code []byte) ([]byte, int) {
+	if len(code) < 2 {
+		return code, -1
+	}
+
+	cborLength := int(binary.BigEndian.Uint16(code[len(code)-2:]))
+	start := len(code) - 2 - cborLength
+	if cborLength == 0 || start < 0 {
+		return code, -1
+	}
+
+	// Solidity metadata is always a CBOR map, starting with a major type 5 byte.
+	if code[start]&0xe0 != 0xa0 {
+		return code, -1
+	}
+
+	return code[:start], start
+}
+
+// diffRegions returns the contiguous regions in which provided byte slices differ. Differences within
+// the metadata section starting at metadataStart are reported as a single metadata region.
+func diffRegions(onchain []byte, compiled []byte, metadataStart int) []BytecodeRegion {
+	var regions []BytecodeRegion
+
+	maxLen := len(onchain)
+	if len(compiled) > maxLen {
+		maxLen = len(compiled)
+	}
+
+	at := func(b []byte, i int) (byte, bool) {
+		if i < len(b) {
+			return b[i], true
+		}
+		return 0, false
+	}
+
+	slice := func(b []byte, start, end int) string {
+		if start >= len(b) {
+			return ""
+		}
+		if end > len(b) {
+			end = len(b)
+		}
+		return hex.EncodeToString(b[start:end])
+	}
+
+	start := -1
+	for i := 0; i <= maxLen; i++ {
+		different := false
+		if i < maxLen {
+			a, aOk := at(onchain, i)
+			b, bOk := at(compiled, i)
+			different = aOk != bOk || a != b
+		}
+
+		if different && start < 0 {
+			start = i
+		} else if !different && start >= 0 {
+			regionType := RegionTypeCode
+			if metadataStart >= 0 && start >= metadataStart {
+				regionType = RegionTypeMetadata
+			}
+			regions = append(regions, BytecodeRegion{
+				Type:     regionType,
+				Start:    start,
+				End:      i,
+				Expected: slice(onchain, start, i),
+				Actual:   slice(compiled, start, i),
+			})
+			start = -1
+		}
+	}
+
+	return regions
+}
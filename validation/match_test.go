@@ -0,0 +1,150 @@
+package validation
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unpackdev/solgo"
+)
+
+func TestMatchRuntimeBytecode(t *testing.T) {
+	code := "6080604052348015600f57600080fd5b50"
+	immutable := strings.Repeat("00", 32)
+	auxdata := "a264697066735822" + strings.Repeat("11", 34) + "64736f6c63430008140033"
+	otherAuxdata := "a264697066735822" + strings.Repeat("22", 34) + "64736f6c63430008140033"
+	immutableValue := strings.Repeat("ab", 32)
+	libraryAddress := strings.Repeat("cd", 20)
+	placeholder := "__$" + strings.Repeat("e", 34) + "$__"
+
+	compiled := &solgo.StandardJsonBytecode{
+		Object: code + immutable + placeholder + auxdata,
+		ImmutableReferences: map[string][]solgo.StandardJsonReference{
+			"42": {{Start: len(code) / 2, Length: 32}},
+		},
+		LinkReferences: map[string]map[string][]solgo.StandardJsonReference{
+			"contracts/Lib.sol": {"Lib": {{Start: len(code)/2 + 32, Length: 20}}},
+		},
+	}
+
+	testCases := []struct {
+		name           string
+		onchain        string
+		expected       MatchType
+		mismatchTypes  []RegionType
+		wantImmutables bool
+	}{
+		{
+			name:           "Full match with immutables and libraries",
+			onchain:        code + immutableValue + libraryAddress + auxdata,
+			expected:       MatchTypeFull,
+			wantImmutables: true,
+		},
+		{
+			name:           "Partial match with different metadata",
+			onchain:        code + immutableValue + libraryAddress + otherAuxdata,
+			expected:       MatchTypePartial,
+			mismatchTypes:  []RegionType{RegionTypeMetadata},
+			wantImmutables: true,
+		},
+		{
+			name:          "No match with different code",
+			onchain:       "6080604052348015600f57600080fd5b51" + immutableValue + libraryAddress + auxdata,
+			expected:      MatchTypeNone,
+			mismatchTypes: []RegionType{RegionTypeCode},
+		},
+		{
+			name:          "No match with different code and metadata",
+			onchain:       "6080604052348015600f57600080fd5b51" + immutableValue + libraryAddress + otherAuxdata,
+			expected:      MatchTypeNone,
+			mismatchTypes: []RegionType{RegionTypeCode, RegionTypeMetadata},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			onchain, err := hex.DecodeString(testCase.onchain)
+			require.NoError(t, err)
+
+			match, err := MatchRuntimeBytecode(onchain, compiled)
+			require.NoError(t, err)
+			require.NotNil(t, match)
+			assert.Equal(t, testCase.expected, match.Type)
+			assert.Equal(t, testCase.expected != MatchTypeNone, match.IsMatch())
+
+			var mismatchTypes []RegionType
+			for _, region := range match.GetMismatches() {
+				mismatchTypes = append(mismatchTypes, region.Type)
+			}
+			assert.Equal(t, testCase.mismatchTypes, mismatchTypes)
+
+			if testCase.wantImmutables {
+				assert.Equal(t, immutableValue, match.Immutables["42"])
+				assert.Equal(t, "0x"+libraryAddress, match.Libraries["contracts/Lib.sol:Lib"])
+			}
+		})
+	}
+}
+
+func TestMatchCreationBytecode(t *testing.T) {
+	runtimeCode := "6080604052600080fd"
+	auxdata := "a264697066735822" + strings.Repeat("11", 34) + "64736f6c63430008140033"
+	otherAuxdata := "a264697066735822" + strings.Repeat("22", 34) + "64736f6c63430008140033"
+	constructor := "608060405234801561001057600080fd5b50"
+	arguments := strings.Repeat("00", 31) + "01"
+
+	compiled := &solgo.StandardJsonBytecode{Object: constructor + runtimeCode + auxdata}
+	deployed := &solgo.StandardJsonBytecode{Object: runtimeCode + auxdata}
+
+	testCases := []struct {
+		name         string
+		onchain      string
+		expected     MatchType
+		expectedArgs string
+	}{
+		{
+			name:         "Full match with constructor arguments",
+			onchain:      constructor + runtimeCode + auxdata + arguments,
+			expected:     MatchTypeFull,
+			expectedArgs: arguments,
+		},
+		{
+			name:         "Partial match with constructor arguments",
+			onchain:      constructor + runtimeCode + otherAuxdata + arguments,
+			expected:     MatchTypePartial,
+			expectedArgs: arguments,
+		},
+		{
+			name:     "Full match without constructor arguments",
+			onchain:  constructor + runtimeCode + auxdata,
+			expected: MatchTypeFull,
+		},
+		{
+			name:     "Shorter bytecode",
+			onchain:  constructor,
+			expected: MatchTypeNone,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			onchain, err := hex.DecodeString(testCase.onchain)
+			require.NoError(t, err)
+
+			match, err := MatchCreationBytecode(onchain, compiled, deployed)
+			require.NoError(t, err)
+			assert.True(t, match.Creation)
+			assert.Equal(t, testCase.expected, match.Type)
+			assert.Equal(t, testCase.expectedArgs, match.ConstructorArguments)
+		})
+	}
+}
+
+func TestVerificationMode(t *testing.T) {
+	assert.True(t, VerificationModeFull.Accepts(MatchTypeFull))
+	assert.False(t, VerificationModeFull.Accepts(MatchTypePartial))
+	assert.True(t, VerificationModePartial.Accepts(MatchTypePartial))
+	assert.False(t, VerificationModePartial.Accepts(MatchTypeNone))
+}
@@ -0,0 +1,148 @@
+package validation
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/0x19/solc-switch"
+	"github.com/unpackdev/solgo"
+	"go.uber.org/zap"
+)
+
+// CompileStandardJSON compiles provided solc Standard JSON Input with the requested compiler version
+// and returns typed Standard JSON Output. Unlike solc.Compile, it preserves complete compiler output
+// including link and immutable references, source maps, storage layout and AST.
+func CompileStandardJSON(ctx context.Context, compiler *solc.Solc, version string, input *solgo.StandardJsonInput) (*solgo.StandardJsonOutput, error) {
+	if compiler == nil {
+		return nil, errors.New("compiler must be set")
+	}
+
+	if input == nil {
+		return nil, errors.New("standard json input must be set")
+	}
+
+	source, err := input.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := runStandardJSON(ctx, compiler, version, source)
+	if err != nil {
+		return nil, err
+	}
+
+	return solgo.NewStandardJsonOutputFromBytes(output)
+}
+
+// runStandardJSON runs the requested compiler version with the Standard JSON Input and returns its raw output.
+func runStandardJSON(ctx context.Context, compiler *solc.Solc, version string, source []byte) ([]byte, error) {
+	binaryPath, err := compiler.GetBinary(version)
+	if err != nil {
+		return nil, err
+	}
+
+	// #nosec G204
+	// Binary path is resolved by the solc-switch from the local releases and arguments are static.
+	cmd := exec.CommandContext(ctx, binaryPath, "--standard-json")
+	cmd.Stdin = bytes.NewReader(source)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		zap.L().Error(
+			"failed to compile standard json input",
+			zap.String("version", version),
+			zap.String("stderr", stderr.String()),
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("failed to compile standard json input: %s", strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// CompileStandardJSON compiles the verifier sources, exported as Standard JSON Input, using the
+// requested compiler version. If input is nil, it is built from the verifier sources and their settings.
+// Output is cached by the compiler version and the input when the verifier has the cache set.
+func (v *Verifier) CompileStandardJSON(ctx context.Context, version string, input *solgo.StandardJsonInput) (*solgo.StandardJsonOutput, error) {
+	if input == nil {
+		sourcesInput, err := v.sources.ToStandardJSON()
+		if err != nil {
+			return nil, err
+		}
+		input = sourcesInput
+	}
+
+	source, err := input.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	return v.compileStandardJSON(ctx, version, source)
+}
+
+// VerifyFromStandardJSON verifies the provided deployed bytecode against the entry contract
+// found within the Standard JSON Output. Entry contract is resolved from the verifier sources.
+func (v *Verifier) VerifyFromStandardJSON(bytecode []byte, output *solgo.StandardJsonOutput) (*VerifyResult, error) {
+	if output == nil {
+		return nil, errors.New("standard json output must be set")
+	}
+
+	if output.HasErrors() {
+		return nil, fmt.Errorf("compilation failed with errors: %v", output.GetErrors())
+	}
+
+	contract, _ := output.GetContract(v.sources.EntrySourceUnitName)
+	if contract == nil {
+		return nil, fmt.Errorf("entry contract %s not found in standard json output", v.sources.EntrySourceUnitName)
+	}
+
+	result := compilerResultFromStandardJson(v.sources.EntrySourceUnitName, contract)
+	return v.verifyRuntime(bytecode, result, contract.GetDeployedBytecode(), contract)
+}
+
+// VerifyCreationFromStandardJSON verifies the provided creation bytecode (contract creation transaction input)
+// against the entry contract found within the Standard JSON Output. Constructor arguments appended to the
+// creation bytecode are extracted and recorded on the match.
+func (v *Verifier) VerifyCreationFromStandardJSON(bytecode []byte, output *solgo.StandardJsonOutput) (*VerifyResult, error) {
+	if output == nil {
+		return nil, errors.New("standard json output must be set")
+	}
+
+	if output.HasErrors() {
+		return nil, fmt.Errorf("compilation failed with errors: %v", output.GetErrors())
+	}
+
+	contract, _ := output.GetContract(v.sources.EntrySourceUnitName)
+	if contract == nil {
+		return nil, fmt.Errorf("entry contract %s not found in standard json output", v.sources.EntrySourceUnitName)
+	}
+
+	match, err := MatchCreationBytecode(bytecode, contract.GetBytecode(), contract.GetDeployedBytecode())
+	if err != nil {
+		return nil, err
+	}
+
+	result := compilerResultFromStandardJson(v.sources.EntrySourceUnitName, contract)
+	return v.buildVerifyResult(bytecode, contract.GetBytecode().GetObject(), result, contract, match)
+}
+
+// compilerResultFromStandardJson converts the Standard JSON contract output into the solc compiler
+// result so that existing consumers of VerifyResult keep working.
+func compilerResultFromStandardJson(name string, contract *solgo.StandardJsonContract) *solc.CompilerResult {
+	return &solc.CompilerResult{
+		IsEntryContract:  true,
+		ContractName:     name,
+		Bytecode:         contract.GetBytecode().GetObject(),
+		DeployedBytecode: contract.GetDeployedBytecode().GetObject(),
+		ABI:              string(contract.Abi),
+		Opcodes:          contract.GetBytecode().Opcodes,
+		Metadata:         contract.Metadata,
+	}
+}
@@ -2,6 +2,8 @@ package validation
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/binary"
 	"encoding/hex"
 	"errors"
 	"fmt"
@@ -10,6 +12,7 @@ import (
 	"github.com/0x19/solc-switch"
 	"github.com/sergi/go-diff/diffmatchpatch"
 	"github.com/unpackdev/solgo"
+	"github.com/unpackdev/solgo/cache"
 	"github.com/unpackdev/solgo/utils"
 	"go.uber.org/zap"
 )
@@ -17,9 +20,11 @@ import (
 // Verifier is a utility that facilitates the verification of Ethereum smart contracts.
 // It uses the solc compiler to compile the provided sources and then verifies the bytecode.
 type Verifier struct {
-	ctx     context.Context // The context for the verifier operations.
-	solc    *solc.Solc      // The solc compiler instance.
-	sources *solgo.Sources  // The sources of the Ethereum smart contracts to be verified.
+	ctx     context.Context  // The context for the verifier operations.
+	solc    *solc.Solc       // The solc compiler instance.
+	sources *solgo.Sources   // The sources of the Ethereum smart contracts to be verified.
+	mode    VerificationMode // The minimum match type required for bytecode to be considered verified.
+	cache   *cache.Namespace // The cache of the compilation results, nil when not set.
 }
 
 // NewVerifier creates a new instance of Verifier.
@@ -46,9 +51,26 @@ func NewVerifier(ctx context.Context, compiler *solc.Solc, sources *solgo.Source
 		ctx:     ctx,
 		solc:    compiler,
 		sources: sources,
+		mode:    VerificationModePartial,
 	}, nil
 }
 
+// SetMode sets the verification mode, that is the minimum match type required for bytecode to be considered verified.
+func (v *Verifier) SetMode(mode VerificationMode) {
+	v.mode = mode
+}
+
+// SetCache sets the cache the compilation results are kept in. Compilation is deterministic, so the results
+// are cached by the compiler version, configuration and sources without expiration.
+func (v *Verifier) SetCache(store cache.Cache) {
+	v.cache = cache.NewNamespace(store, "solc", nil)
+}
+
+// GetMode returns the verification mode associated with the verifier.
+func (v *Verifier) GetMode() VerificationMode {
+	return v.mode
+}
+
 // GetContext returns the context associated with the verifier.
 func (v *Verifier) GetContext() context.Context {
 	return v.ctx
@@ -64,20 +86,75 @@ func (v *Verifier) GetCompiler() *solc.Solc {
 	return v.solc
 }
 
+// Compile compiles the JSON configuration of the compiler config, returning the cached results when the same
+// configuration was compiled before.
 func (v *Verifier) Compile(ctx context.Context, config *solc.CompilerConfig) (*solc.CompilerResults, error) {
 	source, err := config.GetJsonConfig().ToJSON()
 	if err != nil {
 		return nil, err
 	}
 
-	results, err := v.solc.Compile(ctx, string(source), config)
+	return v.compile(ctx, string(source), config)
+}
+
+// compile compiles the source with the compiler config, through the cache when the verifier has it set.
+func (v *Verifier) compile(ctx context.Context, source string, config *solc.CompilerConfig) (*solc.CompilerResults, error) {
+	cacheKey := compileCacheKey(
+		"results",
+		config.GetCompilerVersion(),
+		config.GetEntrySourceName(),
+		strings.Join(config.GetArguments(), " "),
+		source,
+	)
+
+	var cached solc.CompilerResults
+	if err := v.cache.GetJSON(ctx, cacheKey, &cached); err == nil {
+		return &cached, nil
+	}
+
+	results, err := v.solc.Compile(ctx, source, config)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := v.cache.SetJSON(ctx, cacheKey, results, cache.Finalized); err != nil {
+		zap.L().Warn("failed to cache compiler results", zap.String("version", config.GetCompilerVersion()), zap.Error(err))
+	}
+
 	return results, nil
 }
 
+// compileStandardJSON compiles the standard json input with the compiler version, through the cache when the verifier
+// has it set.
+func (v *Verifier) compileStandardJSON(ctx context.Context, version string, source []byte) (*solgo.StandardJsonOutput, error) {
+	cacheKey := compileCacheKey("standard_json", version, string(source))
+	if cached, err := v.cache.Get(ctx, cacheKey); err == nil {
+		return solgo.NewStandardJsonOutputFromBytes(cached)
+	}
+
+	output, err := runStandardJSON(ctx, v.solc, version, source)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := v.cache.Set(ctx, cacheKey, output, cache.Finalized); err != nil {
+		zap.L().Warn("failed to cache standard json output", zap.String("version", version), zap.Error(err))
+	}
+
+	return solgo.NewStandardJsonOutputFromBytes(output)
+}
+
+// compileCacheKey returns the cache key of the compilation, hashing the compiler inputs.
+func compileCacheKey(kind string, inputs ...string) string {
+	hash := sha256.New()
+	for _, input := range inputs {
+		// Lengths are hashed as well, so the inputs cannot shift from one into another.
+		_ = binary.Write(hash, binary.BigEndian, uint64(len(input)))
+		hash.Write([]byte(input))
+	}
+	return cache.Key(kind, hex.EncodeToString(hash.Sum(nil)))
+}
+
 // VerifyFromResults compiles the sources using the solc compiler and then verifies the bytecode.
 // If the bytecode does not match the compiled result, it returns a diff of the two.
 // Returns true if the bytecode matches, otherwise returns false.
@@ -93,89 +170,42 @@ func (v *Verifier) VerifyFromResults(bytecode []byte, results *solc.CompilerResu
 		return nil, errors.New("no appropriate compilation results found (compiled but missing entry contract)")
 	}
 
-	encoded := hex.EncodeToString(bytecode)
-	if !strings.Contains(result.GetDeployedBytecode(), encoded) {
-		dmp := diffmatchpatch.New()
-		diffs := dmp.DiffMain(encoded, result.GetDeployedBytecode(), false)
-		toReturn := &VerifyResult{
-			Verified:            false,
-			CompilerResult:      result,
-			ExpectedBytecode:    encoded,
-			Diffs:               diffs,
-			DiffPretty:          dmp.DiffPrettyText(diffs),
-			LevenshteinDistance: dmp.DiffLevenshtein(diffs),
-		}
-
-		return toReturn, errors.New("bytecode missmatch, failed to verify")
-	}
-
-	toReturn := &VerifyResult{
-		Verified:         true,
-		ExpectedBytecode: encoded,
-		CompilerResult:   result,
-		Diffs:            make([]diffmatchpatch.Diff, 0),
-	}
-
-	return toReturn, nil
+	return v.verifyLegacy(bytecode, result)
 }
 
 // Verify compiles the sources using the solc compiler and then verifies the bytecode.
 // If the bytecode does not match the compiled result, it returns a diff of the two.
 // Returns true if the bytecode matches, otherwise returns false.
 // Also returns an error if there's any issue in the compilation or verification process.
+// Configs with the JSON config are compiled into the complete standard json output, so the immutables and
+// libraries are masked with the references of the compiler.
 func (v *Verifier) Verify(ctx context.Context, bytecode []byte, config *solc.CompilerConfig) (*VerifyResult, error) {
-	var source string
-
 	if config.GetJsonConfig() != nil {
-		sourceBytes, err := config.GetJsonConfig().ToJSON()
+		source, err := config.GetJsonConfig().ToJSON()
 		if err != nil {
 			return nil, err
 		}
-		source = string(sourceBytes)
-	} else {
-		source = utils.StripExtraSPDXLines(utils.SimplifyImportPaths(
-			v.GetSources().GetCombinedSource(),
-		))
+
+		output, err := v.compileStandardJSON(ctx, config.GetCompilerVersion(), source)
+		if err != nil {
+			return nil, err
+		}
+
+		return v.VerifyFromStandardJSON(bytecode, output)
 	}
 
-	results, err := v.solc.Compile(ctx, source, config)
+	source := utils.StripExtraSPDXLines(utils.SimplifyImportPaths(
+		v.GetSources().GetCombinedSource(),
+	))
+
+	results, err := v.compile(ctx, source, config)
 	if err != nil {
 		return nil, err
 	}
 
 	for _, result := range results.GetResults() {
 		if result.IsEntry() {
-			encoded := hex.EncodeToString(bytecode)
-			var retBytecode string
-			if result.GetDeployedBytecode() == "" {
-				retBytecode = result.GetBytecode()
-			} else {
-				retBytecode = result.GetDeployedBytecode()
-			}
-
-			if encoded != retBytecode {
-				dmp := diffmatchpatch.New()
-				diffs := dmp.DiffMain(encoded, retBytecode, false)
-				toReturn := &VerifyResult{
-					Verified:            false,
-					CompilerResult:      result,
-					ExpectedBytecode:    encoded,
-					Diffs:               diffs,
-					DiffPretty:          dmp.DiffPrettyText(diffs),
-					LevenshteinDistance: dmp.DiffLevenshtein(diffs),
-				}
-
-				return toReturn, errors.New("bytecode missmatch, failed to verify")
-			}
-
-			toReturn := &VerifyResult{
-				Verified:         true,
-				ExpectedBytecode: encoded,
-				CompilerResult:   result,
-				Diffs:            make([]diffmatchpatch.Diff, 0),
-			}
-
-			return toReturn, nil
+			return v.verifyLegacy(bytecode, result)
 		}
 	}
 
@@ -188,14 +218,98 @@ func (v *Verifier) Verify(ctx context.Context, bytecode []byte, config *solc.Com
 	return nil, fmt.Errorf("compilation did not contain entry contract results")
 }
 
+// verifyLegacy verifies the bytecode against the compiler result, which carries no references. Libraries are masked
+// at their placeholders, while the immutables cannot be located, so the mismatches confined to the zeroed regions
+// the immutables are placed in are reported with ErrImmutableReferencesUnavailable. Results without the deployed
+// bytecode are matched as the creation bytecode.
+func (v *Verifier) verifyLegacy(bytecode []byte, result *solc.CompilerResult) (*VerifyResult, error) {
+	var match *BytecodeMatch
+	var compiled *solgo.StandardJsonBytecode
+	var err error
+
+	switch {
+	case result.GetDeployedBytecode() != "":
+		compiled = legacyBytecode(result.GetDeployedBytecode())
+		match, err = MatchRuntimeBytecode(bytecode, compiled)
+	case result.GetBytecode() != "":
+		compiled = legacyBytecode(result.GetBytecode())
+		match, err = MatchCreationBytecode(bytecode, compiled, nil)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrDeployedBytecodeMissing, result.GetContractName())
+	}
+
+	if err != nil {
+		// Compiled bytecode cannot be decoded, which means it cannot match.
+		match = &BytecodeMatch{Type: MatchTypeNone}
+	}
+
+	toReturn, err := v.buildVerifyResult(bytecode, compiled.GetObject(), result, nil, match)
+	if err != nil && mayDifferInImmutables(match, compiled) {
+		return toReturn, fmt.Errorf("%w: %w, verify through the standard json to mask the immutables", err, ErrImmutableReferencesUnavailable)
+	}
+
+	return toReturn, err
+}
+
+// verifyRuntime matches provided on-chain runtime bytecode against the compiled bytecode and builds the
+// verification result. Bytecode is considered verified when the match type satisfies the verifier mode.
+func (v *Verifier) verifyRuntime(bytecode []byte, result *solc.CompilerResult, compiled *solgo.StandardJsonBytecode, contract *solgo.StandardJsonContract) (*VerifyResult, error) {
+	match, err := MatchRuntimeBytecode(bytecode, compiled)
+	if err != nil {
+		// Compiled bytecode is missing or cannot be decoded, which means it cannot match.
+		match = &BytecodeMatch{Type: MatchTypeNone}
+	}
+
+	return v.buildVerifyResult(bytecode, compiled.GetObject(), result, contract, match)
+}
+
+// buildVerifyResult builds the verification result out of the bytecode match.
+// Diffs are calculated only when bytecode failed to verify.
+func (v *Verifier) buildVerifyResult(bytecode []byte, compiled string, result *solc.CompilerResult, contract *solgo.StandardJsonContract, match *BytecodeMatch) (*VerifyResult, error) {
+	encoded := hex.EncodeToString(bytecode)
+
+	if !v.mode.Accepts(match.Type) {
+		dmp := diffmatchpatch.New()
+		diffs := dmp.DiffMain(encoded, compiled, false)
+		toReturn := &VerifyResult{
+			Verified:            false,
+			MatchType:           match.Type,
+			Match:               match,
+			CompilerResult:      result,
+			StandardOutput:      contract,
+			ExpectedBytecode:    encoded,
+			Diffs:               diffs,
+			DiffPretty:          dmp.DiffPrettyText(diffs),
+			LevenshteinDistance: dmp.DiffLevenshtein(diffs),
+		}
+
+		return toReturn, errors.New("bytecode missmatch, failed to verify")
+	}
+
+	toReturn := &VerifyResult{
+		Verified:         true,
+		MatchType:        match.Type,
+		Match:            match,
+		ExpectedBytecode: encoded,
+		CompilerResult:   result,
+		StandardOutput:   contract,
+		Diffs:            make([]diffmatchpatch.Diff, 0),
+	}
+
+	return toReturn, nil
+}
+
 // VerifyResult represents the result of the verification process.
 type VerifyResult struct {
-	Verified            bool                  `json:"verified"`             // Whether the verification was successful or not.
-	CompilerResult      *solc.CompilerResult  `json:"compiler_results"`     // The results from the solc compiler.
-	ExpectedBytecode    string                `json:"expected_bytecode"`    // The expected bytecode.
-	Diffs               []diffmatchpatch.Diff `json:"diffs"`                // The diffs between the provided bytecode and the compiled bytecode.
-	DiffPretty          string                `json:"diffs_pretty"`         // The pretty printed diff between the provided bytecode and the compiled bytecode.
-	LevenshteinDistance int                   `json:"levenshtein_distance"` // The levenshtein distance between the provided bytecode and the compiled bytecode.
+	Verified            bool                        `json:"verified"`                  // Whether the verification was successful or not.
+	MatchType           MatchType                   `json:"match_type"`                // The match type (full, partial or none) between provided and compiled bytecode.
+	Match               *BytecodeMatch              `json:"match"`                     // The match details, including masked and mismatched bytecode regions.
+	CompilerResult      *solc.CompilerResult        `json:"compiler_results"`          // The results from the solc compiler.
+	StandardOutput      *solgo.StandardJsonContract `json:"standard_output,omitempty"` // The standard json output of the entry contract, if compiled through standard json.
+	ExpectedBytecode    string                      `json:"expected_bytecode"`         // The expected bytecode.
+	Diffs               []diffmatchpatch.Diff       `json:"diffs"`                     // The diffs between the provided bytecode and the compiled bytecode.
+	DiffPretty          string                      `json:"diffs_pretty"`              // The pretty printed diff between the provided bytecode and the compiled bytecode.
+	LevenshteinDistance int                         `json:"levenshtein_distance"`      // The levenshtein distance between the provided bytecode and the compiled bytecode.
 }
 
 // IsVerified returns whether the verification was successful or not.
@@ -203,11 +317,26 @@ func (vr *VerifyResult) IsVerified() bool {
 	return vr.Verified
 }
 
+// GetMatchType returns the match type between provided and compiled bytecode.
+func (vr *VerifyResult) GetMatchType() MatchType {
+	return vr.MatchType
+}
+
+// GetMatch returns the match details, including masked and mismatched bytecode regions.
+func (vr *VerifyResult) GetMatch() *BytecodeMatch {
+	return vr.Match
+}
+
 // GetCompilerResults returns the results from the solc compiler.
 func (vr *VerifyResult) GetCompilerResult() *solc.CompilerResult {
 	return vr.CompilerResult
 }
 
+// GetStandardOutput returns the standard json output of the entry contract, if available.
+func (vr *VerifyResult) GetStandardOutput() *solgo.StandardJsonContract {
+	return vr.StandardOutput
+}
+
 // GetExpectedBytecode returns the expected bytecode.
 func (vr *VerifyResult) GetExpectedBytecode() string {
 	return vr.ExpectedBytecode
@@ -3,14 +3,17 @@ package validation
 import (
 	"context"
 	"encoding/hex"
+	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/0x19/solc-switch"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/unpackdev/solgo"
+	"github.com/unpackdev/solgo/cache"
 	"github.com/unpackdev/solgo/tests"
 	"github.com/unpackdev/solgo/utils"
 	"go.uber.org/zap"
@@ -392,3 +395,184 @@ func TestVerifier(t *testing.T) {
 		})
 	}
 }
+
+func TestVerifierCompileCache(t *testing.T) {
+	ctx := context.Background()
+
+	compilerConfig, err := solc.NewDefaultCompilerConfig("0.8.0")
+	require.NoError(t, err)
+
+	// Compiler is never run, the results are served from the cache.
+	verifier := &Verifier{ctx: ctx, solc: &solc.Solc{}}
+	verifier.SetCache(cache.NewLRUCache(10))
+
+	source := "contract Contract {}"
+	expected := &solc.CompilerResults{
+		Results: []*solc.CompilerResult{{IsEntryContract: true, ContractName: "Contract", DeployedBytecode: "6080"}},
+	}
+
+	cacheKey := compileCacheKey(
+		"results",
+		compilerConfig.GetCompilerVersion(),
+		compilerConfig.GetEntrySourceName(),
+		strings.Join(compilerConfig.GetArguments(), " "),
+		source,
+	)
+	require.NoError(t, verifier.cache.SetJSON(ctx, cacheKey, expected, cache.Finalized))
+
+	results, err := verifier.compile(ctx, source, compilerConfig)
+	require.NoError(t, err)
+	assert.Equal(t, expected, results)
+
+	assert.NotEqual(t, cacheKey, compileCacheKey("results", compilerConfig.GetCompilerVersion(), "", "", source))
+	assert.NotEqual(t, compileCacheKey("results", "ab", "c"), compileCacheKey("results", "a", "bc"))
+}
+
+func TestVerifierReferences(t *testing.T) {
+	ctx := context.Background()
+
+	// PUSH32 of the immutable followed by PUSH20 of the library, as compiled and as deployed.
+	immutable := strings.Repeat("00", 32)
+	immutableValue := strings.Repeat("00", 30) + "abcd"
+	placeholder := "__$" + strings.Repeat("1a", 17) + "$__"
+	library := "1000000000000000000000000000000000000001"
+	metadata := "a264697066735822" + strings.Repeat("11", 34) + "64736f6c63430008140033"
+
+	compiledImmutable := "60806040527f" + immutable + "50" + metadata
+	deployedImmutable := "60806040527f" + immutableValue + "50" + metadata
+	compiledLibrary := "608060405273" + placeholder + "50" + metadata
+	deployedLibrary := "608060405273" + library + "50" + metadata
+
+	compilerConfig, err := solc.NewDefaultCompilerConfig("0.8.20")
+	require.NoError(t, err)
+
+	jsonConfig := &solc.CompilerJsonConfig{
+		Language: "Solidity",
+		Sources: map[string]solc.Source{
+			"Contract.sol": {Content: "contract Contract {}"},
+		},
+	}
+	compilerConfig.SetJsonConfig(jsonConfig)
+
+	source, err := jsonConfig.ToJSON()
+	require.NoError(t, err)
+
+	output := &solgo.StandardJsonOutput{
+		Contracts: map[string]map[string]*solgo.StandardJsonContract{
+			"Contract.sol": {
+				"Contract": {
+					Evm: solgo.StandardJsonEvm{
+						DeployedBytecode: solgo.StandardJsonBytecode{
+							Object: compiledImmutable,
+							ImmutableReferences: map[string][]solgo.StandardJsonReference{
+								"5": {{Start: 6, Length: 32}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	testCases := []struct {
+		name              string
+		bytecode          string
+		results           *solc.CompilerResults
+		expectedErr       error
+		expectedMatch     MatchType
+		expectedImmutable string
+		expectedLibrary   string
+	}{
+		{
+			name:              "Standard Json Immutables",
+			bytecode:          deployedImmutable,
+			expectedMatch:     MatchTypeFull,
+			expectedImmutable: immutableValue,
+		},
+		{
+			name:     "Legacy Immutables",
+			bytecode: deployedImmutable,
+			results: &solc.CompilerResults{
+				Results: []*solc.CompilerResult{{IsEntryContract: true, ContractName: "Contract", DeployedBytecode: compiledImmutable}},
+			},
+			expectedErr:   ErrImmutableReferencesUnavailable,
+			expectedMatch: MatchTypeNone,
+		},
+		{
+			name:     "Legacy Libraries",
+			bytecode: deployedLibrary,
+			results: &solc.CompilerResults{
+				Results: []*solc.CompilerResult{{IsEntryContract: true, ContractName: "Contract", DeployedBytecode: compiledLibrary}},
+			},
+			expectedMatch:   MatchTypeFull,
+			expectedLibrary: "0x" + library,
+		},
+		{
+			name:     "Legacy Creation Bytecode",
+			bytecode: compiledLibrary[:12] + library + "50" + metadata + strings.Repeat("00", 31) + "01",
+			results: &solc.CompilerResults{
+				Results: []*solc.CompilerResult{{IsEntryContract: true, ContractName: "Contract", Bytecode: compiledLibrary}},
+			},
+			expectedMatch:   MatchTypeFull,
+			expectedLibrary: "0x" + library,
+		},
+		{
+			name:     "Legacy Without Bytecode",
+			bytecode: deployedLibrary,
+			results: &solc.CompilerResults{
+				Results: []*solc.CompilerResult{{IsEntryContract: true, ContractName: "Contract"}},
+			},
+			expectedErr: ErrDeployedBytecodeMissing,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Compiler is never run, the output is served from the cache.
+			verifier := &Verifier{
+				ctx:     ctx,
+				solc:    &solc.Solc{},
+				sources: &solgo.Sources{EntrySourceUnitName: "Contract"},
+				mode:    VerificationModeFull,
+			}
+			verifier.SetCache(cache.NewLRUCache(10))
+
+			data, err := json.Marshal(output)
+			require.NoError(t, err)
+			require.NoError(t, verifier.cache.Set(ctx, compileCacheKey("standard_json", "0.8.20", string(source)), data, cache.Finalized))
+
+			bytecode, err := hex.DecodeString(tc.bytecode)
+			require.NoError(t, err)
+
+			var result *VerifyResult
+			if tc.results != nil {
+				result, err = verifier.VerifyFromResults(bytecode, tc.results)
+			} else {
+				result, err = verifier.Verify(ctx, bytecode, compilerConfig)
+			}
+
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				if result != nil {
+					assert.False(t, result.IsVerified())
+					assert.Equal(t, tc.expectedMatch, result.GetMatchType())
+				}
+				return
+			}
+
+			require.NoError(t, err)
+			assert.True(t, result.IsVerified())
+			assert.Equal(t, tc.expectedMatch, result.GetMatchType())
+
+			if tc.expectedImmutable != "" {
+				assert.Equal(t, map[string]string{"5": tc.expectedImmutable}, result.GetMatch().Immutables)
+			}
+			if tc.expectedLibrary != "" {
+				assert.Len(t, result.GetMatch().Libraries, 1)
+				for _, address := range result.GetMatch().Libraries {
+					assert.Equal(t, tc.expectedLibrary, address)
+				}
+			}
+		})
+	}
+}
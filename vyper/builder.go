@@ -0,0 +1,136 @@
+package vyper
+
+import (
+	"context"
+	"errors"
+
+	ast_pb "github.com/unpackdev/protos/dist/go/ast"
+	"github.com/unpackdev/solgo"
+	"github.com/unpackdev/solgo/ir"
+)
+
+// Builder facilitates parsing of Vyper sources and their lowering into the IR.
+type Builder struct {
+	ctx     context.Context // Context for the builder operations.
+	sources *solgo.Sources  // Vyper source files to be processed.
+	modules []*Module       // Parsed Vyper modules, one per source unit.
+	parser  *ir.Builder     // IR builder holding the lowered IR root.
+}
+
+// NewBuilderFromSources creates a new Vyper builder from the given sources. Every source unit is
+// treated as a single Vyper module.
+func NewBuilderFromSources(ctx context.Context, sources *solgo.Sources) (*Builder, error) {
+	if sources == nil || !sources.HasUnits() {
+		return nil, errors.New("sources needed to initialize vyper builder")
+	}
+
+	return &Builder{
+		ctx:     ctx,
+		sources: sources,
+		modules: make([]*Module, 0),
+	}, nil
+}
+
+// GetContext returns the context associated with the Builder.
+func (b *Builder) GetContext() context.Context {
+	return b.ctx
+}
+
+// GetSources returns the source files being processed.
+func (b *Builder) GetSources() *solgo.Sources {
+	return b.sources
+}
+
+// GetModules returns the parsed Vyper modules.
+func (b *Builder) GetModules() []*Module {
+	return b.modules
+}
+
+// GetModule returns the parsed Vyper module with the provided name or nil if it does not exist.
+func (b *Builder) GetModule(name string) *Module {
+	for _, module := range b.modules {
+		if module.GetName() == name {
+			return module
+		}
+	}
+
+	return nil
+}
+
+// GetParser returns the IR builder. It is available once Build completes and can be used to
+// initialize the ABI builder through abi.NewBuilderFromIR.
+func (b *Builder) GetParser() *ir.Builder {
+	return b.parser
+}
+
+// GetRoot returns the root of the lowered IR.
+func (b *Builder) GetRoot() *ir.RootSourceUnit {
+	if b.parser == nil {
+		return nil
+	}
+
+	return b.parser.GetRoot()
+}
+
+// Parse parses all of the source units into Vyper modules and returns any encountered syntax errors.
+func (b *Builder) Parse() (errs []error) {
+	b.modules = make([]*Module, 0, len(b.sources.GetUnits()))
+
+	for _, unit := range b.sources.GetUnits() {
+		module, syntaxErrs := Parse(unit.GetName(), unit.GetPath(), unit.GetContent())
+		errs = append(errs, syntaxErrs...)
+
+		if module != nil {
+			b.modules = append(b.modules, module)
+		}
+	}
+
+	return errs
+}
+
+// Build lowers the parsed Vyper modules into the IR and discovers the contract standards.
+func (b *Builder) Build() error {
+	if len(b.modules) == 0 {
+		return errors.New("no parsed vyper modules to build, parse sources first")
+	}
+
+	root := &ir.RootSourceUnit{
+		NodeType:       ast_pb.NodeType_ROOT_SOURCE_UNIT,
+		ContractsCount: int32(len(b.modules)),
+		ContractTypes:  make([]string, 0),
+		Standards:      make([]*ir.Standard, 0),
+		Contracts:      make([]*ir.Contract, 0, len(b.modules)),
+		Links:          make([]*ir.Link, 0),
+	}
+
+	lowerer := &lowerer{}
+	for _, module := range b.modules {
+		contract, err := lowerer.lowerModule(module)
+		if err != nil {
+			return err
+		}
+
+		root.Contracts = append(root.Contracts, contract)
+
+		if module.GetName() == b.sources.EntrySourceUnitName {
+			root.EntryContractId = contract.GetId()
+			root.EntryContractName = contract.GetName()
+		}
+	}
+
+	// In case entry is not explicitly set, the last module is the entry one, same as with
+	// the Solidity sources where the entry contract is placed last.
+	if root.EntryContractName == "" {
+		entry := root.Contracts[len(root.Contracts)-1]
+		root.EntryContractId = entry.GetId()
+		root.EntryContractName = entry.GetName()
+	}
+
+	parser, err := ir.NewBuilderFromRoot(b.ctx, b.sources, root)
+	if err != nil {
+		return err
+	}
+
+	b.parser = parser
+	return nil
+}
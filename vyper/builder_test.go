@@ -0,0 +1,93 @@
+package vyper
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unpackdev/solgo"
+	"github.com/unpackdev/solgo/abi"
+	"github.com/unpackdev/solgo/ir"
+	"github.com/unpackdev/solgo/standards"
+)
+
+func TestBuilderFromSources(t *testing.T) {
+	path := filepath.Join("..", "data", "tests", "vyper", "ERC20.vy")
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	sources := &solgo.Sources{
+		SourceUnits: []*solgo.SourceUnit{
+			{
+				Name:    "ERC20",
+				Path:    path,
+				Content: string(content),
+			},
+		},
+		EntrySourceUnitName: "ERC20",
+	}
+
+	builder, err := NewBuilderFromSources(context.TODO(), sources)
+	require.NoError(t, err)
+	assert.Empty(t, builder.Parse())
+	require.NoError(t, builder.Build())
+
+	root := builder.GetRoot()
+	require.NotNil(t, root)
+	assert.Equal(t, "ERC20", root.GetEntryName())
+
+	contract := root.GetEntryContract()
+	require.NotNil(t, contract)
+	assert.Equal(t, ir.LanguageVyper, contract.GetLanguage())
+	assert.Equal(t, "MIT", contract.GetLicense())
+	assert.Equal(t, 9, len(contract.GetStateVariables()))
+	assert.Equal(t, 2, len(contract.GetEvents()))
+	assert.Equal(t, 1, len(contract.GetStructs()))
+	assert.NotNil(t, contract.GetConstructor())
+	assert.NotNil(t, contract.GetFallback())
+	assert.Equal(t, 6, len(contract.GetFunctions()))
+
+	for _, fn := range contract.GetFunctions() {
+		if fn.GetName() == "transfer" {
+			assert.Equal(t, "a9059cbb", fn.GetSignature())
+		}
+	}
+
+	assert.Equal(t, "ddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef", contract.GetEvents()[0].GetSignature().Hex()[2:])
+
+	// Standards are discovered out of the functions and public state variable getters.
+	assert.True(t, root.HasHighConfidenceStandard(standards.ERC20) || root.HasPerfectConfidenceStandard(standards.ERC20))
+	assert.True(t, root.HasContractType("token"))
+
+	abiBuilder, err := abi.NewBuilderFromIR(context.TODO(), builder.GetParser())
+	require.NoError(t, err)
+	assert.Empty(t, abiBuilder.Parse())
+	require.NoError(t, abiBuilder.Build())
+
+	abiContract := abiBuilder.GetEntryContract()
+	require.NotNil(t, abiContract)
+
+	etherAbi, err := abiBuilder.ToABI(abiContract)
+	require.NoError(t, err)
+
+	for _, method := range []string{"name", "symbol", "decimals", "balanceOf", "allowance", "totalSupply", "transfer", "transferFrom", "approve", "getCheckpoint", "mint"} {
+		assert.Contains(t, etherAbi.Methods, method)
+	}
+
+	// Internal functions and non public state variables are not part of the ABI.
+	assert.NotContains(t, etherAbi.Methods, "_mint")
+	assert.NotContains(t, etherAbi.Methods, "minter")
+	assert.NotContains(t, etherAbi.Methods, "checkpoints")
+
+	assert.Equal(t, "0xa9059cbb", hexutil.Encode(etherAbi.Methods["transfer"].ID))
+	assert.Equal(t, 2, len(etherAbi.Methods["allowance"].Inputs))
+	assert.Equal(t, 2, len(etherAbi.Methods["getCheckpoint"].Outputs[0].Type.TupleElems))
+	assert.Equal(t, 4, len(etherAbi.Constructor.Inputs))
+	assert.True(t, etherAbi.HasFallback())
+	assert.Contains(t, etherAbi.Events, "Transfer")
+	assert.True(t, etherAbi.Events["Transfer"].Inputs[0].Indexed)
+}
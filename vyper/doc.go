@@ -0,0 +1,5 @@
+// Package vyper provides a Vyper frontend for solgo. It parses Vyper modules into a lightweight syntax tree
+// covering declarations (interfaces, events, structs, flags, state variables and functions) and lowers them
+// into the same IR used for Solidity contracts, so that ABI generation and standards detection work for
+// Vyper contracts as well.
+package vyper
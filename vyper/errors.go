@@ -0,0 +1,24 @@
+package vyper
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrEmptySource = errors.New("vyper source is not set or empty source provided")
+
+	errUnterminatedString = errors.New("unterminated string literal")
+)
+
+// SyntaxError represents an error encountered while parsing the Vyper source.
+type SyntaxError struct {
+	Path    string `json:"path"`
+	Line    int64  `json:"line"`
+	Message string `json:"message"`
+}
+
+// Error returns the string representation of the syntax error.
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%s:%d: %s", e.Path, e.Line, e.Message)
+}
@@ -0,0 +1,176 @@
+package vyper
+
+import (
+	"strings"
+)
+
+// line represents a single logical line of the Vyper source. Physical lines joined through open brackets
+// or backslash continuation form a single logical line. Comments and standalone docstrings are removed.
+type line struct {
+	text   string
+	indent int
+	number int64
+	column int64
+	start  int64
+	end    int64
+}
+
+// comment represents a single `#` comment found within the Vyper source.
+type comment struct {
+	text   string
+	number int64
+	start  int64
+}
+
+// splitLines splits the Vyper source into logical lines and comments.
+func splitLines(path string, content string) ([]line, []comment, error) {
+	var (
+		lines    []line
+		comments []comment
+		buf      strings.Builder
+		current  line
+		depth    int
+		number   int64 = 1
+		lineHead       = 0
+		started        = false
+		newLine        = true
+		indent         = 0
+	)
+
+	flush := func(end int) {
+		if started {
+			current.text = strings.TrimSpace(buf.String())
+			current.end = int64(end)
+			if current.text != "" {
+				lines = append(lines, current)
+			}
+		}
+		buf.Reset()
+		started = false
+	}
+
+	for i := 0; i < len(content); {
+		c := content[i]
+
+		if newLine {
+			indent = 0
+			for i < len(content) && (content[i] == ' ' || content[i] == '\t') {
+				if content[i] == '\t' {
+					indent += 4
+				} else {
+					indent++
+				}
+				i++
+			}
+			newLine = false
+			continue
+		}
+
+		switch {
+		case c == '"' || c == '\'':
+			end, spans, err := scanString(content, i)
+			if err != nil {
+				return nil, nil, &SyntaxError{Path: path, Line: number, Message: err.Error()}
+			}
+
+			// Standalone (doc)strings are not statements and are skipped entirely.
+			if started || depth > 0 {
+				buf.WriteString(content[i:end])
+			}
+
+			if spans > 0 {
+				number += int64(spans)
+				lineHead = i + strings.LastIndexByte(content[i:end], '\n') + 1
+			}
+			i = end
+		case c == '#':
+			end := strings.IndexByte(content[i:], '\n')
+			if end < 0 {
+				end = len(content) - i
+			}
+			comments = append(comments, comment{
+				text:   strings.TrimSpace(content[i+1 : i+end]),
+				number: number,
+				start:  int64(i),
+			})
+			i += end
+		case c == '\\' && i+1 < len(content) && content[i+1] == '\n':
+			buf.WriteByte(' ')
+			number++
+			i += 2
+			lineHead = i
+		case c == '\r':
+			i++
+		case c == '\n':
+			number++
+			i++
+			lineHead = i
+			if depth > 0 {
+				buf.WriteByte(' ')
+				continue
+			}
+			flush(i - 1)
+			newLine = true
+		default:
+			switch c {
+			case '(', '[', '{':
+				depth++
+			case ')', ']', '}':
+				if depth > 0 {
+					depth--
+				}
+			}
+
+			if !started && c != ' ' && c != '\t' {
+				started = true
+				current = line{
+					indent: indent,
+					number: number,
+					column: int64(i - lineHead),
+					start:  int64(i),
+				}
+			}
+
+			buf.WriteByte(c)
+			i++
+		}
+	}
+
+	flush(len(content))
+
+	if depth > 0 {
+		return nil, nil, &SyntaxError{Path: path, Line: number, Message: "unexpected end of file, unclosed bracket"}
+	}
+
+	return lines, comments, nil
+}
+
+// scanString returns the offset right after the string literal starting at the provided offset,
+// along with the number of new lines the literal spans.
+func scanString(content string, start int) (int, int, error) {
+	quote := content[start]
+	delimiter := string(quote)
+	if strings.HasPrefix(content[start:], strings.Repeat(delimiter, 3)) {
+		delimiter = strings.Repeat(delimiter, 3)
+	}
+
+	lines := 0
+	for i := start + len(delimiter); i < len(content); i++ {
+		switch {
+		case content[i] == '\\':
+			i++
+			if i < len(content) && content[i] == '\n' {
+				lines++
+			}
+		case content[i] == '\n':
+			if len(delimiter) == 1 {
+				return 0, 0, errUnterminatedString
+			}
+			lines++
+		case strings.HasPrefix(content[i:], delimiter):
+			return i + len(delimiter), lines, nil
+		}
+	}
+
+	return 0, 0, errUnterminatedString
+}
@@ -0,0 +1,286 @@
+package vyper
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	ast_pb "github.com/unpackdev/protos/dist/go/ast"
+	"github.com/unpackdev/solgo/ast"
+	"github.com/unpackdev/solgo/ir"
+	"github.com/unpackdev/solgo/utils"
+)
+
+// lowerer lowers parsed Vyper modules into IR contracts. It keeps node ids unique across all of the modules.
+type lowerer struct {
+	id int64
+}
+
+// nextId returns the next unique node id.
+func (l *lowerer) nextId() int64 {
+	l.id++
+	return l.id
+}
+
+// lowerModule lowers the Vyper module into the IR contract.
+func (l *lowerer) lowerModule(module *Module) (*ir.Contract, error) {
+	kind := ast_pb.NodeType_KIND_CONTRACT
+	if module.IsInterface() {
+		kind = ast_pb.NodeType_KIND_INTERFACE
+	}
+
+	contractId := l.nextId()
+	toReturn := &ir.Contract{
+		Id:             contractId,
+		SourceUnitId:   contractId,
+		NodeType:       ast_pb.NodeType_CONTRACT_DEFINITION,
+		Kind:           kind,
+		Name:           module.GetName(),
+		License:        module.License,
+		Language:       ir.LanguageVyper,
+		AbsolutePath:   module.GetPath(),
+		Symbols:        make([]*ir.Symbol, 0),
+		BaseContracts:  make([]*ast.BaseContract, 0),
+		Imports:        make([]*ir.Import, 0),
+		Pragmas:        make([]*ir.Pragma, 0),
+		StateVariables: make([]*ir.StateVariable, 0),
+		Structs:        make([]*ir.Struct, 0),
+		Enums:          make([]*ir.Enum, 0),
+		Events:         make([]*ir.Event, 0),
+		Errors:         make([]*ir.Error, 0),
+		Functions:      make([]*ir.Function, 0),
+	}
+
+	for _, pragma := range module.Pragmas {
+		toReturn.Pragmas = append(toReturn.Pragmas, &ir.Pragma{
+			Id:       l.nextId(),
+			NodeType: ast_pb.NodeType_PRAGMA_DIRECTIVE,
+			Literals: strings.Fields(pragma.Text),
+			Text:     pragma.Text,
+		})
+	}
+
+	for _, imp := range module.Imports {
+		toReturn.Imports = append(toReturn.Imports, &ir.Import{
+			Id:           l.nextId(),
+			NodeType:     ast_pb.NodeType_IMPORT_DIRECTIVE,
+			AbsolutePath: imp.Path,
+			File:         strings.ReplaceAll(imp.Path, ".", "/"),
+			UnitAlias:    imp.Alias,
+			ContractId:   contractId,
+		})
+	}
+
+	for _, structNode := range module.Structs {
+		structType, err := module.ResolveType(structNode.Name)
+		if err != nil {
+			return nil, l.errorf(module, structNode.Src, err)
+		}
+
+		members, err := l.lowerParameters(module, structNode.Members)
+		if err != nil {
+			return nil, err
+		}
+
+		toReturn.Structs = append(toReturn.Structs, &ir.Struct{
+			Id:              l.nextId(),
+			NodeType:        ast_pb.NodeType_STRUCT_DEFINITION,
+			Name:            structNode.Name,
+			CanonicalName:   fmt.Sprintf("%s.%s", module.GetName(), structNode.Name),
+			Visibility:      ast_pb.Visibility_PUBLIC,
+			StorageLocation: ast_pb.StorageLocation_DEFAULT,
+			Members:         members,
+			Type:            structType.Description.GetString(),
+			TypeDescription: structType.Description,
+		})
+	}
+
+	for _, variable := range module.StateVariables {
+		variableType, err := module.ResolveType(variable.Type)
+		if err != nil {
+			return nil, l.errorf(module, variable.Src, err)
+		}
+
+		visibility := ast_pb.Visibility_INTERNAL
+		if variable.Public {
+			visibility = ast_pb.Visibility_PUBLIC
+		}
+
+		mutability := ast_pb.Mutability_MUTABLE
+		if variable.Immutable || variable.Constant {
+			mutability = ast_pb.Mutability_IMMUTABLE
+		}
+
+		toReturn.StateVariables = append(toReturn.StateVariables, &ir.StateVariable{
+			Id:              l.nextId(),
+			ContractId:      contractId,
+			Name:            variable.Name,
+			NodeType:        ast_pb.NodeType_VARIABLE_DECLARATION,
+			Visibility:      visibility,
+			Constant:        variable.Constant,
+			StorageLocation: ast_pb.StorageLocation_DEFAULT,
+			StateMutability: mutability,
+			Type:            variableType.Description.GetString(),
+			TypeDescription: variableType.Description,
+		})
+	}
+
+	for _, event := range module.Events {
+		parameters, err := l.lowerParameters(module, event.Fields)
+		if err != nil {
+			return nil, err
+		}
+
+		toReturn.Events = append(toReturn.Events, &ir.Event{
+			Id:         l.nextId(),
+			NodeType:   ast_pb.NodeType_EVENT_DEFINITION,
+			Name:       event.Name,
+			Parameters: parameters,
+		})
+	}
+
+	for _, fn := range module.Functions {
+		if err := l.lowerFunction(module, fn, toReturn); err != nil {
+			return nil, err
+		}
+	}
+
+	return toReturn, nil
+}
+
+// lowerFunction lowers the function into the IR constructor, fallback or function of the contract.
+func (l *lowerer) lowerFunction(module *Module, fn *Function, contract *ir.Contract) error {
+	parameters, err := l.lowerParameters(module, fn.Parameters)
+	if err != nil {
+		return err
+	}
+
+	returns := make([]*ir.Parameter, 0, len(fn.Returns))
+	for _, ret := range fn.Returns {
+		retType, err := module.ResolveType(ret)
+		if err != nil {
+			return l.errorf(module, fn.Src, err)
+		}
+
+		returns = append(returns, &ir.Parameter{
+			Id:              l.nextId(),
+			NodeType:        ast_pb.NodeType_VARIABLE_DECLARATION,
+			Type:            retType.Canonical,
+			TypeDescription: retType.Description,
+		})
+	}
+
+	canonical := make([]string, 0, len(parameters))
+	for _, parameter := range parameters {
+		canonical = append(canonical, parameter.GetType())
+	}
+
+	mutability := toMutability(fn.GetMutability())
+	modifiers := make([]*ir.Modifier, 0)
+
+	switch {
+	case fn.IsConstructor():
+		contract.Constructor = &ir.Constructor{
+			Id:               l.nextId(),
+			NodeType:         ast_pb.NodeType_FUNCTION_DEFINITION,
+			Kind:             ast_pb.NodeType_CONSTRUCTOR,
+			Name:             fn.Name,
+			Implemented:      true,
+			Visibility:       ast_pb.Visibility_PUBLIC,
+			StateMutability:  mutability,
+			Modifiers:        modifiers,
+			Parameters:       parameters,
+			ReturnStatements: returns,
+		}
+	case fn.IsDefault():
+		contract.Fallback = &ir.Fallback{
+			Id:               l.nextId(),
+			NodeType:         ast_pb.NodeType_FUNCTION_DEFINITION,
+			Kind:             ast_pb.NodeType_FALLBACK,
+			Name:             fn.Name,
+			Implemented:      true,
+			Visibility:       ast_pb.Visibility_EXTERNAL,
+			StateMutability:  mutability,
+			Modifiers:        modifiers,
+			Overrides:        make([]*ir.Override, 0),
+			Parameters:       parameters,
+			ReturnStatements: returns,
+		}
+	default:
+		visibility := ast_pb.Visibility_INTERNAL
+		if fn.IsExternal() {
+			visibility = ast_pb.Visibility_EXTERNAL
+		}
+
+		signatureRaw := fmt.Sprintf("%s(%s)", fn.Name, strings.Join(canonical, ","))
+
+		contract.Functions = append(contract.Functions, &ir.Function{
+			Id:              l.nextId(),
+			NodeType:        ast_pb.NodeType_FUNCTION_DEFINITION,
+			Kind:            ast_pb.NodeType_KIND_FUNCTION,
+			Name:            fn.Name,
+			Implemented:     !module.IsInterface(),
+			Visibility:      visibility,
+			StateMutability: mutability,
+			Signature:       common.Bytes2Hex(utils.Keccak256([]byte(signatureRaw))[:4]),
+			Modifiers:       modifiers,
+			Overrides:       make([]*ir.Override, 0),
+			Parameters:      parameters,
+			Body: &ir.Body{
+				Id:         l.nextId(),
+				NodeType:   ast_pb.NodeType_BLOCK,
+				Kind:       ast_pb.NodeType_KIND_FUNCTION,
+				Statements: make([]ir.Statement, 0),
+			},
+			ReturnStatements: returns,
+			Src:              fn.Src,
+		})
+	}
+
+	return nil
+}
+
+// lowerParameters lowers function arguments, event fields or struct members into the IR parameters.
+func (l *lowerer) lowerParameters(module *Module, parameters []*Parameter) ([]*ir.Parameter, error) {
+	toReturn := make([]*ir.Parameter, 0, len(parameters))
+	for _, parameter := range parameters {
+		parameterType, err := module.ResolveType(parameter.Type)
+		if err != nil {
+			return nil, l.errorf(module, parameter.Src, err)
+		}
+
+		toReturn = append(toReturn, &ir.Parameter{
+			Id:              l.nextId(),
+			NodeType:        ast_pb.NodeType_VARIABLE_DECLARATION,
+			Name:            parameter.Name,
+			Type:            parameterType.Canonical,
+			TypeDescription: parameterType.Description,
+			Indexed:         parameter.Indexed,
+		})
+	}
+
+	return toReturn, nil
+}
+
+// errorf wraps the lowering error into the syntax error positioned at the provided source node.
+func (l *lowerer) errorf(module *Module, src ast.SrcNode, err error) error {
+	return &SyntaxError{
+		Path:    module.GetPath(),
+		Line:    src.GetLine(),
+		Message: err.Error(),
+	}
+}
+
+// toMutability converts the ABI state mutability into its protobuf representation.
+func toMutability(mutability string) ast_pb.Mutability {
+	switch mutability {
+	case "pure":
+		return ast_pb.Mutability_PURE
+	case "view":
+		return ast_pb.Mutability_VIEW
+	case "payable":
+		return ast_pb.Mutability_PAYABLE
+	default:
+		return ast_pb.Mutability_NONPAYABLE
+	}
+}
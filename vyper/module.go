@@ -0,0 +1,253 @@
+package vyper
+
+import (
+	"github.com/unpackdev/solgo/ast"
+)
+
+// Module represents a single parsed Vyper source file. Every Vyper module compiles into a single contract.
+type Module struct {
+	Name           string           `json:"name"`
+	Path           string           `json:"path"`
+	Version        string           `json:"version"`
+	EvmVersion     string           `json:"evm_version,omitempty"`
+	License        string           `json:"license,omitempty"`
+	Interface      bool             `json:"interface"`
+	Pragmas        []*Pragma        `json:"pragmas"`
+	Imports        []*Import        `json:"imports"`
+	Implements     []string         `json:"implements"`
+	Interfaces     []*Interface     `json:"interfaces"`
+	Events         []*Event         `json:"events"`
+	Structs        []*Struct        `json:"structs"`
+	Flags          []*Flag          `json:"flags"`
+	StateVariables []*StateVariable `json:"state_variables"`
+	Functions      []*Function      `json:"functions"`
+}
+
+// GetName returns the name of the module.
+func (m *Module) GetName() string {
+	return m.Name
+}
+
+// GetPath returns the path of the module.
+func (m *Module) GetPath() string {
+	return m.Path
+}
+
+// GetVersion returns the Vyper version declared by the module pragma.
+func (m *Module) GetVersion() string {
+	return m.Version
+}
+
+// IsInterface returns true if the module is an interface file (.vyi).
+func (m *Module) IsInterface() bool {
+	return m.Interface
+}
+
+// GetImports returns the imports of the module.
+func (m *Module) GetImports() []*Import {
+	return m.Imports
+}
+
+// GetInterfaces returns the interfaces declared within the module.
+func (m *Module) GetInterfaces() []*Interface {
+	return m.Interfaces
+}
+
+// GetEvents returns the events declared within the module.
+func (m *Module) GetEvents() []*Event {
+	return m.Events
+}
+
+// GetStructs returns the structs declared within the module.
+func (m *Module) GetStructs() []*Struct {
+	return m.Structs
+}
+
+// GetFlags returns the flags (enums prior to Vyper 0.4) declared within the module.
+func (m *Module) GetFlags() []*Flag {
+	return m.Flags
+}
+
+// GetStateVariables returns the state variables, constants and immutables declared within the module.
+func (m *Module) GetStateVariables() []*StateVariable {
+	return m.StateVariables
+}
+
+// GetFunctions returns the functions declared within the module, including __init__ and __default__.
+func (m *Module) GetFunctions() []*Function {
+	return m.Functions
+}
+
+// GetFunction returns the function with the provided name or nil if it does not exist.
+func (m *Module) GetFunction(name string) *Function {
+	for _, fn := range m.Functions {
+		if fn.Name == name {
+			return fn
+		}
+	}
+
+	return nil
+}
+
+// GetStruct returns the struct with the provided name or nil if it does not exist.
+func (m *Module) GetStruct(name string) *Struct {
+	for _, s := range m.Structs {
+		if s.Name == name {
+			return s
+		}
+	}
+
+	return nil
+}
+
+// GetFlag returns the flag with the provided name or nil if it does not exist.
+func (m *Module) GetFlag(name string) *Flag {
+	for _, f := range m.Flags {
+		if f.Name == name {
+			return f
+		}
+	}
+
+	return nil
+}
+
+// GetInterface returns the interface with the provided name or nil if it does not exist.
+func (m *Module) GetInterface(name string) *Interface {
+	for _, i := range m.Interfaces {
+		if i.Name == name {
+			return i
+		}
+	}
+
+	return nil
+}
+
+// GetImport returns the import bound to the provided name (alias) or nil if it does not exist.
+func (m *Module) GetImport(name string) *Import {
+	for _, i := range m.Imports {
+		if i.Alias == name {
+			return i
+		}
+	}
+
+	return nil
+}
+
+// Pragma represents a `# @version` or `#pragma` comment directive.
+type Pragma struct {
+	Text string      `json:"text"`
+	Src  ast.SrcNode `json:"src"`
+}
+
+// Import represents `import a.b as c` and `from a.b import c` statements.
+type Import struct {
+	Path  string      `json:"path"`
+	Alias string      `json:"alias"`
+	Src   ast.SrcNode `json:"src"`
+}
+
+// Parameter represents a typed name, such as a function argument, event field or struct member.
+type Parameter struct {
+	Name    string      `json:"name"`
+	Type    string      `json:"type"`
+	Indexed bool        `json:"indexed,omitempty"`
+	Default string      `json:"default,omitempty"`
+	Src     ast.SrcNode `json:"src"`
+}
+
+// Interface represents an interface declared within the module.
+type Interface struct {
+	Name      string       `json:"name"`
+	Functions []*Signature `json:"functions"`
+	Src       ast.SrcNode  `json:"src"`
+}
+
+// Signature represents a function declared within an interface.
+type Signature struct {
+	Name       string       `json:"name"`
+	Parameters []*Parameter `json:"parameters"`
+	Returns    []string     `json:"returns"`
+	Mutability string       `json:"mutability"`
+	Src        ast.SrcNode  `json:"src"`
+}
+
+// Event represents an event declaration.
+type Event struct {
+	Name   string       `json:"name"`
+	Fields []*Parameter `json:"fields"`
+	Src    ast.SrcNode  `json:"src"`
+}
+
+// Struct represents a struct declaration.
+type Struct struct {
+	Name    string       `json:"name"`
+	Members []*Parameter `json:"members"`
+	Src     ast.SrcNode  `json:"src"`
+}
+
+// Flag represents a flag declaration (named enum prior to Vyper 0.4).
+type Flag struct {
+	Name    string      `json:"name"`
+	Members []string    `json:"members"`
+	Src     ast.SrcNode `json:"src"`
+}
+
+// StateVariable represents a storage variable, constant, immutable or transient declaration.
+type StateVariable struct {
+	Name      string      `json:"name"`
+	Type      string      `json:"type"`
+	Public    bool        `json:"public"`
+	Constant  bool        `json:"constant"`
+	Immutable bool        `json:"immutable"`
+	Transient bool        `json:"transient"`
+	Value     string      `json:"value,omitempty"`
+	Src       ast.SrcNode `json:"src"`
+}
+
+// Function represents a function definition along with its decorators.
+type Function struct {
+	Name         string       `json:"name"`
+	Decorators   []string     `json:"decorators"`
+	Parameters   []*Parameter `json:"parameters"`
+	Returns      []string     `json:"returns"`
+	Nonreentrant bool         `json:"nonreentrant"`
+	Body         []string     `json:"body"`
+	Src          ast.SrcNode  `json:"src"`
+}
+
+// HasDecorator returns true if the function is decorated with the provided decorator (without `@`).
+func (f *Function) HasDecorator(name string) bool {
+	for _, decorator := range f.Decorators {
+		if decorator == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsConstructor returns true if the function is the module constructor.
+func (f *Function) IsConstructor() bool {
+	return f.Name == "__init__"
+}
+
+// IsDefault returns true if the function is the module default (fallback) function.
+func (f *Function) IsDefault() bool {
+	return f.Name == "__default__"
+}
+
+// IsExternal returns true if the function is callable from outside of the contract.
+func (f *Function) IsExternal() bool {
+	return f.HasDecorator("external")
+}
+
+// GetMutability returns the state mutability of the function as used within the ABI.
+func (f *Function) GetMutability() string {
+	for _, mutability := range []string{"pure", "view", "payable"} {
+		if f.HasDecorator(mutability) {
+			return mutability
+		}
+	}
+
+	return "nonpayable"
+}
@@ -0,0 +1,622 @@
+package vyper
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/unpackdev/solgo/ast"
+)
+
+var (
+	identifierRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	versionRegex    = regexp.MustCompile(`^(?:@version|pragma\s+(?:version|vyper))\s+(.+)$`)
+	evmVersionRegex = regexp.MustCompile(`^pragma\s+evm-?version\s+(.+)$`)
+	licenseRegex    = regexp.MustCompile(`^(?:@license|SPDX-License-Identifier:)\s*(.+)$`)
+)
+
+// parser holds the state of a single Vyper module parsing.
+type parser struct {
+	module *Module
+	lines  []line
+	pos    int
+	errs   []error
+}
+
+// Parse parses the Vyper source into a Module. Name is used as the contract name and, if empty,
+// it is derived from the path. Files with the `.vyi` extension are parsed as interface modules.
+func Parse(name string, path string, content string) (*Module, []error) {
+	if strings.TrimSpace(content) == "" {
+		return nil, []error{ErrEmptySource}
+	}
+
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+
+	lines, comments, err := splitLines(path, content)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	p := &parser{
+		module: &Module{
+			Name:           name,
+			Path:           path,
+			Interface:      filepath.Ext(path) == ".vyi",
+			Pragmas:        make([]*Pragma, 0),
+			Imports:        make([]*Import, 0),
+			Implements:     make([]string, 0),
+			Interfaces:     make([]*Interface, 0),
+			Events:         make([]*Event, 0),
+			Structs:        make([]*Struct, 0),
+			Flags:          make([]*Flag, 0),
+			StateVariables: make([]*StateVariable, 0),
+			Functions:      make([]*Function, 0),
+		},
+		lines: lines,
+	}
+
+	p.parseComments(comments)
+	p.parseModule()
+
+	return p.module, p.errs
+}
+
+// parseComments extracts version pragmas and the license out of the module comments.
+func (p *parser) parseComments(comments []comment) {
+	for _, c := range comments {
+		if matches := versionRegex.FindStringSubmatch(c.text); matches != nil {
+			p.module.Version = strings.TrimSpace(matches[1])
+			p.addPragma(c)
+		} else if matches := evmVersionRegex.FindStringSubmatch(c.text); matches != nil {
+			p.module.EvmVersion = strings.TrimSpace(matches[1])
+			p.addPragma(c)
+		} else if strings.HasPrefix(c.text, "pragma ") {
+			p.addPragma(c)
+		} else if matches := licenseRegex.FindStringSubmatch(c.text); matches != nil && p.module.License == "" {
+			p.module.License = strings.TrimSpace(matches[1])
+		}
+	}
+}
+
+// addPragma records the comment as a module pragma.
+func (p *parser) addPragma(c comment) {
+	p.module.Pragmas = append(p.module.Pragmas, &Pragma{
+		Text: c.text,
+		Src: ast.SrcNode{
+			Line:   c.number,
+			Start:  c.start,
+			End:    c.start + int64(len(c.text)),
+			Length: int64(len(c.text)),
+		},
+	})
+}
+
+// parseModule parses all of the top level statements.
+func (p *parser) parseModule() {
+	decorators := make([]line, 0)
+
+	for p.pos < len(p.lines) {
+		l := p.lines[p.pos]
+
+		if l.indent != 0 {
+			p.errorf(l, "unexpected indentation")
+			p.pos++
+			continue
+		}
+
+		if len(decorators) > 0 && !strings.HasPrefix(l.text, "@") && !strings.HasPrefix(l.text, "def ") {
+			p.errorf(decorators[0], "decorator must be followed by a function definition")
+			decorators = decorators[:0]
+		}
+
+		switch {
+		case strings.HasPrefix(l.text, "@"):
+			decorators = append(decorators, l)
+			p.pos++
+		case strings.HasPrefix(l.text, "def "):
+			p.pos++
+			p.parseFunction(decorators, l, p.block(l))
+			decorators = make([]line, 0)
+		case hasKeyword(l.text, "event"):
+			p.pos++
+			p.parseEvent(l, p.block(l))
+		case hasKeyword(l.text, "struct"):
+			p.pos++
+			p.parseStruct(l, p.block(l))
+		case hasKeyword(l.text, "interface"):
+			p.pos++
+			p.parseInterface(l, p.block(l))
+		case hasKeyword(l.text, "flag"), hasKeyword(l.text, "enum"):
+			p.pos++
+			p.parseFlag(l, p.block(l))
+		case strings.HasPrefix(l.text, "import ") || strings.HasPrefix(l.text, "from "):
+			p.pos++
+			p.parseImport(l)
+		case strings.HasPrefix(l.text, "implements:"):
+			p.pos++
+			p.module.Implements = append(p.module.Implements, strings.TrimSpace(strings.TrimPrefix(l.text, "implements:")))
+		case strings.HasPrefix(l.text, "initializes:"), strings.HasPrefix(l.text, "uses:"), strings.HasPrefix(l.text, "exports:"):
+			// Module composition statements (Vyper 0.4+) do not declare anything on their own.
+			p.pos++
+			p.block(l)
+		default:
+			p.pos++
+			p.parseStateVariable(l)
+		}
+	}
+
+	if len(decorators) > 0 {
+		p.errorf(decorators[0], "decorator must be followed by a function definition")
+	}
+}
+
+// block returns all of the lines indented deeper than the provided header line. In case the header has an
+// inline body, such as `event Paused: pass`, the inline body is returned as the only line of the block.
+func (p *parser) block(header line) []line {
+	toReturn := make([]line, 0)
+
+	if idx := topLevelIndex(header.text, ':', true); idx >= 0 && idx < len(header.text)-1 {
+		inline := header
+		inline.text = strings.TrimSpace(header.text[idx+1:])
+		inline.indent = header.indent + 1
+		if inline.text != "" && !strings.HasPrefix(header.text, "def ") {
+			toReturn = append(toReturn, inline)
+		}
+	}
+
+	for p.pos < len(p.lines) && p.lines[p.pos].indent > header.indent {
+		toReturn = append(toReturn, p.lines[p.pos])
+		p.pos++
+	}
+
+	return toReturn
+}
+
+// parseFunction parses the function definition along with its decorators and body.
+func (p *parser) parseFunction(decorators []line, header line, body []line) {
+	signature, ok := p.parseSignature(header)
+	if !ok {
+		return
+	}
+
+	fn := &Function{
+		Name:       signature.Name,
+		Decorators: make([]string, 0),
+		Parameters: signature.Parameters,
+		Returns:    signature.Returns,
+		Body:       make([]string, 0),
+		Src:        src(header, body),
+	}
+
+	for _, decorator := range decorators {
+		name := strings.TrimPrefix(decorator.text, "@")
+		if idx := strings.IndexByte(name, '('); idx >= 0 {
+			name = name[:idx]
+		}
+		name = strings.TrimSpace(name)
+
+		if name == "nonreentrant" {
+			fn.Nonreentrant = true
+		}
+
+		fn.Decorators = append(fn.Decorators, name)
+	}
+
+	// Interface files (.vyi) may omit the visibility as every function is external.
+	if p.module.Interface && !fn.HasDecorator("internal") && !fn.HasDecorator("external") {
+		fn.Decorators = append(fn.Decorators, "external")
+	}
+
+	for _, l := range body {
+		fn.Body = append(fn.Body, l.text)
+	}
+
+	p.module.Functions = append(p.module.Functions, fn)
+}
+
+// parseSignature parses `def name(args) -> returns: inline` into the signature.
+func (p *parser) parseSignature(l line) (*Signature, bool) {
+	text := strings.TrimSpace(strings.TrimPrefix(l.text, "def "))
+
+	open := strings.IndexByte(text, '(')
+	if open < 0 {
+		p.errorf(l, "missing function arguments")
+		return nil, false
+	}
+
+	name := strings.TrimSpace(text[:open])
+	if !identifierRegex.MatchString(name) {
+		p.errorf(l, "invalid function name %q", name)
+		return nil, false
+	}
+
+	closing := matchingBracket(text, open)
+	if closing < 0 {
+		p.errorf(l, "unclosed function arguments")
+		return nil, false
+	}
+
+	toReturn := &Signature{
+		Name:       name,
+		Parameters: make([]*Parameter, 0),
+		Returns:    make([]string, 0),
+		Src:        src(l, nil),
+	}
+
+	for _, arg := range splitTopLevel(text[open+1:closing], ',') {
+		parameter, err := parseParameter(arg)
+		if err != nil {
+			p.errorf(l, "%s", err)
+			return nil, false
+		}
+		parameter.Src = toReturn.Src
+		toReturn.Parameters = append(toReturn.Parameters, parameter)
+	}
+
+	rest := strings.TrimSpace(text[closing+1:])
+	colon := topLevelIndex(rest, ':', false)
+	if colon < 0 {
+		p.errorf(l, "missing `:` after function definition")
+		return nil, false
+	}
+
+	toReturn.Mutability = strings.TrimSpace(rest[colon+1:])
+
+	if returns := strings.TrimSpace(rest[:colon]); returns != "" {
+		if !strings.HasPrefix(returns, "->") {
+			p.errorf(l, "unexpected %q after function arguments", returns)
+			return nil, false
+		}
+
+		returns = strings.TrimSpace(strings.TrimPrefix(returns, "->"))
+		if strings.HasPrefix(returns, "(") && matchingBracket(returns, 0) == len(returns)-1 {
+			returns = returns[1 : len(returns)-1]
+		}
+
+		toReturn.Returns = append(toReturn.Returns, splitTopLevel(returns, ',')...)
+	}
+
+	return toReturn, true
+}
+
+// parseEvent parses the event declaration.
+func (p *parser) parseEvent(header line, body []line) {
+	event := &Event{
+		Name:   declarationName(header.text, "event"),
+		Fields: make([]*Parameter, 0),
+		Src:    src(header, body),
+	}
+
+	for _, l := range body {
+		if l.text == "pass" || l.text == "..." {
+			continue
+		}
+
+		field, err := parseParameter(l.text)
+		if err != nil {
+			p.errorf(l, "%s", err)
+			continue
+		}
+
+		if inner, ok := unwrap(field.Type, "indexed"); ok {
+			field.Type = inner
+			field.Indexed = true
+		}
+
+		field.Src = src(l, nil)
+		event.Fields = append(event.Fields, field)
+	}
+
+	p.module.Events = append(p.module.Events, event)
+}
+
+// parseStruct parses the struct declaration.
+func (p *parser) parseStruct(header line, body []line) {
+	structNode := &Struct{
+		Name:    declarationName(header.text, "struct"),
+		Members: make([]*Parameter, 0),
+		Src:     src(header, body),
+	}
+
+	for _, l := range body {
+		member, err := parseParameter(l.text)
+		if err != nil {
+			p.errorf(l, "%s", err)
+			continue
+		}
+
+		member.Src = src(l, nil)
+		structNode.Members = append(structNode.Members, member)
+	}
+
+	p.module.Structs = append(p.module.Structs, structNode)
+}
+
+// parseFlag parses the flag (or legacy enum) declaration.
+func (p *parser) parseFlag(header line, body []line) {
+	keyword := "flag"
+	if hasKeyword(header.text, "enum") {
+		keyword = "enum"
+	}
+
+	flag := &Flag{
+		Name:    declarationName(header.text, keyword),
+		Members: make([]string, 0),
+		Src:     src(header, body),
+	}
+
+	for _, l := range body {
+		flag.Members = append(flag.Members, l.text)
+	}
+
+	p.module.Flags = append(p.module.Flags, flag)
+}
+
+// parseInterface parses the interface declaration and its function signatures.
+func (p *parser) parseInterface(header line, body []line) {
+	iface := &Interface{
+		Name:      declarationName(header.text, "interface"),
+		Functions: make([]*Signature, 0),
+		Src:       src(header, body),
+	}
+
+	for _, l := range body {
+		if !strings.HasPrefix(l.text, "def ") {
+			continue
+		}
+
+		if signature, ok := p.parseSignature(l); ok {
+			iface.Functions = append(iface.Functions, signature)
+		}
+	}
+
+	p.module.Interfaces = append(p.module.Interfaces, iface)
+}
+
+// parseImport parses `import a.b [as c]` and `from a.b import c [as d]` statements.
+func (p *parser) parseImport(l line) {
+	var path, alias string
+
+	if strings.HasPrefix(l.text, "from ") {
+		parts := strings.SplitN(strings.TrimPrefix(l.text, "from "), " import ", 2)
+		if len(parts) != 2 {
+			p.errorf(l, "invalid import statement")
+			return
+		}
+
+		name := strings.TrimSpace(parts[1])
+		path = strings.TrimSpace(parts[0]) + "." + name
+		alias = name
+	} else {
+		path = strings.TrimSpace(strings.TrimPrefix(l.text, "import "))
+		alias = path[strings.LastIndexByte(path, '.')+1:]
+	}
+
+	if parts := strings.SplitN(alias, " as ", 2); len(parts) == 2 {
+		alias = strings.TrimSpace(parts[1])
+		path = strings.TrimSpace(strings.SplitN(path, " as ", 2)[0])
+	}
+
+	p.module.Imports = append(p.module.Imports, &Import{
+		Path:  path,
+		Alias: alias,
+		Src:   src(l, nil),
+	})
+}
+
+// parseStateVariable parses storage variables, constants, immutables and transient variables.
+func (p *parser) parseStateVariable(l line) {
+	colon := topLevelIndex(l.text, ':', false)
+	if colon < 0 {
+		p.errorf(l, "unexpected statement %q", l.text)
+		return
+	}
+
+	name := strings.TrimSpace(l.text[:colon])
+	if !identifierRegex.MatchString(name) {
+		p.errorf(l, "invalid state variable name %q", name)
+		return
+	}
+
+	variable := &StateVariable{
+		Name: name,
+		Src:  src(l, nil),
+	}
+
+	spec := strings.TrimSpace(l.text[colon+1:])
+	if idx := topLevelIndex(spec, '=', false); idx >= 0 {
+		variable.Value = strings.TrimSpace(spec[idx+1:])
+		spec = strings.TrimSpace(spec[:idx])
+	}
+
+	for {
+		if inner, ok := unwrap(spec, "public"); ok {
+			variable.Public, spec = true, inner
+		} else if inner, ok := unwrap(spec, "constant"); ok {
+			variable.Constant, spec = true, inner
+		} else if inner, ok := unwrap(spec, "immutable"); ok {
+			variable.Immutable, spec = true, inner
+		} else if inner, ok := unwrap(spec, "transient"); ok {
+			variable.Transient, spec = true, inner
+		} else {
+			break
+		}
+	}
+
+	if spec == "" {
+		p.errorf(l, "missing type of state variable %q", name)
+		return
+	}
+
+	variable.Type = spec
+	p.module.StateVariables = append(p.module.StateVariables, variable)
+}
+
+// errorf records the syntax error for the provided line.
+func (p *parser) errorf(l line, format string, args ...any) {
+	p.errs = append(p.errs, &SyntaxError{
+		Path:    p.module.Path,
+		Line:    l.number,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// parseParameter parses `name: type [= default]`.
+func parseParameter(text string) (*Parameter, error) {
+	colon := topLevelIndex(text, ':', false)
+	if colon < 0 {
+		return nil, fmt.Errorf("missing type for %q", strings.TrimSpace(text))
+	}
+
+	toReturn := &Parameter{
+		Name: strings.TrimSpace(text[:colon]),
+		Type: strings.TrimSpace(text[colon+1:]),
+	}
+
+	if idx := topLevelIndex(toReturn.Type, '=', false); idx >= 0 {
+		toReturn.Default = strings.TrimSpace(toReturn.Type[idx+1:])
+		toReturn.Type = strings.TrimSpace(toReturn.Type[:idx])
+	}
+
+	if !identifierRegex.MatchString(toReturn.Name) {
+		return nil, fmt.Errorf("invalid name %q", toReturn.Name)
+	}
+
+	if toReturn.Type == "" {
+		return nil, fmt.Errorf("missing type for %q", toReturn.Name)
+	}
+
+	return toReturn, nil
+}
+
+// hasKeyword returns true if the statement is a block declaration starting with the keyword, e.g. `struct Foo:`.
+func hasKeyword(text string, keyword string) bool {
+	return strings.HasPrefix(text, keyword+" ") && strings.Contains(text, ":")
+}
+
+// declarationName returns the name of a `keyword Name:` declaration.
+func declarationName(text string, keyword string) string {
+	name := strings.TrimPrefix(text, keyword+" ")
+	if idx := strings.IndexByte(name, ':'); idx >= 0 {
+		name = name[:idx]
+	}
+	return strings.TrimSpace(name)
+}
+
+// unwrap returns the inner part of `wrapper(inner)`.
+func unwrap(text string, wrapper string) (string, bool) {
+	if !strings.HasPrefix(text, wrapper+"(") || !strings.HasSuffix(text, ")") {
+		return text, false
+	}
+
+	if matchingBracket(text, len(wrapper)) != len(text)-1 {
+		return text, false
+	}
+
+	return strings.TrimSpace(text[len(wrapper)+1 : len(text)-1]), true
+}
+
+// src builds the source node spanning the header and the block lines.
+func src(header line, body []line) ast.SrcNode {
+	end := header.end
+	if len(body) > 0 && body[len(body)-1].end > end {
+		end = body[len(body)-1].end
+	}
+
+	return ast.SrcNode{
+		Line:   header.number,
+		Column: header.column,
+		Start:  header.start,
+		End:    end,
+		Length: end - header.start,
+	}
+}
+
+// matchingBracket returns the index of the bracket closing the one at the provided index or -1.
+func matchingBracket(text string, open int) int {
+	depth := 0
+	for i := open; i < len(text); i++ {
+		switch text[i] {
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		case '"', '\'':
+			end, _, err := scanString(text, i)
+			if err != nil {
+				return -1
+			}
+			i = end - 1
+		}
+	}
+
+	return -1
+}
+
+// topLevelIndex returns the index of the separator outside of brackets and strings or -1.
+// If last is set, the last occurrence is returned, otherwise the first one.
+func topLevelIndex(text string, separator byte, last bool) int {
+	toReturn := -1
+	depth := 0
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+		case '"', '\'':
+			end, _, err := scanString(text, i)
+			if err != nil {
+				return toReturn
+			}
+			i = end - 1
+			continue
+		}
+
+		if text[i] == separator && depth == 0 {
+			// Skip comparison operators when looking up assignments.
+			if separator == '=' {
+				if i+1 < len(text) && text[i+1] == '=' {
+					i++
+					continue
+				}
+
+				if i > 0 && strings.IndexByte("!<>", text[i-1]) >= 0 {
+					continue
+				}
+			}
+
+			toReturn = i
+			if !last {
+				return toReturn
+			}
+		}
+	}
+
+	return toReturn
+}
+
+// splitTopLevel splits the text by the separator outside of brackets and strings.
+func splitTopLevel(text string, separator byte) []string {
+	toReturn := make([]string, 0)
+	for strings.TrimSpace(text) != "" {
+		idx := topLevelIndex(text, separator, false)
+		if idx < 0 {
+			toReturn = append(toReturn, strings.TrimSpace(text))
+			break
+		}
+
+		if part := strings.TrimSpace(text[:idx]); part != "" {
+			toReturn = append(toReturn, part)
+		}
+		text = text[idx+1:]
+	}
+
+	return toReturn
+}
@@ -0,0 +1,250 @@
+package vyper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		name            string
+		path            string
+		content         string
+		wantErr         bool
+		version         string
+		evmVersion      string
+		interfaces      int
+		events          int
+		structs         int
+		flags           int
+		stateVariables  int
+		functions       int
+		imports         int
+		checkModuleFunc func(t *testing.T, module *Module)
+	}{
+		{
+			name: "Vyper 0.4 module",
+			path: "Vault.vy",
+			content: `#pragma version ~=0.4.0
+#pragma evm-version cancun
+
+from ethereum.ercs import IERC20
+import math as m
+
+interface IOracle:
+    def price(asset: address) -> uint256: view
+    def update(
+        asset: address,
+        value: uint256
+    ): nonpayable
+
+event Paused: pass
+
+event Deposit:
+    owner: indexed(address)
+    assets: uint256
+
+flag Roles:
+    ADMIN
+    USER
+
+asset: public(immutable(IERC20))
+FEE: public(constant(uint256)) = 30
+roles: public(HashMap[address, Roles])
+locked: transient(bool)
+note: String[64]
+
+@deploy
+def __init__(_asset: IERC20):
+    asset = _asset
+
+@external
+@nonreentrant
+def deposit(assets: uint256, receiver: address = msg.sender) -> (uint256, bool):
+    """
+    @notice Deposit # not a comment
+    """
+    self.note = "hello # not a comment"
+    return assets, True
+
+def _helper(x: uint256) -> uint256:
+    return x * 2 # comment
+`,
+			version:        "~=0.4.0",
+			evmVersion:     "cancun",
+			interfaces:     1,
+			events:         2,
+			flags:          1,
+			stateVariables: 5,
+			functions:      3,
+			imports:        2,
+			checkModuleFunc: func(t *testing.T, module *Module) {
+				oracle := module.GetInterface("IOracle")
+				require.NotNil(t, oracle)
+				require.Equal(t, 2, len(oracle.Functions))
+				assert.Equal(t, "view", oracle.Functions[0].Mutability)
+				assert.Equal(t, 2, len(oracle.Functions[1].Parameters))
+
+				assert.Equal(t, "ethereum.ercs.IERC20", module.GetImport("IERC20").Path)
+				assert.Equal(t, "math", module.GetImport("m").Path)
+
+				asset := module.GetStateVariables()[0]
+				assert.True(t, asset.Public)
+				assert.True(t, asset.Immutable)
+				assert.Equal(t, "IERC20", asset.Type)
+
+				fee := module.GetStateVariables()[1]
+				assert.True(t, fee.Constant)
+				assert.Equal(t, "30", fee.Value)
+				assert.True(t, module.GetStateVariables()[3].Transient)
+
+				deposit := module.GetFunction("deposit")
+				require.NotNil(t, deposit)
+				assert.True(t, deposit.IsExternal())
+				assert.True(t, deposit.Nonreentrant)
+				assert.Equal(t, "nonpayable", deposit.GetMutability())
+				assert.Equal(t, []string{"uint256", "bool"}, deposit.Returns)
+				assert.Equal(t, "msg.sender", deposit.Parameters[1].Default)
+				assert.Equal(t, 2, len(deposit.Body))
+				assert.Equal(t, int64(36), deposit.Src.GetLine())
+
+				assert.True(t, module.GetFunction("__init__").IsConstructor())
+				assert.False(t, module.GetFunction("_helper").IsExternal())
+				assert.Equal(t, []string{"ADMIN", "USER"}, module.GetFlag("Roles").Members)
+				assert.Empty(t, module.GetEvents()[0].Fields)
+				assert.True(t, module.GetEvents()[1].Fields[0].Indexed)
+			},
+		},
+		{
+			name: "Interface file",
+			path: "IToken.vyi",
+			content: `@view
+@external
+def balanceOf(owner: address) -> uint256:
+    ...
+
+def transfer(to: address, amount: uint256) -> bool:
+    ...
+`,
+			functions: 2,
+			checkModuleFunc: func(t *testing.T, module *Module) {
+				assert.True(t, module.IsInterface())
+				assert.Equal(t, "IToken", module.GetName())
+				assert.True(t, module.GetFunction("transfer").IsExternal())
+				assert.Equal(t, "view", module.GetFunction("balanceOf").GetMutability())
+			},
+		},
+		{
+			name:    "Empty source",
+			path:    "Empty.vy",
+			content: "  \n",
+			wantErr: true,
+		},
+		{
+			name:    "Unterminated string",
+			path:    "Broken.vy",
+			content: "x: constant(String[3]) = \"abc\n",
+			wantErr: true,
+		},
+		{
+			name:    "Unclosed bracket",
+			path:    "Broken.vy",
+			content: "@external\ndef foo(a: uint256:\n    pass\n",
+			wantErr: true,
+		},
+		{
+			name:    "Decorator without function",
+			path:    "Broken.vy",
+			content: "@external\nx: uint256\n",
+			wantErr: true,
+		},
+		{
+			name:    "Unexpected statement",
+			path:    "Broken.vy",
+			content: "x = 5\n",
+			wantErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			module, errs := Parse("", testCase.path, testCase.content)
+			if testCase.wantErr {
+				assert.NotEmpty(t, errs)
+				return
+			}
+
+			require.Empty(t, errs)
+			require.NotNil(t, module)
+			assert.Equal(t, testCase.version, module.GetVersion())
+			assert.Equal(t, testCase.evmVersion, module.EvmVersion)
+			assert.Equal(t, testCase.interfaces, len(module.GetInterfaces()))
+			assert.Equal(t, testCase.events, len(module.GetEvents()))
+			assert.Equal(t, testCase.structs, len(module.GetStructs()))
+			assert.Equal(t, testCase.flags, len(module.GetFlags()))
+			assert.Equal(t, testCase.stateVariables, len(module.GetStateVariables()))
+			assert.Equal(t, testCase.functions, len(module.GetFunctions()))
+			assert.Equal(t, testCase.imports, len(module.GetImports()))
+
+			if testCase.checkModuleFunc != nil {
+				testCase.checkModuleFunc(t, module)
+			}
+		})
+	}
+}
+
+func TestResolveType(t *testing.T) {
+	module, errs := Parse("Token", "Token.vy", `
+interface IOracle:
+    def price() -> uint256: view
+
+struct Point:
+    x: int128
+    y: DynArray[uint256, 3]
+
+flag Roles:
+    ADMIN
+`)
+	require.Empty(t, errs)
+
+	testCases := []struct {
+		typeName   string
+		identifier string
+		typeString string
+		canonical  string
+		wantErr    bool
+	}{
+		{typeName: "uint256", identifier: "t_uint256", typeString: "uint256", canonical: "uint256"},
+		{typeName: "bytes32", identifier: "t_bytes32", typeString: "bytes32", canonical: "bytes32"},
+		{typeName: "decimal", identifier: "t_fixed168x10", typeString: "fixed168x10", canonical: "fixed168x10"},
+		{typeName: "Bytes[100]", identifier: "t_bytes_memory_ptr", typeString: "bytes", canonical: "bytes"},
+		{typeName: "String[32]", identifier: "t_string_memory_ptr", typeString: "string", canonical: "string"},
+		{typeName: "DynArray[address, 10]", identifier: "t_array$_t_address_$dyn_memory_ptr", typeString: "address[]", canonical: "address[]"},
+		{typeName: "uint8[4]", identifier: "t_array$_t_uint8_$4_memory_ptr", typeString: "uint8[4]", canonical: "uint8[4]"},
+		{typeName: "HashMap[address, HashMap[address, uint256]]", identifier: "t_mapping$_t_address_$_t_mapping$_t_address_$_t_uint256_$_$", typeString: "mapping(address=>mapping(address=>uint256))"},
+		{typeName: "Point", identifier: "t_struct$_Token_$_Point_$_storage_ptr", typeString: "struct Token.Point", canonical: "(int128,uint256[])"},
+		{typeName: "DynArray[Point, 2]", identifier: "t_array$_t_struct$_Token_$_Point_$_storage_ptr_$dyn_memory_ptr", typeString: "struct Token.Point[]", canonical: "(int128,uint256[])[]"},
+		{typeName: "Roles", identifier: "t_uint256", typeString: "uint256", canonical: "uint256"},
+		{typeName: "IOracle", identifier: "t_contract$_IOracle_$", typeString: "contract IOracle", canonical: "address"},
+		{typeName: "Unknown", wantErr: true},
+		{typeName: "HashMap[address]", wantErr: true},
+		{typeName: "uint7", wantErr: true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.typeName, func(t *testing.T) {
+			resolved, err := module.ResolveType(testCase.typeName)
+			if testCase.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, testCase.identifier, resolved.Description.GetIdentifier())
+			assert.Equal(t, testCase.typeString, resolved.Description.GetString())
+			assert.Equal(t, testCase.canonical, resolved.Canonical)
+		})
+	}
+}
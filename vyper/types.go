@@ -0,0 +1,174 @@
+package vyper
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/unpackdev/solgo/ast"
+)
+
+var (
+	elementaryTypeRegex = regexp.MustCompile(`^(address|bool|u?int(8|16|24|32|40|48|56|64|72|80|88|96|104|112|120|128|136|144|152|160|168|176|184|192|200|208|216|224|232|240|248|256)|bytes([1-9]|[12][0-9]|3[0-2]))$`)
+	staticArrayRegex    = regexp.MustCompile(`^(.+)\[\s*([^\[\]]+)\s*\]$`)
+)
+
+// Type represents a Vyper type translated into its Solidity/ABI equivalent.
+type Type struct {
+	// Description is the Solidity compatible type description used across the IR and ABI builder.
+	Description *ast.TypeDescription `json:"description"`
+
+	// Canonical is the canonical ABI type used to compute function selectors and event topics.
+	Canonical string `json:"canonical"`
+}
+
+// ResolveType translates the Vyper type, as written within the module, into its Solidity/ABI equivalent:
+//
+//	HashMap[K, V]    -> mapping(K=>V)
+//	DynArray[T, N]   -> T[]
+//	T[N]             -> T[N]
+//	Bytes[N]         -> bytes
+//	String[N]        -> string
+//	decimal          -> fixed168x10
+//	flag / enum      -> uint256
+//	interface        -> contract (address)
+//	struct           -> struct (tuple)
+func (m *Module) ResolveType(typeName string) (*Type, error) {
+	return m.resolveType(typeName, make(map[string]bool))
+}
+
+// resolveType resolves the type while keeping track of structs being resolved to detect recursion.
+func (m *Module) resolveType(typeName string, resolving map[string]bool) (*Type, error) {
+	typeName = strings.TrimSpace(typeName)
+
+	switch {
+	case typeName == "":
+		return nil, fmt.Errorf("empty type")
+
+	case elementaryTypeRegex.MatchString(typeName):
+		return newType("t_"+typeName, typeName, typeName), nil
+
+	case typeName == "decimal":
+		return newType("t_fixed168x10", "fixed168x10", "fixed168x10"), nil
+
+	case strings.HasPrefix(typeName, "Bytes["):
+		return newType("t_bytes_memory_ptr", "bytes", "bytes"), nil
+
+	case strings.HasPrefix(typeName, "String["):
+		return newType("t_string_memory_ptr", "string", "string"), nil
+
+	case strings.HasPrefix(typeName, "HashMap["):
+		args := typeArguments(typeName, "HashMap")
+		if len(args) != 2 {
+			return nil, fmt.Errorf("invalid HashMap type %q", typeName)
+		}
+
+		key, err := m.resolveType(args[0], resolving)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := m.resolveType(args[1], resolving)
+		if err != nil {
+			return nil, err
+		}
+
+		return newType(
+			fmt.Sprintf("t_mapping$_%s_$_%s_$", key.Description.GetIdentifier(), value.Description.GetIdentifier()),
+			fmt.Sprintf("mapping(%s=>%s)", key.Description.GetString(), value.Description.GetString()),
+			"",
+		), nil
+
+	case strings.HasPrefix(typeName, "DynArray["):
+		args := typeArguments(typeName, "DynArray")
+		if len(args) != 2 {
+			return nil, fmt.Errorf("invalid DynArray type %q", typeName)
+		}
+
+		element, err := m.resolveType(args[0], resolving)
+		if err != nil {
+			return nil, err
+		}
+
+		return newType(
+			fmt.Sprintf("t_array$_%s_$dyn_memory_ptr", element.Description.GetIdentifier()),
+			element.Description.GetString()+"[]",
+			element.Canonical+"[]",
+		), nil
+
+	case staticArrayRegex.MatchString(typeName):
+		matches := staticArrayRegex.FindStringSubmatch(typeName)
+
+		element, err := m.resolveType(matches[1], resolving)
+		if err != nil {
+			return nil, err
+		}
+
+		size := strings.TrimSpace(matches[2])
+		return newType(
+			fmt.Sprintf("t_array$_%s_$%s_memory_ptr", element.Description.GetIdentifier(), size),
+			fmt.Sprintf("%s[%s]", element.Description.GetString(), size),
+			fmt.Sprintf("%s[%s]", element.Canonical, size),
+		), nil
+	}
+
+	// Module members can be referenced through the module alias in Vyper 0.4+, e.g. `lib.Point`.
+	name := typeName
+	if idx := strings.LastIndexByte(name, '.'); idx >= 0 {
+		name = name[idx+1:]
+	}
+
+	if structNode := m.GetStruct(name); structNode != nil {
+		if resolving[name] {
+			return nil, fmt.Errorf("recursive struct %q", name)
+		}
+		resolving[name] = true
+		defer delete(resolving, name)
+
+		components := make([]string, 0, len(structNode.Members))
+		for _, member := range structNode.Members {
+			memberType, err := m.resolveType(member.Type, resolving)
+			if err != nil {
+				return nil, err
+			}
+			components = append(components, memberType.Canonical)
+		}
+
+		return newType(
+			fmt.Sprintf("t_struct$_%s_$_%s_$_storage_ptr", m.Name, name),
+			fmt.Sprintf("struct %s.%s", m.Name, name),
+			"("+strings.Join(components, ",")+")",
+		), nil
+	}
+
+	if m.GetFlag(name) != nil {
+		return newType("t_uint256", "uint256", "uint256"), nil
+	}
+
+	if m.GetInterface(name) != nil || m.GetImport(name) != nil || m.GetImport(strings.SplitN(typeName, ".", 2)[0]) != nil {
+		return newType(fmt.Sprintf("t_contract$_%s_$", name), "contract "+name, "address"), nil
+	}
+
+	return nil, fmt.Errorf("unknown type %q", typeName)
+}
+
+// newType creates a new Type out of the type identifier, type string and canonical ABI type.
+func newType(identifier string, typeString string, canonical string) *Type {
+	return &Type{
+		Description: &ast.TypeDescription{
+			TypeIdentifier: identifier,
+			TypeString:     typeString,
+		},
+		Canonical: canonical,
+	}
+}
+
+// typeArguments returns the top level arguments of the generic type, e.g. `HashMap[K, V]`.
+func typeArguments(typeName string, generic string) []string {
+	inner := strings.TrimPrefix(typeName, generic)
+	if !strings.HasPrefix(inner, "[") || matchingBracket(inner, 0) != len(inner)-1 {
+		return nil
+	}
+
+	return splitTopLevel(inner[1:len(inner)-1], ',')
+}